@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+
+	"github.com/spf13/cobra"
+
+	"smf/internal/core"
+	"smf/internal/migration"
+)
+
+// drivers names the database/sql driver linked into the binary for each
+// dialect. Db2 has none: its driver requires cgo and IBM's CLI client.
+var drivers = map[core.Dialect]string{
+	core.DialectMySQL:      "mysql",
+	core.DialectMariaDB:    "mysql",
+	core.DialectTiDB:       "mysql",
+	core.DialectPostgreSQL: "pgx",
+	core.DialectSQLite:     "sqlite",
+	core.DialectMSSQL:      "sqlserver",
+	core.DialectOracle:     "oracle",
+	core.DialectSnowflake:  "snowflake",
+}
+
+type applyOptions struct {
+	dsn           string
+	migrationsDir string
+	dryRun        bool
+	transaction   bool
+	unsafe        bool
+}
+
+func applyCmd() *cobra.Command {
+	var opts applyOptions
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply pending migrations to the database",
+		Long: "Connect to the database and apply the pending migrations of the migrations directory " +
+			"in order. Applied migrations are recorded in the " + migration.HistoryTable + " table.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return apply(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.dsn, "dsn", "", "database connection string (required)")
+	cmd.Flags().StringVarP(&opts.migrationsDir, "migrations-dir", "m", "./migrations", "directory where migrations are stored")
+	cmd.Flags().BoolVarP(&opts.dryRun, "dry-run", "d", false, "print statements and run preflight checks without executing")
+	cmd.Flags().BoolVarP(&opts.transaction, "transaction", "t", true, "run migration in a transaction if possible")
+	cmd.Flags().BoolVarP(&opts.unsafe, "unsafe", "u", false, "allow destructive operations (DROP, TRUNCATE, etc.)")
+	_ = cmd.MarkFlagRequired("dsn")
+	return cmd
+}
+
+func apply(ctx context.Context, out io.Writer, opts applyOptions) error {
+	last, err := migration.LastState(opts.migrationsDir)
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		fmt.Fprintf(out, "No migrations in %s\n", opts.migrationsDir)
+		return nil
+	}
+	db, err := openDatabase(last.Dialect, opts.dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	applier := migration.NewApplier(db, last.Dialect, opts.migrationsDir, migration.ApplyOptions{
+		DryRun:      opts.dryRun,
+		Transaction: opts.transaction,
+		Unsafe:      opts.unsafe,
+		AppliedBy:   appliedBy(),
+		Out:         out,
+	})
+	applied, err := applier.Apply(ctx)
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintln(out, "No pending migrations")
+	}
+	return nil
+}
+
+// openDatabase opens and pings the database of dialect.
+func openDatabase(dialect core.Dialect, dsn string) (*sql.DB, error) {
+	driver, ok := drivers[dialect]
+	if !ok {
+		return nil, fmt.Errorf("connecting to %s databases is not supported; "+
+			"generate migrations with smf and run them with the database's own client", dialect)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open %s database: %w", dialect, err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect to %s database: %w", dialect, err)
+	}
+	return db, nil
+}
+
+// appliedBy names the user running the command, as user@host.
+func appliedBy() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return name
+	}
+	return name + "@" + host
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestOpenDatabase(t *testing.T) {
+	t.Parallel()
+	db, err := openDatabase(core.DialectSQLite, filepath.Join(t.TempDir(), "shop.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = openDatabase(core.DialectDB2, "HOSTNAME=localhost;DATABASE=shop")
+	require.ErrorContains(t, err, "connecting to db2 databases is not supported")
+}
+
+func TestDriversRegistered(t *testing.T) {
+	t.Parallel()
+	registered := make(map[string]bool)
+	for _, name := range sql.Drivers() {
+		registered[name] = true
+	}
+	for dialect, driver := range drivers {
+		assert.True(t, registered[driver], "%s driver %q is not linked", dialect, driver)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"smf/internal/diff"
+	"smf/internal/generate"
+	"smf/internal/migration"
+	"smf/internal/pars"
+	"smf/internal/report"
+)
+
+// Output formats of smf diff.
+const (
+	formatSQL  = "sql"
+	formatJSON = "json"
+	formatText = "text"
+)
+
+type diffOptions struct {
+	schema        string
+	migrationsDir string
+	format        string
+}
+
+func diffCmd() *cobra.Command {
+	var opts diffOptions
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show changes pending since the latest migration",
+		Long: "Compare the schema file with the state after the latest migration and print the pending " +
+			"changes. The command exits with a non-zero status when there are pending changes, so CI can " +
+			"check that the schema and the migrations are in sync.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cs, err := pendingChanges(opts)
+			if err != nil {
+				return err
+			}
+			if err := printDiff(cmd.OutOrStdout(), cmd.ErrOrStderr(), opts.format, cs); err != nil {
+				return err
+			}
+			if !cs.Empty() {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d pending changes; run smf migrate", len(cs.Changes))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&opts.schema, "schema", "s", "schema.toml", "path to the schema file")
+	cmd.Flags().StringVarP(&opts.migrationsDir, "migrations-dir", "m", "./migrations", "directory where migrations are stored")
+	cmd.Flags().StringVarP(&opts.format, "format", "f", formatSQL, "output format: sql, json, or text")
+	return cmd
+}
+
+// pendingChanges diffs the schema file against the latest migration.
+func pendingChanges(opts diffOptions) (*diff.ChangeSet, error) {
+	target, err := pars.ParseFile(opts.schema)
+	if err != nil {
+		return nil, err
+	}
+	last, err := migration.LastState(opts.migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	return diff.Databases(last, target), nil
+}
+
+func printDiff(out, errOut io.Writer, format string, cs *diff.ChangeSet) error {
+	switch format {
+	case formatSQL:
+		return printSQL(out, errOut, cs)
+	case formatJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report.New(cs))
+	case formatText:
+		return report.New(cs).WriteText(out, colorEnabled(out))
+	default:
+		return fmt.Errorf("unknown format %q; use %s, %s, or %s", format, formatSQL, formatJSON, formatText)
+	}
+}
+
+func printSQL(out, errOut io.Writer, cs *diff.ChangeSet) error {
+	if cs.Empty() {
+		return nil
+	}
+	gen, err := generate.NewGenerator(cs.To.Dialect)
+	if err != nil {
+		return err
+	}
+	script, err := gen.GenerateChanges(cs)
+	if err != nil {
+		return err
+	}
+	for _, w := range script.Warnings {
+		fmt.Fprintln(errOut, "warning:", w)
+	}
+	_, err = io.WriteString(out, script.String())
+	return err
+}
+
+// colorEnabled reports whether out is a terminal and NO_COLOR is not set.
+func colorEnabled(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/report"
+)
+
+func TestDiffCommand(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "schema.toml")
+	migrations := filepath.Join(dir, "migrations")
+	require.NoError(t, os.WriteFile(schema, []byte(migrateSchema), 0o644))
+
+	run := func(format string) (string, error) {
+		cmd := diffCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--schema", schema, "--migrations-dir", migrations, "--format", format})
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("sql")
+	require.ErrorContains(t, err, "1 pending changes")
+	assert.Contains(t, out, `CREATE TABLE "users"`)
+
+	out, err = run("json")
+	require.Error(t, err)
+	var r report.Report
+	require.NoError(t, json.Unmarshal([]byte(out), &r))
+	assert.Equal(t, 1, r.Summary.TablesAdded)
+
+	_, err = run("yaml")
+	require.ErrorContains(t, err, `unknown format "yaml"`)
+
+	opts := migrateOptions{name: "init", schema: schema, migrationsDir: migrations}
+	require.NoError(t, migrate(&bytes.Buffer{}, &bytes.Buffer{}, opts, time.Now()))
+
+	out, err = run("text")
+	require.NoError(t, err)
+	assert.Equal(t, "Schema is in sync with the latest migration\n", out)
+
+	changed := strings.Replace(migrateSchema, `type = "varchar(255)"`, `type = "varchar(100)"`, 1)
+	require.NoError(t, os.WriteFile(schema, []byte(changed), 0o644))
+	out, err = run("text")
+	require.Error(t, err)
+	assert.Contains(t, out, "~ column email: type varchar(255) → varchar(100) (destructive)")
+}
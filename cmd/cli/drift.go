@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/drift"
+	"smf/internal/migration"
+	"smf/internal/pars"
+)
+
+// Expected states smf drift compares the database with.
+const (
+	againstSchema     = "schema"
+	againstMigrations = "migrations"
+)
+
+type driftOptions struct {
+	dsn           string
+	schema        string
+	migrationsDir string
+	against       string
+	format        string
+	ignore        drift.Ignore
+	keepVolatile  bool
+}
+
+func driftCmd() *cobra.Command {
+	var opts driftOptions
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Detect changes made to the database outside of migrations",
+		Long: "Introspect the live database and compare it with the schema file or with the state after " +
+			"the latest migration. The changes are reported as the changes that bring the database back " +
+			"to the expected schema, and the command exits with a non-zero status when there are any.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cs, err := detectDrift(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			if err := printDiff(cmd.OutOrStdout(), cmd.ErrOrStderr(), opts.format, cs); err != nil {
+				return err
+			}
+			if !cs.Empty() {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d out-of-band changes; the database differs from the %s", len(cs.Changes), opts.against)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&opts.dsn, "dsn", "", "database connection string (required)")
+	cmd.Flags().StringVarP(&opts.schema, "schema", "s", "schema.toml", "path to the schema file")
+	cmd.Flags().StringVarP(&opts.migrationsDir, "migrations-dir", "m", "./migrations", "directory where migrations are stored")
+	cmd.Flags().StringVarP(&opts.against, "against", "a", againstSchema, "expected state: schema or migrations")
+	cmd.Flags().StringVarP(&opts.format, "format", "f", formatText, "output format: sql, json, or text")
+	cmd.Flags().StringSliceVar(&opts.ignore.Tables, "ignore-table", nil, "table or view name pattern to ignore (repeatable)")
+	cmd.Flags().StringSliceVar(&opts.ignore.Indexes, "ignore-index", nil, "index name pattern to ignore (repeatable)")
+	cmd.Flags().BoolVar(&opts.keepVolatile, "keep-volatile", false, keepVolatileUsage)
+	_ = cmd.MarkFlagRequired("dsn")
+	return cmd
+}
+
+func detectDrift(ctx context.Context, opts driftOptions) (*diff.ChangeSet, error) {
+	if err := opts.ignore.Validate(); err != nil {
+		return nil, err
+	}
+	expected, err := expectedSchema(opts)
+	if err != nil {
+		return nil, err
+	}
+	live, err := introspectDatabase(ctx, expected.Dialect, opts.dsn, opts.keepVolatile)
+	if err != nil {
+		return nil, err
+	}
+	return drift.Detect(live, expected, opts.ignore), nil
+}
+
+// expectedSchema returns the schema the database is expected to have.
+func expectedSchema(opts driftOptions) (*core.Database, error) {
+	switch opts.against {
+	case againstSchema:
+		return pars.ParseFile(opts.schema)
+	case againstMigrations:
+		last, err := migration.LastState(opts.migrationsDir)
+		if err == nil && last == nil {
+			err = fmt.Errorf("no migrations in %s", opts.migrationsDir)
+		}
+		return last, err
+	default:
+		return nil, fmt.Errorf("unknown --against %q; use %s or %s", opts.against, againstSchema, againstMigrations)
+	}
+}
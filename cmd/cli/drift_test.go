@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/drift"
+)
+
+func TestExpectedSchema(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "schema.toml")
+	migrations := filepath.Join(dir, "migrations")
+	require.NoError(t, os.WriteFile(schema, []byte(migrateSchema), 0o644))
+	opts := driftOptions{schema: schema, migrationsDir: migrations, against: againstSchema}
+
+	db, err := expectedSchema(opts)
+	require.NoError(t, err)
+	assert.Equal(t, core.DialectPostgreSQL, db.Dialect)
+
+	opts.against = againstMigrations
+	_, err = expectedSchema(opts)
+	require.ErrorContains(t, err, "no migrations in")
+
+	require.NoError(t, migrate(io.Discard, io.Discard, migrateOptions{
+		name: "init", schema: schema, migrationsDir: migrations,
+	}, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)))
+	db, err = expectedSchema(opts)
+	require.NoError(t, err)
+	require.NotNil(t, db.FindTable("users"))
+
+	opts.against = "prod"
+	_, err = expectedSchema(opts)
+	require.ErrorContains(t, err, `unknown --against "prod"`)
+}
+
+func TestDetectDriftRejectsBadPattern(t *testing.T) {
+	t.Parallel()
+	_, err := detectDrift(context.Background(), driftOptions{ignore: drift.Ignore{Tables: []string{"tmp_["}}})
+	require.ErrorContains(t, err, "ignore pattern")
+}
+
+func TestDetectDriftAfterApply(t *testing.T) {
+	t.Parallel()
+	starter, err := renderStarterSchema(core.DialectSQLite)
+	require.NoError(t, err)
+	dir, dsn := applySQLiteSchema(t, string(starter))
+
+	for _, against := range []string{againstSchema, againstMigrations} {
+		cs, err := detectDrift(context.Background(), driftOptions{
+			dsn:           dsn,
+			schema:        filepath.Join(dir, "schema.toml"),
+			migrationsDir: filepath.Join(dir, "migrations"),
+			against:       against,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, cs.Changes, against)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+
+	"smf/internal/core"
+)
+
+// starterOptions holds the sample table options written for each dialect.
+// The key is the name of the [tables.options.<group>] table of the dialect.
+var starterOptions = map[core.Dialect]struct {
+	group string
+	lines []string
+}{
+	core.DialectMySQL: {"mysql", []string{
+		`engine = "InnoDB"`,
+		`charset = "utf8mb4"`,
+		`collate = "utf8mb4_0900_ai_ci"`,
+	}},
+	core.DialectMariaDB: {"mariadb", []string{
+		`with_system_versioning = false  # keep the history of every row`,
+		`# encryption_key_id = 1`,
+	}},
+	core.DialectTiDB: {"tidb", []string{
+		`auto_id_cache = 1  # MySQL-compatible AUTO_INCREMENT`,
+		`# placement_policy = "default"`,
+	}},
+	core.DialectPostgreSQL: {"postgresql", []string{
+		`schema = "public"`,
+		`# fillfactor = 90`,
+	}},
+	core.DialectSQLite: {"sqlite", []string{
+		`strict = true  # enforce column types`,
+		`# without_rowid = true`,
+	}},
+	core.DialectOracle: {"oracle", []string{
+		`organization = "heap"`,
+		`# pctfree = 10`,
+	}},
+	core.DialectDB2: {"db2", []string{
+		`organize_by = "row"`,
+		`# compress = "yes adaptive"`,
+	}},
+	core.DialectSnowflake: {"snowflake", []string{
+		`data_retention_days = 1  # Time Travel retention`,
+		`# cluster_by = ["created_at"]`,
+	}},
+	core.DialectMSSQL: {"sqlserver", []string{
+		`data_compression = "NONE"`,
+		`# file_group = "PRIMARY"`,
+	}},
+}
+
+var starterSchema = template.Must(template.New("schema.toml").Parse(`# smf schema - the single source of truth for the {{.Dialect}} database.
+#
+# Edit the tables below, then run:
+#   smf migrate --name init_schema   to generate the first migration
+#   smf apply --dsn "..."            to apply it to the database
+
+[database]
+name = "app"
+dialect = "{{.Dialect}}"
+
+# Optional naming rules checked on every parse.
+[validation]
+max_table_name_length = 64
+max_column_name_length = 64
+auto_generate_constraint_names = true
+allowed_name_pattern = "^[a-z][a-z0-9_]*$"
+
+[[tables]]
+name = "users"
+comment = "Application user"
+
+# Options only the {{.Dialect}} generator reads.
+[tables.options.{{.Group}}]
+{{range .Options}}{{.}}
+{{end}}
+[tables.timestamps]
+enabled = true  # adds created_at and updated_at
+
+[[tables.columns]]
+name = "id"
+type = "bigint"
+primary_key = true
+auto_increment = true
+
+[[tables.columns]]
+name = "email"
+type = "varchar(255)"
+unique = true
+
+[[tables.columns]]
+name = "display_name"
+type = "varchar(100)"
+nullable = true
+`))
+
+// renderStarterSchema renders the starter schema for dialect.
+func renderStarterSchema(dialect core.Dialect) ([]byte, error) {
+	opts, ok := starterOptions[dialect]
+	if !ok {
+		return nil, fmt.Errorf("unsupported dialect %q; supported dialects: %v", dialect, core.SupportedDialects())
+	}
+	var buf bytes.Buffer
+	err := starterSchema.Execute(&buf, struct {
+		Dialect core.Dialect
+		Group   string
+		Options []string
+	}{dialect, opts.group, opts.lines})
+	if err != nil {
+		return nil, fmt.Errorf("render schema: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeStarterSchema writes the starter schema for dialect to path. An
+// existing file is only replaced when force is set.
+func writeStarterSchema(path string, dialect core.Dialect, force bool) error {
+	content, err := renderStarterSchema(dialect)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, content, force)
+}
+
+// writeFile writes content to path. An existing file is only replaced when
+// force is set.
+func writeFile(path string, content []byte, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists; use --force to overwrite it", path)
+	}
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+func initCmd() *cobra.Command {
+	var (
+		dialect string
+		output  string
+		force   bool
+	)
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a starter schema.toml",
+		Long: "Create a commented schema.toml with a [database] block, a [validation] block " +
+			"and a sample table using the options of the chosen dialect.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			d := core.Dialect(strings.ToLower(dialect))
+			if err := writeStarterSchema(output, d, force); err != nil {
+				return err
+			}
+			cmd.Printf("Created %s for %s\n", output, d)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&dialect, "dialect", "d", string(core.DialectMySQL), "database dialect for the initial schema")
+	cmd.Flags().StringVarP(&output, "output", "o", "schema.toml", "custom filename for the schema")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite an existing file")
+	return cmd
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/pars/toml"
+)
+
+func TestStarterSchemaParses(t *testing.T) {
+	t.Parallel()
+	for _, d := range core.SupportedDialects() {
+		t.Run(string(d), func(t *testing.T) {
+			t.Parallel()
+			content, err := renderStarterSchema(d)
+			require.NoError(t, err)
+
+			db, err := toml.NewParser().Parse(bytes.NewReader(content))
+			require.NoError(t, err)
+			assert.Equal(t, d, db.Dialect)
+			require.Len(t, db.Tables, 1)
+			assert.Equal(t, "users", db.Tables[0].Name)
+			assert.True(t, db.Validation.AutoGenerateConstraintNames)
+		})
+	}
+}
+
+func TestStarterSchemaUnsupportedDialect(t *testing.T) {
+	t.Parallel()
+	_, err := renderStarterSchema("informix")
+	require.Error(t, err)
+}
+
+func TestWriteStarterSchemaRefusesOverwrite(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "schema.toml")
+	require.NoError(t, os.WriteFile(path, []byte("keep"), 0o644))
+
+	err := writeStarterSchema(path, core.DialectSQLite, false)
+	require.ErrorContains(t, err, "already exists")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "keep", string(content))
+
+	require.NoError(t, writeStarterSchema(path, core.DialectSQLite, true))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `dialect = "sqlite"`)
+}
+
+func TestInitCommand(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "db.toml")
+	cmd := initCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--dialect", "PostgreSQL", "--output", path})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "Created "+path+" for postgresql\n", out.String())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "[tables.options.postgresql]")
+}
@@ -5,7 +5,28 @@ package main
 import (
 	"os"
 
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/microsoft/go-mssqldb"
+	_ "github.com/sijms/go-ora/v2"
+	_ "github.com/snowflakedb/gosnowflake"
 	"github.com/spf13/cobra"
+	_ "modernc.org/sqlite"
+
+	_ "smf/internal/generate/db2"
+	_ "smf/internal/generate/mssql"
+	_ "smf/internal/generate/mysql"
+	_ "smf/internal/generate/oracle"
+	_ "smf/internal/generate/postgres"
+	_ "smf/internal/generate/snowflake"
+	_ "smf/internal/generate/sqlite"
+	_ "smf/internal/introspect/db2"
+	_ "smf/internal/introspect/mssql"
+	_ "smf/internal/introspect/mysql"
+	_ "smf/internal/introspect/oracle"
+	_ "smf/internal/introspect/postgresql"
+	_ "smf/internal/introspect/snowflake"
+	_ "smf/internal/introspect/sqlite"
 )
 
 func main() {
@@ -14,7 +35,12 @@ func main() {
 		Short: "Schema migration framework – TOML-first database schema tool",
 	}
 
-	// rootCmd.AddCommand(migrationCmd())
+	rootCmd.AddCommand(initCmd())
+	rootCmd.AddCommand(migrationCmd())
+	rootCmd.AddCommand(applyCmd())
+	rootCmd.AddCommand(diffCmd())
+	rootCmd.AddCommand(pullCmd())
+	rootCmd.AddCommand(driftCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"smf/internal/diff"
+	"smf/internal/generate"
+	"smf/internal/migration"
+	"smf/internal/pars"
+)
+
+type migrateOptions struct {
+	name          string
+	schema        string
+	migrationsDir string
+	unsafe        bool
+}
+
+func migrationCmd() *cobra.Command {
+	var opts migrateOptions
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Generate a migration from the schema changes",
+		Long: "Compare the schema file with the state after the latest migration and write the " +
+			"changes as a new timestamped SQL migration.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return migrate(cmd.OutOrStdout(), cmd.ErrOrStderr(), opts, time.Now())
+		},
+	}
+	cmd.Flags().StringVarP(&opts.name, "name", "n", "schema", "descriptive name for the migration")
+	cmd.Flags().StringVarP(&opts.schema, "schema", "s", "schema.toml", "path to the schema file")
+	cmd.Flags().StringVarP(&opts.migrationsDir, "migrations-dir", "m", "./migrations", "directory to save the migration")
+	cmd.Flags().BoolVarP(&opts.unsafe, "unsafe", "u", false, "generate unsafe migration (may drop/overwrite data)")
+	return cmd
+}
+
+func migrate(out, errOut io.Writer, opts migrateOptions, now time.Time) error {
+	target, err := pars.ParseFile(opts.schema)
+	if err != nil {
+		return err
+	}
+	last, err := migration.LastState(opts.migrationsDir)
+	if err != nil {
+		return err
+	}
+	if last != nil && last.Dialect != target.Dialect {
+		return fmt.Errorf("dialect changed from %s to %s; start a new migrations directory", last.Dialect, target.Dialect)
+	}
+
+	cs := diff.Databases(last, target)
+	if cs.Empty() {
+		fmt.Fprintln(out, "No changes, the schema matches the latest migration")
+		return nil
+	}
+	if destructive := cs.Destructive(); len(destructive) > 0 && !opts.unsafe {
+		return fmt.Errorf("refusing to generate destructive changes without --unsafe:\n  %s",
+			strings.Join(describeChanges(destructive), "\n  "))
+	}
+
+	gen, err := generate.NewGenerator(target.Dialect)
+	if err != nil {
+		return err
+	}
+	script, err := gen.GenerateChanges(cs)
+	if err != nil {
+		return err
+	}
+	m, err := migration.Write(opts.migrationsDir, opts.name, now, script, target)
+	if err != nil {
+		return err
+	}
+	for _, w := range script.Warnings {
+		fmt.Fprintln(errOut, "warning:", w)
+	}
+	fmt.Fprintf(out, "Created %s\n", m.Path)
+	return nil
+}
+
+// describeChanges names each change with its kind and the object it
+// applies to.
+func describeChanges(changes []diff.Change) []string {
+	out := make([]string, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, diff.Describe(c))
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const migrateSchema = `
+[database]
+name = "app"
+dialect = "postgresql"
+
+[[tables]]
+name = "users"
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "email"
+  type = "varchar(255)"
+`
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	opts := migrateOptions{
+		name:          "init",
+		schema:        filepath.Join(dir, "schema.toml"),
+		migrationsDir: filepath.Join(dir, "migrations"),
+	}
+	require.NoError(t, os.WriteFile(opts.schema, []byte(migrateSchema), 0o644))
+	now := time.Date(2026, 2, 15, 12, 30, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+	require.NoError(t, migrate(&out, &out, opts, now))
+	path := filepath.Join(opts.migrationsDir, "20260215123000_init.sql")
+	assert.Equal(t, "Created "+path+"\n", out.String())
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `CREATE TABLE "users"`)
+
+	out.Reset()
+	require.NoError(t, migrate(&out, &out, opts, now.Add(time.Minute)))
+	assert.Contains(t, out.String(), "No changes")
+
+	widened := strings.Replace(migrateSchema, `type = "varchar(255)"`, `type = "varchar(320)"`, 1)
+	require.NoError(t, os.WriteFile(opts.schema, []byte(widened), 0o644))
+	opts.name = "widen email"
+	require.NoError(t, migrate(&out, &out, opts, now.Add(30*time.Second)))
+
+	require.NoError(t, os.WriteFile(opts.schema, []byte(migrateSchema), 0o644))
+	opts.name = "narrow email"
+	err = migrate(&out, &out, opts, now.Add(45*time.Second))
+	require.ErrorContains(t, err, "alter_column_type users.email")
+
+	dropped := strings.Replace(migrateSchema, "  [[tables.columns]]\n  name = \"email\"\n  type = \"varchar(255)\"\n", "", 1)
+	require.NoError(t, os.WriteFile(opts.schema, []byte(dropped), 0o644))
+	opts.name = "drop email"
+	err = migrate(&out, &out, opts, now.Add(time.Minute))
+	require.ErrorContains(t, err, "drop_column users.email")
+
+	opts.unsafe = true
+	require.NoError(t, migrate(&out, &out, opts, now.Add(time.Minute)))
+	content, err = os.ReadFile(filepath.Join(opts.migrationsDir, "20260215123100_drop_email.sql"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `DROP COLUMN "email"`)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+	"smf/internal/migration"
+	"smf/internal/pars/toml"
+)
+
+// stdoutPath is the --output value that prints the schema instead of
+// writing a file.
+const stdoutPath = "-"
+
+type pullOptions struct {
+	dsn          string
+	dialect      string
+	output       string
+	force        bool
+	keepVolatile bool
+}
+
+func pullCmd() *cobra.Command {
+	var opts pullOptions
+	cmd := &cobra.Command{
+		Use:   "pull",
+		Short: "Create schema.toml from a live database",
+		Long: "Connect to the database, introspect its tables and write them as a schema.toml. " +
+			"Constraints are written as column shorthand where the parser would generate the same " +
+			"constraint, and created/updated columns are written as timestamps.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return pull(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.dsn, "dsn", "", "database connection string (required)")
+	cmd.Flags().StringVarP(&opts.dialect, "dialect", "d", string(core.DialectMySQL), "database dialect")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "schema.toml", `file to write the schema to, or "-" for stdout`)
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "overwrite an existing file")
+	cmd.Flags().BoolVar(&opts.keepVolatile, "keep-volatile", false, keepVolatileUsage)
+	_ = cmd.MarkFlagRequired("dsn")
+	return cmd
+}
+
+func pull(ctx context.Context, out, errOut io.Writer, opts pullOptions) error {
+	schema, err := introspectDatabase(ctx, core.Dialect(strings.ToLower(opts.dialect)), opts.dsn, opts.keepVolatile)
+	if err != nil {
+		return err
+	}
+	return writeSchema(out, errOut, opts, schema)
+}
+
+// keepVolatileUsage describes the --keep-volatile flag of pull and drift.
+const keepVolatileUsage = "keep table options that change as rows are written, such as the AUTO_INCREMENT counter"
+
+// introspectDatabase connects to the database of dialect and returns its
+// schema. Volatile table options are cleared unless keepVolatile is set.
+func introspectDatabase(ctx context.Context, dialect core.Dialect, dsn string, keepVolatile bool) (*core.Database, error) {
+	in, err := introspect.NewIntrospecter(dialect)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openDatabase(dialect, dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	schema, err := in.Introspect(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("introspect %s database: %w", dialect, err)
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("introspecting %s databases is not supported yet", dialect)
+	}
+	if !keepVolatile {
+		introspect.ClearVolatile(schema)
+	}
+	return schema, nil
+}
+
+// writeSchema writes the pulled schema to the output of opts. The history
+// table of the migrations is not part of the schema. A schema that does not
+// pass validation is still written, with a warning, so it can be fixed by
+// hand.
+func writeSchema(out, errOut io.Writer, opts pullOptions, schema *core.Database) error {
+	tables := schema.Tables[:0:0]
+	for _, t := range schema.Tables {
+		if t.Name != migration.HistoryTable {
+			tables = append(tables, t)
+		}
+	}
+	schema.Tables = tables
+
+	var buf bytes.Buffer
+	if err := toml.NewWriter().Write(&buf, schema); err != nil {
+		return err
+	}
+	if _, err := toml.NewParser().Parse(bytes.NewReader(buf.Bytes())); err != nil {
+		fmt.Fprintf(errOut, "warning: the pulled schema does not validate: %v\n", err)
+	}
+
+	if opts.output == stdoutPath {
+		_, err := out.Write(buf.Bytes())
+		return err
+	}
+	if err := writeFile(opts.output, buf.Bytes(), opts.force); err != nil {
+		return err
+	}
+	pulled := fmt.Sprintf("%d tables", len(schema.Tables))
+	if len(schema.Views) > 0 {
+		pulled += fmt.Sprintf(" and %d views", len(schema.Views))
+	}
+	fmt.Fprintf(out, "Pulled %s from %s database %q into %s\n", pulled, schema.Dialect, schema.Name, opts.output)
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+	"smf/internal/migration"
+	"smf/internal/pars/toml"
+)
+
+const sqliteSchema = `
+[database]
+name = "app"
+dialect = "sqlite"
+
+[[tables]]
+name = "users"
+
+  [tables.timestamps]
+  enabled = true
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+  auto_increment = true
+
+  [[tables.columns]]
+  name = "email"
+  type = "varchar(255)"
+  unique = true
+
+  [[tables.columns]]
+  name = "verified"
+  type = "boolean"
+  default = "false"
+`
+
+// applySQLiteSchema migrates and applies schema to a new SQLite file and
+// returns the directory of the schema file and migrations, and the DSN.
+func applySQLiteSchema(t *testing.T, schema string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	migrations := filepath.Join(dir, "migrations")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "schema.toml"), []byte(schema), 0o644))
+	require.NoError(t, migrate(io.Discard, io.Discard, migrateOptions{
+		name: "init", schema: filepath.Join(dir, "schema.toml"), migrationsDir: migrations,
+	}, time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)))
+
+	dsn := "file:" + filepath.Join(dir, "app.db")
+	require.NoError(t, apply(context.Background(), io.Discard, applyOptions{
+		dsn: dsn, migrationsDir: migrations, transaction: true,
+	}))
+	return dir, dsn
+}
+
+func pulledSchema() *core.Database {
+	return &core.Database{
+		Name:    "shop",
+		Dialect: core.DialectMySQL,
+		Tables: []*core.Table{
+			{
+				Name:        "users",
+				Columns:     []*core.Column{{Name: "id", Type: core.DataTypeInt, RawType: "bigint"}},
+				Constraints: []*core.Constraint{{Type: core.ConstraintPrimaryKey, Columns: []string{"id"}}},
+			},
+			{
+				Name:    migration.HistoryTable,
+				Columns: []*core.Column{{Name: "version", Type: core.DataTypeString, RawType: "varchar(14)"}},
+			},
+		},
+	}
+}
+
+func TestWriteSchema(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "schema.toml")
+	var out, errOut bytes.Buffer
+
+	require.NoError(t, writeSchema(&out, &errOut, pullOptions{output: path}, pulledSchema()))
+	assert.Equal(t, "Pulled 1 tables from mysql database \"shop\" into "+path+"\n", out.String())
+	assert.Empty(t, errOut.String())
+
+	db, err := toml.NewParser().ParseFile(path)
+	require.NoError(t, err)
+	require.Len(t, db.Tables, 1)
+	assert.True(t, db.Tables[0].Columns[0].PrimaryKey)
+
+	err = writeSchema(&out, &errOut, pullOptions{output: path}, pulledSchema())
+	require.ErrorContains(t, err, "already exists")
+	require.NoError(t, writeSchema(&out, &errOut, pullOptions{output: path, force: true}, pulledSchema()))
+}
+
+func TestWriteSchemaViews(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "schema.toml")
+	var out, errOut bytes.Buffer
+
+	schema := pulledSchema()
+	schema.Views = []*core.View{{Name: "user_ids", Definition: "select `users`.`id` AS `id` from `users`"}}
+	require.NoError(t, writeSchema(&out, &errOut, pullOptions{output: path}, schema))
+	assert.Equal(t, "Pulled 1 tables and 1 views from mysql database \"shop\" into "+path+"\n", out.String())
+	assert.Empty(t, errOut.String())
+
+	db, err := toml.NewParser().ParseFile(path)
+	require.NoError(t, err)
+	require.Len(t, db.Views, 1)
+	assert.Equal(t, "select `users`.`id` AS `id` from `users`", db.Views[0].Definition)
+}
+
+func TestWriteSchemaStdout(t *testing.T) {
+	t.Parallel()
+	var out, errOut bytes.Buffer
+	require.NoError(t, writeSchema(&out, &errOut, pullOptions{output: stdoutPath}, pulledSchema()))
+	assert.True(t, strings.HasPrefix(out.String(), "[database]\n"))
+	assert.NotContains(t, out.String(), migration.HistoryTable)
+}
+
+func TestWriteSchemaWarnsWhenInvalid(t *testing.T) {
+	t.Parallel()
+	schema := pulledSchema()
+	schema.Tables[0].Name = "Users"
+	path := filepath.Join(t.TempDir(), "schema.toml")
+	var out, errOut bytes.Buffer
+
+	require.NoError(t, writeSchema(&out, &errOut, pullOptions{output: path}, schema))
+	assert.Contains(t, errOut.String(), "warning: the pulled schema does not validate")
+	_, err := os.Stat(path)
+	require.NoError(t, err)
+}
+
+func TestPullUnsupportedDialect(t *testing.T) {
+	t.Parallel()
+	err := pull(context.Background(), &bytes.Buffer{}, &bytes.Buffer{}, pullOptions{dsn: "x", dialect: "informix"})
+	require.ErrorContains(t, err, "unsupported dialect")
+}
+
+func TestPullSQLite(t *testing.T) {
+	t.Parallel()
+	dir, dsn := applySQLiteSchema(t, sqliteSchema)
+	path := filepath.Join(dir, "pulled.toml")
+	var out, errOut bytes.Buffer
+	require.NoError(t, pull(context.Background(), &out, &errOut, pullOptions{dsn: dsn, dialect: "sqlite", output: path}))
+	assert.Empty(t, errOut.String())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "[tables.timestamps]\nenabled = true\n")
+	assert.Contains(t, string(content), `type = "varchar(255)"`)
+	assert.NotContains(t, string(content), "[tables.options]")
+
+	pulled, err := toml.NewParser().Parse(bytes.NewReader(content))
+	require.NoError(t, err)
+	declared, err := toml.NewParser().ParseFile(filepath.Join(dir, "schema.toml"))
+	require.NoError(t, err)
+	generate.Normalize(pulled)
+	generate.Normalize(declared)
+	assert.Empty(t, diff.Databases(declared, pulled).Changes)
+}
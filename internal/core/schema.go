@@ -18,6 +18,7 @@ type Database struct {
 	Name       string           `json:"name" toml:"name"`
 	Dialect    Dialect          `json:"dialect" toml:"dialect"`
 	Tables     []*Table         `json:"tables" toml:"tables"`
+	Views      []*View          `json:"views,omitempty" toml:"views,omitempty"`
 	Validation *ValidationRules `json:"validation,omitempty" toml:"validation,omitempty"`
 }
 
@@ -230,6 +231,10 @@ type MySQLTableOptions struct {
 	// Nodegroup assigns the table to an NDB Cluster node group.
 	// MySQL: NDB only | MariaDB: Not supported | TiDB: Not supported
 	Nodegroup uint64 `json:"nodegroup,omitempty" toml:"nodegroup,omitempty"`
+	// PartitionBy holds the partitioning clause after PARTITION BY, with its partition definitions
+	// (e.g. "RANGE (year(created_at)) (PARTITION p0 VALUES LESS THAN (2020), PARTITION p1 VALUES LESS THAN MAXVALUE)").
+	// MySQL: 5.1+ | MariaDB: All versions | TiDB: All versions
+	PartitionBy string `json:"partition_by,omitempty" toml:"partition_by,omitempty"`
 }
 
 // TiDBTableOptions contains TiDB-specific table options.
@@ -431,6 +436,10 @@ type Column struct {
 	// Type is the normalized portable data type category (e.g., DataTypeString).
 	// Always derived from the portable TOML `type` field for consistent classification.
 	Type DataType `json:"type" toml:"type"`
+	// PortableType is the portable type string exactly as declared in the schema
+	// (e.g. "varchar(255)", "decimal(10,2)"), preserving length and precision
+	// that the normalized Type category drops.
+	PortableType string `json:"portable_type,omitempty" toml:"portable_type,omitempty"`
 	// Nullable indicates whether the column allows NULL values.
 	Nullable bool `json:"nullable" toml:"nullable"`
 	// PrimaryKey marks this column as part of the table's primary key.
@@ -528,6 +537,9 @@ type MySQLColumnOptions struct {
 	// SecondaryEngineAttribute is an opaque JSON string passed to the secondary engine for this column.
 	// MySQL: 8.0.21+ | MariaDB: Not supported | TiDB: Not supported
 	SecondaryEngineAttribute string `json:"secondary_engine_attribute,omitempty" toml:"secondary_engine_attribute,omitempty"`
+	// SRID restricts a spatial column to the values of one spatial reference system (e.g. 4326).
+	// MySQL: 8.0.3+ | MariaDB: Not supported | TiDB: Not supported
+	SRID *uint32 `json:"srid,omitempty" toml:"srid,omitempty"`
 }
 
 // TiDBColumnOptions contains TiDB-specific column-level options.
@@ -788,12 +800,17 @@ type Index struct {
 	Columns []ColumnIndex `json:"columns" toml:"columns"`
 	// Unique marks the index as a UNIQUE index that prevents duplicate values.
 	Unique bool `json:"unique,omitempty" toml:"unique,omitempty"`
-	// Type is the index algorithm or kind (BTREE, HASH, FULLTEXT, SPATIAL, GIN, GiST).
+	// Type is the index algorithm or kind (BTREE, HASH, FULLTEXT, SPATIAL, GIN, GiST, BRIN).
 	Type IndexType `json:"type,omitempty" toml:"type,omitempty"`
 	// Comment is an optional descriptive comment stored with the index metadata.
 	Comment string `json:"comment,omitempty" toml:"comment,omitempty"`
 	// Visibility controls whether the optimizer considers this index (VISIBLE or INVISIBLE).
 	Visibility IndexVisibility `json:"visibility,omitempty" toml:"visibility,omitempty"`
+	// Where is the predicate of a partial index (e.g. "deleted_at IS NULL"); empty indexes every row.
+	Where string `json:"where,omitempty" toml:"where,omitempty"`
+	// Parser is the full-text parser plugin of a FULLTEXT index (e.g. "ngram"); empty uses the built-in parser.
+	// MySQL: 5.1+ | MariaDB: 5.1+ | TiDB: Not supported
+	Parser string `json:"parser,omitempty" toml:"parser,omitempty"`
 }
 
 // ColumnIndex describes a single column reference within an index definition.
@@ -804,6 +821,8 @@ type ColumnIndex struct {
 	Length int `json:"length,omitempty" toml:"length,omitempty"`
 	// Order is the sort direction for this column in the index (ASC or DESC).
 	Order SortOrder `json:"order,omitempty" toml:"order,omitempty"`
+	// Expression is the SQL expression of a functional key part (e.g. "lower(email)"), which has no Name.
+	Expression string `json:"expression,omitempty" toml:"expression,omitempty"`
 }
 
 // IndexType is an ENUM with all possible index types.
@@ -816,12 +835,13 @@ const (
 	IndexTypeSpatial  IndexType = "SPATIAL"
 	IndexTypeGIN      IndexType = "GIN"
 	IndexTypeGiST     IndexType = "GiST"
+	IndexTypeBRIN     IndexType = "BRIN"
 )
 
 // IsValid reports whether it is a recognized index type.
 func (it IndexType) IsValid() bool {
 	switch it {
-	case IndexTypeBTree, IndexTypeHash, IndexTypeFullText, IndexTypeSpatial, IndexTypeGIN, IndexTypeGiST:
+	case IndexTypeBTree, IndexTypeHash, IndexTypeFullText, IndexTypeSpatial, IndexTypeGIN, IndexTypeGiST, IndexTypeBRIN:
 		return true
 	default:
 		return false
@@ -864,6 +884,84 @@ func (so SortOrder) IsValid() bool {
 	}
 }
 
+// View represents a view or a materialized view. Views are created after
+// every table, and after the views they select from.
+type View struct {
+	// Name is the view identifier. Views share the namespace of tables.
+	Name string `json:"name" toml:"name"`
+	// Definition is the SELECT statement the view is defined by, written in the target dialect.
+	Definition string `json:"definition" toml:"definition"`
+	// Columns optionally name the columns of the view; empty keeps the names of the SELECT list.
+	Columns []string `json:"columns,omitempty" toml:"columns,omitempty"`
+	// CheckOption rejects writes through the view whose rows the view would not select.
+	CheckOption CheckOption `json:"check_option,omitempty" toml:"check_option,omitempty"`
+	// Security selects whose privileges the view is executed with (DEFINER or INVOKER).
+	Security ViewSecurity `json:"security,omitempty" toml:"security,omitempty"`
+	// Materialized stores the result of the view instead of running its query on every read.
+	Materialized bool `json:"materialized,omitempty" toml:"materialized,omitempty"`
+	// Refresh is the refresh policy of a materialized view; empty means ON DEMAND.
+	Refresh RefreshPolicy `json:"refresh,omitempty" toml:"refresh,omitempty"`
+	// Comment is an optional descriptive comment stored with the view.
+	Comment string `json:"comment,omitempty" toml:"comment,omitempty"`
+}
+
+// CheckOption is an ENUM with all possible view check options.
+type CheckOption string
+
+const (
+	CheckOptionNone     CheckOption = ""
+	CheckOptionLocal    CheckOption = "LOCAL"
+	CheckOptionCascaded CheckOption = "CASCADED"
+)
+
+// IsValid reports whether co is a recognized check option (including empty/none).
+func (co CheckOption) IsValid() bool {
+	switch co {
+	case CheckOptionNone, CheckOptionLocal, CheckOptionCascaded:
+		return true
+	default:
+		return false
+	}
+}
+
+// ViewSecurity is an ENUM with all possible view security contexts.
+type ViewSecurity string
+
+const (
+	SecurityDefiner ViewSecurity = "DEFINER"
+	SecurityInvoker ViewSecurity = "INVOKER"
+)
+
+// IsValid reports whether vs is a recognized view security context.
+func (vs ViewSecurity) IsValid() bool {
+	switch vs {
+	case SecurityDefiner, SecurityInvoker:
+		return true
+	default:
+		return false
+	}
+}
+
+// RefreshPolicy is an ENUM with all possible materialized view refresh policies.
+type RefreshPolicy string
+
+const (
+	// RefreshOnDemand refreshes the view only when asked to (REFRESH DEFERRED in DB2).
+	RefreshOnDemand RefreshPolicy = "ON DEMAND"
+	// RefreshOnCommit refreshes the view with every commit to its base tables (REFRESH IMMEDIATE in DB2).
+	RefreshOnCommit RefreshPolicy = "ON COMMIT"
+)
+
+// IsValid reports whether rp is a recognized refresh policy.
+func (rp RefreshPolicy) IsValid() bool {
+	switch rp {
+	case RefreshOnDemand, RefreshOnCommit:
+		return true
+	default:
+		return false
+	}
+}
+
 // FindTable looks for a table by name inside a database.
 //
 // TODO: consider pre-building a map[string]*Table
@@ -880,6 +978,19 @@ func (db *Database) FindTable(name string) *Table {
 	return nil
 }
 
+// FindView looks for a view by name inside a database.
+func (db *Database) FindView(name string) *View {
+	if db == nil {
+		return nil
+	}
+	for _, v := range db.Views {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
 // FindColumn looks for a column by name inside a table.
 func (t *Table) FindColumn(name string) *Column {
 	if t == nil {
@@ -34,6 +34,29 @@ func TestDatabaseFindTable(t *testing.T) {
 	})
 }
 
+func TestDatabaseFindView(t *testing.T) {
+	db := &Database{
+		Name:   "testdb",
+		Tables: []*Table{{Name: "orders"}},
+		Views:  []*View{{Name: "order_totals"}, {Name: "daily_sales", Materialized: true}},
+	}
+
+	t.Run("find existing view", func(t *testing.T) {
+		view := db.FindView("daily_sales")
+		assert.NotNil(t, view)
+		assert.True(t, view.Materialized)
+	})
+
+	t.Run("tables are not views", func(t *testing.T) {
+		assert.Nil(t, db.FindView("orders"))
+	})
+
+	t.Run("nil database", func(t *testing.T) {
+		var nilDB *Database
+		assert.Nil(t, nilDB.FindView("order_totals"))
+	})
+}
+
 func TestTableFindColumn(t *testing.T) {
 	table := &Table{
 		Name: "users",
@@ -505,3 +528,13 @@ func TestSortOrderConstants(t *testing.T) {
 	assert.Equal(t, SortAsc, SortOrder("ASC"))
 	assert.Equal(t, SortDesc, SortOrder("DESC"))
 }
+
+func TestViewEnums(t *testing.T) {
+	assert.True(t, CheckOptionNone.IsValid())
+	assert.True(t, CheckOption("CASCADED").IsValid())
+	assert.False(t, CheckOption("local").IsValid())
+	assert.True(t, ViewSecurity("INVOKER").IsValid())
+	assert.False(t, ViewSecurity("").IsValid())
+	assert.True(t, RefreshPolicy("ON COMMIT").IsValid())
+	assert.False(t, RefreshPolicy("COMPLETE").IsValid())
+}
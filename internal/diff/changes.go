@@ -0,0 +1,222 @@
+package diff
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+)
+
+// Kind identifies the type of a single schema change.
+type Kind string
+
+const (
+	KindAddTable           Kind = "add_table"
+	KindDropTable          Kind = "drop_table"
+	KindChangeTableComment Kind = "change_table_comment"
+	KindTableOptionChange  Kind = "table_option_change"
+	KindAddColumn          Kind = "add_column"
+	KindDropColumn         Kind = "drop_column"
+	KindAlterColumnType    Kind = "alter_column_type"
+	KindAlterNullability   Kind = "alter_nullability"
+	KindChangeDefault      Kind = "change_default"
+	KindAlterColumn        Kind = "alter_column"
+	KindAddConstraint      Kind = "add_constraint"
+	KindDropConstraint     Kind = "drop_constraint"
+	KindAddIndex           Kind = "add_index"
+	KindDropIndex          Kind = "drop_index"
+	KindAddView            Kind = "add_view"
+	KindDropView           Kind = "drop_view"
+)
+
+// Change is a single typed schema change. Generators type-switch on the
+// concrete change types declared in this file.
+type Change interface {
+	// Kind returns the change kind.
+	Kind() Kind
+	// TableName returns the name of the table or view the change applies to.
+	TableName() string
+}
+
+// FieldChange describes a single attribute that differs between two
+// versions of a column or table. Name is the TOML key path of the
+// attribute (e.g. "comment" or "mysql.engine").
+type FieldChange struct {
+	Name string `json:"name"`
+	Old  any    `json:"old"`
+	New  any    `json:"new"`
+}
+
+// AddTable creates a new table with all its columns, constraints, and indexes.
+type AddTable struct {
+	Table *core.Table
+}
+
+// DropTable removes an existing table. Table holds the last known definition.
+type DropTable struct {
+	Table *core.Table
+}
+
+// ChangeTableComment updates the comment of an existing table.
+type ChangeTableComment struct {
+	Table *core.Table
+	Old   string
+	New   string
+}
+
+// TableOptionChange updates one or more table options, including the
+// dialect-specific option groups. Old and New hold both table versions so
+// generators can re-render the complete option set when they need to.
+type TableOptionChange struct {
+	Old    *core.Table
+	New    *core.Table
+	Fields []FieldChange
+}
+
+// AddColumn adds a column to an existing table. After names the column that
+// precedes it in the target definition and is empty for the first column.
+type AddColumn struct {
+	Table  *core.Table
+	Column *core.Column
+	After  string
+}
+
+// DropColumn removes a column from an existing table.
+type DropColumn struct {
+	Table  *core.Table
+	Column *core.Column
+}
+
+// AlterColumnType changes the data type of a column.
+type AlterColumnType struct {
+	Table *core.Table
+	Old   *core.Column
+	New   *core.Column
+}
+
+// AlterNullability switches a column between NULL and NOT NULL.
+type AlterNullability struct {
+	Table *core.Table
+	Old   *core.Column
+	New   *core.Column
+}
+
+// ChangeDefault sets, changes, or drops the DEFAULT expression of a column.
+type ChangeDefault struct {
+	Table *core.Table
+	Old   *core.Column
+	New   *core.Column
+}
+
+// AlterColumn changes column attributes other than type, nullability, and
+// default (comment, collation, identity, generation, dialect options, …).
+type AlterColumn struct {
+	Table  *core.Table
+	Old    *core.Column
+	New    *core.Column
+	Fields []FieldChange
+}
+
+// AddConstraint adds a table-level constraint.
+type AddConstraint struct {
+	Table      *core.Table
+	Constraint *core.Constraint
+}
+
+// DropConstraint removes a table-level constraint.
+type DropConstraint struct {
+	Table      *core.Table
+	Constraint *core.Constraint
+}
+
+// AddIndex creates an index on an existing table.
+type AddIndex struct {
+	Table *core.Table
+	Index *core.Index
+}
+
+// DropIndex removes an index from an existing table.
+type DropIndex struct {
+	Table *core.Table
+	Index *core.Index
+}
+
+// AddView creates a view. Old holds the definition of a view that is
+// recreated, either because it changed or because an object it selects
+// from changed, and is nil for a new view.
+type AddView struct {
+	View *core.View
+	Old  *core.View
+}
+
+// DropView removes an existing view. View holds the last known definition.
+type DropView struct {
+	View *core.View
+}
+
+func (c *AddTable) Kind() Kind           { return KindAddTable }
+func (c *DropTable) Kind() Kind          { return KindDropTable }
+func (c *ChangeTableComment) Kind() Kind { return KindChangeTableComment }
+func (c *TableOptionChange) Kind() Kind  { return KindTableOptionChange }
+func (c *AddColumn) Kind() Kind          { return KindAddColumn }
+func (c *DropColumn) Kind() Kind         { return KindDropColumn }
+func (c *AlterColumnType) Kind() Kind    { return KindAlterColumnType }
+func (c *AlterNullability) Kind() Kind   { return KindAlterNullability }
+func (c *ChangeDefault) Kind() Kind      { return KindChangeDefault }
+func (c *AlterColumn) Kind() Kind        { return KindAlterColumn }
+func (c *AddConstraint) Kind() Kind      { return KindAddConstraint }
+func (c *DropConstraint) Kind() Kind     { return KindDropConstraint }
+func (c *AddIndex) Kind() Kind           { return KindAddIndex }
+func (c *DropIndex) Kind() Kind          { return KindDropIndex }
+func (c *AddView) Kind() Kind            { return KindAddView }
+func (c *DropView) Kind() Kind           { return KindDropView }
+
+func (c *AddTable) TableName() string           { return c.Table.Name }
+func (c *DropTable) TableName() string          { return c.Table.Name }
+func (c *ChangeTableComment) TableName() string { return c.Table.Name }
+func (c *TableOptionChange) TableName() string  { return c.New.Name }
+func (c *AddColumn) TableName() string          { return c.Table.Name }
+func (c *DropColumn) TableName() string         { return c.Table.Name }
+func (c *AlterColumnType) TableName() string    { return c.Table.Name }
+func (c *AlterNullability) TableName() string   { return c.Table.Name }
+func (c *ChangeDefault) TableName() string      { return c.Table.Name }
+func (c *AlterColumn) TableName() string        { return c.Table.Name }
+func (c *AddConstraint) TableName() string      { return c.Table.Name }
+func (c *DropConstraint) TableName() string     { return c.Table.Name }
+func (c *AddIndex) TableName() string           { return c.Table.Name }
+func (c *DropIndex) TableName() string          { return c.Table.Name }
+func (c *AddView) TableName() string            { return c.View.Name }
+func (c *DropView) TableName() string           { return c.View.Name }
+
+// IsDestructive reports whether applying the change may lose data. A type
+// change is destructive unless it only widens the column (see widens).
+func IsDestructive(c Change) bool {
+	switch c := c.(type) {
+	case *DropTable, *DropColumn:
+		return true
+	case *AlterColumnType:
+		return !widens(c.Old, c.New)
+	default:
+		return false
+	}
+}
+
+// Describe names a change with its kind and the table, or the column, it
+// applies to.
+func Describe(c Change) string {
+	switch c := c.(type) {
+	case *AddColumn:
+		return fmt.Sprintf("%s %s.%s", c.Kind(), c.Table.Name, c.Column.Name)
+	case *DropColumn:
+		return fmt.Sprintf("%s %s.%s", c.Kind(), c.Table.Name, c.Column.Name)
+	case *AlterColumnType:
+		return fmt.Sprintf("%s %s.%s", c.Kind(), c.Table.Name, c.New.Name)
+	case *AlterNullability:
+		return fmt.Sprintf("%s %s.%s", c.Kind(), c.Table.Name, c.New.Name)
+	case *ChangeDefault:
+		return fmt.Sprintf("%s %s.%s", c.Kind(), c.Table.Name, c.New.Name)
+	case *AlterColumn:
+		return fmt.Sprintf("%s %s.%s", c.Kind(), c.Table.Name, c.New.Name)
+	default:
+		return fmt.Sprintf("%s %s", c.Kind(), c.TableName())
+	}
+}
@@ -0,0 +1,224 @@
+package diff
+
+import (
+	"strings"
+)
+
+// Databases store view definitions in their own form: MySQL qualifies
+// every column, quotes every name, aliases every select expression and
+// parenthesizes every condition, and PostgreSQL adds qualifiers, casts and
+// parentheses of its own. canonicalDefinition rewrites a definition into a
+// form that is shared by the server form and the hand-written one, so that
+// a view pulled from a database compares equal to the same view declared
+// in a schema file.
+//
+// The canonical form is only used for comparison. It is deliberately
+// lossy: a change that only moves a column between two tables of a join,
+// or only adds grouping parentheses, is not reported.
+func canonicalDefinition(def string) string {
+	toks := definitionTokens(def)
+	for len(toks) > 0 && toks[len(toks)-1] == ";" {
+		toks = toks[:len(toks)-1]
+	}
+	toks = dropCasts(toks)
+	toks = dropQualifiers(toks)
+	toks = dropNoiseWords(toks)
+	toks = dropSelfAliases(toks)
+	toks = dropGroupingParens(toks)
+	return strings.Join(toks, " ")
+}
+
+// definitionTokens splits def into lower-cased tokens. Comments are
+// dropped, quoted identifiers are unquoted, and string literals are kept
+// verbatim.
+func definitionTokens(def string) []string {
+	var toks []string
+	for i := 0; i < len(def); {
+		var tok string
+		tok, i = nextToken(def, i)
+		if tok != "" {
+			toks = append(toks, tok)
+		}
+	}
+	return toks
+}
+
+// nextToken returns the token of s that starts at i, or an empty string
+// for whitespace and comments, and the index after it.
+func nextToken(s string, i int) (string, int) {
+	switch c := s[i]; {
+	case strings.IndexByte(" \t\r\n", c) >= 0:
+		return "", i + 1
+	case c == '\'':
+		end := skipQuoted(s, i, c)
+		return s[i:end], end
+	case strings.IndexByte("\"`[", c) >= 0:
+		end := skipQuoted(s, i, closingQuote(c))
+		return strings.ToLower(s[i+1 : max(i+1, end-1)]), end
+	case strings.HasPrefix(s[i:], "--"):
+		return "", skipUntil(s, i, "\n")
+	case strings.HasPrefix(s[i:], "/*"):
+		return "", skipUntil(s, i, "*/")
+	case isIdentPart(c):
+		return wordToken(s, i)
+	default:
+		return operatorToken(s, i)
+	}
+}
+
+// wordToken returns the name, keyword or number that starts at i.
+func wordToken(s string, i int) (string, int) {
+	number := s[i] >= '0' && s[i] <= '9'
+	end := i + 1
+	for end < len(s) && (isIdentPart(s[end]) || number && s[end] == '.') {
+		end++
+	}
+	return strings.ToLower(s[i:end]), end
+}
+
+// operators lists the operators of more than one character.
+var operators = []string{"::", "<>", "!=", "<=", ">=", "||"}
+
+func operatorToken(s string, i int) (string, int) {
+	for _, op := range operators {
+		if strings.HasPrefix(s[i:], op) {
+			if op == "!=" {
+				return "<>", i + len(op)
+			}
+			return op, i + len(op)
+		}
+	}
+	return s[i : i+1], i + 1
+}
+
+func isName(tok string) bool {
+	return tok != "" && isIdentStart(tok[0])
+}
+
+// castWords lists the words that continue a multi-word type name.
+var castWords = map[string]bool{"varying": true, "precision": true, "with": true, "without": true, "time": true, "zone": true}
+
+// dropCasts removes the PostgreSQL casts (::type) that pg_get_viewdef adds
+// to literals and expressions.
+func dropCasts(toks []string) []string {
+	out := make([]string, 0, len(toks))
+	for i := 0; i < len(toks); i++ {
+		if toks[i] == "::" {
+			i = skipCast(toks, i+1)
+			continue
+		}
+		out = append(out, toks[i])
+	}
+	return out
+}
+
+// skipCast returns the index of the last token of the type name that
+// starts at i, including its modifiers and array brackets.
+func skipCast(toks []string, i int) int {
+	for i+1 < len(toks) && castWords[toks[i+1]] {
+		i++
+	}
+	if i+1 < len(toks) && toks[i+1] == "(" {
+		i = matchingParen(toks, i+1)
+	}
+	for i+2 < len(toks) && toks[i+1] == "[" && toks[i+2] == "]" {
+		i += 2
+	}
+	return i
+}
+
+// dropQualifiers removes the table and schema qualifiers of names.
+func dropQualifiers(toks []string) []string {
+	out := make([]string, 0, len(toks))
+	for _, tok := range toks {
+		if tok == "." && len(out) > 0 && isName(out[len(out)-1]) {
+			out = out[:len(out)-1]
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// dropNoiseWords removes the optional INNER and OUTER of joins.
+func dropNoiseWords(toks []string) []string {
+	out := make([]string, 0, len(toks))
+	for i, tok := range toks {
+		if (tok == "inner" || tok == "outer") && i+1 < len(toks) && toks[i+1] == "join" {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// dropSelfAliases removes the aliases that repeat the name of the column
+// they follow, which MySQL adds to every select expression.
+func dropSelfAliases(toks []string) []string {
+	out := make([]string, 0, len(toks))
+	for i := 0; i < len(toks); i++ {
+		if toks[i] == "as" && len(out) > 0 && i+1 < len(toks) && toks[i+1] == out[len(out)-1] {
+			i++
+			continue
+		}
+		out = append(out, toks[i])
+	}
+	return out
+}
+
+// groupingKeywords lists the words after which a parenthesis groups an
+// expression or a join rather than opening an argument or value list.
+var groupingKeywords = map[string]bool{
+	"select": true, "from": true, "where": true, "on": true, "having": true,
+	"and": true, "or": true, "when": true, "then": true, "else": true,
+}
+
+// dropGroupingParens removes the parentheses that only group an expression
+// or a join. The parentheses of function calls, value lists and subqueries
+// are kept.
+func dropGroupingParens(toks []string) []string {
+	drop := make(map[int]bool)
+	for i, tok := range toks {
+		if tok != "(" || !groupingParen(toks, i) {
+			continue
+		}
+		drop[i] = true
+		drop[matchingParen(toks, i)] = true
+	}
+	out := make([]string, 0, len(toks))
+	for i, tok := range toks {
+		if !drop[i] {
+			out = append(out, tok)
+		}
+	}
+	return out
+}
+
+func groupingParen(toks []string, i int) bool {
+	if i+1 < len(toks) && (toks[i+1] == "select" || toks[i+1] == "with") {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	prev := toks[i-1]
+	return groupingKeywords[prev] || !isName(prev) && prev != ")" && prev[0] != '\''
+}
+
+// matchingParen returns the index of the parenthesis that closes the one
+// at i, or the last index when it is not closed.
+func matchingParen(toks []string, i int) int {
+	depth := 0
+	for j := i; j < len(toks); j++ {
+		switch toks[j] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(toks) - 1
+}
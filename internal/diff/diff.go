@@ -0,0 +1,217 @@
+// Package diff compares two core.Database values and produces an ordered,
+// typed change set that turns the first schema into the second. The change
+// set is dialect-agnostic; generators render it to SQL for a given dialect.
+package diff
+
+import (
+	"slices"
+	"sort"
+
+	"smf/internal/core"
+)
+
+// ChangeSet is the ordered list of changes that migrate From into To.
+type ChangeSet struct {
+	From    *core.Database
+	To      *core.Database
+	Changes []Change
+}
+
+// Empty reports whether the change set contains no changes.
+func (cs *ChangeSet) Empty() bool {
+	return cs == nil || len(cs.Changes) == 0
+}
+
+// Destructive returns the changes that may lose data when applied.
+func (cs *ChangeSet) Destructive() []Change {
+	if cs == nil {
+		return nil
+	}
+	var out []Change
+	for _, c := range cs.Changes {
+		if IsDestructive(c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Filter returns a copy of the change set that keeps only the changes for
+// which keep returns true.
+func (cs *ChangeSet) Filter(keep func(Change) bool) *ChangeSet {
+	out := &ChangeSet{From: cs.From, To: cs.To}
+	for _, c := range cs.Changes {
+		if keep(c) {
+			out.Changes = append(out.Changes, c)
+		}
+	}
+	return out
+}
+
+// Change phases. Changes are emitted phase by phase so that every statement
+// only depends on objects that already exist: views are dropped first and
+// created last, and foreign keys are dropped before the indexes and tables
+// they rely on and are added back after them.
+const (
+	phaseDropView = iota
+	phaseDropForeignKey
+	phaseDropIndex
+	phaseDropConstraint
+	phaseDropTable
+	phaseAddTable
+	phaseTable
+	phaseDropColumn
+	phaseAddColumn
+	phaseAlterColumn
+	phaseAddConstraint
+	phaseAddIndex
+	phaseAddForeignKey
+	phaseAddView
+)
+
+// Databases compares from and to and returns the changes needed to migrate
+// from into to. A nil from is treated as an empty database, which yields an
+// AddTable change for every table and an AddView change for every view in
+// to.
+func Databases(from, to *core.Database) *ChangeSet {
+	if from == nil {
+		from = &core.Database{}
+	}
+	if to == nil {
+		to = &core.Database{}
+	}
+
+	var changes []Change
+	var added []*core.Table
+	for _, t := range to.Tables {
+		old := from.FindTable(t.Name)
+		if old == nil {
+			added = append(added, t)
+			continue
+		}
+		changes = append(changes, Table(old, t)...)
+	}
+
+	var dropped []*core.Table
+	for _, t := range from.Tables {
+		if to.FindTable(t.Name) == nil {
+			dropped = append(dropped, t)
+		}
+	}
+
+	for _, t := range SortByDependencies(added) {
+		changes = append(changes, &AddTable{Table: t})
+	}
+	sortedDrops := SortByDependencies(dropped)
+	slices.Reverse(sortedDrops)
+	for _, t := range sortedDrops {
+		changes = append(changes, &DropTable{Table: t})
+	}
+	changes = append(changes, viewChanges(from, to, changes)...)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return phase(changes[i]) < phase(changes[j])
+	})
+
+	return &ChangeSet{From: from, To: to, Changes: changes}
+}
+
+// Table compares two versions of the same table and returns the unordered
+// changes between them.
+func Table(from, to *core.Table) []Change {
+	var changes []Change
+
+	if from.Comment != to.Comment {
+		changes = append(changes, &ChangeTableComment{Table: to, Old: from.Comment, New: to.Comment})
+	}
+	if fields := tableOptionsCompare.compare(from.Options, to.Options); len(fields) > 0 {
+		changes = append(changes, &TableOptionChange{Old: from, New: to, Fields: fields})
+	}
+
+	changes = append(changes, columnChanges(from, to)...)
+	changes = append(changes, constraintChanges(from, to)...)
+	changes = append(changes, indexChanges(from, to)...)
+	return changes
+}
+
+func phase(c Change) int {
+	switch c := c.(type) {
+	case *DropConstraint:
+		if c.Constraint.Type == core.ConstraintForeignKey {
+			return phaseDropForeignKey
+		}
+		return phaseDropConstraint
+	case *DropIndex:
+		return phaseDropIndex
+	case *DropTable:
+		return phaseDropTable
+	case *AddTable:
+		return phaseAddTable
+	case *ChangeTableComment, *TableOptionChange:
+		return phaseTable
+	case *DropColumn:
+		return phaseDropColumn
+	case *AddColumn:
+		return phaseAddColumn
+	case *AddConstraint:
+		if c.Constraint.Type == core.ConstraintForeignKey {
+			return phaseAddForeignKey
+		}
+		return phaseAddConstraint
+	case *AddIndex:
+		return phaseAddIndex
+	case *DropView:
+		return phaseDropView
+	case *AddView:
+		return phaseAddView
+	default:
+		return phaseAlterColumn
+	}
+}
+
+// SortByDependencies orders tables so that every table comes after the
+// tables its foreign keys reference. Only references between tables in the
+// given slice are considered. Tables that are part of a reference cycle keep
+// their original relative order and are placed after all other tables.
+func SortByDependencies(tables []*core.Table) []*core.Table {
+	inSet := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		inSet[t.Name] = true
+	}
+
+	placed := make(map[string]bool, len(tables))
+	sorted := make([]*core.Table, 0, len(tables))
+	for len(sorted) < len(tables) {
+		progress := false
+		for _, t := range tables {
+			if placed[t.Name] || !dependenciesPlaced(t, inSet, placed) {
+				continue
+			}
+			placed[t.Name] = true
+			sorted = append(sorted, t)
+			progress = true
+		}
+		if !progress {
+			break
+		}
+	}
+
+	for _, t := range tables {
+		if !placed[t.Name] {
+			sorted = append(sorted, t)
+		}
+	}
+	return sorted
+}
+
+func dependenciesPlaced(t *core.Table, inSet, placed map[string]bool) bool {
+	for _, con := range t.Constraints {
+		if con.Type != core.ConstraintForeignKey || con.ReferencedTable == t.Name {
+			continue
+		}
+		if inSet[con.ReferencedTable] && !placed[con.ReferencedTable] {
+			return false
+		}
+	}
+	return true
+}
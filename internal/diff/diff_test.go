@@ -0,0 +1,125 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func usersTable() *core.Table {
+	return &core.Table{
+		Name: "users",
+		Columns: []*core.Column{
+			{Name: "id", Type: core.DataTypeInt, PortableType: "bigint"},
+			{Name: "email", Type: core.DataTypeString, PortableType: "varchar(255)"},
+		},
+		Constraints: []*core.Constraint{
+			{Name: "pk_users", Type: core.ConstraintPrimaryKey, Columns: []string{"id"}},
+		},
+	}
+}
+
+func ordersTable() *core.Table {
+	return &core.Table{
+		Name: "orders",
+		Columns: []*core.Column{
+			{Name: "id", Type: core.DataTypeInt, PortableType: "bigint"},
+			{Name: "user_id", Type: core.DataTypeInt, PortableType: "bigint"},
+		},
+		Constraints: []*core.Constraint{
+			{Name: "pk_orders", Type: core.ConstraintPrimaryKey, Columns: []string{"id"}},
+			{
+				Name:              "fk_orders_users",
+				Type:              core.ConstraintForeignKey,
+				Columns:           []string{"user_id"},
+				ReferencedTable:   "users",
+				ReferencedColumns: []string{"id"},
+			},
+		},
+	}
+}
+
+func kinds(cs *ChangeSet) []Kind {
+	out := make([]Kind, 0, len(cs.Changes))
+	for _, c := range cs.Changes {
+		out = append(out, c.Kind())
+	}
+	return out
+}
+
+func TestDatabasesIdentical(t *testing.T) {
+	from := &core.Database{Tables: []*core.Table{usersTable(), ordersTable()}}
+	to := &core.Database{Tables: []*core.Table{usersTable(), ordersTable()}}
+
+	cs := Databases(from, to)
+	assert.True(t, cs.Empty())
+}
+
+func TestDatabasesAddTablesInDependencyOrder(t *testing.T) {
+	to := &core.Database{Tables: []*core.Table{ordersTable(), usersTable()}}
+
+	cs := Databases(nil, to)
+	require.Len(t, cs.Changes, 2)
+	assert.Equal(t, "users", cs.Changes[0].TableName())
+	assert.Equal(t, "orders", cs.Changes[1].TableName())
+	assert.Equal(t, []Kind{KindAddTable, KindAddTable}, kinds(cs))
+}
+
+func TestDatabasesDropTablesInReverseDependencyOrder(t *testing.T) {
+	from := &core.Database{Tables: []*core.Table{usersTable(), ordersTable()}}
+
+	cs := Databases(from, &core.Database{})
+	require.Len(t, cs.Changes, 2)
+	assert.Equal(t, "orders", cs.Changes[0].TableName())
+	assert.Equal(t, "users", cs.Changes[1].TableName())
+	assert.Len(t, cs.Destructive(), 2)
+}
+
+func TestDatabasesPhaseOrdering(t *testing.T) {
+	from := &core.Database{Tables: []*core.Table{usersTable(), ordersTable()}}
+
+	users := usersTable()
+	users.Columns = append(users.Columns, &core.Column{Name: "name", Type: core.DataTypeString})
+	users.Indexes = []*core.Index{{Name: "idx_users_email", Columns: []core.ColumnIndex{{Name: "email"}}}}
+	orders := ordersTable()
+	orders.Constraints = orders.Constraints[:1]
+	to := &core.Database{Tables: []*core.Table{users, orders, {
+		Name:    "audit",
+		Columns: []*core.Column{{Name: "id", Type: core.DataTypeInt}},
+	}}}
+
+	cs := Databases(from, to)
+	assert.Equal(t, []Kind{KindDropConstraint, KindAddTable, KindAddColumn, KindAddIndex}, kinds(cs))
+
+	add, ok := cs.Changes[2].(*AddColumn)
+	require.True(t, ok)
+	assert.Equal(t, "email", add.After)
+}
+
+func TestSortByDependenciesCycle(t *testing.T) {
+	a := &core.Table{Name: "a", Constraints: []*core.Constraint{
+		{Type: core.ConstraintForeignKey, ReferencedTable: "b"},
+	}}
+	b := &core.Table{Name: "b", Constraints: []*core.Constraint{
+		{Type: core.ConstraintForeignKey, ReferencedTable: "a"},
+	}}
+	c := &core.Table{Name: "c"}
+
+	sorted := SortByDependencies([]*core.Table{a, b, c})
+	require.Len(t, sorted, 3)
+	assert.Equal(t, "c", sorted[0].Name)
+	assert.Equal(t, "a", sorted[1].Name)
+	assert.Equal(t, "b", sorted[2].Name)
+}
+
+func TestChangeSetFilter(t *testing.T) {
+	cs := Databases(nil, &core.Database{Tables: []*core.Table{usersTable(), ordersTable()}})
+
+	filtered := cs.Filter(func(c Change) bool { return c.TableName() == "orders" })
+	require.Len(t, filtered.Changes, 1)
+	assert.Equal(t, "orders", filtered.Changes[0].TableName())
+	assert.Len(t, cs.Changes, 2)
+}
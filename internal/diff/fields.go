@@ -0,0 +1,115 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fieldsCompare walks two values of the same struct type field by field and
+// reports every leaf attribute that differs. Field names are taken from the
+// toml struct tags, so nested dialect groups are reported as "mysql.engine".
+type fieldsCompare struct {
+	// skip lists top-level field names that are compared elsewhere.
+	skip map[string]bool
+	// ignoreUnsetTarget drops changes whose target value is the zero value.
+	// Table options use it so that options the schema does not declare are
+	// left to the database default instead of being reported as removed.
+	ignoreUnsetTarget bool
+}
+
+func (fc fieldsCompare) compare(from, to any) []FieldChange {
+	var changes []FieldChange
+	fc.walk("", reflect.ValueOf(from), reflect.ValueOf(to), &changes)
+	return changes
+}
+
+func (fc fieldsCompare) walk(prefix string, from, to reflect.Value, out *[]FieldChange) {
+	from, to = derefStruct(from), derefStruct(to)
+	if from.Kind() != reflect.Struct {
+		fc.leaf(prefix, from, to, out)
+		return
+	}
+
+	typ := from.Type()
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name := tagName(field)
+		if name == "" || (prefix == "" && fc.skip[name]) {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		fc.walk(name, from.Field(i), to.Field(i), out)
+	}
+}
+
+func (fc fieldsCompare) leaf(name string, from, to reflect.Value, out *[]FieldChange) {
+	oldVal, newVal := leafValue(from), leafValue(to)
+	if equalValues(oldVal, newVal) {
+		return
+	}
+	if fc.ignoreUnsetTarget && isZero(newVal) {
+		return
+	}
+	*out = append(*out, FieldChange{Name: name, Old: oldVal, New: newVal})
+}
+
+// derefStruct follows pointers to structs, substituting the zero struct
+// for nil so that an absent option group compares equal to an empty one.
+func derefStruct(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer && v.Type().Elem().Kind() == reflect.Struct {
+		if v.IsNil() {
+			return reflect.Zero(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// leafValue converts a scalar, slice, or pointer-to-scalar field to a plain
+// value. Nil pointers become nil.
+func leafValue(v reflect.Value) any {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+func equalValues(a, b any) bool {
+	if isEmptySlice(a) && isEmptySlice(b) {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func isEmptySlice(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Slice && rv.Len() == 0
+}
+
+func isZero(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice {
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+func tagName(f reflect.StructField) string {
+	tag := f.Tag.Get("toml")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return strings.ToLower(f.Name)
+	}
+	return name
+}
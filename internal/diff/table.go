@@ -0,0 +1,306 @@
+package diff
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// tableOptionsCompare compares core.TableOptions. Options that the target
+// schema leaves unset are not reported (see fieldsCompare.ignoreUnsetTarget).
+var tableOptionsCompare = fieldsCompare{ignoreUnsetTarget: true}
+
+// columnAttributesCompare compares the column attributes reported by
+// AlterColumn. The skipped fields are either compared by dedicated change
+// types or are column-level shorthand that the parser expands into
+// constraints, which are compared separately.
+var columnAttributesCompare = fieldsCompare{skip: map[string]bool{
+	"name":          true,
+	"raw_type":      true,
+	"type":          true,
+	"portable_type": true,
+	"enum_values":   true,
+	"nullable":      true,
+	"default_value": true,
+	"primary_key":   true,
+	"unique":        true,
+	"check":         true,
+	"references":    true,
+	"ref_on_delete": true,
+	"ref_on_update": true,
+}}
+
+var wsRe = regexp.MustCompile(`\s+`)
+
+func columnChanges(from, to *core.Table) []Change {
+	var changes []Change
+	for i, col := range to.Columns {
+		old := from.FindColumn(col.Name)
+		if old == nil {
+			after := ""
+			if i > 0 {
+				after = to.Columns[i-1].Name
+			}
+			changes = append(changes, &AddColumn{Table: to, Column: col, After: after})
+			continue
+		}
+		changes = append(changes, Column(to, old, col)...)
+	}
+
+	for _, col := range from.Columns {
+		if to.FindColumn(col.Name) == nil {
+			changes = append(changes, &DropColumn{Table: to, Column: col})
+		}
+	}
+	return changes
+}
+
+// Column compares two versions of the same column of table t.
+func Column(t *core.Table, from, to *core.Column) []Change {
+	var changes []Change
+	if !SameType(from, to) {
+		changes = append(changes, &AlterColumnType{Table: t, Old: from, New: to})
+	}
+	if from.Nullable != to.Nullable {
+		changes = append(changes, &AlterNullability{Table: t, Old: from, New: to})
+	}
+	if !equalStringPtr(from.DefaultValue, to.DefaultValue) {
+		changes = append(changes, &ChangeDefault{Table: t, Old: from, New: to})
+	}
+	if fields := columnAttributesCompare.compare(from, to); len(fields) > 0 {
+		changes = append(changes, &AlterColumn{Table: t, Old: from, New: to, Fields: fields})
+	}
+	return changes
+}
+
+// SameType reports whether two columns have the same data type. The declared
+// type strings (RawType, falling back to PortableType) are compared when both
+// columns have one; otherwise the normalized portable categories are compared.
+func SameType(a, b *core.Column) bool {
+	ta, tb := declaredType(a), declaredType(b)
+	if ta != "" && tb != "" {
+		if ta != tb {
+			return false
+		}
+	} else if a.Type != b.Type {
+		return false
+	}
+	return slices.Equal(a.EnumValues, b.EnumValues)
+}
+
+func declaredType(c *core.Column) string {
+	t := c.RawType
+	if t == "" {
+		t = c.PortableType
+	}
+	return normalizeSQL(t)
+}
+
+// normalizeSQL lower-cases and collapses whitespace so that cosmetic
+// differences in type strings and expressions are not reported.
+func normalizeSQL(s string) string {
+	s = wsRe.ReplaceAllString(strings.TrimSpace(s), " ")
+	s = strings.ReplaceAll(s, "( ", "(")
+	s = strings.ReplaceAll(s, " )", ")")
+	s = strings.ReplaceAll(s, ", ", ",")
+	return strings.ToLower(s)
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return strings.TrimSpace(*a) == strings.TrimSpace(*b)
+}
+
+func constraintChanges(from, to *core.Table) []Change {
+	var changes []Change
+	for _, con := range to.Constraints {
+		old := matchConstraint(from, con)
+		switch {
+		case old == nil:
+			changes = append(changes, &AddConstraint{Table: to, Constraint: con})
+		case !SameConstraint(old, con):
+			changes = append(changes,
+				&DropConstraint{Table: to, Constraint: old},
+				&AddConstraint{Table: to, Constraint: con},
+			)
+		}
+	}
+
+	for _, con := range from.Constraints {
+		if matchConstraint(to, con) == nil {
+			changes = append(changes, &DropConstraint{Table: to, Constraint: con})
+		}
+	}
+	return changes
+}
+
+// matchConstraint finds the counterpart of con in t. Primary keys are matched
+// by type because a table has at most one and dialects name them differently.
+// Other constraints are matched by name, or by definition when either side
+// is unnamed.
+func matchConstraint(t *core.Table, con *core.Constraint) *core.Constraint {
+	if con.Type == core.ConstraintPrimaryKey {
+		return t.PrimaryKey()
+	}
+	if con.Name != "" {
+		if c := t.FindConstraint(con.Name); c != nil {
+			return c
+		}
+	}
+	for _, c := range t.Constraints {
+		if (c.Name == "" || con.Name == "") && SameConstraint(c, con) {
+			return c
+		}
+	}
+	return nil
+}
+
+// SameConstraint reports whether two constraints have the same definition.
+// Names are not compared, nor are the columns of CHECK constraints: they
+// only record whether the check was declared on a column or on the table.
+func SameConstraint(a, b *core.Constraint) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	switch a.Type {
+	case core.ConstraintForeignKey:
+		return slices.Equal(a.Columns, b.Columns) &&
+			a.ReferencedTable == b.ReferencedTable &&
+			slices.Equal(a.ReferencedColumns, b.ReferencedColumns) &&
+			refAction(a.OnDelete) == refAction(b.OnDelete) &&
+			refAction(a.OnUpdate) == refAction(b.OnUpdate)
+	case core.ConstraintCheck:
+		return normalizeCheck(a.CheckExpression) == normalizeCheck(b.CheckExpression) &&
+			enforced(a) == enforced(b)
+	default:
+		return slices.Equal(a.Columns, b.Columns)
+	}
+}
+
+// refAction maps the implicit default action to NO ACTION.
+func refAction(ra core.ReferentialAction) core.ReferentialAction {
+	if ra == core.RefActionNone {
+		return core.RefActionNoAction
+	}
+	return ra
+}
+
+func enforced(c *core.Constraint) bool {
+	return c.Enforced == nil || *c.Enforced
+}
+
+// normalizeCheck strips redundant outer parentheses, which databases add
+// when they store CHECK expressions, before normalizing the expression.
+func normalizeCheck(expr string) string {
+	expr = normalizeSQL(expr)
+	for strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") && balanced(expr[1:len(expr)-1]) {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	return expr
+}
+
+// balanced reports whether the parentheses in s are balanced, so that
+// "(a) and (b)" is not mistaken for a fully parenthesized expression.
+func balanced(s string) bool {
+	depth := 0
+	for _, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+func indexChanges(from, to *core.Table) []Change {
+	var changes []Change
+	for _, idx := range to.Indexes {
+		old := matchIndex(from, idx)
+		switch {
+		case old == nil:
+			changes = append(changes, &AddIndex{Table: to, Index: idx})
+		case !SameIndex(old, idx):
+			changes = append(changes,
+				&DropIndex{Table: to, Index: old},
+				&AddIndex{Table: to, Index: idx},
+			)
+		}
+	}
+
+	for _, idx := range from.Indexes {
+		if matchIndex(to, idx) == nil {
+			changes = append(changes, &DropIndex{Table: to, Index: idx})
+		}
+	}
+	return changes
+}
+
+// matchIndex finds the counterpart of idx in t by name, or by definition
+// when either side is unnamed.
+func matchIndex(t *core.Table, idx *core.Index) *core.Index {
+	if idx.Name != "" {
+		if i := t.FindIndex(idx.Name); i != nil {
+			return i
+		}
+	}
+	for _, i := range t.Indexes {
+		if (i.Name == "" || idx.Name == "") && SameIndex(i, idx) {
+			return i
+		}
+	}
+	return nil
+}
+
+// SameIndex reports whether two indexes have the same definition. Names are
+// not compared. Unset type, visibility, and sort order compare equal to
+// their defaults (BTREE, VISIBLE, ASC).
+func SameIndex(a, b *core.Index) bool {
+	if a.Unique != b.Unique || a.Comment != b.Comment || a.Parser != b.Parser ||
+		normalizeSQL(a.Where) != normalizeSQL(b.Where) ||
+		indexType(a.Type) != indexType(b.Type) ||
+		indexVisibility(a.Visibility) != indexVisibility(b.Visibility) ||
+		len(a.Columns) != len(b.Columns) {
+		return false
+	}
+	for i := range a.Columns {
+		if !sameKeyPart(a.Columns[i], b.Columns[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameKeyPart(a, b core.ColumnIndex) bool {
+	return a.Name == b.Name && a.Length == b.Length && sortOrder(a.Order) == sortOrder(b.Order) &&
+		normalizeSQL(a.Expression) == normalizeSQL(b.Expression)
+}
+
+func indexType(it core.IndexType) core.IndexType {
+	if it == "" {
+		return core.IndexTypeBTree
+	}
+	return it
+}
+
+func indexVisibility(iv core.IndexVisibility) core.IndexVisibility {
+	if iv == "" {
+		return core.IndexVisible
+	}
+	return iv
+}
+
+func sortOrder(so core.SortOrder) core.SortOrder {
+	if so == "" {
+		return core.SortAsc
+	}
+	return so
+}
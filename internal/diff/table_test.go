@@ -0,0 +1,230 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestColumnTypeChange(t *testing.T) {
+	tbl := usersTable()
+	from := &core.Column{Name: "email", Type: core.DataTypeString, PortableType: "varchar(255)"}
+	to := &core.Column{Name: "email", Type: core.DataTypeString, PortableType: "VARCHAR( 64 )"}
+
+	changes := Column(tbl, from, to)
+	require.Len(t, changes, 1)
+	assert.IsType(t, &AlterColumnType{}, changes[0])
+	assert.True(t, IsDestructive(changes[0]))
+	assert.False(t, IsDestructive(Column(tbl, to, from)[0]))
+}
+
+func TestWidens(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"varchar(100)", "varchar(120)", true},
+		{"varchar(120)", "varchar(100)", false},
+		{"char(10)", "varchar(10)", true},
+		{"varchar(10)", "char(10)", false},
+		{"varchar(255)", "text", true},
+		{"text", "varchar(255)", false},
+		{"text", "longtext", true},
+		{"int", "bigint", true},
+		{"integer", "bigint", true},
+		{"bigint", "int", false},
+		{"int unsigned", "bigint", true},
+		{"int unsigned", "int", false},
+		{"int", "int unsigned", false},
+		{"float", "double precision", true},
+		{"double", "float", false},
+		{"decimal(10,2)", "numeric(12,2)", true},
+		{"decimal(10,2)", "decimal(12,4)", true},
+		{"decimal(10,2)", "decimal(10,4)", false},
+		{"decimal(10,2)", "decimal(10,1)", false},
+		{"timestamp(3)", "timestamp(6)", true},
+		{"timestamp(6)", "timestamp(3)", false},
+		{"int", "varchar(20)", false},
+		{"varchar(20)", "int", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			t.Parallel()
+			from := &core.Column{Name: "c", PortableType: tt.from}
+			to := &core.Column{Name: "c", PortableType: tt.to}
+			assert.Equal(t, tt.want, widens(from, to))
+		})
+	}
+}
+
+func TestWidensEnum(t *testing.T) {
+	t.Parallel()
+	from := &core.Column{Name: "c", Type: core.DataTypeEnum, EnumValues: []string{"a", "b"}}
+	to := &core.Column{Name: "c", Type: core.DataTypeEnum, EnumValues: []string{"a", "b", "c"}}
+
+	assert.True(t, widens(from, to))
+	assert.False(t, widens(to, from))
+	assert.False(t, widens(&core.Column{Name: "c", PortableType: "varchar(10)"}, from))
+}
+
+func TestColumnTypeCosmeticDifference(t *testing.T) {
+	tbl := usersTable()
+	from := &core.Column{Name: "email", Type: core.DataTypeString, RawType: "varchar(255)"}
+	to := &core.Column{Name: "email", Type: core.DataTypeString, PortableType: "VARCHAR(255)"}
+
+	assert.Empty(t, Column(tbl, from, to))
+}
+
+func TestColumnNullabilityAndDefault(t *testing.T) {
+	tbl := usersTable()
+	from := &core.Column{Name: "plan", Type: core.DataTypeString}
+	to := &core.Column{Name: "plan", Type: core.DataTypeString, Nullable: true, DefaultValue: new("free")}
+
+	changes := Column(tbl, from, to)
+	require.Len(t, changes, 2)
+	assert.IsType(t, &AlterNullability{}, changes[0])
+	assert.IsType(t, &ChangeDefault{}, changes[1])
+}
+
+func TestColumnAttributes(t *testing.T) {
+	tbl := usersTable()
+	from := &core.Column{Name: "name", Type: core.DataTypeString}
+	to := &core.Column{
+		Name:    "name",
+		Type:    core.DataTypeString,
+		Comment: "display name",
+		Unique:  true,
+		TiDB:    &core.TiDBColumnOptions{ShardBits: 5},
+	}
+
+	changes := Column(tbl, from, to)
+	require.Len(t, changes, 1)
+	alter, ok := changes[0].(*AlterColumn)
+	require.True(t, ok)
+	assert.Equal(t, []FieldChange{
+		{Name: "comment", Old: "", New: "display name"},
+		{Name: "tidb.shard_bits", Old: uint64(0), New: uint64(5)},
+	}, alter.Fields)
+}
+
+func TestTableOptionChanges(t *testing.T) {
+	from := usersTable()
+	from.Options.MySQL = &core.MySQLTableOptions{Engine: "InnoDB", AutoIncrement: 42, RowFormat: "DYNAMIC"}
+	to := usersTable()
+	to.Options.MySQL = &core.MySQLTableOptions{Engine: "MyISAM"}
+	to.Options.SQLite = &core.SQLiteTableOptions{Strict: true}
+	to.Comment = "people"
+
+	changes := Table(from, to)
+	require.Len(t, changes, 2)
+
+	comment, ok := changes[0].(*ChangeTableComment)
+	require.True(t, ok)
+	assert.Equal(t, "people", comment.New)
+
+	opts, ok := changes[1].(*TableOptionChange)
+	require.True(t, ok)
+	assert.Equal(t, []FieldChange{
+		{Name: "mysql.engine", Old: "InnoDB", New: "MyISAM"},
+		{Name: "sqlite.strict", Old: false, New: true},
+	}, opts.Fields)
+}
+
+func TestConstraintChanges(t *testing.T) {
+	from := ordersTable()
+	from.Constraints[0].Name = "PRIMARY"
+	to := ordersTable()
+	to.Constraints[1].OnDelete = core.RefActionCascade
+	to.Constraints = append(to.Constraints, &core.Constraint{
+		Name:            "chk_orders_id",
+		Type:            core.ConstraintCheck,
+		CheckExpression: "id > 0",
+	})
+
+	changes := Table(from, to)
+	require.Len(t, changes, 3)
+	assert.IsType(t, &DropConstraint{}, changes[0])
+	assert.IsType(t, &AddConstraint{}, changes[1])
+	assert.Equal(t, core.RefActionCascade, changes[1].(*AddConstraint).Constraint.OnDelete)
+	assert.IsType(t, &AddConstraint{}, changes[2])
+}
+
+func TestSameConstraintCheckNormalization(t *testing.T) {
+	a := &core.Constraint{Type: core.ConstraintCheck, CheckExpression: "((`price` > 0))"}
+	b := &core.Constraint{Type: core.ConstraintCheck, CheckExpression: "`price`  > 0", Enforced: new(true)}
+	c := &core.Constraint{Type: core.ConstraintCheck, CheckExpression: "(a > 0) and (b > 0)"}
+	d := &core.Constraint{Type: core.ConstraintCheck, CheckExpression: "a > 0) and (b > 0"}
+
+	assert.True(t, SameConstraint(a, b))
+	assert.False(t, SameConstraint(c, d))
+}
+
+func TestSameConstraintCheckIgnoresColumns(t *testing.T) {
+	column := &core.Constraint{Type: core.ConstraintCheck, Columns: []string{"age"}, CheckExpression: "age >= 0"}
+	table := &core.Constraint{Type: core.ConstraintCheck, CheckExpression: "age >= 0"}
+	unique := &core.Constraint{Type: core.ConstraintUnique, Columns: []string{"age"}}
+
+	assert.True(t, SameConstraint(column, table))
+	assert.False(t, SameConstraint(unique, &core.Constraint{Type: core.ConstraintUnique}))
+}
+
+func TestIndexChanges(t *testing.T) {
+	from := usersTable()
+	from.Indexes = []*core.Index{
+		{Name: "idx_email", Columns: []core.ColumnIndex{{Name: "email"}}},
+		{Name: "idx_old", Columns: []core.ColumnIndex{{Name: "id"}}},
+	}
+	to := usersTable()
+	to.Indexes = []*core.Index{
+		{
+			Name:       "idx_email",
+			Type:       core.IndexTypeBTree,
+			Visibility: core.IndexVisible,
+			Columns:    []core.ColumnIndex{{Name: "email", Order: core.SortDesc}},
+		},
+	}
+
+	changes := Table(from, to)
+	require.Len(t, changes, 3)
+	assert.IsType(t, &DropIndex{}, changes[0])
+	assert.IsType(t, &AddIndex{}, changes[1])
+	drop, ok := changes[2].(*DropIndex)
+	require.True(t, ok)
+	assert.Equal(t, "idx_old", drop.Index.Name)
+}
+
+func TestSameIndexDefaults(t *testing.T) {
+	a := &core.Index{Columns: []core.ColumnIndex{{Name: "email"}}}
+	b := &core.Index{
+		Type:       core.IndexTypeBTree,
+		Visibility: core.IndexVisible,
+		Columns:    []core.ColumnIndex{{Name: "email", Order: core.SortAsc}},
+	}
+	assert.True(t, SameIndex(a, b))
+}
+
+func TestSameIndexExpression(t *testing.T) {
+	a := &core.Index{Columns: []core.ColumnIndex{{Expression: "lower(email)"}}}
+	b := &core.Index{Columns: []core.ColumnIndex{{Expression: "LOWER(email)"}}}
+	c := &core.Index{Columns: []core.ColumnIndex{{Expression: "upper(email)"}}}
+	assert.True(t, SameIndex(a, b))
+	assert.False(t, SameIndex(a, c))
+}
+
+func TestSameIndexParser(t *testing.T) {
+	a := &core.Index{Type: core.IndexTypeFullText, Parser: "ngram", Columns: []core.ColumnIndex{{Name: "body"}}}
+	b := &core.Index{Type: core.IndexTypeFullText, Columns: []core.ColumnIndex{{Name: "body"}}}
+	assert.False(t, SameIndex(a, b))
+}
+
+func TestSameIndexWhere(t *testing.T) {
+	a := &core.Index{Where: "deleted_at IS NULL", Columns: []core.ColumnIndex{{Name: "email"}}}
+	b := &core.Index{Where: "deleted_at is null", Columns: []core.ColumnIndex{{Name: "email"}}}
+	c := &core.Index{Columns: []core.ColumnIndex{{Name: "email"}}}
+	assert.True(t, SameIndex(a, b))
+	assert.False(t, SameIndex(a, c))
+}
@@ -0,0 +1,145 @@
+package diff
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+
+	"smf/internal/core"
+)
+
+// sqlType is a declared type split into its base name, its arguments
+// (length, or precision and scale) and the MySQL UNSIGNED attribute.
+type sqlType struct {
+	name     string
+	args     []int
+	unsigned bool
+}
+
+var sqlTypeRe = regexp.MustCompile(`^([a-z][a-z0-9 ]*?)\s*(?:\((\d+)(?:,(\d+))?\))?(\s+unsigned)?$`)
+
+// typeAliases maps the alternative spellings of a type to one name.
+var typeAliases = map[string]string{
+	"integer":           "int",
+	"int4":              "int",
+	"int8":              "bigint",
+	"int2":              "smallint",
+	"character varying": "varchar",
+	"character":         "char",
+	"numeric":           "decimal",
+	"double precision":  "double",
+	"float8":            "double",
+	"float4":            "real",
+	"nvarchar":          "varchar",
+	"nchar":             "char",
+}
+
+func parseType(declared string) (sqlType, bool) {
+	m := sqlTypeRe.FindStringSubmatch(declared)
+	if m == nil {
+		return sqlType{}, false
+	}
+	t := sqlType{name: m[1], unsigned: m[4] != ""}
+	if alias, ok := typeAliases[t.name]; ok {
+		t.name = alias
+	}
+	for _, arg := range m[2:4] {
+		if arg != "" {
+			n, _ := strconv.Atoi(arg)
+			t.args = append(t.args, n)
+		}
+	}
+	return t, true
+}
+
+// Ranks of the types whose values are all held by the types ranked above
+// them in the same family.
+var (
+	intRanks   = map[string]int{"tinyint": 1, "smallint": 2, "mediumint": 3, "int": 4, "bigint": 5}
+	floatRanks = map[string]int{"real": 1, "float": 1, "double": 2}
+	textRanks  = map[string]int{"tinytext": 1, "text": 2, "mediumtext": 3, "longtext": 4, "clob": 4}
+)
+
+// widens reports whether changing the type of a column from that of from
+// to that of to keeps every value the column can hold: a longer string, a
+// larger integer or float type, a decimal with more digits on both sides of
+// the point, a higher time precision, or an enum with more values. Every
+// other type change may lose data.
+func widens(from, to *core.Column) bool {
+	if len(from.EnumValues) > 0 || len(to.EnumValues) > 0 {
+		return len(from.EnumValues) > 0 && isSubset(from.EnumValues, to.EnumValues)
+	}
+	a, okA := parseType(declaredType(from))
+	b, okB := parseType(declaredType(to))
+	if !okA || !okB {
+		return false
+	}
+	switch {
+	case intRanks[a.name] > 0 && intRanks[b.name] > 0:
+		return widensInt(a, b)
+	case floatRanks[a.name] > 0 && floatRanks[b.name] > 0:
+		return floatRanks[b.name] >= floatRanks[a.name]
+	case isString(a) && isString(b):
+		return widensString(a, b)
+	case a.name == "decimal" && b.name == "decimal":
+		return widensDecimal(a, b)
+	case a.name == b.name:
+		return len(a.args) == len(b.args) && (len(a.args) == 0 || b.args[0] >= a.args[0])
+	default:
+		return false
+	}
+}
+
+func isSubset(values, of []string) bool {
+	for _, v := range values {
+		if !slices.Contains(of, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// widensInt reports whether every value of integer type a fits in b. An
+// unsigned type only fits in a larger signed type.
+func widensInt(a, b sqlType) bool {
+	switch {
+	case a.unsigned == b.unsigned:
+		return intRanks[b.name] >= intRanks[a.name]
+	case a.unsigned:
+		return intRanks[b.name] > intRanks[a.name]
+	default:
+		return false
+	}
+}
+
+func isString(t sqlType) bool {
+	return t.name == "char" || t.name == "varchar" || textRanks[t.name] > 0
+}
+
+// widensString reports whether every value of string type a fits in b. A
+// VARCHAR does not become a CHAR, which pads its values.
+func widensString(a, b sqlType) bool {
+	switch {
+	case textRanks[b.name] > 0:
+		return textRanks[a.name] <= textRanks[b.name]
+	case textRanks[a.name] > 0, a.name == "varchar" && b.name == "char":
+		return false
+	default:
+		return len(a.args) == 1 && len(b.args) == 1 && b.args[0] >= a.args[0]
+	}
+}
+
+// widensDecimal reports whether decimal b has at least the integer and the
+// fractional digits of a.
+func widensDecimal(a, b sqlType) bool {
+	if len(a.args) == 0 || len(b.args) == 0 {
+		return len(a.args) == len(b.args)
+	}
+	scale := func(t sqlType) int {
+		if len(t.args) == 2 {
+			return t.args[1]
+		}
+		return 0
+	}
+	return b.args[0]-scale(b) >= a.args[0]-scale(a) && scale(b) >= scale(a)
+}
@@ -0,0 +1,236 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// viewAttributesCompare compares the view attributes other than the
+// definition, which is compared by sameDefinition.
+var viewAttributesCompare = fieldsCompare{skip: map[string]bool{"definition": true}}
+
+// View compares two versions of the same view and returns the attributes
+// that differ.
+func View(from, to *core.View) []FieldChange {
+	fields := viewAttributesCompare.compare(*from, *to)
+	if !sameDefinition(from.Definition, to.Definition) {
+		fields = append([]FieldChange{{Name: "definition", Old: from.Definition, New: to.Definition}}, fields...)
+	}
+	return fields
+}
+
+// sameDefinition reports whether two view definitions have the same
+// canonical form (see canonicalDefinition).
+func sameDefinition(a, b string) bool {
+	return canonicalDefinition(a) == canonicalDefinition(b)
+}
+
+// viewChanges returns the changes that migrate the views of from into
+// those of to. A view cannot be altered in place in every dialect, so a
+// changed view is dropped and created again. The views that select from a
+// recreated view or from a table with changes are recreated as well, since
+// the databases that track view dependencies refuse to drop or alter the
+// objects they depend on.
+func viewChanges(from, to *core.Database, tableChanges []Change) []Change {
+	recreate := recreatedViews(from, to, tableChanges)
+
+	var dropped, added []*core.View
+	for _, v := range from.Views {
+		if to.FindView(v.Name) == nil || recreate[v.Name] {
+			dropped = append(dropped, v)
+		}
+	}
+	for _, v := range to.Views {
+		if from.FindView(v.Name) == nil || recreate[v.Name] {
+			added = append(added, v)
+		}
+	}
+
+	var changes []Change
+	sortedDrops := SortViews(dropped)
+	slices.Reverse(sortedDrops)
+	for _, v := range sortedDrops {
+		changes = append(changes, &DropView{View: v})
+	}
+	for _, v := range SortViews(added) {
+		changes = append(changes, &AddView{View: v, Old: from.FindView(v.Name)})
+	}
+	return changes
+}
+
+// recreatedViews returns the names of the views of to that exist in from
+// and have to be recreated. The set also holds the tables with changes
+// that affect the views selecting from them.
+func recreatedViews(from, to *core.Database, tableChanges []Change) map[string]bool {
+	recreate := make(map[string]bool)
+	for _, c := range tableChanges {
+		if affectsViews(c) {
+			recreate[c.TableName()] = true
+		}
+	}
+	for _, v := range to.Views {
+		if old := from.FindView(v.Name); old != nil && len(View(old, v)) > 0 {
+			recreate[v.Name] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, v := range to.Views {
+			if recreate[v.Name] || from.FindView(v.Name) == nil || !referencesAny(v, recreate) {
+				continue
+			}
+			recreate[v.Name] = true
+			changed = true
+		}
+	}
+	return recreate
+}
+
+// affectsViews reports whether the views that select from the table of c
+// have to be recreated around it. Index and comment changes leave the
+// columns alone. Every other change may alter a column a view selects, or
+// rebuild the table, which SQLite refuses to rename into place while a view
+// refers to it.
+func affectsViews(c Change) bool {
+	switch c := c.(type) {
+	case *AddIndex, *DropIndex, *ChangeTableComment:
+		return false
+	case *AlterColumn:
+		return slices.ContainsFunc(c.Fields, func(f FieldChange) bool { return f.Name != "comment" })
+	default:
+		return true
+	}
+}
+
+// SortViews orders views so that every view comes after the views it
+// selects from. Views that are part of a reference cycle keep their
+// original relative order and are placed after all other views.
+func SortViews(views []*core.View) []*core.View {
+	inSet := make(map[string]bool, len(views))
+	for _, v := range views {
+		inSet[v.Name] = true
+	}
+
+	placed := make(map[string]bool, len(views))
+	sorted := make([]*core.View, 0, len(views))
+	for len(sorted) < len(views) {
+		progress := false
+		for _, v := range views {
+			if placed[v.Name] || !viewDependenciesPlaced(v, inSet, placed) {
+				continue
+			}
+			placed[v.Name] = true
+			sorted = append(sorted, v)
+			progress = true
+		}
+		if !progress {
+			break
+		}
+	}
+
+	for _, v := range views {
+		if !placed[v.Name] {
+			sorted = append(sorted, v)
+		}
+	}
+	return sorted
+}
+
+func viewDependenciesPlaced(v *core.View, inSet, placed map[string]bool) bool {
+	for name := range References(v) {
+		if name != v.Name && inSet[name] && !placed[name] {
+			return false
+		}
+	}
+	return true
+}
+
+func referencesAny(v *core.View, names map[string]bool) bool {
+	for name := range References(v) {
+		if names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// References returns the lower-cased names that appear in the definition
+// of v, outside string literals and comments. Quoted identifiers are
+// unquoted, and a qualified name yields each of its parts. The set is a
+// superset of the tables and views the view selects from, which is enough
+// to order views since they are only matched against known object names.
+func References(v *core.View) map[string]bool {
+	refs := make(map[string]bool)
+	for i := 0; i < len(v.Definition); {
+		var name string
+		name, i = nextName(v.Definition, i)
+		if name != "" {
+			refs[strings.ToLower(name)] = true
+		}
+	}
+	return refs
+}
+
+// nextName skips the token of s that starts at i and returns the name it
+// spells, if any, and the index after it.
+func nextName(s string, i int) (string, int) {
+	switch c := s[i]; {
+	case c == '\'':
+		return "", skipQuoted(s, i, c)
+	case c == '"' || c == '`' || c == '[':
+		end := skipQuoted(s, i, closingQuote(c))
+		return s[i+1 : max(i+1, end-1)], end
+	case strings.HasPrefix(s[i:], "--"):
+		return "", skipUntil(s, i, "\n")
+	case strings.HasPrefix(s[i:], "/*"):
+		return "", skipUntil(s, i, "*/")
+	case isIdentStart(c):
+		end := i + 1
+		for end < len(s) && isIdentPart(s[end]) {
+			end++
+		}
+		return s[i:end], end
+	default:
+		return "", i + 1
+	}
+}
+
+func closingQuote(open byte) byte {
+	if open == '[' {
+		return ']'
+	}
+	return open
+}
+
+// skipQuoted returns the index after the quoted text that starts at i. A
+// doubled closing quote is part of the text.
+func skipQuoted(s string, i int, quote byte) int {
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != quote {
+			continue
+		}
+		if j+1 < len(s) && s[j+1] == quote {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(s)
+}
+
+func skipUntil(s string, i int, end string) int {
+	if j := strings.Index(s[i:], end); j >= 0 {
+		return i + j + len(end)
+	}
+	return len(s)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9' || c == '$' || c == '#'
+}
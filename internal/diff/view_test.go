@@ -0,0 +1,205 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func userOrdersView() *core.View {
+	return &core.View{
+		Name:       "user_orders",
+		Definition: "SELECT u.id, count(o.id) AS orders FROM users u JOIN orders o ON o.user_id = u.id GROUP BY u.id",
+	}
+}
+
+func topUsersView() *core.View {
+	return &core.View{Name: "top_users", Definition: `SELECT id FROM "user_orders" WHERE orders > 100`}
+}
+
+func viewNames(changes []Change) []string {
+	out := make([]string, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, string(c.Kind())+" "+c.TableName())
+	}
+	return out
+}
+
+func TestDatabasesAddViewsAfterTables(t *testing.T) {
+	to := &core.Database{
+		Tables: []*core.Table{usersTable(), ordersTable()},
+		Views:  []*core.View{topUsersView(), userOrdersView()},
+	}
+
+	cs := Databases(nil, to)
+	assert.Equal(t, []string{
+		"add_table users", "add_table orders", "add_view user_orders", "add_view top_users",
+	}, viewNames(cs.Changes))
+	assert.Empty(t, cs.Destructive())
+}
+
+func TestDatabasesDropViewsFirst(t *testing.T) {
+	from := &core.Database{
+		Tables: []*core.Table{usersTable(), ordersTable()},
+		Views:  []*core.View{userOrdersView(), topUsersView()},
+	}
+
+	cs := Databases(from, &core.Database{})
+	assert.Equal(t, []string{
+		"drop_view top_users", "drop_view user_orders", "drop_table orders", "drop_table users",
+	}, viewNames(cs.Changes))
+}
+
+func TestDatabasesRecreateChangedView(t *testing.T) {
+	from := &core.Database{
+		Tables: []*core.Table{usersTable(), ordersTable()},
+		Views:  []*core.View{userOrdersView(), topUsersView()},
+	}
+	changed := userOrdersView()
+	changed.Security = core.SecurityInvoker
+	to := &core.Database{
+		Tables: []*core.Table{usersTable(), ordersTable()},
+		Views:  []*core.View{changed, topUsersView()},
+	}
+
+	cs := Databases(from, to)
+	assert.Equal(t, []string{
+		"drop_view top_users", "drop_view user_orders", "add_view user_orders", "add_view top_users",
+	}, viewNames(cs.Changes))
+
+	add, ok := cs.Changes[2].(*AddView)
+	require.True(t, ok)
+	assert.Equal(t, from.Views[0], add.Old)
+	assert.Equal(t, []FieldChange{{Name: "security", Old: core.ViewSecurity(""), New: core.SecurityInvoker}}, View(add.Old, add.View))
+}
+
+func TestDatabasesRecreateViewsOfChangedTable(t *testing.T) {
+	from := &core.Database{
+		Tables: []*core.Table{usersTable(), ordersTable()},
+		Views:  []*core.View{userOrdersView(), topUsersView()},
+	}
+	orders := ordersTable()
+	orders.Columns = append(orders.Columns, &core.Column{Name: "total", Type: core.DataTypeFloat})
+	to := &core.Database{
+		Tables: []*core.Table{usersTable(), orders},
+		Views:  []*core.View{userOrdersView(), topUsersView()},
+	}
+
+	cs := Databases(from, to)
+	assert.Equal(t, []string{
+		"drop_view top_users", "drop_view user_orders", "add_column orders", "add_view user_orders", "add_view top_users",
+	}, viewNames(cs.Changes))
+}
+
+func TestDatabasesViewDefinitionWhitespace(t *testing.T) {
+	from := &core.Database{Views: []*core.View{{Name: "v", Definition: "SELECT id\n  FROM users;"}}}
+	to := &core.Database{Views: []*core.View{{Name: "v", Definition: "SELECT id FROM users"}}}
+
+	assert.True(t, Databases(from, to).Empty())
+}
+
+func TestDatabasesKeepViewsOfIndexAndCommentChanges(t *testing.T) {
+	from := &core.Database{
+		Tables: []*core.Table{usersTable(), ordersTable()},
+		Views:  []*core.View{userOrdersView(), topUsersView()},
+	}
+	orders := ordersTable()
+	orders.Comment = "customer orders"
+	orders.Columns[0].Comment = "order number"
+	orders.Indexes = append(orders.Indexes, &core.Index{Name: "idx_orders_user", Columns: []core.ColumnIndex{{Name: "user_id"}}})
+	to := &core.Database{
+		Tables: []*core.Table{usersTable(), orders},
+		Views:  []*core.View{userOrdersView(), topUsersView()},
+	}
+
+	cs := Databases(from, to)
+	assert.Equal(t, []string{
+		"change_table_comment orders", "alter_column orders", "add_index orders",
+	}, viewNames(cs.Changes))
+}
+
+func TestSameDefinition(t *testing.T) {
+	t.Parallel()
+	declared := "SELECT u.id, count(o.id) AS orders FROM users u JOIN orders o ON o.user_id = u.id WHERE o.total != 0 GROUP BY u.id"
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{
+			name: "mysql server form",
+			a:    "select `u`.`id` AS `id`,count(`o`.`id`) AS `orders` from (`users` `u` join `orders` `o` on((`o`.`user_id` = `u`.`id`))) where (`o`.`total` <> 0) group by `u`.`id`",
+			b:    declared,
+			want: true,
+		},
+		{
+			name: "postgresql server form",
+			a:    " SELECT u.id,\n    count(o.id) AS orders\n   FROM (users u\n     JOIN orders o ON ((o.user_id = u.id)))\n  WHERE (o.total <> (0)::numeric)\n  GROUP BY u.id;",
+			b:    declared,
+			want: true,
+		},
+		{
+			name: "inner join and comments",
+			a:    "SELECT u.id, count(o.id) AS orders -- per user\nFROM users u INNER JOIN orders o ON o.user_id = u.id WHERE o.total != 0 GROUP BY u.id",
+			b:    declared,
+			want: true,
+		},
+		{
+			name: "different condition",
+			a:    "select `u`.`id` AS `id`,count(`o`.`id`) AS `orders` from (`users` `u` join `orders` `o` on((`o`.`user_id` = `u`.`id`))) where (`o`.`total` > 0) group by `u`.`id`",
+			b:    declared,
+			want: false,
+		},
+		{
+			name: "different alias",
+			a:    "SELECT id AS user_id FROM users",
+			b:    "SELECT id FROM users",
+			want: false,
+		},
+		{
+			name: "string literal case",
+			a:    "SELECT id FROM users WHERE status = 'A'",
+			b:    "SELECT id FROM users WHERE status = 'a'",
+			want: false,
+		},
+		{
+			name: "value list",
+			a:    "SELECT id FROM users WHERE status IN ('a', 'b')",
+			b:    "select `users`.`id` AS `id` from `users` where (`users`.`status` in ('a','b'))",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, sameDefinition(tt.a, tt.b))
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	refs := References(&core.View{Definition: `SELECT a.id, 'orders' FROM "Accounts" a -- users
+		JOIN [dbo].[Ledger] l ON l.id = a.id /* audit */ JOIN ` + "`events`" + ` e USING (id)`})
+
+	for _, name := range []string{"select", "a", "id", "accounts", "dbo", "ledger", "events", "e"} {
+		assert.True(t, refs[name], name)
+	}
+	for _, name := range []string{"orders", "users", "audit"} {
+		assert.False(t, refs[name], name)
+	}
+}
+
+func TestSortViewsCycle(t *testing.T) {
+	a := &core.View{Name: "a", Definition: "SELECT * FROM b"}
+	b := &core.View{Name: "b", Definition: "SELECT * FROM a"}
+	c := &core.View{Name: "c", Definition: "SELECT * FROM c_base"}
+
+	sorted := SortViews([]*core.View{a, b, c})
+	require.Len(t, sorted, 3)
+	assert.Equal(t, "c", sorted[0].Name)
+	assert.Equal(t, "a", sorted[1].Name)
+	assert.Equal(t, "b", sorted[2].Name)
+}
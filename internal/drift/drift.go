@@ -0,0 +1,74 @@
+// Package drift compares a live database with the schema it is expected to
+// have and reports the out-of-band changes, such as an index added by hand
+// or a column altered outside of a migration. Known exceptions are left out
+// with an Ignore list.
+package drift
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+	"smf/internal/migration"
+)
+
+// Ignore lists the objects that are not reported. Entries are glob patterns
+// as understood by path.Match, so an exact name matches only itself.
+type Ignore struct {
+	// Tables matches table and view names. All changes of a matching table
+	// or view are ignored.
+	Tables []string
+	// Indexes matches index names. Unnamed indexes match by the name the
+	// generator gives them.
+	Indexes []string
+}
+
+// Validate reports a malformed pattern.
+func (ig Ignore) Validate() error {
+	for _, p := range slices.Concat(ig.Tables, ig.Indexes) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("drift: ignore pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// Detect returns the changes that bring the live database back to the
+// expected schema, without the ignored objects and the migration history
+// table. Table options the expected schema does not declare are left to the
+// database and are not reported. Both schemas are normalized in place with
+// generate.Normalize first, so a declared varchar that SQLite stores as
+// TEXT, or a comment it cannot store, is not reported.
+func Detect(live, expected *core.Database, ig Ignore) *diff.ChangeSet {
+	generate.Normalize(live)
+	generate.Normalize(expected)
+	return diff.Databases(live, expected).Filter(func(c diff.Change) bool {
+		return !ig.ignored(c)
+	})
+}
+
+func (ig Ignore) ignored(c diff.Change) bool {
+	if c.TableName() == migration.HistoryTable || match(ig.Tables, c.TableName()) {
+		return true
+	}
+	switch c := c.(type) {
+	case *diff.AddIndex:
+		return match(ig.Indexes, generate.IndexName(c.Table, c.Index))
+	case *diff.DropIndex:
+		return match(ig.Indexes, generate.IndexName(c.Table, c.Index))
+	default:
+		return false
+	}
+}
+
+func match(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package drift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	_ "smf/internal/generate/sqlite"
+	"smf/internal/migration"
+)
+
+func expected() *core.Database {
+	return &core.Database{
+		Name:    "shop",
+		Dialect: core.DialectMySQL,
+		Tables: []*core.Table{
+			{
+				Name: "orders",
+				Columns: []*core.Column{
+					{Name: "id", Type: core.DataTypeInt, PortableType: "bigint"},
+					{Name: "total", Type: core.DataTypeFloat, PortableType: "decimal(10,2)"},
+				},
+				Constraints: []*core.Constraint{{Type: core.ConstraintPrimaryKey, Columns: []string{"id"}}},
+			},
+		},
+	}
+}
+
+// live returns the expected schema with out-of-band changes: a hotfix
+// index, a widened column, and tables nobody declared.
+func live() *core.Database {
+	db := expected()
+	orders := db.Tables[0]
+	orders.Columns[1] = &core.Column{Name: "total", Type: core.DataTypeFloat, PortableType: "decimal(12,2)"}
+	orders.Indexes = []*core.Index{
+		{Name: "hotfix_orders_total", Columns: []core.ColumnIndex{{Name: "total"}}},
+	}
+	db.Tables = append(db.Tables,
+		&core.Table{Name: "orders_backup_2024", Columns: []*core.Column{{Name: "id", Type: core.DataTypeInt}}},
+		&core.Table{Name: migration.HistoryTable, Columns: []*core.Column{{Name: "version", Type: core.DataTypeString}}},
+	)
+	return db
+}
+
+func kinds(cs *diff.ChangeSet) []string {
+	var out []string
+	for _, c := range cs.Changes {
+		out = append(out, string(c.Kind())+" "+c.TableName())
+	}
+	return out
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	cs := Detect(live(), expected(), Ignore{})
+
+	assert.ElementsMatch(t, []string{
+		"drop_index orders",
+		"alter_column_type orders",
+		"drop_table orders_backup_2024",
+	}, kinds(cs))
+}
+
+func TestDetectInSync(t *testing.T) {
+	t.Parallel()
+	assert.True(t, Detect(expected(), expected(), Ignore{}).Empty())
+}
+
+func TestDetectStoredForm(t *testing.T) {
+	t.Parallel()
+	declared := &core.Database{
+		Name:    "app",
+		Dialect: core.DialectSQLite,
+		Tables: []*core.Table{{
+			Name:    "users",
+			Comment: "Application user",
+			Columns: []*core.Column{
+				{Name: "id", Type: core.DataTypeInt, PortableType: "bigint"},
+				{Name: "verified", Type: core.DataTypeBoolean, DefaultValue: new("false")},
+				{Name: "updated_at", Type: core.DataTypeDatetime, DefaultValue: new("NOW()"), OnUpdate: new("NOW()")},
+			},
+		}},
+	}
+	stored := &core.Database{
+		Name:    "app",
+		Dialect: core.DialectSQLite,
+		Tables: []*core.Table{{
+			Name: "users",
+			Columns: []*core.Column{
+				{Name: "id", Type: core.DataTypeInt, PortableType: "int"},
+				{Name: "verified", Type: core.DataTypeInt, RawType: "INTEGER", DefaultValue: new("0")},
+				{Name: "updated_at", Type: core.DataTypeString, PortableType: "text",
+					DefaultValue: new("CURRENT_TIMESTAMP"), OnUpdate: new("CURRENT_TIMESTAMP")},
+			},
+		}},
+	}
+	assert.Empty(t, kinds(Detect(stored, declared, Ignore{})))
+}
+
+func TestDetectIgnore(t *testing.T) {
+	t.Parallel()
+	cs := Detect(live(), expected(), Ignore{Tables: []string{"*_backup_*"}, Indexes: []string{"hotfix_*"}})
+	assert.Equal(t, []string{"alter_column_type orders"}, kinds(cs))
+
+	cs = Detect(live(), expected(), Ignore{Tables: []string{"orders*"}})
+	assert.True(t, cs.Empty())
+}
+
+func TestDetectIgnoreUnnamedIndex(t *testing.T) {
+	t.Parallel()
+	db := live()
+	db.Tables[0].Indexes[0].Name = ""
+	cs := Detect(db, expected(), Ignore{Indexes: []string{"idx_orders_total"}})
+	assert.NotContains(t, kinds(cs), "drop_index orders")
+}
+
+func TestIgnoreValidate(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, Ignore{Tables: []string{"tmp_*"}, Indexes: []string{"idx_[a-z]*"}}.Validate())
+	require.ErrorContains(t, Ignore{Indexes: []string{"idx_["}}.Validate(), `ignore pattern "idx_["`)
+}
@@ -0,0 +1,147 @@
+package db2
+
+import (
+	"fmt"
+	"strconv"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// alterColumn renders the ALTER COLUMN statements for a column change.
+// Changing the type or the nullability of a column is reorg-recommended.
+func (r *renderer) alterColumn(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AlterColumnType:
+		r.alterType(c.Table, c.Old, c.New)
+	case *diff.AlterNullability:
+		action := "DROP NOT NULL"
+		if !c.New.Nullable {
+			action = "SET NOT NULL"
+		}
+		r.reorgRecommended(c.Table)
+		r.script.Add(alter(c.Table, c.New, action))
+	case *diff.ChangeDefault:
+		r.alterDefault(c.Table, c.New)
+	case *diff.AlterColumn:
+		for _, f := range c.Fields {
+			r.alterField(c, f)
+		}
+	}
+}
+
+func alter(t *core.Table, c *core.Column, action string) string {
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", quote(t.Name), quote(c.Name), action)
+}
+
+// alterType changes the type of a column and replaces the CHECK constraint
+// of emulated enum columns.
+func (r *renderer) alterType(t *core.Table, from, to *core.Column) {
+	if generate.IsEnum(from) {
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", quote(t.Name), quote(checkName(t, from))))
+	}
+	if oldType, newType := columnType(from), columnType(to); oldType != newType {
+		r.reorgRecommended(t)
+		r.script.Add(alter(t, to, "SET DATA TYPE "+newType))
+	}
+	if check := enumCheck(t, to); check != "" {
+		r.reorg()
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD %s", quote(t.Name), check))
+	}
+}
+
+// alterDefault sets or drops the DEFAULT of c.
+func (r *renderer) alterDefault(t *core.Table, c *core.Column) {
+	if isIdentity(c) || c.IsGenerated {
+		return
+	}
+	if c.DefaultValue == nil {
+		r.script.Add(alter(t, c, "DROP DEFAULT"))
+		return
+	}
+	r.script.Add(alter(t, c, "SET DEFAULT "+defaultValue(c, *c.DefaultValue)))
+}
+
+// once reports whether the attribute of c was already altered, and records
+// it otherwise.
+func (r *renderer) once(attr string, t *core.Table, c *core.Column) bool {
+	key := attr + ":" + t.Name + "." + c.Name
+	if r.altered[key] {
+		return true
+	}
+	r.altered[key] = true
+	return false
+}
+
+// alterField renders a single changed column attribute. Attributes of other
+// dialects are ignored.
+func (r *renderer) alterField(c *diff.AlterColumn, f diff.FieldChange) {
+	t, col := c.Table, c.New
+	switch f.Name {
+	case "comment":
+		r.script.Add(columnComment(t, col))
+	case "invisible", "db2.implicitly_hidden":
+		r.alterHidden(t, col)
+	case "db2.inline_length":
+		r.alterInlineLength(t, col)
+	case "db2.compress":
+		if o := columnOptions(col); o.Compress != nil && *o.Compress {
+			r.script.Add(alter(t, col, "COMPRESS SYSTEM DEFAULT"))
+		} else {
+			r.script.Add(alter(t, col, "COMPRESS OFF"))
+		}
+	case "auto_increment", "identity_generation", "identity_seed", "identity_increment":
+		r.alterIdentity(c)
+	case "on_update":
+		r.dropOnUpdateTrigger(t, c.Old)
+		r.createOnUpdateTrigger(t, col)
+	case "collate", "charset":
+		r.script.Warnf("column %s.%s: Db2 sets the code page and collation per database; the change was ignored", t.Name, col.Name)
+	case "is_generated", "generation_expression", "generation_storage":
+		r.script.Warnf("column %s.%s: the generation expression cannot be altered; recreate the column", t.Name, col.Name)
+	}
+}
+
+func (r *renderer) alterHidden(t *core.Table, c *core.Column) {
+	if r.once("hidden", t, c) {
+		return
+	}
+	if hidden(c) {
+		r.script.Add(alter(t, c, "SET IMPLICITLY HIDDEN"))
+	} else {
+		r.script.Add(alter(t, c, "SET NOT HIDDEN"))
+	}
+}
+
+// alterInlineLength increases the INLINE LENGTH of a LOB column. Db2
+// cannot decrease or remove it.
+func (r *renderer) alterInlineLength(t *core.Table, c *core.Column) {
+	length := columnOptions(c).InlineLength
+	if length == nil || !isLOB(columnType(c)) {
+		r.script.Warnf("column %s.%s: the inline length can only be increased; recreate the column to remove it", t.Name, c.Name)
+		return
+	}
+	r.script.Add(alter(t, c, "SET INLINE LENGTH "+strconv.Itoa(*length)))
+}
+
+// alterIdentity changes the identity options of a column, turns it into an
+// identity column, or drops its identity.
+func (r *renderer) alterIdentity(c *diff.AlterColumn) {
+	t, from, to := c.Table, c.Old, c.New
+	if r.once("identity", t, to) {
+		return
+	}
+	switch {
+	case isIdentity(from) && isIdentity(to):
+		action := "SET " + generation(to)
+		if to.IdentityIncrement != 0 {
+			action += " SET INCREMENT BY " + strconv.FormatInt(to.IdentityIncrement, 10)
+		}
+		r.script.Add(alter(t, to, action))
+	case isIdentity(from):
+		r.script.Add(alter(t, to, "DROP IDENTITY"))
+	default:
+		r.script.Add(alter(t, to, "SET "+identityClause(to)))
+	}
+}
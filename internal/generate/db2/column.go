@@ -0,0 +1,243 @@
+package db2
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+var typeMapper = generate.TypeMapper{
+	Types: map[string]string{
+		"varchar":   "VARCHAR(%s)",
+		"char":      "CHAR(%s)",
+		"text":      "CLOB",
+		"tinyint":   "SMALLINT",
+		"smallint":  "SMALLINT",
+		"int":       "INTEGER",
+		"integer":   "INTEGER",
+		"bigint":    "BIGINT",
+		"decimal":   "DECIMAL(%s)",
+		"numeric":   "DECIMAL(%s)",
+		"float":     "REAL",
+		"double":    "DOUBLE",
+		"boolean":   "BOOLEAN",
+		"bool":      "BOOLEAN",
+		"date":      "DATE",
+		"time":      "TIME",
+		"timestamp": "TIMESTAMP",
+		"datetime":  "TIMESTAMP",
+		"json":      "CLOB",
+		"uuid":      "CHAR(16) FOR BIT DATA",
+		"blob":      "BLOB",
+		"binary":    "BINARY(%s)",
+		"varbinary": "VARBINARY(%s)",
+	},
+	DefaultArgs: map[string]string{
+		"varchar":   "255",
+		"binary":    "1",
+		"varbinary": "255",
+	},
+	Fallback: map[core.DataType]string{
+		core.DataTypeString:   "VARCHAR(4000)",
+		core.DataTypeInt:      "INTEGER",
+		core.DataTypeFloat:    "DOUBLE",
+		core.DataTypeBoolean:  "BOOLEAN",
+		core.DataTypeDatetime: "TIMESTAMP",
+		core.DataTypeJSON:     "CLOB",
+		core.DataTypeUUID:     "CHAR(16) FOR BIT DATA",
+		core.DataTypeBinary:   "BLOB",
+	},
+	StripSuffix: true,
+}
+
+// isIdentity reports whether c is rendered as an identity column.
+func isIdentity(c *core.Column) bool {
+	return c.AutoIncrement || c.IdentityGeneration != ""
+}
+
+// columnType returns the Db2 type of c. Enums become VARCHAR columns sized
+// for their longest value.
+func columnType(c *core.Column) string {
+	if generate.IsEnum(c) {
+		size := 1
+		for _, v := range c.EnumValues {
+			size = max(size, len(v))
+		}
+		return "VARCHAR(" + strconv.Itoa(size) + ")"
+	}
+	return typeMapper.ColumnType(c)
+}
+
+// isLOB reports whether a Db2 type stores its values as large objects,
+// which are the only types that accept an INLINE LENGTH.
+func isLOB(typ string) bool {
+	switch strings.ToUpper(generate.ParseType(typ).Base) {
+	case "CLOB", "DBCLOB", "NCLOB", "BLOB", "XML":
+		return true
+	default:
+		return false
+	}
+}
+
+// checkName returns the name of the CHECK constraint that emulates an enum
+// column.
+func checkName(t *core.Table, c *core.Column) string {
+	return "chk_" + t.Name + "_" + c.Name + "_enum"
+}
+
+// enumCheck renders the CHECK constraint that restricts an enum column to
+// its values, or "" for other columns.
+func enumCheck(t *core.Table, c *core.Column) string {
+	if !generate.IsEnum(c) {
+		return ""
+	}
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s IN (%s))",
+		quote(checkName(t, c)), quote(c.Name), generate.QuoteList(c.EnumValues, generate.QuoteString))
+}
+
+func columnOptions(c *core.Column) *core.DB2ColumnOptions {
+	if c.DB2 == nil {
+		return &core.DB2ColumnOptions{}
+	}
+	return c.DB2
+}
+
+// hidden reports whether c is IMPLICITLY HIDDEN, the Db2 counterpart of an
+// invisible column.
+func hidden(c *core.Column) bool {
+	return c.Invisible || columnOptions(c).ImplicitlyHidden
+}
+
+// columnDefinition renders a column as it appears in CREATE TABLE and in
+// ALTER TABLE ... ADD COLUMN clauses.
+func (r *renderer) columnDefinition(t *core.Table, c *core.Column) string {
+	parts := []string{quote(c.Name), columnType(c)}
+	if !c.Nullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if clause := r.valueClause(t, c); clause != "" {
+		parts = append(parts, clause)
+	}
+	if hidden(c) {
+		parts = append(parts, "IMPLICITLY HIDDEN")
+	}
+	parts = append(parts, r.storageClauses(t, c)...)
+	if check := enumCheck(t, c); check != "" {
+		parts = append(parts, check)
+	}
+	if c.Charset != "" || c.Collate != "" {
+		r.script.Warnf("column %s.%s: Db2 sets the code page and collation per database; they were ignored", t.Name, c.Name)
+	}
+	return strings.Join(parts, " ")
+}
+
+// storageClauses renders the INLINE LENGTH and value compression of c.
+func (r *renderer) storageClauses(t *core.Table, c *core.Column) []string {
+	o := columnOptions(c)
+	var clauses []string
+	if o.InlineLength != nil {
+		if isLOB(columnType(c)) {
+			clauses = append(clauses, "INLINE LENGTH "+strconv.Itoa(*o.InlineLength))
+		} else {
+			r.script.Warnf("column %s.%s: INLINE LENGTH only applies to LOB and XML columns; it was ignored", t.Name, c.Name)
+		}
+	}
+	if o.Compress != nil && *o.Compress {
+		clauses = append(clauses, "COMPRESS SYSTEM DEFAULT")
+	}
+	return clauses
+}
+
+// valueClause renders the generation expression, the identity clause, or
+// the DEFAULT of c. Db2 does not accept sequence expressions as defaults.
+func (r *renderer) valueClause(t *core.Table, c *core.Column) string {
+	switch {
+	case c.IsGenerated:
+		if c.GenerationStorage == core.GenerationVirtual {
+			r.script.Warnf("column %s.%s: Db2 only supports stored generated columns", t.Name, c.Name)
+		}
+		return "GENERATED ALWAYS AS (" + c.GenerationExpression + ")"
+	case isIdentity(c):
+		return identityClause(c)
+	case c.SequenceName != "":
+		r.script.Warnf("column %s.%s: Db2 does not allow sequence defaults; use an identity column instead of %s",
+			t.Name, c.Name, c.SequenceName)
+	}
+	if c.DefaultValue == nil {
+		return ""
+	}
+	return "DEFAULT " + defaultValue(c, *c.DefaultValue)
+}
+
+func identityClause(c *core.Column) string {
+	clause := generation(c) + " AS IDENTITY"
+	if opts := identityOptions(c); opts != "" {
+		clause += " (" + opts + ")"
+	}
+	return clause
+}
+
+func generation(c *core.Column) string {
+	if c.IdentityGeneration == core.IdentityByDefault {
+		return "GENERATED BY DEFAULT"
+	}
+	return "GENERATED ALWAYS"
+}
+
+func identityOptions(c *core.Column) string {
+	var opts []string
+	if c.IdentitySeed != 0 {
+		opts = append(opts, "START WITH "+strconv.FormatInt(c.IdentitySeed, 10))
+	}
+	if c.IdentityIncrement != 0 {
+		opts = append(opts, "INCREMENT BY "+strconv.FormatInt(c.IdentityIncrement, 10))
+	}
+	return strings.Join(opts, " ")
+}
+
+// specialRegisterRe matches the Db2 date and time special registers, which
+// are written with a space and therefore not recognized as timestamps.
+var specialRegisterRe = regexp.MustCompile(`(?i)^CURRENT\s+(DATE|TIME|TIMESTAMP)$`)
+
+// defaultValue renders a DEFAULT value. Db2 spells the current date and
+// time special registers with a space.
+func defaultValue(c *core.Column, v string) string {
+	v = strings.TrimSpace(v)
+	if specialRegisterRe.MatchString(v) {
+		return strings.ToUpper(strings.Join(strings.Fields(v), " "))
+	}
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return generate.QuoteString(v)
+	case generate.DefaultBoolean:
+		return boolLiteral(generate.BoolDefault(v))
+	case generate.DefaultNumber:
+		if c.Type == core.DataTypeBoolean {
+			return boolLiteral(generate.BoolDefault(v))
+		}
+		return v
+	case generate.DefaultTimestamp:
+		return generate.TimestampDefault(v, timestampFuncs, "CURRENT TIMESTAMP")
+	default:
+		return v
+	}
+}
+
+func boolLiteral(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// timestampFuncs spells the current date and time functions of other
+// dialects as Db2 special registers.
+var timestampFuncs = map[string]string{
+	"CURRENT_DATE": "CURRENT DATE",
+	"CURRENT_TIME": "CURRENT TIME",
+	"LOCALTIME":    "CURRENT TIME",
+}
@@ -0,0 +1,153 @@
+// Package db2 contains the DDL generator for IBM Db2 for Linux, UNIX, and
+// Windows. Portable types are mapped to VARCHAR, INTEGER, DECIMAL, CLOB,
+// and BLOB; enums are emulated with CHECK constraints, and ON UPDATE
+// clauses with triggers. ALTER TABLE statements that leave a table in
+// reorg-pending state are followed by a REORG through SYSPROC.ADMIN_CMD.
+package db2
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+func init() {
+	generate.Register(core.DialectDB2, New)
+}
+
+// Generator renders Db2 DDL.
+type Generator struct{}
+
+func New() generate.Generator {
+	return &Generator{}
+}
+
+// Generate renders a CREATE TABLE statement for every table and a CREATE
+// VIEW statement for every view in db, ordered so that referenced tables
+// and views are created first.
+func (g *Generator) Generate(db *core.Database) (*generate.Script, error) {
+	return g.GenerateChanges(diff.Databases(nil, db))
+}
+
+// GenerateChanges renders the statements that apply cs.
+func (g *Generator) GenerateChanges(cs *diff.ChangeSet) (*generate.Script, error) {
+	r := &renderer{
+		script:  &generate.Script{},
+		order:   generate.NewCreateOrder(cs),
+		altered: make(map[string]bool),
+	}
+	for _, c := range cs.Changes {
+		if r.pending != "" && (c.TableName() != r.pending || !isColumnChange(c)) {
+			r.reorg()
+		}
+		if err := r.change(c); err != nil {
+			return nil, err
+		}
+	}
+	r.reorg()
+	r.script.Add(r.deferred...)
+	return r.script, nil
+}
+
+// renderer collects the Db2 statements of one change set. Consecutive
+// reorg-recommended ALTER TABLE statements of one table share a single
+// REORG, which runs before any statement on another table.
+type renderer struct {
+	script *generate.Script
+	order  *generate.CreateOrder
+	// deferred holds foreign keys of new tables that reference a table
+	// created later in the script.
+	deferred []string
+	// altered tracks column attributes that were already altered, since
+	// several field changes may map to one statement.
+	altered map[string]bool
+	// pending is the table left in reorg-pending state by the statements
+	// rendered so far, or "", and pendingAlters counts those statements.
+	pending       string
+	pendingAlters int
+}
+
+func (r *renderer) change(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddTable:
+		r.createTable(c.Table)
+	case *diff.DropTable:
+		r.script.Add("DROP TABLE " + quote(c.Table.Name))
+	case *diff.ChangeTableComment:
+		r.script.Add(tableComment(c.Table.Name, c.New))
+	case *diff.TableOptionChange:
+		r.alterTableOptions(c)
+	case *diff.AddColumn:
+		r.addColumn(c.Table, c.Column)
+	case *diff.DropColumn:
+		r.dropOnUpdateTrigger(c.Table, c.Column)
+		r.reorgRecommended(c.Table)
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", quote(c.Table.Name), quote(c.Column.Name)))
+	case *diff.AlterColumnType, *diff.AlterNullability, *diff.ChangeDefault, *diff.AlterColumn:
+		r.alterColumn(c)
+	case *diff.AddConstraint, *diff.DropConstraint, *diff.AddIndex, *diff.DropIndex:
+		r.keyChange(c)
+	default:
+		return r.viewChange(c)
+	}
+	return nil
+}
+
+func (r *renderer) keyChange(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AddConstraint:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD %s", quote(c.Table.Name), r.constraintDefinition(c.Table, c.Constraint)))
+	case *diff.DropConstraint:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s",
+			quote(c.Table.Name), quote(generate.ConstraintName(c.Table, c.Constraint))))
+	case *diff.AddIndex:
+		r.createIndex(c.Table, c.Index)
+	case *diff.DropIndex:
+		r.script.Add("DROP INDEX " + quote(generate.IndexName(c.Table, c.Index)))
+	}
+}
+
+// isColumnChange reports whether c only alters or drops columns. Db2
+// accepts a few of these statements on a reorg-pending table, so they are
+// batched before a single REORG.
+func isColumnChange(c diff.Change) bool {
+	switch c.(type) {
+	case *diff.DropColumn, *diff.AlterColumnType, *diff.AlterNullability, *diff.ChangeDefault, *diff.AlterColumn:
+		return true
+	default:
+		return false
+	}
+}
+
+// maxPendingAlters is the number of reorg-recommended statements Db2
+// accepts on a table before it must be reorganized.
+const maxPendingAlters = 3
+
+// reorgRecommended records that the next statement leaves t in
+// reorg-pending state. It must be called before the statement is added,
+// so that a REORG can be rendered first when the limit is reached.
+func (r *renderer) reorgRecommended(t *core.Table) {
+	if r.pending != t.Name || r.pendingAlters == maxPendingAlters {
+		r.reorg()
+	}
+	r.pending = t.Name
+	r.pendingAlters++
+}
+
+// reorg renders the REORG of the reorg-pending table, if any. Most
+// statements fail on a table in that state until it is reorganized.
+func (r *renderer) reorg() {
+	if r.pending == "" {
+		return
+	}
+	r.script.Add(fmt.Sprintf("CALL SYSPROC.ADMIN_CMD(%s)", generate.QuoteString("REORG TABLE "+quote(r.pending))))
+	r.pending, r.pendingAlters = "", 0
+}
+
+// quote quotes a name in the case Db2 folds it to, so that unquoted
+// references in CHECK expressions, generated columns and views resolve.
+func quote(name string) string {
+	return generate.QuoteFolded(name)
+}
@@ -0,0 +1,263 @@
+package db2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/pars/toml"
+)
+
+func parse(t *testing.T, schema string) *core.Database {
+	t.Helper()
+	db, err := toml.NewParser().Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+	return db
+}
+
+const documentsSchema = `
+[database]
+name = "app"
+dialect = "db2"
+
+[[tables]]
+name = "documents"
+comment = "uploaded documents"
+
+  [tables.options]
+  tablespace = "userspace1"
+
+  [tables.options.db2]
+  organize_by = "row"
+  compress = "yes"
+  data_capture = "changes"
+  append_mode = true
+  volatile = true
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+  auto_increment = true
+  identity_generation = "BY DEFAULT"
+
+  [[tables.columns]]
+  name = "status"
+  type = "enum"
+  values = ["draft", "final"]
+  default = "draft"
+
+  [[tables.columns]]
+  name = "body"
+  type = "text"
+  nullable = true
+
+    [tables.columns.db2]
+    inline_length = 1024
+    compress = true
+
+  [[tables.columns]]
+  name = "checksum"
+  type = "varchar(64)"
+  nullable = true
+
+    [tables.columns.db2]
+    implicitly_hidden = true
+
+  [[tables.columns]]
+  name = "updated_at"
+  type = "timestamp"
+  default = "NOW()"
+  on_update = "CURRENT_TIMESTAMP"
+`
+
+func TestGenerateCreateTable(t *testing.T) {
+	t.Parallel()
+	script, err := New().Generate(parse(t, documentsSchema))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"CREATE TABLE \"DOCUMENTS\" (\n" +
+			`  "ID" BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,` + "\n" +
+			`  "STATUS" VARCHAR(5) NOT NULL DEFAULT 'draft' CONSTRAINT "CHK_DOCUMENTS_STATUS_ENUM" CHECK ("STATUS" IN ('draft', 'final')),` + "\n" +
+			`  "BODY" CLOB INLINE LENGTH 1024 COMPRESS SYSTEM DEFAULT,` + "\n" +
+			`  "CHECKSUM" VARCHAR(64) IMPLICITLY HIDDEN,` + "\n" +
+			`  "UPDATED_AT" TIMESTAMP NOT NULL DEFAULT CURRENT TIMESTAMP,` + "\n" +
+			`  CONSTRAINT "PK_DOCUMENTS" PRIMARY KEY ("ID")` + "\n" +
+			`) DATA CAPTURE CHANGES IN "USERSPACE1" COMPRESS YES ORGANIZE BY ROW`,
+		`ALTER TABLE "DOCUMENTS" APPEND ON`,
+		`ALTER TABLE "DOCUMENTS" VOLATILE CARDINALITY`,
+		`COMMENT ON TABLE "DOCUMENTS" IS 'uploaded documents'`,
+		"CREATE OR REPLACE TRIGGER \"DOCUMENTS_UPDATED_AT_ON_UPDATE\"\n" +
+			"NO CASCADE BEFORE UPDATE ON \"DOCUMENTS\"\n" +
+			"REFERENCING OLD AS o NEW AS n\n" +
+			"FOR EACH ROW\n" +
+			"WHEN (n.\"UPDATED_AT\" IS NOT DISTINCT FROM o.\"UPDATED_AT\")\n" +
+			"SET n.\"UPDATED_AT\" = CURRENT TIMESTAMP",
+	}, script.Statements)
+	assert.Empty(t, script.Warnings)
+}
+
+func TestGenerateColumnOrganized(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{
+		Name:    "facts",
+		Columns: []*core.Column{{Name: "amount", PortableType: "decimal(12,2)"}},
+		Options: core.TableOptions{DB2: &core.DB2TableOptions{OrganizeBy: "COLUMN", Compress: "YES", AppendMode: true}},
+	}
+	script, err := New().Generate(&core.Database{Tables: []*core.Table{tbl}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"CREATE TABLE \"FACTS\" (\n  \"AMOUNT\" DECIMAL(12,2) NOT NULL\n) ORGANIZE BY COLUMN",
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 2)
+}
+
+func TestGenerateColumnTypes(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"varchar":       "VARCHAR(255)",
+		"text":          "CLOB",
+		"tinyint":       "SMALLINT",
+		"int":           "INTEGER",
+		"decimal(10,2)": "DECIMAL(10,2)",
+		"boolean":       "BOOLEAN",
+		"DATETIME":      "TIMESTAMP",
+		"uuid":          "CHAR(16) FOR BIT DATA",
+		"varbinary(32)": "VARBINARY(32)",
+	}
+	for portable, want := range tests {
+		assert.Equal(t, want, columnType(&core.Column{PortableType: portable}), portable)
+	}
+}
+
+func TestGenerateChangesReorg(t *testing.T) {
+	t.Parallel()
+	from := parse(t, documentsSchema)
+	to := parse(t, strings.NewReplacer(
+		`values = ["draft", "final"]`, `values = ["draft", "final", "archived"]`,
+		`inline_length = 1024`, `inline_length = 2048`,
+		`data_capture = "changes"`, `data_capture = "none"`,
+		`  type = "varchar(64)"
+  nullable = true`, `  type = "varchar(64)"`,
+	).Replace(documentsSchema))
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "DOCUMENTS" DATA CAPTURE NONE`,
+		`ALTER TABLE "DOCUMENTS" DROP CONSTRAINT "CHK_DOCUMENTS_STATUS_ENUM"`,
+		`ALTER TABLE "DOCUMENTS" ALTER COLUMN "STATUS" SET DATA TYPE VARCHAR(8)`,
+		`CALL SYSPROC.ADMIN_CMD('REORG TABLE "DOCUMENTS"')`,
+		`ALTER TABLE "DOCUMENTS" ADD CONSTRAINT "CHK_DOCUMENTS_STATUS_ENUM" CHECK ("STATUS" IN ('draft', 'final', 'archived'))`,
+		`ALTER TABLE "DOCUMENTS" ALTER COLUMN "BODY" SET INLINE LENGTH 2048`,
+		`ALTER TABLE "DOCUMENTS" ALTER COLUMN "CHECKSUM" SET NOT NULL`,
+		`CALL SYSPROC.ADMIN_CMD('REORG TABLE "DOCUMENTS"')`,
+	}, script.Statements)
+}
+
+func TestGenerateReorgLimit(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "logs"}
+	cs := &diff.ChangeSet{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		cs.Changes = append(cs.Changes, &diff.DropColumn{Table: tbl, Column: &core.Column{Name: name}})
+	}
+	cs.Changes = append(cs.Changes, &diff.AddIndex{Table: tbl, Index: &core.Index{Columns: []core.ColumnIndex{{Name: "e"}}}})
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "LOGS" DROP COLUMN "A"`,
+		`ALTER TABLE "LOGS" DROP COLUMN "B"`,
+		`ALTER TABLE "LOGS" DROP COLUMN "C"`,
+		`CALL SYSPROC.ADMIN_CMD('REORG TABLE "LOGS"')`,
+		`ALTER TABLE "LOGS" DROP COLUMN "D"`,
+		`CALL SYSPROC.ADMIN_CMD('REORG TABLE "LOGS"')`,
+		`CREATE INDEX "IDX_LOGS_E" ON "LOGS" ("E")`,
+	}, script.Statements)
+}
+
+func TestGenerateKeys(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "lines"}
+	enforced := false
+	cs := &diff.ChangeSet{Changes: []diff.Change{
+		&diff.AddConstraint{Table: tbl, Constraint: &core.Constraint{
+			Type: core.ConstraintForeignKey, Columns: []string{"order_id"}, ReferencedTable: "orders",
+			ReferencedColumns: []string{"id"}, OnDelete: core.RefActionCascade, OnUpdate: core.RefActionCascade,
+		}},
+		&diff.AddConstraint{Table: tbl, Constraint: &core.Constraint{
+			Name: "chk_lines_qty", Type: core.ConstraintCheck, CheckExpression: "qty > 0", Enforced: &enforced,
+		}},
+		&diff.AddIndex{Table: tbl, Index: &core.Index{Type: core.IndexTypeFullText, Columns: []core.ColumnIndex{{Name: "note"}}}},
+		&diff.AddIndex{Table: tbl, Index: &core.Index{
+			Name: "idx_lines_sku", Unique: true, Comment: "sku lookup", Columns: []core.ColumnIndex{{Name: "sku"}},
+		}},
+	}}
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "LINES" ADD CONSTRAINT "FK_LINES_ORDERS" FOREIGN KEY ("ORDER_ID") REFERENCES "ORDERS" ("ID") ON DELETE CASCADE`,
+		`ALTER TABLE "LINES" ADD CONSTRAINT "CHK_LINES_QTY" CHECK (qty > 0) NOT ENFORCED`,
+		`CREATE UNIQUE INDEX "IDX_LINES_SKU" ON "LINES" ("SKU")`,
+		`COMMENT ON INDEX "IDX_LINES_SKU" IS 'sku lookup'`,
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 2)
+}
+
+// TestGenerateMaterializedQueryTable checks that a materialized view
+// becomes a materialized query table that is refreshed right after it is
+// created.
+func TestGenerateMaterializedQueryTable(t *testing.T) {
+	t.Parallel()
+	tests := map[core.RefreshPolicy]string{
+		"":                   "DEFERRED",
+		core.RefreshOnDemand: "DEFERRED",
+		core.RefreshOnCommit: "IMMEDIATE",
+	}
+	for refresh, want := range tests {
+		view := &core.View{
+			Name: "draft_count", Definition: "SELECT count(*) AS n FROM documents WHERE status = 'draft'",
+			Materialized: true, Refresh: refresh, Comment: "drafts",
+		}
+		script, err := New().GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{
+			&diff.DropView{View: view}, &diff.AddView{View: view},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			`DROP TABLE "DRAFT_COUNT"`,
+			`CREATE TABLE "DRAFT_COUNT" AS (SELECT count(*) AS n FROM documents WHERE status = 'draft') ` +
+				`DATA INITIALLY DEFERRED REFRESH ` + want,
+			`REFRESH TABLE "DRAFT_COUNT"`,
+			`COMMENT ON TABLE "DRAFT_COUNT" IS 'drafts'`,
+		}, script.Statements, refresh)
+	}
+}
+
+func TestGenerateViewCheckOption(t *testing.T) {
+	t.Parallel()
+	view := &core.View{
+		Name:        "final_documents",
+		Definition:  "SELECT id, body FROM documents WHERE status = 'final';",
+		Columns:     []string{"document_id", "body"},
+		CheckOption: core.CheckOptionLocal,
+		Security:    core.SecurityInvoker,
+	}
+	script, err := New().GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{
+		&diff.DropView{View: view}, &diff.AddView{View: view},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`DROP VIEW "FINAL_DOCUMENTS"`,
+		`CREATE VIEW "FINAL_DOCUMENTS" ("DOCUMENT_ID", "BODY") AS ` +
+			`SELECT id, body FROM documents WHERE status = 'final' WITH LOCAL CHECK OPTION`,
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 1)
+}
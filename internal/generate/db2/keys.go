@@ -0,0 +1,103 @@
+package db2
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// constraintDefinition renders a named table constraint as it appears in
+// CREATE TABLE and in ALTER TABLE ... ADD clauses. Constraints that are not
+// enforced become informational constraints.
+func (r *renderer) constraintDefinition(t *core.Table, con *core.Constraint) string {
+	def := "CONSTRAINT " + quote(generate.ConstraintName(t, con)) + " "
+	cols := generate.QuoteList(con.Columns, quote)
+	switch con.Type {
+	case core.ConstraintPrimaryKey:
+		def += "PRIMARY KEY (" + cols + ")"
+	case core.ConstraintUnique:
+		def += "UNIQUE (" + cols + ")"
+	case core.ConstraintCheck:
+		def += "CHECK (" + con.CheckExpression + ")"
+	default:
+		def += r.foreignKey(t, con)
+	}
+	if !generate.Enforced(con) {
+		def += " NOT ENFORCED"
+	}
+	return def
+}
+
+// foreignKey renders a FOREIGN KEY clause. Db2 has no SET DEFAULT action
+// and only accepts NO ACTION and RESTRICT on update.
+func (r *renderer) foreignKey(t *core.Table, con *core.Constraint) string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		generate.QuoteList(con.Columns, quote),
+		quote(con.ReferencedTable),
+		generate.QuoteList(con.ReferencedColumns, quote))
+	switch con.OnDelete {
+	case core.RefActionNone:
+	case core.RefActionSetDefault:
+		r.script.Warnf("table %s: Db2 does not support ON DELETE SET DEFAULT; %s uses NO ACTION",
+			t.Name, generate.ConstraintName(t, con))
+	default:
+		def += " ON DELETE " + string(con.OnDelete)
+	}
+	switch con.OnUpdate {
+	case core.RefActionNone:
+	case core.RefActionNoAction, core.RefActionRestrict:
+		def += " ON UPDATE " + string(con.OnUpdate)
+	default:
+		r.script.Warnf("table %s: Db2 does not support ON UPDATE %s; it was ignored for %s",
+			t.Name, con.OnUpdate, generate.ConstraintName(t, con))
+	}
+	return def
+}
+
+// createIndex renders CREATE INDEX for idx and its comment. FULLTEXT and
+// spatial indexes need Db2 extenders and are skipped.
+func (r *renderer) createIndex(t *core.Table, idx *core.Index) {
+	name := generate.IndexName(t, idx)
+	switch idx.Type {
+	case "", core.IndexTypeBTree:
+	case core.IndexTypeFullText:
+		r.script.Warnf("table %s: full-text index %s was skipped; create it with Db2 Text Search", t.Name, name)
+		return
+	case core.IndexTypeSpatial:
+		r.script.Warnf("table %s: spatial index %s was skipped; it requires Db2 Spatial Extender", t.Name, name)
+		return
+	default:
+		r.script.Warnf("table %s: Db2 does not support %s indexes; %s is created as a B-tree index", t.Name, idx.Type, name)
+	}
+	if idx.Visibility == core.IndexInvisible {
+		r.script.Warnf("table %s: Db2 does not support invisible indexes; %s is visible", t.Name, name)
+	}
+	if idx.Where != "" {
+		r.script.Warnf("table %s: Db2 does not support partial indexes; the WHERE clause of %s was ignored", t.Name, name)
+	}
+
+	stmt := "CREATE "
+	if idx.Unique {
+		stmt += "UNIQUE "
+	}
+	r.script.Add(stmt + fmt.Sprintf("INDEX %s ON %s (%s)", quote(name), quote(t.Name), r.indexColumns(t, idx)))
+	if idx.Comment != "" {
+		r.script.Add(fmt.Sprintf("COMMENT ON INDEX %s IS %s", quote(name), generate.QuoteString(idx.Comment)))
+	}
+}
+
+func (r *renderer) indexColumns(t *core.Table, idx *core.Index) string {
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		if c.Length > 0 {
+			r.script.Warnf("table %s: Db2 does not support prefix indexes; the length of %s was ignored", t.Name, c.Name)
+		}
+		cols[i] = generate.KeyPart(c, quote)
+		if c.Order == core.SortDesc {
+			cols[i] += " DESC"
+		}
+	}
+	return strings.Join(cols, ", ")
+}
@@ -0,0 +1,164 @@
+package db2
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// createTable renders CREATE TABLE for t together with the statements Db2
+// does not accept inline (append mode, volatile cardinality, comments,
+// indexes, and ON UPDATE triggers).
+func (r *renderer) createTable(t *core.Table) {
+	r.script.Add(r.createTableStatement(t))
+	r.order.Created(t.Name)
+
+	r.script.Add(r.tableAttributes(t)...)
+	if t.Comment != "" {
+		r.script.Add(tableComment(t.Name, t.Comment))
+	}
+	for _, c := range t.Columns {
+		r.columnExtras(t, c)
+	}
+	for _, idx := range t.Indexes {
+		r.createIndex(t, idx)
+	}
+}
+
+func (r *renderer) createTableStatement(t *core.Table) string {
+	var defs []string
+	for _, c := range t.Columns {
+		defs = append(defs, r.columnDefinition(t, c))
+	}
+	for _, con := range t.Constraints {
+		if con.Type == core.ConstraintForeignKey && !r.order.Inline(t, con) {
+			r.deferred = append(r.deferred,
+				fmt.Sprintf("ALTER TABLE %s ADD %s", quote(t.Name), r.constraintDefinition(t, con)))
+			continue
+		}
+		defs = append(defs, r.constraintDefinition(t, con))
+	}
+
+	stmt := "CREATE TABLE " + quote(t.Name) + " (\n  " + strings.Join(defs, ",\n  ") + "\n)"
+	for _, opt := range r.tableOptions(t) {
+		stmt += " " + opt
+	}
+	return stmt
+}
+
+// columnExtras renders the per-column statements that follow CREATE TABLE
+// or ADD COLUMN.
+func (r *renderer) columnExtras(t *core.Table, c *core.Column) {
+	if c.Comment != "" {
+		r.script.Add(columnComment(t, c))
+	}
+	r.createOnUpdateTrigger(t, c)
+}
+
+func tableComment(table, comment string) string {
+	return fmt.Sprintf("COMMENT ON TABLE %s IS %s", quote(table), generate.QuoteString(comment))
+}
+
+func columnComment(t *core.Table, c *core.Column) string {
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", quote(t.Name), quote(c.Name), generate.QuoteString(c.Comment))
+}
+
+func options(t *core.Table) *core.DB2TableOptions {
+	if t.Options.DB2 == nil {
+		return &core.DB2TableOptions{}
+	}
+	return t.Options.DB2
+}
+
+func columnOrganized(t *core.Table) bool {
+	return strings.EqualFold(options(t).OrganizeBy, "COLUMN")
+}
+
+// tableOptions renders the clauses that follow the column list: change
+// data capture, the tablespace, row compression, and the organization.
+func (r *renderer) tableOptions(t *core.Table) []string {
+	o := options(t)
+	var opts []string
+	if o.DataCapture != "" {
+		opts = append(opts, "DATA CAPTURE "+strings.ToUpper(o.DataCapture))
+	}
+	if t.Options.Tablespace != "" {
+		opts = append(opts, "IN "+quote(t.Options.Tablespace))
+	}
+	if o.Compress != "" {
+		if columnOrganized(t) {
+			r.script.Warnf("table %s: column-organized tables are always compressed; COMPRESS was ignored", t.Name)
+		} else {
+			opts = append(opts, "COMPRESS "+strings.ToUpper(o.Compress))
+		}
+	}
+	if o.OrganizeBy != "" {
+		opts = append(opts, "ORGANIZE BY "+strings.ToUpper(o.OrganizeBy))
+	}
+	return opts
+}
+
+// tableAttributes renders the ALTER TABLE statements for the options that
+// CREATE TABLE does not accept.
+func (r *renderer) tableAttributes(t *core.Table) []string {
+	o := options(t)
+	var stmts []string
+	if o.AppendMode {
+		if columnOrganized(t) {
+			r.script.Warnf("table %s: column-organized tables do not support APPEND mode; it was ignored", t.Name)
+		} else {
+			stmts = append(stmts, appendMode(t.Name, true))
+		}
+	}
+	if o.Volatile {
+		stmts = append(stmts, volatile(t.Name, true))
+	}
+	return stmts
+}
+
+func appendMode(table string, on bool) string {
+	if on {
+		return "ALTER TABLE " + quote(table) + " APPEND ON"
+	}
+	return "ALTER TABLE " + quote(table) + " APPEND OFF"
+}
+
+func volatile(table string, on bool) string {
+	if on {
+		return "ALTER TABLE " + quote(table) + " VOLATILE CARDINALITY"
+	}
+	return "ALTER TABLE " + quote(table) + " NOT VOLATILE CARDINALITY"
+}
+
+func (r *renderer) addColumn(t *core.Table, c *core.Column) {
+	r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", quote(t.Name), r.columnDefinition(t, c)))
+	r.columnExtras(t, c)
+}
+
+// alterTableOptions renders a table option change. Changing the row
+// compression only affects new rows; existing rows are compressed by the
+// next REORG.
+func (r *renderer) alterTableOptions(c *diff.TableOptionChange) {
+	t, o := c.New, options(c.New)
+	name := quote(t.Name)
+	for _, f := range c.Fields {
+		switch f.Name {
+		case "db2.compress":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s COMPRESS %s", name, strings.ToUpper(o.Compress)))
+			r.script.Warnf("table %s: the compression of existing rows changes with the next REORG", t.Name)
+		case "db2.data_capture":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s DATA CAPTURE %s", name, strings.ToUpper(o.DataCapture)))
+		case "db2.append_mode":
+			r.script.Add(appendMode(t.Name, o.AppendMode))
+		case "db2.volatile":
+			r.script.Add(volatile(t.Name, o.Volatile))
+		case "db2.organize_by":
+			r.script.Warnf("table %s: the organization cannot be changed on an existing table; recreate the table", t.Name)
+		case "tablespace":
+			r.script.Warnf("table %s: moving a table to another tablespace requires SYSPROC.ADMIN_MOVE_TABLE", t.Name)
+		}
+	}
+}
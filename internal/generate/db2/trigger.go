@@ -0,0 +1,36 @@
+package db2
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+)
+
+// Db2 has no ON UPDATE column clause. A column with OnUpdate gets a BEFORE
+// UPDATE trigger that assigns the expression unless the statement changed
+// the column itself.
+
+func onUpdateName(t *core.Table, c *core.Column) string {
+	return t.Name + "_" + c.Name + "_on_update"
+}
+
+// createOnUpdateTrigger renders the trigger that emulates ON UPDATE for c.
+func (r *renderer) createOnUpdateTrigger(t *core.Table, c *core.Column) {
+	if c.OnUpdate == nil {
+		return
+	}
+	r.script.Add(fmt.Sprintf("CREATE OR REPLACE TRIGGER %s\n"+
+		"NO CASCADE BEFORE UPDATE ON %s\n"+
+		"REFERENCING OLD AS o NEW AS n\n"+
+		"FOR EACH ROW\n"+
+		"WHEN (n.%s IS NOT DISTINCT FROM o.%s)\n"+
+		"SET n.%s = %s",
+		quote(onUpdateName(t, c)), quote(t.Name),
+		quote(c.Name), quote(c.Name), quote(c.Name), defaultValue(c, *c.OnUpdate)))
+}
+
+func (r *renderer) dropOnUpdateTrigger(t *core.Table, c *core.Column) {
+	if c.OnUpdate != nil {
+		r.script.Add("DROP TRIGGER " + quote(onUpdateName(t, c)))
+	}
+}
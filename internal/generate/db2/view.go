@@ -0,0 +1,61 @@
+package db2
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// viewChange renders the statements of a view change. Db2 implements
+// materialized views as materialized query tables, which are dropped as
+// tables.
+func (r *renderer) viewChange(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddView:
+		r.createView(c.View)
+	case *diff.DropView:
+		if c.View.Materialized {
+			r.script.Add("DROP TABLE " + quote(c.View.Name))
+		} else {
+			r.script.Add("DROP VIEW " + quote(c.View.Name))
+		}
+	default:
+		return fmt.Errorf("db2: unsupported change %T", c)
+	}
+	return nil
+}
+
+// mqtRefresh maps the refresh policy of a materialized view to the REFRESH
+// clause of its materialized query table.
+var mqtRefresh = map[core.RefreshPolicy]string{
+	"":                   "DEFERRED",
+	core.RefreshOnDemand: "DEFERRED",
+	core.RefreshOnCommit: "IMMEDIATE",
+}
+
+// createView renders CREATE VIEW, or a materialized query table for a
+// materialized view. A new materialized query table is empty until it is
+// refreshed, so it is refreshed right away. Db2 views always run with the
+// privileges of their owner.
+func (r *renderer) createView(v *core.View) {
+	if v.Security == core.SecurityInvoker {
+		r.script.Warnf("view %s: Db2 does not support SECURITY INVOKER; it was ignored", v.Name)
+	}
+	columns, def := generate.ViewColumns(v, quote), generate.ViewDefinition(v)
+	if v.Materialized {
+		r.script.Add(fmt.Sprintf("CREATE TABLE %s%s AS (%s) DATA INITIALLY DEFERRED REFRESH %s",
+			quote(v.Name), columns, def, mqtRefresh[v.Refresh]))
+		r.script.Add("REFRESH TABLE " + quote(v.Name))
+	} else {
+		stmt := fmt.Sprintf("CREATE VIEW %s%s AS %s", quote(v.Name), columns, def)
+		if v.CheckOption != core.CheckOptionNone {
+			stmt += fmt.Sprintf(" WITH %s CHECK OPTION", v.CheckOption)
+		}
+		r.script.Add(stmt)
+	}
+	if v.Comment != "" {
+		r.script.Add(tableComment(v.Name, v.Comment))
+	}
+}
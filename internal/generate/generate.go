@@ -0,0 +1,165 @@
+// Package generate contains the Generator interface that renders a
+// core.Database or a diff.ChangeSet to dialect-specific SQL, a registry of
+// generators keyed by dialect, and helpers shared by the dialect packages.
+package generate
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+)
+
+type Generator interface {
+	// Generate renders the statements that create every table in db.
+	Generate(db *core.Database) (*Script, error)
+	// GenerateChanges renders the statements that apply the change set.
+	GenerateChanges(cs *diff.ChangeSet) (*Script, error)
+}
+
+// Normalizer is implemented by the generators of databases that store a
+// schema in another form than it is declared in, such as SQLite, where a
+// column type comes down to its storage class. Normalize rewrites db in
+// place into the stored form. A declared schema and the schema introspected
+// from the database created from it normalize alike, so diff.Databases
+// reports only the changes the database can hold.
+type Normalizer interface {
+	Normalize(db *core.Database)
+}
+
+// Normalize normalizes db with the generator of its dialect. It leaves db
+// unchanged when the generator has no Normalizer.
+func Normalize(db *core.Database) {
+	g, err := NewGenerator(db.Dialect)
+	if err != nil {
+		return
+	}
+	if n, ok := g.(Normalizer); ok {
+		n.Normalize(db)
+	}
+}
+
+// Script is an ordered list of SQL statements produced by a Generator,
+// together with warnings about schema features that could not be rendered
+// for the target dialect.
+type Script struct {
+	// Statements hold the SQL statements without a trailing terminator.
+	Statements []string
+	// Warnings describe skipped or emulated features.
+	Warnings []string
+	// BatchSeparator, when set, is written on a line of its own after every
+	// statement, as SQL Server tools expect GO between batches.
+	BatchSeparator string
+}
+
+// Add appends statements to the script.
+func (s *Script) Add(stmts ...string) {
+	s.Statements = append(s.Statements, stmts...)
+}
+
+// Warnf records a warning about the generated script.
+func (s *Script) Warnf(format string, args ...any) {
+	s.Warnings = append(s.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Append adds the statements and warnings of other to s.
+func (s *Script) Append(other *Script) {
+	s.Statements = append(s.Statements, other.Statements...)
+	s.Warnings = append(s.Warnings, other.Warnings...)
+}
+
+// Empty reports whether the script has no statements.
+func (s *Script) Empty() bool {
+	return s == nil || len(s.Statements) == 0
+}
+
+// String renders the script as SQL text. Statements are terminated with a
+// semicolon. Procedural blocks (BEGIN … END;) already end with a semicolon
+// and are terminated with a "/" line instead, which is the convention
+// understood by Oracle tooling. With a BatchSeparator, every statement is
+// followed by a separator line, which also ends the blocks.
+func (s *Script) String() string {
+	var sb strings.Builder
+	for i, stmt := range s.Statements {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(stmt)
+		switch {
+		case s.BatchSeparator != "" && IsBlock(stmt):
+			sb.WriteString("\n" + s.BatchSeparator + "\n")
+		case s.BatchSeparator != "":
+			sb.WriteString(";\n" + s.BatchSeparator + "\n")
+		case IsBlock(stmt):
+			sb.WriteString("\n/\n")
+		default:
+			sb.WriteString(";\n")
+		}
+	}
+	return sb.String()
+}
+
+// BatchSeparator returns the batch separator of the scripts of dialect:
+// GO for SQL Server, whose tools require CREATE TRIGGER and CREATE VIEW to
+// start a batch, and "" for the other dialects.
+func BatchSeparator(d core.Dialect) string {
+	if d == core.DialectMSSQL {
+		return "GO"
+	}
+	return ""
+}
+
+var (
+	registry = make(map[core.Dialect]func() Generator)
+	mu       sync.RWMutex
+)
+
+func Register(dialect core.Dialect, fn func() Generator) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[dialect] = fn
+}
+
+func NewGenerator(dialect core.Dialect) (Generator, error) {
+	mu.RLock()
+	fn, ok := registry[dialect]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported dialect %v", dialect)
+	}
+	return fn(), nil
+}
+
+// CreateOrder tracks the tables a change set creates, so that generators can
+// render a foreign key inline in CREATE TABLE only when the referenced table
+// already exists. References inside a foreign key cycle must be added with
+// ALTER TABLE after all tables have been created.
+type CreateOrder struct {
+	pending map[string]bool
+	created map[string]bool
+}
+
+// NewCreateOrder collects the tables added by cs.
+func NewCreateOrder(cs *diff.ChangeSet) *CreateOrder {
+	o := &CreateOrder{pending: make(map[string]bool), created: make(map[string]bool)}
+	for _, c := range cs.Changes {
+		if add, ok := c.(*diff.AddTable); ok {
+			o.pending[add.Table.Name] = true
+		}
+	}
+	return o
+}
+
+// Created marks table as created.
+func (o *CreateOrder) Created(table string) {
+	o.created[table] = true
+}
+
+// Inline reports whether the foreign key con of table t can be declared in
+// its CREATE TABLE statement.
+func (o *CreateOrder) Inline(t *core.Table, con *core.Constraint) bool {
+	ref := con.ReferencedTable
+	return ref == t.Name || !o.pending[ref] || o.created[ref]
+}
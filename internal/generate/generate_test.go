@@ -0,0 +1,28 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestScriptString(t *testing.T) {
+	s := &Script{}
+	s.Add("CREATE TABLE t (id INT)", "BEGIN\n  NULL;\nEND;")
+
+	assert.Equal(t, "CREATE TABLE t (id INT);\n\nBEGIN\n  NULL;\nEND;\n/\n", s.String())
+	assert.True(t, (*Script)(nil).Empty())
+
+	s.BatchSeparator = BatchSeparator(core.DialectMSSQL)
+	assert.Equal(t, "CREATE TABLE t (id INT);\nGO\n\nBEGIN\n  NULL;\nEND;\nGO\n", s.String())
+	assert.Empty(t, BatchSeparator(core.DialectOracle))
+}
+
+func TestNewGeneratorUnsupported(t *testing.T) {
+	g, err := NewGenerator(core.Dialect("unknown"))
+	require.Error(t, err)
+	assert.Nil(t, g)
+}
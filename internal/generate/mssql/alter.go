@@ -0,0 +1,172 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// alterColumn renders the statements for a column change. The type,
+// collation, and nullability are set together by one ALTER COLUMN; the
+// default is a separate constraint.
+func (r *renderer) alterColumn(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AlterColumnType:
+		r.alterType(c.Table, c.Old, c.New)
+	case *diff.AlterNullability:
+		r.alterColumnDefinition(c.Table, c.New)
+	case *diff.ChangeDefault:
+		r.replaceDefault(c.Table, c.Old, c.New)
+	case *diff.AlterColumn:
+		for _, f := range c.Fields {
+			r.alterField(c, f)
+		}
+	}
+}
+
+// alterType changes the type of a column. SQL Server refuses to change the
+// type of a column that has a DEFAULT constraint, so the default is
+// recreated around the change, as is the CHECK constraint of enum columns.
+func (r *renderer) alterType(t *core.Table, from, to *core.Column) {
+	name := quote(t.Name)
+	if from.DefaultValue != nil {
+		r.script.Add(dropDefault(t, from))
+	}
+	if generate.IsEnum(from) {
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", name, quote(enumCheckName(t, from))))
+	}
+	r.alterColumnDefinition(t, to)
+	if generate.IsEnum(to) {
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD %s", name, enumCheck(t, to)))
+	}
+	if to.DefaultValue != nil {
+		r.script.Add(addDefault(t, to))
+	}
+	r.altered["default:"+t.Name+"."+to.Name] = true
+}
+
+// alterColumnDefinition renders ALTER COLUMN with the type, collation, and
+// nullability of c. It is called for each of these changes, so it records
+// the column to render the statement only once.
+func (r *renderer) alterColumnDefinition(t *core.Table, c *core.Column) {
+	key := t.Name + "." + c.Name
+	if r.altered[key] {
+		return
+	}
+	r.altered[key] = true
+	if c.IsGenerated {
+		r.script.Warnf("column %s.%s: computed columns cannot be altered; recreate the column", t.Name, c.Name)
+		return
+	}
+	def := columnType(c)
+	if c.Collate != "" {
+		def += " COLLATE " + c.Collate
+	}
+	r.script.Add(r.alterColumnStatement(t, c, def+" "+nullability(c)))
+}
+
+func (r *renderer) alterColumnStatement(t *core.Table, c *core.Column, action string) string {
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", quote(t.Name), quote(c.Name), action)
+}
+
+// replaceDefault drops the DEFAULT constraint of a column and adds the new
+// one, unless alterType already did.
+func (r *renderer) replaceDefault(t *core.Table, from, to *core.Column) {
+	key := "default:" + t.Name + "." + to.Name
+	if r.altered[key] {
+		return
+	}
+	r.altered[key] = true
+	if from.DefaultValue != nil {
+		r.script.Add(dropDefault(t, from))
+	}
+	if to.DefaultValue != nil {
+		r.script.Add(addDefault(t, to))
+	}
+}
+
+func addDefault(t *core.Table, c *core.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s FOR %s", quote(t.Name), defaultConstraint(t, c), quote(c.Name))
+}
+
+func dropDefault(t *core.Table, c *core.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", quote(t.Name), quote(defaultName(t, c.Name)))
+}
+
+// alterField renders a single changed column attribute. Attributes of other
+// dialects are ignored.
+func (r *renderer) alterField(c *diff.AlterColumn, f diff.FieldChange) {
+	t, col := c.Table, c.New
+	switch f.Name {
+	case "comment":
+		r.script.Add(changeDescription(c.Old.Comment, col.Comment, objectPath(t, "COLUMN", col.Name)))
+	case "collate":
+		r.alterColumnDefinition(t, col)
+	case "mssql.sparse":
+		r.script.Add(r.alterColumnStatement(t, col, toggle(col.MSSQL != nil && col.MSSQL.Sparse, "SPARSE")))
+	case "mssql.row_guid_col":
+		r.script.Add(r.alterColumnStatement(t, col, toggle(col.MSSQL != nil && col.MSSQL.RowGUIDCol, "ROWGUIDCOL")))
+	case "mssql.persisted":
+		r.script.Add(r.alterColumnStatement(t, col, toggle(col.MSSQL != nil && col.MSSQL.Persisted, "PERSISTED")))
+	case "mssql.data_masking.function":
+		r.alterMasking(t, col)
+	case "on_update":
+		r.dropOnUpdateTrigger(t, c.Old)
+		r.createOnUpdateTrigger(t, col)
+	default:
+		r.warnRecreate(t, col, f.Name)
+	}
+}
+
+func toggle(on bool, property string) string {
+	if on {
+		return "ADD " + property
+	}
+	return "DROP " + property
+}
+
+func (r *renderer) alterMasking(t *core.Table, c *core.Column) {
+	if fn := maskingFunction(c); fn != "" {
+		r.script.Add(r.alterColumnStatement(t, c, "ADD "+masked(fn)))
+		return
+	}
+	r.script.Add(r.alterColumnStatement(t, c, "DROP MASKED"))
+}
+
+// recreateFields are the column attributes SQL Server cannot alter in place.
+var recreateFields = map[string]string{
+	"auto_increment":                     "identity",
+	"identity_seed":                      "identity",
+	"identity_increment":                 "identity",
+	"identity_generation":                "identity",
+	"mssql.identity_not_for_replication": "identity",
+	"is_generated":                       "computed column",
+	"generation_expression":              "computed column",
+	"generation_storage":                 "computed column",
+	"mssql.file_stream":                  "FILESTREAM",
+}
+
+// warnRecreate records a warning for an attribute that needs the column
+// to be recreated, once per column and kind of attribute.
+func (r *renderer) warnRecreate(t *core.Table, c *core.Column, field string) {
+	kind, ok := recreateFields[field]
+	if !ok {
+		if strings.HasPrefix(field, "mssql.always_encrypted.") {
+			r.warnOnce(t, c, "encryption", "column %s.%s: Always Encrypted settings must be changed with client-side tooling", t.Name, c.Name)
+		}
+		return
+	}
+	r.warnOnce(t, c, kind, "column %s.%s: the %s cannot be altered; recreate the column", t.Name, c.Name, kind)
+}
+
+func (r *renderer) warnOnce(t *core.Table, c *core.Column, kind, format string, args ...any) {
+	key := "warn:" + kind + ":" + t.Name + "." + c.Name
+	if r.altered[key] {
+		return
+	}
+	r.altered[key] = true
+	r.script.Warnf(format, args...)
+}
@@ -0,0 +1,264 @@
+package mssql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+var typeMapper = generate.TypeMapper{
+	Types: map[string]string{
+		"varchar":   "NVARCHAR(%s)",
+		"char":      "NCHAR(%s)",
+		"text":      "NVARCHAR(MAX)",
+		"tinyint":   "TINYINT",
+		"smallint":  "SMALLINT",
+		"int":       "INT",
+		"integer":   "INT",
+		"bigint":    "BIGINT",
+		"decimal":   "DECIMAL(%s)",
+		"numeric":   "NUMERIC(%s)",
+		"float":     "REAL",
+		"double":    "FLOAT",
+		"boolean":   "BIT",
+		"bool":      "BIT",
+		"date":      "DATE",
+		"time":      "TIME",
+		"timestamp": "DATETIMEOFFSET",
+		"datetime":  "DATETIME2",
+		"json":      "NVARCHAR(MAX)",
+		"uuid":      "UNIQUEIDENTIFIER",
+		"blob":      "VARBINARY(MAX)",
+		"binary":    "BINARY(%s)",
+		"varbinary": "VARBINARY(%s)",
+	},
+	DefaultArgs: map[string]string{
+		"varchar":   "255",
+		"varbinary": "MAX",
+	},
+	Fallback: map[core.DataType]string{
+		core.DataTypeString:   "NVARCHAR(MAX)",
+		core.DataTypeInt:      "INT",
+		core.DataTypeFloat:    "FLOAT",
+		core.DataTypeBoolean:  "BIT",
+		core.DataTypeDatetime: "DATETIME2",
+		core.DataTypeJSON:     "NVARCHAR(MAX)",
+		core.DataTypeUUID:     "UNIQUEIDENTIFIER",
+		core.DataTypeBinary:   "VARBINARY(MAX)",
+	},
+	StripSuffix: true,
+}
+
+// isIdentity reports whether c is rendered as an IDENTITY column.
+func isIdentity(c *core.Column) bool {
+	return c.AutoIncrement || c.IdentityGeneration != ""
+}
+
+// columnType returns the SQL Server type of c. Enums become NVARCHAR columns
+// sized for their longest value.
+func columnType(c *core.Column) string {
+	if !generate.IsEnum(c) {
+		return typeMapper.ColumnType(c)
+	}
+	size := 1
+	for _, v := range c.EnumValues {
+		size = max(size, len([]rune(v)))
+	}
+	return "NVARCHAR(" + strconv.Itoa(size) + ")"
+}
+
+// defaultName returns the name of the DEFAULT constraint of a column.
+func defaultName(t *core.Table, column string) string {
+	return "df_" + t.Name + "_" + column
+}
+
+// enumCheckName returns the name of the CHECK constraint that emulates an
+// enum column.
+func enumCheckName(t *core.Table, c *core.Column) string {
+	return "chk_" + t.Name + "_" + c.Name + "_enum"
+}
+
+func enumCheck(t *core.Table, c *core.Column) string {
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s IN (%s))",
+		quote(enumCheckName(t, c)), quote(c.Name), generate.QuoteList(c.EnumValues, quoteString))
+}
+
+// columnDefinition renders a column as it appears in CREATE TABLE and in
+// ALTER TABLE ... ADD clauses, following the clause order of the SQL Server
+// grammar.
+func (r *renderer) columnDefinition(t *core.Table, c *core.Column) string {
+	if c.IsGenerated {
+		return computedColumn(c)
+	}
+	parts := []string{quote(c.Name), columnType(c)}
+	parts = append(parts, storageClauses(c)...)
+	if c.DefaultValue != nil {
+		parts = append(parts, defaultConstraint(t, c))
+	}
+	if isIdentity(c) {
+		parts = append(parts, identityClause(c))
+	}
+	parts = append(parts, nullability(c))
+	parts = append(parts, r.optionClauses(t, c)...)
+	if generate.IsEnum(c) {
+		parts = append(parts, enumCheck(t, c))
+	}
+	r.warnUnsupported(t, c)
+	return strings.Join(parts, " ")
+}
+
+// computedColumn renders a computed column. STORED generated columns and
+// columns with the persisted option are PERSISTED.
+func computedColumn(c *core.Column) string {
+	def := quote(c.Name) + " AS (" + c.GenerationExpression + ")"
+	if c.GenerationStorage == core.GenerationStored || (c.MSSQL != nil && c.MSSQL.Persisted) {
+		def += " PERSISTED"
+		if !c.Nullable {
+			def += " NOT NULL"
+		}
+	}
+	return def
+}
+
+// storageClauses renders the clauses that precede the DEFAULT constraint.
+func storageClauses(c *core.Column) []string {
+	var parts []string
+	if c.MSSQL != nil && c.MSSQL.FileStream {
+		parts = append(parts, "FILESTREAM")
+	}
+	if c.Collate != "" {
+		parts = append(parts, "COLLATE "+c.Collate)
+	}
+	if c.MSSQL != nil && c.MSSQL.Sparse {
+		parts = append(parts, "SPARSE")
+	}
+	if fn := maskingFunction(c); fn != "" {
+		parts = append(parts, masked(fn))
+	}
+	return parts
+}
+
+func maskingFunction(c *core.Column) string {
+	if c.MSSQL == nil || c.MSSQL.DataMasking == nil {
+		return ""
+	}
+	return c.MSSQL.DataMasking.Function
+}
+
+func masked(fn string) string {
+	return "MASKED WITH (FUNCTION = " + generate.QuoteString(fn) + ")"
+}
+
+func defaultConstraint(t *core.Table, c *core.Column) string {
+	return "CONSTRAINT " + quote(defaultName(t, c.Name)) + " DEFAULT " + defaultValue(c, *c.DefaultValue)
+}
+
+func identityClause(c *core.Column) string {
+	seed, step := c.IdentitySeed, c.IdentityIncrement
+	if seed == 0 {
+		seed = 1
+	}
+	if step == 0 {
+		step = 1
+	}
+	clause := fmt.Sprintf("IDENTITY(%d,%d)", seed, step)
+	if c.MSSQL != nil && c.MSSQL.IdentityNotForReplication {
+		clause += " NOT FOR REPLICATION"
+	}
+	return clause
+}
+
+func nullability(c *core.Column) string {
+	if c.Nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+// optionClauses renders the clauses that follow NULL or NOT NULL.
+func (r *renderer) optionClauses(t *core.Table, c *core.Column) []string {
+	var parts []string
+	if c.MSSQL != nil && c.MSSQL.RowGUIDCol {
+		parts = append(parts, "ROWGUIDCOL")
+	}
+	if c.MSSQL != nil && c.MSSQL.AlwaysEncrypted != nil {
+		parts = append(parts, r.encryptedWith(t, c))
+	}
+	return parts
+}
+
+// encryptedWith renders the Always Encrypted clause. Deterministic
+// encryption of character columns requires a BIN2 collation.
+func (r *renderer) encryptedWith(t *core.Table, c *core.Column) string {
+	ae := c.MSSQL.AlwaysEncrypted
+	typ := strings.ToUpper(ae.EncryptionType)
+	if typ == "" {
+		typ = "RANDOMIZED"
+	}
+	alg := ae.Algorithm
+	if alg == "" {
+		alg = "AEAD_AES_256_CBC_HMAC_SHA_256"
+	}
+	if typ == "DETERMINISTIC" && c.Type == core.DataTypeString && !strings.HasSuffix(strings.ToUpper(c.Collate), "_BIN2") {
+		r.script.Warnf("column %s.%s: deterministic encryption of character columns requires a BIN2 collation", t.Name, c.Name)
+	}
+	return fmt.Sprintf("ENCRYPTED WITH (COLUMN_ENCRYPTION_KEY = %s, ENCRYPTION_TYPE = %s, ALGORITHM = %s)",
+		quote(ae.ColumnEncryptionKey), typ, generate.QuoteString(alg))
+}
+
+// defaultValue renders a DEFAULT value. SQL Server has no boolean literals
+// and its own names for the current date and time.
+func defaultValue(c *core.Column, v string) string {
+	v = strings.TrimSpace(v)
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return quoteString(v)
+	case generate.DefaultBoolean:
+		return boolLiteral(generate.BoolDefault(v))
+	case generate.DefaultNumber:
+		if c.Type == core.DataTypeBoolean {
+			return boolLiteral(generate.BoolDefault(v))
+		}
+		return v
+	case generate.DefaultTimestamp:
+		return generate.TimestampDefault(v, timestampFuncs, "SYSDATETIME()")
+	default:
+		return v
+	}
+}
+
+func boolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// timestampFuncs maps the current date and time functions of other
+// dialects to SQL Server. GETDATE and SYSDATETIME are kept as written;
+// SQL Server has no CURRENT_DATE or CURRENT_TIME, so those cast GETDATE.
+var timestampFuncs = map[string]string{
+	"CURRENT_TIMESTAMP": "CURRENT_TIMESTAMP",
+	"GETDATE":           "",
+	"SYSDATETIME":       "",
+	"CURRENT_DATE":      "CAST(GETDATE() AS DATE)",
+	"CURRENT_TIME":      "CAST(GETDATE() AS TIME)",
+	"LOCALTIME":         "CAST(GETDATE() AS TIME)",
+}
+
+// warnUnsupported records warnings for column attributes that SQL Server
+// has no equivalent for.
+func (r *renderer) warnUnsupported(t *core.Table, c *core.Column) {
+	if c.Charset != "" {
+		r.script.Warnf("column %s.%s: SQL Server has no per-column character set; %s was ignored", t.Name, c.Name, c.Charset)
+	}
+	if c.Invisible {
+		r.script.Warnf("column %s.%s: SQL Server only supports hidden period columns; the column is visible", t.Name, c.Name)
+	}
+	if c.MSSQL != nil && c.MSSQL.Sparse && !c.Nullable {
+		r.script.Warnf("column %s.%s: SPARSE columns must be nullable", t.Name, c.Name)
+	}
+}
@@ -0,0 +1,47 @@
+package mssql
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+)
+
+// SQL Server stores comments as MS_Description extended properties of the
+// table, its columns, and its indexes, and of views. Tables and views are
+// assumed to live in dbo.
+
+const defaultSchema = "dbo"
+
+// objectPath renders the level arguments that address a table, or one of
+// its columns or indexes when kind is "COLUMN" or "INDEX".
+func objectPath(t *core.Table, kind, name string) string {
+	path := fmt.Sprintf("@level0type = N'SCHEMA', @level0name = %s, @level1type = N'TABLE', @level1name = %s",
+		quoteString(defaultSchema), quoteString(t.Name))
+	if kind != "" {
+		path += fmt.Sprintf(", @level2type = N'%s', @level2name = %s", kind, quoteString(name))
+	}
+	return path
+}
+
+// viewPath renders the level arguments that address a view.
+func viewPath(v *core.View) string {
+	return fmt.Sprintf("@level0type = N'SCHEMA', @level0name = %s, @level1type = N'VIEW', @level1name = %s",
+		quoteString(defaultSchema), quoteString(v.Name))
+}
+
+func addDescription(text, path string) string {
+	return fmt.Sprintf("EXEC sp_addextendedproperty @name = N'MS_Description', @value = %s, %s", quoteString(text), path)
+}
+
+// changeDescription renders the statement that replaces the description
+// old with text.
+func changeDescription(old, text, path string) string {
+	switch {
+	case old == "":
+		return addDescription(text, path)
+	case text == "":
+		return "EXEC sp_dropextendedproperty @name = N'MS_Description', " + path
+	default:
+		return fmt.Sprintf("EXEC sp_updateextendedproperty @name = N'MS_Description', @value = %s, %s", quoteString(text), path)
+	}
+}
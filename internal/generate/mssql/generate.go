@@ -0,0 +1,113 @@
+// Package mssql contains the DDL generator for Microsoft SQL Server. Besides
+// the portable schema it renders the SQL Server table options (filegroups,
+// compression, memory-optimized, temporal, and ledger tables) and column
+// options (sparse, FILESTREAM, masked, and encrypted columns).
+//
+// DEFAULT values are rendered as named constraints (df_{table}_{column}) so
+// that later migrations can drop them by name.
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+func init() {
+	generate.Register(core.DialectMSSQL, New)
+}
+
+// Generator renders SQL Server DDL.
+type Generator struct{}
+
+func New() generate.Generator {
+	return &Generator{}
+}
+
+// Generate renders a CREATE TABLE statement for every table and a CREATE
+// VIEW statement for every view in db, ordered so that referenced tables
+// and views are created first.
+func (g *Generator) Generate(db *core.Database) (*generate.Script, error) {
+	return g.GenerateChanges(diff.Databases(nil, db))
+}
+
+// GenerateChanges renders the statements that apply cs.
+func (g *Generator) GenerateChanges(cs *diff.ChangeSet) (*generate.Script, error) {
+	r := &renderer{
+		script:  &generate.Script{BatchSeparator: generate.BatchSeparator(core.DialectMSSQL)},
+		order:   generate.NewCreateOrder(cs),
+		altered: make(map[string]bool),
+	}
+	for _, c := range cs.Changes {
+		if err := r.change(c); err != nil {
+			return nil, err
+		}
+	}
+	r.script.Add(r.deferred...)
+	return r.script, nil
+}
+
+// renderer collects the T-SQL statements of one change set, each of which
+// the script runs as a batch of its own. The type, collation and
+// nullability of a column are set by a single ALTER COLUMN.
+type renderer struct {
+	script *generate.Script
+	order  *generate.CreateOrder
+	// deferred holds foreign keys of new tables that reference a table
+	// created later in the script.
+	deferred []string
+	// altered tracks columns that already received an ALTER COLUMN
+	// statement, since one statement covers the type and nullability.
+	altered map[string]bool
+}
+
+func (r *renderer) change(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddTable:
+		r.createTable(c.Table)
+	case *diff.DropTable:
+		r.dropTable(c.Table)
+	case *diff.ChangeTableComment:
+		r.script.Add(changeDescription(c.Old, c.New, objectPath(c.Table, "", "")))
+	case *diff.TableOptionChange:
+		r.alterTableOptions(c)
+	case *diff.AddColumn:
+		r.addColumn(c.Table, c.Column)
+	case *diff.DropColumn:
+		r.dropColumn(c.Table, c.Column)
+	case *diff.AlterColumnType, *diff.AlterNullability, *diff.ChangeDefault, *diff.AlterColumn:
+		r.alterColumn(c)
+	case *diff.AddConstraint, *diff.DropConstraint, *diff.AddIndex, *diff.DropIndex:
+		r.keyChange(c)
+	default:
+		return r.viewChange(c)
+	}
+	return nil
+}
+
+func (r *renderer) keyChange(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AddConstraint:
+		r.addConstraint(c.Table, c.Constraint)
+	case *diff.DropConstraint:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s",
+			quote(c.Table.Name), quote(generate.ConstraintName(c.Table, c.Constraint))))
+	case *diff.AddIndex:
+		r.createIndex(c.Table, c.Index)
+	case *diff.DropIndex:
+		r.script.Add(fmt.Sprintf("DROP INDEX %s ON %s", quote(generate.IndexName(c.Table, c.Index)), quote(c.Table.Name)))
+	}
+}
+
+// quote quotes name as a bracket-delimited SQL Server identifier.
+func quote(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// quoteString quotes s as a Unicode string literal.
+func quoteString(s string) string {
+	return "N" + generate.QuoteString(s)
+}
@@ -0,0 +1,301 @@
+package mssql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+	"smf/internal/pars/toml"
+)
+
+func parse(t *testing.T, schema string) *core.Database {
+	t.Helper()
+	db, err := toml.NewParser().Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+	return db
+}
+
+const customersSchema = `
+[database]
+name = "app"
+dialect = "mssql"
+
+[[tables]]
+name = "customers"
+comment = "customer master data"
+
+  [tables.options.sqlserver]
+  file_group = "data"
+  data_compression = "page"
+  system_versioning = true
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+  auto_increment = true
+  identity_seed = 1000
+
+  [[tables.columns]]
+  name = "email"
+  type = "varchar(120)"
+
+    [tables.columns.mssql.data_masking]
+    function = "email()"
+
+  [[tables.columns]]
+  name = "ssn"
+  type = "char(11)"
+  nullable = true
+  collate = "Latin1_General_BIN2"
+
+    [tables.columns.mssql.always_encrypted]
+    column_encryption_key = "cek_main"
+    encryption_type = "deterministic"
+
+  [[tables.columns]]
+  name = "active"
+  type = "boolean"
+  default = "true"
+`
+
+func TestGenerateCreateTable(t *testing.T) {
+	t.Parallel()
+	script, err := New().Generate(parse(t, customersSchema))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"CREATE TABLE [customers] (\n" +
+			"  [id] BIGINT IDENTITY(1000,1) NOT NULL,\n" +
+			"  [email] NVARCHAR(120) MASKED WITH (FUNCTION = 'email()') NOT NULL,\n" +
+			"  [ssn] NCHAR(11) COLLATE Latin1_General_BIN2 NULL ENCRYPTED WITH " +
+			"(COLUMN_ENCRYPTION_KEY = [cek_main], ENCRYPTION_TYPE = DETERMINISTIC, ALGORITHM = 'AEAD_AES_256_CBC_HMAC_SHA_256'),\n" +
+			"  [active] BIT CONSTRAINT [df_customers_active] DEFAULT 1 NOT NULL,\n" +
+			"  [valid_from] DATETIME2 GENERATED ALWAYS AS ROW START HIDDEN CONSTRAINT [df_customers_valid_from] DEFAULT SYSUTCDATETIME(),\n" +
+			"  [valid_to] DATETIME2 GENERATED ALWAYS AS ROW END HIDDEN CONSTRAINT [df_customers_valid_to] " +
+			"DEFAULT CONVERT(DATETIME2, '9999-12-31 23:59:59.9999999'),\n" +
+			"  PERIOD FOR SYSTEM_TIME ([valid_from], [valid_to]),\n" +
+			"  CONSTRAINT [pk_customers] PRIMARY KEY ([id])\n" +
+			") ON [data] WITH (DATA_COMPRESSION = PAGE, SYSTEM_VERSIONING = ON (HISTORY_TABLE = [dbo].[customers_history]))",
+		"EXEC sp_addextendedproperty @name = N'MS_Description', @value = N'customer master data', " +
+			"@level0type = N'SCHEMA', @level0name = N'dbo', @level1type = N'TABLE', @level1name = N'customers'",
+	}, script.Statements)
+	assert.Empty(t, script.Warnings)
+}
+
+func TestGenerateTableOptions(t *testing.T) {
+	t.Parallel()
+	tbl := func(o *core.SQLServerTableOptions) *core.Table {
+		return &core.Table{
+			Name:        "events",
+			Columns:     []*core.Column{{Name: "id", PortableType: "int"}},
+			Constraints: []*core.Constraint{{Name: "pk_events", Type: core.ConstraintPrimaryKey, Columns: []string{"id"}}},
+			Options:     core.TableOptions{SQLServer: o},
+		}
+	}
+	tests := map[string]struct {
+		opts  *core.SQLServerTableOptions
+		table string
+		extra []string
+	}{
+		"memory optimized": {
+			opts:  &core.SQLServerTableOptions{MemoryOptimized: true},
+			table: `CONSTRAINT [pk_events] PRIMARY KEY NONCLUSTERED ([id])` + "\n) WITH (MEMORY_OPTIMIZED = ON, DURABILITY = SCHEMA_AND_DATA)",
+		},
+		"columnstore": {
+			opts:  &core.SQLServerTableOptions{DataCompression: "COLUMNSTORE", TextImageOn: "lobs"},
+			table: `CONSTRAINT [pk_events] PRIMARY KEY NONCLUSTERED ([id])` + "\n) TEXTIMAGE_ON [lobs]",
+			extra: []string{`CREATE CLUSTERED COLUMNSTORE INDEX [cci_events] ON [events]`},
+		},
+		"append-only ledger": {
+			opts:  &core.SQLServerTableOptions{LedgerTable: true},
+			table: "\n) WITH (LEDGER = ON (APPEND_ONLY = ON))",
+		},
+		"updatable ledger": {
+			opts:  &core.SQLServerTableOptions{LedgerTable: true, SystemVersioning: true},
+			table: "\n) WITH (SYSTEM_VERSIONING = ON (HISTORY_TABLE = [dbo].[events_history]), LEDGER = ON)",
+		},
+	}
+	for name, tt := range tests {
+		script, err := New().Generate(&core.Database{Tables: []*core.Table{tbl(tt.opts)}})
+		require.NoError(t, err, name)
+		assert.True(t, strings.HasSuffix(script.Statements[0], tt.table), name)
+		assert.NotContains(t, script.Statements[0], "valid_from", name)
+		assert.ElementsMatch(t, tt.extra, script.Statements[1:], name)
+	}
+}
+
+func TestGenerateColumnDefinitions(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "files"}
+	now := "NOW()"
+	tests := []struct {
+		col  *core.Column
+		want string
+	}{
+		{&core.Column{Name: "total", PortableType: "int", IsGenerated: true, GenerationExpression: "[a] + [b]",
+			MSSQL: &core.MSSQLColumnOptions{Persisted: true}}, "[total] AS ([a] + [b]) PERSISTED NOT NULL"},
+		{&core.Column{Name: "doc", PortableType: "varbinary", Nullable: true,
+			MSSQL: &core.MSSQLColumnOptions{FileStream: true}}, "[doc] VARBINARY(MAX) FILESTREAM NULL"},
+		{&core.Column{Name: "guid", PortableType: "uuid",
+			MSSQL: &core.MSSQLColumnOptions{RowGUIDCol: true}}, "[guid] UNIQUEIDENTIFIER NOT NULL ROWGUIDCOL"},
+		{&core.Column{Name: "note", PortableType: "text", Nullable: true,
+			MSSQL: &core.MSSQLColumnOptions{Sparse: true}}, "[note] NVARCHAR(MAX) SPARSE NULL"},
+		{&core.Column{Name: "created", PortableType: "datetime", DefaultValue: &now},
+			"[created] DATETIME2 CONSTRAINT [df_files_created] DEFAULT SYSDATETIME() NOT NULL"},
+		{&core.Column{Name: "kind", Type: core.DataTypeEnum, EnumValues: []string{"a", "bbb"}},
+			"[kind] NVARCHAR(3) NOT NULL CONSTRAINT [chk_files_kind_enum] CHECK ([kind] IN (N'a', N'bbb'))"},
+		{&core.Column{Name: "id", PortableType: "int", AutoIncrement: true,
+			MSSQL: &core.MSSQLColumnOptions{IdentityNotForReplication: true}}, "[id] INT IDENTITY(1,1) NOT FOR REPLICATION NOT NULL"},
+	}
+	r := &renderer{script: &generate.Script{}}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, r.columnDefinition(tbl, tt.col), tt.col.Name)
+	}
+	assert.Empty(t, r.script.Warnings)
+}
+
+func TestGenerateChanges(t *testing.T) {
+	t.Parallel()
+	from := parse(t, customersSchema)
+	to := parse(t, strings.NewReplacer(
+		`type = "varchar(120)"`, `type = "varchar(200)"`,
+		`function = "email()"`, `function = "partial(1,\"XXX\",0)"`,
+		`default = "true"`, `default = "false"`,
+		`data_compression = "page"`, `data_compression = "row"`,
+	).Replace(customersSchema))
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE [customers] REBUILD WITH (DATA_COMPRESSION = ROW)`,
+		`ALTER TABLE [customers] ALTER COLUMN [email] NVARCHAR(200) NOT NULL`,
+		`ALTER TABLE [customers] ALTER COLUMN [email] ADD MASKED WITH (FUNCTION = 'partial(1,"XXX",0)')`,
+		`ALTER TABLE [customers] DROP CONSTRAINT [df_customers_active]`,
+		`ALTER TABLE [customers] ADD CONSTRAINT [df_customers_active] DEFAULT 0 FOR [active]`,
+	}, script.Statements)
+}
+
+func TestGenerateDropTemporalTable(t *testing.T) {
+	t.Parallel()
+	script, err := New().GenerateChanges(diff.Databases(parse(t, customersSchema), &core.Database{}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE [customers] SET (SYSTEM_VERSIONING = OFF)`,
+		`DROP TABLE [customers]`,
+		`DROP TABLE [dbo].[customers_history]`,
+	}, script.Statements)
+}
+
+func TestGenerateKeys(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "orders", Columns: []*core.Column{{Name: "id"}}}
+	enforced := false
+	cs := &diff.ChangeSet{Changes: []diff.Change{
+		&diff.AddConstraint{Table: tbl, Constraint: &core.Constraint{
+			Name: "fk_orders_customers", Type: core.ConstraintForeignKey, Columns: []string{"customer_id"},
+			ReferencedTable: "customers", ReferencedColumns: []string{"id"}, OnDelete: core.RefActionRestrict,
+		}},
+		&diff.AddConstraint{Table: tbl, Constraint: &core.Constraint{
+			Name: "chk_orders_total", Type: core.ConstraintCheck, CheckExpression: "[total] >= 0", Enforced: &enforced,
+		}},
+		&diff.AddIndex{Table: tbl, Index: &core.Index{Columns: []core.ColumnIndex{{Name: "placed_at", Order: core.SortDesc}}, Comment: "recent"}},
+		&diff.AddIndex{Table: tbl, Index: &core.Index{Type: core.IndexTypeFullText, Columns: []core.ColumnIndex{{Name: "notes"}}}},
+		&diff.DropIndex{Table: tbl, Index: &core.Index{Name: "idx_old"}},
+	}}
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE [orders] ADD CONSTRAINT [fk_orders_customers] FOREIGN KEY ([customer_id]) REFERENCES [customers] ([id]) ON DELETE NO ACTION`,
+		`ALTER TABLE [orders] WITH NOCHECK ADD CONSTRAINT [chk_orders_total] CHECK ([total] >= 0)`,
+		`ALTER TABLE [orders] NOCHECK CONSTRAINT [chk_orders_total]`,
+		`CREATE INDEX [idx_orders_placed_at] ON [orders] ([placed_at] DESC)`,
+		`EXEC sp_addextendedproperty @name = N'MS_Description', @value = N'recent', @level0type = N'SCHEMA', @level0name = N'dbo', ` +
+			`@level1type = N'TABLE', @level1name = N'orders', @level2type = N'INDEX', @level2name = N'idx_orders_placed_at'`,
+		`DROP INDEX [idx_old] ON [orders]`,
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 1)
+}
+
+func TestGenerateOnUpdateTrigger(t *testing.T) {
+	t.Parallel()
+	now := "CURRENT_TIMESTAMP"
+	tbl := &core.Table{
+		Name:        "posts",
+		Columns:     []*core.Column{{Name: "id", PortableType: "int"}, {Name: "updated_at", PortableType: "datetime", OnUpdate: &now}},
+		Constraints: []*core.Constraint{{Type: core.ConstraintPrimaryKey, Columns: []string{"id"}}},
+	}
+	script, err := New().Generate(&core.Database{Tables: []*core.Table{tbl}})
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 2)
+	assert.Equal(t, "CREATE TRIGGER [posts_updated_at_on_update] ON [posts] AFTER UPDATE AS\n"+
+		"BEGIN\n"+
+		"  SET NOCOUNT ON;\n"+
+		"  IF NOT UPDATE([updated_at])\n"+
+		"    UPDATE t SET [updated_at] = CURRENT_TIMESTAMP FROM [posts] AS t INNER JOIN inserted AS i ON t.[id] = i.[id];\n"+
+		"END", script.Statements[1])
+}
+
+// TestGenerateViewDescription checks that a view comment becomes an
+// MS_Description property, and that CREATE VIEW starts a batch of its own
+// as SQL Server requires.
+func TestGenerateViewDescription(t *testing.T) {
+	t.Parallel()
+	view := &core.View{
+		Name:       "active_customers",
+		Definition: "SELECT id, email FROM customers WHERE active = 1;",
+		Columns:    []string{"customer_id", "email"},
+		Comment:    "customers who can log in",
+	}
+	script, err := New().GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{
+		&diff.DropView{View: view}, &diff.AddView{View: view},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"DROP VIEW [active_customers]",
+		"CREATE VIEW [active_customers] ([customer_id], [email]) AS SELECT id, email FROM customers WHERE active = 1",
+		"EXEC sp_addextendedproperty @name = N'MS_Description', @value = N'customers who can log in', " +
+			"@level0type = N'SCHEMA', @level0name = N'dbo', @level1type = N'VIEW', @level1name = N'active_customers'",
+	}, script.Statements)
+	assert.Contains(t, script.String(), "DROP VIEW [active_customers];\nGO\n\nCREATE VIEW")
+}
+
+func TestGenerateViewFallbacks(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		view *core.View
+		want string
+	}{
+		{
+			name: "local check option",
+			view: &core.View{Name: "v", Definition: "SELECT id FROM customers", CheckOption: core.CheckOptionLocal},
+			want: "CREATE VIEW [v] AS SELECT id FROM customers WITH CHECK OPTION",
+		},
+		{
+			name: "materialized",
+			view: &core.View{Name: "v", Definition: "SELECT id FROM customers", Materialized: true},
+			want: "CREATE VIEW [v] AS SELECT id FROM customers",
+		},
+		{
+			name: "security invoker",
+			view: &core.View{Name: "v", Definition: "SELECT id FROM customers", Security: core.SecurityInvoker},
+			want: "CREATE VIEW [v] AS SELECT id FROM customers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			script, err := New().GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{&diff.AddView{View: tt.view}}})
+			require.NoError(t, err)
+			assert.Equal(t, []string{tt.want}, script.Statements)
+			assert.Len(t, script.Warnings, 1)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// constraintDefinition renders a named table constraint as it appears in
+// CREATE TABLE and in ALTER TABLE ... ADD clauses.
+func (r *renderer) constraintDefinition(t *core.Table, con *core.Constraint) string {
+	def := "CONSTRAINT " + quote(generate.ConstraintName(t, con)) + " "
+	cols := generate.QuoteList(con.Columns, quote)
+	switch con.Type {
+	case core.ConstraintPrimaryKey:
+		if options(t).MemoryOptimized || clusteredColumnstore(t) {
+			return def + "PRIMARY KEY NONCLUSTERED (" + cols + ")"
+		}
+		return def + "PRIMARY KEY (" + cols + ")"
+	case core.ConstraintUnique:
+		return def + "UNIQUE (" + cols + ")"
+	case core.ConstraintCheck:
+		return def + "CHECK (" + con.CheckExpression + ")"
+	default:
+		return def + foreignKey(con)
+	}
+}
+
+// foreignKey renders a FOREIGN KEY clause. SQL Server has no RESTRICT
+// action; NO ACTION behaves the same for non-deferred constraints.
+func foreignKey(con *core.Constraint) string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		generate.QuoteList(con.Columns, quote),
+		quote(con.ReferencedTable),
+		generate.QuoteList(con.ReferencedColumns, quote))
+	if a := refAction(con.OnDelete); a != "" {
+		def += " ON DELETE " + a
+	}
+	if a := refAction(con.OnUpdate); a != "" {
+		def += " ON UPDATE " + a
+	}
+	return def
+}
+
+func refAction(a core.ReferentialAction) string {
+	if a == core.RefActionRestrict {
+		return string(core.RefActionNoAction)
+	}
+	return string(a)
+}
+
+func (r *renderer) addConstraint(t *core.Table, con *core.Constraint) {
+	r.script.Add(r.addConstraintStatements(t, con)...)
+}
+
+// addConstraintStatements renders the statements that add con to an
+// existing table. A constraint that is not enforced is added without
+// checking the existing rows and then disabled.
+func (r *renderer) addConstraintStatements(t *core.Table, con *core.Constraint) []string {
+	if generate.Enforced(con) {
+		return []string{fmt.Sprintf("ALTER TABLE %s ADD %s", quote(t.Name), r.constraintDefinition(t, con))}
+	}
+	return []string{
+		fmt.Sprintf("ALTER TABLE %s WITH NOCHECK ADD %s", quote(t.Name), r.constraintDefinition(t, con)),
+		noCheck(t, con),
+	}
+}
+
+// noCheck renders the statement that disables a CHECK or FOREIGN KEY
+// constraint.
+func noCheck(t *core.Table, con *core.Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s NOCHECK CONSTRAINT %s", quote(t.Name), quote(generate.ConstraintName(t, con)))
+}
+
+// createIndex renders CREATE INDEX for idx together with its description.
+// Memory-optimized tables only accept indexes through ALTER TABLE.
+func (r *renderer) createIndex(t *core.Table, idx *core.Index) {
+	name := generate.IndexName(t, idx)
+	kind, ok := r.indexKind(t, idx, name)
+	if !ok {
+		return
+	}
+	if idx.Visibility == core.IndexInvisible {
+		r.script.Warnf("table %s: SQL Server does not support invisible indexes; %s is created visible", t.Name, name)
+	}
+
+	if idx.Where != "" && options(t).MemoryOptimized {
+		r.script.Warnf("table %s: memory-optimized tables do not support filtered indexes; the WHERE clause of %s was ignored", t.Name, name)
+	}
+
+	cols := r.indexColumns(t, idx)
+	switch {
+	case options(t).MemoryOptimized && idx.Unique:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE NONCLUSTERED (%s)", quote(t.Name), quote(name), cols))
+	case options(t).MemoryOptimized:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD INDEX %s NONCLUSTERED (%s)", quote(t.Name), quote(name), cols))
+	default:
+		stmt := "CREATE "
+		if idx.Unique {
+			stmt += "UNIQUE "
+		}
+		stmt += fmt.Sprintf("%sINDEX %s ON %s (%s)", kind, quote(name), quote(t.Name), cols)
+		if idx.Where != "" {
+			stmt += " WHERE " + idx.Where
+		}
+		r.script.Add(stmt)
+	}
+
+	if idx.Comment != "" {
+		r.script.Add(addDescription(idx.Comment, objectPath(t, "INDEX", name)))
+	}
+}
+
+// indexKind returns the keyword that precedes INDEX for the index type, or
+// false when the index is skipped.
+func (r *renderer) indexKind(t *core.Table, idx *core.Index, name string) (string, bool) {
+	switch idx.Type {
+	case "", core.IndexTypeBTree:
+		return "", true
+	case core.IndexTypeSpatial:
+		return "SPATIAL ", true
+	case core.IndexTypeFullText:
+		r.script.Warnf("table %s: FULLTEXT index %s was skipped; create it with CREATE FULLTEXT INDEX on a full-text catalog", t.Name, name)
+		return "", false
+	default:
+		r.script.Warnf("table %s: SQL Server does not support %s indexes; %s is created as a nonclustered index", t.Name, idx.Type, name)
+		return "", true
+	}
+}
+
+func (r *renderer) indexColumns(t *core.Table, idx *core.Index) string {
+	cols := make([]string, 0, len(idx.Columns))
+	for _, c := range idx.Columns {
+		if c.Expression != "" {
+			r.script.Warnf("table %s: SQL Server does not support functional key parts; (%s) was left out of index %s",
+				t.Name, c.Expression, generate.IndexName(t, idx))
+			continue
+		}
+		if c.Length > 0 {
+			r.script.Warnf("table %s: SQL Server does not support prefix indexes; the length of %s was ignored", t.Name, c.Name)
+		}
+		col := quote(c.Name)
+		if c.Order == core.SortDesc {
+			col += " DESC"
+		}
+		cols = append(cols, col)
+	}
+	return strings.Join(cols, ", ")
+}
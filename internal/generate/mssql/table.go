@@ -0,0 +1,195 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// columnstore is the DATA_COMPRESSION value that stores the table as a
+// clustered columnstore index.
+const columnstore = "COLUMNSTORE"
+
+// createTable renders CREATE TABLE for t together with the statements SQL
+// Server does not accept inline (descriptions, disabled constraints, the
+// clustered columnstore index, indexes, and ON UPDATE triggers).
+func (r *renderer) createTable(t *core.Table) {
+	r.script.Add(r.createTableStatement(t))
+	r.order.Created(t.Name)
+
+	for _, con := range t.Constraints {
+		if !generate.Enforced(con) && (con.Type != core.ConstraintForeignKey || r.order.Inline(t, con)) {
+			r.script.Add(noCheck(t, con))
+		}
+	}
+	if t.Comment != "" {
+		r.script.Add(addDescription(t.Comment, objectPath(t, "", "")))
+	}
+	for _, c := range t.Columns {
+		if c.Comment != "" {
+			r.script.Add(addDescription(c.Comment, objectPath(t, "COLUMN", c.Name)))
+		}
+	}
+	if clusteredColumnstore(t) {
+		r.script.Add(createColumnstore(t))
+	}
+	for _, idx := range t.Indexes {
+		r.createIndex(t, idx)
+	}
+	for _, c := range t.Columns {
+		r.createOnUpdateTrigger(t, c)
+	}
+}
+
+func (r *renderer) createTableStatement(t *core.Table) string {
+	var defs []string
+	for _, c := range t.Columns {
+		defs = append(defs, r.columnDefinition(t, c))
+	}
+	if temporal(t) {
+		defs = append(defs, periodDefinitions(t)...)
+	}
+	for _, con := range t.Constraints {
+		if con.Type == core.ConstraintForeignKey && !r.order.Inline(t, con) {
+			r.deferred = append(r.deferred, r.addConstraintStatements(t, con)...)
+			continue
+		}
+		defs = append(defs, r.constraintDefinition(t, con))
+	}
+
+	stmt := "CREATE TABLE " + quote(t.Name) + " (\n  " + strings.Join(defs, ",\n  ") + "\n)"
+	for _, opt := range r.tableOptions(t) {
+		stmt += " " + opt
+	}
+	return stmt
+}
+
+// tableOptions renders the clauses that follow the column list.
+func (r *renderer) tableOptions(t *core.Table) []string {
+	o := options(t)
+	var opts []string
+	if o.FileGroup != "" {
+		if o.MemoryOptimized {
+			r.script.Warnf("table %s: memory-optimized tables cannot be placed on filegroup %s; it was ignored", t.Name, o.FileGroup)
+		} else {
+			opts = append(opts, "ON "+quote(o.FileGroup))
+		}
+	}
+	if o.TextImageOn != "" {
+		opts = append(opts, "TEXTIMAGE_ON "+quote(o.TextImageOn))
+	}
+	if with := withOptions(t); len(with) > 0 {
+		opts = append(opts, "WITH ("+strings.Join(with, ", ")+")")
+	}
+	return opts
+}
+
+func withOptions(t *core.Table) []string {
+	o := options(t)
+	var with []string
+	if o.MemoryOptimized {
+		with = append(with, "MEMORY_OPTIMIZED = ON", "DURABILITY = SCHEMA_AND_DATA")
+	}
+	if c := strings.ToUpper(o.DataCompression); c != "" && c != columnstore {
+		with = append(with, "DATA_COMPRESSION = "+c)
+	}
+	if o.SystemVersioning {
+		with = append(with, systemVersioningOn(t))
+	}
+	switch {
+	case o.LedgerTable && o.SystemVersioning:
+		with = append(with, "LEDGER = ON")
+	case o.LedgerTable:
+		with = append(with, "LEDGER = ON (APPEND_ONLY = ON)")
+	}
+	return with
+}
+
+// clusteredColumnstore reports whether t is stored as a clustered
+// columnstore index. Its primary key is then nonclustered.
+func clusteredColumnstore(t *core.Table) bool {
+	return strings.EqualFold(options(t).DataCompression, columnstore)
+}
+
+func columnstoreName(t *core.Table) string {
+	return "cci_" + t.Name
+}
+
+func createColumnstore(t *core.Table) string {
+	return fmt.Sprintf("CREATE CLUSTERED COLUMNSTORE INDEX %s ON %s", quote(columnstoreName(t)), quote(t.Name))
+}
+
+// dropTable drops t. A temporal table must stop versioning first, and its
+// history table is dropped with it.
+func (r *renderer) dropTable(t *core.Table) {
+	if !temporal(t) {
+		r.script.Add("DROP TABLE " + quote(t.Name))
+		return
+	}
+	r.script.Add(
+		fmt.Sprintf("ALTER TABLE %s SET (SYSTEM_VERSIONING = OFF)", quote(t.Name)),
+		"DROP TABLE "+quote(t.Name),
+		"DROP TABLE "+historyTable(t),
+	)
+}
+
+func (r *renderer) addColumn(t *core.Table, c *core.Column) {
+	r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD %s", quote(t.Name), r.columnDefinition(t, c)))
+	if c.Comment != "" {
+		r.script.Add(addDescription(c.Comment, objectPath(t, "COLUMN", c.Name)))
+	}
+	r.createOnUpdateTrigger(t, c)
+}
+
+// dropColumn drops c after the objects that depend on it: its ON UPDATE
+// trigger, DEFAULT constraint, and enum CHECK constraint.
+func (r *renderer) dropColumn(t *core.Table, c *core.Column) {
+	r.dropOnUpdateTrigger(t, c)
+	if c.DefaultValue != nil {
+		r.script.Add(dropDefault(t, c))
+	}
+	if generate.IsEnum(c) {
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", quote(t.Name), quote(enumCheckName(t, c))))
+	}
+	r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", quote(t.Name), quote(c.Name)))
+}
+
+// alterTableOptions renders a table option change. Storage placement,
+// memory optimization, and ledger tables are fixed when the table is
+// created.
+func (r *renderer) alterTableOptions(c *diff.TableOptionChange) {
+	for _, f := range c.Fields {
+		switch f.Name {
+		case "sqlserver.data_compression":
+			r.alterCompression(c.Old, c.New)
+		case "sqlserver.system_versioning":
+			if options(c.New).SystemVersioning {
+				r.enableSystemVersioning(c.New)
+			} else {
+				r.disableSystemVersioning(c.New)
+			}
+		case "sqlserver.file_group", "sqlserver.textimage_on", "sqlserver.memory_optimized", "sqlserver.ledger_table":
+			r.script.Warnf("table %s: %s cannot be changed on an existing table; recreate the table", c.New.Name, f.Name)
+		}
+	}
+}
+
+// alterCompression switches the table between row or page compression and
+// a clustered columnstore index.
+func (r *renderer) alterCompression(from, to *core.Table) {
+	if clusteredColumnstore(from) {
+		r.script.Add(fmt.Sprintf("DROP INDEX %s ON %s", quote(columnstoreName(from)), quote(from.Name)))
+	}
+	if clusteredColumnstore(to) {
+		r.script.Add(createColumnstore(to))
+		return
+	}
+	compression := strings.ToUpper(options(to).DataCompression)
+	if compression == "" {
+		compression = "NONE"
+	}
+	r.script.Add(fmt.Sprintf("ALTER TABLE %s REBUILD WITH (DATA_COMPRESSION = %s)", quote(to.Name), compression))
+}
@@ -0,0 +1,75 @@
+package mssql
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+)
+
+// System-versioned (temporal) tables need a pair of period columns and a
+// history table. The generator adds hidden period columns with fixed names
+// and keeps the history in {table}_history. Updatable ledger tables manage
+// their period columns themselves.
+
+const (
+	periodStart = "valid_from"
+	periodEnd   = "valid_to"
+)
+
+func options(t *core.Table) *core.SQLServerTableOptions {
+	if t.Options.SQLServer == nil {
+		return &core.SQLServerTableOptions{}
+	}
+	return t.Options.SQLServer
+}
+
+// temporal reports whether t is a system-versioned table whose period
+// columns are rendered by the generator.
+func temporal(t *core.Table) bool {
+	o := options(t)
+	return o.SystemVersioning && !o.LedgerTable
+}
+
+func historyTable(t *core.Table) string {
+	return quote(defaultSchema) + "." + quote(t.Name+"_history")
+}
+
+// periodDefinitions renders the period columns and the PERIOD FOR
+// SYSTEM_TIME clause. The columns have defaults so that they can be added
+// to tables that already contain rows.
+func periodDefinitions(t *core.Table) []string {
+	return []string{
+		fmt.Sprintf("%s DATETIME2 GENERATED ALWAYS AS ROW START HIDDEN CONSTRAINT %s DEFAULT SYSUTCDATETIME()",
+			quote(periodStart), quote(defaultName(t, periodStart))),
+		fmt.Sprintf("%s DATETIME2 GENERATED ALWAYS AS ROW END HIDDEN CONSTRAINT %s DEFAULT CONVERT(DATETIME2, '9999-12-31 23:59:59.9999999')",
+			quote(periodEnd), quote(defaultName(t, periodEnd))),
+		fmt.Sprintf("PERIOD FOR SYSTEM_TIME (%s, %s)", quote(periodStart), quote(periodEnd)),
+	}
+}
+
+func systemVersioningOn(t *core.Table) string {
+	return "SYSTEM_VERSIONING = ON (HISTORY_TABLE = " + historyTable(t) + ")"
+}
+
+// enableSystemVersioning turns an existing table into a temporal table.
+func (r *renderer) enableSystemVersioning(t *core.Table) {
+	defs := periodDefinitions(t)
+	r.script.Add(
+		fmt.Sprintf("ALTER TABLE %s ADD %s, %s, %s", quote(t.Name), defs[0], defs[1], defs[2]),
+		fmt.Sprintf("ALTER TABLE %s SET (%s)", quote(t.Name), systemVersioningOn(t)),
+	)
+}
+
+// disableSystemVersioning turns a temporal table back into a regular table.
+// The history table is kept.
+func (r *renderer) disableSystemVersioning(t *core.Table) {
+	name := quote(t.Name)
+	r.script.Add(
+		fmt.Sprintf("ALTER TABLE %s SET (SYSTEM_VERSIONING = OFF)", name),
+		fmt.Sprintf("ALTER TABLE %s DROP PERIOD FOR SYSTEM_TIME", name),
+		fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s, %s", name,
+			quote(defaultName(t, periodStart)), quote(defaultName(t, periodEnd))),
+		fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s, %s", name, quote(periodStart), quote(periodEnd)),
+	)
+	r.script.Warnf("table %s: system versioning was disabled; the history table %s was kept", t.Name, historyTable(t))
+}
@@ -0,0 +1,46 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// SQL Server has no ON UPDATE column clause. A column with OnUpdate gets an
+// AFTER UPDATE trigger that sets the column on the updated rows unless the
+// statement assigned it. The rows are matched on the primary key.
+
+func onUpdateName(t *core.Table, c *core.Column) string {
+	return t.Name + "_" + c.Name + "_on_update"
+}
+
+// createOnUpdateTrigger renders the trigger that emulates ON UPDATE for c.
+func (r *renderer) createOnUpdateTrigger(t *core.Table, c *core.Column) {
+	if c.OnUpdate == nil {
+		return
+	}
+	pk := t.PrimaryKey()
+	if pk == nil {
+		r.script.Warnf("column %s.%s: ON UPDATE requires a primary key to match updated rows; it was ignored", t.Name, c.Name)
+		return
+	}
+	match := make([]string, len(pk.Columns))
+	for i, col := range pk.Columns {
+		match[i] = fmt.Sprintf("t.%s = i.%s", quote(col), quote(col))
+	}
+	r.script.Add(fmt.Sprintf("CREATE TRIGGER %s ON %s AFTER UPDATE AS\n"+
+		"BEGIN\n"+
+		"  SET NOCOUNT ON;\n"+
+		"  IF NOT UPDATE(%s)\n"+
+		"    UPDATE t SET %s = %s FROM %s AS t INNER JOIN inserted AS i ON %s;\n"+
+		"END",
+		quote(onUpdateName(t, c)), quote(t.Name), quote(c.Name),
+		quote(c.Name), defaultValue(c, *c.OnUpdate), quote(t.Name), strings.Join(match, " AND ")))
+}
+
+func (r *renderer) dropOnUpdateTrigger(t *core.Table, c *core.Column) {
+	if c.OnUpdate != nil {
+		r.script.Add("DROP TRIGGER IF EXISTS " + quote(onUpdateName(t, c)))
+	}
+}
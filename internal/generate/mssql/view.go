@@ -0,0 +1,51 @@
+package mssql
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// viewChange renders the statements of a view change. A materialized view
+// was created as a regular view, so it is dropped with DROP VIEW.
+func (r *renderer) viewChange(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddView:
+		r.createView(c.View)
+	case *diff.DropView:
+		r.script.Add("DROP VIEW " + quote(c.View.Name))
+	default:
+		return fmt.Errorf("mssql: unsupported change %T", c)
+	}
+	return nil
+}
+
+// createView renders CREATE VIEW. SQL Server only has the cascaded check
+// option, and views always run with ownership chaining. The counterpart of
+// a materialized view is an indexed view, which requires SCHEMABINDING and
+// a unique clustered index that the schema cannot describe, so a
+// materialized view is created as a regular view.
+func (r *renderer) createView(v *core.View) {
+	if v.Materialized {
+		r.script.Warnf("view %s: materialized views are created as regular views; index the view WITH SCHEMABINDING to materialize it", v.Name)
+	}
+	if v.Security == core.SecurityInvoker {
+		r.script.Warnf("view %s: SQL Server does not support SECURITY INVOKER; it was ignored", v.Name)
+	}
+
+	stmt := fmt.Sprintf("CREATE VIEW %s%s AS %s", quote(v.Name), generate.ViewColumns(v, quote), generate.ViewDefinition(v))
+	switch v.CheckOption {
+	case core.CheckOptionLocal:
+		r.script.Warnf("view %s: SQL Server only supports cascaded check options; LOCAL is rendered as WITH CHECK OPTION", v.Name)
+		stmt += " WITH CHECK OPTION"
+	case core.CheckOptionCascaded:
+		stmt += " WITH CHECK OPTION"
+	}
+	r.script.Add(stmt)
+
+	if v.Comment != "" {
+		r.script.Add(addDescription(v.Comment, viewPath(v)))
+	}
+}
@@ -0,0 +1,219 @@
+package mysql
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+var typeMapper = generate.TypeMapper{
+	Types: map[string]string{
+		"varchar":   "VARCHAR(%s)",
+		"char":      "CHAR(%s)",
+		"text":      "TEXT",
+		"smallint":  "SMALLINT",
+		"int":       "INT",
+		"integer":   "INT",
+		"bigint":    "BIGINT",
+		"decimal":   "DECIMAL(%s)",
+		"numeric":   "DECIMAL(%s)",
+		"float":     "FLOAT",
+		"double":    "DOUBLE",
+		"boolean":   "TINYINT(1)",
+		"bool":      "TINYINT(1)",
+		"date":      "DATE",
+		"time":      "TIME",
+		"timestamp": "TIMESTAMP",
+		"datetime":  "DATETIME",
+		"json":      "JSON",
+		"uuid":      "CHAR(36)",
+		"blob":      "BLOB",
+		"binary":    "BINARY(%s)",
+		"varbinary": "VARBINARY(%s)",
+	},
+	DefaultArgs: map[string]string{
+		"varchar":   "255",
+		"varbinary": "255",
+	},
+	Fallback: map[core.DataType]string{
+		core.DataTypeString:   "VARCHAR(255)",
+		core.DataTypeInt:      "INT",
+		core.DataTypeFloat:    "DOUBLE",
+		core.DataTypeBoolean:  "TINYINT(1)",
+		core.DataTypeDatetime: "DATETIME",
+		core.DataTypeJSON:     "JSON",
+		core.DataTypeUUID:     "CHAR(36)",
+		core.DataTypeBinary:   "BLOB",
+	},
+}
+
+// ColumnType returns the MySQL type for a column.
+func ColumnType(c *core.Column) string {
+	if generate.IsEnum(c) {
+		return "ENUM(" + generate.QuoteList(c.EnumValues, quoteString) + ")"
+	}
+	return typeMapper.ColumnType(c)
+}
+
+// columnDefinition renders a column as it appears in CREATE TABLE and in
+// ADD/MODIFY COLUMN clauses.
+func (r *renderer) columnDefinition(c *core.Column) string {
+	parts := []string{quote(c.Name), ColumnType(c)}
+	parts = append(parts, typeClauses(c)...)
+	parts = append(parts, r.attributeClauses(c)...)
+	parts = append(parts, r.columnOptions(c)...)
+	return strings.Join(parts, " ")
+}
+
+// typeClauses renders the clauses that belong to the column type: character
+// set, collation, generation expression, and nullability.
+func typeClauses(c *core.Column) []string {
+	var parts []string
+	if c.Charset != "" {
+		parts = append(parts, "CHARACTER SET "+c.Charset)
+	}
+	if c.Collate != "" {
+		parts = append(parts, "COLLATE "+c.Collate)
+	}
+	if c.IsGenerated {
+		parts = append(parts, generatedClause(c))
+	}
+	if c.Nullable {
+		return append(parts, "NULL")
+	}
+	return append(parts, "NOT NULL")
+}
+
+func (r *renderer) attributeClauses(c *core.Column) []string {
+	var parts []string
+	if c.DefaultValue != nil && !c.IsGenerated {
+		parts = append(parts, "DEFAULT "+defaultValue(c, *c.DefaultValue))
+	}
+	if c.OnUpdate != nil {
+		parts = append(parts, "ON UPDATE "+*c.OnUpdate)
+	}
+	if clause := r.flavor.autoIncrement(c); clause != "" {
+		parts = append(parts, clause)
+	}
+	if c.Invisible {
+		parts = append(parts, "INVISIBLE")
+	}
+	if c.Comment != "" {
+		parts = append(parts, "COMMENT "+quoteString(c.Comment))
+	}
+	return parts
+}
+
+func generatedClause(c *core.Column) string {
+	storage := c.GenerationStorage
+	if storage == "" {
+		storage = core.GenerationVirtual
+	}
+	return "GENERATED ALWAYS AS (" + c.GenerationExpression + ") " + string(storage)
+}
+
+// columnOption renders one MySQL column option clause. key is the diff
+// field name of the option.
+type columnOption struct {
+	key    string
+	render func(o *core.MySQLColumnOptions) string
+}
+
+var columnOptionClauses = []columnOption{
+	{"mysql.column_format", func(o *core.MySQLColumnOptions) string { return keyword("COLUMN_FORMAT", o.ColumnFormat) }},
+	{"mysql.primary_engine_attribute", func(o *core.MySQLColumnOptions) string {
+		return attribute("ENGINE_ATTRIBUTE", o.PrimaryEngineAttribute)
+	}},
+	{"mysql.secondary_engine_attribute", func(o *core.MySQLColumnOptions) string {
+		return attribute("SECONDARY_ENGINE_ATTRIBUTE", o.SecondaryEngineAttribute)
+	}},
+	{"mysql.srid", func(o *core.MySQLColumnOptions) string {
+		if o.SRID == nil {
+			return ""
+		}
+		return "SRID " + strconv.FormatUint(uint64(*o.SRID), 10)
+	}},
+	{"mysql.storage", func(o *core.MySQLColumnOptions) string { return keyword("STORAGE", o.Storage) }},
+}
+
+// columnOptions renders the MySQL column options of c that the server
+// supports.
+func (r *renderer) columnOptions(c *core.Column) []string {
+	if c.MySQL == nil {
+		return nil
+	}
+	var parts []string
+	for _, opt := range columnOptionClauses {
+		if !r.flavor.supportsOption(opt.key) {
+			continue
+		}
+		if clause := opt.render(c.MySQL); clause != "" {
+			parts = append(parts, clause)
+		}
+	}
+	return parts
+}
+
+func keyword(name, v string) string {
+	if v == "" {
+		return ""
+	}
+	return name + " " + strings.ToUpper(v)
+}
+
+func attribute(name, v string) string {
+	if v == "" {
+		return ""
+	}
+	return name + " " + quoteString(v)
+}
+
+// defaultValue renders a DEFAULT value. MySQL only accepts expression
+// defaults in parentheses, and TEXT, BLOB, JSON, and GEOMETRY columns
+// accept no literal defaults at all, so those are wrapped as well.
+func defaultValue(c *core.Column, v string) string {
+	v = strings.TrimSpace(v)
+	var out string
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		out = quoteString(v)
+	case generate.DefaultBoolean:
+		if generate.BoolDefault(v) {
+			return "1"
+		}
+		return "0"
+	case generate.DefaultNull, generate.DefaultTimestamp:
+		return v
+	case generate.DefaultExpression:
+		if strings.HasPrefix(v, "(") {
+			return v
+		}
+		return "(" + v + ")"
+	default:
+		out = v
+	}
+	if expressionOnlyDefault(c) {
+		return "(" + out + ")"
+	}
+	return out
+}
+
+var expressionOnlyTypes = []string{
+	"text", "tinytext", "mediumtext", "longtext",
+	"blob", "tinyblob", "mediumblob", "longblob",
+	"json", "geometry",
+}
+
+func expressionOnlyDefault(c *core.Column) bool {
+	return c.Type == core.DataTypeJSON || slices.Contains(expressionOnlyTypes, generate.TypeBase(c))
+}
+
+// quoteString quotes s as a MySQL string literal. Backslashes are escaped
+// because MySQL treats them as escape characters unless NO_BACKSLASH_ESCAPES
+// is set.
+func quoteString(s string) string {
+	return generate.QuoteString(strings.ReplaceAll(s, `\`, `\\`))
+}
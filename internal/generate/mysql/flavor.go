@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"smf/internal/core"
+	"smf/internal/diff"
+)
+
+// flavor adapts the MySQL renderer to a server of the MySQL family. The
+// servers share the MySQL syntax and option groups, and add option groups
+// of their own on top.
+type flavor interface {
+	// sequence reports whether t is declared as a SEQUENCE object instead
+	// of a table.
+	sequence(t *core.Table) bool
+	// tableOptions renders the table options of the flavor's own group.
+	tableOptions(r *renderer, t *core.Table) []string
+	// changedTableOptions renders the clauses for the options of the
+	// flavor's own group that are listed in c.
+	changedTableOptions(r *renderer, c *diff.TableOptionChange) []string
+	// autoIncrement renders the clause that generates the values of c, or
+	// returns "" when the server does not generate them.
+	autoIncrement(c *core.Column) string
+	// supportedIndex reports whether the server supports idx, recording a
+	// warning when it does not.
+	supportedIndex(r *renderer, t *core.Table, idx *core.Index) bool
+	// alterType returns the column definition that a type change from
+	// "from" to "to" is rendered with.
+	alterType(r *renderer, t *core.Table, from, to *core.Column) *core.Column
+	// supportsOption reports whether the server accepts the MySQL table or
+	// column option with the given diff field name.
+	supportsOption(key string) bool
+	// invisibleIndex returns the keyword that hides an index from the
+	// optimizer.
+	invisibleIndex() string
+}
+
+// mysqlFlavor is the flavor of MySQL itself. Other flavors embed it and
+// override the hooks they need.
+type mysqlFlavor struct{}
+
+func (mysqlFlavor) sequence(*core.Table) bool { return false }
+
+func (mysqlFlavor) tableOptions(*renderer, *core.Table) []string { return nil }
+
+func (mysqlFlavor) changedTableOptions(*renderer, *diff.TableOptionChange) []string { return nil }
+
+func (mysqlFlavor) autoIncrement(c *core.Column) string {
+	if !c.AutoIncrement {
+		return ""
+	}
+	return "AUTO_INCREMENT"
+}
+
+func (mysqlFlavor) supportedIndex(r *renderer, t *core.Table, idx *core.Index) bool {
+	return r.supportedIndex(t, idx)
+}
+
+func (mysqlFlavor) alterType(_ *renderer, _ *core.Table, _, to *core.Column) *core.Column { return to }
+
+func (mysqlFlavor) supportsOption(string) bool { return true }
+
+func (mysqlFlavor) invisibleIndex() string { return "INVISIBLE" }
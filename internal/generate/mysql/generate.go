@@ -0,0 +1,150 @@
+// Package mysql contains the DDL generator for MySQL. It renders a complete
+// core.Database as CREATE TABLE statements and a diff.ChangeSet as ALTER
+// TABLE statements, honoring the MySQL column and table option groups.
+// Servers of the MySQL family, such as TiDB and MariaDB, are rendered by the same
+// generator with a flavor that adds their own options.
+package mysql
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+func init() {
+	generate.Register(core.DialectMySQL, New)
+}
+
+// Generator renders MySQL DDL.
+type Generator struct {
+	flavor flavor
+}
+
+func New() generate.Generator {
+	return &Generator{flavor: mysqlFlavor{}}
+}
+
+// Generate renders a CREATE TABLE statement for every table and a CREATE
+// VIEW statement for every view in db, ordered so that referenced tables
+// and views are created first.
+func (g *Generator) Generate(db *core.Database) (*generate.Script, error) {
+	return g.GenerateChanges(diff.Databases(nil, db))
+}
+
+// GenerateChanges renders the statements that apply cs.
+func (g *Generator) GenerateChanges(cs *diff.ChangeSet) (*generate.Script, error) {
+	r := &renderer{
+		script:   &generate.Script{},
+		order:    generate.NewCreateOrder(cs),
+		flavor:   g.flavor,
+		modified: make(map[string]bool),
+	}
+	for _, c := range cs.Changes {
+		if err := r.change(c); err != nil {
+			return nil, err
+		}
+	}
+	r.script.Add(r.deferred...)
+	return r.script, nil
+}
+
+// renderer holds the state of a single GenerateChanges call.
+type renderer struct {
+	script *generate.Script
+	order  *generate.CreateOrder
+	flavor flavor
+	// deferred holds foreign keys of new tables that reference a table
+	// created later in the script.
+	deferred []string
+	// modified tracks columns that already received a MODIFY COLUMN
+	// statement, since one statement covers every attribute change.
+	modified map[string]bool
+}
+
+func (r *renderer) change(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddTable, *diff.DropTable, *diff.ChangeTableComment, *diff.TableOptionChange:
+		r.tableChange(c)
+	case *diff.AddColumn:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s%s",
+			quote(c.Table.Name), r.columnDefinition(c.Column), position(c.After)))
+	case *diff.DropColumn:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", quote(c.Table.Name), quote(c.Column.Name)))
+	case *diff.AlterColumnType:
+		r.modifyColumn(c.Table, r.flavor.alterType(r, c.Table, c.Old, c.New))
+	case *diff.AlterNullability:
+		r.modifyColumn(c.Table, c.New)
+	case *diff.ChangeDefault:
+		r.modifyColumn(c.Table, c.New)
+	case *diff.AlterColumn:
+		r.modifyColumn(c.Table, c.New)
+	case *diff.AddConstraint, *diff.DropConstraint, *diff.AddIndex, *diff.DropIndex:
+		r.keyChange(c)
+	default:
+		return r.viewChange(c)
+	}
+	return nil
+}
+
+func (r *renderer) tableChange(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AddTable:
+		if r.flavor.sequence(c.Table) {
+			r.script.Add("CREATE SEQUENCE " + quote(c.Table.Name))
+		} else {
+			r.script.Add(r.createTable(c.Table))
+		}
+	case *diff.DropTable:
+		if r.flavor.sequence(c.Table) {
+			r.script.Add("DROP SEQUENCE " + quote(c.Table.Name))
+		} else {
+			r.script.Add("DROP TABLE " + quote(c.Table.Name))
+		}
+	case *diff.ChangeTableComment:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s COMMENT = %s", quote(c.Table.Name), quoteString(c.New)))
+	case *diff.TableOptionChange:
+		if clauses := r.changedTableOptions(c); len(clauses) > 0 {
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s %s", quote(c.New.Name), joinOptions(clauses)))
+		}
+	}
+}
+
+func (r *renderer) keyChange(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AddConstraint:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD %s", quote(c.Table.Name), r.constraintDefinition(c.Constraint)))
+	case *diff.DropConstraint:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s %s", quote(c.Table.Name), dropConstraint(c.Table, c.Constraint)))
+	case *diff.AddIndex:
+		if stmt := r.createIndex(c.Table, c.Index); stmt != "" {
+			r.script.Add(stmt)
+		}
+	case *diff.DropIndex:
+		r.script.Add(fmt.Sprintf("DROP INDEX %s ON %s",
+			quote(generate.IndexName(c.Table, c.Index)), quote(c.Table.Name)))
+	}
+}
+
+// modifyColumn emits a single MODIFY COLUMN with the full target definition,
+// which MySQL requires for any column attribute change.
+func (r *renderer) modifyColumn(t *core.Table, c *core.Column) {
+	key := t.Name + "." + c.Name
+	if r.modified[key] {
+		return
+	}
+	r.modified[key] = true
+	r.script.Add(fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", quote(t.Name), r.columnDefinition(c)))
+}
+
+func position(after string) string {
+	if after == "" {
+		return " FIRST"
+	}
+	return " AFTER " + quote(after)
+}
+
+func quote(name string) string {
+	return core.QuoteMySQLIdentifier(name)
+}
@@ -0,0 +1,464 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+	"smf/internal/pars/toml"
+)
+
+func parse(t *testing.T, schema string) *core.Database {
+	t.Helper()
+	db, err := toml.NewParser().Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+	return db
+}
+
+func TestGenerateCreateTable(t *testing.T) {
+	t.Parallel()
+	db := parse(t, `
+[database]
+name = "app"
+dialect = "mysql"
+
+[[tables]]
+name = "users"
+comment = "registered users"
+
+  [tables.options]
+  tablespace = "ts1"
+
+  [tables.options.mysql]
+  engine = "InnoDB"
+  charset = "utf8mb4"
+  row_format = "dynamic"
+  compression = "LZ4"
+  secondary_engine = "RAPID"
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+  auto_increment = true
+
+  [[tables.columns]]
+  name = "email"
+  type = "varchar(320)"
+  unique = true
+  collate = "utf8mb4_bin"
+
+  [[tables.columns]]
+  name = "plan"
+  type = "enum"
+  values = ["free", "pro"]
+  default = "free"
+
+  [[tables.columns]]
+  name = "updated_at"
+  type = "timestamp"
+  default = "CURRENT_TIMESTAMP"
+  on_update = "CURRENT_TIMESTAMP"
+  invisible = true
+`)
+
+	script, err := New().Generate(db)
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 1)
+	assert.Equal(t, "CREATE TABLE `users` (\n"+
+		"  `id` BIGINT NOT NULL AUTO_INCREMENT,\n"+
+		"  `email` VARCHAR(320) COLLATE utf8mb4_bin NOT NULL,\n"+
+		"  `plan` ENUM('free', 'pro') NOT NULL DEFAULT 'free',\n"+
+		"  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP INVISIBLE,\n"+
+		"  PRIMARY KEY (`id`),\n"+
+		"  CONSTRAINT `uq_users_email` UNIQUE (`email`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 ROW_FORMAT=DYNAMIC COMPRESSION='LZ4' SECONDARY_ENGINE=RAPID "+
+		"TABLESPACE `ts1` COMMENT='registered users'",
+		script.Statements[0])
+}
+
+func TestGenerateDependencyOrder(t *testing.T) {
+	t.Parallel()
+	db := parse(t, `
+[database]
+name = "app"
+dialect = "mysql"
+
+[[tables]]
+name = "orders"
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "user_id"
+  type = "int"
+  references = "users.id"
+  on_delete = "CASCADE"
+
+[[tables]]
+name = "users"
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+`)
+
+	script, err := New().Generate(db)
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 2)
+	assert.True(t, strings.HasPrefix(script.Statements[0], "CREATE TABLE `users`"))
+	assert.Contains(t, script.Statements[1], "CONSTRAINT `fk_orders_users` FOREIGN KEY (`user_id`) "+
+		"REFERENCES `users` (`id`) ON DELETE CASCADE")
+}
+
+func TestGenerateForeignKeyCycle(t *testing.T) {
+	t.Parallel()
+	a := &core.Table{
+		Name:    "a",
+		Columns: []*core.Column{{Name: "b_id", PortableType: "int"}},
+		Constraints: []*core.Constraint{{
+			Name: "fk_a_b", Type: core.ConstraintForeignKey, Columns: []string{"b_id"},
+			ReferencedTable: "b", ReferencedColumns: []string{"id"},
+		}},
+	}
+	b := &core.Table{
+		Name:    "b",
+		Columns: []*core.Column{{Name: "a_id", PortableType: "int"}},
+		Constraints: []*core.Constraint{{
+			Name: "fk_b_a", Type: core.ConstraintForeignKey, Columns: []string{"a_id"},
+			ReferencedTable: "a", ReferencedColumns: []string{"id"},
+		}},
+	}
+
+	script, err := New().Generate(&core.Database{Tables: []*core.Table{a, b}})
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 3)
+	assert.NotContains(t, script.Statements[0], "FOREIGN KEY")
+	assert.Contains(t, script.Statements[1], "CONSTRAINT `fk_b_a` FOREIGN KEY")
+	assert.Equal(t, "ALTER TABLE `a` ADD CONSTRAINT `fk_a_b` FOREIGN KEY (`b_id`) REFERENCES `b` (`id`)",
+		script.Statements[2])
+}
+
+func TestGenerateColumnDefinitions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		col  *core.Column
+		want string
+	}{
+		{
+			name: "generated stored",
+			col: &core.Column{
+				Name: "total", PortableType: "decimal(10,2)", Nullable: true,
+				IsGenerated: true, GenerationExpression: "price * qty", GenerationStorage: core.GenerationStored,
+			},
+			want: "`total` DECIMAL(10,2) GENERATED ALWAYS AS (price * qty) STORED NULL",
+		},
+		{
+			name: "charset and comment",
+			col: &core.Column{
+				Name: "bio", PortableType: "text", Nullable: true, Charset: "latin1",
+				Comment: `it's a "bio"`, DefaultValue: new("none"),
+			},
+			want: "`bio` TEXT CHARACTER SET latin1 NULL DEFAULT ('none') COMMENT 'it''s a \"bio\"'",
+		},
+		{
+			name: "boolean and options",
+			col: &core.Column{
+				Name: "active", PortableType: "boolean", DefaultValue: new("true"),
+				MySQL: &core.MySQLColumnOptions{ColumnFormat: "fixed", Storage: "disk"},
+			},
+			want: "`active` TINYINT(1) NOT NULL DEFAULT 1 COLUMN_FORMAT FIXED STORAGE DISK",
+		},
+		{
+			name: "expression default",
+			col:  &core.Column{Name: "uid", PortableType: "uuid", DefaultValue: new("uuid()")},
+			want: "`uid` CHAR(36) NOT NULL DEFAULT (uuid())",
+		},
+		{
+			name: "raw type",
+			col:  &core.Column{Name: "flags", RawType: "SET('a','b')", Nullable: true},
+			want: "`flags` SET('a','b') NULL",
+		},
+		{
+			name: "spatial reference system",
+			col: &core.Column{
+				Name: "location", RawType: "POINT", MySQL: &core.MySQLColumnOptions{SRID: new(uint32(4326))},
+			},
+			want: "`location` POINT NOT NULL SRID 4326",
+		},
+		{
+			name: "injected timestamp",
+			col: &core.Column{
+				Name: "updated_at", Type: core.DataTypeDatetime,
+				DefaultValue: new("CURRENT_TIMESTAMP"), OnUpdate: new("CURRENT_TIMESTAMP"),
+			},
+			want: "`updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := &renderer{script: &generate.Script{}, flavor: mysqlFlavor{}}
+			assert.Equal(t, tt.want, r.columnDefinition(tt.col))
+		})
+	}
+}
+
+func TestGenerateChanges(t *testing.T) {
+	t.Parallel()
+	from := parse(t, `
+[database]
+name = "app"
+dialect = "mysql"
+
+[[tables]]
+name = "users"
+
+  [tables.options.mysql]
+  engine = "InnoDB"
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "name"
+  type = "varchar(64)"
+
+  [[tables.columns]]
+  name = "legacy"
+  type = "int"
+
+  [[tables.indexes]]
+  name = "idx_name"
+  columns = ["name"]
+`)
+	to := parse(t, `
+[database]
+name = "app"
+dialect = "mysql"
+
+[[tables]]
+name = "users"
+
+  [tables.options.mysql]
+  engine = "InnoDB"
+  row_format = "COMPRESSED"
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "name"
+  type = "varchar(128)"
+  nullable = true
+  default = "anon"
+
+  [[tables.columns]]
+  name = "age"
+  type = "int"
+  check = "age >= 0"
+
+  [[tables.indexes]]
+  name = "idx_name"
+  visibility = "INVISIBLE"
+
+    [[tables.indexes.column_defs]]
+    name = "name"
+    length = 10
+    order = "DESC"
+`)
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"DROP INDEX `idx_name` ON `users`",
+		"ALTER TABLE `users` ROW_FORMAT=COMPRESSED",
+		"ALTER TABLE `users` DROP COLUMN `legacy`",
+		"ALTER TABLE `users` ADD COLUMN `age` INT NOT NULL AFTER `name`",
+		"ALTER TABLE `users` MODIFY COLUMN `name` VARCHAR(128) NULL DEFAULT 'anon'",
+		"ALTER TABLE `users` ADD CONSTRAINT `chk_users_age` CHECK (age >= 0)",
+		"CREATE INDEX `idx_name` ON `users` (`name`(10) DESC) USING BTREE INVISIBLE",
+	}, script.Statements)
+}
+
+func TestGenerateDropConstraints(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "users"}
+	cs := &diff.ChangeSet{Changes: []diff.Change{
+		&diff.DropConstraint{Table: tbl, Constraint: &core.Constraint{Type: core.ConstraintPrimaryKey}},
+		&diff.DropConstraint{Table: tbl, Constraint: &core.Constraint{Type: core.ConstraintUnique, Columns: []string{"email"}}},
+		&diff.DropConstraint{Table: tbl, Constraint: &core.Constraint{Name: "chk_age", Type: core.ConstraintCheck}},
+		&diff.DropConstraint{Table: tbl, Constraint: &core.Constraint{Name: "fk_x", Type: core.ConstraintForeignKey}},
+		&diff.AddIndex{Table: tbl, Index: &core.Index{Name: "g", Type: core.IndexTypeGIN}},
+	}}
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"ALTER TABLE `users` DROP PRIMARY KEY",
+		"ALTER TABLE `users` DROP INDEX `uq_users_email`",
+		"ALTER TABLE `users` DROP CHECK `chk_age`",
+		"ALTER TABLE `users` DROP FOREIGN KEY `fk_x`",
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 1)
+}
+
+func TestGeneratorRegistered(t *testing.T) {
+	t.Parallel()
+	g, err := generate.NewGenerator(core.DialectMySQL)
+	require.NoError(t, err)
+	assert.IsType(t, &Generator{}, g)
+}
+
+func TestGenerateFunctionalKeyPart(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "users"}
+	cs := &diff.ChangeSet{Changes: []diff.Change{
+		&diff.AddIndex{Table: tbl, Index: &core.Index{Name: "idx_users_email", Type: core.IndexTypeBTree,
+			Columns: []core.ColumnIndex{{Name: "tenant_id"}, {Expression: "lower(email)", Order: core.SortDesc}}}},
+	}}
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"CREATE INDEX `idx_users_email` ON `users` (`tenant_id`, (lower(email)) DESC) USING BTREE",
+	}, script.Statements)
+}
+
+func TestGenerateFullTextParser(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "posts"}
+	cs := &diff.ChangeSet{Changes: []diff.Change{
+		&diff.AddIndex{Table: tbl, Index: &core.Index{Name: "ft_posts_body", Type: core.IndexTypeFullText,
+			Parser: "ngram", Columns: []core.ColumnIndex{{Name: "body"}}}},
+	}}
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CREATE FULLTEXT INDEX `ft_posts_body` ON `posts` (`body`) WITH PARSER `ngram`"}, script.Statements)
+}
+
+func TestGeneratePartialIndexWarns(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "users"}
+	cs := &diff.ChangeSet{Changes: []diff.Change{
+		&diff.AddIndex{Table: tbl, Index: &core.Index{Name: "idx_users_email", Type: core.IndexTypeBTree,
+			Where: "deleted_at IS NULL", Columns: []core.ColumnIndex{{Name: "email"}}}},
+	}}
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CREATE INDEX `idx_users_email` ON `users` (`email`) USING BTREE"}, script.Statements)
+	require.Len(t, script.Warnings, 1)
+	assert.Contains(t, script.Warnings[0], "partial indexes")
+}
+
+func TestGeneratePartitionBy(t *testing.T) {
+	t.Parallel()
+	db := parse(t, `
+[database]
+name = "app"
+dialect = "mysql"
+
+[[tables]]
+name = "events"
+comment = "audit trail"
+
+  [tables.options.mysql]
+  engine = "InnoDB"
+  partition_by = "RANGE (id) (PARTITION p0 VALUES LESS THAN (1000), PARTITION p1 VALUES LESS THAN MAXVALUE)"
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+`)
+
+	script, err := New().Generate(db)
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 1)
+	assert.True(t, strings.HasSuffix(script.Statements[0], ") ENGINE=InnoDB COMMENT='audit trail' "+
+		"PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (1000), PARTITION p1 VALUES LESS THAN MAXVALUE)"),
+		script.Statements[0])
+}
+
+func TestGenerateViews(t *testing.T) {
+	t.Parallel()
+	db := parse(t, ordersSchema+`
+[[views]]
+name = "large_order_ids"
+definition = "SELECT order_id FROM large_orders"
+
+[[views]]
+name = "large_orders"
+definition = "SELECT id, total FROM orders WHERE total > 1000;"
+columns = ["order_id", "total"]
+security = "INVOKER"
+check_option = "CASCADED"
+
+[[views]]
+name = "order_stats"
+definition = "SELECT count(*) AS n FROM orders"
+materialized = true
+comment = "order count"
+`)
+
+	script, err := New().Generate(db)
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 4)
+	assert.Equal(t, "CREATE SQL SECURITY INVOKER VIEW `large_orders` (`order_id`, `total`) AS "+
+		"SELECT id, total FROM orders WHERE total > 1000 WITH CASCADED CHECK OPTION", script.Statements[1])
+	assert.Equal(t, "CREATE VIEW `order_stats` AS SELECT count(*) AS n FROM orders", script.Statements[2])
+	assert.Equal(t, "CREATE VIEW `large_order_ids` AS SELECT order_id FROM large_orders", script.Statements[3])
+	assert.Len(t, script.Warnings, 2)
+}
+
+func TestGenerateViewChanges(t *testing.T) {
+	t.Parallel()
+	const view = `
+[[views]]
+name = "order_ids"
+definition = "SELECT id FROM orders"
+`
+	from := parse(t, ordersSchema+view)
+	to := parse(t, ordersSchema+strings.Replace(view, "SELECT id", "SELECT DISTINCT id", 1))
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"DROP VIEW `order_ids`",
+		"CREATE VIEW `order_ids` AS SELECT DISTINCT id FROM orders",
+	}, script.Statements)
+}
+
+const ordersSchema = `
+[database]
+name = "app"
+dialect = "mysql"
+
+[[tables]]
+name = "orders"
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "total"
+  type = "decimal(10,2)"
+`
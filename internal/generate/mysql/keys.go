@@ -0,0 +1,147 @@
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// constraintDefinition renders a constraint as it appears in CREATE TABLE
+// and in ALTER TABLE ... ADD clauses.
+func (r *renderer) constraintDefinition(con *core.Constraint) string {
+	cols := generate.QuoteList(con.Columns, quote)
+	switch con.Type {
+	case core.ConstraintPrimaryKey:
+		return "PRIMARY KEY (" + cols + ")"
+	case core.ConstraintUnique:
+		return named(con) + "UNIQUE (" + cols + ")"
+	case core.ConstraintCheck:
+		def := named(con) + "CHECK (" + con.CheckExpression + ")"
+		if !generate.Enforced(con) {
+			def += " NOT ENFORCED"
+		}
+		return def
+	case core.ConstraintForeignKey:
+		return named(con) + foreignKey(con)
+	default:
+		return ""
+	}
+}
+
+func named(con *core.Constraint) string {
+	if con.Name == "" {
+		return ""
+	}
+	return "CONSTRAINT " + quote(con.Name) + " "
+}
+
+func foreignKey(con *core.Constraint) string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		generate.QuoteList(con.Columns, quote),
+		quote(con.ReferencedTable),
+		generate.QuoteList(con.ReferencedColumns, quote))
+	if con.OnDelete != core.RefActionNone {
+		def += " ON DELETE " + string(con.OnDelete)
+	}
+	if con.OnUpdate != core.RefActionNone {
+		def += " ON UPDATE " + string(con.OnUpdate)
+	}
+	return def
+}
+
+// dropConstraint renders the ALTER TABLE clause that removes con. MySQL
+// implements UNIQUE constraints as indexes, so they are dropped as such.
+func dropConstraint(t *core.Table, con *core.Constraint) string {
+	name := quote(generate.ConstraintName(t, con))
+	switch con.Type {
+	case core.ConstraintPrimaryKey:
+		return "DROP PRIMARY KEY"
+	case core.ConstraintUnique:
+		return "DROP INDEX " + name
+	case core.ConstraintCheck:
+		return "DROP CHECK " + name
+	default:
+		return "DROP FOREIGN KEY " + name
+	}
+}
+
+// indexDefinition renders an index inline in CREATE TABLE.
+func (r *renderer) indexDefinition(t *core.Table, idx *core.Index) string {
+	if !r.flavor.supportedIndex(r, t, idx) {
+		return ""
+	}
+	def := indexKind(idx) + "INDEX " + quote(generate.IndexName(t, idx)) + " (" + indexColumns(idx) + ")"
+	return def + r.indexOptions(idx)
+}
+
+// createIndex renders a standalone CREATE INDEX statement.
+func (r *renderer) createIndex(t *core.Table, idx *core.Index) string {
+	if !r.flavor.supportedIndex(r, t, idx) {
+		return ""
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)%s",
+		indexKind(idx), quote(generate.IndexName(t, idx)), quote(t.Name), indexColumns(idx), r.indexOptions(idx))
+}
+
+func (r *renderer) supportedIndex(t *core.Table, idx *core.Index) bool {
+	if idx.Where != "" {
+		r.script.Warnf("table %s: MySQL does not support partial indexes; the WHERE clause of %s was ignored",
+			t.Name, generate.IndexName(t, idx))
+	}
+	switch idx.Type {
+	case core.IndexTypeGIN, core.IndexTypeGiST, core.IndexTypeBRIN:
+		r.script.Warnf("table %s: index %s uses %s, which MySQL does not support, and was skipped",
+			t.Name, generate.IndexName(t, idx), idx.Type)
+		return false
+	default:
+		return true
+	}
+}
+
+func indexKind(idx *core.Index) string {
+	switch {
+	case idx.Type == core.IndexTypeFullText:
+		return "FULLTEXT "
+	case idx.Type == core.IndexTypeSpatial:
+		return "SPATIAL "
+	case idx.Unique:
+		return "UNIQUE "
+	default:
+		return ""
+	}
+}
+
+func indexColumns(idx *core.Index) string {
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		col := generate.KeyPart(c, quote)
+		if c.Length > 0 {
+			col += "(" + strconv.Itoa(c.Length) + ")"
+		}
+		if c.Order == core.SortDesc {
+			col += " DESC"
+		}
+		cols[i] = col
+	}
+	return strings.Join(cols, ", ")
+}
+
+func (r *renderer) indexOptions(idx *core.Index) string {
+	var opts string
+	if idx.Type == core.IndexTypeBTree || idx.Type == core.IndexTypeHash {
+		opts += " USING " + string(idx.Type)
+	}
+	if idx.Parser != "" {
+		opts += " WITH PARSER " + quote(idx.Parser)
+	}
+	if idx.Comment != "" {
+		opts += " COMMENT " + quoteString(idx.Comment)
+	}
+	if idx.Visibility == core.IndexInvisible {
+		opts += " " + r.flavor.invisibleIndex()
+	}
+	return opts
+}
@@ -0,0 +1,115 @@
+package mysql
+
+import (
+	"strconv"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+func init() {
+	generate.Register(core.DialectMariaDB, NewMariaDB)
+}
+
+// NewMariaDB returns a generator for MariaDB. MariaDB accepts most of the
+// MySQL syntax and adds system-versioned tables, sequences, and options of
+// the Aria engine and of data-at-rest encryption. MySQL-only options are
+// skipped.
+func NewMariaDB() generate.Generator {
+	return &Generator{flavor: mariadbFlavor{}}
+}
+
+type mariadbFlavor struct {
+	mysqlFlavor
+}
+
+// mysqlOnlyOptions lists the MySQL table and column options MariaDB
+// rejects.
+var mysqlOnlyOptions = map[string]bool{
+	"mysql.secondary_engine":           true,
+	"mysql.secondary_engine_attribute": true,
+	"mysql.engine_attribute":           true,
+	"mysql.primary_engine_attribute":   true,
+	"mysql.autoextend_size":            true,
+	"mysql.compression":                true,
+	"mysql.encryption":                 true,
+	"mysql.srid":                       true,
+}
+
+func mariadbOptions(t *core.Table) *core.MariaDBTableOptions {
+	if t.Options.MariaDB == nil {
+		return &core.MariaDBTableOptions{}
+	}
+	return t.Options.MariaDB
+}
+
+// mariadbOption renders one MariaDB table option clause. key is the diff
+// field name of the option.
+type mariadbOption struct {
+	key    string
+	render func(o *core.MariaDBTableOptions) string
+}
+
+var mariadbOptionClauses = []mariadbOption{
+	{"mariadb.page_checksum", func(o *core.MariaDBTableOptions) string { return number("PAGE_CHECKSUM", o.PageChecksum) }},
+	{"mariadb.transactional", func(o *core.MariaDBTableOptions) string { return number("TRANSACTIONAL", o.Transactional) }},
+	{"mariadb.encryption_key_id", encryptionKeyID},
+}
+
+func encryptionKeyID(o *core.MariaDBTableOptions) string {
+	if o.EncryptionKeyID == nil {
+		return ""
+	}
+	return "ENCRYPTION_KEY_ID=" + strconv.Itoa(*o.EncryptionKeyID)
+}
+
+func (mariadbFlavor) sequence(t *core.Table) bool {
+	return mariadbOptions(t).Sequence
+}
+
+func (mariadbFlavor) supportsOption(key string) bool {
+	return !mysqlOnlyOptions[key]
+}
+
+func (mariadbFlavor) invisibleIndex() string {
+	return "IGNORED"
+}
+
+// tableOptions renders the MariaDB options of t. System versioning comes
+// last, as MariaDB documents it.
+func (mariadbFlavor) tableOptions(_ *renderer, t *core.Table) []string {
+	o := mariadbOptions(t)
+	var opts []string
+	for _, opt := range mariadbOptionClauses {
+		if clause := opt.render(o); clause != "" {
+			opts = append(opts, clause)
+		}
+	}
+	if o.WithSystemVersioning {
+		opts = append(opts, "WITH SYSTEM VERSIONING")
+	}
+	return opts
+}
+
+// changedTableOptions renders the changed MariaDB options. Enabling
+// system versioning is a separate ALTER TABLE statement, since it cannot
+// be combined with table options.
+func (mariadbFlavor) changedTableOptions(r *renderer, c *diff.TableOptionChange) []string {
+	o := mariadbOptions(c.New)
+	var opts []string
+	for _, f := range c.Fields {
+		switch f.Name {
+		case "mariadb.with_system_versioning":
+			r.script.Add("ALTER TABLE " + quote(c.New.Name) + " ADD SYSTEM VERSIONING")
+		case "mariadb.sequence":
+			r.script.Warnf("table %s: a table cannot be converted to or from a sequence; recreate it", c.New.Name)
+		}
+		for _, opt := range mariadbOptionClauses {
+			if clause := opt.render(o); opt.key == f.Name && clause != "" {
+				opts = append(opts, clause)
+			}
+		}
+	}
+	return opts
+}
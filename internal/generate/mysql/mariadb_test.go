@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+const mariadbSchema = `
+[database]
+name = "app"
+dialect = "mariadb"
+
+[[tables]]
+name = "prices"
+
+  [tables.options.mysql]
+  engine = "Aria"
+  secondary_engine = "RAPID"
+
+  [tables.options.mariadb]
+  page_checksum = 1
+  transactional = 1
+  encryption_key_id = 2
+  with_system_versioning = true
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "amount"
+  type = "decimal(10,2)"
+
+    [tables.columns.mysql]
+    secondary_engine_attribute = "{}"
+
+  [[tables.indexes]]
+  columns = ["amount"]
+  visibility = "INVISIBLE"
+`
+
+func TestGenerateMariaDBCreateTable(t *testing.T) {
+	t.Parallel()
+	script, err := NewMariaDB().Generate(parse(t, mariadbSchema))
+	require.NoError(t, err)
+
+	require.Len(t, script.Statements, 1)
+	assert.Equal(t, "CREATE TABLE `prices` (\n"+
+		"  `id` INT NOT NULL,\n"+
+		"  `amount` DECIMAL(10,2) NOT NULL,\n"+
+		"  PRIMARY KEY (`id`),\n"+
+		"  INDEX `idx_prices_amount` (`amount`) USING BTREE IGNORED\n"+
+		") ENGINE=Aria PAGE_CHECKSUM=1 TRANSACTIONAL=1 ENCRYPTION_KEY_ID=2 WITH SYSTEM VERSIONING",
+		script.Statements[0])
+	require.Len(t, script.Warnings, 1)
+	assert.Contains(t, script.Warnings[0], "mysql.secondary_engine")
+}
+
+func TestGenerateMariaDBChanges(t *testing.T) {
+	t.Parallel()
+	from := parse(t, strings.Replace(mariadbSchema, "with_system_versioning = true", "", 1))
+	to := parse(t, strings.Replace(mariadbSchema, "encryption_key_id = 2", "encryption_key_id = 3", 1))
+
+	script, err := NewMariaDB().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"ALTER TABLE `prices` ADD SYSTEM VERSIONING",
+		"ALTER TABLE `prices` ENCRYPTION_KEY_ID=3",
+	}, script.Statements)
+}
+
+func TestGenerateMariaDBSequence(t *testing.T) {
+	t.Parallel()
+	seq := &core.Table{Name: "invoice_no", Options: core.TableOptions{MariaDB: &core.MariaDBTableOptions{Sequence: true}}}
+	cs := &diff.ChangeSet{Changes: []diff.Change{&diff.AddTable{Table: seq}, &diff.DropTable{Table: seq}}}
+	script, err := NewMariaDB().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CREATE SEQUENCE `invoice_no`", "DROP SEQUENCE `invoice_no`"}, script.Statements)
+}
+
+func TestMariaDBGeneratorRegistered(t *testing.T) {
+	t.Parallel()
+	g, err := generate.NewGenerator(core.DialectMariaDB)
+	require.NoError(t, err)
+	assert.IsType(t, &Generator{}, g)
+	assert.IsType(t, mariadbFlavor{}, g.(*Generator).flavor)
+}
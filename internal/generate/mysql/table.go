@@ -0,0 +1,208 @@
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// createTable renders a CREATE TABLE statement with inline constraints,
+// indexes, and table options.
+func (r *renderer) createTable(t *core.Table) string {
+	var defs []string
+	for _, c := range t.Columns {
+		defs = append(defs, r.columnDefinition(c))
+	}
+	for _, con := range t.Constraints {
+		if con.Type == core.ConstraintForeignKey && !r.order.Inline(t, con) {
+			r.deferred = append(r.deferred, fmt.Sprintf("ALTER TABLE %s ADD %s", quote(t.Name), r.constraintDefinition(con)))
+			continue
+		}
+		defs = append(defs, r.constraintDefinition(con))
+	}
+	for _, idx := range t.Indexes {
+		if def := r.indexDefinition(t, idx); def != "" {
+			defs = append(defs, def)
+		}
+	}
+
+	r.order.Created(t.Name)
+
+	var sb strings.Builder
+	sb.WriteString("CREATE TABLE ")
+	sb.WriteString(quote(t.Name))
+	sb.WriteString(" (\n  ")
+	sb.WriteString(strings.Join(defs, ",\n  "))
+	sb.WriteString("\n)")
+	if opts := r.tableOptions(t); len(opts) > 0 {
+		sb.WriteString(" ")
+		sb.WriteString(joinOptions(opts))
+	}
+	return sb.String()
+}
+
+// tableOption renders one MySQL table option clause. key is the diff field
+// name of the option (see diff.FieldChange.Name).
+type tableOption struct {
+	key    string
+	render func(o *core.MySQLTableOptions) string
+}
+
+var tableOptionClauses = []tableOption{
+	{"mysql.engine", func(o *core.MySQLTableOptions) string { return ident("ENGINE", o.Engine) }},
+	{"mysql.charset", func(o *core.MySQLTableOptions) string { return ident("DEFAULT CHARSET", o.Charset) }},
+	{"mysql.collate", func(o *core.MySQLTableOptions) string { return ident("COLLATE", o.Collate) }},
+	{"mysql.auto_increment", func(o *core.MySQLTableOptions) string { return number("AUTO_INCREMENT", o.AutoIncrement) }},
+	{"mysql.row_format", func(o *core.MySQLTableOptions) string { return ident("ROW_FORMAT", strings.ToUpper(o.RowFormat)) }},
+	{"mysql.avg_row_length", func(o *core.MySQLTableOptions) string { return number("AVG_ROW_LENGTH", o.AvgRowLength) }},
+	{"mysql.key_block_size", func(o *core.MySQLTableOptions) string { return number("KEY_BLOCK_SIZE", o.KeyBlockSize) }},
+	{"mysql.max_rows", func(o *core.MySQLTableOptions) string { return number("MAX_ROWS", o.MaxRows) }},
+	{"mysql.min_rows", func(o *core.MySQLTableOptions) string { return number("MIN_ROWS", o.MinRows) }},
+	{"mysql.checksum", func(o *core.MySQLTableOptions) string { return number("CHECKSUM", o.Checksum) }},
+	{"mysql.delay_key_write", func(o *core.MySQLTableOptions) string { return number("DELAY_KEY_WRITE", o.DelayKeyWrite) }},
+	{"mysql.compression", func(o *core.MySQLTableOptions) string { return literal("COMPRESSION", o.Compression) }},
+	{"mysql.encryption", func(o *core.MySQLTableOptions) string { return literal("ENCRYPTION", o.Encryption) }},
+	{"mysql.pack_keys", func(o *core.MySQLTableOptions) string { return ident("PACK_KEYS", o.PackKeys) }},
+	{"mysql.data_directory", func(o *core.MySQLTableOptions) string { return literal("DATA DIRECTORY", o.DataDirectory) }},
+	{"mysql.index_directory", func(o *core.MySQLTableOptions) string { return literal("INDEX DIRECTORY", o.IndexDirectory) }},
+	{"mysql.insert_method", func(o *core.MySQLTableOptions) string { return ident("INSERT_METHOD", strings.ToUpper(o.InsertMethod)) }},
+	{"mysql.stats_persistent", func(o *core.MySQLTableOptions) string { return ident("STATS_PERSISTENT", o.StatsPersistent) }},
+	{"mysql.stats_auto_recalc", func(o *core.MySQLTableOptions) string { return ident("STATS_AUTO_RECALC", o.StatsAutoRecalc) }},
+	{"mysql.stats_sample_pages", func(o *core.MySQLTableOptions) string { return ident("STATS_SAMPLE_PAGES", o.StatsSamplePages) }},
+	{"mysql.connection", func(o *core.MySQLTableOptions) string { return literal("CONNECTION", o.Connection) }},
+	{"mysql.password", func(o *core.MySQLTableOptions) string { return literal("PASSWORD", o.Password) }},
+	{"mysql.autoextend_size", func(o *core.MySQLTableOptions) string { return ident("AUTOEXTEND_SIZE", o.AutoextendSize) }},
+	{"mysql.union", unionOption},
+	{"mysql.secondary_engine", func(o *core.MySQLTableOptions) string { return ident("SECONDARY_ENGINE", o.SecondaryEngine) }},
+	{"mysql.table_checksum", func(o *core.MySQLTableOptions) string { return number("TABLE_CHECKSUM", o.TableChecksum) }},
+	{"mysql.engine_attribute", func(o *core.MySQLTableOptions) string { return literal("ENGINE_ATTRIBUTE", o.EngineAttribute) }},
+	{"mysql.secondary_engine_attribute", func(o *core.MySQLTableOptions) string {
+		return literal("SECONDARY_ENGINE_ATTRIBUTE", o.SecondaryEngineAttribute)
+	}},
+	{"mysql.page_compressed", func(o *core.MySQLTableOptions) string { return flag("PAGE_COMPRESSED", o.PageCompressed) }},
+	{"mysql.page_compression_level", func(o *core.MySQLTableOptions) string {
+		return number("PAGE_COMPRESSION_LEVEL", o.PageCompressionLevel)
+	}},
+	{"mysql.ietf_quotes", func(o *core.MySQLTableOptions) string { return flag("IETF_QUOTES", o.IETFQuotes) }},
+}
+
+// tableOptions renders every table option set on t, including the
+// dialect-neutral tablespace, the options of the flavor, and the table
+// comment. The partitioning clause must come last.
+func (r *renderer) tableOptions(t *core.Table) []string {
+	o := t.Options.MySQL
+	if o == nil {
+		o = &core.MySQLTableOptions{}
+	}
+	var opts []string
+	for _, opt := range tableOptionClauses {
+		clause := opt.render(o)
+		switch {
+		case clause == "":
+		case r.flavor.supportsOption(opt.key):
+			opts = append(opts, clause)
+		default:
+			r.script.Warnf("table %s: %s is not supported by the server and was skipped", t.Name, opt.key)
+		}
+	}
+	opts = append(opts, tablespaceOptions(t.Options.Tablespace, o.StorageMedia)...)
+	opts = append(opts, r.flavor.tableOptions(r, t)...)
+	if o.Nodegroup != 0 {
+		r.script.Warnf("table %s: NODEGROUP is only valid in partition definitions and was skipped", t.Name)
+	}
+	if t.Comment != "" {
+		opts = append(opts, "COMMENT="+quoteString(t.Comment))
+	}
+	if o.PartitionBy != "" {
+		opts = append(opts, "PARTITION BY "+o.PartitionBy)
+	}
+	return opts
+}
+
+// changedTableOptions renders the option clauses for the MySQL and flavor
+// option fields listed in c, with a changed partitioning last. Options of
+// other dialects are ignored.
+func (r *renderer) changedTableOptions(c *diff.TableOptionChange) []string {
+	o := c.New.Options.MySQL
+	if o == nil {
+		o = &core.MySQLTableOptions{}
+	}
+	changed := make(map[string]bool, len(c.Fields))
+	for _, f := range c.Fields {
+		changed[f.Name] = true
+	}
+
+	var opts []string
+	for _, opt := range tableOptionClauses {
+		if !changed[opt.key] || !r.flavor.supportsOption(opt.key) {
+			continue
+		}
+		if clause := opt.render(o); clause != "" {
+			opts = append(opts, clause)
+		} else {
+			r.script.Warnf("table %s: %s cannot be reset with ALTER TABLE and was left unchanged", c.New.Name, opt.key)
+		}
+	}
+	if changed["tablespace"] || changed["mysql.storage_media"] {
+		opts = append(opts, tablespaceOptions(c.New.Options.Tablespace, o.StorageMedia)...)
+	}
+	opts = append(opts, r.flavor.changedTableOptions(r, c)...)
+	if changed["mysql.partition_by"] && o.PartitionBy != "" {
+		opts = append(opts, "PARTITION BY "+o.PartitionBy)
+	}
+	return opts
+}
+
+func tablespaceOptions(tablespace, storage string) []string {
+	var opts []string
+	if tablespace != "" {
+		opts = append(opts, "TABLESPACE "+quote(tablespace))
+	}
+	if storage != "" {
+		opts = append(opts, "STORAGE "+strings.ToUpper(storage))
+	}
+	return opts
+}
+
+func unionOption(o *core.MySQLTableOptions) string {
+	if len(o.Union) == 0 {
+		return ""
+	}
+	return "UNION=(" + generate.QuoteList(o.Union, quote) + ")"
+}
+
+func joinOptions(opts []string) string {
+	return strings.Join(opts, " ")
+}
+
+func ident(name, v string) string {
+	if v == "" {
+		return ""
+	}
+	return name + "=" + v
+}
+
+func literal(name, v string) string {
+	if v == "" {
+		return ""
+	}
+	return name + "=" + quoteString(v)
+}
+
+func number(name string, v uint64) string {
+	if v == 0 {
+		return ""
+	}
+	return name + "=" + strconv.FormatUint(v, 10)
+}
+
+func flag(name string, v bool) string {
+	if !v {
+		return ""
+	}
+	return name + "=1"
+}
@@ -0,0 +1,209 @@
+package mysql
+
+import (
+	"strconv"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+func init() {
+	generate.Register(core.DialectTiDB, NewTiDB)
+}
+
+// NewTiDB returns a generator for TiDB. TiDB accepts the MySQL syntax and
+// adds options for ID allocation, region sharding, row expiration (TTL),
+// placement policies, and statistics.
+func NewTiDB() generate.Generator {
+	return &Generator{flavor: tidbFlavor{}}
+}
+
+type tidbFlavor struct {
+	mysqlFlavor
+}
+
+func tidbOptions(t *core.Table) *core.TiDBTableOptions {
+	if t.Options.TiDB == nil {
+		return &core.TiDBTableOptions{}
+	}
+	return t.Options.TiDB
+}
+
+// tidbOption renders one TiDB table option clause. key is the diff field
+// name of the option.
+type tidbOption struct {
+	key    string
+	render func(o *core.TiDBTableOptions) string
+}
+
+var tidbOptionClauses = []tidbOption{
+	{"tidb.auto_id_cache", func(o *core.TiDBTableOptions) string { return number("AUTO_ID_CACHE", o.AutoIDCache) }},
+	{"tidb.auto_random_base", func(o *core.TiDBTableOptions) string { return number("AUTO_RANDOM_BASE", o.AutoRandomBase) }},
+	{"tidb.shard_row_id", func(o *core.TiDBTableOptions) string { return number("SHARD_ROW_ID_BITS", o.ShardRowID) }},
+	{"tidb.ttl", func(o *core.TiDBTableOptions) string { return ident("TTL", o.TTL) }},
+	{"tidb.ttl_enable", ttlEnable},
+	{"tidb.ttl_job_interval", func(o *core.TiDBTableOptions) string { return literal("TTL_JOB_INTERVAL", o.TTLJobInterval) }},
+	{"tidb.placement_policy", placementPolicy},
+	{"tidb.stats_buckets", func(o *core.TiDBTableOptions) string { return number("STATS_BUCKETS", o.StatsBuckets) }},
+	{"tidb.stats_top_n", func(o *core.TiDBTableOptions) string { return number("STATS_TOPN", o.StatsTopN) }},
+	{"tidb.stats_cols_choice", func(o *core.TiDBTableOptions) string { return literal("STATS_COL_CHOICE", o.StatsColsChoice) }},
+	{"tidb.stats_col_list", func(o *core.TiDBTableOptions) string { return literal("STATS_COL_LIST", o.StatsColList) }},
+	{"tidb.stats_sample_rate", statsSampleRate},
+}
+
+// ttlEnable renders TTL_ENABLE for a table with a TTL. The TTL job only
+// runs when ttl_enable is set.
+func ttlEnable(o *core.TiDBTableOptions) string {
+	switch {
+	case o.TTL == "":
+		return ""
+	case o.TTLEnable:
+		return "TTL_ENABLE='ON'"
+	default:
+		return "TTL_ENABLE='OFF'"
+	}
+}
+
+func placementPolicy(o *core.TiDBTableOptions) string {
+	if o.PlacementPolicy == "" {
+		return ""
+	}
+	return "PLACEMENT POLICY=" + quote(o.PlacementPolicy)
+}
+
+func statsSampleRate(o *core.TiDBTableOptions) string {
+	if o.StatsSampleRate == 0 {
+		return ""
+	}
+	return "STATS_SAMPLE_RATE=" + strconv.FormatFloat(o.StatsSampleRate, 'f', -1, 64)
+}
+
+func (tidbFlavor) sequence(t *core.Table) bool {
+	return tidbOptions(t).Sequence
+}
+
+// tableOptions renders the TiDB options of t. PRE_SPLIT_REGIONS only
+// applies when the table is created.
+func (tidbFlavor) tableOptions(r *renderer, t *core.Table) []string {
+	o := tidbOptions(t)
+	var opts []string
+	for _, opt := range tidbOptionClauses {
+		if clause := opt.render(o); clause != "" {
+			opts = append(opts, clause)
+		}
+	}
+	if o.PreSplitRegion != 0 {
+		opts = append(opts, number("PRE_SPLIT_REGIONS", o.PreSplitRegion))
+	}
+	if o.ShardRowID != 0 && clusteredPrimaryKey(t) {
+		r.script.Warnf("table %s: SHARD_ROW_ID_BITS has no effect on tables with a clustered primary key", t.Name)
+	}
+	if o.Affinity != "" {
+		r.script.Warnf("table %s: the follower-read affinity is set with tidb_replica_read, not in the schema; it was ignored", t.Name)
+	}
+	return opts
+}
+
+func (tidbFlavor) changedTableOptions(r *renderer, c *diff.TableOptionChange) []string {
+	o := tidbOptions(c.New)
+	var opts []string
+	for _, f := range c.Fields {
+		if f.Name == "tidb.pre_split_region" {
+			r.script.Warnf("table %s: PRE_SPLIT_REGIONS only applies when the table is created", c.New.Name)
+			continue
+		}
+		for _, opt := range tidbOptionClauses {
+			if clause := opt.render(o); opt.key == f.Name && clause != "" {
+				opts = append(opts, clause)
+			}
+		}
+	}
+	return opts
+}
+
+// clusteredPrimaryKey reports whether TiDB stores t clustered by its
+// primary key, which it does for a single integer key in every
+// configuration.
+func clusteredPrimaryKey(t *core.Table) bool {
+	pk := t.PrimaryKey()
+	if pk == nil || len(pk.Columns) != 1 {
+		return false
+	}
+	c := t.FindColumn(pk.Columns[0])
+	return c != nil && c.Type == core.DataTypeInt
+}
+
+// autoIncrement renders AUTO_RANDOM for columns with shard bits, whether or
+// not they are declared auto_increment: a pulled AUTO_RANDOM column is not.
+// TiDB allocates AUTO_RANDOM values scattered across regions to avoid write
+// hotspots on the primary key.
+func (f tidbFlavor) autoIncrement(c *core.Column) string {
+	if c.TiDB == nil || c.TiDB.ShardBits == 0 {
+		return f.mysqlFlavor.autoIncrement(c)
+	}
+	clause := "AUTO_RANDOM(" + strconv.FormatUint(c.TiDB.ShardBits, 10)
+	if c.TiDB.RangeBits != nil {
+		clause += ", " + strconv.FormatUint(*c.TiDB.RangeBits, 10)
+	}
+	return clause + ")"
+}
+
+// supportedIndex skips the index types TiDB does not implement.
+func (f tidbFlavor) supportedIndex(r *renderer, t *core.Table, idx *core.Index) bool {
+	switch idx.Type {
+	case core.IndexTypeFullText, core.IndexTypeSpatial:
+		r.script.Warnf("table %s: TiDB does not support %s indexes; %s was skipped",
+			t.Name, idx.Type, generate.IndexName(t, idx))
+		return false
+	default:
+		return f.mysqlFlavor.supportedIndex(r, t, idx)
+	}
+}
+
+// typeFamily groups column types by how TiDB converts between them.
+type typeFamily int
+
+const (
+	familyOther typeFamily = iota
+	familyTemporal
+	familyBit
+	familyEnum
+	familySet
+	familyJSON
+)
+
+func columnFamily(c *core.Column) typeFamily {
+	if generate.IsEnum(c) {
+		return familyEnum
+	}
+	switch generate.TypeBase(c) {
+	case "date", "datetime", "timestamp", "time", "year":
+		return familyTemporal
+	case "bit":
+		return familyBit
+	case "enum":
+		return familyEnum
+	case "set":
+		return familySet
+	case "json":
+		return familyJSON
+	default:
+		return familyOther
+	}
+}
+
+// alterType keeps the old type of a column when TiDB cannot convert it in
+// a single MODIFY COLUMN: TiDB does not convert between temporal, BIT,
+// ENUM, SET, or JSON types and other types. The other attribute changes
+// are still applied.
+func (tidbFlavor) alterType(r *renderer, t *core.Table, from, to *core.Column) *core.Column {
+	if columnFamily(from) == columnFamily(to) {
+		return to
+	}
+	r.script.Warnf("column %s.%s: TiDB cannot change the type from %s to %s; add a new column and copy the data",
+		t.Name, to.Name, ColumnType(from), ColumnType(to))
+	kept := *to
+	kept.Type, kept.PortableType, kept.RawType, kept.EnumValues = from.Type, from.PortableType, from.RawType, from.EnumValues
+	return &kept
+}
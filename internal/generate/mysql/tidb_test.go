@@ -0,0 +1,110 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+const tidbSchema = `
+[database]
+name = "app"
+dialect = "tidb"
+
+[[tables]]
+name = "orders"
+
+  [tables.options.tidb]
+  auto_id_cache = 1000
+  pre_split_region = 4
+  ttl = "created_at + INTERVAL 90 DAY"
+  ttl_enable = true
+  ttl_job_interval = "1h"
+  placement_policy = "eu_only"
+  stats_sample_rate = 0.5
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+  auto_increment = true
+
+    [tables.columns.tidb]
+    shard_bits = 5
+    range_bits = 54
+
+  [[tables.columns]]
+  name = "note"
+  type = "text"
+  nullable = true
+
+  [[tables.columns]]
+  name = "created_at"
+  type = "datetime"
+
+  [[tables.indexes]]
+  type = "FULLTEXT"
+  columns = ["note"]
+`
+
+func TestGenerateTiDBCreateTable(t *testing.T) {
+	t.Parallel()
+	script, err := NewTiDB().Generate(parse(t, tidbSchema))
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 1)
+
+	assert.Equal(t, "CREATE TABLE `orders` (\n"+
+		"  `id` BIGINT NOT NULL AUTO_RANDOM(5, 54),\n"+
+		"  `note` TEXT NULL,\n"+
+		"  `created_at` DATETIME NOT NULL,\n"+
+		"  PRIMARY KEY (`id`)\n"+
+		") AUTO_ID_CACHE=1000 TTL=created_at + INTERVAL 90 DAY TTL_ENABLE='ON' TTL_JOB_INTERVAL='1h' "+
+		"PLACEMENT POLICY=`eu_only` STATS_SAMPLE_RATE=0.5 PRE_SPLIT_REGIONS=4",
+		script.Statements[0])
+	require.Len(t, script.Warnings, 1)
+	assert.Contains(t, script.Warnings[0], "idx_orders_note was skipped")
+}
+
+func TestGenerateTiDBChanges(t *testing.T) {
+	t.Parallel()
+	from := parse(t, tidbSchema)
+	to := parse(t, strings.NewReplacer(
+		`auto_id_cache = 1000`, `auto_id_cache = 1`,
+		`pre_split_region = 4`, `pre_split_region = 8`,
+		`placement_policy = "eu_only"`, `placement_policy = "us_only"`,
+		`  name = "created_at"
+  type = "datetime"`, `  name = "created_at"
+  type = "bigint"
+  nullable = true`,
+	).Replace(tidbSchema))
+
+	script, err := NewTiDB().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"ALTER TABLE `orders` AUTO_ID_CACHE=1 PLACEMENT POLICY=`us_only`",
+		"ALTER TABLE `orders` MODIFY COLUMN `created_at` DATETIME NULL",
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 2)
+}
+
+func TestGenerateTiDBSequence(t *testing.T) {
+	t.Parallel()
+	seq := &core.Table{Name: "order_no", Options: core.TableOptions{TiDB: &core.TiDBTableOptions{Sequence: true}}}
+	script, err := NewTiDB().Generate(&core.Database{Tables: []*core.Table{seq}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CREATE SEQUENCE `order_no`"}, script.Statements)
+}
+
+func TestTiDBGeneratorRegistered(t *testing.T) {
+	t.Parallel()
+	g, err := generate.NewGenerator(core.DialectTiDB)
+	require.NoError(t, err)
+	assert.IsType(t, &Generator{}, g)
+	assert.IsType(t, tidbFlavor{}, g.(*Generator).flavor)
+}
@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// viewChange renders the CREATE and DROP VIEW statements of a view change.
+// MySQL has no materialized views, so every view is dropped as a plain one.
+func (r *renderer) viewChange(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddView:
+		r.createView(c.View)
+	case *diff.DropView:
+		r.script.Add("DROP VIEW " + quote(c.View.Name))
+	default:
+		return fmt.Errorf("mysql: unsupported change %T", c)
+	}
+	return nil
+}
+
+// createView renders CREATE VIEW. Servers of the MySQL family have no
+// materialized views and no view comments; a materialized view is created
+// as a regular view.
+func (r *renderer) createView(v *core.View) {
+	if v.Materialized {
+		r.script.Warnf("view %s: materialized views are not supported; it is created as a regular view", v.Name)
+	}
+	if v.Comment != "" {
+		r.script.Warnf("view %s: views cannot have comments; the comment was ignored", v.Name)
+	}
+
+	stmt := "CREATE "
+	if v.Security != "" {
+		stmt += "SQL SECURITY " + string(v.Security) + " "
+	}
+	stmt += fmt.Sprintf("VIEW %s%s AS %s", quote(v.Name), generate.ViewColumns(v, quote), generate.ViewDefinition(v))
+	if v.CheckOption != core.CheckOptionNone {
+		stmt += fmt.Sprintf(" WITH %s CHECK OPTION", v.CheckOption)
+	}
+	r.script.Add(stmt)
+}
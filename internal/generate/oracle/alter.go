@@ -0,0 +1,151 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// alterColumn renders the MODIFY statements for a column change. Each
+// attribute is modified separately.
+func (r *renderer) alterColumn(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AlterColumnType:
+		r.alterType(c.Table, c.Old, c.New)
+	case *diff.AlterNullability:
+		action := "NULL"
+		if !c.New.Nullable {
+			action = "NOT NULL"
+		}
+		r.script.Add(modify(c.Table, c.New, action))
+	case *diff.ChangeDefault:
+		r.alterDefault(c.Table, c.New)
+	case *diff.AlterColumn:
+		for _, f := range c.Fields {
+			r.alterField(c, f)
+		}
+	}
+}
+
+func modify(t *core.Table, c *core.Column, action string) string {
+	return fmt.Sprintf("ALTER TABLE %s MODIFY (%s %s)", quote(t.Name), quote(c.Name), action)
+}
+
+// alterType changes the type of a column and replaces the CHECK constraint
+// of emulated boolean and enum columns. MODIFY cannot convert to or from
+// LOB types.
+func (r *renderer) alterType(t *core.Table, from, to *core.Column) {
+	if emulationCheck(t, from) != "" {
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", quote(t.Name), quote(checkName(t, from))))
+	}
+	switch oldType, newType := columnType(from), columnType(to); {
+	case oldType == newType:
+	case isLOB(oldType) != isLOB(newType):
+		r.script.Warnf("column %s.%s: Oracle cannot convert %s to %s in place; copy the data into a new column",
+			t.Name, to.Name, oldType, newType)
+	default:
+		r.script.Add(modify(t, to, newType))
+	}
+	if check := emulationCheck(t, to); check != "" {
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD %s", quote(t.Name), check))
+	}
+}
+
+// isLOB reports whether an Oracle type is a large object type.
+func isLOB(typ string) bool {
+	switch strings.ToUpper(generate.ParseType(typ).Base) {
+	case "CLOB", "NCLOB", "BLOB":
+		return true
+	default:
+		return false
+	}
+}
+
+// alterDefault sets the DEFAULT of c. It may be called for the default and
+// for the options that affect it, so it renders the statement only once.
+func (r *renderer) alterDefault(t *core.Table, c *core.Column) {
+	if isIdentity(c) || r.once("default", t, c) {
+		return
+	}
+	clause := valueClause(c)
+	if clause == "" {
+		clause = "DEFAULT NULL"
+	}
+	r.script.Add(modify(t, c, clause))
+}
+
+// once reports whether the attribute of c was already altered, and records
+// it otherwise.
+func (r *renderer) once(attr string, t *core.Table, c *core.Column) bool {
+	key := attr + ":" + t.Name + "." + c.Name
+	if r.altered[key] {
+		return true
+	}
+	r.altered[key] = true
+	return false
+}
+
+// alterField renders a single changed column attribute. Attributes of other
+// dialects are ignored.
+func (r *renderer) alterField(c *diff.AlterColumn, f diff.FieldChange) {
+	t, col := c.Table, c.New
+	switch f.Name {
+	case "comment":
+		r.script.Add(columnComment(t, col))
+	case "collate":
+		r.script.Add(modify(t, col, "COLLATE "+col.Collate))
+	case "invisible":
+		if col.Invisible {
+			r.script.Add(modify(t, col, "INVISIBLE"))
+		} else {
+			r.script.Add(modify(t, col, "VISIBLE"))
+		}
+	case "oracle.encrypt", "oracle.encryption_algorithm", "oracle.salt":
+		r.alterEncryption(t, col)
+	case "oracle.default_on_null":
+		r.alterDefault(t, col)
+	case "sequence_name":
+		if seq := createSequence(col); seq != "" {
+			r.script.Add(seq)
+		}
+		r.alterDefault(t, col)
+	case "auto_increment", "identity_generation", "identity_seed", "identity_increment":
+		r.alterIdentity(c)
+	case "on_update":
+		r.dropOnUpdateTrigger(t, c.Old)
+		r.createOnUpdateTrigger(t, col)
+	case "is_generated", "generation_expression", "generation_storage":
+		r.script.Warnf("column %s.%s: the generation expression cannot be altered; recreate the column", t.Name, col.Name)
+	}
+}
+
+func (r *renderer) alterEncryption(t *core.Table, c *core.Column) {
+	if r.once("encrypt", t, c) {
+		return
+	}
+	if enc := encryptClause(c); enc != "" {
+		r.script.Add(modify(t, c, enc))
+		return
+	}
+	r.script.Add(modify(t, c, "DECRYPT"))
+}
+
+// alterIdentity changes the identity options of a column or drops its
+// identity. An existing column cannot become an identity column.
+func (r *renderer) alterIdentity(c *diff.AlterColumn) {
+	t, from, to := c.Table, c.Old, c.New
+	if r.once("identity", t, to) {
+		return
+	}
+	switch {
+	case isIdentity(from) && isIdentity(to):
+		r.script.Add(modify(t, to, identityClause(to)))
+	case isIdentity(from):
+		r.script.Add(modify(t, to, "DROP IDENTITY"))
+	default:
+		r.script.Warnf("column %s.%s: an existing column cannot become an identity column; recreate the column", t.Name, to.Name)
+	}
+}
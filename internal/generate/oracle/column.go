@@ -0,0 +1,255 @@
+package oracle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+var typeMapper = generate.TypeMapper{
+	Types: map[string]string{
+		"varchar":   "VARCHAR2(%s)",
+		"char":      "CHAR(%s)",
+		"text":      "CLOB",
+		"tinyint":   "NUMBER(3)",
+		"smallint":  "NUMBER(5)",
+		"int":       "NUMBER(10)",
+		"integer":   "NUMBER(10)",
+		"bigint":    "NUMBER(19)",
+		"decimal":   "NUMBER(%s)",
+		"numeric":   "NUMBER(%s)",
+		"float":     "BINARY_FLOAT",
+		"double":    "BINARY_DOUBLE",
+		"date":      "DATE",
+		"time":      "INTERVAL DAY(0) TO SECOND",
+		"timestamp": "TIMESTAMP WITH TIME ZONE",
+		"datetime":  "TIMESTAMP",
+		"json":      "JSON",
+		"uuid":      "RAW(16)",
+		"blob":      "BLOB",
+		"binary":    "RAW(%s)",
+		"varbinary": "RAW(%s)",
+	},
+	DefaultArgs: map[string]string{
+		"varchar":   "255",
+		"binary":    "1",
+		"varbinary": "2000",
+	},
+	Fallback: map[core.DataType]string{
+		core.DataTypeString:   "VARCHAR2(4000)",
+		core.DataTypeInt:      "NUMBER(10)",
+		core.DataTypeFloat:    "BINARY_DOUBLE",
+		core.DataTypeDatetime: "TIMESTAMP",
+		core.DataTypeJSON:     "JSON",
+		core.DataTypeUUID:     "RAW(16)",
+		core.DataTypeBinary:   "BLOB",
+	},
+	StripSuffix: true,
+}
+
+// isBool reports whether c is a portable boolean that the generator
+// emulates with NUMBER(1).
+func isBool(c *core.Column) bool {
+	return c.RawType == "" && c.Type == core.DataTypeBoolean
+}
+
+// isIdentity reports whether c is rendered as an identity column.
+func isIdentity(c *core.Column) bool {
+	return c.AutoIncrement || c.IdentityGeneration != ""
+}
+
+// columnType returns the Oracle type of c. Enums become VARCHAR2 columns
+// sized for their longest value.
+func columnType(c *core.Column) string {
+	switch {
+	case generate.IsEnum(c):
+		size := 1
+		for _, v := range c.EnumValues {
+			size = max(size, len(v))
+		}
+		return "VARCHAR2(" + strconv.Itoa(size) + ")"
+	case isBool(c):
+		return "NUMBER(1)"
+	default:
+		return typeMapper.ColumnType(c)
+	}
+}
+
+// checkName returns the name of the CHECK constraint that emulates a
+// boolean or enum column.
+func checkName(t *core.Table, c *core.Column) string {
+	if isBool(c) {
+		return "chk_" + t.Name + "_" + c.Name + "_bool"
+	}
+	return "chk_" + t.Name + "_" + c.Name + "_enum"
+}
+
+// emulationCheck renders the CHECK constraint that restricts a boolean or
+// enum column to its values, or "" for other columns.
+func emulationCheck(t *core.Table, c *core.Column) string {
+	var values string
+	switch {
+	case generate.IsEnum(c):
+		values = generate.QuoteList(c.EnumValues, generate.QuoteString)
+	case isBool(c):
+		values = "0, 1"
+	default:
+		return ""
+	}
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s IN (%s))", quote(checkName(t, c)), quote(c.Name), values)
+}
+
+// columnDefinition renders a column as it appears in CREATE TABLE and in
+// ALTER TABLE ... ADD clauses.
+func (r *renderer) columnDefinition(t *core.Table, c *core.Column) string {
+	parts := []string{quote(c.Name), columnType(c)}
+	if c.Collate != "" {
+		parts = append(parts, "COLLATE "+c.Collate)
+	}
+	if c.Invisible {
+		parts = append(parts, "INVISIBLE")
+	}
+	if c.IsGenerated {
+		parts = append(parts, r.virtualClause(t, c))
+	} else {
+		if clause := valueClause(c); clause != "" {
+			parts = append(parts, clause)
+		}
+		if enc := encryptClause(c); enc != "" {
+			parts = append(parts, enc)
+		}
+	}
+	if !c.Nullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if check := emulationCheck(t, c); check != "" {
+		parts = append(parts, check)
+	}
+	if c.Charset != "" {
+		r.script.Warnf("column %s.%s: Oracle has no per-column character set; %s was ignored", t.Name, c.Name, c.Charset)
+	}
+	return strings.Join(parts, " ")
+}
+
+// virtualClause renders a generated column. Oracle only has virtual
+// columns.
+func (r *renderer) virtualClause(t *core.Table, c *core.Column) string {
+	if c.GenerationStorage == core.GenerationStored {
+		r.script.Warnf("column %s.%s: Oracle only supports VIRTUAL generated columns", t.Name, c.Name)
+	}
+	return "GENERATED ALWAYS AS (" + c.GenerationExpression + ") VIRTUAL"
+}
+
+func defaultOnNull(c *core.Column) bool {
+	return c.Oracle != nil && c.Oracle.DefaultOnNull
+}
+
+// valueClause renders the identity clause or the DEFAULT of c. Columns
+// bound to a named sequence default to its next value.
+func valueClause(c *core.Column) string {
+	if isIdentity(c) {
+		return identityClause(c)
+	}
+	var value string
+	switch {
+	case c.DefaultValue != nil:
+		value = defaultValue(c, *c.DefaultValue)
+	case c.SequenceName != "":
+		value = generate.QuoteQualified(c.SequenceName, quote) + ".NEXTVAL"
+	default:
+		return ""
+	}
+	if defaultOnNull(c) {
+		return "DEFAULT ON NULL " + value
+	}
+	return "DEFAULT " + value
+}
+
+func identityClause(c *core.Column) string {
+	clause := "GENERATED ALWAYS"
+	if c.IdentityGeneration == core.IdentityByDefault {
+		clause = "GENERATED BY DEFAULT"
+		if defaultOnNull(c) {
+			clause += " ON NULL"
+		}
+	}
+	clause += " AS IDENTITY"
+	if opts := identityOptions(c); opts != "" {
+		clause += " (" + opts + ")"
+	}
+	return clause
+}
+
+func identityOptions(c *core.Column) string {
+	var opts []string
+	if c.IdentitySeed != 0 {
+		opts = append(opts, "START WITH "+strconv.FormatInt(c.IdentitySeed, 10))
+	}
+	if c.IdentityIncrement != 0 {
+		opts = append(opts, "INCREMENT BY "+strconv.FormatInt(c.IdentityIncrement, 10))
+	}
+	return strings.Join(opts, " ")
+}
+
+// encryptClause renders the Transparent Data Encryption clause of c.
+func encryptClause(c *core.Column) string {
+	if c.Oracle == nil || !c.Oracle.Encrypt {
+		return ""
+	}
+	clause := "ENCRYPT"
+	if alg := c.Oracle.EncryptionAlgorithm; alg != "" {
+		clause += " USING " + generate.QuoteString(alg)
+	}
+	if salt := c.Oracle.Salt; salt != nil {
+		if *salt {
+			clause += " SALT"
+		} else {
+			clause += " NO SALT"
+		}
+	}
+	return clause
+}
+
+// defaultValue renders a DEFAULT value. Oracle has no boolean literals and
+// its own names for the current date and time.
+func defaultValue(c *core.Column, v string) string {
+	v = strings.TrimSpace(v)
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return generate.QuoteString(v)
+	case generate.DefaultBoolean:
+		return boolLiteral(generate.BoolDefault(v))
+	case generate.DefaultNumber:
+		if c.Type == core.DataTypeBoolean {
+			return boolLiteral(generate.BoolDefault(v))
+		}
+		return v
+	case generate.DefaultTimestamp:
+		return generate.TimestampDefault(v, timestampFuncs, "SYSTIMESTAMP")
+	default:
+		return v
+	}
+}
+
+func boolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// timestampFuncs lists the current date and time functions that Oracle
+// accepts as written. LOCALTIME becomes LOCALTIMESTAMP, since Oracle has
+// no time-only type.
+var timestampFuncs = map[string]string{
+	"CURRENT_TIMESTAMP": "",
+	"CURRENT_DATE":      "",
+	"CURRENT_TIME":      "",
+	"LOCALTIMESTAMP":    "",
+	"SYSDATE":           "",
+	"SYSTIMESTAMP":      "",
+	"LOCALTIME":         "LOCALTIMESTAMP",
+}
@@ -0,0 +1,108 @@
+// Package oracle contains the DDL generator for Oracle Database. Portable
+// types are mapped to VARCHAR2, NUMBER, CLOB, and RAW; booleans and enums
+// are emulated with CHECK constraints, and ON UPDATE clauses with triggers.
+// Statements that have no IF [NOT] EXISTS form are wrapped in PL/SQL blocks
+// that ignore the corresponding error.
+package oracle
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+func init() {
+	generate.Register(core.DialectOracle, New)
+}
+
+// Generator renders Oracle DDL.
+type Generator struct{}
+
+func New() generate.Generator {
+	return &Generator{}
+}
+
+// Generate renders a CREATE TABLE statement for every table and a CREATE
+// VIEW statement for every view in db, ordered so that referenced tables
+// and views are created first.
+func (g *Generator) Generate(db *core.Database) (*generate.Script, error) {
+	return g.GenerateChanges(diff.Databases(nil, db))
+}
+
+// GenerateChanges renders the statements that apply cs.
+func (g *Generator) GenerateChanges(cs *diff.ChangeSet) (*generate.Script, error) {
+	r := &renderer{
+		script:  &generate.Script{},
+		order:   generate.NewCreateOrder(cs),
+		altered: make(map[string]bool),
+	}
+	for _, c := range cs.Changes {
+		if err := r.change(c); err != nil {
+			return nil, err
+		}
+	}
+	r.script.Add(r.deferred...)
+	return r.script, nil
+}
+
+// renderer collects the Oracle statements of one change set. Each MODIFY
+// clause changes one column attribute, but the DEFAULT, encryption and
+// identity clauses each combine several schema attributes, so each of them
+// is rendered once per column however many of those attributes changed.
+type renderer struct {
+	script *generate.Script
+	order  *generate.CreateOrder
+	// deferred holds foreign keys of new tables that reference a table
+	// created later in the script.
+	deferred []string
+	// altered tracks column attributes that were already altered, since
+	// several field changes may map to one statement.
+	altered map[string]bool
+}
+
+func (r *renderer) change(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddTable:
+		r.createTable(c.Table)
+	case *diff.DropTable:
+		r.script.Add("DROP TABLE " + quote(c.Table.Name) + " PURGE")
+	case *diff.ChangeTableComment:
+		r.script.Add(fmt.Sprintf("COMMENT ON TABLE %s IS %s", quote(c.Table.Name), generate.QuoteString(c.New)))
+	case *diff.TableOptionChange:
+		r.alterTableOptions(c)
+	case *diff.AddColumn:
+		r.addColumn(c.Table, c.Column)
+	case *diff.DropColumn:
+		r.dropOnUpdateTrigger(c.Table, c.Column)
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", quote(c.Table.Name), quote(c.Column.Name)))
+	case *diff.AlterColumnType, *diff.AlterNullability, *diff.ChangeDefault, *diff.AlterColumn:
+		r.alterColumn(c)
+	case *diff.AddConstraint, *diff.DropConstraint, *diff.AddIndex, *diff.DropIndex:
+		r.keyChange(c)
+	default:
+		return r.viewChange(c)
+	}
+	return nil
+}
+
+func (r *renderer) keyChange(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AddConstraint:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD %s", quote(c.Table.Name), r.constraintDefinition(c.Table, c.Constraint)))
+	case *diff.DropConstraint:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s",
+			quote(c.Table.Name), quote(generate.ConstraintName(c.Table, c.Constraint))))
+	case *diff.AddIndex:
+		r.createIndex(c.Table, c.Index)
+	case *diff.DropIndex:
+		r.script.Add("DROP INDEX " + quote(generate.IndexName(c.Table, c.Index)))
+	}
+}
+
+// quote quotes a name in the case Oracle folds it to, so that unquoted
+// references in CHECK expressions, generated columns and views resolve.
+func quote(name string) string {
+	return generate.QuoteFolded(name)
+}
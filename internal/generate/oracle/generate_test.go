@@ -0,0 +1,242 @@
+package oracle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/pars/toml"
+)
+
+func parse(t *testing.T, schema string) *core.Database {
+	t.Helper()
+	db, err := toml.NewParser().Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+	return db
+}
+
+const ordersSchema = `
+[database]
+name = "app"
+dialect = "oracle"
+
+[[tables]]
+name = "orders"
+comment = "customer orders"
+
+  [tables.options]
+  tablespace = "users"
+
+  [tables.options.oracle]
+  organization = "index"
+  logging = false
+  pctfree = 5
+  init_trans = 4
+  segment_creation = "deferred"
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+  auto_increment = true
+  identity_generation = "BY DEFAULT"
+
+    [tables.columns.oracle]
+    default_on_null = true
+
+  [[tables.columns]]
+  name = "status"
+  type = "enum"
+  values = ["open", "shipped"]
+  default = "open"
+
+  [[tables.columns]]
+  name = "paid"
+  type = "boolean"
+  default = "false"
+
+  [[tables.columns]]
+  name = "card"
+  type = "varchar(19)"
+  nullable = true
+  invisible = true
+
+    [tables.columns.oracle]
+    encrypt = true
+    encryption_algorithm = "AES256"
+    salt = false
+
+  [[tables.columns]]
+  name = "updated_at"
+  type = "timestamp"
+  default = "NOW()"
+  on_update = "CURRENT_TIMESTAMP"
+`
+
+func TestGenerateCreateTable(t *testing.T) {
+	t.Parallel()
+	script, err := New().Generate(parse(t, ordersSchema))
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 3)
+
+	assert.Equal(t, "CREATE TABLE \"ORDERS\" (\n"+
+		`  "ID" NUMBER(19) GENERATED BY DEFAULT ON NULL AS IDENTITY NOT NULL,`+"\n"+
+		`  "STATUS" VARCHAR2(7) DEFAULT 'open' NOT NULL CONSTRAINT "CHK_ORDERS_STATUS_ENUM" CHECK ("STATUS" IN ('open', 'shipped')),`+"\n"+
+		`  "PAID" NUMBER(1) DEFAULT 0 NOT NULL CONSTRAINT "CHK_ORDERS_PAID_BOOL" CHECK ("PAID" IN (0, 1)),`+"\n"+
+		`  "CARD" VARCHAR2(19) INVISIBLE ENCRYPT USING 'AES256' NO SALT,`+"\n"+
+		`  "UPDATED_AT" TIMESTAMP WITH TIME ZONE DEFAULT SYSTIMESTAMP NOT NULL,`+"\n"+
+		`  CONSTRAINT "PK_ORDERS" PRIMARY KEY ("ID")`+"\n"+
+		`) SEGMENT CREATION DEFERRED ORGANIZATION INDEX PCTFREE 5 INITRANS 4 TABLESPACE "USERS" NOLOGGING`,
+		script.Statements[0])
+	assert.Equal(t, `COMMENT ON TABLE "ORDERS" IS 'customer orders'`, script.Statements[1])
+	assert.Equal(t, "CREATE OR REPLACE TRIGGER \"ORDERS_UPDATED_AT_ON_UPDATE\"\n"+
+		"BEFORE UPDATE ON \"ORDERS\"\n"+
+		"FOR EACH ROW\n"+
+		"BEGIN\n"+
+		"  IF NOT UPDATING('\"UPDATED_AT\"') THEN\n"+
+		"    :NEW.\"UPDATED_AT\" := CURRENT_TIMESTAMP;\n"+
+		"  END IF;\n"+
+		"END;", script.Statements[2])
+	assert.True(t, strings.HasSuffix(script.String(), "END;\n/\n"))
+	assert.Empty(t, script.Warnings)
+}
+
+func TestGenerateSequence(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "invoices", Columns: []*core.Column{
+		{Name: "no", PortableType: "bigint", SequenceName: "invoice_seq"},
+	}}
+	script, err := New().Generate(&core.Database{Tables: []*core.Table{tbl}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "BEGIN\n"+
+		"  EXECUTE IMMEDIATE 'CREATE SEQUENCE \"INVOICE_SEQ\"';\n"+
+		"EXCEPTION\n"+
+		"  WHEN OTHERS THEN\n"+
+		"    IF SQLCODE != -955 THEN\n"+
+		"      RAISE;\n"+
+		"    END IF;\n"+
+		"END;", script.Statements[0])
+	assert.Contains(t, script.Statements[1], `"NO" NUMBER(19) DEFAULT "INVOICE_SEQ".NEXTVAL NOT NULL`)
+}
+
+func TestGenerateColumnTypes(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"varchar":       "VARCHAR2(255)",
+		"text":          "CLOB",
+		"int":           "NUMBER(10)",
+		"decimal(10,2)": "NUMBER(10,2)",
+		"double":        "BINARY_DOUBLE",
+		"datetime":      "TIMESTAMP",
+		"uuid":          "RAW(16)",
+		"varbinary(32)": "RAW(32)",
+	}
+	for portable, want := range tests {
+		assert.Equal(t, want, columnType(&core.Column{PortableType: portable}), portable)
+	}
+}
+
+func TestGenerateChanges(t *testing.T) {
+	t.Parallel()
+	from := parse(t, ordersSchema)
+	to := parse(t, strings.NewReplacer(
+		`values = ["open", "shipped"]`, `values = ["open", "shipped", "returned"]`,
+		`pctfree = 5`, `pctfree = 10`,
+		`salt = false`, `salt = true`,
+		`  type = "varchar(19)"
+  nullable = true`, `  type = "varchar(19)"`,
+	).Replace(ordersSchema))
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "ORDERS" PCTFREE 10`,
+		`ALTER TABLE "ORDERS" DROP CONSTRAINT "CHK_ORDERS_STATUS_ENUM"`,
+		`ALTER TABLE "ORDERS" MODIFY ("STATUS" VARCHAR2(8))`,
+		`ALTER TABLE "ORDERS" ADD CONSTRAINT "CHK_ORDERS_STATUS_ENUM" CHECK ("STATUS" IN ('open', 'shipped', 'returned'))`,
+		`ALTER TABLE "ORDERS" MODIFY ("CARD" NOT NULL)`,
+		`ALTER TABLE "ORDERS" MODIFY ("CARD" ENCRYPT USING 'AES256' SALT)`,
+	}, script.Statements)
+}
+
+func TestGenerateKeys(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "lines"}
+	enforced := false
+	cs := &diff.ChangeSet{Changes: []diff.Change{
+		&diff.AddConstraint{Table: tbl, Constraint: &core.Constraint{
+			Type: core.ConstraintForeignKey, Columns: []string{"order_id"}, ReferencedTable: "orders",
+			ReferencedColumns: []string{"id"}, OnDelete: core.RefActionCascade, OnUpdate: core.RefActionCascade,
+		}},
+		&diff.AddConstraint{Table: tbl, Constraint: &core.Constraint{
+			Name: "chk_lines_qty", Type: core.ConstraintCheck, CheckExpression: "qty > 0", Enforced: &enforced,
+		}},
+		&diff.AddIndex{Table: tbl, Index: &core.Index{Type: core.IndexTypeFullText, Columns: []core.ColumnIndex{{Name: "note"}}}},
+		&diff.AddIndex{Table: tbl, Index: &core.Index{
+			Name: "idx_lines_sku", Visibility: core.IndexInvisible, Columns: []core.ColumnIndex{{Name: "sku"}},
+		}},
+	}}
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "LINES" ADD CONSTRAINT "FK_LINES_ORDERS" FOREIGN KEY ("ORDER_ID") REFERENCES "ORDERS" ("ID") ON DELETE CASCADE`,
+		`ALTER TABLE "LINES" ADD CONSTRAINT "CHK_LINES_QTY" CHECK (qty > 0) DISABLE`,
+		`CREATE INDEX "IDX_LINES_NOTE" ON "LINES" ("NOTE") INDEXTYPE IS CTXSYS.CONTEXT`,
+		`CREATE INDEX "IDX_LINES_SKU" ON "LINES" ("SKU") INVISIBLE`,
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 1)
+}
+
+func TestGenerateBequeathView(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		security core.ViewSecurity
+		want     string
+	}{
+		{security: "", want: `CREATE VIEW "OPEN_ORDERS" AS SELECT id FROM orders WHERE status = 'open' WITH CHECK OPTION`},
+		{security: core.SecurityDefiner, want: `CREATE VIEW "OPEN_ORDERS" BEQUEATH DEFINER AS ` +
+			`SELECT id FROM orders WHERE status = 'open' WITH CHECK OPTION`},
+		{security: core.SecurityInvoker, want: `CREATE VIEW "OPEN_ORDERS" BEQUEATH CURRENT_USER AS ` +
+			`SELECT id FROM orders WHERE status = 'open' WITH CHECK OPTION`},
+	}
+	for _, tt := range tests {
+		view := &core.View{
+			Name: "open_orders", Definition: "SELECT id FROM orders WHERE status = 'open';",
+			Security: tt.security, CheckOption: core.CheckOptionLocal,
+		}
+		script, err := New().GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{&diff.AddView{View: view}}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{tt.want}, script.Statements, tt.security)
+		assert.Len(t, script.Warnings, 1, "LOCAL is rendered as a cascaded check option")
+	}
+}
+
+func TestGenerateMaterializedViewRefresh(t *testing.T) {
+	t.Parallel()
+	daily := &core.View{
+		Name: "daily_totals", Definition: "SELECT trunc(updated_at) AS day, count(*) AS n FROM orders GROUP BY trunc(updated_at)",
+		Materialized: true, Comment: "orders per day",
+	}
+	open := &core.View{
+		Name: "open_count", Definition: "SELECT count(*) AS n FROM orders WHERE status = 'open'",
+		Materialized: true, Refresh: core.RefreshOnCommit,
+	}
+	script, err := New().GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{
+		&diff.DropView{View: open}, &diff.AddView{View: daily}, &diff.AddView{View: open},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`DROP MATERIALIZED VIEW "OPEN_COUNT"`,
+		`CREATE MATERIALIZED VIEW "DAILY_TOTALS" REFRESH ON DEMAND AS ` +
+			`SELECT trunc(updated_at) AS day, count(*) AS n FROM orders GROUP BY trunc(updated_at)`,
+		`COMMENT ON MATERIALIZED VIEW "DAILY_TOTALS" IS 'orders per day'`,
+		`CREATE MATERIALIZED VIEW "OPEN_COUNT" REFRESH ON COMMIT AS SELECT count(*) AS n FROM orders WHERE status = 'open'`,
+	}, script.Statements)
+	assert.Empty(t, script.Warnings)
+}
@@ -0,0 +1,111 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// constraintDefinition renders a named table constraint as it appears in
+// CREATE TABLE and in ALTER TABLE ... ADD clauses. Constraints that are not
+// enforced are created disabled.
+func (r *renderer) constraintDefinition(t *core.Table, con *core.Constraint) string {
+	def := "CONSTRAINT " + quote(generate.ConstraintName(t, con)) + " "
+	cols := generate.QuoteList(con.Columns, quote)
+	switch con.Type {
+	case core.ConstraintPrimaryKey:
+		def += "PRIMARY KEY (" + cols + ")"
+	case core.ConstraintUnique:
+		def += "UNIQUE (" + cols + ")"
+	case core.ConstraintCheck:
+		def += "CHECK (" + con.CheckExpression + ")"
+	default:
+		def += r.foreignKey(t, con)
+	}
+	if !generate.Enforced(con) {
+		def += " DISABLE"
+	}
+	return def
+}
+
+// foreignKey renders a FOREIGN KEY clause. Oracle only supports the
+// CASCADE and SET NULL delete actions; the default behaves like NO ACTION.
+func (r *renderer) foreignKey(t *core.Table, con *core.Constraint) string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		generate.QuoteList(con.Columns, quote),
+		quote(con.ReferencedTable),
+		generate.QuoteList(con.ReferencedColumns, quote))
+	switch con.OnDelete {
+	case core.RefActionCascade, core.RefActionSetNull:
+		def += " ON DELETE " + string(con.OnDelete)
+	case core.RefActionSetDefault:
+		r.script.Warnf("table %s: Oracle does not support ON DELETE SET DEFAULT; %s uses NO ACTION",
+			t.Name, generate.ConstraintName(t, con))
+	}
+	switch con.OnUpdate {
+	case core.RefActionNone, core.RefActionNoAction, core.RefActionRestrict:
+	default:
+		r.script.Warnf("table %s: Oracle does not support ON UPDATE %s; it was ignored for %s",
+			t.Name, con.OnUpdate, generate.ConstraintName(t, con))
+	}
+	return def
+}
+
+// createIndex renders CREATE INDEX for idx. FULLTEXT indexes become Oracle
+// Text indexes.
+func (r *renderer) createIndex(t *core.Table, idx *core.Index) {
+	name := generate.IndexName(t, idx)
+	suffix, ok := r.indexType(t, idx, name)
+	if !ok {
+		return
+	}
+	if idx.Comment != "" {
+		r.script.Warnf("table %s: Oracle does not support index comments; the comment of %s was ignored", t.Name, name)
+	}
+	if idx.Where != "" {
+		r.script.Warnf("table %s: Oracle does not support partial indexes; the WHERE clause of %s was ignored", t.Name, name)
+	}
+
+	stmt := "CREATE "
+	if idx.Unique {
+		stmt += "UNIQUE "
+	}
+	stmt += fmt.Sprintf("INDEX %s ON %s (%s)%s", quote(name), quote(t.Name), r.indexColumns(t, idx), suffix)
+	if idx.Visibility == core.IndexInvisible {
+		stmt += " INVISIBLE"
+	}
+	r.script.Add(stmt)
+}
+
+// indexType returns the clause that follows the column list for the index
+// type, or false when the index is skipped.
+func (r *renderer) indexType(t *core.Table, idx *core.Index, name string) (string, bool) {
+	switch idx.Type {
+	case "", core.IndexTypeBTree:
+		return "", true
+	case core.IndexTypeFullText:
+		return " INDEXTYPE IS CTXSYS.CONTEXT", true
+	case core.IndexTypeSpatial:
+		r.script.Warnf("table %s: spatial index %s was skipped; it requires SDO geometry metadata", t.Name, name)
+		return "", false
+	default:
+		r.script.Warnf("table %s: Oracle does not support %s indexes; %s is created as a B-tree index", t.Name, idx.Type, name)
+		return "", true
+	}
+}
+
+func (r *renderer) indexColumns(t *core.Table, idx *core.Index) string {
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		if c.Length > 0 {
+			r.script.Warnf("table %s: Oracle does not support prefix indexes; the length of %s was ignored", t.Name, c.Name)
+		}
+		cols[i] = generate.KeyPart(c, quote)
+		if c.Order == core.SortDesc {
+			cols[i] += " DESC"
+		}
+	}
+	return strings.Join(cols, ", ")
+}
@@ -0,0 +1,67 @@
+package oracle
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// Oracle error codes that guarded statements ignore.
+const (
+	errNameInUse = -955 // ORA-00955: name is already used by an existing object
+)
+
+// ignoreError wraps stmt in a PL/SQL block that runs it with EXECUTE
+// IMMEDIATE and ignores the given error code. This stands in for the
+// IF [NOT] EXISTS clauses Oracle lacks before 23ai.
+func ignoreError(stmt string, code int) string {
+	return fmt.Sprintf("BEGIN\n"+
+		"  EXECUTE IMMEDIATE %s;\n"+
+		"EXCEPTION\n"+
+		"  WHEN OTHERS THEN\n"+
+		"    IF SQLCODE != %d THEN\n"+
+		"      RAISE;\n"+
+		"    END IF;\n"+
+		"END;", generate.QuoteString(stmt), code)
+}
+
+// createSequence renders the statement that creates the sequence a column
+// is bound to, unless it already exists.
+func createSequence(c *core.Column) string {
+	if c.SequenceName == "" || isIdentity(c) {
+		return ""
+	}
+	return ignoreError("CREATE SEQUENCE "+generate.QuoteQualified(c.SequenceName, quote), errNameInUse)
+}
+
+// Oracle has no ON UPDATE column clause. A column with OnUpdate gets a
+// BEFORE UPDATE trigger that assigns the expression unless the statement
+// sets the column itself.
+
+func onUpdateName(t *core.Table, c *core.Column) string {
+	return t.Name + "_" + c.Name + "_on_update"
+}
+
+// createOnUpdateTrigger renders the trigger that emulates ON UPDATE for c.
+func (r *renderer) createOnUpdateTrigger(t *core.Table, c *core.Column) {
+	if c.OnUpdate == nil {
+		return
+	}
+	r.script.Add(fmt.Sprintf("CREATE OR REPLACE TRIGGER %s\n"+
+		"BEFORE UPDATE ON %s\n"+
+		"FOR EACH ROW\n"+
+		"BEGIN\n"+
+		"  IF NOT UPDATING(%s) THEN\n"+
+		"    :NEW.%s := %s;\n"+
+		"  END IF;\n"+
+		"END;",
+		quote(onUpdateName(t, c)), quote(t.Name),
+		generate.QuoteString(quote(c.Name)), quote(c.Name), defaultValue(c, *c.OnUpdate)))
+}
+
+func (r *renderer) dropOnUpdateTrigger(t *core.Table, c *core.Column) {
+	if c.OnUpdate != nil {
+		r.script.Add("DROP TRIGGER " + quote(onUpdateName(t, c)))
+	}
+}
@@ -0,0 +1,166 @@
+package oracle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// createTable renders CREATE TABLE for t together with the sequences it
+// depends on and the statements Oracle does not accept inline (comments,
+// indexes, and ON UPDATE triggers).
+func (r *renderer) createTable(t *core.Table) {
+	for _, c := range t.Columns {
+		if seq := createSequence(c); seq != "" {
+			r.script.Add(seq)
+		}
+	}
+
+	r.script.Add(r.createTableStatement(t))
+	r.order.Created(t.Name)
+
+	if t.Comment != "" {
+		r.script.Add(fmt.Sprintf("COMMENT ON TABLE %s IS %s", quote(t.Name), generate.QuoteString(t.Comment)))
+	}
+	for _, c := range t.Columns {
+		r.columnExtras(t, c)
+	}
+	for _, idx := range t.Indexes {
+		r.createIndex(t, idx)
+	}
+}
+
+func (r *renderer) createTableStatement(t *core.Table) string {
+	var defs []string
+	for _, c := range t.Columns {
+		defs = append(defs, r.columnDefinition(t, c))
+	}
+	for _, con := range t.Constraints {
+		if con.Type == core.ConstraintForeignKey && !r.order.Inline(t, con) {
+			r.deferred = append(r.deferred,
+				fmt.Sprintf("ALTER TABLE %s ADD %s", quote(t.Name), r.constraintDefinition(t, con)))
+			continue
+		}
+		defs = append(defs, r.constraintDefinition(t, con))
+	}
+
+	stmt := "CREATE TABLE " + quote(t.Name) + " (\n  " + strings.Join(defs, ",\n  ") + "\n)"
+	for _, opt := range r.tableOptions(t) {
+		stmt += " " + opt
+	}
+	return stmt
+}
+
+// columnExtras renders the per-column statements that follow CREATE TABLE
+// or ADD COLUMN.
+func (r *renderer) columnExtras(t *core.Table, c *core.Column) {
+	if c.Comment != "" {
+		r.script.Add(columnComment(t, c))
+	}
+	r.createOnUpdateTrigger(t, c)
+}
+
+func columnComment(t *core.Table, c *core.Column) string {
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", quote(t.Name), quote(c.Name), generate.QuoteString(c.Comment))
+}
+
+func options(t *core.Table) *core.OracleTableOptions {
+	if t.Options.Oracle == nil {
+		return &core.OracleTableOptions{}
+	}
+	return t.Options.Oracle
+}
+
+func indexOrganized(t *core.Table) bool {
+	return strings.EqualFold(options(t).Organization, "INDEX")
+}
+
+// tableOptions renders the physical properties that follow the column
+// list: deferred segment creation, the organization, and the segment
+// attributes.
+func (r *renderer) tableOptions(t *core.Table) []string {
+	o := options(t)
+	var opts []string
+	if o.SegmentCreation != "" {
+		opts = append(opts, "SEGMENT CREATION "+strings.ToUpper(o.SegmentCreation))
+	}
+	if indexOrganized(t) {
+		if t.PrimaryKey() == nil {
+			r.script.Warnf("table %s: index-organized tables require a primary key", t.Name)
+		}
+		opts = append(opts, "ORGANIZATION INDEX")
+	}
+	return append(opts, r.segmentAttributes(t)...)
+}
+
+func (r *renderer) segmentAttributes(t *core.Table) []string {
+	o := options(t)
+	var opts []string
+	if o.Pctfree > 0 {
+		opts = append(opts, "PCTFREE "+strconv.Itoa(o.Pctfree))
+	}
+	if o.Pctused > 0 {
+		if indexOrganized(t) {
+			r.script.Warnf("table %s: PCTUSED is not allowed for index-organized tables; it was ignored", t.Name)
+		} else {
+			opts = append(opts, "PCTUSED "+strconv.Itoa(o.Pctused))
+		}
+	}
+	if o.InitTrans > 0 {
+		opts = append(opts, "INITRANS "+strconv.Itoa(o.InitTrans))
+	}
+	if t.Options.Tablespace != "" {
+		opts = append(opts, "TABLESPACE "+quote(t.Options.Tablespace))
+	}
+	if o.Logging != nil {
+		opts = append(opts, logging(*o.Logging))
+	}
+	return opts
+}
+
+func logging(on bool) string {
+	if on {
+		return "LOGGING"
+	}
+	return "NOLOGGING"
+}
+
+func (r *renderer) addColumn(t *core.Table, c *core.Column) {
+	if seq := createSequence(c); seq != "" {
+		r.script.Add(seq)
+	}
+	r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD (%s)", quote(t.Name), r.columnDefinition(t, c)))
+	r.columnExtras(t, c)
+}
+
+// alterTableOptions renders a table option change. Moving a table to
+// another tablespace leaves its indexes unusable, so they are rebuilt.
+func (r *renderer) alterTableOptions(c *diff.TableOptionChange) {
+	t, o := c.New, options(c.New)
+	name := quote(t.Name)
+	for _, f := range c.Fields {
+		switch f.Name {
+		case "tablespace":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s MOVE TABLESPACE %s", name, quote(t.Options.Tablespace)))
+			for _, idx := range t.Indexes {
+				r.script.Add(fmt.Sprintf("ALTER INDEX %s REBUILD", quote(generate.IndexName(t, idx))))
+			}
+		case "oracle.logging":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s %s", name, logging(o.Logging != nil && *o.Logging)))
+		case "oracle.pctfree":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s PCTFREE %d", name, o.Pctfree))
+		case "oracle.pctused":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s PCTUSED %d", name, o.Pctused))
+		case "oracle.init_trans":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s INITRANS %d", name, o.InitTrans))
+		case "oracle.organization":
+			r.script.Warnf("table %s: the organization cannot be changed on an existing table; recreate the table", t.Name)
+		case "oracle.segment_creation":
+			r.script.Warnf("table %s: segment creation only applies when the table is created", t.Name)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package oracle
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// viewChange renders the statements of a view change. Materialized views
+// are created with their refresh policy and dropped with DROP MATERIALIZED
+// VIEW.
+func (r *renderer) viewChange(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddView:
+		if c.View.Materialized {
+			r.createMaterializedView(c.View)
+		} else {
+			r.createView(c.View)
+		}
+	case *diff.DropView:
+		r.script.Add(fmt.Sprintf("DROP %s %s", viewKind(c.View), quote(c.View.Name)))
+	default:
+		return fmt.Errorf("oracle: unsupported change %T", c)
+	}
+	return nil
+}
+
+func viewKind(v *core.View) string {
+	if v.Materialized {
+		return "MATERIALIZED VIEW"
+	}
+	return "VIEW"
+}
+
+// bequeath maps the security of a view to its BEQUEATH clause (Oracle 12c).
+var bequeath = map[core.ViewSecurity]string{
+	core.SecurityDefiner: " BEQUEATH DEFINER",
+	core.SecurityInvoker: " BEQUEATH CURRENT_USER",
+}
+
+// createView renders CREATE VIEW. Oracle only has the cascaded check
+// option.
+func (r *renderer) createView(v *core.View) {
+	stmt := fmt.Sprintf("CREATE VIEW %s%s%s AS %s",
+		quote(v.Name), generate.ViewColumns(v, quote), bequeath[v.Security], generate.ViewDefinition(v))
+	if v.CheckOption == core.CheckOptionLocal {
+		r.script.Warnf("view %s: Oracle only supports cascaded check options; LOCAL is rendered as WITH CHECK OPTION", v.Name)
+	}
+	if v.CheckOption != core.CheckOptionNone {
+		stmt += " WITH CHECK OPTION"
+	}
+	r.script.Add(stmt)
+	r.viewComment(v, "TABLE")
+}
+
+// createMaterializedView renders CREATE MATERIALIZED VIEW with the refresh
+// policy. The refresh method is left to Oracle, which refreshes fast when
+// it can and completely otherwise.
+func (r *renderer) createMaterializedView(v *core.View) {
+	if v.Security == core.SecurityInvoker {
+		r.script.Warnf("view %s: materialized views run with the privileges of their owner; SECURITY INVOKER was ignored", v.Name)
+	}
+	refresh := v.Refresh
+	if refresh == "" {
+		refresh = core.RefreshOnDemand
+	}
+	r.script.Add(fmt.Sprintf("CREATE MATERIALIZED VIEW %s%s REFRESH %s AS %s",
+		quote(v.Name), generate.ViewColumns(v, quote), refresh, generate.ViewDefinition(v)))
+	r.viewComment(v, "MATERIALIZED VIEW")
+}
+
+// viewComment renders the comment of v. Oracle comments on views with
+// COMMENT ON TABLE.
+func (r *renderer) viewComment(v *core.View, kind string) {
+	if v.Comment != "" {
+		r.script.Add(fmt.Sprintf("COMMENT ON %s %s IS %s", kind, quote(v.Name), generate.QuoteString(v.Comment)))
+	}
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"fmt"
+	"slices"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// alterColumn renders the ALTER COLUMN statements for a column change.
+// Unlike MySQL, PostgreSQL alters each attribute separately.
+func (r *renderer) alterColumn(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AlterColumnType:
+		r.alterType(c.Table, c.Old, c.New)
+	case *diff.AlterNullability:
+		action := "DROP NOT NULL"
+		if !c.New.Nullable {
+			action = "SET NOT NULL"
+		}
+		r.script.Add(r.alterColumnStatement(c.Table, c.New, action))
+	case *diff.ChangeDefault:
+		r.alterDefault(c.Table, c.New)
+	case *diff.AlterColumn:
+		for _, f := range c.Fields {
+			r.alterField(c, f)
+		}
+	}
+}
+
+func (r *renderer) alterColumnStatement(t *core.Table, c *core.Column, action string) string {
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", r.tableName(t), quote(c.Name), action)
+}
+
+// alterType changes the type of a column, converting existing values with a
+// cast. Enum columns also get their CHECK constraint or enum type replaced.
+func (r *renderer) alterType(t *core.Table, from, to *core.Column) {
+	if generate.IsEnum(from) && generate.IsEnum(to) && r.nativeEnums {
+		r.alterEnumValues(t, from, to)
+		return
+	}
+	if generate.IsEnum(from) && !r.nativeEnums {
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s", r.tableName(t), quote(enumCheckName(t, from))))
+	}
+	if generate.IsEnum(to) && r.nativeEnums {
+		r.script.Add(r.createEnumType(t, to))
+	}
+	if typ := r.columnType(t, to); typ != r.columnType(t, from) {
+		action := "TYPE " + typ
+		if to.Collate != "" {
+			action += " COLLATE " + quote(to.Collate)
+		}
+		action += " USING " + quote(to.Name) + "::" + typ
+		r.script.Add(r.alterColumnStatement(t, to, action))
+	}
+	if generate.IsEnum(from) && !generate.IsEnum(to) && r.nativeEnums {
+		r.script.Add("DROP TYPE IF EXISTS " + r.qualify(t, enumTypeName(t, from)))
+	}
+	if generate.IsEnum(to) && !r.nativeEnums {
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD %s", r.tableName(t), enumCheck(t, to)))
+	}
+}
+
+// alterEnumValues adds new values to a native enum type. PostgreSQL cannot
+// remove values from an enum, so removals are reported as warnings.
+func (r *renderer) alterEnumValues(t *core.Table, from, to *core.Column) {
+	typ := r.qualify(t, enumTypeName(t, to))
+	for _, v := range to.EnumValues {
+		if !slices.Contains(from.EnumValues, v) {
+			r.script.Add(fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS %s", typ, generate.QuoteString(v)))
+		}
+	}
+	for _, v := range from.EnumValues {
+		if !slices.Contains(to.EnumValues, v) {
+			r.script.Warnf("column %s.%s: enum value %q cannot be removed from type %s", t.Name, to.Name, v, typ)
+		}
+	}
+}
+
+func (r *renderer) alterDefault(t *core.Table, c *core.Column) {
+	if def := defaultClause(c); def != "" {
+		r.script.Add(r.alterColumnStatement(t, c, "SET DEFAULT "+def))
+		return
+	}
+	if !isIdentity(c) {
+		r.script.Add(r.alterColumnStatement(t, c, "DROP DEFAULT"))
+	}
+}
+
+// alterField renders a single changed column attribute. Attributes of other
+// dialects are ignored.
+func (r *renderer) alterField(c *diff.AlterColumn, f diff.FieldChange) {
+	t, col := c.Table, c.New
+	switch f.Name {
+	case "comment":
+		r.script.Add(r.columnComment(t, col))
+	case "collate":
+		r.script.Add(r.alterColumnStatement(t, col, "TYPE "+r.columnType(t, col)+" COLLATE "+quote(collateOrDefault(col.Collate))))
+	case "postgresql.storage":
+		r.alterStorage(t, col)
+	case "postgresql.compression":
+		r.script.Add(r.alterColumnStatement(t, col, "SET COMPRESSION "+compression(col)))
+	case "auto_increment", "identity_generation", "identity_seed", "identity_increment":
+		r.alterIdentity(c)
+	case "sequence_name":
+		r.alterDefault(t, col)
+	case "on_update":
+		r.alterOnUpdate(t, c.Old, col)
+	case "is_generated", "generation_expression", "generation_storage":
+		r.script.Warnf("column %s.%s: the generation expression cannot be altered; recreate the column", t.Name, col.Name)
+	}
+}
+
+// alterIdentity adds, drops, or changes the identity of a column. It may be
+// called once per changed identity field, so it records the column to avoid
+// rendering the same statements twice.
+func (r *renderer) alterIdentity(c *diff.AlterColumn) {
+	t, from, to := c.Table, c.Old, c.New
+	key := t.Name + "." + to.Name
+	if r.identity[key] {
+		return
+	}
+	r.identity[key] = true
+
+	switch {
+	case isIdentity(from) && !isIdentity(to):
+		r.script.Add(r.alterColumnStatement(t, to, "DROP IDENTITY IF EXISTS"))
+	case !isIdentity(from) && isIdentity(to):
+		r.script.Add(r.alterColumnStatement(t, to, "ADD "+identityClause(to)))
+	case isIdentity(to):
+		gen := to.IdentityGeneration
+		if gen == "" {
+			gen = core.IdentityAlways
+		}
+		action := "SET GENERATED " + string(gen)
+		if to.IdentitySeed != 0 {
+			action += fmt.Sprintf(" SET START WITH %d RESTART", to.IdentitySeed)
+		}
+		if to.IdentityIncrement != 0 {
+			action += fmt.Sprintf(" SET INCREMENT BY %d", to.IdentityIncrement)
+		}
+		r.script.Add(r.alterColumnStatement(t, to, action))
+	}
+}
+
+// alterStorage sets the storage mode of a column. A column whose storage
+// option is removed is reset to EXTENDED, the default for TOAST-able types.
+func (r *renderer) alterStorage(t *core.Table, c *core.Column) {
+	if stmt := r.columnStorage(t, c); stmt != "" {
+		r.script.Add(stmt)
+		return
+	}
+	r.script.Add(r.alterColumnStatement(t, c, "SET STORAGE EXTENDED"))
+}
+
+func (r *renderer) alterOnUpdate(t *core.Table, from, to *core.Column) {
+	if from.OnUpdate != nil {
+		r.script.Add(r.dropOnUpdateTrigger(t, from, false)...)
+	}
+	if to.OnUpdate != nil {
+		r.script.Add(r.createOnUpdateTrigger(t, to)...)
+	}
+}
+
+func collateOrDefault(s string) string {
+	if s == "" {
+		return "default"
+	}
+	return s
+}
+
+func compression(c *core.Column) string {
+	if c.PostgreSQL == nil || c.PostgreSQL.Compression == "" {
+		return "DEFAULT"
+	}
+	return c.PostgreSQL.Compression
+}
@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+var typeMapper = generate.TypeMapper{
+	Types: map[string]string{
+		"varchar":   "VARCHAR(%s)",
+		"char":      "CHAR(%s)",
+		"text":      "TEXT",
+		"smallint":  "SMALLINT",
+		"int":       "INTEGER",
+		"integer":   "INTEGER",
+		"bigint":    "BIGINT",
+		"decimal":   "NUMERIC(%s)",
+		"numeric":   "NUMERIC(%s)",
+		"float":     "REAL",
+		"double":    "DOUBLE PRECISION",
+		"boolean":   "BOOLEAN",
+		"bool":      "BOOLEAN",
+		"date":      "DATE",
+		"time":      "TIME",
+		"timestamp": "TIMESTAMPTZ",
+		"datetime":  "TIMESTAMP",
+		"json":      "JSONB",
+		"uuid":      "UUID",
+		"blob":      "BYTEA",
+		"binary":    "BYTEA",
+		"varbinary": "BYTEA",
+	},
+	Fallback: map[core.DataType]string{
+		core.DataTypeString:   "TEXT",
+		core.DataTypeInt:      "INTEGER",
+		core.DataTypeFloat:    "DOUBLE PRECISION",
+		core.DataTypeBoolean:  "BOOLEAN",
+		core.DataTypeDatetime: "TIMESTAMP",
+		core.DataTypeJSON:     "JSONB",
+		core.DataTypeUUID:     "UUID",
+		core.DataTypeBinary:   "BYTEA",
+	},
+	StripSuffix: true,
+}
+
+// isIdentity reports whether c is rendered as an identity column.
+func isIdentity(c *core.Column) bool {
+	return c.AutoIncrement || c.IdentityGeneration != ""
+}
+
+// columnType returns the PostgreSQL type for a column of table t.
+func (r *renderer) columnType(t *core.Table, c *core.Column) string {
+	if !generate.IsEnum(c) {
+		return typeMapper.ColumnType(c)
+	}
+	if r.nativeEnums {
+		return r.qualify(t, enumTypeName(t, c))
+	}
+	return "TEXT"
+}
+
+// enumTypeName returns the name of the enum type created for a column.
+func enumTypeName(t *core.Table, c *core.Column) string {
+	return t.Name + "_" + c.Name
+}
+
+// enumCheckName returns the name of the CHECK constraint that emulates an
+// enum column.
+func enumCheckName(t *core.Table, c *core.Column) string {
+	return "chk_" + t.Name + "_" + c.Name + "_enum"
+}
+
+func enumCheck(t *core.Table, c *core.Column) string {
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s IN (%s))",
+		quote(enumCheckName(t, c)), quote(c.Name), generate.QuoteList(c.EnumValues, generate.QuoteString))
+}
+
+// createEnumType renders the CREATE TYPE statement for a native enum column.
+func (r *renderer) createEnumType(t *core.Table, c *core.Column) string {
+	return fmt.Sprintf("CREATE TYPE %s AS ENUM (%s)",
+		r.qualify(t, enumTypeName(t, c)), generate.QuoteList(c.EnumValues, generate.QuoteString))
+}
+
+// columnDefinition renders a column as it appears in CREATE TABLE and in
+// ADD COLUMN clauses.
+func (r *renderer) columnDefinition(t *core.Table, c *core.Column) string {
+	parts := []string{quote(c.Name), r.columnType(t, c)}
+	if c.PostgreSQL != nil && c.PostgreSQL.Compression != "" {
+		parts = append(parts, "COMPRESSION "+c.PostgreSQL.Compression)
+	}
+	if c.Collate != "" {
+		parts = append(parts, "COLLATE "+quote(c.Collate))
+	}
+	parts = append(parts, r.generationClauses(t, c)...)
+	if !c.Nullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if def := defaultClause(c); def != "" {
+		parts = append(parts, "DEFAULT "+def)
+	}
+	if generate.IsEnum(c) && !r.nativeEnums {
+		parts = append(parts, enumCheck(t, c))
+	}
+	r.warnUnsupported(t, c)
+	return strings.Join(parts, " ")
+}
+
+// generationClauses renders the computed-column or identity clause.
+func (r *renderer) generationClauses(t *core.Table, c *core.Column) []string {
+	switch {
+	case c.IsGenerated:
+		if c.GenerationStorage == core.GenerationVirtual {
+			r.script.Warnf("column %s.%s: PostgreSQL only supports STORED generated columns", t.Name, c.Name)
+		}
+		return []string{"GENERATED ALWAYS AS (" + c.GenerationExpression + ") STORED"}
+	case isIdentity(c):
+		return []string{identityClause(c)}
+	default:
+		return nil
+	}
+}
+
+func identityClause(c *core.Column) string {
+	gen := c.IdentityGeneration
+	if gen == "" {
+		gen = core.IdentityAlways
+	}
+	clause := "GENERATED " + string(gen) + " AS IDENTITY"
+	if opts := identityOptions(c); opts != "" {
+		clause += " (" + opts + ")"
+	}
+	return clause
+}
+
+func identityOptions(c *core.Column) string {
+	var opts []string
+	if c.IdentitySeed != 0 {
+		opts = append(opts, "START WITH "+strconv.FormatInt(c.IdentitySeed, 10))
+	}
+	if c.IdentityIncrement != 0 {
+		opts = append(opts, "INCREMENT BY "+strconv.FormatInt(c.IdentityIncrement, 10))
+	}
+	return strings.Join(opts, " ")
+}
+
+// defaultClause renders the DEFAULT value of c, or "" when it has none.
+// Columns bound to a named sequence default to its next value.
+func defaultClause(c *core.Column) string {
+	if c.IsGenerated || isIdentity(c) {
+		return ""
+	}
+	if c.DefaultValue == nil {
+		if c.SequenceName != "" {
+			return "nextval(" + generate.QuoteString(c.SequenceName) + ")"
+		}
+		return ""
+	}
+	return defaultValue(c, *c.DefaultValue)
+}
+
+func defaultValue(c *core.Column, v string) string {
+	v = strings.TrimSpace(v)
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return generate.QuoteString(v)
+	case generate.DefaultBoolean:
+		return strings.ToUpper(v)
+	case generate.DefaultNumber:
+		if c.Type == core.DataTypeBoolean {
+			return strings.ToUpper(strconv.FormatBool(generate.BoolDefault(v)))
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// warnUnsupported records warnings for column attributes that PostgreSQL
+// has no equivalent for.
+func (r *renderer) warnUnsupported(t *core.Table, c *core.Column) {
+	if c.Charset != "" {
+		r.script.Warnf("column %s.%s: PostgreSQL has no per-column character set; %s was ignored", t.Name, c.Name, c.Charset)
+	}
+	if c.Invisible {
+		r.script.Warnf("column %s.%s: PostgreSQL does not support invisible columns", t.Name, c.Name)
+	}
+}
+
+// columnStorage renders the ALTER TABLE statement that sets the storage
+// mode of a column. STORAGE is only accepted in CREATE TABLE since
+// PostgreSQL 16, so it is always applied separately.
+func (r *renderer) columnStorage(t *core.Table, c *core.Column) string {
+	if c.PostgreSQL == nil || c.PostgreSQL.Storage == "" {
+		return ""
+	}
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET STORAGE %s",
+		r.tableName(t), quote(c.Name), strings.ToUpper(c.PostgreSQL.Storage))
+}
+
+// createSequence renders the statement that creates a sequence a column is
+// bound to, unless the column is an identity column.
+func createSequence(c *core.Column) string {
+	if c.SequenceName == "" || isIdentity(c) {
+		return ""
+	}
+	return "CREATE SEQUENCE IF NOT EXISTS " + qualified(c.SequenceName)
+}
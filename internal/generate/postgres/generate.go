@@ -0,0 +1,165 @@
+// Package postgres contains the DDL generator for PostgreSQL. Portable types
+// are mapped to their PostgreSQL equivalents, auto-increment columns become
+// identity columns, and ON UPDATE clauses are emulated with triggers.
+package postgres
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+func init() {
+	generate.Register(core.DialectPostgreSQL, New)
+}
+
+// Generator renders PostgreSQL DDL.
+type Generator struct {
+	// NativeEnums renders enum columns as a dedicated CREATE TYPE … AS ENUM
+	// instead of TEXT with a CHECK constraint.
+	NativeEnums bool
+}
+
+func New() generate.Generator {
+	return &Generator{}
+}
+
+// Generate renders a CREATE TABLE statement for every table and a CREATE
+// VIEW statement for every view in db, ordered so that referenced tables
+// and views are created first.
+func (g *Generator) Generate(db *core.Database) (*generate.Script, error) {
+	return g.GenerateChanges(diff.Databases(nil, db))
+}
+
+// GenerateChanges renders the statements that apply cs.
+func (g *Generator) GenerateChanges(cs *diff.ChangeSet) (*generate.Script, error) {
+	r := &renderer{
+		script:      &generate.Script{},
+		order:       generate.NewCreateOrder(cs),
+		schemas:     tableSchemas(cs.From, cs.To),
+		nativeEnums: g.NativeEnums,
+		identity:    make(map[string]bool),
+	}
+	for _, c := range cs.Changes {
+		if err := r.change(c); err != nil {
+			return nil, err
+		}
+	}
+	r.script.Add(r.deferred...)
+	return r.script, nil
+}
+
+// renderer collects the PostgreSQL statements of one change set. Tables
+// are named with their schema, and the identity of a column is altered at
+// most once however many of its attributes change.
+type renderer struct {
+	script *generate.Script
+	order  *generate.CreateOrder
+	// schemas maps table names to their PostgreSQL schema, so that foreign
+	// keys can reference tables in other schemas.
+	schemas     map[string]string
+	nativeEnums bool
+	// deferred holds foreign keys of new tables that reference a table
+	// created later in the script.
+	deferred []string
+	// identity tracks columns whose identity was already altered.
+	identity map[string]bool
+}
+
+func (r *renderer) change(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddTable:
+		r.createTable(c.Table)
+	case *diff.DropTable:
+		r.dropTable(c.Table)
+	case *diff.ChangeTableComment:
+		r.script.Add(fmt.Sprintf("COMMENT ON TABLE %s IS %s", r.tableName(c.Table), comment(c.New)))
+	case *diff.TableOptionChange:
+		r.alterTableOptions(c)
+	case *diff.AddColumn:
+		r.addColumn(c.Table, c.Column)
+	case *diff.DropColumn:
+		r.dropColumn(c.Table, c.Column)
+	case *diff.AlterColumnType, *diff.AlterNullability, *diff.ChangeDefault, *diff.AlterColumn:
+		r.alterColumn(c)
+	case *diff.AddConstraint, *diff.DropConstraint, *diff.AddIndex, *diff.DropIndex:
+		r.keyChange(c)
+	default:
+		return r.viewChange(c)
+	}
+	return nil
+}
+
+func (r *renderer) keyChange(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AddConstraint:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD %s", r.tableName(c.Table), r.constraintDefinition(c.Table, c.Constraint)))
+	case *diff.DropConstraint:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s",
+			r.tableName(c.Table), quote(generate.ConstraintName(c.Table, c.Constraint))))
+	case *diff.AddIndex:
+		r.createIndex(c.Table, c.Index)
+	case *diff.DropIndex:
+		r.script.Add("DROP INDEX " + r.qualify(c.Table, generate.IndexName(c.Table, c.Index)))
+	}
+}
+
+func tableSchemas(dbs ...*core.Database) map[string]string {
+	schemas := make(map[string]string)
+	for _, db := range dbs {
+		if db == nil {
+			continue
+		}
+		for _, t := range db.Tables {
+			schemas[t.Name] = schema(t)
+		}
+	}
+	return schemas
+}
+
+func schema(t *core.Table) string {
+	if t.Options.PostgreSQL == nil {
+		return ""
+	}
+	return t.Options.PostgreSQL.Schema
+}
+
+// tableName returns the schema-qualified, quoted name of t.
+func (r *renderer) tableName(t *core.Table) string {
+	return r.qualify(t, t.Name)
+}
+
+// qualify quotes name and prefixes it with the schema of t.
+func (r *renderer) qualify(t *core.Table, name string) string {
+	if s := schema(t); s != "" {
+		return quote(s) + "." + quote(name)
+	}
+	return quote(name)
+}
+
+// refName returns the schema-qualified, quoted name of a referenced table.
+func (r *renderer) refName(table string) string {
+	if s := r.schemas[table]; s != "" {
+		return quote(s) + "." + quote(table)
+	}
+	return quote(table)
+}
+
+func quote(name string) string {
+	return generate.QuoteIdentifier(name)
+}
+
+// qualified quotes a name that may carry a schema, such as a parent table
+// of INHERITS.
+func qualified(name string) string {
+	return generate.QuoteQualified(name, quote)
+}
+
+func comment(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+	return generate.QuoteString(s)
+}
@@ -0,0 +1,269 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+	"smf/internal/pars/toml"
+)
+
+func parse(t *testing.T, schema string) *core.Database {
+	t.Helper()
+	db, err := toml.NewParser().Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+	return db
+}
+
+const accountsSchema = `
+[database]
+name = "app"
+dialect = "postgresql"
+
+[[tables]]
+name = "accounts"
+comment = "customer accounts"
+
+  [tables.options]
+  tablespace = "fast"
+
+  [tables.options.postgresql]
+  schema = "billing"
+  unlogged = true
+  fillfactor = 70
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+  auto_increment = true
+  identity_generation = "BY DEFAULT"
+
+  [[tables.columns]]
+  name = "plan"
+  type = "enum"
+  values = ["free", "pro"]
+  default = "free"
+
+  [[tables.columns]]
+  name = "payload"
+  type = "json"
+  nullable = true
+
+    [tables.columns.postgresql]
+    storage = "external"
+    compression = "lz4"
+
+  [[tables.columns]]
+  name = "updated_at"
+  type = "timestamp"
+  default = "CURRENT_TIMESTAMP"
+  on_update = "CURRENT_TIMESTAMP"
+`
+
+func TestGenerateCreateTable(t *testing.T) {
+	t.Parallel()
+	script, err := New().Generate(parse(t, accountsSchema))
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 5)
+
+	assert.Equal(t, `CREATE UNLOGGED TABLE "billing"."accounts" (`+"\n"+
+		`  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,`+"\n"+
+		`  "plan" TEXT NOT NULL DEFAULT 'free' CONSTRAINT "chk_accounts_plan_enum" CHECK ("plan" IN ('free', 'pro')),`+"\n"+
+		`  "payload" JSONB COMPRESSION lz4,`+"\n"+
+		`  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,`+"\n"+
+		`  CONSTRAINT "pk_accounts" PRIMARY KEY ("id")`+"\n"+
+		`) WITH (fillfactor=70) TABLESPACE "fast"`,
+		script.Statements[0])
+	assert.Equal(t, `COMMENT ON TABLE "billing"."accounts" IS 'customer accounts'`, script.Statements[1])
+	assert.Equal(t, `ALTER TABLE "billing"."accounts" ALTER COLUMN "payload" SET STORAGE EXTERNAL`, script.Statements[2])
+	assert.Contains(t, script.Statements[3], `CREATE OR REPLACE FUNCTION "billing"."accounts_updated_at_on_update"()`)
+	assert.Contains(t, script.Statements[3], `NEW."updated_at" := CURRENT_TIMESTAMP;`)
+	assert.Equal(t, `CREATE TRIGGER "accounts_updated_at_on_update" BEFORE UPDATE ON "billing"."accounts" `+
+		`FOR EACH ROW EXECUTE FUNCTION "billing"."accounts_updated_at_on_update"()`, script.Statements[4])
+}
+
+func TestGenerateNativeEnums(t *testing.T) {
+	t.Parallel()
+	g := &Generator{NativeEnums: true}
+	script, err := g.Generate(parse(t, accountsSchema))
+	require.NoError(t, err)
+
+	assert.Equal(t, `CREATE TYPE "billing"."accounts_plan" AS ENUM ('free', 'pro')`, script.Statements[0])
+	assert.Contains(t, script.Statements[1], `"plan" "billing"."accounts_plan" NOT NULL DEFAULT 'free',`)
+}
+
+func TestGeneratePartitionAndInherits(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{
+		Name:    "events",
+		Columns: []*core.Column{{Name: "at", PortableType: "timestamp", Type: core.DataTypeDatetime}},
+		Options: core.TableOptions{PostgreSQL: &core.PostgreSQLTableOptions{
+			Inherits:    []string{"base_events"},
+			PartitionBy: "RANGE (at)",
+		}},
+	}
+
+	script, err := New().Generate(&core.Database{Tables: []*core.Table{tbl}})
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE \"events\" (\n  \"at\" TIMESTAMPTZ NOT NULL\n) "+
+		`INHERITS ("base_events") PARTITION BY RANGE (at)`, script.Statements[0])
+}
+
+func TestGenerateColumnTypes(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"varchar(80)":   "VARCHAR(80)",
+		"int unsigned":  "INTEGER",
+		"decimal(10,2)": "NUMERIC(10,2)",
+		"double":        "DOUBLE PRECISION",
+		"datetime":      "TIMESTAMP",
+		"uuid":          "UUID",
+		"varbinary(16)": "BYTEA",
+		"boolean":       "BOOLEAN",
+		"timestamp":     "TIMESTAMPTZ",
+		"json":          "JSONB",
+		"varchar":       "VARCHAR",
+		"interval":      "INTERVAL",
+	}
+	r := &renderer{script: &generate.Script{}}
+	for portable, want := range tests {
+		assert.Equal(t, want, r.columnType(&core.Table{}, &core.Column{PortableType: portable}), portable)
+	}
+	assert.Equal(t, "TIMESTAMP", r.columnType(&core.Table{}, &core.Column{Type: core.DataTypeDatetime}), "injected timestamp")
+}
+
+func TestGenerateChanges(t *testing.T) {
+	t.Parallel()
+	from := parse(t, accountsSchema)
+	to := parse(t, strings.NewReplacer(
+		`values = ["free", "pro"]`, `values = ["free", "pro", "team"]`,
+		`identity_generation = "BY DEFAULT"`, `identity_generation = "ALWAYS"`,
+		`fillfactor = 70`, `fillfactor = 90`,
+		`  name = "payload"
+  type = "json"
+  nullable = true`, `  name = "payload"
+  type = "json"`,
+	).Replace(accountsSchema))
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "billing"."accounts" SET (fillfactor=90)`,
+		`ALTER TABLE "billing"."accounts" ALTER COLUMN "id" SET GENERATED ALWAYS`,
+		`ALTER TABLE "billing"."accounts" DROP CONSTRAINT IF EXISTS "chk_accounts_plan_enum"`,
+		`ALTER TABLE "billing"."accounts" ADD CONSTRAINT "chk_accounts_plan_enum" CHECK ("plan" IN ('free', 'pro', 'team'))`,
+		`ALTER TABLE "billing"."accounts" ALTER COLUMN "payload" SET NOT NULL`,
+	}, script.Statements)
+}
+
+func TestGenerateNativeEnumValues(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "accounts"}
+	from := &core.Column{Name: "plan", Type: core.DataTypeEnum, EnumValues: []string{"free", "pro"}}
+	to := &core.Column{Name: "plan", Type: core.DataTypeEnum, EnumValues: []string{"free", "team"}}
+
+	g := &Generator{NativeEnums: true}
+	script, err := g.GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{
+		&diff.AlterColumnType{Table: tbl, Old: from, New: to},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`ALTER TYPE "accounts_plan" ADD VALUE IF NOT EXISTS 'team'`}, script.Statements)
+	assert.Len(t, script.Warnings, 1)
+}
+
+func TestGenerateIndexes(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "docs"}
+	cs := &diff.ChangeSet{Changes: []diff.Change{
+		&diff.AddIndex{Table: tbl, Index: &core.Index{
+			Name: "idx_docs_body", Type: core.IndexTypeGIN, Comment: "search",
+			Columns: []core.ColumnIndex{{Name: "body"}},
+		}},
+		&diff.AddIndex{Table: tbl, Index: &core.Index{
+			Unique: true, Type: core.IndexTypeBTree,
+			Columns: []core.ColumnIndex{{Name: "slug", Length: 10, Order: core.SortDesc}},
+		}},
+		&diff.AddIndex{Table: tbl, Index: &core.Index{Type: core.IndexTypeFullText, Columns: []core.ColumnIndex{{Name: "body"}}}},
+		&diff.DropIndex{Table: tbl, Index: &core.Index{Name: "idx_old"}},
+	}}
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`CREATE INDEX "idx_docs_body" ON "docs" USING gin ("body")`,
+		`COMMENT ON INDEX "idx_docs_body" IS 'search'`,
+		`CREATE UNIQUE INDEX "idx_docs_slug" ON "docs" ("slug" DESC)`,
+		`DROP INDEX "idx_old"`,
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 2)
+}
+
+func TestGeneratePartialIndex(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "events"}
+	cs := &diff.ChangeSet{Changes: []diff.Change{
+		&diff.AddIndex{Table: tbl, Index: &core.Index{
+			Name: "idx_events_live", Unique: true, Type: core.IndexTypeBTree, Where: "deleted_at IS NULL",
+			Columns: []core.ColumnIndex{{Name: "slug"}},
+		}},
+		&diff.AddIndex{Table: tbl, Index: &core.Index{
+			Name: "idx_events_created", Type: core.IndexTypeBRIN,
+			Columns: []core.ColumnIndex{{Name: "created_at"}},
+		}},
+	}}
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`CREATE UNIQUE INDEX "idx_events_live" ON "events" ("slug") WHERE deleted_at IS NULL`,
+		`CREATE INDEX "idx_events_created" ON "events" USING brin ("created_at")`,
+	}, script.Statements)
+	assert.Empty(t, script.Warnings)
+}
+
+func TestGenerateSecurityInvokerView(t *testing.T) {
+	t.Parallel()
+	view := &core.View{
+		Name:        "pro_accounts",
+		Definition:  "SELECT id, plan FROM billing.accounts WHERE plan = 'pro';",
+		Columns:     []string{"account_id", "plan"},
+		Security:    core.SecurityInvoker,
+		CheckOption: core.CheckOptionLocal,
+	}
+	script, err := New().GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{
+		&diff.DropView{View: view}, &diff.AddView{View: view},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`DROP VIEW "pro_accounts"`,
+		`CREATE VIEW "pro_accounts" ("account_id", "plan") WITH (security_invoker = true) AS ` +
+			`SELECT id, plan FROM billing.accounts WHERE plan = 'pro' WITH LOCAL CHECK OPTION`,
+	}, script.Statements)
+	assert.Empty(t, script.Warnings)
+}
+
+// TestGenerateMaterializedView checks that the options PostgreSQL has no
+// counterpart for are dropped with a warning each.
+func TestGenerateMaterializedView(t *testing.T) {
+	t.Parallel()
+	view := &core.View{
+		Name: "plan_counts", Definition: "SELECT plan, count(*) AS n FROM billing.accounts GROUP BY plan",
+		Materialized: true, Refresh: core.RefreshOnCommit, Security: core.SecurityInvoker, Comment: "accounts per plan",
+	}
+	script, err := New().GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{
+		&diff.DropView{View: view}, &diff.AddView{View: view},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`DROP MATERIALIZED VIEW "plan_counts"`,
+		`CREATE MATERIALIZED VIEW "plan_counts" AS SELECT plan, count(*) AS n FROM billing.accounts GROUP BY plan`,
+		`COMMENT ON MATERIALIZED VIEW "plan_counts" IS 'accounts per plan'`,
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 2)
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// constraintDefinition renders a named table constraint as it appears in
+// CREATE TABLE and in ALTER TABLE ... ADD clauses.
+func (r *renderer) constraintDefinition(t *core.Table, con *core.Constraint) string {
+	def := "CONSTRAINT " + quote(generate.ConstraintName(t, con)) + " "
+	cols := generate.QuoteList(con.Columns, quote)
+	switch con.Type {
+	case core.ConstraintPrimaryKey:
+		return def + "PRIMARY KEY (" + cols + ")"
+	case core.ConstraintUnique:
+		return def + "UNIQUE (" + cols + ")"
+	case core.ConstraintCheck:
+		if !generate.Enforced(con) {
+			r.script.Warnf("table %s: CHECK constraint %s is NOT ENFORCED, which PostgreSQL does not support; it will be enforced",
+				t.Name, generate.ConstraintName(t, con))
+		}
+		return def + "CHECK (" + con.CheckExpression + ")"
+	default:
+		return def + r.foreignKey(con)
+	}
+}
+
+func (r *renderer) foreignKey(con *core.Constraint) string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		generate.QuoteList(con.Columns, quote),
+		r.refName(con.ReferencedTable),
+		generate.QuoteList(con.ReferencedColumns, quote))
+	if con.OnDelete != core.RefActionNone {
+		def += " ON DELETE " + string(con.OnDelete)
+	}
+	if con.OnUpdate != core.RefActionNone {
+		def += " ON UPDATE " + string(con.OnUpdate)
+	}
+	return def
+}
+
+// indexMethods maps index types to PostgreSQL access methods.
+var indexMethods = map[core.IndexType]string{
+	core.IndexTypeBTree:   "btree",
+	core.IndexTypeHash:    "hash",
+	core.IndexTypeGIN:     "gin",
+	core.IndexTypeGiST:    "gist",
+	core.IndexTypeSpatial: "gist",
+	core.IndexTypeBRIN:    "brin",
+}
+
+// createIndex renders CREATE INDEX for idx together with its comment. A
+// partial index keeps its WHERE predicate.
+func (r *renderer) createIndex(t *core.Table, idx *core.Index) {
+	name := generate.IndexName(t, idx)
+	if idx.Type == core.IndexTypeFullText {
+		r.script.Warnf("table %s: FULLTEXT index %s was skipped; use a GIN index on a tsvector expression instead", t.Name, name)
+		return
+	}
+	if idx.Visibility == core.IndexInvisible {
+		r.script.Warnf("table %s: PostgreSQL does not support invisible indexes; %s is created visible", t.Name, name)
+	}
+
+	stmt := "CREATE "
+	if idx.Unique {
+		stmt += "UNIQUE "
+	}
+	stmt += "INDEX " + quote(name) + " ON " + r.tableName(t)
+	if method, ok := indexMethods[idx.Type]; ok && idx.Type != core.IndexTypeBTree {
+		stmt += " USING " + method
+	}
+	stmt += " (" + r.indexColumns(t, idx) + ")"
+	if idx.Where != "" {
+		stmt += " WHERE " + idx.Where
+	}
+	r.script.Add(stmt)
+
+	if idx.Comment != "" {
+		r.script.Add(fmt.Sprintf("COMMENT ON INDEX %s IS %s", r.qualify(t, name), comment(idx.Comment)))
+	}
+}
+
+func (r *renderer) indexColumns(t *core.Table, idx *core.Index) string {
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		if c.Length > 0 {
+			r.script.Warnf("table %s: PostgreSQL does not support prefix indexes; the length of %s was ignored", t.Name, c.Name)
+		}
+		cols[i] = generate.KeyPart(c, quote)
+		if c.Order == core.SortDesc {
+			cols[i] += " DESC"
+		}
+	}
+	return strings.Join(cols, ", ")
+}
@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// createTable renders CREATE TABLE for t together with the objects it
+// depends on (sequences, enum types) and the statements PostgreSQL does not
+// accept inline (comments, storage modes, indexes, and ON UPDATE triggers).
+func (r *renderer) createTable(t *core.Table) {
+	for _, c := range t.Columns {
+		if seq := createSequence(c); seq != "" {
+			r.script.Add(seq)
+		}
+		if generate.IsEnum(c) && r.nativeEnums {
+			r.script.Add(r.createEnumType(t, c))
+		}
+	}
+
+	r.script.Add(r.createTableStatement(t))
+	r.order.Created(t.Name)
+
+	if t.Comment != "" {
+		r.script.Add(fmt.Sprintf("COMMENT ON TABLE %s IS %s", r.tableName(t), comment(t.Comment)))
+	}
+	for _, c := range t.Columns {
+		r.columnExtras(t, c)
+	}
+	for _, idx := range t.Indexes {
+		r.createIndex(t, idx)
+	}
+}
+
+func (r *renderer) createTableStatement(t *core.Table) string {
+	var defs []string
+	for _, c := range t.Columns {
+		defs = append(defs, r.columnDefinition(t, c))
+	}
+	for _, con := range t.Constraints {
+		if con.Type == core.ConstraintForeignKey && !r.order.Inline(t, con) {
+			r.deferred = append(r.deferred,
+				fmt.Sprintf("ALTER TABLE %s ADD %s", r.tableName(t), r.constraintDefinition(t, con)))
+			continue
+		}
+		defs = append(defs, r.constraintDefinition(t, con))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE ")
+	if o := t.Options.PostgreSQL; o != nil && o.Unlogged {
+		sb.WriteString("UNLOGGED ")
+	}
+	sb.WriteString("TABLE ")
+	sb.WriteString(r.tableName(t))
+	sb.WriteString(" (\n  ")
+	sb.WriteString(strings.Join(defs, ",\n  "))
+	sb.WriteString("\n)")
+	for _, opt := range tableOptions(t) {
+		sb.WriteString(" ")
+		sb.WriteString(opt)
+	}
+	return sb.String()
+}
+
+// columnExtras renders the per-column statements that follow CREATE TABLE
+// or ADD COLUMN.
+func (r *renderer) columnExtras(t *core.Table, c *core.Column) {
+	if c.Comment != "" {
+		r.script.Add(r.columnComment(t, c))
+	}
+	if stmt := r.columnStorage(t, c); stmt != "" {
+		r.script.Add(stmt)
+	}
+	if c.OnUpdate != nil {
+		r.script.Add(r.createOnUpdateTrigger(t, c)...)
+	}
+}
+
+func (r *renderer) columnComment(t *core.Table, c *core.Column) string {
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", r.tableName(t), quote(c.Name), comment(c.Comment))
+}
+
+// tableOptions renders the clauses that follow the column list, in the
+// order PostgreSQL expects them.
+func tableOptions(t *core.Table) []string {
+	var opts []string
+	if o := t.Options.PostgreSQL; o != nil {
+		if len(o.Inherits) > 0 {
+			opts = append(opts, "INHERITS ("+generate.QuoteList(o.Inherits, qualified)+")")
+		}
+		if o.PartitionBy != "" {
+			opts = append(opts, "PARTITION BY "+o.PartitionBy)
+		}
+		if o.Fillfactor != 0 {
+			opts = append(opts, "WITH (fillfactor="+strconv.Itoa(o.Fillfactor)+")")
+		}
+	}
+	if t.Options.Tablespace != "" {
+		opts = append(opts, "TABLESPACE "+quote(t.Options.Tablespace))
+	}
+	return opts
+}
+
+func (r *renderer) dropTable(t *core.Table) {
+	r.script.Add("DROP TABLE " + r.tableName(t))
+	for _, c := range t.Columns {
+		if c.OnUpdate != nil {
+			r.script.Add(r.dropOnUpdateTrigger(t, c, true)...)
+		}
+		if generate.IsEnum(c) && r.nativeEnums {
+			r.script.Add("DROP TYPE IF EXISTS " + r.qualify(t, enumTypeName(t, c)))
+		}
+	}
+}
+
+func (r *renderer) addColumn(t *core.Table, c *core.Column) {
+	if seq := createSequence(c); seq != "" {
+		r.script.Add(seq)
+	}
+	if generate.IsEnum(c) && r.nativeEnums {
+		r.script.Add(r.createEnumType(t, c))
+	}
+	r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", r.tableName(t), r.columnDefinition(t, c)))
+	r.columnExtras(t, c)
+}
+
+func (r *renderer) dropColumn(t *core.Table, c *core.Column) {
+	if c.OnUpdate != nil {
+		r.script.Add(r.dropOnUpdateTrigger(t, c, false)...)
+	}
+	r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", r.tableName(t), quote(c.Name)))
+	if generate.IsEnum(c) && r.nativeEnums {
+		r.script.Add("DROP TYPE IF EXISTS " + r.qualify(t, enumTypeName(t, c)))
+	}
+}
+
+// alterTableOptions renders the PostgreSQL option fields listed in c.
+// Options of other dialects are ignored.
+func (r *renderer) alterTableOptions(c *diff.TableOptionChange) {
+	oldOpts, newOpts := pgOptions(c.Old), pgOptions(c.New)
+	for _, f := range c.Fields {
+		switch f.Name {
+		case "postgresql.schema":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s SET SCHEMA %s", r.tableName(c.Old), quote(schemaOrPublic(newOpts.Schema))))
+		case "postgresql.unlogged":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s SET %s", r.tableName(c.New), logged(newOpts.Unlogged)))
+		case "postgresql.fillfactor":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s %s", r.tableName(c.New), fillfactor(newOpts.Fillfactor)))
+		case "tablespace":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s SET TABLESPACE %s",
+				r.tableName(c.New), quote(tablespaceOrDefault(c.New.Options.Tablespace))))
+		case "postgresql.inherits":
+			r.alterInherits(c.New, oldOpts.Inherits, newOpts.Inherits)
+		case "postgresql.partition_by":
+			r.script.Warnf("table %s: the partition key of an existing table cannot be changed; recreate the table", c.New.Name)
+		}
+	}
+}
+
+func (r *renderer) alterInherits(t *core.Table, from, to []string) {
+	for _, parent := range from {
+		if !slices.Contains(to, parent) {
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s NO INHERIT %s", r.tableName(t), qualified(parent)))
+		}
+	}
+	for _, parent := range to {
+		if !slices.Contains(from, parent) {
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s INHERIT %s", r.tableName(t), qualified(parent)))
+		}
+	}
+}
+
+func pgOptions(t *core.Table) *core.PostgreSQLTableOptions {
+	if t.Options.PostgreSQL == nil {
+		return &core.PostgreSQLTableOptions{}
+	}
+	return t.Options.PostgreSQL
+}
+
+func logged(unlogged bool) string {
+	if unlogged {
+		return "UNLOGGED"
+	}
+	return "LOGGED"
+}
+
+func fillfactor(v int) string {
+	if v == 0 {
+		return "RESET (fillfactor)"
+	}
+	return "SET (fillfactor=" + strconv.Itoa(v) + ")"
+}
+
+func schemaOrPublic(s string) string {
+	if s == "" {
+		return "public"
+	}
+	return s
+}
+
+func tablespaceOrDefault(s string) string {
+	if s == "" {
+		return "pg_default"
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+)
+
+// PostgreSQL has no ON UPDATE column clause. A column with OnUpdate gets a
+// BEFORE UPDATE trigger that assigns the expression to the new row.
+
+func onUpdateName(t *core.Table, c *core.Column) string {
+	return t.Name + "_" + c.Name + "_on_update"
+}
+
+// createOnUpdateTrigger renders the trigger function and trigger that
+// emulate ON UPDATE for c.
+func (r *renderer) createOnUpdateTrigger(t *core.Table, c *core.Column) []string {
+	fn := r.qualify(t, onUpdateName(t, c))
+	return []string{
+		fmt.Sprintf("CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $$\n"+
+			"BEGIN\n"+
+			"  NEW.%s := %s;\n"+
+			"  RETURN NEW;\n"+
+			"END;\n"+
+			"$$", fn, quote(c.Name), *c.OnUpdate),
+		fmt.Sprintf("CREATE TRIGGER %s BEFORE UPDATE ON %s FOR EACH ROW EXECUTE FUNCTION %s()",
+			quote(onUpdateName(t, c)), r.tableName(t), fn),
+	}
+}
+
+// dropOnUpdateTrigger renders the statements that remove the trigger and
+// function created by createOnUpdateTrigger. When the table itself is
+// dropped, only the function needs to be removed.
+func (r *renderer) dropOnUpdateTrigger(t *core.Table, c *core.Column, tableDropped bool) []string {
+	var stmts []string
+	if !tableDropped {
+		stmts = append(stmts, fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", quote(onUpdateName(t, c)), r.tableName(t)))
+	}
+	return append(stmts, fmt.Sprintf("DROP FUNCTION IF EXISTS %s()", r.qualify(t, onUpdateName(t, c))))
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// viewChange renders the statements of a view change. A materialized view
+// is dropped with DROP MATERIALIZED VIEW.
+func (r *renderer) viewChange(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddView:
+		r.createView(c.View)
+	case *diff.DropView:
+		r.script.Add(fmt.Sprintf("DROP %s %s", viewKind(c.View), quote(c.View.Name)))
+	default:
+		return fmt.Errorf("postgres: unsupported change %T", c)
+	}
+	return nil
+}
+
+func viewKind(v *core.View) string {
+	if v.Materialized {
+		return "MATERIALIZED VIEW"
+	}
+	return "VIEW"
+}
+
+// createView renders CREATE VIEW or CREATE MATERIALIZED VIEW. SECURITY
+// INVOKER maps to the security_invoker option of PostgreSQL 15. A
+// materialized view is only refreshed by REFRESH MATERIALIZED VIEW and
+// always runs with the privileges of its owner.
+func (r *renderer) createView(v *core.View) {
+	stmt := fmt.Sprintf("CREATE %s %s%s", viewKind(v), quote(v.Name), generate.ViewColumns(v, quote))
+	switch {
+	case v.Materialized && v.Security == core.SecurityInvoker:
+		r.script.Warnf("view %s: materialized views cannot use SECURITY INVOKER; it was ignored", v.Name)
+	case v.Security == core.SecurityInvoker:
+		stmt += " WITH (security_invoker = true)"
+	}
+	if v.Refresh == core.RefreshOnCommit {
+		r.script.Warnf("view %s: PostgreSQL only refreshes materialized views on demand; ON COMMIT was ignored", v.Name)
+	}
+	stmt += " AS " + generate.ViewDefinition(v)
+	if v.CheckOption != core.CheckOptionNone {
+		stmt += fmt.Sprintf(" WITH %s CHECK OPTION", v.CheckOption)
+	}
+	r.script.Add(stmt)
+
+	if v.Comment != "" {
+		r.script.Add(fmt.Sprintf("COMMENT ON %s %s IS %s", viewKind(v), quote(v.Name), comment(v.Comment)))
+	}
+}
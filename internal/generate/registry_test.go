@@ -0,0 +1,34 @@
+package generate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+	"smf/internal/generate/db2"
+	"smf/internal/generate/mssql"
+	"smf/internal/generate/oracle"
+	"smf/internal/generate/postgres"
+	"smf/internal/generate/snowflake"
+	"smf/internal/generate/sqlite"
+)
+
+func TestGeneratorsRegistered(t *testing.T) {
+	t.Parallel()
+	tests := map[core.Dialect]generate.Generator{
+		core.DialectPostgreSQL: &postgres.Generator{},
+		core.DialectSQLite:     &sqlite.Generator{},
+		core.DialectMSSQL:      &mssql.Generator{},
+		core.DialectOracle:     &oracle.Generator{},
+		core.DialectDB2:        &db2.Generator{},
+		core.DialectSnowflake:  &snowflake.Generator{},
+	}
+	for dialect, want := range tests {
+		g, err := generate.NewGenerator(dialect)
+		require.NoError(t, err, dialect)
+		assert.IsType(t, want, g, dialect)
+	}
+}
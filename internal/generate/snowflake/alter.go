@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// alterColumn renders the ALTER COLUMN statements for a column change.
+// Snowflake can only widen a column type and cannot set a DEFAULT other
+// than a sequence on an existing column.
+func (r *renderer) alterColumn(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AlterColumnType:
+		r.alterType(c.Table, c.Old, c.New)
+	case *diff.AlterNullability:
+		action := "DROP NOT NULL"
+		if !c.New.Nullable {
+			action = "SET NOT NULL"
+		}
+		r.script.Add(alter(c.Table, c.New, action))
+	case *diff.ChangeDefault:
+		r.alterDefault(c.Table, c.New)
+	case *diff.AlterColumn:
+		for _, f := range c.Fields {
+			r.alterField(c, f)
+		}
+	}
+}
+
+func alter(t *core.Table, c *core.Column, action string) string {
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", quote(t.Name), quote(c.Name), action)
+}
+
+func (r *renderer) alterType(t *core.Table, from, to *core.Column) {
+	oldType, newType := columnType(from), columnType(to)
+	if oldType == newType {
+		return
+	}
+	if generate.ParseType(oldType).Base != generate.ParseType(newType).Base {
+		r.script.Warnf("column %s.%s: Snowflake can only change the length or precision of a type; converting %s to %s requires a new column",
+			t.Name, to.Name, oldType, newType)
+		return
+	}
+	r.script.Add(alter(t, to, "SET DATA TYPE "+newType))
+}
+
+// alterDefault drops the DEFAULT of c or binds it to a sequence. It may be
+// called for the default and for the sequence, so it renders only once.
+func (r *renderer) alterDefault(t *core.Table, c *core.Column) {
+	switch {
+	case isIdentity(c), r.once("default:" + t.Name + "." + c.Name):
+	case c.DefaultValue != nil:
+		r.script.Warnf("column %s.%s: Snowflake cannot change the default of an existing column; recreate the column", t.Name, c.Name)
+	case c.SequenceName != "":
+		r.script.Add(createSequence(c))
+		r.script.Add(alter(t, c, "SET DEFAULT "+sequenceValue(c)))
+	default:
+		r.script.Add(alter(t, c, "DROP DEFAULT"))
+	}
+}
+
+// alterField renders a single changed column attribute. Attributes of other
+// dialects are ignored.
+func (r *renderer) alterField(c *diff.AlterColumn, f diff.FieldChange) {
+	t, col := c.Table, c.New
+	switch f.Name {
+	case "comment":
+		if col.Comment == "" {
+			r.script.Add(alter(t, col, "UNSET COMMENT"))
+		} else {
+			r.script.Add(alter(t, col, "COMMENT "+generate.QuoteString(col.Comment)))
+		}
+	case "sequence_name":
+		r.alterDefault(t, col)
+	case "auto_increment", "identity_generation", "identity_seed", "identity_increment", "collate":
+		r.warnOnce(t.Name+"."+col.Name+":"+f.Name,
+			"column %s.%s: Snowflake cannot alter the %s of an existing column; recreate the column", t.Name, col.Name, f.Name)
+	}
+}
@@ -0,0 +1,181 @@
+package snowflake
+
+import (
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+var typeMapper = generate.TypeMapper{
+	Types: map[string]string{
+		"varchar":   "VARCHAR(%s)",
+		"char":      "CHAR(%s)",
+		"text":      "VARCHAR",
+		"tinyint":   "TINYINT",
+		"smallint":  "SMALLINT",
+		"int":       "INT",
+		"integer":   "INT",
+		"bigint":    "BIGINT",
+		"decimal":   "NUMBER(%s)",
+		"numeric":   "NUMBER(%s)",
+		"float":     "FLOAT",
+		"double":    "DOUBLE",
+		"boolean":   "BOOLEAN",
+		"bool":      "BOOLEAN",
+		"date":      "DATE",
+		"time":      "TIME",
+		"timestamp": "TIMESTAMP_TZ",
+		"datetime":  "TIMESTAMP_NTZ",
+		"json":      "VARIANT",
+		"uuid":      "VARCHAR(36)",
+		"blob":      "BINARY",
+		"binary":    "BINARY(%s)",
+		"varbinary": "VARBINARY(%s)",
+	},
+	Fallback: map[core.DataType]string{
+		core.DataTypeString:   "VARCHAR",
+		core.DataTypeInt:      "INT",
+		core.DataTypeFloat:    "DOUBLE",
+		core.DataTypeBoolean:  "BOOLEAN",
+		core.DataTypeDatetime: "TIMESTAMP_NTZ",
+		core.DataTypeJSON:     "VARIANT",
+		core.DataTypeUUID:     "VARCHAR(36)",
+		core.DataTypeBinary:   "BINARY",
+	},
+	StripSuffix: true,
+}
+
+// isIdentity reports whether c is rendered as an IDENTITY column.
+func isIdentity(c *core.Column) bool {
+	return c.AutoIncrement || c.IdentityGeneration != ""
+}
+
+// columnType returns the Snowflake type of c. Enums become VARCHAR columns
+// sized for their longest value.
+func columnType(c *core.Column) string {
+	if generate.IsEnum(c) {
+		size := 1
+		for _, v := range c.EnumValues {
+			size = max(size, len(v))
+		}
+		return "VARCHAR(" + strconv.Itoa(size) + ")"
+	}
+	return typeMapper.ColumnType(c)
+}
+
+// columnDefinition renders a column as it appears in CREATE TABLE and in
+// ALTER TABLE ... ADD COLUMN clauses.
+func (r *renderer) columnDefinition(t *core.Table, c *core.Column) string {
+	parts := []string{quote(c.Name), columnType(c)}
+	if c.Collate != "" {
+		parts = append(parts, "COLLATE "+generate.QuoteString(c.Collate))
+	}
+	if clause := valueClause(c); clause != "" {
+		parts = append(parts, clause)
+	}
+	if !c.Nullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if c.Comment != "" {
+		parts = append(parts, "COMMENT "+generate.QuoteString(c.Comment))
+	}
+	r.warnUnsupported(t, c)
+	return strings.Join(parts, " ")
+}
+
+// warnUnsupported records a warning for every column attribute Snowflake
+// cannot represent.
+func (r *renderer) warnUnsupported(t *core.Table, c *core.Column) {
+	if generate.IsEnum(c) {
+		r.script.Warnf("column %s.%s: Snowflake has no CHECK constraints; the enum values are not enforced", t.Name, c.Name)
+	}
+	if c.IsGenerated {
+		r.script.Warnf("column %s.%s: Snowflake does not support generated columns; the expression was ignored", t.Name, c.Name)
+	}
+	if c.OnUpdate != nil {
+		r.script.Warnf("column %s.%s: Snowflake has no triggers; ON UPDATE was ignored", t.Name, c.Name)
+	}
+	if c.Invisible {
+		r.script.Warnf("column %s.%s: Snowflake does not support invisible columns", t.Name, c.Name)
+	}
+	if c.Charset != "" {
+		r.script.Warnf("column %s.%s: Snowflake stores all text as UTF-8; %s was ignored", t.Name, c.Name, c.Charset)
+	}
+}
+
+// valueClause renders the IDENTITY clause or the DEFAULT of c. Columns
+// bound to a named sequence default to its next value.
+func valueClause(c *core.Column) string {
+	switch {
+	case isIdentity(c):
+		return identityClause(c)
+	case c.DefaultValue != nil:
+		return "DEFAULT " + defaultValue(c, *c.DefaultValue)
+	case c.SequenceName != "":
+		return "DEFAULT " + sequenceValue(c)
+	default:
+		return ""
+	}
+}
+
+func sequenceValue(c *core.Column) string {
+	return generate.QuoteQualified(c.SequenceName, quote) + ".NEXTVAL"
+}
+
+// identityClause renders IDENTITY with its start and step. Snowflake uses
+// 1 for either when only the other is given.
+func identityClause(c *core.Column) string {
+	if c.IdentitySeed == 0 && c.IdentityIncrement == 0 {
+		return "IDENTITY"
+	}
+	seed, step := c.IdentitySeed, c.IdentityIncrement
+	if seed == 0 {
+		seed = 1
+	}
+	if step == 0 {
+		step = 1
+	}
+	return "IDENTITY(" + strconv.FormatInt(seed, 10) + ", " + strconv.FormatInt(step, 10) + ")"
+}
+
+// defaultValue renders a DEFAULT value.
+func defaultValue(c *core.Column, v string) string {
+	v = strings.TrimSpace(v)
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return generate.QuoteString(v)
+	case generate.DefaultBoolean:
+		return strings.ToUpper(v)
+	case generate.DefaultNumber:
+		if c.Type == core.DataTypeBoolean {
+			return boolLiteral(generate.BoolDefault(v))
+		}
+		return v
+	case generate.DefaultTimestamp:
+		return generate.TimestampDefault(v, timestampFuncs, "CURRENT_TIMESTAMP()")
+	default:
+		return v
+	}
+}
+
+func boolLiteral(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// timestampFuncs maps the current date and time functions of other
+// dialects to Snowflake, where every one of them is called with
+// parentheses.
+var timestampFuncs = map[string]string{
+	"CURRENT_DATE":      "CURRENT_DATE()",
+	"CURRENT_TIMESTAMP": "CURRENT_TIMESTAMP()",
+	"CURRENT_TIME":      "CURRENT_TIME()",
+	"LOCALTIMESTAMP":    "LOCALTIMESTAMP()",
+	"LOCALTIME":         "LOCALTIME()",
+	"SYSDATE":           "SYSDATE()",
+	"GETDATE":           "SYSDATE()",
+}
@@ -0,0 +1,127 @@
+// Package snowflake contains the DDL generator for Snowflake. Snowflake has
+// no indexes, triggers, or CHECK constraints, and does not enforce PRIMARY
+// KEY, UNIQUE, or FOREIGN KEY constraints; the generator skips the former
+// with a warning and declares the latter as informational constraints.
+// Physical design is expressed with clustering keys and table parameters.
+package snowflake
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+func init() {
+	generate.Register(core.DialectSnowflake, New)
+}
+
+// Generator renders Snowflake DDL.
+type Generator struct{}
+
+func New() generate.Generator {
+	return &Generator{}
+}
+
+// Generate renders a CREATE TABLE statement for every table and a CREATE
+// VIEW statement for every view in db, ordered so that referenced tables
+// and views are created first.
+func (g *Generator) Generate(db *core.Database) (*generate.Script, error) {
+	return g.GenerateChanges(diff.Databases(nil, db))
+}
+
+// GenerateChanges renders the statements that apply cs.
+func (g *Generator) GenerateChanges(cs *diff.ChangeSet) (*generate.Script, error) {
+	r := &renderer{
+		script: &generate.Script{},
+		order:  generate.NewCreateOrder(cs),
+		seen:   make(map[string]bool),
+	}
+	for _, c := range cs.Changes {
+		if err := r.change(c); err != nil {
+			return nil, err
+		}
+	}
+	r.script.Add(r.deferred...)
+	return r.script, nil
+}
+
+// renderer collects the Snowflake statements of one change set. Most of
+// what Snowflake lacks is reported with a warning; the note that key
+// constraints are informational only is given once per script.
+type renderer struct {
+	script *generate.Script
+	order  *generate.CreateOrder
+	// deferred holds foreign keys of new tables that reference a table
+	// created later in the script.
+	deferred []string
+	// seen tracks statements and warnings that are rendered only once per
+	// script or column.
+	seen map[string]bool
+}
+
+func (r *renderer) change(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddTable:
+		r.createTable(c.Table)
+	case *diff.DropTable:
+		r.script.Add("DROP TABLE " + quote(c.Table.Name))
+	case *diff.ChangeTableComment:
+		r.alterComment(c.Table, c.New)
+	case *diff.TableOptionChange:
+		r.alterTableOptions(c)
+	case *diff.AddColumn:
+		r.addColumn(c.Table, c.Column)
+	case *diff.DropColumn:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", quote(c.Table.Name), quote(c.Column.Name)))
+	case *diff.AlterColumnType, *diff.AlterNullability, *diff.ChangeDefault, *diff.AlterColumn:
+		r.alterColumn(c)
+	case *diff.AddConstraint, *diff.DropConstraint, *diff.AddIndex, *diff.DropIndex:
+		r.keyChange(c)
+	default:
+		return r.viewChange(c)
+	}
+	return nil
+}
+
+// keyChange renders a constraint change. Indexes do not exist in Snowflake,
+// so added indexes are skipped with a warning and dropped ones ignored.
+func (r *renderer) keyChange(c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AddConstraint:
+		if def := r.constraintDefinition(c.Table, c.Constraint); def != "" {
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD %s", quote(c.Table.Name), def))
+		}
+	case *diff.DropConstraint:
+		if c.Constraint.Type != core.ConstraintCheck {
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s",
+				quote(c.Table.Name), quote(generate.ConstraintName(c.Table, c.Constraint))))
+		}
+	case *diff.AddIndex:
+		r.skipIndex(c.Table, c.Index)
+	}
+}
+
+// once reports whether key was already seen, and records it otherwise.
+func (r *renderer) once(key string) bool {
+	if r.seen[key] {
+		return true
+	}
+	r.seen[key] = true
+	return false
+}
+
+// warnOnce records a warning unless one with the same key was already
+// recorded.
+func (r *renderer) warnOnce(key, format string, args ...any) {
+	if !r.once("warn:" + key) {
+		r.script.Warnf(format, args...)
+	}
+}
+
+// quote quotes a name in the case Snowflake folds it to, so that unquoted
+// references in CHECK expressions, clustering keys and views resolve.
+func quote(name string) string {
+	return generate.QuoteFolded(name)
+}
@@ -0,0 +1,203 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/pars/toml"
+)
+
+func parse(t *testing.T, schema string) *core.Database {
+	t.Helper()
+	db, err := toml.NewParser().Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+	return db
+}
+
+const eventsSchema = `
+[database]
+name = "analytics"
+dialect = "snowflake"
+
+[[tables]]
+name = "users"
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+  auto_increment = true
+
+[[tables]]
+name = "events"
+comment = "raw events"
+
+  [tables.options.snowflake]
+  cluster_by = ["created_at", "TO_DATE(received_at)"]
+  data_retention_days = 7
+  change_tracking = true
+  transient = true
+  copy_grants = true
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+  auto_increment = true
+  identity_seed = 100
+
+  [[tables.columns]]
+  name = "user_id"
+  type = "bigint"
+  references = "users.id"
+
+  [[tables.columns]]
+  name = "payload"
+  type = "json"
+  nullable = true
+  comment = "event body"
+
+  [[tables.columns]]
+  name = "created_at"
+  type = "datetime"
+  default = "NOW()"
+
+  [[tables.columns]]
+  name = "received_at"
+  type = "timestamp"
+
+  [[tables.indexes]]
+  columns = ["user_id"]
+`
+
+func TestGenerateCreateTable(t *testing.T) {
+	t.Parallel()
+	script, err := New().Generate(parse(t, eventsSchema))
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 2)
+
+	assert.Equal(t, "CREATE OR REPLACE TRANSIENT TABLE \"EVENTS\" (\n"+
+		`  "ID" BIGINT IDENTITY(100, 1) NOT NULL,`+"\n"+
+		`  "USER_ID" BIGINT NOT NULL,`+"\n"+
+		`  "PAYLOAD" VARIANT COMMENT 'event body',`+"\n"+
+		`  "CREATED_AT" TIMESTAMP_NTZ DEFAULT CURRENT_TIMESTAMP() NOT NULL,`+"\n"+
+		`  "RECEIVED_AT" TIMESTAMP_TZ NOT NULL,`+"\n"+
+		`  CONSTRAINT "PK_EVENTS" PRIMARY KEY ("ID"),`+"\n"+
+		`  CONSTRAINT "FK_EVENTS_USERS" FOREIGN KEY ("USER_ID") REFERENCES "USERS" ("ID")`+"\n"+
+		`) CLUSTER BY ("CREATED_AT", TO_DATE(received_at)) DATA_RETENTION_TIME_IN_DAYS = 7 CHANGE_TRACKING = TRUE`+
+		` COPY GRANTS COMMENT = 'raw events'`,
+		script.Statements[1])
+	assert.Len(t, script.Warnings, 2)
+	assert.Contains(t, script.Warnings[1], "idx_events_user_id was skipped")
+}
+
+func TestGenerateConstraints(t *testing.T) {
+	t.Parallel()
+	tbl := &core.Table{Name: "lines"}
+	enforced := false
+	cs := &diff.ChangeSet{Changes: []diff.Change{
+		&diff.AddConstraint{Table: tbl, Constraint: &core.Constraint{
+			Type: core.ConstraintUnique, Columns: []string{"sku"}, Enforced: &enforced,
+		}},
+		&diff.AddConstraint{Table: tbl, Constraint: &core.Constraint{
+			Name: "chk_lines_qty", Type: core.ConstraintCheck, CheckExpression: `"qty" > 0`,
+		}},
+		&diff.DropConstraint{Table: tbl, Constraint: &core.Constraint{Name: "chk_lines_price", Type: core.ConstraintCheck}},
+		&diff.DropIndex{Table: tbl, Index: &core.Index{Name: "idx_lines_sku"}},
+	}}
+
+	script, err := New().GenerateChanges(cs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "LINES" ADD CONSTRAINT "UQ_LINES_SKU" UNIQUE ("SKU")`,
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 1)
+}
+
+func TestGenerateChanges(t *testing.T) {
+	t.Parallel()
+	from := parse(t, eventsSchema)
+	to := parse(t, strings.NewReplacer(
+		`cluster_by = ["created_at", "TO_DATE(received_at)"]`, `cluster_by = ["user_id"]`,
+		`data_retention_days = 7`, `data_retention_days = 30`,
+		`transient = true`, `transient = false`,
+		`comment = "event body"`, `comment = "event payload"`,
+		`  name = "received_at"
+  type = "timestamp"`, `  name = "received_at"
+  type = "timestamp"
+  nullable = true`,
+	).Replace(eventsSchema))
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "EVENTS" CLUSTER BY ("USER_ID")`,
+		`ALTER TABLE "EVENTS" SET DATA_RETENTION_TIME_IN_DAYS = 30`,
+		`ALTER TABLE "EVENTS" ALTER COLUMN "PAYLOAD" COMMENT 'event payload'`,
+		`ALTER TABLE "EVENTS" ALTER COLUMN "RECEIVED_AT" DROP NOT NULL`,
+	}, script.Statements)
+}
+
+func TestGenerateColumnTypes(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"varchar":       "VARCHAR",
+		"varchar(64)":   "VARCHAR(64)",
+		"text":          "VARCHAR",
+		"decimal(10,2)": "NUMBER(10,2)",
+		"json":          "VARIANT",
+		"uuid":          "VARCHAR(36)",
+		"blob":          "BINARY",
+	}
+	for portable, want := range tests {
+		assert.Equal(t, want, columnType(&core.Column{PortableType: portable}), portable)
+	}
+}
+
+// TestGenerateViewInlineComment checks that view comments are rendered in
+// the COMMENT clause of the CREATE statement.
+func TestGenerateViewInlineComment(t *testing.T) {
+	t.Parallel()
+	recent := &core.View{
+		Name:       "recent_events",
+		Definition: "SELECT id, user_id FROM events WHERE created_at > DATEADD(day, -1, CURRENT_TIMESTAMP());",
+		Columns:    []string{"event_id", "user_id"},
+		Comment:    "events of the last day",
+	}
+	perUser := &core.View{
+		Name: "events_per_user", Definition: "SELECT user_id, count(*) AS n FROM events GROUP BY user_id",
+		Materialized: true, Comment: "event counts",
+	}
+	script, err := New().GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{
+		&diff.DropView{View: perUser}, &diff.AddView{View: recent}, &diff.AddView{View: perUser},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`DROP MATERIALIZED VIEW "EVENTS_PER_USER"`,
+		`CREATE VIEW "RECENT_EVENTS" ("EVENT_ID", "USER_ID") COMMENT = 'events of the last day' AS ` +
+			`SELECT id, user_id FROM events WHERE created_at > DATEADD(day, -1, CURRENT_TIMESTAMP())`,
+		`CREATE MATERIALIZED VIEW "EVENTS_PER_USER" COMMENT = 'event counts' AS ` +
+			`SELECT user_id, count(*) AS n FROM events GROUP BY user_id`,
+	}, script.Statements)
+	assert.Empty(t, script.Warnings)
+}
+
+func TestGenerateViewIgnoredOptions(t *testing.T) {
+	t.Parallel()
+	view := &core.View{
+		Name: "user_events", Definition: "SELECT user_id, count(*) AS n FROM events GROUP BY user_id",
+		Materialized: true, Refresh: core.RefreshOnDemand,
+		CheckOption: core.CheckOptionCascaded, Security: core.SecurityInvoker,
+	}
+	script, err := New().GenerateChanges(&diff.ChangeSet{Changes: []diff.Change{&diff.AddView{View: view}}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`CREATE MATERIALIZED VIEW "USER_EVENTS" AS SELECT user_id, count(*) AS n FROM events GROUP BY user_id`,
+	}, script.Statements)
+	assert.Len(t, script.Warnings, 3)
+}
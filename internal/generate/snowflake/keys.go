@@ -0,0 +1,58 @@
+package snowflake
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// constraintDefinition renders a named table constraint as it appears in
+// CREATE TABLE and in ALTER TABLE ... ADD clauses, or "" for CHECK
+// constraints, which Snowflake does not support. The other constraints are
+// informational: Snowflake records them for tools and the optimizer but
+// only enforces NOT NULL.
+func (r *renderer) constraintDefinition(t *core.Table, con *core.Constraint) string {
+	name := generate.ConstraintName(t, con)
+	if con.Type == core.ConstraintCheck {
+		r.script.Warnf("table %s: Snowflake does not support CHECK constraints; %s was skipped", t.Name, name)
+		return ""
+	}
+	if generate.Enforced(con) {
+		r.warnOnce("informational",
+			"Snowflake does not enforce PRIMARY KEY, UNIQUE, or FOREIGN KEY constraints; they are created as informational constraints")
+	}
+
+	def := "CONSTRAINT " + quote(name) + " "
+	cols := generate.QuoteList(con.Columns, quote)
+	switch con.Type {
+	case core.ConstraintPrimaryKey:
+		def += "PRIMARY KEY (" + cols + ")"
+	case core.ConstraintUnique:
+		def += "UNIQUE (" + cols + ")"
+	default:
+		def += foreignKey(con)
+	}
+	return def
+}
+
+func foreignKey(con *core.Constraint) string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		generate.QuoteList(con.Columns, quote),
+		quote(con.ReferencedTable),
+		generate.QuoteList(con.ReferencedColumns, quote))
+	if con.OnDelete != core.RefActionNone {
+		def += " ON DELETE " + string(con.OnDelete)
+	}
+	if con.OnUpdate != core.RefActionNone {
+		def += " ON UPDATE " + string(con.OnUpdate)
+	}
+	return def
+}
+
+// skipIndex records that idx was not created. Snowflake has no indexes;
+// clustering keys serve a similar purpose for large tables.
+func (r *renderer) skipIndex(t *core.Table, idx *core.Index) {
+	r.script.Warnf("table %s: Snowflake does not support indexes; %s was skipped (consider a clustering key)",
+		t.Name, generate.IndexName(t, idx))
+}
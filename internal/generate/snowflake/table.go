@@ -0,0 +1,152 @@
+package snowflake
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// createTable renders CREATE TABLE for t together with the sequences its
+// columns default to. A table with COPY GRANTS is created with CREATE OR
+// REPLACE, so that an existing table it replaces keeps its grants.
+func (r *renderer) createTable(t *core.Table) {
+	for _, c := range t.Columns {
+		if seq := createSequence(c); seq != "" {
+			r.script.Add(seq)
+		}
+	}
+
+	var defs []string
+	for _, c := range t.Columns {
+		defs = append(defs, r.columnDefinition(t, c))
+	}
+	for _, con := range t.Constraints {
+		def := r.constraintDefinition(t, con)
+		switch {
+		case def == "":
+		case con.Type == core.ConstraintForeignKey && !r.order.Inline(t, con):
+			r.deferred = append(r.deferred, fmt.Sprintf("ALTER TABLE %s ADD %s", quote(t.Name), def))
+		default:
+			defs = append(defs, def)
+		}
+	}
+
+	stmt := "CREATE "
+	if options(t).CopyGrants {
+		stmt += "OR REPLACE "
+	}
+	if options(t).Transient {
+		stmt += "TRANSIENT "
+	}
+	stmt += "TABLE " + quote(t.Name) + " (\n  " + strings.Join(defs, ",\n  ") + "\n)"
+	for _, opt := range tableOptions(t) {
+		stmt += " " + opt
+	}
+	r.script.Add(stmt)
+	r.order.Created(t.Name)
+
+	for _, idx := range t.Indexes {
+		r.skipIndex(t, idx)
+	}
+}
+
+// createSequence renders the statement that creates the sequence a column
+// defaults to, unless it already exists.
+func createSequence(c *core.Column) string {
+	if c.SequenceName == "" || isIdentity(c) {
+		return ""
+	}
+	return "CREATE SEQUENCE IF NOT EXISTS " + generate.QuoteQualified(c.SequenceName, quote)
+}
+
+func options(t *core.Table) *core.SnowflakeTableOptions {
+	if t.Options.Snowflake == nil {
+		return &core.SnowflakeTableOptions{}
+	}
+	return t.Options.Snowflake
+}
+
+// tableOptions renders the clauses that follow the column list, in the
+// order Snowflake documents them.
+func tableOptions(t *core.Table) []string {
+	o := options(t)
+	var opts []string
+	if len(o.ClusterBy) > 0 {
+		opts = append(opts, clusterBy(o.ClusterBy))
+	}
+	if o.DataRetentionDays != nil {
+		opts = append(opts, "DATA_RETENTION_TIME_IN_DAYS = "+strconv.Itoa(*o.DataRetentionDays))
+	}
+	if o.ChangeTracking {
+		opts = append(opts, "CHANGE_TRACKING = TRUE")
+	}
+	if o.CopyGrants {
+		opts = append(opts, "COPY GRANTS")
+	}
+	if t.Comment != "" {
+		opts = append(opts, "COMMENT = "+generate.QuoteString(t.Comment))
+	}
+	return opts
+}
+
+var identifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// clusterBy renders a clustering key. Plain column names are quoted;
+// expressions such as TO_DATE(created_at) are kept verbatim.
+func clusterBy(keys []string) string {
+	exprs := make([]string, len(keys))
+	for i, k := range keys {
+		exprs[i] = k
+		if identifierRe.MatchString(k) {
+			exprs[i] = quote(k)
+		}
+	}
+	return "CLUSTER BY (" + strings.Join(exprs, ", ") + ")"
+}
+
+func (r *renderer) addColumn(t *core.Table, c *core.Column) {
+	if seq := createSequence(c); seq != "" {
+		r.script.Add(seq)
+	}
+	r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", quote(t.Name), r.columnDefinition(t, c)))
+}
+
+func (r *renderer) alterComment(t *core.Table, comment string) {
+	if comment == "" {
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s UNSET COMMENT", quote(t.Name)))
+		return
+	}
+	r.script.Add(fmt.Sprintf("ALTER TABLE %s SET COMMENT = %s", quote(t.Name), generate.QuoteString(comment)))
+}
+
+// alterTableOptions renders a table option change. A table cannot switch
+// between permanent and transient; it has to be recreated.
+func (r *renderer) alterTableOptions(c *diff.TableOptionChange) {
+	t, o := c.New, options(c.New)
+	name := quote(t.Name)
+	for _, f := range c.Fields {
+		switch f.Name {
+		case "snowflake.cluster_by":
+			if len(o.ClusterBy) == 0 {
+				r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP CLUSTERING KEY", name))
+			} else {
+				r.script.Add(fmt.Sprintf("ALTER TABLE %s %s", name, clusterBy(o.ClusterBy)))
+			}
+		case "snowflake.data_retention_days":
+			if o.DataRetentionDays == nil {
+				r.script.Add(fmt.Sprintf("ALTER TABLE %s UNSET DATA_RETENTION_TIME_IN_DAYS", name))
+			} else {
+				r.script.Add(fmt.Sprintf("ALTER TABLE %s SET DATA_RETENTION_TIME_IN_DAYS = %d", name, *o.DataRetentionDays))
+			}
+		case "snowflake.change_tracking":
+			r.script.Add(fmt.Sprintf("ALTER TABLE %s SET CHANGE_TRACKING = %s", name, boolLiteral(o.ChangeTracking)))
+		case "snowflake.transient":
+			r.script.Warnf("table %s: a table cannot switch between permanent and transient; recreate the table", t.Name)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package snowflake
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// viewChange renders the statements of a view change. Materialized views
+// are dropped with DROP MATERIALIZED VIEW.
+func (r *renderer) viewChange(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddView:
+		r.createView(c.View)
+	case *diff.DropView:
+		r.script.Add(fmt.Sprintf("DROP %s %s", viewKind(c.View), quote(c.View.Name)))
+	default:
+		return fmt.Errorf("snowflake: unsupported change %T", c)
+	}
+	return nil
+}
+
+func viewKind(v *core.View) string {
+	if v.Materialized {
+		return "MATERIALIZED VIEW"
+	}
+	return "VIEW"
+}
+
+// createView renders CREATE VIEW or CREATE MATERIALIZED VIEW. Snowflake
+// views are read-only and run with the privileges of their owner, and
+// materialized views are maintained in the background as their base table
+// changes.
+func (r *renderer) createView(v *core.View) {
+	if v.CheckOption != core.CheckOptionNone {
+		r.script.Warnf("view %s: Snowflake views are read-only; WITH CHECK OPTION was ignored", v.Name)
+	}
+	if v.Security == core.SecurityInvoker {
+		r.script.Warnf("view %s: Snowflake does not support SECURITY INVOKER; it was ignored", v.Name)
+	}
+	if v.Refresh == core.RefreshOnDemand {
+		r.script.Warnf("view %s: Snowflake maintains materialized views automatically; ON DEMAND was ignored", v.Name)
+	}
+
+	stmt := fmt.Sprintf("CREATE %s %s%s", viewKind(v), quote(v.Name), generate.ViewColumns(v, quote))
+	if v.Comment != "" {
+		stmt += " COMMENT = " + generate.QuoteString(v.Comment)
+	}
+	r.script.Add(stmt + " AS " + generate.ViewDefinition(v))
+}
@@ -0,0 +1,202 @@
+package generate
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// DefaultKind classifies a column DEFAULT value so that generators can
+// decide whether it must be quoted or rewritten for their dialect.
+type DefaultKind int
+
+const (
+	DefaultString     DefaultKind = iota // Plain string literal that must be quoted.
+	DefaultQuoted                        // Literal that is already quoted ('abc').
+	DefaultNumber                        // Numeric literal.
+	DefaultBoolean                       // TRUE or FALSE.
+	DefaultNull                          // NULL.
+	DefaultTimestamp                     // CURRENT_TIMESTAMP, NOW(), and friends.
+	DefaultExpression                    // Function call or parenthesized expression.
+)
+
+var (
+	functionCallRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*\s*\(.*\)$`)
+	timestampRe    = regexp.MustCompile(`(?i)^(CURRENT_TIMESTAMP|CURRENT_DATE|CURRENT_TIME|LOCALTIMESTAMP|LOCALTIME|NOW|SYSDATE|SYSTIMESTAMP|GETDATE|SYSDATETIME)\s*(\(\s*\d*\s*\))?$`)
+)
+
+// ClassifyDefault determines the kind of a DEFAULT value as written in the
+// schema (see core.Column.DefaultValue).
+func ClassifyDefault(v string) DefaultKind {
+	v = strings.TrimSpace(v)
+	upper := strings.ToUpper(v)
+	switch {
+	case upper == "TRUE" || upper == "FALSE":
+		return DefaultBoolean
+	case upper == "NULL":
+		return DefaultNull
+	case timestampRe.MatchString(v):
+		return DefaultTimestamp
+	case len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'':
+		return DefaultQuoted
+	case isNumber(v):
+		return DefaultNumber
+	case strings.HasPrefix(v, "(") && strings.HasSuffix(v, ")"), functionCallRe.MatchString(v):
+		return DefaultExpression
+	default:
+		return DefaultString
+	}
+}
+
+// TimestampDefault rewrites a DefaultTimestamp value for a dialect. funcs
+// maps the upper-cased name of a function that ClassifyDefault recognizes
+// to its spelling in the dialect, or to an empty string when the dialect
+// accepts the value as written; any other function becomes fallback.
+func TimestampDefault(v string, funcs map[string]string, fallback string) string {
+	v = strings.TrimSpace(v)
+	name := strings.ToUpper(v)
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+	spelling, ok := funcs[name]
+	switch {
+	case !ok:
+		return fallback
+	case spelling == "":
+		return v
+	default:
+		return spelling
+	}
+}
+
+func isNumber(v string) bool {
+	_, err := strconv.ParseFloat(v, 64)
+	return err == nil
+}
+
+// BoolDefault reports whether v is the boolean TRUE. It accepts the forms
+// the parser and introspecters produce (TRUE/FALSE, 1/0).
+func BoolDefault(v string) bool {
+	switch strings.ToUpper(strings.TrimSpace(v)) {
+	case "TRUE", "1", "'1'", "B'1'":
+		return true
+	default:
+		return false
+	}
+}
+
+// QuoteString quotes s as a standard SQL string literal by doubling single
+// quotes.
+func QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// QuoteIdentifier quotes name as a standard SQL (ANSI) delimited identifier.
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteFolded quotes name for dialects that fold unquoted identifiers to upper
+// case (Oracle, Db2, Snowflake). A lowercase plain name is quoted in upper case
+// so that it matches the same name written unquoted in raw SQL such as CHECK
+// expressions and view queries; any other name is quoted verbatim.
+func QuoteFolded(name string) string {
+	if plainLowerRe.MatchString(name) {
+		name = strings.ToUpper(name)
+	}
+	return QuoteIdentifier(name)
+}
+
+var plainLowerRe = regexp.MustCompile(`^[a-z_][a-z0-9_$#]*$`)
+
+// QuoteQualified quotes every part of a dotted name (schema.sequence) with
+// quote.
+func QuoteQualified(name string, quote func(string) string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = quote(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// QuoteList quotes every name with quote and joins them with ", ".
+func QuoteList(names []string, quote func(string) string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quote(n)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// IsBlock reports whether stmt is a procedural block (PL/SQL, SQL PL, or
+// T-SQL) that contains semicolons of its own: an anonymous block, or a
+// stored program unit whose body ends with "END;". A bare BEGIN or BEGIN
+// TRANSACTION is a plain statement.
+func IsBlock(stmt string) bool {
+	upper := strings.ToUpper(strings.TrimSpace(stmt))
+	switch {
+	case strings.HasPrefix(upper, "DECLARE"):
+		return true
+	case strings.HasPrefix(upper, "BEGIN"):
+		return strings.Contains(upper, ";")
+	default:
+		return strings.HasPrefix(upper, "CREATE") && strings.HasSuffix(upper, "END;")
+	}
+}
+
+// IsEnum reports whether c is a portable enum, which a dialect without a
+// native enum type has to emulate. A raw type always wins over the enum
+// values.
+func IsEnum(c *core.Column) bool {
+	return c.RawType == "" && len(c.EnumValues) > 0
+}
+
+// IndexName returns the index name, generating a deterministic
+// idx_{table}_{columns} name for unnamed indexes.
+func IndexName(t *core.Table, idx *core.Index) string {
+	if idx.Name != "" {
+		return idx.Name
+	}
+	return "idx_" + strings.ToLower(t.Name+"_"+strings.Join(idx.Names(), "_"))
+}
+
+// KeyPart renders an index key part: the quoted column, or the expression
+// of a functional key part in parentheses.
+func KeyPart(c core.ColumnIndex, quote func(string) string) string {
+	if c.Expression != "" {
+		return "(" + c.Expression + ")"
+	}
+	return quote(c.Name)
+}
+
+// ConstraintName returns the constraint name, generating one with
+// core.AutoGenerateConstraintName for unnamed constraints.
+func ConstraintName(t *core.Table, con *core.Constraint) string {
+	if con.Name != "" {
+		return con.Name
+	}
+	return core.AutoGenerateConstraintName(con.Type, t.Name, con.Columns, con.ReferencedTable)
+}
+
+// Enforced reports whether a constraint is enforced (nil means enforced).
+func Enforced(con *core.Constraint) bool {
+	return con.Enforced == nil || *con.Enforced
+}
+
+// ViewColumns renders the column list of a view, preceded by a space, or
+// an empty string when the view keeps the names of its SELECT list.
+func ViewColumns(v *core.View, quote func(string) string) string {
+	if len(v.Columns) == 0 {
+		return ""
+	}
+	return " (" + QuoteList(v.Columns, quote) + ")"
+}
+
+// ViewDefinition returns the SELECT statement of v without the surrounding
+// whitespace and the trailing semicolon, which would end the statement the
+// definition is embedded in.
+func ViewDefinition(v *core.View) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(v.Definition), ";"))
+}
@@ -0,0 +1,77 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestClassifyDefault(t *testing.T) {
+	tests := map[string]DefaultKind{
+		"free":               DefaultString,
+		"'free'":             DefaultQuoted,
+		"42":                 DefaultNumber,
+		"-1.5":               DefaultNumber,
+		"TRUE":               DefaultBoolean,
+		"null":               DefaultNull,
+		"CURRENT_TIMESTAMP":  DefaultTimestamp,
+		"now()":              DefaultTimestamp,
+		"uuid()":             DefaultExpression,
+		"(json_array())":     DefaultExpression,
+		"gen_random_uuid ()": DefaultExpression,
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, ClassifyDefault(in), in)
+	}
+}
+
+func TestQuoting(t *testing.T) {
+	assert.Equal(t, "'it''s'", QuoteString("it's"))
+	assert.Equal(t, `"a""b"`, QuoteIdentifier(`a"b`))
+	assert.Equal(t, `"a", "b"`, QuoteList([]string{"a", "b"}, QuoteIdentifier))
+	assert.Equal(t, `"USER_ID"`, QuoteFolded("user_id"))
+	assert.Equal(t, `"MixedCase"`, QuoteFolded("MixedCase"))
+	assert.Equal(t, `"order items"`, QuoteFolded("order items"))
+	assert.Equal(t, `"APP"."SEQ"`, QuoteQualified("app.seq", QuoteFolded))
+}
+
+func TestTimestampDefault(t *testing.T) {
+	funcs := map[string]string{"CURRENT_DATE": "CURRENT DATE", "SYSDATE": ""}
+	assert.Equal(t, "CURRENT DATE", TimestampDefault("current_date", funcs, "CURRENT TIMESTAMP"))
+	assert.Equal(t, "sysdate", TimestampDefault(" sysdate ", funcs, "CURRENT TIMESTAMP"))
+	assert.Equal(t, "CURRENT TIMESTAMP", TimestampDefault("now()", funcs, "CURRENT TIMESTAMP"))
+	assert.Equal(t, "CURRENT TIMESTAMP", TimestampDefault("CURRENT_TIMESTAMP(3)", funcs, "CURRENT TIMESTAMP"))
+}
+
+func TestIsEnum(t *testing.T) {
+	assert.True(t, IsEnum(&core.Column{EnumValues: []string{"a"}}))
+	assert.False(t, IsEnum(&core.Column{RawType: "SET('a')", EnumValues: []string{"a"}}))
+	assert.False(t, IsEnum(&core.Column{PortableType: "varchar(10)"}))
+}
+
+func TestGeneratedNames(t *testing.T) {
+	tbl := &core.Table{Name: "Users"}
+	idx := &core.Index{Columns: []core.ColumnIndex{{Name: "Email"}, {Name: "name"}}}
+	con := &core.Constraint{Type: core.ConstraintUnique, Columns: []string{"email"}}
+
+	assert.Equal(t, "idx_users_email_name", IndexName(tbl, idx))
+	assert.Equal(t, "uq_users_email", ConstraintName(tbl, con))
+}
+
+func TestViewClauses(t *testing.T) {
+	v := &core.View{Name: "v", Definition: "\n  SELECT a, b FROM t;  \n", Columns: []string{"x", "y"}}
+	assert.Equal(t, "SELECT a, b FROM t", ViewDefinition(v))
+	assert.Equal(t, ` ("x", "y")`, ViewColumns(v, QuoteIdentifier))
+	assert.Empty(t, ViewColumns(&core.View{}, QuoteIdentifier))
+}
+
+func TestIsBlock(t *testing.T) {
+	assert.True(t, IsBlock("BEGIN\n  EXECUTE IMMEDIATE 'DROP TABLE t';\nEND;"))
+	assert.True(t, IsBlock("DECLARE x NUMBER; BEGIN NULL; END;"))
+	assert.True(t, IsBlock("CREATE OR REPLACE TRIGGER x BEFORE UPDATE ON t FOR EACH ROW\nBEGIN\n  NULL;\nEND;"))
+	assert.False(t, IsBlock("BEGIN TRANSACTION"))
+	assert.False(t, IsBlock("CREATE TRIGGER x AFTER UPDATE ON t BEGIN UPDATE t SET a = 1; END"))
+	assert.False(t, IsBlock("CREATE TABLE t (id INT)"))
+}
@@ -0,0 +1,232 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// typeMapper maps portable types to the storage classes of SQLite, so that
+// the generated columns are also valid in STRICT tables.
+var typeMapper = generate.TypeMapper{
+	Types: map[string]string{
+		"varchar":   "TEXT",
+		"char":      "TEXT",
+		"text":      "TEXT",
+		"smallint":  "INTEGER",
+		"int":       "INTEGER",
+		"integer":   "INTEGER",
+		"bigint":    "INTEGER",
+		"boolean":   "INTEGER",
+		"bool":      "INTEGER",
+		"decimal":   "REAL",
+		"numeric":   "REAL",
+		"float":     "REAL",
+		"double":    "REAL",
+		"date":      "TEXT",
+		"time":      "TEXT",
+		"timestamp": "TEXT",
+		"datetime":  "TEXT",
+		"json":      "TEXT",
+		"uuid":      "TEXT",
+		"blob":      "BLOB",
+		"binary":    "BLOB",
+		"varbinary": "BLOB",
+	},
+	Fallback: map[core.DataType]string{
+		core.DataTypeString:   "TEXT",
+		core.DataTypeInt:      "INTEGER",
+		core.DataTypeFloat:    "REAL",
+		core.DataTypeBoolean:  "INTEGER",
+		core.DataTypeDatetime: "TEXT",
+		core.DataTypeJSON:     "TEXT",
+		core.DataTypeUUID:     "TEXT",
+		core.DataTypeBinary:   "BLOB",
+		core.DataTypeEnum:     "TEXT",
+	},
+	StripSuffix: true,
+}
+
+// strictTypes are the only column types STRICT tables accept.
+var strictTypes = map[string]bool{"INT": true, "INTEGER": true, "REAL": true, "TEXT": true, "BLOB": true, "ANY": true}
+
+// storageType returns the storage class c is stored with.
+func storageType(c *core.Column) string {
+	if generate.IsEnum(c) {
+		return "TEXT"
+	}
+	return typeMapper.ColumnType(c)
+}
+
+// columnType returns the type c is declared with in t. Outside STRICT
+// tables a portable type is declared as written when SQLite derives the
+// affinity of its storage class from it, so varchar(255) is declared as
+// VARCHAR(255) and read back as such, while datetime, whose name has
+// NUMERIC affinity, is declared as TEXT.
+func columnType(t *core.Table, c *core.Column) string {
+	storage := storageType(c)
+	if strict(t) || c.RawType != "" || c.PortableType == "" || generate.IsEnum(c) {
+		return storage
+	}
+	if declared := strings.ToUpper(c.PortableType); affinity(declared) == storage {
+		return declared
+	}
+	return storage
+}
+
+// affinity returns the type affinity SQLite derives from a declared type,
+// by the rules of section 3.1 of its datatype documentation.
+func affinity(declared string) string {
+	switch typ := strings.ToUpper(declared); {
+	case strings.Contains(typ, "INT"):
+		return "INTEGER"
+	case strings.Contains(typ, "CHAR"), strings.Contains(typ, "CLOB"), strings.Contains(typ, "TEXT"):
+		return "TEXT"
+	case typ == "", strings.Contains(typ, "BLOB"):
+		return "BLOB"
+	case strings.Contains(typ, "REAL"), strings.Contains(typ, "FLOA"), strings.Contains(typ, "DOUB"):
+		return "REAL"
+	default:
+		return "NUMERIC"
+	}
+}
+
+func withoutRowid(t *core.Table) bool {
+	return t.Options.SQLite != nil && t.Options.SQLite.WithoutRowid
+}
+
+func strict(t *core.Table) bool {
+	return t.Options.SQLite != nil && t.Options.SQLite.Strict
+}
+
+// isRowidAlias reports whether c is an auto-increment column that is the
+// whole primary key of a rowid table. Such a column is declared as INTEGER
+// PRIMARY KEY and becomes an alias for the rowid.
+func isRowidAlias(t *core.Table, c *core.Column) bool {
+	if !c.AutoIncrement || withoutRowid(t) {
+		return false
+	}
+	pk := t.PrimaryKey()
+	return pk != nil && len(pk.Columns) == 1 && pk.Columns[0] == c.Name
+}
+
+// hasRowidAlias reports whether the primary key of t is declared inline by
+// its rowid alias column.
+func hasRowidAlias(t *core.Table) bool {
+	pk := t.PrimaryKey()
+	if pk == nil || len(pk.Columns) != 1 {
+		return false
+	}
+	c := t.FindColumn(pk.Columns[0])
+	return c != nil && isRowidAlias(t, c)
+}
+
+// columnDefinition renders a column as it appears in CREATE TABLE and in
+// ADD COLUMN clauses.
+func (r *renderer) columnDefinition(t *core.Table, c *core.Column) string {
+	parts := []string{quote(c.Name)}
+	if isRowidAlias(t, c) {
+		parts = append(parts, "INTEGER PRIMARY KEY")
+		if c.SQLite != nil && c.SQLite.StrictAutoincrement {
+			parts = append(parts, "AUTOINCREMENT")
+		}
+	} else {
+		parts = append(parts, columnType(t, c))
+		r.warnAutoIncrement(t, c)
+	}
+	if !c.Nullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if c.DefaultValue != nil && !c.IsGenerated {
+		parts = append(parts, "DEFAULT "+defaultValue(c, *c.DefaultValue))
+	}
+	if c.Collate != "" {
+		parts = append(parts, "COLLATE "+quote(c.Collate))
+	}
+	if c.IsGenerated {
+		parts = append(parts, generationClause(c))
+	}
+	if generate.IsEnum(c) {
+		parts = append(parts, fmt.Sprintf("CHECK (%s IN (%s))",
+			quote(c.Name), generate.QuoteList(c.EnumValues, generate.QuoteString)))
+	}
+	r.warnUnsupported(t, c)
+	return strings.Join(parts, " ")
+}
+
+func generationClause(c *core.Column) string {
+	storage := core.GenerationVirtual
+	if c.GenerationStorage == core.GenerationStored {
+		storage = core.GenerationStored
+	}
+	return "GENERATED ALWAYS AS (" + c.GenerationExpression + ") " + string(storage)
+}
+
+// defaultValue renders a DEFAULT value. SQLite has no boolean literals and
+// only accepts the CURRENT_* keywords and parenthesized expressions as
+// non-constant defaults.
+func defaultValue(c *core.Column, v string) string {
+	v = strings.TrimSpace(v)
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return generate.QuoteString(v)
+	case generate.DefaultBoolean:
+		return boolLiteral(generate.BoolDefault(v))
+	case generate.DefaultNumber:
+		if c.Type == core.DataTypeBoolean {
+			return boolLiteral(generate.BoolDefault(v))
+		}
+		return v
+	case generate.DefaultTimestamp:
+		return generate.TimestampDefault(v, timestampFuncs, "CURRENT_TIMESTAMP")
+	case generate.DefaultExpression:
+		if strings.HasPrefix(v, "(") {
+			return v
+		}
+		return "(" + v + ")"
+	default:
+		return v
+	}
+}
+
+func boolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// timestampFuncs maps the current date and time functions of other
+// dialects to the CURRENT_* keywords of SQLite, which take no parentheses.
+var timestampFuncs = map[string]string{
+	"CURRENT_DATE": "CURRENT_DATE",
+	"CURRENT_TIME": "CURRENT_TIME",
+	"LOCALTIME":    "CURRENT_TIME",
+}
+
+func (r *renderer) warnAutoIncrement(t *core.Table, c *core.Column) {
+	switch {
+	case !c.AutoIncrement:
+	case withoutRowid(t):
+		r.script.Warnf("column %s.%s: WITHOUT ROWID tables do not support AUTOINCREMENT; it was ignored", t.Name, c.Name)
+	default:
+		r.script.Warnf("column %s.%s: SQLite only supports auto-increment on a single-column INTEGER PRIMARY KEY; it was ignored",
+			t.Name, c.Name)
+	}
+}
+
+// warnUnsupported records warnings for column attributes that SQLite has
+// no equivalent for.
+func (r *renderer) warnUnsupported(t *core.Table, c *core.Column) {
+	if c.Charset != "" {
+		r.script.Warnf("column %s.%s: SQLite has no per-column character set; %s was ignored", t.Name, c.Name, c.Charset)
+	}
+	if c.Invisible {
+		r.script.Warnf("column %s.%s: SQLite does not support invisible columns", t.Name, c.Name)
+	}
+	if typ := storageType(c); strict(t) && !isRowidAlias(t, c) && !strictTypes[strings.ToUpper(generate.ParseType(typ).Base)] {
+		r.script.Warnf("column %s.%s: type %s is not allowed in STRICT tables", t.Name, c.Name, typ)
+	}
+}
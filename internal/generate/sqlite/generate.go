@@ -0,0 +1,118 @@
+// Package sqlite contains the DDL generator for SQLite.
+//
+// SQLite's ALTER TABLE only supports adding, dropping, and renaming columns,
+// so most changes are applied with the table rebuild procedure described in
+// https://www.sqlite.org/lang_altertable.html#otheralter: the target table is
+// created under a temporary name, the data is copied, the old table is
+// dropped, and the new table is renamed and gets its indexes and triggers
+// back. A script that rebuilds tables runs in a single transaction with
+// foreign key enforcement disabled and checks all foreign keys before it
+// commits.
+package sqlite
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+func init() {
+	generate.Register(core.DialectSQLite, New)
+}
+
+// Generator renders SQLite DDL.
+type Generator struct{}
+
+func New() generate.Generator {
+	return &Generator{}
+}
+
+// Generate renders a CREATE TABLE statement for every table and a CREATE
+// VIEW statement for every view in db.
+func (g *Generator) Generate(db *core.Database) (*generate.Script, error) {
+	return g.GenerateChanges(diff.Databases(nil, db))
+}
+
+// GenerateChanges renders the statements that apply cs. When a table is
+// rebuilt, the statements run in a transaction with foreign keys off, and
+// PRAGMA foreign_key_check reports the violations the rebuild left before
+// COMMIT; smf apply rolls the migration back when it reports any.
+func (g *Generator) GenerateChanges(cs *diff.ChangeSet) (*generate.Script, error) {
+	r := &renderer{
+		script:   &generate.Script{},
+		from:     cs.From,
+		to:       cs.To,
+		rebuild:  planRebuilds(cs),
+		rebuilt:  make(map[string]bool),
+		triggers: make(map[string]bool),
+	}
+	for _, c := range cs.Changes {
+		if err := r.change(c); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.rebuild) == 0 {
+		return r.script, nil
+	}
+
+	script := &generate.Script{Warnings: r.script.Warnings}
+	script.Add("PRAGMA foreign_keys = OFF", "BEGIN TRANSACTION")
+	script.Add(r.script.Statements...)
+	script.Add("PRAGMA foreign_key_check", "COMMIT", "PRAGMA foreign_keys = ON")
+	return script, nil
+}
+
+// renderer collects the SQLite statements of one change set. A table is
+// rebuilt at most once, with all of its changes, when the first change
+// that needs the rebuild is rendered; later changes to it are then
+// skipped.
+type renderer struct {
+	script   *generate.Script
+	from, to *core.Database
+	// rebuild holds the tables whose changes require a rebuild; rebuilt
+	// holds those that have already been rebuilt.
+	rebuild map[string]bool
+	rebuilt map[string]bool
+	// triggers tracks columns whose ON UPDATE trigger was already replaced.
+	triggers map[string]bool
+}
+
+func (r *renderer) change(c diff.Change) error {
+	if _, ok := c.(*diff.DropTable); !ok && r.rebuild[c.TableName()] {
+		r.rebuildTable(c.TableName())
+		return nil
+	}
+
+	switch c := c.(type) {
+	case *diff.AddTable:
+		r.createTable(c.Table, c.Table.Name)
+		r.createIndexesAndTriggers(c.Table)
+	case *diff.DropTable:
+		r.script.Add("DROP TABLE " + quote(c.Table.Name))
+	case *diff.ChangeTableComment:
+		r.script.Warnf("table %s: SQLite does not support table comments; the comment change was ignored", c.Table.Name)
+	case *diff.AlterColumnType, *diff.TableOptionChange:
+		// The type maps to the same storage class; option changes require a rebuild.
+	case *diff.AddColumn:
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", quote(c.Table.Name), r.columnDefinition(c.Table, c.Column)))
+		r.createOnUpdateTrigger(c.Table, c.Column)
+	case *diff.DropColumn:
+		r.dropOnUpdateTrigger(c.Table, c.Column)
+		r.script.Add(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", quote(c.Table.Name), quote(c.Column.Name)))
+	case *diff.AlterColumn:
+		r.replaceOnUpdateTrigger(c)
+	case *diff.AddIndex:
+		r.createIndex(c.Table, c.Index)
+	case *diff.DropIndex:
+		r.script.Add("DROP INDEX " + quote(generate.IndexName(c.Table, c.Index)))
+	default:
+		return r.viewChange(c)
+	}
+	return nil
+}
+
+func quote(name string) string {
+	return generate.QuoteIdentifier(name)
+}
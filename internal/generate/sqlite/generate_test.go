@@ -0,0 +1,302 @@
+package sqlite
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/pars/toml"
+)
+
+func parse(t *testing.T, schema string) *core.Database {
+	t.Helper()
+	db, err := toml.NewParser().Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+	return db
+}
+
+const notesSchema = `
+[database]
+name = "app"
+dialect = "sqlite"
+
+[[tables]]
+name = "notes"
+
+  [tables.options.sqlite]
+  strict = true
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+  auto_increment = true
+
+    [tables.columns.sqlite]
+    strict_autoincrement = true
+
+  [[tables.columns]]
+  name = "title"
+  type = "varchar(80)"
+
+  [[tables.columns]]
+  name = "pinned"
+  type = "boolean"
+  default = "false"
+
+  [[tables.columns]]
+  name = "updated_at"
+  type = "timestamp"
+  default = "NOW()"
+  on_update = "CURRENT_TIMESTAMP"
+
+  [[tables.indexes]]
+  columns = ["title"]
+`
+
+func TestGenerateCreateTable(t *testing.T) {
+	t.Parallel()
+	script, err := New().Generate(parse(t, notesSchema))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"CREATE TABLE \"notes\" (\n" +
+			`  "id" INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,` + "\n" +
+			`  "title" TEXT NOT NULL,` + "\n" +
+			`  "pinned" INTEGER NOT NULL DEFAULT 0,` + "\n" +
+			`  "updated_at" TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP` + "\n" +
+			`) STRICT`,
+		`CREATE INDEX "idx_notes_title" ON "notes" ("title")`,
+		`CREATE TRIGGER "notes_updated_at_on_update" AFTER UPDATE ON "notes" FOR EACH ROW WHEN NEW."updated_at" IS OLD."updated_at"` + "\n" +
+			"BEGIN\n" +
+			`  UPDATE "notes" SET "updated_at" = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;` + "\n" +
+			"END",
+	}, script.Statements)
+	assert.Empty(t, script.Warnings)
+}
+
+func TestGenerateWithoutRowid(t *testing.T) {
+	t.Parallel()
+	db := parse(t, strings.Replace(notesSchema, "strict = true", "without_rowid = true", 1))
+	script, err := New().Generate(db)
+	require.NoError(t, err)
+
+	assert.Contains(t, script.Statements[0], `"id" INT NOT NULL,`)
+	assert.Contains(t, script.Statements[0], `"title" VARCHAR(80) NOT NULL,`)
+	assert.Contains(t, script.Statements[0], `"updated_at" TEXT NOT NULL`)
+	assert.Contains(t, script.Statements[0], `CONSTRAINT "pk_notes" PRIMARY KEY ("id")`+"\n) WITHOUT ROWID")
+	assert.Contains(t, script.Statements[2], `WHERE "id" = NEW."id";`)
+	assert.Len(t, script.Warnings, 1)
+}
+
+func TestColumnType(t *testing.T) {
+	t.Parallel()
+	plain := &core.Table{Name: "notes"}
+	strictTable := &core.Table{Name: "notes", Options: core.TableOptions{SQLite: &core.SQLiteTableOptions{Strict: true}}}
+	tests := map[string]struct {
+		table *core.Table
+		col   *core.Column
+		want  string
+	}{
+		"varchar":         {plain, &core.Column{Type: core.DataTypeString, PortableType: "varchar(255)"}, "VARCHAR(255)"},
+		"bigint":          {plain, &core.Column{Type: core.DataTypeInt, PortableType: "bigint"}, "BIGINT"},
+		"double":          {plain, &core.Column{Type: core.DataTypeFloat, PortableType: "double"}, "DOUBLE"},
+		"numeric name":    {plain, &core.Column{Type: core.DataTypeDatetime, PortableType: "datetime"}, "TEXT"},
+		"decimal":         {plain, &core.Column{Type: core.DataTypeFloat, PortableType: "decimal(10,2)"}, "REAL"},
+		"enum":            {plain, &core.Column{Type: core.DataTypeEnum, PortableType: "enum", EnumValues: []string{"a"}}, "TEXT"},
+		"raw":             {plain, &core.Column{Type: core.DataTypeString, RawType: "CLOB"}, "CLOB"},
+		"strict":          {strictTable, &core.Column{Type: core.DataTypeString, PortableType: "varchar(255)"}, "TEXT"},
+		"strict category": {strictTable, &core.Column{Type: core.DataTypeInt}, "INTEGER"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, columnType(tt.table, tt.col))
+		})
+	}
+}
+
+func TestGenerateAddColumn(t *testing.T) {
+	t.Parallel()
+	from := parse(t, notesSchema)
+	to := parse(t, notesSchema+`
+  [[tables.columns]]
+  name = "body"
+  type = "text"
+  nullable = true
+`)
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Equal(t, []string{`ALTER TABLE "notes" ADD COLUMN "body" TEXT`}, script.Statements)
+}
+
+func TestGenerateRebuild(t *testing.T) {
+	t.Parallel()
+	from := parse(t, notesSchema)
+	to := parse(t, strings.NewReplacer(
+		`type = "varchar(80)"`, `type = "varchar(80)"
+  default = "untitled"`,
+		`type = "boolean"`, `type = "boolean"
+  nullable = true`,
+	).Replace(notesSchema))
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 11)
+
+	assert.Equal(t, []string{"PRAGMA foreign_keys = OFF", "BEGIN TRANSACTION"}, script.Statements[:2])
+	assert.True(t, strings.HasPrefix(script.Statements[2], `CREATE TABLE "_smf_new_notes" (`))
+	assert.Contains(t, script.Statements[2], `"title" TEXT NOT NULL DEFAULT 'untitled',`)
+	assert.Contains(t, script.Statements[2], `"pinned" INTEGER DEFAULT 0,`)
+	assert.Equal(t, `INSERT INTO "_smf_new_notes" ("id", "title", "pinned", "updated_at") `+
+		`SELECT "id", "title", "pinned", "updated_at" FROM "notes"`, script.Statements[3])
+	assert.Equal(t, []string{
+		`DROP TABLE "notes"`,
+		`ALTER TABLE "_smf_new_notes" RENAME TO "notes"`,
+		`CREATE INDEX "idx_notes_title" ON "notes" ("title")`,
+	}, script.Statements[4:7])
+	assert.True(t, strings.HasPrefix(script.Statements[7], `CREATE TRIGGER "notes_updated_at_on_update"`))
+	assert.Equal(t, []string{"PRAGMA foreign_key_check", "COMMIT", "PRAGMA foreign_keys = ON"}, script.Statements[8:])
+}
+
+func TestGenerateWidenWithinStorageClass(t *testing.T) {
+	t.Parallel()
+	from := parse(t, notesSchema)
+	to := parse(t, strings.Replace(notesSchema, `type = "varchar(80)"`, `type = "varchar(120)"`, 1))
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Empty(t, script.Statements)
+}
+
+func TestGenerateTableCommentWarning(t *testing.T) {
+	t.Parallel()
+	from := parse(t, notesSchema)
+	to := parse(t, strings.Replace(notesSchema, `name = "notes"`, `name = "notes"
+comment = "Sticky notes"`, 1))
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Empty(t, script.Statements)
+	assert.Equal(t, []string{"table notes: SQLite does not support table comments; the comment change was ignored"}, script.Warnings)
+}
+
+func TestGenerateRebuildCoalesce(t *testing.T) {
+	t.Parallel()
+	old := &core.Table{Name: "t", Columns: []*core.Column{
+		{Name: "a", PortableType: "int", Nullable: true},
+		{Name: "b", PortableType: "int", Nullable: true},
+	}}
+	def := "1"
+	tbl := &core.Table{Name: "t", Columns: []*core.Column{
+		{Name: "a", PortableType: "int", DefaultValue: &def},
+		{Name: "b", PortableType: "int", Nullable: true},
+		{Name: "c", PortableType: "int"},
+	}}
+	from := &core.Database{Tables: []*core.Table{old}}
+	to := &core.Database{Tables: []*core.Table{tbl}}
+
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+	assert.Contains(t, script.Statements, `INSERT INTO "_smf_new_t" ("a", "b") SELECT COALESCE("a", 1), "b" FROM "t"`)
+	assert.Len(t, script.Warnings, 1)
+}
+
+func TestNeedsRebuild(t *testing.T) {
+	t.Parallel()
+	now := "CURRENT_TIMESTAMP"
+	tbl := &core.Table{Name: "t", Constraints: []*core.Constraint{
+		{Type: core.ConstraintUnique, Columns: []string{"code"}},
+	}}
+	tests := map[string]struct {
+		change diff.Change
+		want   bool
+	}{
+		"nullable column":     {&diff.AddColumn{Table: tbl, Column: &core.Column{Name: "a", Nullable: true}}, false},
+		"not null no default": {&diff.AddColumn{Table: tbl, Column: &core.Column{Name: "a"}}, true},
+		"timestamp default":   {&diff.AddColumn{Table: tbl, Column: &core.Column{Name: "a", DefaultValue: &now}}, true},
+		"unique column":       {&diff.AddColumn{Table: tbl, Column: &core.Column{Name: "code", Nullable: true}}, true},
+		"stored generated": {&diff.AddColumn{Table: tbl, Column: &core.Column{
+			Name: "a", IsGenerated: true, GenerationStorage: core.GenerationStored,
+		}}, true},
+		"drop plain column":   {&diff.DropColumn{Table: tbl, Column: &core.Column{Name: "a"}}, false},
+		"comment only":        {&diff.AlterColumn{Table: tbl, Fields: []diff.FieldChange{{Name: "comment"}}}, false},
+		"collation":           {&diff.AlterColumn{Table: tbl, Fields: []diff.FieldChange{{Name: "collate"}}}, true},
+		"strict table option": {&diff.TableOptionChange{Old: tbl, New: tbl, Fields: []diff.FieldChange{{Name: "sqlite.strict"}}}, true},
+		"mysql table option":  {&diff.TableOptionChange{Old: tbl, New: tbl, Fields: []diff.FieldChange{{Name: "mysql.engine"}}}, false},
+		"nullability":         {&diff.AlterNullability{Table: tbl}, true},
+		"same storage class": {&diff.AlterColumnType{Table: tbl,
+			Old: &core.Column{Name: "a", PortableType: "varchar(100)"}, New: &core.Column{Name: "a", PortableType: "varchar(120)"}}, false},
+		"other storage class": {&diff.AlterColumnType{Table: tbl,
+			Old: &core.Column{Name: "a", PortableType: "varchar(100)"}, New: &core.Column{Name: "a", PortableType: "bigint"}}, true},
+		"enum values": {&diff.AlterColumnType{Table: tbl,
+			Old: &core.Column{Name: "a", EnumValues: []string{"x"}}, New: &core.Column{Name: "a", EnumValues: []string{"x", "y"}}}, true},
+	}
+	from := &core.Database{Tables: []*core.Table{tbl}}
+	for name, tt := range tests {
+		assert.Equal(t, tt.want, needsRebuild(from, tt.change), name)
+	}
+}
+
+const pinnedNotesView = `
+[[views]]
+name = "pinned_notes"
+definition = "SELECT id, title FROM notes WHERE pinned"
+columns = ["note_id", "title"]
+`
+
+func TestGenerateMaterializedViewWarns(t *testing.T) {
+	t.Parallel()
+	script, err := New().Generate(parse(t, notesSchema+pinnedNotesView+"materialized = true\n"))
+	require.NoError(t, err)
+	assert.Equal(t, `CREATE VIEW "pinned_notes" ("note_id", "title") AS SELECT id, title FROM notes WHERE pinned`,
+		script.Statements[len(script.Statements)-1])
+	assert.Len(t, script.Warnings, 1)
+}
+
+// TestGenerateRebuildRecreatesViews checks that the views on a rebuilt
+// table are dropped before the table and created again after it, since
+// SQLite cannot rename a table over a dropped one that views refer to.
+func TestGenerateRebuildRecreatesViews(t *testing.T) {
+	t.Parallel()
+	from := parse(t, notesSchema+pinnedNotesView)
+	to := parse(t, strings.Replace(notesSchema, `type = "varchar(80)"`, `type = "varchar(80)"
+  collate = "NOCASE"`, 1)+pinnedNotesView)
+	script, err := New().GenerateChanges(diff.Databases(from, to))
+	require.NoError(t, err)
+
+	drop := slices.Index(script.Statements, `DROP VIEW "pinned_notes"`)
+	dropTable := slices.Index(script.Statements, `DROP TABLE "notes"`)
+	create := slices.IndexFunc(script.Statements, func(s string) bool {
+		return strings.HasPrefix(s, `CREATE VIEW "pinned_notes"`)
+	})
+	rename := slices.Index(script.Statements, `ALTER TABLE "_smf_new_notes" RENAME TO "notes"`)
+	require.True(t, drop >= 0 && dropTable >= 0 && create >= 0 && rename >= 0, script.Statements)
+	assert.Less(t, drop, dropTable)
+	assert.Greater(t, create, rename)
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+	db := parse(t, notesSchema)
+	db.Tables[0].Comment = "notes"
+	New().(*Generator).Normalize(db)
+
+	notes := db.Tables[0]
+	assert.Empty(t, notes.Comment)
+	title := notes.FindColumn("title")
+	assert.Equal(t, "TEXT", title.RawType)
+	assert.Empty(t, title.PortableType)
+	pinned := notes.FindColumn("pinned")
+	assert.Equal(t, "INTEGER", pinned.RawType)
+	assert.Equal(t, new("0"), pinned.DefaultValue)
+	updated := notes.FindColumn("updated_at")
+	assert.Equal(t, new("CURRENT_TIMESTAMP"), updated.DefaultValue)
+	assert.Equal(t, new("CURRENT_TIMESTAMP"), updated.OnUpdate)
+}
@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"smf/internal/core"
+)
+
+// Normalize rewrites db into the form in which SQLite stores it. Column
+// types become the storage classes the generator renders, DEFAULT and ON
+// UPDATE values are written as the generator writes them, and the
+// attributes that SQLite cannot store are dropped: comments, character
+// sets, invisible columns, and the options of materialized and updatable
+// views.
+func (g *Generator) Normalize(db *core.Database) {
+	for _, t := range db.Tables {
+		t.Comment = ""
+		for _, c := range t.Columns {
+			normalizeColumn(c)
+		}
+	}
+	for _, v := range db.Views {
+		v.Comment = ""
+		v.Materialized, v.Refresh = false, ""
+		v.CheckOption, v.Security = core.CheckOptionNone, ""
+	}
+}
+
+// normalizeColumn renders the values of c before its type, since how a
+// default is written depends on the declared type.
+func normalizeColumn(c *core.Column) {
+	if c.DefaultValue != nil {
+		c.DefaultValue = new(defaultValue(c, *c.DefaultValue))
+	}
+	if c.OnUpdate != nil {
+		c.OnUpdate = new(defaultValue(c, *c.OnUpdate))
+	}
+	c.RawType, c.PortableType = storageType(c), ""
+	c.Comment, c.Charset, c.Invisible = "", "", false
+}
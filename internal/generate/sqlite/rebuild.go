@@ -0,0 +1,159 @@
+package sqlite
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// rebuildPrefix is prepended to the name of the temporary table a rebuild
+// copies the data into.
+const rebuildPrefix = "_smf_new_"
+
+// rebuildFields are the column attributes whose change requires a rebuild.
+var rebuildFields = map[string]bool{
+	"collate":                     true,
+	"auto_increment":              true,
+	"is_generated":                true,
+	"generation_expression":       true,
+	"generation_storage":          true,
+	"sqlite.strict_autoincrement": true,
+}
+
+// planRebuilds returns the existing tables that have at least one change
+// that ALTER TABLE cannot express.
+func planRebuilds(cs *diff.ChangeSet) map[string]bool {
+	rebuild := make(map[string]bool)
+	for _, c := range cs.Changes {
+		if needsRebuild(cs.From, c) {
+			rebuild[c.TableName()] = true
+		}
+	}
+	return rebuild
+}
+
+// needsRebuild reports whether c requires a rebuild of its table. A type
+// change only does when the column maps to another storage class, or its
+// enum CHECK constraint changes: varchar(100) and varchar(120) are both TEXT.
+func needsRebuild(from *core.Database, c diff.Change) bool {
+	switch c := c.(type) {
+	case *diff.AlterColumnType:
+		return storageType(c.Old) != storageType(c.New) || !slices.Equal(c.Old.EnumValues, c.New.EnumValues)
+	case *diff.AlterNullability, *diff.ChangeDefault, *diff.AddConstraint, *diff.DropConstraint:
+		return true
+	case *diff.AlterColumn:
+		return slices.ContainsFunc(c.Fields, func(f diff.FieldChange) bool { return rebuildFields[f.Name] })
+	case *diff.TableOptionChange:
+		return slices.ContainsFunc(c.Fields, func(f diff.FieldChange) bool { return strings.HasPrefix(f.Name, "sqlite.") })
+	case *diff.AddColumn:
+		return !canAddColumn(c.Table, c.Column)
+	case *diff.DropColumn:
+		return !canDropColumn(from.FindTable(c.Table.Name), c.Table, c.Column.Name)
+	default:
+		return false
+	}
+}
+
+// canAddColumn reports whether ALTER TABLE ADD COLUMN accepts c. SQLite
+// rejects PRIMARY KEY and UNIQUE columns, non-constant defaults, NOT NULL
+// columns without a default, and STORED generated columns.
+func canAddColumn(t *core.Table, c *core.Column) bool {
+	if inConstraint(t, c.Name, core.ConstraintPrimaryKey, core.ConstraintUnique) {
+		return false
+	}
+	if c.IsGenerated {
+		return c.GenerationStorage != core.GenerationStored
+	}
+	if c.DefaultValue == nil {
+		return c.Nullable
+	}
+	switch generate.ClassifyDefault(*c.DefaultValue) {
+	case generate.DefaultTimestamp, generate.DefaultExpression:
+		return false
+	default:
+		return true
+	}
+}
+
+// canDropColumn reports whether ALTER TABLE DROP COLUMN accepts the column:
+// it must not be part of any constraint of the old table or of an index of
+// the new table.
+func canDropColumn(from, to *core.Table, name string) bool {
+	if from != nil && inConstraint(from, name) {
+		return false
+	}
+	for _, idx := range to.Indexes {
+		if slices.Contains(idx.Names(), name) {
+			return false
+		}
+	}
+	return true
+}
+
+// inConstraint reports whether column is part of a constraint of t with one
+// of the given types, or of any constraint when no types are given.
+func inConstraint(t *core.Table, column string, types ...core.ConstraintType) bool {
+	for _, con := range t.Constraints {
+		if len(types) > 0 && !slices.Contains(types, con.Type) {
+			continue
+		}
+		if slices.Contains(con.Columns, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuildTable renders the rebuild of an existing table into its target
+// definition. It runs once per table; the other changes of the table are
+// covered by it.
+func (r *renderer) rebuildTable(name string) {
+	if r.rebuilt[name] {
+		return
+	}
+	r.rebuilt[name] = true
+
+	from, to := r.from.FindTable(name), r.to.FindTable(name)
+	tmp := rebuildPrefix + name
+	r.createTable(to, tmp)
+	if cols, values := r.copyColumns(from, to); len(cols) > 0 {
+		r.script.Add(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+			quote(tmp), strings.Join(cols, ", "), strings.Join(values, ", "), quote(name)))
+	}
+	r.script.Add(
+		"DROP TABLE "+quote(name),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", quote(tmp), quote(name)),
+	)
+	r.createIndexesAndTriggers(to)
+}
+
+// copyColumns returns the columns that exist in both versions of the table
+// and the expressions that copy their values. Generated columns are
+// computed and cannot be copied. A column that becomes NOT NULL gets its
+// default in place of NULL values.
+func (r *renderer) copyColumns(from, to *core.Table) (cols, values []string) {
+	for _, c := range to.Columns {
+		old := from.FindColumn(c.Name)
+		if old == nil {
+			if !c.Nullable && c.DefaultValue == nil && !c.IsGenerated && !isRowidAlias(to, c) {
+				r.script.Warnf("table %s: new column %s is NOT NULL without a default; the rebuild fails if the table has rows",
+					to.Name, c.Name)
+			}
+			continue
+		}
+		if c.IsGenerated || old.IsGenerated {
+			continue
+		}
+		value := quote(c.Name)
+		if old.Nullable && !c.Nullable && c.DefaultValue != nil {
+			value = fmt.Sprintf("COALESCE(%s, %s)", value, defaultValue(c, *c.DefaultValue))
+		}
+		cols = append(cols, quote(c.Name))
+		values = append(values, value)
+	}
+	return cols, values
+}
@@ -0,0 +1,129 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// createTable renders CREATE TABLE for t under the given name. Rebuilds
+// create the target table under a temporary name. SQLite only checks
+// foreign keys when rows change, so they are always declared inline.
+func (r *renderer) createTable(t *core.Table, name string) {
+	var defs []string
+	for _, c := range t.Columns {
+		defs = append(defs, r.columnDefinition(t, c))
+	}
+	for _, con := range t.Constraints {
+		if con.Type == core.ConstraintPrimaryKey && hasRowidAlias(t) {
+			continue
+		}
+		defs = append(defs, r.constraintDefinition(t, con))
+	}
+
+	stmt := "CREATE TABLE " + quote(name) + " (\n  " + strings.Join(defs, ",\n  ") + "\n)"
+	if opts := tableOptions(t); len(opts) > 0 {
+		stmt += " " + strings.Join(opts, ", ")
+	}
+	r.script.Add(stmt)
+}
+
+func tableOptions(t *core.Table) []string {
+	var opts []string
+	if withoutRowid(t) {
+		opts = append(opts, "WITHOUT ROWID")
+	}
+	if strict(t) {
+		opts = append(opts, "STRICT")
+	}
+	return opts
+}
+
+// createIndexesAndTriggers renders the objects that belong to t but are not
+// part of CREATE TABLE.
+func (r *renderer) createIndexesAndTriggers(t *core.Table) {
+	for _, idx := range t.Indexes {
+		r.createIndex(t, idx)
+	}
+	for _, c := range t.Columns {
+		r.createOnUpdateTrigger(t, c)
+	}
+}
+
+// constraintDefinition renders a named table constraint as it appears in
+// CREATE TABLE.
+func (r *renderer) constraintDefinition(t *core.Table, con *core.Constraint) string {
+	def := "CONSTRAINT " + quote(generate.ConstraintName(t, con)) + " "
+	cols := generate.QuoteList(con.Columns, quote)
+	switch con.Type {
+	case core.ConstraintPrimaryKey:
+		return def + "PRIMARY KEY (" + cols + ")"
+	case core.ConstraintUnique:
+		return def + "UNIQUE (" + cols + ")"
+	case core.ConstraintCheck:
+		if !generate.Enforced(con) {
+			r.script.Warnf("table %s: CHECK constraint %s is NOT ENFORCED, which SQLite does not support; it will be enforced",
+				t.Name, generate.ConstraintName(t, con))
+		}
+		return def + "CHECK (" + con.CheckExpression + ")"
+	default:
+		return def + foreignKey(con)
+	}
+}
+
+func foreignKey(con *core.Constraint) string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		generate.QuoteList(con.Columns, quote),
+		quote(con.ReferencedTable),
+		generate.QuoteList(con.ReferencedColumns, quote))
+	if con.OnDelete != core.RefActionNone {
+		def += " ON DELETE " + string(con.OnDelete)
+	}
+	if con.OnUpdate != core.RefActionNone {
+		def += " ON UPDATE " + string(con.OnUpdate)
+	}
+	return def
+}
+
+// createIndex renders CREATE INDEX for idx. SQLite only has B-tree indexes,
+// so other index types are created as plain indexes.
+func (r *renderer) createIndex(t *core.Table, idx *core.Index) {
+	name := generate.IndexName(t, idx)
+	switch idx.Type {
+	case "", core.IndexTypeBTree:
+	case core.IndexTypeFullText:
+		r.script.Warnf("table %s: FULLTEXT index %s was skipped; use an FTS5 virtual table instead", t.Name, name)
+		return
+	default:
+		r.script.Warnf("table %s: SQLite only supports B-tree indexes; %s is created as one", t.Name, name)
+	}
+	if idx.Visibility == core.IndexInvisible {
+		r.script.Warnf("table %s: SQLite does not support invisible indexes; %s is created visible", t.Name, name)
+	}
+
+	stmt := "CREATE "
+	if idx.Unique {
+		stmt += "UNIQUE "
+	}
+	stmt += fmt.Sprintf("INDEX %s ON %s (%s)", quote(name), quote(t.Name), r.indexColumns(t, idx))
+	if idx.Where != "" {
+		stmt += " WHERE " + idx.Where
+	}
+	r.script.Add(stmt)
+}
+
+func (r *renderer) indexColumns(t *core.Table, idx *core.Index) string {
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		if c.Length > 0 {
+			r.script.Warnf("table %s: SQLite does not support prefix indexes; the length of %s was ignored", t.Name, c.Name)
+		}
+		cols[i] = generate.KeyPart(c, quote)
+		if c.Order == core.SortDesc {
+			cols[i] += " DESC"
+		}
+	}
+	return strings.Join(cols, ", ")
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+)
+
+// SQLite has no ON UPDATE column clause. A column with OnUpdate gets an
+// AFTER UPDATE trigger that sets the column unless the statement changed it
+// itself. Triggers do not fire recursively unless recursive_triggers is on.
+
+func onUpdateName(t *core.Table, c *core.Column) string {
+	return t.Name + "_" + c.Name + "_on_update"
+}
+
+// createOnUpdateTrigger renders the trigger that emulates ON UPDATE for c.
+func (r *renderer) createOnUpdateTrigger(t *core.Table, c *core.Column) {
+	if c.OnUpdate == nil {
+		return
+	}
+	r.script.Add(fmt.Sprintf("CREATE TRIGGER %s AFTER UPDATE ON %s FOR EACH ROW WHEN NEW.%s IS OLD.%s\n"+
+		"BEGIN\n"+
+		"  UPDATE %s SET %s = %s WHERE %s;\n"+
+		"END",
+		quote(onUpdateName(t, c)), quote(t.Name), quote(c.Name), quote(c.Name),
+		quote(t.Name), quote(c.Name), defaultValue(c, *c.OnUpdate), rowMatch(t)))
+}
+
+// rowMatch renders the condition that selects the updated row. WITHOUT
+// ROWID tables are matched on their primary key.
+func rowMatch(t *core.Table) string {
+	pk := t.PrimaryKey()
+	if !withoutRowid(t) || pk == nil {
+		return "rowid = NEW.rowid"
+	}
+	conds := make([]string, len(pk.Columns))
+	for i, col := range pk.Columns {
+		conds[i] = fmt.Sprintf("%s = NEW.%s", quote(col), quote(col))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func (r *renderer) dropOnUpdateTrigger(t *core.Table, c *core.Column) {
+	if c.OnUpdate != nil {
+		r.script.Add("DROP TRIGGER IF EXISTS " + quote(onUpdateName(t, c)))
+	}
+}
+
+// replaceOnUpdateTrigger recreates the ON UPDATE trigger of a column whose
+// on_update expression changed.
+func (r *renderer) replaceOnUpdateTrigger(c *diff.AlterColumn) {
+	key := c.Table.Name + "." + c.New.Name
+	changed := slices.ContainsFunc(c.Fields, func(f diff.FieldChange) bool { return f.Name == "on_update" })
+	if !changed || r.triggers[key] {
+		return
+	}
+	r.triggers[key] = true
+	r.dropOnUpdateTrigger(c.Table, c.Old)
+	r.createOnUpdateTrigger(c.Table, c.New)
+}
@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// viewChange renders the CREATE and DROP VIEW statements of a view change.
+// A materialized view was created as a plain view and is dropped as one.
+func (r *renderer) viewChange(c diff.Change) error {
+	switch c := c.(type) {
+	case *diff.AddView:
+		r.createView(c.View)
+	case *diff.DropView:
+		r.script.Add("DROP VIEW " + quote(c.View.Name))
+	default:
+		return fmt.Errorf("sqlite: unsupported change %T", c)
+	}
+	return nil
+}
+
+// createView renders CREATE VIEW. SQLite views are read-only and run with
+// the privileges of the connection, so they take no check option or
+// security, and SQLite has neither materialized views nor comments.
+func (r *renderer) createView(v *core.View) {
+	if v.Materialized {
+		r.script.Warnf("view %s: SQLite does not support materialized views; it is created as a regular view", v.Name)
+	}
+	if v.CheckOption != core.CheckOptionNone {
+		r.script.Warnf("view %s: SQLite views are read-only; WITH CHECK OPTION was ignored", v.Name)
+	}
+	if v.Security != "" {
+		r.script.Warnf("view %s: SQLite does not support SQL SECURITY; it was ignored", v.Name)
+	}
+	r.script.Add(fmt.Sprintf("CREATE VIEW %s%s AS %s", quote(v.Name), generate.ViewColumns(v, quote), generate.ViewDefinition(v)))
+}
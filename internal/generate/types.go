@@ -0,0 +1,142 @@
+package generate
+
+import (
+	"strings"
+
+	"smf/internal/core"
+)
+
+// TypeSpec is a portable type string split into its parts.
+//
+//	"decimal(10,2) unsigned" -> {Base: "decimal", Args: ["10", "2"], Suffix: "unsigned"}
+type TypeSpec struct {
+	Base   string
+	Args   []string
+	Suffix string
+}
+
+// ParseType splits a type string into a lower-cased base name, the
+// comma-separated arguments inside the parentheses, and any trailing
+// modifiers.
+func ParseType(s string) TypeSpec {
+	s = strings.TrimSpace(s)
+	open := strings.IndexByte(s, '(')
+	closing := strings.LastIndexByte(s, ')')
+	if open < 0 || closing < open {
+		return splitModifiers(normalizeBase(s))
+	}
+
+	var args []string
+	for arg := range strings.SplitSeq(s[open+1:closing], ",") {
+		if arg = strings.TrimSpace(arg); arg != "" {
+			args = append(args, arg)
+		}
+	}
+	return TypeSpec{
+		Base:   normalizeBase(s[:open]),
+		Args:   args,
+		Suffix: strings.ToLower(strings.TrimSpace(s[closing+1:])),
+	}
+}
+
+// typeModifiers are trailing keywords that modify a numeric type.
+var typeModifiers = map[string]bool{"unsigned": true, "signed": true, "zerofill": true}
+
+// splitModifiers moves trailing modifiers of a type without arguments
+// ("int unsigned") to the suffix.
+func splitModifiers(base string) TypeSpec {
+	words := strings.Fields(base)
+	n := len(words)
+	for n > 1 && typeModifiers[words[n-1]] {
+		n--
+	}
+	return TypeSpec{Base: strings.Join(words[:n], " "), Suffix: strings.Join(words[n:], " ")}
+}
+
+func normalizeBase(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// TypeMapper maps portable column types to dialect-specific SQL types.
+type TypeMapper struct {
+	// Types maps lower-cased portable base types to dialect types. A "%s"
+	// in the target receives the declared arguments (e.g. "VARCHAR(%s)");
+	// when the portable type has no arguments, DefaultArgs is used, and
+	// without a default the parenthesized placeholder is dropped.
+	Types map[string]string
+	// DefaultArgs holds arguments for base types that require them.
+	DefaultArgs map[string]string
+	// Fallback maps a normalized data type to the dialect type used when a
+	// column has no portable type string (e.g. injected timestamp columns).
+	Fallback map[core.DataType]string
+	// StripSuffix drops trailing modifiers such as UNSIGNED or ZEROFILL that
+	// the dialect does not support.
+	StripSuffix bool
+}
+
+// ColumnType returns the SQL type for a column. An explicit RawType always
+// wins. Portable base types that are not in the mapping are passed through
+// upper-cased, so dialect types written in the portable field keep working.
+func (m *TypeMapper) ColumnType(c *core.Column) string {
+	if c.RawType != "" {
+		return c.RawType
+	}
+	if c.PortableType == "" {
+		return m.Fallback[c.Type]
+	}
+	return m.Map(c.PortableType)
+}
+
+// Map converts a single portable type string.
+func (m *TypeMapper) Map(portable string) string {
+	spec := ParseType(portable)
+	target, ok := m.Types[spec.Base]
+	if !ok {
+		return upperBase(portable)
+	}
+
+	args := strings.Join(spec.Args, ",")
+	if args == "" {
+		args = m.DefaultArgs[spec.Base]
+	}
+	var out string
+	switch {
+	case !strings.Contains(target, "%s"):
+		out = target
+	case args == "":
+		out = strings.Replace(strings.Replace(target, "(%s)", "", 1), "%s", "", 1)
+	default:
+		out = strings.Replace(target, "%s", args, 1)
+	}
+
+	if spec.Suffix != "" && !m.StripSuffix {
+		out += " " + strings.ToUpper(spec.Suffix)
+	}
+	return out
+}
+
+// TypeArgs returns the declared arguments of a column's portable type,
+// e.g. ["255"] for "varchar(255)".
+func TypeArgs(c *core.Column) []string {
+	return ParseType(c.PortableType).Args
+}
+
+// TypeBase returns the lower-cased base of a column's declared type,
+// preferring RawType over PortableType.
+func TypeBase(c *core.Column) string {
+	if c.RawType != "" {
+		return ParseType(c.RawType).Base
+	}
+	return ParseType(c.PortableType).Base
+}
+
+// upperBase upper-cases the type name of a type string but keeps the
+// parenthesized arguments verbatim, so that enum or set values keep their case.
+func upperBase(s string) string {
+	s = strings.TrimSpace(s)
+	open := strings.IndexByte(s, '(')
+	if open < 0 {
+		return strings.ToUpper(s)
+	}
+	return strings.ToUpper(s[:open]) + s[open:]
+}
@@ -0,0 +1,41 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestParseType(t *testing.T) {
+	assert.Equal(t, TypeSpec{Base: "varchar", Args: []string{"255"}}, ParseType("VARCHAR( 255 )"))
+	assert.Equal(t, TypeSpec{Base: "decimal", Args: []string{"10", "2"}, Suffix: "unsigned"}, ParseType("decimal(10, 2) UNSIGNED"))
+	assert.Equal(t, TypeSpec{Base: "double precision"}, ParseType("double   precision"))
+	assert.Equal(t, TypeSpec{Base: "int", Suffix: "unsigned zerofill"}, ParseType("INT UNSIGNED ZEROFILL"))
+}
+
+func TestTypeMapperMap(t *testing.T) {
+	m := &TypeMapper{
+		Types:       map[string]string{"varchar": "VARCHAR(%s)", "decimal": "NUMERIC(%s)", "text": "CLOB"},
+		DefaultArgs: map[string]string{"varchar": "255"},
+	}
+
+	assert.Equal(t, "VARCHAR(64)", m.Map("varchar(64)"))
+	assert.Equal(t, "VARCHAR(255)", m.Map("varchar"))
+	assert.Equal(t, "NUMERIC", m.Map("decimal"))
+	assert.Equal(t, "NUMERIC(10,2)", m.Map("decimal(10,2)"))
+	assert.Equal(t, "CLOB", m.Map("text"))
+	assert.Equal(t, "ENUM('Free','Pro')", m.Map("enum('Free','Pro')"))
+}
+
+func TestTypeMapperColumnType(t *testing.T) {
+	m := &TypeMapper{
+		Types:    map[string]string{"varchar": "VARCHAR(%s)"},
+		Fallback: map[core.DataType]string{core.DataTypeDatetime: "TIMESTAMP"},
+	}
+
+	assert.Equal(t, "JSONB", m.ColumnType(&core.Column{RawType: "JSONB", PortableType: "json"}))
+	assert.Equal(t, "VARCHAR(10)", m.ColumnType(&core.Column{PortableType: "varchar(10)"}))
+	assert.Equal(t, "TIMESTAMP", m.ColumnType(&core.Column{Type: core.DataTypeDatetime}))
+}
@@ -0,0 +1,289 @@
+package db2
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+	"smf/internal/introspect"
+)
+
+// portableTypes maps the types printed by formatType to the portable type
+// they are written as, and whether the portable type takes arguments. The
+// entries follow the types the generator declares; SMALLINT is read as
+// smallint, although tinyint columns are declared as SMALLINT too. Every
+// other type is kept as a raw type.
+var portableTypes = map[string]struct {
+	name     string
+	takesArg bool
+}{
+	"VARCHAR":               {"varchar", true},
+	"CHAR":                  {"char", true},
+	"CHAR(16) FOR BIT DATA": {"uuid", false},
+	"CLOB":                  {"text", false},
+	"SMALLINT":              {"smallint", false},
+	"INTEGER":               {"int", false},
+	"BIGINT":                {"bigint", false},
+	"DECIMAL":               {"decimal", true},
+	"REAL":                  {"float", false},
+	"DOUBLE":                {"double", false},
+	"BOOLEAN":               {"boolean", false},
+	"DATE":                  {"date", false},
+	"TIME":                  {"time", false},
+	"TIMESTAMP":             {"datetime", false},
+	"BLOB":                  {"blob", false},
+	"BINARY":                {"binary", true},
+	"VARBINARY":             {"varbinary", true},
+}
+
+// Lengths and precisions that the type names imply when they are declared
+// without arguments.
+const (
+	defaultLOBLength          = 1048576
+	defaultTimestampPrecision = 6
+	defaultDecfloatPrecision  = 34
+)
+
+var (
+	stringLiteralRe = regexp.MustCompile(`^'((?:[^']|'')*)'$`)
+	asKeywordRe     = regexp.MustCompile(`(?i)^AS\b\s*`)
+)
+
+// columnRow holds a row of the columns query.
+type columnRow struct {
+	table        string
+	col          *core.Column
+	typeName     string
+	length       int
+	scale        int
+	codepage     int
+	nulls        string
+	defaultExpr  sql.NullString
+	identity     string
+	generated    string
+	text         sql.NullString
+	hidden       string
+	inlineLength int
+	compress     string
+	comment      sql.NullString
+	start        sql.NullInt64
+	increment    sql.NullInt64
+}
+
+// queryColumns reads the columns of the tables, including implicitly hidden
+// ones. The period columns of system-period temporal tables have no
+// counterpart in the schema and are left out.
+func queryColumns(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT c.TABNAME, c.COLNAME, c.TYPENAME, c.LENGTH, c.SCALE, c.CODEPAGE, c.NULLS, c.DEFAULT,
+            c.IDENTITY, c.GENERATED, c.TEXT, c.HIDDEN, c.INLINE_LENGTH, c.COMPRESS, c.REMARKS,
+            CAST(a.START AS BIGINT), CAST(a.INCREMENT AS BIGINT)
+        FROM SYSCAT.COLUMNS c
+        JOIN SYSCAT.TABLES t ON t.TABSCHEMA = c.TABSCHEMA AND t.TABNAME = c.TABNAME
+        LEFT JOIN SYSCAT.COLIDENTATTRIBUTES a ON a.TABSCHEMA = c.TABSCHEMA
+            AND a.TABNAME = c.TABNAME
+            AND a.COLNAME = c.COLNAME
+        WHERE ` + tableFilter + `
+            AND c.HIDDEN <> 'S'
+            AND c.ROWBEGIN = 'N'
+            AND c.ROWEND = 'N'
+            AND c.TRANSACTIONSTARTID = 'N'
+        ORDER BY c.TABNAME, c.COLNO
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := columnRow{col: &core.Column{}}
+		err := rows.Scan(&r.table, &r.col.Name, &r.typeName, &r.length, &r.scale, &r.codepage, &r.nulls,
+			&r.defaultExpr, &r.identity, &r.generated, &r.text, &r.hidden, &r.inlineLength, &r.compress,
+			&r.comment, &r.start, &r.increment)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(r.table)
+		if err != nil {
+			return err
+		}
+		r.build()
+		table.Columns = append(table.Columns, r.col)
+	}
+	return rows.Err()
+}
+
+// build fills col from the rest of the row. An implicitly hidden column is
+// written as invisible, which the generator declares IMPLICITLY HIDDEN.
+func (r *columnRow) build() {
+	col := r.col
+	col.Nullable = r.nulls == "Y"
+	col.Invisible = r.hidden == "I"
+	col.Comment = r.comment.String
+	typ := formatType(r)
+	setColumnType(col, typ)
+
+	switch {
+	case r.identity == "Y":
+		r.setIdentity()
+	case r.generated == "A" && r.text.Valid:
+		col.IsGenerated = true
+		col.GenerationExpression = generationExpression(r.text.String)
+		col.GenerationStorage = core.GenerationStored
+	case r.defaultExpr.Valid:
+		col.DefaultValue = columnDefault(col, strings.TrimSpace(r.defaultExpr.String))
+	}
+	r.setOptions(typ)
+}
+
+// setIdentity marks an identity column as auto-increment. GENERATED ALWAYS
+// is the default of the schema, so only BY DEFAULT is written, and a start
+// or increment of 1 is left unset.
+func (r *columnRow) setIdentity() {
+	col := r.col
+	col.AutoIncrement = true
+	if r.generated == "D" {
+		col.IdentityGeneration = core.IdentityByDefault
+	}
+	if r.start.Valid && r.start.Int64 != 1 {
+		col.IdentitySeed = r.start.Int64
+	}
+	if r.increment.Valid && r.increment.Int64 != 1 {
+		col.IdentityIncrement = r.increment.Int64
+	}
+}
+
+// setOptions sets the Db2 options of the column. The inline length is
+// only read for LOB columns; value compression is COMPRESS SYSTEM DEFAULT.
+func (r *columnRow) setOptions(typ string) {
+	opts := &core.DB2ColumnOptions{}
+	if r.inlineLength > 0 && isLOB(typ) {
+		opts.InlineLength = new(r.inlineLength)
+	}
+	if r.compress == "S" {
+		opts.Compress = new(true)
+	}
+	if opts.InlineLength != nil || opts.Compress != nil {
+		r.col.DB2 = opts
+	}
+}
+
+// isLOB reports whether a type printed by formatType stores its values as
+// large objects.
+func isLOB(typ string) bool {
+	base, _, _ := strings.Cut(typ, "(")
+	switch base {
+	case "CLOB", "DBCLOB", "BLOB", "XML":
+		return true
+	default:
+		return false
+	}
+}
+
+// generationExpression returns the expression of a generated column from
+// the TEXT Db2 keeps for it, which starts with the keyword AS.
+//
+// Example input: "AS (price * quantity)".
+func generationExpression(text string) string {
+	return introspect.StripParens(asKeywordRe.ReplaceAllString(strings.TrimSpace(text), ""))
+}
+
+// formatType returns the type of a column as it is declared from its entry
+// in SYSCAT.COLUMNS. LENGTH holds the precision of DECIMAL and DECFLOAT
+// columns, and SCALE the fractional second precision of TIMESTAMP columns.
+//
+// Example output: "VARCHAR(255)", "DECIMAL(10,2)", "CHAR(16) FOR BIT DATA".
+func formatType(r *columnRow) string {
+	typ := strings.TrimSpace(r.typeName)
+	switch typ {
+	case "CHARACTER":
+		return characterType("CHAR", r.length, r.codepage)
+	case "VARCHAR":
+		return characterType(typ, r.length, r.codepage)
+	case "GRAPHIC", "VARGRAPHIC", "BINARY", "VARBINARY":
+		return fmt.Sprintf("%s(%d)", typ, r.length)
+	case "CLOB", "DBCLOB", "BLOB":
+		return optionalArg(typ, r.length, defaultLOBLength)
+	case "DECFLOAT":
+		return optionalArg(typ, r.length, defaultDecfloatPrecision)
+	case "TIMESTAMP":
+		return optionalArg(typ, r.scale, defaultTimestampPrecision)
+	case "DECIMAL":
+		if r.scale == 0 {
+			return fmt.Sprintf("DECIMAL(%d)", r.length)
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", r.length, r.scale)
+	default:
+		return typ
+	}
+}
+
+// characterType formats a character string type. Strings declared
+// FOR BIT DATA have a code page of 0.
+func characterType(typ string, length, codepage int) string {
+	typ = fmt.Sprintf("%s(%d)", typ, length)
+	if codepage == 0 {
+		typ += " FOR BIT DATA"
+	}
+	return typ
+}
+
+// optionalArg formats a type whose argument is left out when it is the
+// one the type name implies.
+func optionalArg(typ string, arg, implied int) string {
+	if arg == implied {
+		return typ
+	}
+	return fmt.Sprintf("%s(%d)", typ, arg)
+}
+
+// setColumnType sets the type of col from the type printed by formatType.
+// Types that the portable type names describe exactly are written as
+// portable types.
+func setColumnType(col *core.Column, typ string) {
+	if p, ok := portableTypes[typ]; ok && !p.takesArg {
+		col.PortableType = p.name
+		col.Type = core.NormalizeDataType(col.PortableType)
+		return
+	}
+	base, args, _ := strings.Cut(typ, "(")
+	if p, ok := portableTypes[base]; ok && p.takesArg && strings.HasSuffix(args, ")") {
+		col.PortableType = p.name + "(" + args
+		col.Type = core.NormalizeDataType(col.PortableType)
+		return
+	}
+	col.RawType = typ
+	col.Type = core.NormalizeDataType(typ)
+}
+
+// columnDefault returns the default value for a default expression as it
+// is stored in SYSCAT.COLUMNS.DEFAULT. A string literal is stored unquoted,
+// as it is written in the schema, unless it would then read as something
+// else: a number is only unquoted for numeric columns, and keywords and
+// expressions keep their quotes. Other expressions are kept as stored.
+//
+// Example input: "'active'", "0", "CURRENT TIMESTAMP".
+func columnDefault(col *core.Column, expr string) *string {
+	if strings.EqualFold(expr, "NULL") {
+		return nil
+	}
+	m := stringLiteralRe.FindStringSubmatch(expr)
+	if m == nil {
+		return &expr
+	}
+
+	v := strings.ReplaceAll(m[1], "''", "'")
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return &v
+	case generate.DefaultNumber:
+		if col.Type == core.DataTypeInt || col.Type == core.DataTypeFloat {
+			return &v
+		}
+	}
+	return &expr
+}
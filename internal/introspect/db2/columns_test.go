@@ -0,0 +1,109 @@
+package db2
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestFormatType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		row  columnRow
+		want string
+	}{
+		{columnRow{typeName: "VARCHAR", length: 255, codepage: 1208}, "VARCHAR(255)"},
+		{columnRow{typeName: "CHARACTER", length: 16}, "CHAR(16) FOR BIT DATA"},
+		{columnRow{typeName: "CHARACTER", length: 2, codepage: 1208}, "CHAR(2)"},
+		{columnRow{typeName: "CLOB", length: 1048576, codepage: 1208}, "CLOB"},
+		{columnRow{typeName: "BLOB", length: 2097152}, "BLOB(2097152)"},
+		{columnRow{typeName: "DECIMAL", length: 10}, "DECIMAL(10)"},
+		{columnRow{typeName: "DECIMAL", length: 10, scale: 2}, "DECIMAL(10,2)"},
+		{columnRow{typeName: "TIMESTAMP", length: 10, scale: 6}, "TIMESTAMP"},
+		{columnRow{typeName: "TIMESTAMP", length: 7, scale: 0}, "TIMESTAMP(0)"},
+		{columnRow{typeName: "DECFLOAT", length: 16}, "DECFLOAT(16)"},
+		{columnRow{typeName: "INTEGER", length: 4}, "INTEGER"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, formatType(&tt.row))
+		})
+	}
+}
+
+func TestSetColumnType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		typ  string
+		want core.Column
+	}{
+		{"VARCHAR(255)", core.Column{Type: core.DataTypeString, PortableType: "varchar(255)"}},
+		{"VARCHAR(20) FOR BIT DATA", core.Column{Type: core.DataTypeString, RawType: "VARCHAR(20) FOR BIT DATA"}},
+		{"CHAR(16) FOR BIT DATA", core.Column{Type: core.DataTypeUUID, PortableType: "uuid"}},
+		{"CLOB", core.Column{Type: core.DataTypeString, PortableType: "text"}},
+		{"INTEGER", core.Column{Type: core.DataTypeInt, PortableType: "int"}},
+		{"DECIMAL(10,2)", core.Column{Type: core.DataTypeFloat, PortableType: "decimal(10,2)"}},
+		{"TIMESTAMP", core.Column{Type: core.DataTypeDatetime, PortableType: "datetime"}},
+		{"TIMESTAMP(0)", core.Column{Type: core.DataTypeDatetime, RawType: "TIMESTAMP(0)"}},
+		{"VARBINARY(32)", core.Column{Type: core.DataTypeBinary, PortableType: "varbinary(32)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			t.Parallel()
+			var col core.Column
+			setColumnType(&col, tt.typ)
+			assert.Equal(t, tt.want, col)
+		})
+	}
+}
+
+func TestColumnDefault(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		expr string
+		typ  core.DataType
+		want *string
+	}{
+		{"NULL", core.DataTypeString, nil},
+		{"'active'", core.DataTypeString, new("active")},
+		{"'it''s'", core.DataTypeString, new("it's")},
+		{"'01234'", core.DataTypeString, new("'01234'")},
+		{"'5'", core.DataTypeInt, new("5")},
+		{"-1", core.DataTypeInt, new("-1")},
+		{"CURRENT TIMESTAMP", core.DataTypeDatetime, new("CURRENT TIMESTAMP")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, columnDefault(&core.Column{Type: tt.typ}, tt.expr))
+		})
+	}
+}
+
+func TestColumnRowBuild(t *testing.T) {
+	t.Parallel()
+	valid := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+
+	r := &columnRow{col: &core.Column{Name: "id"}, typeName: "BIGINT", nulls: "N", identity: "Y", generated: "D",
+		start: sql.NullInt64{Int64: 100, Valid: true}, increment: sql.NullInt64{Int64: 1, Valid: true}}
+	r.build()
+	assert.Equal(t, &core.Column{Name: "id", Type: core.DataTypeInt, PortableType: "bigint", AutoIncrement: true,
+		IdentityGeneration: core.IdentityByDefault, IdentitySeed: 100}, r.col)
+
+	r = &columnRow{col: &core.Column{Name: "total"}, typeName: "DOUBLE", nulls: "Y", generated: "A",
+		text: valid("AS (price * quantity)")}
+	r.build()
+	assert.Equal(t, &core.Column{Name: "total", Type: core.DataTypeFloat, PortableType: "double", Nullable: true,
+		IsGenerated: true, GenerationExpression: "price * quantity", GenerationStorage: core.GenerationStored}, r.col)
+
+	r = &columnRow{col: &core.Column{Name: "notes"}, typeName: "CLOB", length: 1048576, codepage: 1208, nulls: "Y",
+		hidden: "I", inlineLength: 1000, compress: "S", defaultExpr: valid("'none'"), comment: valid("Free text")}
+	r.build()
+	assert.Equal(t, &core.Column{Name: "notes", Type: core.DataTypeString, PortableType: "text", Nullable: true,
+		Invisible: true, DefaultValue: new("none"), Comment: "Free text",
+		DB2: &core.DB2ColumnOptions{InlineLength: new(1000), Compress: new(true)}}, r.col)
+}
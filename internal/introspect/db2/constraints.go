@@ -0,0 +1,234 @@
+package db2
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// referentialActions maps the DELETERULE and UPDATERULE codes of
+// SYSCAT.REFERENCES to the action they stand for. NO ACTION is the default
+// and is left unset.
+var referentialActions = map[string]core.ReferentialAction{
+	"A": core.RefActionNone,
+	"C": core.RefActionCascade,
+	"N": core.RefActionSetNull,
+	"R": core.RefActionRestrict,
+}
+
+// constraintTypes maps SYSCAT.TABCONST.TYPE to the constraint type it
+// stands for.
+var constraintTypes = map[string]core.ConstraintType{
+	"P": core.ConstraintPrimaryKey,
+	"U": core.ConstraintUnique,
+	"F": core.ConstraintForeignKey,
+	"K": core.ConstraintCheck,
+}
+
+var (
+	// enumCheckRe matches the CHECK constraint the generator declares for an
+	// enum column: `"status" IN ('new', 'paid')`.
+	enumCheckRe = regexp.MustCompile(`^"((?:[^"]|"")+)" IN \((.*)\)$`)
+
+	// enumValueRe matches a value of the list of such a constraint, and the
+	// comma that follows it.
+	enumValueRe = regexp.MustCompile(`^\s*'((?:[^']|'')*)'\s*(,|$)`)
+)
+
+// constraintKey identifies a constraint by the schema and table it belongs
+// to and its name.
+type constraintKey struct {
+	schema string
+	table  string
+	name   string
+}
+
+// constraintRow holds a row of the constraints query.
+type constraintRow struct {
+	schema     string
+	table      string
+	con        *core.Constraint
+	typ        string
+	enforced   string
+	refSchema  sql.NullString
+	refTable   sql.NullString
+	refKey     sql.NullString
+	deleteRule sql.NullString
+	updateRule sql.NullString
+	text       sql.NullString
+}
+
+// queryConstraints reads the primary key, unique, foreign key, and CHECK
+// constraints of the tables. The CHECK constraints Db2 creates for
+// generated columns and the informational functional dependencies are not
+// read.
+func queryConstraints(ic *introspectCtx, set *introspect.TableSet) error {
+	keys, err := queryKeyColumns(ic)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        SELECT tc.TABSCHEMA, tc.TABNAME, tc.CONSTNAME, tc.TYPE, tc.ENFORCED,
+            r.REFTABSCHEMA, r.REFTABNAME, r.REFKEYNAME, r.DELETERULE, r.UPDATERULE,
+            ch.TEXT
+        FROM SYSCAT.TABCONST tc
+        JOIN SYSCAT.TABLES t ON t.TABSCHEMA = tc.TABSCHEMA AND t.TABNAME = tc.TABNAME
+        LEFT JOIN SYSCAT.REFERENCES r ON r.TABSCHEMA = tc.TABSCHEMA
+            AND r.TABNAME = tc.TABNAME
+            AND r.CONSTNAME = tc.CONSTNAME
+        LEFT JOIN SYSCAT.CHECKS ch ON ch.TABSCHEMA = tc.TABSCHEMA
+            AND ch.TABNAME = tc.TABNAME
+            AND ch.CONSTNAME = tc.CONSTNAME
+        WHERE ` + tableFilter + `
+            AND tc.TYPE IN ('P', 'U', 'F', 'K')
+            AND (ch.TYPE IS NULL OR ch.TYPE = 'C')
+        ORDER BY tc.TABNAME, tc.CONSTNAME
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := constraintRow{con: &core.Constraint{}}
+		err := rows.Scan(&r.schema, &r.table, &r.con.Name, &r.typ, &r.enforced, &r.refSchema, &r.refTable,
+			&r.refKey, &r.deleteRule, &r.updateRule, &r.text)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(r.table)
+		if err != nil {
+			return err
+		}
+		r.build(keys)
+		if r.con.Type == core.ConstraintCheck && setEnum(table, r.con) {
+			continue
+		}
+		table.Constraints = append(table.Constraints, r.con)
+		if r.con.Type == core.ConstraintPrimaryKey {
+			markPrimaryKey(table, r.con.Columns)
+		}
+	}
+	return rows.Err()
+}
+
+// queryKeyColumns reads the columns of the constraints of the schema, and
+// of the keys in other schemas that its foreign keys refer to. A CHECK
+// constraint lists the columns it references, in no particular order.
+func queryKeyColumns(ic *introspectCtx) (map[constraintKey][]string, error) {
+	query := `
+        SELECT k.TABSCHEMA, k.TABNAME, k.CONSTNAME, k.COLNAME, k.COLSEQ
+        FROM SYSCAT.KEYCOLUSE k
+        WHERE k.TABSCHEMA = CURRENT SCHEMA
+            OR EXISTS (
+                SELECT 1
+                FROM SYSCAT.REFERENCES r
+                WHERE r.TABSCHEMA = CURRENT SCHEMA
+                    AND r.REFTABSCHEMA = k.TABSCHEMA
+                    AND r.REFTABNAME = k.TABNAME
+                    AND r.REFKEYNAME = k.CONSTNAME
+            )
+        UNION ALL
+        SELECT c.TABSCHEMA, c.TABNAME, c.CONSTNAME, c.COLNAME, 0
+        FROM SYSCAT.COLCHECKS c
+        WHERE c.TABSCHEMA = CURRENT SCHEMA
+            AND c.USAGE = 'R'
+        ORDER BY 1, 2, 3, 5, 4
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make(map[constraintKey][]string)
+	for rows.Next() {
+		var key constraintKey
+		var column string
+		var seq int
+		if err := rows.Scan(&key.schema, &key.table, &key.name, &column, &seq); err != nil {
+			return nil, err
+		}
+		keys[key] = append(keys[key], column)
+	}
+	return keys, rows.Err()
+}
+
+// build fills con from the rest of the row and the columns of the
+// constraints. A constraint declared NOT ENFORCED is written as not
+// enforced.
+func (r *constraintRow) build(keys map[constraintKey][]string) {
+	con := r.con
+	con.Type = constraintTypes[r.typ]
+	con.Columns = keys[constraintKey{r.schema, r.table, con.Name}]
+	switch con.Type {
+	case core.ConstraintForeignKey:
+		con.ReferencedTable = r.refTable.String
+		con.ReferencedColumns = keys[constraintKey{r.refSchema.String, r.refTable.String, r.refKey.String}]
+		con.OnDelete = referentialActions[r.deleteRule.String]
+		con.OnUpdate = referentialActions[r.updateRule.String]
+	case core.ConstraintCheck:
+		con.CheckExpression = strings.TrimSpace(r.text.String)
+	}
+	if r.enforced == "N" {
+		con.Enforced = new(false)
+	}
+}
+
+func markPrimaryKey(table *core.Table, columns []string) {
+	for _, name := range columns {
+		if col := table.FindColumn(name); col != nil {
+			col.PrimaryKey = true
+		}
+	}
+}
+
+// setEnum makes a character column an enum when con is the CHECK
+// constraint that the generator declares for an enum column, named
+// chk_{table}_{column}_enum in any case.
+func setEnum(table *core.Table, con *core.Constraint) bool {
+	if len(con.Columns) != 1 || !strings.EqualFold(con.Name, "chk_"+table.Name+"_"+con.Columns[0]+"_enum") {
+		return false
+	}
+	col := table.FindColumn(con.Columns[0])
+	if col == nil || col.Type != core.DataTypeString {
+		return false
+	}
+	values, ok := enumValues(col.Name, con.CheckExpression)
+	if !ok {
+		return false
+	}
+	col.Type = core.DataTypeEnum
+	col.EnumValues = values
+	col.PortableType = core.BuildEnumTypeRaw(values)
+	col.RawType = ""
+	return true
+}
+
+// enumValues returns the strings of a check of the form
+// `"column" IN ('a', ...)` on the given column.
+func enumValues(column, check string) ([]string, bool) {
+	m := enumCheckRe.FindStringSubmatch(check)
+	if m == nil || strings.ReplaceAll(m[1], `""`, `"`) != column {
+		return nil, false
+	}
+	var values []string
+	rest := m[2]
+	for rest != "" {
+		v := enumValueRe.FindStringSubmatch(rest)
+		if v == nil {
+			return nil, false
+		}
+		values = append(values, strings.ReplaceAll(v[1], "''", "'"))
+		rest = rest[len(v[0]):]
+		if v[2] == "," && rest == "" {
+			return nil, false
+		}
+	}
+	return values, len(values) > 0
+}
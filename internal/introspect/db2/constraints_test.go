@@ -0,0 +1,94 @@
+package db2
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestConstraintRowBuild(t *testing.T) {
+	t.Parallel()
+	valid := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+	keys := map[constraintKey][]string{
+		{"APP", "orders", "fk_orders_users"}: {"tenant_id", "user_id"},
+		{"AUTH", "users", "pk_users"}:        {"tenant_id", "id"},
+	}
+
+	r := constraintRow{
+		schema:     "APP",
+		table:      "orders",
+		con:        &core.Constraint{Name: "fk_orders_users"},
+		typ:        "F",
+		enforced:   "N",
+		refSchema:  valid("AUTH"),
+		refTable:   valid("users"),
+		refKey:     valid("pk_users"),
+		deleteRule: valid("C"),
+		updateRule: valid("R"),
+	}
+	r.build(keys)
+	assert.Equal(t, &core.Constraint{
+		Name:              "fk_orders_users",
+		Type:              core.ConstraintForeignKey,
+		Columns:           []string{"tenant_id", "user_id"},
+		ReferencedTable:   "users",
+		ReferencedColumns: []string{"tenant_id", "id"},
+		OnDelete:          core.RefActionCascade,
+		OnUpdate:          core.RefActionRestrict,
+		Enforced:          new(false),
+	}, r.con)
+
+	r = constraintRow{
+		schema:   "APP",
+		table:    "orders",
+		con:      &core.Constraint{Name: "chk_orders_qty"},
+		typ:      "K",
+		enforced: "Y",
+		text:     valid(" qty > 0 "),
+	}
+	r.build(keys)
+	assert.Equal(t, &core.Constraint{Name: "chk_orders_qty", Type: core.ConstraintCheck, CheckExpression: "qty > 0"}, r.con)
+}
+
+func TestSetEnum(t *testing.T) {
+	t.Parallel()
+	table := &core.Table{Name: "orders", Columns: []*core.Column{
+		{Name: "status", Type: core.DataTypeString, PortableType: "varchar(4)"},
+		{Name: "qty", Type: core.DataTypeInt, PortableType: "int"},
+	}}
+	enum := &core.Constraint{Name: "CHK_ORDERS_STATUS_ENUM", Type: core.ConstraintCheck, Columns: []string{"status"},
+		CheckExpression: `"status" IN ('new', 'it''s')`}
+	assert.True(t, setEnum(table, enum))
+	assert.Equal(t, &core.Column{Name: "status", Type: core.DataTypeEnum, PortableType: "enum('new','it''s')",
+		EnumValues: []string{"new", "it's"}}, table.Columns[0])
+
+	check := &core.Constraint{Name: "chk_orders_qty_enum", Type: core.ConstraintCheck, Columns: []string{"qty"},
+		CheckExpression: `"qty" IN ('1')`}
+	assert.False(t, setEnum(table, check))
+}
+
+func TestEnumValues(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		check string
+		want  []string
+	}{
+		{`"status" IN ('new', 'paid')`, []string{"new", "paid"}},
+		{`"status" IN ('a, b','c')`, []string{"a, b", "c"}},
+		{`"status" IN ('new',)`, nil},
+		{`"status" IN (0, 1)`, nil},
+		{`"kind" IN ('new')`, nil},
+		{`"status" = 'new'`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.check, func(t *testing.T) {
+			t.Parallel()
+			got, ok := enumValues("status", tt.check)
+			assert.Equal(t, tt.want != nil, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
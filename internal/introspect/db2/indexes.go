@@ -0,0 +1,129 @@
+package db2
+
+import (
+	"database/sql"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// indexTypes maps SYSCAT.INDEXES.INDEXTYPE to the index type it stands for.
+// Regular and clustering indexes are both B-trees. Block indexes of
+// multidimensional clustering tables, XML indexes, and the other index
+// types have no counterpart in the schema and are not read.
+var indexTypes = map[string]core.IndexType{
+	"REG":  core.IndexTypeBTree,
+	"CLUS": core.IndexTypeBTree,
+}
+
+// indexKey identifies an index among the indexes of the tables. An index
+// may belong to another schema than its table.
+type indexKey struct {
+	schema string
+	index  string
+}
+
+// queryIndexes reads the indexes of the tables and then their key columns.
+// The indexes that back a primary key or unique constraint are part of the
+// constraint and are not read.
+func queryIndexes(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT i.TABNAME, i.INDSCHEMA, i.INDNAME, i.INDEXTYPE, i.UNIQUERULE, i.REMARKS
+        FROM SYSCAT.INDEXES i
+        JOIN SYSCAT.TABLES t ON t.TABSCHEMA = i.TABSCHEMA AND t.TABNAME = i.TABNAME
+        WHERE ` + tableFilter + `
+            AND i.UNIQUERULE <> 'P'
+            AND NOT EXISTS (
+                SELECT 1
+                FROM SYSCAT.CONSTDEP d
+                WHERE d.BTYPE = 'I'
+                    AND d.BSCHEMA = i.INDSCHEMA
+                    AND d.BNAME = i.INDNAME
+            )
+        ORDER BY i.TABNAME, i.INDNAME
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	indexes := make(map[indexKey]*core.Index)
+	for rows.Next() {
+		idx := &core.Index{Visibility: core.IndexVisible}
+		var tableName, schema, indexType, uniqueRule string
+		var comment sql.NullString
+		err := rows.Scan(&tableName, &schema, &idx.Name, &indexType, &uniqueRule, &comment)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(tableName)
+		if err != nil {
+			return err
+		}
+		typ, ok := indexTypes[strings.TrimSpace(indexType)]
+		if !ok {
+			continue
+		}
+		idx.Type = typ
+		idx.Unique = uniqueRule == "U"
+		idx.Comment = comment.String
+		table.Indexes = append(table.Indexes, idx)
+		indexes[indexKey{schema, idx.Name}] = idx
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return queryIndexColumns(ic, indexes)
+}
+
+// queryIndexColumns adds the key columns to the indexes. The INCLUDE
+// columns of unique indexes have no counterpart in the schema.
+func queryIndexColumns(ic *introspectCtx, indexes map[indexKey]*core.Index) error {
+	query := `
+        SELECT c.INDSCHEMA, c.INDNAME, c.COLNAME, c.COLORDER, c.TEXT
+        FROM SYSCAT.INDEXCOLUSE c
+        JOIN SYSCAT.INDEXES i ON i.INDSCHEMA = c.INDSCHEMA AND i.INDNAME = c.INDNAME
+        JOIN SYSCAT.TABLES t ON t.TABSCHEMA = i.TABSCHEMA AND t.TABNAME = i.TABNAME
+        WHERE ` + tableFilter + `
+            AND c.COLORDER <> 'I'
+        ORDER BY c.INDSCHEMA, c.INDNAME, c.COLSEQ
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key indexKey
+		var columnName, order string
+		var expression sql.NullString
+		if err := rows.Scan(&key.schema, &key.index, &columnName, &order, &expression); err != nil {
+			return err
+		}
+		idx, ok := indexes[key]
+		if !ok {
+			continue
+		}
+		idx.Columns = append(idx.Columns, indexColumn(columnName, order, expression))
+	}
+	return rows.Err()
+}
+
+// indexColumn returns a key column of an index. The key of an
+// expression-based index has a generated column name and keeps the
+// expression in TEXT.
+func indexColumn(name, order string, expression sql.NullString) core.ColumnIndex {
+	col := core.ColumnIndex{Name: name, Order: core.SortAsc}
+	if order == "D" {
+		col.Order = core.SortDesc
+	}
+	if expr := strings.TrimSpace(expression.String); expr != "" {
+		col.Name = ""
+		col.Expression = expr
+	}
+	return col
+}
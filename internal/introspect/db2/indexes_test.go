@@ -0,0 +1,31 @@
+package db2
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestIndexColumn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		order      string
+		expression sql.NullString
+		want       core.ColumnIndex
+	}{
+		{"email", "A", sql.NullString{}, core.ColumnIndex{Name: "email", Order: core.SortAsc}},
+		{"created_at", "D", sql.NullString{}, core.ColumnIndex{Name: "created_at", Order: core.SortDesc}},
+		{"K00", "A", sql.NullString{String: `LOWER("email") `, Valid: true},
+			core.ColumnIndex{Expression: `LOWER("email")`, Order: core.SortAsc}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, indexColumn(tt.name, tt.order, tt.expression))
+		})
+	}
+}
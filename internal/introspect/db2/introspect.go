@@ -1,8 +1,12 @@
+// Package db2 contains introspect implementation for IBM Db2 for Linux, UNIX,
+// and Windows. It reads the tables, views, and materialized query tables of
+// the current schema from the SYSCAT catalog views with a few bulk queries.
 package db2
 
 import (
 	"context"
 	"database/sql"
+	"strings"
 
 	"smf/internal/core"
 	"smf/internal/introspect"
@@ -14,10 +18,42 @@ func init() {
 
 type introspecter struct{}
 
+type introspectCtx struct {
+	db  *sql.DB
+	ctx context.Context
+}
+
 func New() introspect.Introspecter {
 	return &introspecter{}
 }
 
-func (i *introspecter) Introspect(_ context.Context, _ *sql.DB) (*core.Database, error) {
-	return nil, nil
+func (i *introspecter) Introspect(ctx context.Context, db *sql.DB) (*core.Database, error) {
+	d := &core.Database{Dialect: core.DialectDB2}
+	ic := &introspectCtx{
+		db:  db,
+		ctx: ctx,
+	}
+
+	query := `
+        SELECT CURRENT SERVER
+        FROM SYSIBM.SYSDUMMY1
+    `
+	err := db.QueryRowContext(ctx, query).Scan(&d.Name)
+	if err != nil {
+		return nil, err
+	}
+	d.Name = strings.TrimSpace(d.Name)
+
+	err = introspectTables(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = introspectViews(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	introspect.FoldNames(d)
+	return d, nil
 }
@@ -0,0 +1,122 @@
+package db2
+
+import (
+	"database/sql"
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// tableFilter selects the regular tables of the current schema from
+// SYSCAT.TABLES t. Views, aliases, materialized query tables, and created
+// temporary tables have other types.
+const tableFilter = `t.TABSCHEMA = CURRENT SCHEMA
+        AND t.TYPE = 'T'`
+
+// defaultTablespace is the tablespace every database is created with.
+// Tables stored in it are written without a tablespace.
+const defaultTablespace = "USERSPACE1"
+
+func introspectTables(ic *introspectCtx, db *core.Database) error {
+	set, err := queryTables(ic)
+	if err != nil {
+		return fmt.Errorf("query tables: %w", err)
+	}
+	if len(set.Tables) == 0 {
+		return nil
+	}
+
+	if err := queryColumns(ic, set); err != nil {
+		return fmt.Errorf("query columns: %w", err)
+	}
+	if err := queryConstraints(ic, set); err != nil {
+		return fmt.Errorf("query constraints: %w", err)
+	}
+	if err := queryIndexes(ic, set); err != nil {
+		return fmt.Errorf("query indexes: %w", err)
+	}
+	if err := queryOnUpdateTriggers(ic, set); err != nil {
+		return fmt.Errorf("query triggers: %w", err)
+	}
+
+	db.Tables = append(db.Tables, set.Tables...)
+	return nil
+}
+
+// tableRow holds a row of the tables query. The flags are the one-letter
+// codes of SYSCAT.TABLES.
+type tableRow struct {
+	table       *core.Table
+	tablespace  sql.NullString
+	comment     sql.NullString
+	compression string
+	dataCapture string
+	appendMode  string
+	volatile    string
+	tableOrg    string
+}
+
+func queryTables(ic *introspectCtx) (*introspect.TableSet, error) {
+	query := `
+        SELECT t.TABNAME, t.TBSPACE, t.REMARKS, t.COMPRESSION, t.DATACAPTURE, t.APPEND_MODE,
+            t.VOLATILE, t.TABLEORG
+        FROM SYSCAT.TABLES t
+        WHERE ` + tableFilter + `
+        ORDER BY t.TABNAME
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set := introspect.NewTableSet()
+	for rows.Next() {
+		r := tableRow{table: &core.Table{
+			Columns:     make([]*core.Column, 0),
+			Constraints: make([]*core.Constraint, 0),
+			Indexes:     make([]*core.Index, 0),
+		}}
+		err := rows.Scan(&r.table.Name, &r.tablespace, &r.comment, &r.compression, &r.dataCapture,
+			&r.appendMode, &r.volatile, &r.tableOrg)
+		if err != nil {
+			return nil, err
+		}
+		r.build()
+		set.Add(r.table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// build sets the comment, tablespace, and Db2 options of the table. Row
+// organization is the default and is left unset. Column-organized tables
+// are always compressed, so only row compression is written, and
+// DATA CAPTURE CHANGES INCLUDE LONGVAR COLUMNS is written as CHANGES.
+func (r *tableRow) build() {
+	t := r.table
+	t.Comment = r.comment.String
+	if r.tablespace.String != defaultTablespace {
+		t.Options.Tablespace = r.tablespace.String
+	}
+
+	opts := &core.DB2TableOptions{
+		AppendMode: r.appendMode == "Y",
+		Volatile:   r.volatile == "C",
+	}
+	if r.tableOrg == "C" {
+		opts.OrganizeBy = "COLUMN"
+	} else if r.compression == "R" || r.compression == "B" {
+		opts.Compress = "YES"
+	}
+	if r.dataCapture == "Y" || r.dataCapture == "L" {
+		opts.DataCapture = "CHANGES"
+	}
+	if *opts != (core.DB2TableOptions{}) {
+		t.Options.DB2 = opts
+	}
+}
@@ -0,0 +1,44 @@
+package db2
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestTableRowBuild(t *testing.T) {
+	t.Parallel()
+	r := tableRow{
+		table:       &core.Table{Name: "orders"},
+		tablespace:  sql.NullString{String: "USERSPACE1", Valid: true},
+		comment:     sql.NullString{String: "Customer orders", Valid: true},
+		compression: "N",
+		dataCapture: "N",
+		appendMode:  "N",
+		volatile:    " ",
+		tableOrg:    "R",
+	}
+	r.build()
+	assert.Equal(t, &core.Table{Name: "orders", Comment: "Customer orders"}, r.table)
+
+	r = tableRow{
+		table:       &core.Table{Name: "events"},
+		tablespace:  sql.NullString{String: "HISTORY", Valid: true},
+		compression: "R",
+		dataCapture: "L",
+		appendMode:  "Y",
+		volatile:    "C",
+		tableOrg:    "R",
+	}
+	r.build()
+	assert.Equal(t, "HISTORY", r.table.Options.Tablespace)
+	assert.Equal(t, &core.DB2TableOptions{Compress: "YES", DataCapture: "CHANGES", AppendMode: true, Volatile: true},
+		r.table.Options.DB2)
+
+	r = tableRow{table: &core.Table{Name: "facts"}, compression: "B", tableOrg: "C"}
+	r.build()
+	assert.Equal(t, &core.DB2TableOptions{OrganizeBy: "COLUMN"}, r.table.Options.DB2)
+}
@@ -0,0 +1,63 @@
+package db2
+
+import (
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// Db2 has no ON UPDATE column clause, and the generator emulates it with a
+// BEFORE UPDATE trigger named {table}_{column}_on_update. The value such a
+// trigger assigns is read back as the ON UPDATE value of the column; other
+// triggers are not read.
+
+// onUpdateSetRe matches the assignment at the end of an ON UPDATE trigger.
+var onUpdateSetRe = regexp.MustCompile(`(?s)\bSET n\."((?:[^"]|"")+)" = (.+)$`)
+
+// queryOnUpdateTriggers reads the ON UPDATE triggers of the tables from the
+// text of their CREATE TRIGGER statements.
+func queryOnUpdateTriggers(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT tr.TABNAME, tr.TRIGNAME, tr.TEXT
+        FROM SYSCAT.TRIGGERS tr
+        JOIN SYSCAT.TABLES t ON t.TABSCHEMA = tr.TABSCHEMA AND t.TABNAME = tr.TABNAME
+        WHERE ` + tableFilter + `
+            AND LOWER(tr.TRIGNAME) LIKE '%\_on\_update' ESCAPE '\'
+        ORDER BY tr.TABNAME, tr.TRIGNAME
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, name, text string
+		if err := rows.Scan(&tableName, &name, &text); err != nil {
+			return err
+		}
+		table, err := set.Get(tableName)
+		if err != nil {
+			return err
+		}
+		setOnUpdate(table, name, text)
+	}
+	return rows.Err()
+}
+
+// setOnUpdate sets the ON UPDATE value of the column a trigger emulates it
+// for, when the trigger is named and written as the generator writes it. The
+// name is compared ignoring case, as the generator creates it in upper case.
+func setOnUpdate(table *core.Table, name, text string) {
+	m := onUpdateSetRe.FindStringSubmatch(text)
+	if m == nil {
+		return
+	}
+	col := table.FindColumn(strings.ReplaceAll(m[1], `""`, `"`))
+	if col == nil || !strings.EqualFold(name, table.Name+"_"+col.Name+"_on_update") {
+		return
+	}
+	col.OnUpdate = new(strings.TrimSpace(m[2]))
+}
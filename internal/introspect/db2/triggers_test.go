@@ -0,0 +1,25 @@
+package db2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestSetOnUpdate(t *testing.T) {
+	t.Parallel()
+	const text = `CREATE OR REPLACE TRIGGER "ORDERS_UPDATED_AT_ON_UPDATE"
+NO CASCADE BEFORE UPDATE ON "ORDERS"
+REFERENCING OLD AS o NEW AS n
+FOR EACH ROW
+WHEN (n."UPDATED_AT" IS NOT DISTINCT FROM o."UPDATED_AT")
+SET n."UPDATED_AT" = CURRENT TIMESTAMP`
+	table := &core.Table{Name: "ORDERS", Columns: []*core.Column{{Name: "ID"}, {Name: "UPDATED_AT"}}}
+	setOnUpdate(table, "ORDERS_TOUCHED_ON_UPDATE", text)
+	assert.Nil(t, table.Columns[1].OnUpdate)
+
+	setOnUpdate(table, "ORDERS_UPDATED_AT_ON_UPDATE", text)
+	assert.Equal(t, new("CURRENT TIMESTAMP"), table.Columns[1].OnUpdate)
+}
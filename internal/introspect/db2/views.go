@@ -0,0 +1,125 @@
+package db2
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// Db2 keeps the CREATE statement of a view, and of a materialized query
+// table, in SYSCAT.VIEWS.TEXT as it was written. The definition is the
+// query after AS: the parenthesized query of a materialized query table,
+// and the query of a view without a trailing WITH CHECK OPTION, which
+// SYSCAT.VIEWS reports on its own.
+
+// createViewRe matches the head of a CREATE VIEW or CREATE TABLE statement,
+// after leading comments, up to the AS before the query. The optional group
+// is the column list.
+var createViewRe = regexp.MustCompile(`(?is)^(?:\s+|--[^\n]*\n)*CREATE\s+(?:OR\s+REPLACE\s+)?` +
+	`(?:VIEW|(?:SUMMARY\s+)?TABLE)\s+(?:(?:"(?:[^"]|"")+"|\w+)\s*\.\s*)?(?:"(?:[^"]|"")+"|\w+)\s*` +
+	`(?:\(([^)]*)\))?\s*AS\b\s*`)
+
+// checkOptionRe matches the WITH CHECK OPTION that ends a view definition.
+var checkOptionRe = regexp.MustCompile(`(?is)\s+WITH\s+(?:LOCAL\s+|CASCADED\s+)?CHECK\s+OPTION\s*;?\s*$`)
+
+// viewChecks maps SYSCAT.VIEWS.VIEWCHECK to the check option of a view.
+var viewChecks = map[string]core.CheckOption{
+	"L": core.CheckOptionLocal,
+	"C": core.CheckOptionCascaded,
+}
+
+// viewRow holds a row of the views query. The flags are the one-letter
+// codes of SYSCAT.TABLES and SYSCAT.VIEWS.
+type viewRow struct {
+	name      string
+	typ       string
+	viewCheck string
+	text      string
+	refresh   string
+	comment   sql.NullString
+}
+
+func introspectViews(ic *introspectCtx, db *core.Database) error {
+	query := `
+        SELECT v.VIEWNAME, t.TYPE, v.VIEWCHECK, v.TEXT, t.REFRESH, t.REMARKS
+        FROM SYSCAT.VIEWS v
+        JOIN SYSCAT.TABLES t ON t.TABSCHEMA = v.VIEWSCHEMA AND t.TABNAME = v.VIEWNAME
+        WHERE v.VIEWSCHEMA = CURRENT SCHEMA
+            AND t.TYPE IN ('V', 'S')
+        ORDER BY v.VIEWNAME
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return fmt.Errorf("query views: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r viewRow
+		if err := rows.Scan(&r.name, &r.typ, &r.viewCheck, &r.text, &r.refresh, &r.comment); err != nil {
+			return fmt.Errorf("query views: %w", err)
+		}
+		v, err := r.build()
+		if err != nil {
+			return err
+		}
+		db.Views = append(db.Views, v)
+	}
+	return rows.Err()
+}
+
+// build converts the row into a core.View. A materialized query table is a
+// materialized view; REFRESH DEFERRED is the default and is left unset, and
+// REFRESH IMMEDIATE is written as ON COMMIT. Db2 views always run with the
+// privileges of their owner.
+func (r viewRow) build() (*core.View, error) {
+	m := createViewRe.FindStringSubmatchIndex(r.text)
+	if m == nil {
+		return nil, fmt.Errorf("view %s: unexpected definition", r.name)
+	}
+	v := &core.View{
+		Name:         r.name,
+		Materialized: r.typ == "S",
+		CheckOption:  viewChecks[r.viewCheck],
+		Comment:      r.comment.String,
+	}
+	if m[2] >= 0 {
+		v.Columns = viewColumns(r.text[m[2]:m[3]])
+	}
+
+	query := r.text[m[1]:]
+	if v.Materialized {
+		end := introspect.ClosingParen(query)
+		if !strings.HasPrefix(query, "(") || end < 0 {
+			return nil, fmt.Errorf("view %s: unexpected definition", r.name)
+		}
+		query = query[1:end]
+		if r.refresh == "I" {
+			v.Refresh = core.RefreshOnCommit
+		}
+	} else {
+		query = checkOptionRe.ReplaceAllString(query, "")
+	}
+	v.Definition = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	return v, nil
+}
+
+// viewColumns splits the column list of a CREATE statement and unquotes
+// its names. Unquoted names are stored in upper case.
+func viewColumns(list string) []string {
+	var columns []string
+	for name := range strings.SplitSeq(list, ",") {
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+			name = strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+		} else {
+			name = strings.ToUpper(name)
+		}
+		columns = append(columns, name)
+	}
+	return columns
+}
@@ -0,0 +1,77 @@
+package db2
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestViewRowBuild(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		row  viewRow
+		want *core.View
+	}{
+		{
+			name: "plain view",
+			row: viewRow{
+				name:      "ACTIVE_USERS",
+				typ:       "V",
+				viewCheck: "N",
+				text:      `CREATE VIEW "ACTIVE_USERS" AS SELECT ID FROM USERS WHERE ACTIVE = 1`,
+			},
+			want: &core.View{Name: "ACTIVE_USERS", Definition: "SELECT ID FROM USERS WHERE ACTIVE = 1"},
+		},
+		{
+			name: "columns and check option",
+			row: viewRow{
+				name:      "BIG_ORDERS",
+				typ:       "V",
+				viewCheck: "L",
+				text: "CREATE VIEW APP.BIG_ORDERS (id, \"Total\")\nAS SELECT ID, TOTAL FROM ORDERS WHERE TOTAL > 100\n" +
+					"WITH LOCAL CHECK OPTION",
+				comment: sql.NullString{String: "large orders", Valid: true},
+			},
+			want: &core.View{
+				Name:        "BIG_ORDERS",
+				Definition:  "SELECT ID, TOTAL FROM ORDERS WHERE TOTAL > 100",
+				Columns:     []string{"ID", "Total"},
+				CheckOption: core.CheckOptionLocal,
+				Comment:     "large orders",
+			},
+		},
+		{
+			name: "materialized query table",
+			row: viewRow{
+				name:      "ORDER_STATS",
+				typ:       "S",
+				viewCheck: "N",
+				text: `CREATE TABLE "ORDER_STATS" AS (SELECT COUNT(*) AS N FROM ORDERS WHERE NOTE <> ')') ` +
+					"DATA INITIALLY DEFERRED REFRESH IMMEDIATE",
+				refresh: "I",
+			},
+			want: &core.View{
+				Name:         "ORDER_STATS",
+				Definition:   "SELECT COUNT(*) AS N FROM ORDERS WHERE NOTE <> ')'",
+				Materialized: true,
+				Refresh:      core.RefreshOnCommit,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := tt.row.build()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+
+	_, err := viewRow{name: "V", typ: "S", text: "CREATE TABLE V AS SELECT 1"}.build()
+	require.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package introspect
+
+import "strings"
+
+// StripParens removes the parentheses around the whole of expr. Catalogs
+// store CHECK, DEFAULT and generation expressions in their own printed
+// form, which PostgreSQL, SQL Server and Db2 wrap in one or more pairs of
+// parentheses.
+func StripParens(expr string) string {
+	expr = strings.TrimSpace(expr)
+	for strings.HasPrefix(expr, "(") && ClosingParen(expr) == len(expr)-1 {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	return expr
+}
+
+// ClosingParen returns the index of the parenthesis that closes the one at
+// s[0], or -1 when it is not closed. Parentheses in string literals and in
+// double-quoted or bracketed identifiers are skipped.
+func ClosingParen(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,30 @@
+package introspect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripParens(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		" ((price * 2)) ":        "price * 2",
+		"(a) + (b)":              "(a) + (b)",
+		`("a)" || ')')`:          `"a)" || ')'`,
+		"((0))":                  "0",
+		"([a]>(0) AND [b]>(0))":  "[a]>(0) AND [b]>(0)",
+		"([a]>(0)) OR ([b]>(0))": "([a]>(0)) OR ([b]>(0))",
+		"([a)]='(')":             "[a)]='('",
+		"(status IN ('a', 'b'))": "status IN ('a', 'b')",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, StripParens(in), in)
+	}
+}
+
+func TestClosingParen(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 6, ClosingParen("(a (b)) c"))
+	assert.Equal(t, -1, ClosingParen("(a"))
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
+	"strings"
 	"sync"
 
 	"smf/internal/core"
@@ -38,3 +40,76 @@ func NewIntrospecter(dialect core.Dialect) (Introspecter, error) {
 
 	return fn(), nil
 }
+
+// ClearVolatile resets the table options of db whose values change as rows
+// are written rather than with the schema: the next AUTO_INCREMENT value and
+// TiDB's AUTO_RANDOM_BASE. Without them a pulled schema or a drift report
+// does not change with every insert.
+func ClearVolatile(db *core.Database) {
+	for _, t := range db.Tables {
+		if t.Options.MySQL != nil {
+			t.Options.MySQL.AutoIncrement = 0
+		}
+		if t.Options.TiDB != nil {
+			t.Options.TiDB.AutoRandomBase = 0
+		}
+	}
+}
+
+// FoldNames lowercases the names of db that are stored in upper case because
+// the database folds unquoted identifiers to upper case (Oracle, Db2,
+// Snowflake). The generators of these dialects quote lowercase plain names in
+// upper case, so folding them back makes a pulled schema match the declared
+// one. Names in mixed case or with other characters were created quoted and
+// are kept as they are.
+func FoldNames(db *core.Database) {
+	for _, t := range db.Tables {
+		foldTable(t)
+	}
+	for _, v := range db.Views {
+		v.Name = foldName(v.Name)
+		foldAll(v.Columns)
+	}
+}
+
+func foldTable(t *core.Table) {
+	t.Name = foldName(t.Name)
+	for _, c := range t.Columns {
+		c.Name = foldName(c.Name)
+		if c.SequenceName != "" {
+			parts := strings.Split(c.SequenceName, ".")
+			foldAll(parts)
+			c.SequenceName = strings.Join(parts, ".")
+		}
+	}
+	for _, con := range t.Constraints {
+		con.Name = foldName(con.Name)
+		foldAll(con.Columns)
+		con.ReferencedTable = foldName(con.ReferencedTable)
+		foldAll(con.ReferencedColumns)
+	}
+	for _, idx := range t.Indexes {
+		idx.Name = foldName(idx.Name)
+		for i := range idx.Columns {
+			idx.Columns[i].Name = foldName(idx.Columns[i].Name)
+		}
+	}
+	if t.Options.Snowflake != nil {
+		foldAll(t.Options.Snowflake.ClusterBy)
+	}
+}
+
+var plainUpperRe = regexp.MustCompile(`^[A-Z_][A-Z0-9_$#]*$`)
+
+func foldName(name string) string {
+	if plainUpperRe.MatchString(name) {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+func foldAll(names []string) {
+	for i, n := range names {
+		names[i] = foldName(n)
+	}
+}
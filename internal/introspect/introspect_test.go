@@ -0,0 +1,65 @@
+package introspect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestClearVolatile(t *testing.T) {
+	t.Parallel()
+	db := &core.Database{Tables: []*core.Table{
+		{Name: "users", Options: core.TableOptions{
+			MySQL: &core.MySQLTableOptions{Engine: "InnoDB", AutoIncrement: 1042},
+			TiDB:  &core.TiDBTableOptions{AutoIDCache: 100, AutoRandomBase: 77},
+		}},
+		{Name: "tags"},
+	}}
+
+	ClearVolatile(db)
+
+	assert.Equal(t, &core.MySQLTableOptions{Engine: "InnoDB"}, db.Tables[0].Options.MySQL)
+	assert.Equal(t, &core.TiDBTableOptions{AutoIDCache: 100}, db.Tables[0].Options.TiDB)
+	assert.Nil(t, db.Tables[1].Options.MySQL)
+}
+
+func TestFoldNames(t *testing.T) {
+	t.Parallel()
+	db := &core.Database{
+		Tables: []*core.Table{{
+			Name: "ORDERS",
+			Columns: []*core.Column{
+				{Name: "ID", SequenceName: "APP.ORDERS_SEQ"},
+				{Name: "CustomerNote"},
+			},
+			Constraints: []*core.Constraint{{
+				Name: "FK_ORDERS_USER", Type: core.ConstraintForeignKey, Columns: []string{"USER_ID"},
+				ReferencedTable: "USERS", ReferencedColumns: []string{"ID"},
+			}},
+			Indexes: []*core.Index{{Name: "IDX_ORDERS_USER", Columns: []core.ColumnIndex{{Name: "USER_ID"}}}},
+			Options: core.TableOptions{Snowflake: &core.SnowflakeTableOptions{
+				ClusterBy: []string{"REGION", "TO_DATE(CREATED_AT)"},
+			}},
+		}},
+		Views: []*core.View{{Name: "ACTIVE_ORDERS", Columns: []string{"ID", "order total"}}},
+	}
+
+	FoldNames(db)
+
+	tbl := db.Tables[0]
+	assert.Equal(t, "orders", tbl.Name)
+	assert.Equal(t, "id", tbl.Columns[0].Name)
+	assert.Equal(t, "app.orders_seq", tbl.Columns[0].SequenceName)
+	assert.Equal(t, "CustomerNote", tbl.Columns[1].Name)
+	assert.Equal(t, &core.Constraint{
+		Name: "fk_orders_user", Type: core.ConstraintForeignKey, Columns: []string{"user_id"},
+		ReferencedTable: "users", ReferencedColumns: []string{"id"},
+	}, tbl.Constraints[0])
+	assert.Equal(t, "idx_orders_user", tbl.Indexes[0].Name)
+	assert.Equal(t, "user_id", tbl.Indexes[0].Columns[0].Name)
+	assert.Equal(t, []string{"region", "TO_DATE(CREATED_AT)"}, tbl.Options.Snowflake.ClusterBy)
+	assert.Equal(t, "active_orders", db.Views[0].Name)
+	assert.Equal(t, []string{"id", "order total"}, db.Views[0].Columns)
+}
@@ -0,0 +1,280 @@
+package mssql
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+	"smf/internal/introspect"
+)
+
+// portableTypes maps the types printed by formatType to the portable type
+// they are written as, and whether the portable type takes arguments. The
+// date and time types only match with their default precision of 7. Every
+// other type is kept as a raw type.
+var portableTypes = map[string]struct {
+	name     string
+	takesArg bool
+}{
+	"nvarchar":          {"varchar", true},
+	"nchar":             {"char", true},
+	"nvarchar(max)":     {"text", false},
+	"tinyint":           {"tinyint", false},
+	"smallint":          {"smallint", false},
+	"int":               {"int", false},
+	"bigint":            {"bigint", false},
+	"decimal":           {"decimal", true},
+	"numeric":           {"numeric", true},
+	"real":              {"float", false},
+	"float":             {"double", false},
+	"bit":               {"boolean", false},
+	"date":              {"date", false},
+	"time(7)":           {"time", false},
+	"datetimeoffset(7)": {"timestamp", false},
+	"datetime2(7)":      {"datetime", false},
+	"uniqueidentifier":  {"uuid", false},
+	"varbinary(max)":    {"blob", false},
+	"binary":            {"binary", true},
+	"varbinary":         {"varbinary", true},
+}
+
+// stringLiteralRe matches a string literal as SQL Server prints it in a
+// default definition, with or without the N prefix.
+var stringLiteralRe = regexp.MustCompile(`^N?'((?:[^']|'')*)'$`)
+
+// defaultEncryptionAlgorithm is the only algorithm Always Encrypted
+// supports, and the one the generator uses when none is given.
+const defaultEncryptionAlgorithm = "AEAD_AES_256_CBC_HMAC_SHA_256"
+
+// columnRow holds a row of the columns query.
+type columnRow struct {
+	table             string
+	col               *core.Column
+	typeName          string
+	maxLength         int
+	precision         int
+	scale             int
+	collation         string
+	defaultDef        sql.NullString
+	identity          bool
+	seed              int64
+	increment         int64
+	notForReplication bool
+	computedDef       sql.NullString
+	persisted         bool
+	sparse            bool
+	rowGUIDCol        bool
+	fileStream        bool
+	maskingFunction   string
+	encryptionType    string
+	encryptionAlg     string
+	encryptionKey     string
+}
+
+// queryColumns reads the columns of the tables. The period columns of
+// temporal tables and the columns SQL Server adds to ledger tables are
+// generated always and are left out; the table options describe them.
+func queryColumns(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT t.name, c.name,
+            CASE WHEN ty.is_user_defined = 1 AND ty.is_assembly_type = 0 THEN TYPE_NAME(c.system_type_id) ELSE ty.name END,
+            c.max_length, c.precision, c.scale, c.is_nullable,
+            coalesce(c.collation_name, ''),
+            coalesce(CAST(ep.value AS nvarchar(max)), ''),
+            dc.definition,
+            c.is_identity,
+            coalesce(CAST(idc.seed_value AS bigint), 1),
+            coalesce(CAST(idc.increment_value AS bigint), 1),
+            coalesce(idc.is_not_for_replication, 0),
+            cc.definition,
+            coalesce(cc.is_persisted, 0),
+            c.is_sparse, c.is_rowguidcol, c.is_filestream,
+            coalesce(mc.masking_function, ''),
+            coalesce(c.encryption_type_desc, ''),
+            coalesce(c.encryption_algorithm_name, ''),
+            coalesce(cek.name, '')
+        FROM sys.columns c
+        JOIN sys.tables t ON t.object_id = c.object_id
+        JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+        LEFT JOIN sys.default_constraints dc ON dc.parent_object_id = c.object_id AND dc.parent_column_id = c.column_id
+        LEFT JOIN sys.identity_columns idc ON idc.object_id = c.object_id AND idc.column_id = c.column_id
+        LEFT JOIN sys.computed_columns cc ON cc.object_id = c.object_id AND cc.column_id = c.column_id
+        LEFT JOIN sys.masked_columns mc ON mc.object_id = c.object_id AND mc.column_id = c.column_id AND mc.is_masked = 1
+        LEFT JOIN sys.column_encryption_keys cek ON cek.column_encryption_key_id = c.column_encryption_key_id
+        LEFT JOIN sys.extended_properties ep ON ep.class = 1
+            AND ep.major_id = c.object_id
+            AND ep.minor_id = c.column_id
+            AND ep.name = N'MS_Description'
+        WHERE ` + ic.tableFilter() + `
+            AND c.generated_always_type = 0
+        ORDER BY t.name, c.column_id
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := columnRow{col: &core.Column{}}
+		err := rows.Scan(&r.table, &r.col.Name, &r.typeName, &r.maxLength, &r.precision, &r.scale, &r.col.Nullable,
+			&r.collation, &r.col.Comment, &r.defaultDef, &r.identity, &r.seed, &r.increment, &r.notForReplication,
+			&r.computedDef, &r.persisted, &r.sparse, &r.rowGUIDCol, &r.fileStream, &r.maskingFunction,
+			&r.encryptionType, &r.encryptionAlg, &r.encryptionKey)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(r.table)
+		if err != nil {
+			return err
+		}
+		if r.collation != ic.collation {
+			r.col.Collate = r.collation
+		}
+		r.build()
+		table.Columns = append(table.Columns, r.col)
+	}
+	return rows.Err()
+}
+
+// build fills col from the rest of the row.
+func (r *columnRow) build() {
+	col := r.col
+	setColumnType(col, formatType(r.typeName, r.maxLength, r.precision, r.scale))
+
+	switch {
+	case r.computedDef.Valid:
+		col.IsGenerated = true
+		col.GenerationExpression = introspect.StripParens(r.computedDef.String)
+		col.GenerationStorage = core.GenerationVirtual
+		if r.persisted {
+			col.GenerationStorage = core.GenerationStored
+		}
+	case r.identity:
+		col.AutoIncrement = true
+		if r.seed != 1 {
+			col.IdentitySeed = r.seed
+		}
+		if r.increment != 1 {
+			col.IdentityIncrement = r.increment
+		}
+	}
+	if r.defaultDef.Valid {
+		col.DefaultValue = columnDefault(col, r.defaultDef.String)
+	}
+	r.setOptions()
+}
+
+// setOptions sets the SQL Server options of the column. A persisted
+// computed column is written as a stored generated column instead.
+func (r *columnRow) setOptions() {
+	opts := &core.MSSQLColumnOptions{
+		FileStream:                r.fileStream,
+		Sparse:                    r.sparse,
+		RowGUIDCol:                r.rowGUIDCol,
+		IdentityNotForReplication: r.notForReplication,
+	}
+	if r.maskingFunction != "" {
+		opts.DataMasking = &core.MSSQLDataMaskingOptions{Function: r.maskingFunction}
+	}
+	if r.encryptionType != "" {
+		opts.AlwaysEncrypted = &core.MSSQLAlwaysEncryptedOptions{
+			ColumnEncryptionKey: r.encryptionKey,
+			EncryptionType:      r.encryptionType,
+		}
+		if !strings.EqualFold(r.encryptionAlg, defaultEncryptionAlgorithm) {
+			opts.AlwaysEncrypted.Algorithm = r.encryptionAlg
+		}
+	}
+	if opts.FileStream || opts.Sparse || opts.RowGUIDCol || opts.IdentityNotForReplication ||
+		opts.DataMasking != nil || opts.AlwaysEncrypted != nil {
+		r.col.MSSQL = opts
+	}
+}
+
+// formatType returns the type of a column as it is declared, in lower case,
+// from its entry in sys.columns. Lengths of character and binary types are
+// in bytes, so those of the Unicode types are halved; -1 stands for MAX.
+//
+// Example output: "nvarchar(255)", "decimal(10,2)", "datetime2(3)".
+func formatType(name string, maxLength, precision, scale int) string {
+	switch name {
+	case "char", "varchar", "binary", "varbinary":
+		return name + typeLength(maxLength)
+	case "nchar", "nvarchar":
+		if maxLength > 0 {
+			maxLength /= 2
+		}
+		return name + typeLength(maxLength)
+	case "decimal", "numeric":
+		return fmt.Sprintf("%s(%d,%d)", name, precision, scale)
+	case "time", "datetime2", "datetimeoffset":
+		return fmt.Sprintf("%s(%d)", name, scale)
+	default:
+		return name
+	}
+}
+
+func typeLength(n int) string {
+	if n < 0 {
+		return "(max)"
+	}
+	return fmt.Sprintf("(%d)", n)
+}
+
+// setColumnType sets the type of col from the type printed by formatType.
+// Types that the portable type names describe exactly, as the generator
+// declares them, are written as portable types; the rest are written as
+// upper-case raw types.
+func setColumnType(col *core.Column, typ string) {
+	if p, ok := portableTypes[typ]; ok && !p.takesArg {
+		col.PortableType = p.name
+		col.Type = core.NormalizeDataType(col.PortableType)
+		return
+	}
+	base, args, _ := strings.Cut(typ, "(")
+	if p, ok := portableTypes[base]; ok && p.takesArg && args != "" && args != "max)" {
+		col.PortableType = p.name + "(" + args
+		col.Type = core.NormalizeDataType(col.PortableType)
+		return
+	}
+	col.RawType = strings.ToUpper(typ)
+	col.Type = core.NormalizeDataType(typ)
+}
+
+// columnDefault returns the default value for a default definition as
+// stored in sys.default_constraints. A literal loses its parentheses and
+// quotes and is stored as it is written in the schema, unless it would then
+// read as something else: a number is only unquoted for numeric columns,
+// and keywords and expressions keep their quotes. The 1 and 0 of a bit
+// column are written as booleans. Other expressions are kept as stored.
+//
+// Example input: "((0))", "(N'active')", "(getdate())".
+func columnDefault(col *core.Column, def string) *string {
+	expr := introspect.StripParens(def)
+	if strings.EqualFold(expr, "NULL") {
+		return nil
+	}
+	if col.Type == core.DataTypeBoolean && (expr == "1" || expr == "0") {
+		return new(strings.ToUpper(strconv.FormatBool(generate.BoolDefault(expr))))
+	}
+	m := stringLiteralRe.FindStringSubmatch(expr)
+	if m == nil {
+		return &expr
+	}
+
+	v := strings.ReplaceAll(m[1], "''", "'")
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return &v
+	case generate.DefaultNumber:
+		if col.Type == core.DataTypeInt || col.Type == core.DataTypeFloat {
+			return &v
+		}
+	}
+	return new(generate.QuoteString(v))
+}
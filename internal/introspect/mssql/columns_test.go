@@ -0,0 +1,109 @@
+package mssql
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestFormatType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name                        string
+		maxLength, precision, scale int
+		want                        string
+	}{
+		{"nvarchar", 510, 0, 0, "nvarchar(255)"},
+		{"nvarchar", -1, 0, 0, "nvarchar(max)"},
+		{"varchar", 50, 0, 0, "varchar(50)"},
+		{"varbinary", -1, 0, 0, "varbinary(max)"},
+		{"decimal", 9, 10, 2, "decimal(10,2)"},
+		{"datetime2", 8, 27, 7, "datetime2(7)"},
+		{"int", 4, 10, 0, "int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, formatType(tt.name, tt.maxLength, tt.precision, tt.scale))
+		})
+	}
+}
+
+func TestSetColumnType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		typ  string
+		want core.Column
+	}{
+		{"nvarchar(255)", core.Column{Type: core.DataTypeString, PortableType: "varchar(255)"}},
+		{"nvarchar(max)", core.Column{Type: core.DataTypeString, PortableType: "text"}},
+		{"varchar(50)", core.Column{Type: core.DataTypeString, RawType: "VARCHAR(50)"}},
+		{"decimal(10,2)", core.Column{Type: core.DataTypeFloat, PortableType: "decimal(10,2)"}},
+		{"bit", core.Column{Type: core.DataTypeBoolean, PortableType: "boolean"}},
+		{"datetime2(7)", core.Column{Type: core.DataTypeDatetime, PortableType: "datetime"}},
+		{"datetime2(3)", core.Column{Type: core.DataTypeDatetime, RawType: "DATETIME2(3)"}},
+		{"uniqueidentifier", core.Column{Type: core.DataTypeUUID, PortableType: "uuid"}},
+		{"varbinary(max)", core.Column{Type: core.DataTypeBinary, PortableType: "blob"}},
+		{"money", core.Column{Type: core.DataTypeUnknown, RawType: "MONEY"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			t.Parallel()
+			var col core.Column
+			setColumnType(&col, tt.typ)
+			assert.Equal(t, tt.want, col)
+		})
+	}
+}
+
+func TestColumnDefault(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		def  string
+		typ  core.DataType
+		want *string
+	}{
+		{"(NULL)", core.DataTypeString, nil},
+		{"(N'active')", core.DataTypeString, new("active")},
+		{"('it''s')", core.DataTypeString, new("it's")},
+		{"(N'01234')", core.DataTypeString, new("'01234'")},
+		{"(N'5')", core.DataTypeInt, new("5")},
+		{"((-1))", core.DataTypeInt, new("-1")},
+		{"((1))", core.DataTypeBoolean, new("TRUE")},
+		{"((0))", core.DataTypeBoolean, new("FALSE")},
+		{"(getdate())", core.DataTypeDatetime, new("getdate()")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.def, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, columnDefault(&core.Column{Type: tt.typ}, tt.def))
+		})
+	}
+}
+
+func TestColumnRowBuild(t *testing.T) {
+	t.Parallel()
+	r := &columnRow{col: &core.Column{Name: "id"}, typeName: "bigint", identity: true, seed: 100, increment: 1, notForReplication: true}
+	r.build()
+	assert.Equal(t, &core.Column{Name: "id", Type: core.DataTypeInt, PortableType: "bigint", AutoIncrement: true,
+		IdentitySeed: 100, MSSQL: &core.MSSQLColumnOptions{IdentityNotForReplication: true}}, r.col)
+
+	r = &columnRow{col: &core.Column{Name: "total", Nullable: true}, typeName: "decimal", precision: 12, scale: 2,
+		computedDef: sql.NullString{String: "([price]*(2))", Valid: true}, persisted: true}
+	r.build()
+	assert.Equal(t, &core.Column{Name: "total", Type: core.DataTypeFloat, PortableType: "decimal(12,2)", Nullable: true,
+		IsGenerated: true, GenerationExpression: "[price]*(2)", GenerationStorage: core.GenerationStored}, r.col)
+
+	r = &columnRow{col: &core.Column{Name: "ssn", Nullable: true}, typeName: "nvarchar", maxLength: 22, sparse: true,
+		maskingFunction: "partial(0, \"XXX-XX-\", 4)", encryptionType: "DETERMINISTIC",
+		encryptionAlg: "AEAD_AES_256_CBC_HMAC_SHA_256", encryptionKey: "cek_ssn"}
+	r.build()
+	assert.Equal(t, &core.MSSQLColumnOptions{
+		Sparse:          true,
+		DataMasking:     &core.MSSQLDataMaskingOptions{Function: "partial(0, \"XXX-XX-\", 4)"},
+		AlwaysEncrypted: &core.MSSQLAlwaysEncryptedOptions{ColumnEncryptionKey: "cek_ssn", EncryptionType: "DETERMINISTIC"},
+	}, r.col.MSSQL)
+}
@@ -0,0 +1,218 @@
+package mssql
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// referentialActions maps the referential_action_desc of sys.foreign_keys
+// to the action it stands for. NO ACTION is the default and is left unset.
+var referentialActions = map[string]core.ReferentialAction{
+	"NO_ACTION":   core.RefActionNone,
+	"CASCADE":     core.RefActionCascade,
+	"SET_NULL":    core.RefActionSetNull,
+	"SET_DEFAULT": core.RefActionSetDefault,
+}
+
+// constraintTypes maps sys.objects.type to the constraint type it stands
+// for.
+var constraintTypes = map[string]core.ConstraintType{
+	"PK": core.ConstraintPrimaryKey,
+	"UQ": core.ConstraintUnique,
+	"F":  core.ConstraintForeignKey,
+	"C":  core.ConstraintCheck,
+}
+
+// enumTermRe matches a term of the CHECK constraint the generator declares
+// for an enum column, as SQL Server stores it: "[status]=N'paid'".
+var enumTermRe = regexp.MustCompile(`^\[((?:[^\]]|\]\])+)\]=N'((?:[^']|'')*)'`)
+
+// keyColumn is a column of a constraint as returned by the constraints
+// query. Referenced is only set for foreign keys.
+type keyColumn struct {
+	Name       string `json:"name"`
+	Referenced string `json:"referenced"`
+}
+
+// constraintRow holds a row of the constraints query.
+type constraintRow struct {
+	table      string
+	con        *core.Constraint
+	typ        string
+	columns    string
+	onDelete   string
+	onUpdate   string
+	definition string
+	disabled   bool
+}
+
+// queryConstraints reads the primary key, unique, foreign key, and CHECK
+// constraints of the tables.
+func queryConstraints(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT t.name, kc.name, kc.type,
+            coalesce((
+                SELECT c.name
+                FROM sys.index_columns ic
+                JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+                WHERE ic.object_id = kc.parent_object_id
+                    AND ic.index_id = kc.unique_index_id
+                    AND ic.key_ordinal > 0
+                ORDER BY ic.key_ordinal
+                FOR JSON PATH
+            ), '[]'),
+            '', '', '', '', CAST(0 AS bit)
+        FROM sys.key_constraints kc
+        JOIN sys.tables t ON t.object_id = kc.parent_object_id
+        WHERE ` + ic.tableFilter() + `
+        UNION ALL
+        SELECT t.name, fk.name, fk.type,
+            coalesce((
+                SELECT pc.name, rc.name AS referenced
+                FROM sys.foreign_key_columns fkc
+                JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+                JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+                WHERE fkc.constraint_object_id = fk.object_id
+                ORDER BY fkc.constraint_column_id
+                FOR JSON PATH
+            ), '[]'),
+            OBJECT_NAME(fk.referenced_object_id),
+            fk.delete_referential_action_desc,
+            fk.update_referential_action_desc,
+            '', fk.is_disabled
+        FROM sys.foreign_keys fk
+        JOIN sys.tables t ON t.object_id = fk.parent_object_id
+        WHERE ` + ic.tableFilter() + `
+        UNION ALL
+        SELECT t.name, cc.name, cc.type,
+            coalesce((
+                SELECT c.name
+                FROM sys.columns c
+                WHERE c.object_id = cc.parent_object_id
+                    AND c.column_id = cc.parent_column_id
+                FOR JSON PATH
+            ), '[]'),
+            '', '', '', cc.definition, cc.is_disabled
+        FROM sys.check_constraints cc
+        JOIN sys.tables t ON t.object_id = cc.parent_object_id
+        WHERE ` + ic.tableFilter() + `
+        ORDER BY 1, 2
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := constraintRow{con: &core.Constraint{}}
+		err := rows.Scan(&r.table, &r.con.Name, &r.typ, &r.columns, &r.con.ReferencedTable,
+			&r.onDelete, &r.onUpdate, &r.definition, &r.disabled)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(r.table)
+		if err != nil {
+			return err
+		}
+		if err := r.build(); err != nil {
+			return fmt.Errorf("table %s: constraint %s: %w", r.table, r.con.Name, err)
+		}
+		if r.con.Type == core.ConstraintCheck && setEnum(table, r.con) {
+			continue
+		}
+		table.Constraints = append(table.Constraints, r.con)
+		if r.con.Type == core.ConstraintPrimaryKey {
+			markPrimaryKey(table, r.con.Columns)
+		}
+	}
+	return rows.Err()
+}
+
+// build fills con from the rest of the row. A disabled foreign key or CHECK
+// constraint is written as not enforced.
+func (r *constraintRow) build() error {
+	con := r.con
+	con.Type = constraintTypes[strings.TrimSpace(r.typ)]
+	var columns []keyColumn
+	if err := json.Unmarshal([]byte(r.columns), &columns); err != nil {
+		return fmt.Errorf("columns: %w", err)
+	}
+	for _, c := range columns {
+		con.Columns = append(con.Columns, c.Name)
+		if con.Type == core.ConstraintForeignKey {
+			con.ReferencedColumns = append(con.ReferencedColumns, c.Referenced)
+		}
+	}
+
+	switch con.Type {
+	case core.ConstraintForeignKey:
+		con.OnDelete = referentialActions[r.onDelete]
+		con.OnUpdate = referentialActions[r.onUpdate]
+	case core.ConstraintCheck:
+		con.CheckExpression = introspect.StripParens(r.definition)
+	}
+	if r.disabled {
+		con.Enforced = new(false)
+	}
+	return nil
+}
+
+func markPrimaryKey(table *core.Table, columns []string) {
+	for _, name := range columns {
+		if col := table.FindColumn(name); col != nil {
+			col.PrimaryKey = true
+		}
+	}
+}
+
+// setEnum makes a character column an enum when con is the CHECK
+// constraint that the generator declares for an enum column, named
+// chk_{table}_{column}_enum.
+func setEnum(table *core.Table, con *core.Constraint) bool {
+	if len(con.Columns) != 1 || con.Name != "chk_"+table.Name+"_"+con.Columns[0]+"_enum" {
+		return false
+	}
+	col := table.FindColumn(con.Columns[0])
+	if col == nil || col.Type != core.DataTypeString {
+		return false
+	}
+	values, ok := enumValues(col.Name, con.CheckExpression)
+	if !ok {
+		return false
+	}
+	col.Type = core.DataTypeEnum
+	col.EnumValues = values
+	col.PortableType = core.BuildEnumTypeRaw(values)
+	col.RawType = ""
+	return true
+}
+
+// enumValues returns the strings of a check on the given column that SQL
+// Server stores for "column IN (N'a', N'b')".
+//
+// Example input: "[status]=N'new' OR [status]=N'paid'".
+func enumValues(column, check string) ([]string, bool) {
+	var values []string
+	rest := check
+	for {
+		m := enumTermRe.FindStringSubmatch(rest)
+		if m == nil || strings.ReplaceAll(m[1], "]]", "]") != column {
+			return nil, false
+		}
+		values = append(values, strings.ReplaceAll(m[2], "''", "'"))
+		rest = rest[len(m[0]):]
+		if rest == "" {
+			return values, true
+		}
+		var ok bool
+		if rest, ok = strings.CutPrefix(rest, " OR "); !ok {
+			return nil, false
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestConstraintRowBuild(t *testing.T) {
+	t.Parallel()
+	r := constraintRow{
+		con:      &core.Constraint{Name: "fk_orders_users", ReferencedTable: "users"},
+		typ:      "F ",
+		columns:  `[{"name":"user_id","referenced":"id"}]`,
+		onDelete: "CASCADE",
+		onUpdate: "NO_ACTION",
+		disabled: true,
+	}
+	require.NoError(t, r.build())
+	assert.Equal(t, &core.Constraint{
+		Name:              "fk_orders_users",
+		Type:              core.ConstraintForeignKey,
+		Columns:           []string{"user_id"},
+		ReferencedTable:   "users",
+		ReferencedColumns: []string{"id"},
+		OnDelete:          core.RefActionCascade,
+		Enforced:          new(false),
+	}, r.con)
+
+	r = constraintRow{con: &core.Constraint{Name: "chk_orders_qty"}, typ: "C ", columns: `[{"name":"qty"}]`, definition: "([qty]>(0))"}
+	require.NoError(t, r.build())
+	assert.Equal(t, &core.Constraint{Name: "chk_orders_qty", Type: core.ConstraintCheck, Columns: []string{"qty"},
+		CheckExpression: "[qty]>(0)"}, r.con)
+}
+
+func TestSetEnum(t *testing.T) {
+	t.Parallel()
+	newTable := func() *core.Table {
+		return &core.Table{Name: "orders", Columns: []*core.Column{
+			{Name: "status", Type: core.DataTypeString, RawType: "NVARCHAR(4)"},
+		}}
+	}
+	con := &core.Constraint{Name: "chk_orders_status_enum", Type: core.ConstraintCheck, Columns: []string{"status"},
+		CheckExpression: "[status]=N'new' OR [status]=N'paid'"}
+	table := newTable()
+	assert.True(t, setEnum(table, con))
+	assert.Equal(t, &core.Column{Name: "status", Type: core.DataTypeEnum, PortableType: "enum('new','paid')",
+		EnumValues: []string{"new", "paid"}}, table.Columns[0])
+
+	con.Name = "chk_orders_status"
+	assert.False(t, setEnum(newTable(), con))
+}
+
+func TestEnumValues(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		check string
+		want  []string
+	}{
+		{"[status]=N'new'", []string{"new"}},
+		{"[status]=N'new' OR [status]=N'it''s'", []string{"new", "it's"}},
+		{"[status]=N'a OR b' OR [status]=N'c'", []string{"a OR b", "c"}},
+		{"[status]=N'new' OR [kind]=N'paid'", nil},
+		{"[status]=N'new' AND [status]=N'paid'", nil},
+		{"[status]>N'new'", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.check, func(t *testing.T) {
+			t.Parallel()
+			got, ok := enumValues("status", tt.check)
+			assert.Equal(t, tt.want != nil, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
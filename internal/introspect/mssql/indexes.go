@@ -0,0 +1,109 @@
+package mssql
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// indexTypes maps sys.indexes.type to the index type it stands for.
+// Clustered and nonclustered rowstore indexes are both B-trees, and the
+// hash indexes of memory-optimized tables are hash indexes. A clustered
+// columnstore index is written as the COLUMNSTORE data compression of the
+// table; XML and nonclustered columnstore indexes have no counterpart in
+// the schema and are not read.
+var indexTypes = map[int]core.IndexType{
+	1: core.IndexTypeBTree,
+	2: core.IndexTypeBTree,
+	4: core.IndexTypeSpatial,
+	7: core.IndexTypeHash,
+}
+
+// keyPart is a key column of an index as returned by the indexes query.
+type keyPart struct {
+	Name string `json:"name"`
+	Desc bool   `json:"desc"`
+}
+
+// queryIndexes reads the indexes of the tables. The indexes that back a
+// primary key or unique constraint are part of the constraint and are not
+// read. Included columns have no counterpart in the schema.
+func queryIndexes(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT t.name, i.name, i.is_unique, i.type,
+            coalesce(i.filter_definition, ''),
+            coalesce(CAST(ep.value AS nvarchar(max)), ''),
+            coalesce((
+                SELECT c.name, ixc.is_descending_key AS [desc]
+                FROM sys.index_columns ixc
+                JOIN sys.columns c ON c.object_id = ixc.object_id AND c.column_id = ixc.column_id
+                WHERE ixc.object_id = i.object_id
+                    AND ixc.index_id = i.index_id
+                    AND ixc.key_ordinal > 0
+                ORDER BY ixc.key_ordinal
+                FOR JSON PATH
+            ), '[]')
+        FROM sys.indexes i
+        JOIN sys.tables t ON t.object_id = i.object_id
+        LEFT JOIN sys.extended_properties ep ON ep.class = 7
+            AND ep.major_id = i.object_id
+            AND ep.minor_id = i.index_id
+            AND ep.name = N'MS_Description'
+        WHERE ` + ic.tableFilter() + `
+            AND i.is_primary_key = 0
+            AND i.is_unique_constraint = 0
+            AND i.is_hypothetical = 0
+        ORDER BY t.name, i.name
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		idx := &core.Index{Visibility: core.IndexVisible}
+		var tableName, where, parts string
+		var typ int
+		err := rows.Scan(&tableName, &idx.Name, &idx.Unique, &typ, &where, &idx.Comment, &parts)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(tableName)
+		if err != nil {
+			return err
+		}
+		indexType, ok := indexTypes[typ]
+		if !ok {
+			continue
+		}
+		idx.Type = indexType
+		idx.Where = introspect.StripParens(where)
+		if idx.Columns, err = indexColumns(parts); err != nil {
+			return fmt.Errorf("table %s: index %s: %w", tableName, idx.Name, err)
+		}
+		table.Indexes = append(table.Indexes, idx)
+	}
+	return rows.Err()
+}
+
+// indexColumns decodes the key columns of an index.
+//
+// Example input: `[{"name":"email","desc":false}]`.
+func indexColumns(parts string) ([]core.ColumnIndex, error) {
+	var keys []keyPart
+	if err := json.Unmarshal([]byte(parts), &keys); err != nil {
+		return nil, fmt.Errorf("key columns: %w", err)
+	}
+	columns := make([]core.ColumnIndex, 0, len(keys))
+	for _, k := range keys {
+		col := core.ColumnIndex{Name: k.Name, Order: core.SortAsc}
+		if k.Desc {
+			col.Order = core.SortDesc
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
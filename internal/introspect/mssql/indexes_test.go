@@ -0,0 +1,23 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestIndexColumns(t *testing.T) {
+	t.Parallel()
+	got, err := indexColumns(`[{"name":"email"},{"name":"created_at","desc":true}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []core.ColumnIndex{
+		{Name: "email", Order: core.SortAsc},
+		{Name: "created_at", Order: core.SortDesc},
+	}, got)
+
+	_, err = indexColumns("not json")
+	assert.Error(t, err)
+}
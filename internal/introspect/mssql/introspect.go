@@ -1,3 +1,6 @@
+// Package mssql contains introspect implementation for Microsoft SQL Server. It reads the
+// tables and views of the default schema of the connected user from the sys catalog
+// views with a few bulk queries, so the schema to introspect is chosen by the login.
 package mssql
 
 import (
@@ -14,10 +17,48 @@ func init() {
 
 type introspecter struct{}
 
+type introspectCtx struct {
+	db  *sql.DB
+	ctx context.Context
+
+	// collation is the default collation of the database. Columns that use
+	// it are written without a collation.
+	collation string
+	// ledger reports whether the server knows ledger tables (SQL Server
+	// 2022 and Azure SQL).
+	ledger bool
+}
+
 func New() introspect.Introspecter {
 	return &introspecter{}
 }
 
-func (i *introspecter) Introspect(_ context.Context, _ *sql.DB) (*core.Database, error) {
-	return nil, nil
+func (i *introspecter) Introspect(ctx context.Context, db *sql.DB) (*core.Database, error) {
+	d := &core.Database{Dialect: core.DialectMSSQL}
+	ic := &introspectCtx{
+		db:  db,
+		ctx: ctx,
+	}
+
+	query := `
+        SELECT DB_NAME(),
+            CAST(DATABASEPROPERTYEX(DB_NAME(), 'Collation') AS nvarchar(128)),
+            CASE WHEN COL_LENGTH('sys.tables', 'ledger_type') IS NULL THEN 0 ELSE 1 END
+    `
+	err := db.QueryRowContext(ctx, query).Scan(&d.Name, &ic.collation, &ic.ledger)
+	if err != nil {
+		return nil, err
+	}
+
+	err = introspectTables(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = introspectViews(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
 }
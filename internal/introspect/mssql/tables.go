@@ -0,0 +1,161 @@
+package mssql
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// tableFilter selects the user tables of the default schema from sys.tables
+// t. The history tables of temporal and ledger tables are left out: the
+// schema describes the table they belong to, and they are created with it.
+func (ic *introspectCtx) tableFilter() string {
+	filter := `t.schema_id = SCHEMA_ID()
+        AND t.is_ms_shipped = 0
+        AND t.temporal_type <> 1`
+	if ic.ledger {
+		filter += `
+        AND t.ledger_type <> 1
+        AND t.is_dropped_ledger_table = 0`
+	}
+	return filter
+}
+
+// ledgerType returns the expression that selects sys.tables.ledger_type,
+// which servers without ledger tables do not have.
+func (ic *introspectCtx) ledgerType() string {
+	if ic.ledger {
+		return "t.ledger_type"
+	}
+	return "0"
+}
+
+// compressions maps sys.partitions.data_compression_desc to the data
+// compression it is written as. Archival columnstore compression is
+// written as columnstore, and no compression is left unset.
+var compressions = map[string]string{
+	"ROW":                 "ROW",
+	"PAGE":                "PAGE",
+	"COLUMNSTORE":         "COLUMNSTORE",
+	"COLUMNSTORE_ARCHIVE": "COLUMNSTORE",
+}
+
+func introspectTables(ic *introspectCtx, db *core.Database) error {
+	set, err := queryTables(ic)
+	if err != nil {
+		return fmt.Errorf("query tables: %w", err)
+	}
+	if len(set.Tables) == 0 {
+		return nil
+	}
+
+	if err := queryColumns(ic, set); err != nil {
+		return fmt.Errorf("query columns: %w", err)
+	}
+	if err := queryConstraints(ic, set); err != nil {
+		return fmt.Errorf("query constraints: %w", err)
+	}
+	if err := queryIndexes(ic, set); err != nil {
+		return fmt.Errorf("query indexes: %w", err)
+	}
+	if err := queryOnUpdateTriggers(ic, set); err != nil {
+		return fmt.Errorf("query triggers: %w", err)
+	}
+
+	db.Tables = append(db.Tables, set.Tables...)
+	return nil
+}
+
+// tableRow holds a row of the tables query.
+type tableRow struct {
+	table           *core.Table
+	memoryOptimized bool
+	temporalType    int
+	ledgerType      int
+	fileGroup       string
+	textImageOn     string
+	compression     string
+}
+
+func queryTables(ic *introspectCtx) (*introspect.TableSet, error) {
+	query := `
+        SELECT t.name, t.is_memory_optimized, t.temporal_type, ` + ic.ledgerType() + `,
+            coalesce((
+                SELECT ds.name
+                FROM sys.indexes i
+                JOIN sys.data_spaces ds ON ds.data_space_id = i.data_space_id
+                WHERE i.object_id = t.object_id
+                    AND i.index_id IN (0, 1)
+                    AND ds.type = 'FG'
+                    AND ds.is_default = 0
+            ), ''),
+            coalesce((
+                SELECT ds.name
+                FROM sys.data_spaces ds
+                WHERE ds.data_space_id = t.lob_data_space_id
+                    AND ds.is_default = 0
+            ), ''),
+            coalesce((
+                SELECT TOP 1 p.data_compression_desc
+                FROM sys.partitions p
+                WHERE p.object_id = t.object_id
+                    AND p.index_id IN (0, 1)
+                ORDER BY p.partition_number
+            ), ''),
+            coalesce(CAST(ep.value AS nvarchar(max)), '')
+        FROM sys.tables t
+        LEFT JOIN sys.extended_properties ep ON ep.class = 1
+            AND ep.major_id = t.object_id
+            AND ep.minor_id = 0
+            AND ep.name = N'MS_Description'
+        WHERE ` + ic.tableFilter() + `
+        ORDER BY t.name
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set := introspect.NewTableSet()
+	for rows.Next() {
+		r := tableRow{table: &core.Table{
+			Columns:     make([]*core.Column, 0),
+			Constraints: make([]*core.Constraint, 0),
+			Indexes:     make([]*core.Index, 0),
+		}}
+		err := rows.Scan(&r.table.Name, &r.memoryOptimized, &r.temporalType, &r.ledgerType,
+			&r.fileGroup, &r.textImageOn, &r.compression, &r.table.Comment)
+		if err != nil {
+			return nil, err
+		}
+		r.build()
+		set.Add(r.table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// build sets the SQL Server options of the table. The filegroup of a
+// memory-optimized table is the one that holds its checkpoint files, and is
+// not an option of the table. Updatable and append-only ledger tables are
+// both written as ledger tables.
+func (r *tableRow) build() {
+	opts := &core.SQLServerTableOptions{
+		DataCompression:  compressions[r.compression],
+		MemoryOptimized:  r.memoryOptimized,
+		SystemVersioning: r.temporalType == 2,
+		TextImageOn:      r.textImageOn,
+		LedgerTable:      r.ledgerType == 2 || r.ledgerType == 3,
+	}
+	if !r.memoryOptimized {
+		opts.FileGroup = r.fileGroup
+	}
+	if *opts != (core.SQLServerTableOptions{}) {
+		r.table.Options.SQLServer = opts
+	}
+}
@@ -0,0 +1,45 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestTableRowBuild(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		row  tableRow
+		want *core.SQLServerTableOptions
+	}{
+		{"plain", tableRow{compression: "NONE"}, nil},
+		{"compressed", tableRow{compression: "PAGE", fileGroup: "data", textImageOn: "lobs"},
+			&core.SQLServerTableOptions{DataCompression: "PAGE", FileGroup: "data", TextImageOn: "lobs"}},
+		{"archive", tableRow{compression: "COLUMNSTORE_ARCHIVE"}, &core.SQLServerTableOptions{DataCompression: "COLUMNSTORE"}},
+		{"memory optimized", tableRow{memoryOptimized: true, fileGroup: "mem"}, &core.SQLServerTableOptions{MemoryOptimized: true}},
+		{"temporal", tableRow{temporalType: 2}, &core.SQLServerTableOptions{SystemVersioning: true}},
+		{"append-only ledger", tableRow{ledgerType: 3}, &core.SQLServerTableOptions{LedgerTable: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.row.table = &core.Table{Name: "t"}
+			tt.row.build()
+			assert.Equal(t, tt.want, tt.row.table.Options.SQLServer)
+		})
+	}
+}
+
+func TestTableFilter(t *testing.T) {
+	t.Parallel()
+	ic := &introspectCtx{}
+	assert.NotContains(t, ic.tableFilter(), "ledger_type")
+	assert.Equal(t, "0", ic.ledgerType())
+
+	ic.ledger = true
+	assert.Contains(t, ic.tableFilter(), "t.ledger_type <> 1")
+	assert.Equal(t, "t.ledger_type", ic.ledgerType())
+}
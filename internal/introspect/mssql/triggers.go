@@ -0,0 +1,60 @@
+package mssql
+
+import (
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// SQL Server has no ON UPDATE column clause, and the generator emulates it
+// with an AFTER UPDATE trigger named {table}_{column}_on_update. The value
+// such a trigger assigns is read back as the ON UPDATE value of the column;
+// other triggers are not read.
+
+// onUpdateSetRe matches the assignment in the body of an ON UPDATE trigger.
+var onUpdateSetRe = regexp.MustCompile(`(?s)UPDATE t SET \[((?:[^\]]|\]\])+)\] = (.+?) FROM `)
+
+func queryOnUpdateTriggers(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT t.name, tr.name, coalesce(OBJECT_DEFINITION(tr.object_id), '')
+        FROM sys.triggers tr
+        JOIN sys.tables t ON t.object_id = tr.parent_id
+        WHERE ` + ic.tableFilter() + `
+            AND tr.name LIKE N'%[_]on[_]update'
+        ORDER BY t.name, tr.name
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, name, definition string
+		if err := rows.Scan(&tableName, &name, &definition); err != nil {
+			return err
+		}
+		table, err := set.Get(tableName)
+		if err != nil {
+			return err
+		}
+		setOnUpdate(table, name, definition)
+	}
+	return rows.Err()
+}
+
+// setOnUpdate sets the ON UPDATE value of the column a trigger emulates it
+// for, when the trigger is named and written as the generator writes it.
+func setOnUpdate(table *core.Table, name, definition string) {
+	m := onUpdateSetRe.FindStringSubmatch(definition)
+	if m == nil {
+		return
+	}
+	col := table.FindColumn(strings.ReplaceAll(m[1], "]]", "]"))
+	if col == nil || name != table.Name+"_"+col.Name+"_on_update" {
+		return
+	}
+	col.OnUpdate = new(strings.TrimSpace(m[2]))
+}
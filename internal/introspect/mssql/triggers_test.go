@@ -0,0 +1,25 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestSetOnUpdate(t *testing.T) {
+	t.Parallel()
+	const definition = `CREATE TRIGGER [orders_updated_at_on_update] ON [orders] AFTER UPDATE AS
+BEGIN
+  SET NOCOUNT ON;
+  IF NOT UPDATE([updated_at])
+    UPDATE t SET [updated_at] = CURRENT_TIMESTAMP FROM [orders] AS t INNER JOIN inserted AS i ON t.[id] = i.[id];
+END`
+	table := &core.Table{Name: "orders", Columns: []*core.Column{{Name: "id"}, {Name: "updated_at"}}}
+	setOnUpdate(table, "orders_id_on_update", definition)
+	assert.Nil(t, table.Columns[1].OnUpdate)
+
+	setOnUpdate(table, "orders_updated_at_on_update", definition)
+	assert.Equal(t, new("CURRENT_TIMESTAMP"), table.Columns[1].OnUpdate)
+}
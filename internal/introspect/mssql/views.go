@@ -0,0 +1,103 @@
+package mssql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// SQL Server keeps the CREATE VIEW statement of a view as it was written.
+// The definition is the query after AS, without a trailing WITH CHECK
+// OPTION, which sys.views reports on its own.
+
+// createViewRe matches the head of a CREATE VIEW statement, after leading
+// comments, up to the AS before the query. The optional group is the column
+// list.
+var createViewRe = regexp.MustCompile(`(?is)^(?:\s+|--[^\n]*\n|/\*.*?\*/)*CREATE\s+(?:OR\s+ALTER\s+)?VIEW\s+` +
+	`(?:(?:\[(?:[^\]]|\]\])+\]|"[^"]*"|[\w@#$]+)\.)?(?:\[(?:[^\]]|\]\])+\]|"[^"]*"|[\w@#$]+)\s*` +
+	`(?:\(([^)]*)\))?\s*(?:WITH\s+\w+(?:\s*,\s*\w+)*\s+)?AS\b`)
+
+// checkOptionRe matches the WITH CHECK OPTION that ends a view definition.
+var checkOptionRe = regexp.MustCompile(`(?is)\s+WITH\s+CHECK\s+OPTION\s*;?\s*$`)
+
+// viewRow holds a row of the views query.
+type viewRow struct {
+	name        string
+	statement   string
+	checkOption bool
+	comment     string
+}
+
+func introspectViews(ic *introspectCtx, db *core.Database) error {
+	query := `
+        SELECT v.name, coalesce(m.definition, ''), v.with_check_option,
+            coalesce(CAST(ep.value AS nvarchar(max)), '')
+        FROM sys.views v
+        LEFT JOIN sys.sql_modules m ON m.object_id = v.object_id
+        LEFT JOIN sys.extended_properties ep ON ep.class = 1
+            AND ep.major_id = v.object_id
+            AND ep.minor_id = 0
+            AND ep.name = N'MS_Description'
+        WHERE v.schema_id = SCHEMA_ID()
+            AND v.is_ms_shipped = 0
+        ORDER BY v.name
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return fmt.Errorf("query views: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r viewRow
+		if err := rows.Scan(&r.name, &r.statement, &r.checkOption, &r.comment); err != nil {
+			return fmt.Errorf("query views: %w", err)
+		}
+		v, err := r.build()
+		if err != nil {
+			return err
+		}
+		db.Views = append(db.Views, v)
+	}
+	return rows.Err()
+}
+
+// build converts the row into a core.View. SQL Server has a single check
+// option, which is written as CASCADED.
+func (r viewRow) build() (*core.View, error) {
+	m := createViewRe.FindStringSubmatchIndex(r.statement)
+	if m == nil {
+		return nil, fmt.Errorf("view %s: unexpected definition", r.name)
+	}
+	definition := checkOptionRe.ReplaceAllString(r.statement[m[1]:], "")
+	v := &core.View{
+		Name:       r.name,
+		Definition: strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(definition), ";")),
+		Comment:    r.comment,
+	}
+	if m[2] >= 0 {
+		v.Columns = viewColumns(r.statement[m[2]:m[3]])
+	}
+	if r.checkOption {
+		v.CheckOption = core.CheckOptionCascaded
+	}
+	return v, nil
+}
+
+// viewColumns splits the column list of a CREATE VIEW statement and
+// unquotes its names.
+func viewColumns(list string) []string {
+	var columns []string
+	for name := range strings.SplitSeq(list, ",") {
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]") {
+			name = strings.ReplaceAll(name[1:len(name)-1], "]]", "]")
+		} else {
+			name = strings.Trim(name, `"`)
+		}
+		columns = append(columns, name)
+	}
+	return columns
+}
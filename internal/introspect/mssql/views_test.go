@@ -0,0 +1,64 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestViewRowBuild(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		row  viewRow
+		want *core.View
+	}{
+		{
+			name: "plain view",
+			row: viewRow{
+				name:      "active_users",
+				statement: "CREATE VIEW [active_users] AS SELECT id FROM users WHERE active = 1",
+			},
+			want: &core.View{Name: "active_users", Definition: "SELECT id FROM users WHERE active = 1"},
+		},
+		{
+			name: "columns, schema, and check option",
+			row: viewRow{
+				name: "big_orders",
+				statement: "-- orders over 100\nCREATE VIEW dbo.[big_orders] ([id], [total]]x])\nAS\n" +
+					"SELECT id, total FROM orders WHERE total > 100\nWITH CHECK OPTION;",
+				checkOption: true,
+				comment:     "large orders",
+			},
+			want: &core.View{
+				Name:        "big_orders",
+				Definition:  "SELECT id, total FROM orders WHERE total > 100",
+				Columns:     []string{"id", "total]x"},
+				CheckOption: core.CheckOptionCascaded,
+				Comment:     "large orders",
+			},
+		},
+		{
+			name: "view attributes",
+			row: viewRow{
+				name:      "order_ids",
+				statement: "create view order_ids with schemabinding, view_metadata as select id from dbo.orders",
+			},
+			want: &core.View{Name: "order_ids", Definition: "select id from dbo.orders"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := tt.row.build()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+
+	_, err := viewRow{name: "v", statement: "SELECT 1"}.build()
+	require.Error(t, err)
+}
@@ -2,15 +2,393 @@ package mysql
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	"smf/internal/core"
+	"smf/internal/generate"
 )
 
+// integerTypes carry a display width in MariaDB and before MySQL 8.0.19.
+// The width has no effect unless the column is ZEROFILL, so it is dropped.
+var integerTypes = []string{"tinyint", "smallint", "mediumint", "int", "bigint"}
+
+// spatialTypes would otherwise be categorized by the type names they
+// contain, such as "int" in "point".
+var spatialTypes = []string{
+	"geometry", "point", "linestring", "polygon",
+	"multipoint", "multilinestring", "multipolygon", "geometrycollection",
+}
+
+// portableTypes lists the MySQL types that are written as a portable type,
+// and whether the portable type takes arguments. Every other type is kept as
+// a raw type.
+var portableTypes = map[string]bool{
+	"varchar": true, "char": true, "decimal": true, "binary": true, "varbinary": true,
+	"text": false, "smallint": false, "int": false, "bigint": false, "float": false, "double": false,
+	"date": false, "time": false, "timestamp": false, "datetime": false, "json": false, "blob": false,
+}
+
+var (
+	timestampDefaultRe = regexp.MustCompile(`(?i)^(current_timestamp|localtimestamp|localtime|now)(\((\d*)\))?$`)
+	jsonValidCheckRe   = regexp.MustCompile("(?i)^json_valid\\(`?([^`)]+)`?\\)$")
+)
+
+// columnAttributes maps the keyword that starts a column attribute to its
+// parser.
+var columnAttributes = map[string]func(*columnParser) error{
+	"NOT":                        (*columnParser).parseNotNull,
+	"NULL":                       func(p *columnParser) error { p.col.Nullable = true; return nil },
+	"DEFAULT":                    (*columnParser).parseDefault,
+	"ON":                         (*columnParser).parseOnUpdate,
+	"AUTO_INCREMENT":             func(p *columnParser) error { p.col.AutoIncrement = true; return nil },
+	"COMMENT":                    func(p *columnParser) error { return p.stringInto(&p.col.Comment) },
+	"CHARACTER":                  (*columnParser).parseCharacterSet,
+	"CHARSET":                    func(p *columnParser) error { return p.wordInto(&p.col.Charset) },
+	"COLLATE":                    func(p *columnParser) error { return p.wordInto(&p.col.Collate) },
+	"GENERATED":                  (*columnParser).parseGeneratedAlways,
+	"AS":                         (*columnParser).parseGenerated,
+	"VIRTUAL":                    func(p *columnParser) error { p.col.GenerationStorage = core.GenerationVirtual; return nil },
+	"STORED":                     func(p *columnParser) error { p.col.GenerationStorage = core.GenerationStored; return nil },
+	"PERSISTENT":                 func(p *columnParser) error { p.col.GenerationStorage = core.GenerationStored; return nil },
+	"INVISIBLE":                  func(p *columnParser) error { p.col.Invisible = true; return nil },
+	"VISIBLE":                    func(p *columnParser) error { p.col.Invisible = false; return nil },
+	"SRID":                       (*columnParser).parseSRID,
+	"AUTO_RANDOM":                (*columnParser).parseAutoRandom,
+	"COLUMN_FORMAT":              func(p *columnParser) error { return p.wordInto(&p.mysqlOptions().ColumnFormat) },
+	"STORAGE":                    func(p *columnParser) error { return p.wordInto(&p.mysqlOptions().Storage) },
+	"ENGINE_ATTRIBUTE":           func(p *columnParser) error { return p.stringInto(&p.mysqlOptions().PrimaryEngineAttribute) },
+	"SECONDARY_ENGINE_ATTRIBUTE": func(p *columnParser) error { return p.stringInto(&p.mysqlOptions().SecondaryEngineAttribute) },
+	"CHECK":                      (*columnParser).parseCheck,
+}
+
+// columnParser parses the tokens of a column definition into col.
+type columnParser struct {
+	tokenReader
+	col *core.Column
+}
+
 // parseColumn parses a single column definition from a CREATE TABLE body item.
 //
 // Example input: "`id` bigint unsigned NOT NULL AUTO_INCREMENT".
 func parseColumn(_ core.Dialect, item string) (*core.Column, error) {
-	// TODO: implement full column parsing (name, type, nullability, default, etc.)
-	_ = item
-	return nil, fmt.Errorf("parseColumn not yet implemented")
+	tokens, err := tokenize(item)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) < 2 || tokens[0].kind != tokenIdent && tokens[0].kind != tokenWord || tokens[1].kind != tokenWord {
+		return nil, fmt.Errorf("invalid column definition %q", item)
+	}
+
+	p := &columnParser{tokenReader: tokenReader{tokens: tokens, pos: 1}, col: &core.Column{Name: tokens[0].text, Nullable: true}}
+	if err := p.parseType(); err != nil {
+		return nil, fmt.Errorf("column %s: %w", p.col.Name, err)
+	}
+	for !p.done() {
+		t := p.next()
+		parse, ok := columnAttributes[t.keyword()]
+		if !ok {
+			return nil, fmt.Errorf("column %s: unexpected %q", p.col.Name, t.raw)
+		}
+		if err := parse(p); err != nil {
+			return nil, fmt.Errorf("column %s: %s: %w", p.col.Name, t.keyword(), err)
+		}
+	}
+	p.finish()
+	return p.col, nil
+}
+
+func (p *columnParser) mysqlOptions() *core.MySQLColumnOptions {
+	if p.col.MySQL == nil {
+		p.col.MySQL = &core.MySQLColumnOptions{}
+	}
+	return p.col.MySQL
+}
+
+// parseType parses the column type with its arguments and the UNSIGNED and
+// ZEROFILL modifiers.
+func (p *columnParser) parseType() error {
+	base := strings.ToLower(p.next().text)
+	var args string
+	if p.peek().kind == tokenGroup {
+		args = p.next().text
+	}
+	var modifiers []string
+	for k := p.peek().keyword(); k == "UNSIGNED" || k == "ZEROFILL"; k = p.peek().keyword() {
+		modifiers = append(modifiers, strings.ToLower(p.next().text))
+	}
+
+	if base == "enum" || base == "set" {
+		return p.parseEnumType(base, args)
+	}
+	setColumnType(p.col, base, args, modifiers)
+	return nil
+}
+
+// parseEnumType parses the values of an ENUM or SET column. An ENUM is
+// written as a portable enum, a SET keeps its raw type.
+func (p *columnParser) parseEnumType(base, args string) error {
+	if args == "" {
+		return fmt.Errorf("%s without values", base)
+	}
+	tokens, err := groupTokens(args)
+	if err != nil {
+		return err
+	}
+	var values []string
+	for _, t := range tokens {
+		if t.kind == tokenString {
+			values = append(values, t.text)
+		}
+	}
+
+	p.col.Type = core.DataTypeEnum
+	p.col.EnumValues = values
+	if base == "enum" {
+		p.col.PortableType = core.BuildEnumTypeRaw(values)
+	} else {
+		p.col.RawType = base + args
+	}
+	return nil
+}
+
+// setColumnType sets the type of col. tinyint(1) is a boolean, and types
+// that the portable type names describe exactly are written as portable
+// types.
+func setColumnType(col *core.Column, base, args string, modifiers []string) {
+	if base == "tinyint" && args == "(1)" && len(modifiers) == 0 {
+		col.Type = core.DataTypeBoolean
+		col.PortableType = "boolean"
+		return
+	}
+	if slices.Contains(integerTypes, base) && !slices.Contains(modifiers, "zerofill") {
+		args = ""
+	}
+
+	typ := strings.Join(append([]string{base + args}, modifiers...), " ")
+	col.Type = core.NormalizeDataType(typ)
+	if slices.Contains(spatialTypes, base) {
+		col.Type = core.DataTypeUnknown
+	}
+	if takesArgs, ok := portableTypes[base]; ok && takesArgs == (args != "") && len(modifiers) == 0 {
+		col.PortableType = typ
+	} else {
+		col.RawType = typ
+	}
+}
+
+func (p *columnParser) parseNotNull() error {
+	if err := p.expect("NULL"); err != nil {
+		return err
+	}
+	p.col.Nullable = false
+	return nil
+}
+
+func (p *columnParser) parseDefault() error {
+	t := p.peek()
+	switch {
+	case t.kind == tokenString:
+		p.next()
+		p.col.DefaultValue = new(stringDefault(p.col, t))
+	case t.keyword() == "NULL":
+		p.next()
+		p.col.DefaultValue = nil
+	default:
+		v, err := p.expression()
+		if err != nil {
+			return err
+		}
+		p.col.DefaultValue = &v
+	}
+	return nil
+}
+
+func (p *columnParser) parseOnUpdate() error {
+	if err := p.expect("UPDATE"); err != nil {
+		return err
+	}
+	v, err := p.expression()
+	if err != nil {
+		return err
+	}
+	p.col.OnUpdate = &v
+	return nil
+}
+
+// expression reads an unquoted default or ON UPDATE value: a number, a
+// keyword, a function call, or a parenthesized expression. The synonyms of
+// CURRENT_TIMESTAMP, which MariaDB prints as current_timestamp(), are
+// written as CURRENT_TIMESTAMP.
+func (p *columnParser) expression() (string, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenGroup:
+		return t.text, nil
+	case tokenWord:
+		v := t.text
+		if p.peek().kind == tokenGroup {
+			v += p.next().text
+		}
+		return normalizeTimestamp(v), nil
+	default:
+		return "", fmt.Errorf("unexpected %q", t.raw)
+	}
+}
+
+func normalizeTimestamp(v string) string {
+	m := timestampDefaultRe.FindStringSubmatch(v)
+	switch {
+	case m == nil:
+		return v
+	case m[3] != "":
+		return "CURRENT_TIMESTAMP(" + m[3] + ")"
+	default:
+		return "CURRENT_TIMESTAMP"
+	}
+}
+
+// stringDefault returns the default value for a string literal. It is
+// stored unquoted, as it is written in the schema, unless it would then
+// read as something else: a number is only unquoted for numeric columns,
+// and keywords and expressions keep their quotes. Bit and hex literals are
+// kept as printed.
+func stringDefault(col *core.Column, t token) string {
+	switch strings.ToLower(t.raw[:1]) {
+	case "b", "x":
+		return t.raw
+	}
+	switch generate.ClassifyDefault(t.text) {
+	case generate.DefaultString:
+		return t.text
+	case generate.DefaultNumber:
+		if col.Type == core.DataTypeBoolean {
+			return booleanDefault(t.text)
+		}
+		if col.Type == core.DataTypeInt || col.Type == core.DataTypeFloat {
+			return t.text
+		}
+	}
+	return "'" + strings.ReplaceAll(t.text, "'", "''") + "'"
+}
+
+// booleanDefault writes the 0 and 1 that MySQL stores for boolean defaults
+// as FALSE and TRUE, as the schema parser does for boolean values.
+func booleanDefault(v string) string {
+	switch v {
+	case "0":
+		return "FALSE"
+	case "1":
+		return "TRUE"
+	default:
+		return v
+	}
+}
+
+func (p *columnParser) parseCharacterSet() error {
+	if err := p.expect("SET"); err != nil {
+		return err
+	}
+	return p.wordInto(&p.col.Charset)
+}
+
+func (p *columnParser) parseGeneratedAlways() error {
+	if err := p.expect("ALWAYS"); err != nil {
+		return err
+	}
+	if err := p.expect("AS"); err != nil {
+		return err
+	}
+	return p.parseGenerated()
+}
+
+// parseGenerated parses the expression of a generated column. MySQL wraps
+// the stored expression in an extra pair of parentheses and MariaDB does
+// not; both are written with a single pair and without identifier quotes.
+func (p *columnParser) parseGenerated() error {
+	t := p.next()
+	if t.kind != tokenGroup {
+		return fmt.Errorf("expected a parenthesized expression, got %q", t.raw)
+	}
+	p.col.IsGenerated = true
+	p.col.GenerationExpression = "(" + groupExpression(t.text) + ")"
+	if p.col.GenerationStorage == "" {
+		p.col.GenerationStorage = core.GenerationVirtual
+	}
+	return nil
+}
+
+// parseSRID reads the spatial reference system of a spatial column.
+func (p *columnParser) parseSRID() error {
+	var srid string
+	if err := p.wordInto(&srid); err != nil {
+		return err
+	}
+	n, err := strconv.ParseUint(srid, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid SRID %q", srid)
+	}
+	p.mysqlOptions().SRID = new(uint32(n))
+	return nil
+}
+
+// parseAutoRandom parses TiDB's AUTO_RANDOM(shard_bits[, range_bits]).
+func (p *columnParser) parseAutoRandom() error {
+	opts := &core.TiDBColumnOptions{ShardBits: 5}
+	p.col.TiDB = opts
+	if p.peek().kind != tokenGroup {
+		return nil
+	}
+	tokens, err := groupTokens(p.next().text)
+	if err != nil {
+		return err
+	}
+	var bits []uint64
+	for _, t := range tokens {
+		if t.kind != tokenWord {
+			continue
+		}
+		n, err := strconv.ParseUint(t.text, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid bits %q", t.text)
+		}
+		bits = append(bits, n)
+	}
+	if len(bits) > 0 {
+		opts.ShardBits = bits[0]
+	}
+	if len(bits) > 1 {
+		opts.RangeBits = &bits[1]
+	}
+	return nil
+}
+
+// parseCheck parses the inline CHECK that MariaDB prints on the column.
+func (p *columnParser) parseCheck() error {
+	t := p.next()
+	if t.kind != tokenGroup {
+		return fmt.Errorf("expected a parenthesized expression, got %q", t.raw)
+	}
+	p.col.Check = groupExpression(t.text)
+	return nil
+}
+
+// finish derives what SHOW CREATE TABLE leaves implicit. The character set
+// of a column is omitted when only its collation differs from the table, and
+// MariaDB stores JSON as LONGTEXT with a json_valid check.
+func (p *columnParser) finish() {
+	col := p.col
+	if col.Charset == "" && col.Collate != "" {
+		col.Charset, _, _ = strings.Cut(col.Collate, "_")
+	}
+	if m := jsonValidCheckRe.FindStringSubmatch(col.Check); m != nil && m[1] == col.Name && col.RawType == "longtext" {
+		col.Type = core.DataTypeJSON
+		col.PortableType = "json"
+		col.RawType = ""
+		col.Charset = ""
+		col.Collate = ""
+		col.Check = ""
+	}
 }
@@ -0,0 +1,177 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	genmysql "smf/internal/generate/mysql"
+)
+
+func TestParseColumn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		item string
+		want *core.Column
+	}{
+		{
+			name: "unsigned auto increment",
+			item: "`id` bigint unsigned NOT NULL AUTO_INCREMENT",
+			want: &core.Column{Name: "id", Type: core.DataTypeInt, RawType: "bigint unsigned", AutoIncrement: true},
+		},
+		{
+			name: "display width is dropped",
+			item: "`id` int(11) NOT NULL",
+			want: &core.Column{Name: "id", Type: core.DataTypeInt, PortableType: "int"},
+		},
+		{
+			name: "zerofill keeps display width",
+			item: "`code` int(5) unsigned zerofill DEFAULT NULL",
+			want: &core.Column{Name: "code", Type: core.DataTypeInt, RawType: "int(5) unsigned zerofill", Nullable: true},
+		},
+		{
+			name: "boolean",
+			item: "`is_active` tinyint(1) DEFAULT '1'",
+			want: &core.Column{Name: "is_active", Type: core.DataTypeBoolean, PortableType: "boolean", Nullable: true, DefaultValue: new("TRUE")},
+		},
+		{
+			name: "numeric default",
+			item: "`priority` int DEFAULT '0'",
+			want: &core.Column{Name: "priority", Type: core.DataTypeInt, PortableType: "int", Nullable: true, DefaultValue: new("0")},
+		},
+		{
+			name: "string default that reads as a number",
+			item: "`zip` varchar(5) NOT NULL DEFAULT '01234'",
+			want: &core.Column{Name: "zip", Type: core.DataTypeString, PortableType: "varchar(5)", DefaultValue: new("'01234'")},
+		},
+		{
+			name: "string default and comment with escapes",
+			item: "`plan` varchar(16) NOT NULL DEFAULT 'it''s free' COMMENT 'line\\none \\'x\\''",
+			want: &core.Column{Name: "plan", Type: core.DataTypeString, PortableType: "varchar(16)", DefaultValue: new("it's free"), Comment: "line\none 'x'"},
+		},
+		{
+			name: "charset from collation",
+			item: "`name` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL",
+			want: &core.Column{Name: "name", Type: core.DataTypeString, PortableType: "varchar(255)", Nullable: true,
+				Charset: "utf8mb4", Collate: "utf8mb4_unicode_ci"},
+		},
+		{
+			name: "character set",
+			item: "`name` char(2) CHARACTER SET latin1 COLLATE latin1_bin NOT NULL",
+			want: &core.Column{Name: "name", Type: core.DataTypeString, PortableType: "char(2)", Charset: "latin1", Collate: "latin1_bin"},
+		},
+		{
+			name: "timestamps",
+			item: "`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP",
+			want: &core.Column{Name: "updated_at", Type: core.DataTypeDatetime, PortableType: "timestamp", Nullable: true,
+				DefaultValue: new("CURRENT_TIMESTAMP"), OnUpdate: new("CURRENT_TIMESTAMP")},
+		},
+		{
+			name: "mariadb timestamps",
+			item: "`updated_at` datetime(3) NOT NULL DEFAULT current_timestamp(3) ON UPDATE current_timestamp(3)",
+			want: &core.Column{Name: "updated_at", Type: core.DataTypeDatetime, RawType: "datetime(3)",
+				DefaultValue: new("CURRENT_TIMESTAMP(3)"), OnUpdate: new("CURRENT_TIMESTAMP(3)")},
+		},
+		{
+			name: "expression default",
+			item: "`id` char(36) NOT NULL DEFAULT (uuid())",
+			want: &core.Column{Name: "id", Type: core.DataTypeString, PortableType: "char(36)", DefaultValue: new("(uuid())")},
+		},
+		{
+			name: "enum",
+			item: "`status` enum('active','it''s') NOT NULL DEFAULT 'active'",
+			want: &core.Column{Name: "status", Type: core.DataTypeEnum, PortableType: "enum('active','it''s')",
+				EnumValues: []string{"active", "it's"}, DefaultValue: new("active")},
+		},
+		{
+			name: "set",
+			item: "`tags` set('a','b') DEFAULT NULL",
+			want: &core.Column{Name: "tags", Type: core.DataTypeEnum, RawType: "set('a','b')", EnumValues: []string{"a", "b"}, Nullable: true},
+		},
+		{
+			name: "mysql generated column",
+			item: "`total` decimal(10,2) GENERATED ALWAYS AS ((`quantity` * `unit_price`)) STORED",
+			want: &core.Column{Name: "total", Type: core.DataTypeFloat, PortableType: "decimal(10,2)", Nullable: true,
+				IsGenerated: true, GenerationExpression: "(quantity * unit_price)", GenerationStorage: core.GenerationStored},
+		},
+		{
+			name: "mariadb generated column",
+			item: "`total` decimal(10,2) GENERATED ALWAYS AS (`quantity` * `unit_price`) VIRTUAL",
+			want: &core.Column{Name: "total", Type: core.DataTypeFloat, PortableType: "decimal(10,2)", Nullable: true,
+				IsGenerated: true, GenerationExpression: "(quantity * unit_price)", GenerationStorage: core.GenerationVirtual},
+		},
+		{
+			name: "invisible",
+			item: "`secret` varchar(64) DEFAULT NULL /*!80023 INVISIBLE */",
+			want: &core.Column{Name: "secret", Type: core.DataTypeString, PortableType: "varchar(64)", Nullable: true, Invisible: true},
+		},
+		{
+			name: "srid",
+			item: "`location` point NOT NULL /*!80003 SRID 4326 */",
+			want: &core.Column{Name: "location", Type: core.DataTypeUnknown, RawType: "point",
+				MySQL: &core.MySQLColumnOptions{SRID: new(uint32(4326))}},
+		},
+		{
+			name: "ndb options",
+			item: "`data` varchar(10) DEFAULT NULL /*!50606 STORAGE DISK */ /*!50606 COLUMN_FORMAT FIXED */",
+			want: &core.Column{Name: "data", Type: core.DataTypeString, PortableType: "varchar(10)", Nullable: true,
+				MySQL: &core.MySQLColumnOptions{Storage: "DISK", ColumnFormat: "FIXED"}},
+		},
+		{
+			name: "tidb auto random",
+			item: "`id` bigint NOT NULL /*T![auto_rand] AUTO_RANDOM(6, 54) */",
+			want: &core.Column{Name: "id", Type: core.DataTypeInt, PortableType: "bigint",
+				TiDB: &core.TiDBColumnOptions{ShardBits: 6, RangeBits: new(uint64(54))}},
+		},
+		{
+			name: "mariadb json",
+			item: "`data` longtext CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL CHECK (json_valid(`data`))",
+			want: &core.Column{Name: "data", Type: core.DataTypeJSON, PortableType: "json", Nullable: true},
+		},
+		{
+			name: "mariadb check",
+			item: "`age` int(11) DEFAULT NULL CHECK (`age` >= 0)",
+			want: &core.Column{Name: "age", Type: core.DataTypeInt, PortableType: "int", Nullable: true, Check: "age >= 0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			col, err := parseColumn(core.DialectMySQL, tt.item)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, col)
+		})
+	}
+}
+
+func TestParseColumnAutoRandomRoundTrip(t *testing.T) {
+	t.Parallel()
+	col, err := parseColumn(core.DialectTiDB, "`id` bigint NOT NULL /*T![auto_rand] AUTO_RANDOM(6, 54) */")
+	require.NoError(t, err)
+	col.PrimaryKey = true
+	table := &core.Table{Name: "orders", Columns: []*core.Column{col}, Constraints: []*core.Constraint{
+		{Name: "PRIMARY", Type: core.ConstraintPrimaryKey, Columns: []string{"id"}},
+	}}
+
+	script, err := genmysql.NewTiDB().Generate(&core.Database{Dialect: core.DialectTiDB, Tables: []*core.Table{table}})
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 1)
+	assert.Contains(t, script.Statements[0], "`id` BIGINT NOT NULL AUTO_RANDOM(6, 54)")
+}
+
+func TestParseColumnErrors(t *testing.T) {
+	t.Parallel()
+	for _, item := range []string{
+		"`id`",
+		"`id` int NOT DEFAULT",
+		"`id` int UNKNOWN_ATTRIBUTE",
+		"`name` varchar(10) COMMENT 'unterminated",
+		"`status` enum NOT NULL",
+	} {
+		_, err := parseColumn(core.DialectMySQL, item)
+		assert.Error(t, err, item)
+	}
+}
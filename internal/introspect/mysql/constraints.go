@@ -6,14 +6,200 @@ import (
 	"smf/internal/core"
 )
 
+// constraintKeywords start a constraint definition, so they are not taken
+// as the name of an unnamed CONSTRAINT.
+var constraintKeywords = map[string]bool{"PRIMARY": true, "UNIQUE": true, "FOREIGN": true, "CHECK": true}
+
 // parseConstraint parses a table-level constraint from a CREATE TABLE body item.
 //
 // Handles: PRIMARY KEY, UNIQUE KEY, FOREIGN KEY, CHECK, and named CONSTRAINT declarations.
+// MySQL names every primary key PRIMARY, so primary keys are returned
+// without a name.
 //
 // Example input: "PRIMARY KEY (`id`)"
 // Example input: "CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)".
 func parseConstraint(_ core.Dialect, item string) (*core.Constraint, error) {
-	// TODO: implement full constraint parsing.
-	_ = item
-	return nil, fmt.Errorf("parseConstraint not yet implemented")
+	tokens, err := tokenize(item)
+	if err != nil {
+		return nil, err
+	}
+	r := &tokenReader{tokens: tokens}
+	con := &core.Constraint{}
+	if r.accept("CONSTRAINT") && !constraintKeywords[r.peek().keyword()] {
+		if con.Name, err = r.identifier(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch r.next().keyword() {
+	case "PRIMARY":
+		con.Name = ""
+		err = parsePrimaryKey(r, con)
+	case "UNIQUE":
+		err = parseUnique(r, con)
+	case "FOREIGN":
+		err = parseForeignKey(r, con)
+	case "CHECK":
+		err = parseCheck(r, con)
+	default:
+		err = fmt.Errorf("unknown constraint %q", item)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("constraint %s: %w", con.Name, err)
+	}
+	return con, nil
+}
+
+// parsePrimaryKey parses the key parts of a primary key. Its index options
+// have no counterpart on a constraint and are skipped.
+func parsePrimaryKey(r *tokenReader, con *core.Constraint) error {
+	if err := r.expect("KEY"); err != nil {
+		return err
+	}
+	con.Type = core.ConstraintPrimaryKey
+	p := &indexParser{tokenReader: *r, idx: &core.Index{}}
+	if p.accept("USING") {
+		if err := p.parseUsing(); err != nil {
+			return err
+		}
+	}
+	if err := constraintColumns(&p.tokenReader, con); err != nil {
+		return err
+	}
+	return p.parseOptions()
+}
+
+// parseUnique parses UNIQUE [KEY | INDEX] [name] (columns).
+func parseUnique(r *tokenReader, con *core.Constraint) error {
+	con.Type = core.ConstraintUnique
+	if !r.accept("KEY") {
+		r.accept("INDEX")
+	}
+	if t := r.peek(); t.kind == tokenIdent || t.kind == tokenWord {
+		con.Name = r.next().text
+	}
+	return constraintColumns(r, con)
+}
+
+// parseForeignKey parses FOREIGN KEY [name] (columns) REFERENCES table
+// (columns) [ON DELETE action] [ON UPDATE action]. A table in another
+// database is referenced by its name only.
+func parseForeignKey(r *tokenReader, con *core.Constraint) error {
+	if err := r.expect("KEY"); err != nil {
+		return err
+	}
+	con.Type = core.ConstraintForeignKey
+	if t := r.peek(); t.kind == tokenIdent || t.kind == tokenWord {
+		r.next()
+	}
+	if err := constraintColumns(r, con); err != nil {
+		return err
+	}
+	if err := parseReferences(r, con); err != nil {
+		return err
+	}
+	for r.accept("ON") {
+		if err := parseReferentialAction(r, con); err != nil {
+			return err
+		}
+	}
+	if !r.done() {
+		return fmt.Errorf("unexpected %q", r.peek().raw)
+	}
+	return nil
+}
+
+func parseReferences(r *tokenReader, con *core.Constraint) error {
+	if err := r.expect("REFERENCES"); err != nil {
+		return err
+	}
+	table, err := r.identifier()
+	if err != nil {
+		return err
+	}
+	if t := r.peek(); t.kind == tokenSymbol && t.text == "." {
+		r.next()
+		if table, err = r.identifier(); err != nil {
+			return err
+		}
+	}
+	con.ReferencedTable = table
+
+	t := r.next()
+	if t.kind != tokenGroup {
+		return fmt.Errorf("expected referenced columns, got %q", t.raw)
+	}
+	parts, err := keyParts(t.text)
+	if err != nil {
+		return err
+	}
+	for _, part := range parts {
+		con.ReferencedColumns = append(con.ReferencedColumns, part.Name)
+	}
+	return nil
+}
+
+// parseReferentialAction parses DELETE or UPDATE and its action after ON.
+func parseReferentialAction(r *tokenReader, con *core.Constraint) error {
+	event := r.next().keyword()
+	action := core.ReferentialAction(r.next().keyword())
+	if action == "SET" || action == "NO" {
+		action += core.ReferentialAction(" " + r.next().keyword())
+	}
+	if action == core.RefActionNone || !action.IsValid() {
+		return fmt.Errorf("invalid referential action %q", action)
+	}
+
+	switch event {
+	case "DELETE":
+		con.OnDelete = action
+	case "UPDATE":
+		con.OnUpdate = action
+	default:
+		return fmt.Errorf("expected DELETE or UPDATE, got %q", event)
+	}
+	return nil
+}
+
+// parseCheck parses CHECK (expression) [[NOT] ENFORCED]. MySQL prints NOT
+// ENFORCED in a /*!80016 */ comment.
+func parseCheck(r *tokenReader, con *core.Constraint) error {
+	con.Type = core.ConstraintCheck
+	t := r.next()
+	if t.kind != tokenGroup {
+		return fmt.Errorf("expected a parenthesized expression, got %q", t.raw)
+	}
+	con.CheckExpression = groupExpression(t.text)
+
+	if r.accept("NOT") {
+		if err := r.expect("ENFORCED"); err != nil {
+			return err
+		}
+		con.Enforced = new(false)
+	} else {
+		r.accept("ENFORCED")
+	}
+	if !r.done() {
+		return fmt.Errorf("unexpected %q", r.peek().raw)
+	}
+	return nil
+}
+
+// constraintColumns reads the parenthesized columns of a constraint.
+func constraintColumns(r *tokenReader, con *core.Constraint) error {
+	t := r.next()
+	if t.kind != tokenGroup {
+		return fmt.Errorf("expected columns, got %q", t.raw)
+	}
+	parts, err := keyParts(t.text)
+	if err != nil {
+		return err
+	}
+	for _, part := range parts {
+		if part.Name == "" {
+			return fmt.Errorf("expression (%s) in a constraint", part.Expression)
+		}
+		con.Columns = append(con.Columns, part.Name)
+	}
+	return nil
 }
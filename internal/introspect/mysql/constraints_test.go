@@ -0,0 +1,86 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestParseConstraint(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		item string
+		want *core.Constraint
+	}{
+		{
+			name: "primary key",
+			item: "PRIMARY KEY (`tenant_id`,`id`)",
+			want: &core.Constraint{Type: core.ConstraintPrimaryKey, Columns: []string{"tenant_id", "id"}},
+		},
+		{
+			name: "tidb clustered primary key",
+			item: "PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */",
+			want: &core.Constraint{Type: core.ConstraintPrimaryKey, Columns: []string{"id"}},
+		},
+		{
+			name: "foreign key",
+			item: "CONSTRAINT `fk_posts_users` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE SET NULL",
+			want: &core.Constraint{Name: "fk_posts_users", Type: core.ConstraintForeignKey, Columns: []string{"user_id"},
+				ReferencedTable: "users", ReferencedColumns: []string{"id"},
+				OnDelete: core.RefActionCascade, OnUpdate: core.RefActionSetNull},
+		},
+		{
+			name: "foreign key to another database",
+			item: "CONSTRAINT `fk_a` FOREIGN KEY (`a`, `b`) REFERENCES `other`.`t` (`x`, `y`) ON UPDATE NO ACTION",
+			want: &core.Constraint{Name: "fk_a", Type: core.ConstraintForeignKey, Columns: []string{"a", "b"},
+				ReferencedTable: "t", ReferencedColumns: []string{"x", "y"}, OnUpdate: core.RefActionNoAction},
+		},
+		{
+			name: "check",
+			item: "CONSTRAINT `chk_discount` CHECK (((`discount_price` is null) or (`discount_price` < `price`)))",
+			want: &core.Constraint{Name: "chk_discount", Type: core.ConstraintCheck,
+				CheckExpression: "(discount_price is null) or (discount_price < price)"},
+		},
+		{
+			name: "check not enforced",
+			item: "CONSTRAINT `chk_price` CHECK ((`price` > 0)) /*!80016 NOT ENFORCED */",
+			want: &core.Constraint{Name: "chk_price", Type: core.ConstraintCheck, CheckExpression: "price > 0", Enforced: new(false)},
+		},
+		{
+			name: "check with string",
+			item: "CONSTRAINT `chk_code` CHECK ((`code` <> _utf8mb4'`x`'))",
+			want: &core.Constraint{Name: "chk_code", Type: core.ConstraintCheck, CheckExpression: "code <> _utf8mb4'`x`'"},
+		},
+		{
+			name: "named unique",
+			item: "CONSTRAINT `uq_users_email` UNIQUE (`email`)",
+			want: &core.Constraint{Name: "uq_users_email", Type: core.ConstraintUnique, Columns: []string{"email"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			con, err := parseConstraint(core.DialectMySQL, tt.item)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, con)
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	t.Parallel()
+	for _, item := range []string{
+		"CONSTRAINT `x` EXCLUDE (`a`)",
+		"CONSTRAINT `fk` FOREIGN KEY (`a`) REFERENCES `t` (`b`) ON DELETE EXPLODE",
+		"CONSTRAINT `fk` FOREIGN KEY (`a`) `t` (`b`)",
+		"CONSTRAINT `chk` CHECK `a` > 0",
+		"PRIMARY KEY ((`a` + 1))",
+	} {
+		_, err := parseConstraint(core.DialectMySQL, item)
+		assert.Error(t, err, item)
+	}
+}
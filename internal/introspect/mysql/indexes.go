@@ -2,18 +2,191 @@ package mysql
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"smf/internal/core"
 )
 
+// indexOptions maps the keyword that starts an index option to its parser.
+// KEY_BLOCK_SIZE, the engine attributes, and TiDB's CLUSTERED have no
+// counterpart in the schema and are skipped.
+var indexOptions = map[string]func(*indexParser) error{
+	"USING":                      (*indexParser).parseUsing,
+	"COMMENT":                    func(p *indexParser) error { return p.stringInto(&p.idx.Comment) },
+	"VISIBLE":                    func(p *indexParser) error { p.idx.Visibility = core.IndexVisible; return nil },
+	"INVISIBLE":                  func(p *indexParser) error { p.idx.Visibility = core.IndexInvisible; return nil },
+	"IGNORED":                    func(p *indexParser) error { p.idx.Visibility = core.IndexInvisible; return nil },
+	"NOT":                        (*indexParser).parseNotIgnored,
+	"WITH":                       (*indexParser).parseWithParser,
+	"KEY_BLOCK_SIZE":             func(p *indexParser) error { return p.wordInto(new(string)) },
+	"ENGINE_ATTRIBUTE":           func(p *indexParser) error { return p.stringInto(new(string)) },
+	"SECONDARY_ENGINE_ATTRIBUTE": func(p *indexParser) error { return p.stringInto(new(string)) },
+	"CLUSTERED":                  func(*indexParser) error { return nil },
+	"NONCLUSTERED":               func(*indexParser) error { return nil },
+}
+
+// indexParser parses the tokens of an index declaration into idx.
+type indexParser struct {
+	tokenReader
+	idx *core.Index
+}
+
 // parseIndex parses an inline index declaration from a CREATE TABLE body item.
 //
-// Handles: KEY, INDEX, FULLTEXT KEY/INDEX, SPATIAL KEY/INDEX.
+// Handles: KEY, INDEX, UNIQUE KEY/INDEX, FULLTEXT KEY/INDEX, SPATIAL KEY/INDEX.
 //
 // Example input: "KEY `idx_name` (`name`)"
 // Example input: "FULLTEXT INDEX `ft_content` (`content`)".
 func parseIndex(_ core.Dialect, item string) (*core.Index, error) {
-	// TODO: implement full index parsing.
-	_ = item
-	return nil, fmt.Errorf("parseIndex not yet implemented")
+	tokens, err := tokenize(item)
+	if err != nil {
+		return nil, err
+	}
+	p := &indexParser{
+		tokenReader: tokenReader{tokens: tokens},
+		idx:         &core.Index{Type: core.IndexTypeBTree, Visibility: core.IndexVisible},
+	}
+	if err := p.parseHead(); err != nil {
+		return nil, fmt.Errorf("index %s: %w", item, err)
+	}
+	if err := p.parseOptions(); err != nil {
+		return nil, fmt.Errorf("index %s: %w", p.idx.Name, err)
+	}
+	return p.idx, nil
+}
+
+// parseHead parses the index kind, name, and key parts.
+func (p *indexParser) parseHead() error {
+	switch p.peek().keyword() {
+	case "UNIQUE":
+		p.idx.Unique = true
+	case "FULLTEXT":
+		p.idx.Type = core.IndexTypeFullText
+	case "SPATIAL":
+		p.idx.Type = core.IndexTypeSpatial
+	}
+	if p.idx.Unique || p.idx.Type != core.IndexTypeBTree {
+		p.next()
+	}
+	if !p.accept("KEY") && !p.accept("INDEX") && !p.idx.Unique && p.idx.Type == core.IndexTypeBTree {
+		return fmt.Errorf("expected KEY or INDEX, got %q", p.peek().raw)
+	}
+
+	if t := p.peek(); t.kind == tokenIdent || t.kind == tokenWord && t.keyword() != "USING" {
+		p.idx.Name = p.next().text
+	}
+	if p.accept("USING") {
+		if err := p.parseUsing(); err != nil {
+			return err
+		}
+	}
+	t := p.next()
+	if t.kind != tokenGroup {
+		return fmt.Errorf("expected key parts, got %q", t.raw)
+	}
+	parts, err := keyParts(t.text)
+	if err != nil {
+		return err
+	}
+	p.idx.Columns = parts
+	return nil
+}
+
+func (p *indexParser) parseOptions() error {
+	for !p.done() {
+		t := p.next()
+		parse, ok := indexOptions[t.keyword()]
+		if !ok {
+			return fmt.Errorf("unexpected %q", t.raw)
+		}
+		if err := parse(p); err != nil {
+			return fmt.Errorf("%s: %w", t.keyword(), err)
+		}
+	}
+	return nil
+}
+
+func (p *indexParser) parseUsing() error {
+	var algorithm string
+	if err := p.wordInto(&algorithm); err != nil {
+		return err
+	}
+	typ := core.IndexType(strings.ToUpper(algorithm))
+	if typ != core.IndexTypeBTree && typ != core.IndexTypeHash {
+		return fmt.Errorf("unknown index algorithm %q", algorithm)
+	}
+	p.idx.Type = typ
+	return nil
+}
+
+// parseNotIgnored parses MariaDB's NOT IGNORED.
+func (p *indexParser) parseNotIgnored() error {
+	if err := p.expect("IGNORED"); err != nil {
+		return err
+	}
+	p.idx.Visibility = core.IndexVisible
+	return nil
+}
+
+func (p *indexParser) parseWithParser() error {
+	if err := p.expect("PARSER"); err != nil {
+		return err
+	}
+	parser, err := p.identifier()
+	p.idx.Parser = parser
+	return err
+}
+
+// keyParts parses the key parts of an index: columns with an optional
+// prefix length, and parenthesized expressions, each with an optional
+// ASC or DESC.
+//
+// Example input: "(`name`(10),`created_at` DESC,(lower(`email`)))".
+func keyParts(group string) ([]core.ColumnIndex, error) {
+	tokens, err := groupTokens(group)
+	if err != nil {
+		return nil, err
+	}
+	r := &tokenReader{tokens: tokens}
+	var parts []core.ColumnIndex
+	for {
+		part, err := keyPart(r)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+		if r.done() {
+			return parts, nil
+		}
+		if t := r.next(); t.kind != tokenSymbol || t.text != "," {
+			return nil, fmt.Errorf("unexpected %q in key parts", t.raw)
+		}
+	}
+}
+
+func keyPart(r *tokenReader) (core.ColumnIndex, error) {
+	part := core.ColumnIndex{Order: core.SortAsc}
+	t := r.next()
+	switch t.kind {
+	case tokenGroup:
+		part.Expression = groupExpression(t.text)
+	case tokenIdent, tokenWord:
+		part.Name = t.text
+		if r.peek().kind == tokenGroup {
+			n, err := strconv.Atoi(unwrapGroup(r.next().text))
+			if err != nil {
+				return part, fmt.Errorf("invalid prefix length of %s", part.Name)
+			}
+			part.Length = n
+		}
+	default:
+		return part, fmt.Errorf("unexpected %q in key parts", t.raw)
+	}
+	if r.accept("DESC") {
+		part.Order = core.SortDesc
+	} else {
+		r.accept("ASC")
+	}
+	return part, nil
 }
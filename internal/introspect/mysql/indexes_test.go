@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestParseIndex(t *testing.T) {
+	t.Parallel()
+	asc := func(name string) core.ColumnIndex { return core.ColumnIndex{Name: name, Order: core.SortAsc} }
+	tests := []struct {
+		name string
+		item string
+		want *core.Index
+	}{
+		{
+			name: "key",
+			item: "KEY `idx_author` (`author_id`,`created_at` DESC)",
+			want: &core.Index{Name: "idx_author", Type: core.IndexTypeBTree, Visibility: core.IndexVisible,
+				Columns: []core.ColumnIndex{asc("author_id"), {Name: "created_at", Order: core.SortDesc}}},
+		},
+		{
+			name: "prefix length and options",
+			item: "UNIQUE KEY `idx_name` (`name`(10)) USING HASH COMMENT 'by ''name''' /*!80000 INVISIBLE */",
+			want: &core.Index{Name: "idx_name", Unique: true, Type: core.IndexTypeHash, Visibility: core.IndexInvisible,
+				Comment: "by 'name'", Columns: []core.ColumnIndex{{Name: "name", Length: 10, Order: core.SortAsc}}},
+		},
+		{
+			name: "functional key part",
+			item: "KEY `idx_email` ((lower(`email`)) DESC,`id`)",
+			want: &core.Index{Name: "idx_email", Type: core.IndexTypeBTree, Visibility: core.IndexVisible,
+				Columns: []core.ColumnIndex{{Expression: "lower(email)", Order: core.SortDesc}, asc("id")}},
+		},
+		{
+			name: "fulltext with parser",
+			item: "FULLTEXT KEY `ft_title_content` (`title`,`content`) /*!50100 WITH PARSER `ngram` */ ",
+			want: &core.Index{Name: "ft_title_content", Type: core.IndexTypeFullText, Visibility: core.IndexVisible,
+				Parser: "ngram", Columns: []core.ColumnIndex{asc("title"), asc("content")}},
+		},
+		{
+			name: "spatial",
+			item: "SPATIAL KEY `sp_location` (`location`)",
+			want: &core.Index{Name: "sp_location", Type: core.IndexTypeSpatial, Visibility: core.IndexVisible,
+				Columns: []core.ColumnIndex{asc("location")}},
+		},
+		{
+			name: "mariadb ignored",
+			item: "KEY `idx_amount` (`amount`) USING BTREE IGNORED",
+			want: &core.Index{Name: "idx_amount", Type: core.IndexTypeBTree, Visibility: core.IndexInvisible,
+				Columns: []core.ColumnIndex{asc("amount")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			idx, err := parseIndex(core.DialectMySQL, tt.item)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, idx)
+		})
+	}
+}
+
+func TestParseIndexErrors(t *testing.T) {
+	t.Parallel()
+	for _, item := range []string{
+		"KEY `idx`",
+		"KEY `idx` ()",
+		"KEY `idx` (`a` `b`)",
+		"KEY `idx` (`a`(x))",
+		"KEY `idx` (`a`) USING RTREE",
+		"KEY `idx` (`a`) PARTITIONED",
+	} {
+		_, err := parseIndex(core.DialectMySQL, item)
+		assert.Error(t, err, item)
+	}
+}
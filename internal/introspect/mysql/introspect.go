@@ -55,5 +55,10 @@ func (i *introspecter) Introspect(ctx context.Context, db *sql.DB) (*core.Databa
 		return nil, err
 	}
 
+	err = introspectViews(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
 	return d, nil
 }
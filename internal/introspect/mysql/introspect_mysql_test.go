@@ -323,7 +323,11 @@ func TestMySQLConstraints(t *testing.T) {
 
 	productsTable := result.FindTable("products")
 	require.NotNil(t, productsTable)
-	require.Len(t, productsTable.Constraints, 2)
+	require.Len(t, productsTable.Constraints, 3, "the primary key and two checks")
+	chkPrice := productsTable.FindConstraint("chk_price")
+	require.NotNil(t, chkPrice)
+	require.Equal(t, core.ConstraintCheck, chkPrice.Type)
+	require.Equal(t, "price > 0", chkPrice.CheckExpression)
 }
 
 func TestMySQLIndexes(t *testing.T) {
@@ -0,0 +1,330 @@
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// tableOptions maps the keyword that starts a table option to its parser.
+// ENCRYPTED, MariaDB's spelling of ENCRYPTION, has no counterpart the
+// MariaDB generator accepts and is skipped.
+var tableOptions = map[string]func(*optionsParser) error{
+	"ENGINE":                     func(p *optionsParser) error { return p.wordInto(&p.mysql.Engine) },
+	"CHARSET":                    func(p *optionsParser) error { return p.wordInto(&p.mysql.Charset) },
+	"CHARACTER":                  (*optionsParser).parseCharacterSet,
+	"COLLATE":                    func(p *optionsParser) error { return p.wordInto(&p.mysql.Collate) },
+	"AUTO_INCREMENT":             func(p *optionsParser) error { return p.numberInto(&p.mysql.AutoIncrement) },
+	"ROW_FORMAT":                 func(p *optionsParser) error { return p.wordInto(&p.mysql.RowFormat) },
+	"AVG_ROW_LENGTH":             func(p *optionsParser) error { return p.numberInto(&p.mysql.AvgRowLength) },
+	"KEY_BLOCK_SIZE":             func(p *optionsParser) error { return p.numberInto(&p.mysql.KeyBlockSize) },
+	"MAX_ROWS":                   func(p *optionsParser) error { return p.numberInto(&p.mysql.MaxRows) },
+	"MIN_ROWS":                   func(p *optionsParser) error { return p.numberInto(&p.mysql.MinRows) },
+	"CHECKSUM":                   func(p *optionsParser) error { return p.numberInto(&p.mysql.Checksum) },
+	"DELAY_KEY_WRITE":            func(p *optionsParser) error { return p.numberInto(&p.mysql.DelayKeyWrite) },
+	"COMPRESSION":                func(p *optionsParser) error { return p.stringInto(&p.mysql.Compression) },
+	"ENCRYPTION":                 func(p *optionsParser) error { return p.stringInto(&p.mysql.Encryption) },
+	"ENCRYPTED":                  func(p *optionsParser) error { return p.wordInto(new(string)) },
+	"PACK_KEYS":                  func(p *optionsParser) error { return p.wordInto(&p.mysql.PackKeys) },
+	"DATA":                       func(p *optionsParser) error { return p.directoryInto(&p.mysql.DataDirectory) },
+	"INDEX":                      func(p *optionsParser) error { return p.directoryInto(&p.mysql.IndexDirectory) },
+	"INSERT_METHOD":              func(p *optionsParser) error { return p.wordInto(&p.mysql.InsertMethod) },
+	"TABLESPACE":                 (*optionsParser).parseTablespace,
+	"STORAGE":                    func(p *optionsParser) error { return p.wordInto(&p.mysql.StorageMedia) },
+	"STATS_PERSISTENT":           func(p *optionsParser) error { return p.wordInto(&p.mysql.StatsPersistent) },
+	"STATS_AUTO_RECALC":          func(p *optionsParser) error { return p.wordInto(&p.mysql.StatsAutoRecalc) },
+	"STATS_SAMPLE_PAGES":         func(p *optionsParser) error { return p.wordInto(&p.mysql.StatsSamplePages) },
+	"CONNECTION":                 func(p *optionsParser) error { return p.stringInto(&p.mysql.Connection) },
+	"PASSWORD":                   func(p *optionsParser) error { return p.stringInto(&p.mysql.Password) },
+	"AUTOEXTEND_SIZE":            func(p *optionsParser) error { return p.wordInto(&p.mysql.AutoextendSize) },
+	"UNION":                      (*optionsParser).parseUnion,
+	"SECONDARY_ENGINE":           func(p *optionsParser) error { return p.wordInto(&p.mysql.SecondaryEngine) },
+	"TABLE_CHECKSUM":             func(p *optionsParser) error { return p.numberInto(&p.mysql.TableChecksum) },
+	"ENGINE_ATTRIBUTE":           func(p *optionsParser) error { return p.stringInto(&p.mysql.EngineAttribute) },
+	"SECONDARY_ENGINE_ATTRIBUTE": func(p *optionsParser) error { return p.stringInto(&p.mysql.SecondaryEngineAttribute) },
+	"PAGE_COMPRESSED":            func(p *optionsParser) error { return p.flagInto(&p.mysql.PageCompressed) },
+	"PAGE_COMPRESSION_LEVEL":     func(p *optionsParser) error { return p.numberInto(&p.mysql.PageCompressionLevel) },
+	"IETF_QUOTES":                func(p *optionsParser) error { return p.flagInto(&p.mysql.IETFQuotes) },
+	"NODEGROUP":                  func(p *optionsParser) error { return p.numberInto(&p.mysql.Nodegroup) },
+	"COMMENT":                    func(p *optionsParser) error { return p.stringInto(&p.table.Comment) },
+	"PARTITION":                  (*optionsParser).parsePartitionBy,
+
+	"PAGE_CHECKSUM":     func(p *optionsParser) error { return p.numberInto(&p.mariadb().PageChecksum) },
+	"TRANSACTIONAL":     func(p *optionsParser) error { return p.numberInto(&p.mariadb().Transactional) },
+	"ENCRYPTION_KEY_ID": (*optionsParser).parseEncryptionKeyID,
+	"WITH":              (*optionsParser).parseSystemVersioning,
+	"SEQUENCE":          (*optionsParser).parseSequence,
+
+	"AUTO_ID_CACHE":     func(p *optionsParser) error { return p.numberInto(&p.tidb().AutoIDCache) },
+	"AUTO_RANDOM_BASE":  func(p *optionsParser) error { return p.numberInto(&p.tidb().AutoRandomBase) },
+	"SHARD_ROW_ID_BITS": func(p *optionsParser) error { return p.numberInto(&p.tidb().ShardRowID) },
+	"PRE_SPLIT_REGIONS": func(p *optionsParser) error { return p.numberInto(&p.tidb().PreSplitRegion) },
+	"TTL":               (*optionsParser).parseTTL,
+	"TTL_ENABLE":        func(p *optionsParser) error { return p.flagInto(&p.tidb().TTLEnable) },
+	"TTL_JOB_INTERVAL":  func(p *optionsParser) error { return p.stringInto(&p.tidb().TTLJobInterval) },
+	"AFFINITY":          func(p *optionsParser) error { return p.wordInto(&p.tidb().Affinity) },
+	"PLACEMENT":         (*optionsParser).parsePlacementPolicy,
+	"STATS_BUCKETS":     func(p *optionsParser) error { return p.numberInto(&p.tidb().StatsBuckets) },
+	"STATS_TOPN":        func(p *optionsParser) error { return p.numberInto(&p.tidb().StatsTopN) },
+	"STATS_COL_CHOICE":  func(p *optionsParser) error { return p.wordInto(&p.tidb().StatsColsChoice) },
+	"STATS_COL_LIST":    func(p *optionsParser) error { return p.wordInto(&p.tidb().StatsColList) },
+	"STATS_SAMPLE_RATE": (*optionsParser).parseStatsSampleRate,
+}
+
+// optionsParser parses the table options that follow the body of a CREATE
+// TABLE statement into table.
+type optionsParser struct {
+	tokenReader
+	table *core.Table
+	mysql *core.MySQLTableOptions
+}
+
+// parseTableOptions parses the table options of a CREATE TABLE statement,
+// as printed by SHOW CREATE TABLE, into table. MySQL options are kept for
+// all three dialects; MariaDB and TiDB options are added to the options of
+// their dialect.
+//
+// Example input: "ENGINE=InnoDB AUTO_INCREMENT=12 DEFAULT CHARSET=utf8mb4 COMMENT='Users'".
+func parseTableOptions(table *core.Table, tail string) error {
+	tokens, err := tokenize(tail)
+	if err != nil {
+		return err
+	}
+	if table.Options.MySQL == nil {
+		table.Options.MySQL = &core.MySQLTableOptions{}
+	}
+	p := &optionsParser{tokenReader: tokenReader{tokens: tokens}, table: table, mysql: table.Options.MySQL}
+	for !p.done() {
+		if t := p.peek(); t.kind == tokenSymbol && t.text == "," {
+			p.next()
+			continue
+		}
+		if err := p.parseOption(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseOption parses one option. DEFAULT before CHARSET and COLLATE is
+// optional and does not change their meaning. MariaDB quotes the names of
+// options defined by the storage engine, as in `ENCRYPTED`=YES.
+func (p *optionsParser) parseOption() error {
+	p.accept("DEFAULT")
+	t := p.next()
+	name := t.keyword()
+	if t.kind == tokenIdent {
+		name = strings.ToUpper(t.text)
+	}
+	parse, ok := tableOptions[name]
+	if !ok {
+		return fmt.Errorf("unknown table option %q", t.raw)
+	}
+	if err := parse(p); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func (p *optionsParser) mariadb() *core.MariaDBTableOptions {
+	if p.table.Options.MariaDB == nil {
+		p.table.Options.MariaDB = &core.MariaDBTableOptions{}
+	}
+	return p.table.Options.MariaDB
+}
+
+func (p *optionsParser) tidb() *core.TiDBTableOptions {
+	if p.table.Options.TiDB == nil {
+		p.table.Options.TiDB = &core.TiDBTableOptions{}
+	}
+	return p.table.Options.TiDB
+}
+
+func (p *optionsParser) numberInto(dst *uint64) error {
+	var s string
+	if err := p.wordInto(&s); err != nil {
+		return err
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %q", s)
+	}
+	*dst = n
+	return nil
+}
+
+// flagInto reads a boolean option, written as 1 or 0 in MySQL and also as
+// ON or OFF in MariaDB and TiDB.
+func (p *optionsParser) flagInto(dst *bool) error {
+	var s string
+	if err := p.wordInto(&s); err != nil {
+		return err
+	}
+	switch strings.ToUpper(s) {
+	case "1", "ON", "YES":
+		*dst = true
+	case "0", "OFF", "NO":
+		*dst = false
+	default:
+		return fmt.Errorf("invalid flag %q", s)
+	}
+	return nil
+}
+
+// directoryInto parses DIRECTORY [=] 'path' after DATA or INDEX.
+func (p *optionsParser) directoryInto(dst *string) error {
+	if err := p.expect("DIRECTORY"); err != nil {
+		return err
+	}
+	return p.stringInto(dst)
+}
+
+func (p *optionsParser) parseCharacterSet() error {
+	if err := p.expect("SET"); err != nil {
+		return err
+	}
+	return p.wordInto(&p.mysql.Charset)
+}
+
+func (p *optionsParser) parseTablespace() error {
+	p.acceptEquals()
+	name, err := p.identifier()
+	if err != nil {
+		return err
+	}
+	p.table.Options.Tablespace = name
+	return nil
+}
+
+// parseUnion parses UNION [=] (table, ...) of a MERGE table.
+func (p *optionsParser) parseUnion() error {
+	p.acceptEquals()
+	t := p.next()
+	if t.kind != tokenGroup {
+		return fmt.Errorf("expected a table list, got %q", t.raw)
+	}
+	tokens, err := groupTokens(t.text)
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		switch {
+		case t.kind == tokenIdent || t.kind == tokenWord:
+			p.mysql.Union = append(p.mysql.Union, t.text)
+		case t.kind != tokenSymbol || t.text != ",":
+			return fmt.Errorf("unexpected %q in table list", t.raw)
+		}
+	}
+	return nil
+}
+
+// parsePartitionBy parses the partitioning clause, which comes last, and
+// keeps it with its partition definitions.
+func (p *optionsParser) parsePartitionBy() error {
+	if err := p.expect("BY"); err != nil {
+		return err
+	}
+	p.mysql.PartitionBy = p.rest()
+	if p.mysql.PartitionBy == "" {
+		return fmt.Errorf("missing partitioning type")
+	}
+	return nil
+}
+
+// rest joins the remaining tokens into a clause without identifier quotes
+// and with runs of whitespace collapsed.
+func (p *optionsParser) rest() string {
+	var parts []string
+	for !p.done() {
+		parts = append(parts, p.next().raw)
+	}
+	return unquoteIdentifiers(strings.Join(strings.Fields(strings.Join(parts, " ")), " "))
+}
+
+func (p *optionsParser) parseEncryptionKeyID() error {
+	var s string
+	if err := p.wordInto(&s); err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid key id %q", s)
+	}
+	p.mariadb().EncryptionKeyID = &id
+	return nil
+}
+
+// parseSystemVersioning parses MariaDB's WITH SYSTEM VERSIONING.
+func (p *optionsParser) parseSystemVersioning() error {
+	if err := p.expect("SYSTEM"); err != nil {
+		return err
+	}
+	if err := p.expect("VERSIONING"); err != nil {
+		return err
+	}
+	p.mariadb().WithSystemVersioning = true
+	return nil
+}
+
+// parseSequence parses SEQUENCE=1, which marks a MariaDB or TiDB table
+// that backs a sequence.
+func (p *optionsParser) parseSequence() error {
+	var sequence bool
+	if err := p.flagInto(&sequence); err != nil {
+		return err
+	}
+	if p.table.Options.TiDB != nil {
+		p.tidb().Sequence = sequence
+	} else {
+		p.mariadb().Sequence = sequence
+	}
+	return nil
+}
+
+// parseTTL parses TiDB's TTL [=] column + INTERVAL value unit. The
+// expression has no delimiter and ends with the unit of the interval.
+func (p *optionsParser) parseTTL() error {
+	p.acceptEquals()
+	var parts []string
+	for !p.done() {
+		t := p.next()
+		parts = append(parts, t.raw)
+		if t.keyword() == "INTERVAL" {
+			parts = append(parts, p.next().raw, p.next().raw)
+			break
+		}
+	}
+	if len(parts) < 3 || parts[len(parts)-1] == "" {
+		return fmt.Errorf("expected column + INTERVAL value unit")
+	}
+	p.tidb().TTL = unquoteIdentifiers(strings.Join(parts, " "))
+	return nil
+}
+
+func (p *optionsParser) parsePlacementPolicy() error {
+	if err := p.expect("POLICY"); err != nil {
+		return err
+	}
+	p.acceptEquals()
+	name, err := p.identifier()
+	if err != nil {
+		return err
+	}
+	p.tidb().PlacementPolicy = name
+	return nil
+}
+
+func (p *optionsParser) parseStatsSampleRate() error {
+	var s string
+	if err := p.wordInto(&s); err != nil {
+		return err
+	}
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid rate %q", s)
+	}
+	p.tidb().StatsSampleRate = rate
+	return nil
+}
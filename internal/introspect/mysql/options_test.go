@@ -0,0 +1,145 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestParseTableOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		tail string
+		want core.TableOptions
+	}{
+		{
+			name: "innodb",
+			tail: "ENGINE=InnoDB AUTO_INCREMENT=1000 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci " +
+				"ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8 COMPRESSION='ZLIB' ENCRYPTION='N' " +
+				"STATS_PERSISTENT=1 STATS_AUTO_RECALC=DEFAULT STATS_SAMPLE_PAGES=10",
+			want: core.TableOptions{MySQL: &core.MySQLTableOptions{
+				Engine: "InnoDB", AutoIncrement: 1000, Charset: "utf8mb4", Collate: "utf8mb4_unicode_ci",
+				RowFormat: "COMPRESSED", KeyBlockSize: 8, Compression: "ZLIB", Encryption: "N",
+				StatsPersistent: "1", StatsAutoRecalc: "DEFAULT", StatsSamplePages: "10",
+			}},
+		},
+		{
+			name: "myisam",
+			tail: "ENGINE=MyISAM DEFAULT CHARACTER SET latin1 MAX_ROWS=1000000 MIN_ROWS=10 AVG_ROW_LENGTH=100 " +
+				"PACK_KEYS=1 CHECKSUM=1 DELAY_KEY_WRITE=1 " +
+				"DATA DIRECTORY='/var/lib/mysql-data/' INDEX DIRECTORY='/var/lib/mysql-index/'",
+			want: core.TableOptions{MySQL: &core.MySQLTableOptions{
+				Engine: "MyISAM", Charset: "latin1", MaxRows: 1000000, MinRows: 10, AvgRowLength: 100,
+				PackKeys: "1", Checksum: 1, DelayKeyWrite: 1,
+				DataDirectory: "/var/lib/mysql-data/", IndexDirectory: "/var/lib/mysql-index/",
+			}},
+		},
+		{
+			name: "merge",
+			tail: "ENGINE=MRG_MyISAM DEFAULT CHARSET=utf8mb4 INSERT_METHOD=LAST UNION=(`log_2023`,`log_2024`)",
+			want: core.TableOptions{MySQL: &core.MySQLTableOptions{
+				Engine: "MRG_MyISAM", Charset: "utf8mb4", InsertMethod: "LAST", Union: []string{"log_2023", "log_2024"},
+			}},
+		},
+		{
+			name: "federated",
+			tail: "ENGINE=FEDERATED DEFAULT CHARSET=utf8mb4 CONNECTION='mysql://user@remote_host:3306/testdb/users'",
+			want: core.TableOptions{MySQL: &core.MySQLTableOptions{
+				Engine: "FEDERATED", Charset: "utf8mb4", Connection: "mysql://user@remote_host:3306/testdb/users",
+			}},
+		},
+		{
+			name: "tablespace and storage",
+			tail: "/*!50100 TABLESPACE `ts1` STORAGE DISK */ ENGINE=ndbcluster /*!80023 AUTOEXTEND_SIZE=134217728 */",
+			want: core.TableOptions{Tablespace: "ts1", MySQL: &core.MySQLTableOptions{
+				Engine: "ndbcluster", StorageMedia: "DISK", AutoextendSize: "134217728",
+			}},
+		},
+		{
+			name: "heatwave",
+			tail: "ENGINE=InnoDB SECONDARY_ENGINE=RAPID ENGINE_ATTRIBUTE='{\"k\": 1}'",
+			want: core.TableOptions{MySQL: &core.MySQLTableOptions{
+				Engine: "InnoDB", SecondaryEngine: "RAPID", EngineAttribute: `{"k": 1}`,
+			}},
+		},
+		{
+			name: "page compression and csv",
+			tail: "ENGINE=InnoDB `PAGE_COMPRESSED`='ON' `PAGE_COMPRESSION_LEVEL`=9 IETF_QUOTES=1",
+			want: core.TableOptions{MySQL: &core.MySQLTableOptions{
+				Engine: "InnoDB", PageCompressed: true, PageCompressionLevel: 9, IETFQuotes: true,
+			}},
+		},
+		{
+			name: "partitioning",
+			tail: "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4\n/*!50100 PARTITION BY RANGE (year(`created_at`))\n" +
+				"(PARTITION p0 VALUES LESS THAN (2024) ENGINE = InnoDB,\n PARTITION p1 VALUES LESS THAN MAXVALUE ENGINE = InnoDB) */",
+			want: core.TableOptions{MySQL: &core.MySQLTableOptions{
+				Engine: "InnoDB", Charset: "utf8mb4",
+				PartitionBy: "RANGE (year(created_at)) (PARTITION p0 VALUES LESS THAN (2024) ENGINE = InnoDB, " +
+					"PARTITION p1 VALUES LESS THAN MAXVALUE ENGINE = InnoDB)",
+			}},
+		},
+		{
+			name: "mariadb",
+			tail: "ENGINE=Aria DEFAULT CHARSET=utf8mb4 PAGE_CHECKSUM=1 TRANSACTIONAL=1 `ENCRYPTED`=YES ENCRYPTION_KEY_ID=5 " +
+				"WITH SYSTEM VERSIONING",
+			want: core.TableOptions{
+				MySQL: &core.MySQLTableOptions{Engine: "Aria", Charset: "utf8mb4"},
+				MariaDB: &core.MariaDBTableOptions{
+					PageChecksum: 1, Transactional: 1, EncryptionKeyID: new(5), WithSystemVersioning: true,
+				},
+			},
+		},
+		{
+			name: "tidb",
+			tail: "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin AUTO_INCREMENT=30001 " +
+				"/*T! SHARD_ROW_ID_BITS=4 PRE_SPLIT_REGIONS=2 */ /*T![auto_id_cache] AUTO_ID_CACHE=100 */ " +
+				"/*T![ttl] TTL=`created_at` + INTERVAL 3 MONTH */ /*T![ttl] TTL_ENABLE='ON' */ " +
+				"/*T![ttl] TTL_JOB_INTERVAL='1h' */ /*T![placement] PLACEMENT POLICY=`p1` */ STATS_SAMPLE_RATE=0.5",
+			want: core.TableOptions{
+				MySQL: &core.MySQLTableOptions{Engine: "InnoDB", Charset: "utf8mb4", Collate: "utf8mb4_bin", AutoIncrement: 30001},
+				TiDB: &core.TiDBTableOptions{
+					ShardRowID: 4, PreSplitRegion: 2, AutoIDCache: 100,
+					TTL: "created_at + INTERVAL 3 MONTH", TTLEnable: true, TTLJobInterval: "1h",
+					PlacementPolicy: "p1", StatsSampleRate: 0.5,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			table := &core.Table{Name: "t"}
+			require.NoError(t, parseTableOptions(table, tt.tail))
+			assert.Equal(t, tt.want, table.Options)
+		})
+	}
+}
+
+func TestParseTableOptionsComment(t *testing.T) {
+	t.Parallel()
+	table := &core.Table{Name: "t"}
+	require.NoError(t, parseTableOptions(table, "ENGINE=InnoDB COMMENT='Users, and their ''roles'''"))
+	assert.Equal(t, "Users, and their 'roles'", table.Comment)
+}
+
+func TestParseTableOptionsErrors(t *testing.T) {
+	t.Parallel()
+	for _, tail := range []string{
+		"ENGINE",
+		"AUTO_INCREMENT=abc",
+		"UNKNOWN_OPTION=1",
+		"DATA='/tmp'",
+		"UNION=`t1`",
+		"PARTITION BY",
+		"TTL=`created_at` + INTERVAL",
+		"COMMENT='unterminated",
+	} {
+		err := parseTableOptions(&core.Table{Name: "t"}, tail)
+		assert.Error(t, err, tail)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 
@@ -199,12 +200,18 @@ func parseCreateTableDDL(dialect core.Dialect, tableName, ddl string) (*core.Tab
 			if err != nil {
 				return nil, fmt.Errorf("parse index: %w", err)
 			}
-			table.Indexes = append(table.Indexes, idx)
+			if con := uniqueConstraint(idx); con != nil {
+				table.Constraints = append(table.Constraints, con)
+			} else {
+				table.Indexes = append(table.Indexes, idx)
+			}
 		}
 	}
+	resolveKeys(table)
 
-	// TODO: parse sections.tail for table options (ENGINE, CHARSET, etc.)
-	_ = sections.tail
+	if err := parseTableOptions(table, sections.tail); err != nil {
+		return nil, fmt.Errorf("parse table options: %w", err)
+	}
 
 	return table, nil
 }
@@ -322,7 +329,6 @@ func classifyBodyItem(item string) bodyItemKind {
 
 	for _, prefix := range []string{
 		"PRIMARY KEY",
-		"UNIQUE KEY", "UNIQUE INDEX", "UNIQUE ",
 		"FOREIGN KEY",
 		"CHECK",
 	} {
@@ -333,6 +339,7 @@ func classifyBodyItem(item string) bodyItemKind {
 
 	for _, prefix := range []string{
 		"KEY", "INDEX",
+		"UNIQUE KEY", "UNIQUE INDEX", "UNIQUE ",
 		"FULLTEXT KEY", "FULLTEXT INDEX", "FULLTEXT",
 		"SPATIAL KEY", "SPATIAL INDEX", "SPATIAL",
 	} {
@@ -343,3 +350,51 @@ func classifyBodyItem(item string) bodyItemKind {
 
 	return bodyItemColumn
 }
+
+// uniqueConstraint returns the UNIQUE constraint for a unique index that a
+// constraint can describe: one on plain columns with default options.
+// MySQL keeps UNIQUE constraints as unique indexes, so SHOW CREATE TABLE
+// prints both as UNIQUE KEY.
+func uniqueConstraint(idx *core.Index) *core.Constraint {
+	if !idx.Unique || idx.Type != core.IndexTypeBTree || idx.Visibility != core.IndexVisible || idx.Comment != "" {
+		return nil
+	}
+	for _, c := range idx.Columns {
+		if c.Expression != "" || c.Length > 0 || c.Order != core.SortAsc {
+			return nil
+		}
+	}
+	return &core.Constraint{Name: idx.Name, Type: core.ConstraintUnique, Columns: idx.Names()}
+}
+
+// resolveKeys completes the keys of a table after its body is parsed. The
+// columns of the primary key are marked, inline column checks that MariaDB
+// prints become CHECK constraints named as the schema parser names them,
+// and the indexes that MySQL creates for foreign keys, which carry the name
+// of the constraint, are dropped.
+func resolveKeys(table *core.Table) {
+	if pk := table.PrimaryKey(); pk != nil {
+		for _, name := range pk.Columns {
+			if col := table.FindColumn(name); col != nil {
+				col.PrimaryKey = true
+			}
+		}
+	}
+
+	for _, col := range table.Columns {
+		if col.Check == "" {
+			continue
+		}
+		table.Constraints = append(table.Constraints, &core.Constraint{
+			Name:            core.AutoGenerateConstraintName(core.ConstraintCheck, table.Name, []string{col.Name}, ""),
+			Type:            core.ConstraintCheck,
+			CheckExpression: col.Check,
+		})
+		col.Check = ""
+	}
+
+	table.Indexes = slices.DeleteFunc(table.Indexes, func(idx *core.Index) bool {
+		con := table.FindConstraint(idx.Name)
+		return con != nil && con.Type == core.ConstraintForeignKey && slices.Equal(idx.Names(), con.Columns)
+	})
+}
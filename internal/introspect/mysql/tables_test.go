@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+const postsDDL = "CREATE TABLE `posts` (\n" +
+	"  `id` int NOT NULL AUTO_INCREMENT,\n" +
+	"  `user_id` int NOT NULL,\n" +
+	"  `slug` varchar(64) NOT NULL,\n" +
+	"  `title` varchar(255) NOT NULL,\n" +
+	"  `score` int DEFAULT NULL CHECK (`score` >= 0),\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  UNIQUE KEY `uq_posts_slug` (`slug`),\n" +
+	"  UNIQUE KEY `uq_posts_title` (`title`(32)),\n" +
+	"  KEY `fk_posts_users` (`user_id`),\n" +
+	"  CONSTRAINT `fk_posts_users` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+
+func TestParseCreateTableDDLKeys(t *testing.T) {
+	t.Parallel()
+	table, err := parseCreateTableDDL(core.DialectMariaDB, "posts", postsDDL)
+	require.NoError(t, err)
+
+	assert.True(t, table.FindColumn("id").PrimaryKey)
+	assert.False(t, table.FindColumn("user_id").PrimaryKey)
+	assert.Empty(t, table.FindColumn("score").Check)
+
+	pk := table.PrimaryKey()
+	require.NotNil(t, pk)
+	assert.Empty(t, pk.Name)
+
+	uq := table.FindConstraint("uq_posts_slug")
+	require.NotNil(t, uq)
+	assert.Equal(t, core.ConstraintUnique, uq.Type)
+
+	chk := table.FindConstraint("chk_posts_score")
+	require.NotNil(t, chk)
+	assert.Equal(t, "score >= 0", chk.CheckExpression)
+
+	require.NotNil(t, table.FindConstraint("fk_posts_users"))
+	require.Len(t, table.Indexes, 1, "the prefix unique index stays an index, the foreign key index is dropped")
+	assert.Equal(t, "uq_posts_title", table.Indexes[0].Name)
+	assert.True(t, table.Indexes[0].Unique)
+}
@@ -0,0 +1,360 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenKind classifies a token of a CREATE TABLE body item or table option
+// list as printed by SHOW CREATE TABLE.
+type tokenKind int
+
+const (
+	tokenEnd    tokenKind = iota // End of input, the zero token.
+	tokenWord                    // Keyword, unquoted identifier, or number.
+	tokenIdent                   // Backtick-quoted identifier.
+	tokenString                  // Single- or double-quoted string literal.
+	tokenGroup                   // Parenthesized group, kept verbatim.
+	tokenSymbol                  // Any other character, such as '=' or ','.
+)
+
+// token is a single lexical token. text holds the unquoted identifier or the
+// unescaped string for tokenIdent and tokenString, and the verbatim source
+// otherwise. raw always holds the verbatim source, including a character
+// set introducer such as _utf8mb4 before a string.
+type token struct {
+	kind tokenKind
+	text string
+	raw  string
+}
+
+// keyword returns the upper-cased text of a word token, or "" for any other
+// kind of token.
+func (t token) keyword() string {
+	if t.kind != tokenWord {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+// tokenize splits s into tokens. Executable comments, /*!50100 ... */ in
+// MySQL and MariaDB and /*T![feature] ... */ or /*T! ... */ in TiDB, are
+// replaced by their content; other comments are dropped.
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment in %q", s)
+			}
+			inner, err := tokenize(executableComment(s[i+2 : i+2+end]))
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, inner...)
+			i += end + 4
+		default:
+			t, n, err := scanToken(s, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, t)
+			i = n
+		}
+	}
+	return tokens, nil
+}
+
+// executableComment returns the SQL inside a comment that the server
+// executes, or "" for a plain comment.
+func executableComment(body string) string {
+	switch {
+	case strings.HasPrefix(body, "!"):
+		return strings.TrimLeft(body[1:], "0123456789")
+	case strings.HasPrefix(body, "T!["):
+		if end := strings.IndexByte(body, ']'); end >= 0 {
+			return body[end+1:]
+		}
+	case strings.HasPrefix(body, "T!"):
+		return body[2:]
+	}
+	return ""
+}
+
+// scanToken scans the token that starts at s[i] and returns it with the
+// index just past it.
+func scanToken(s string, i int) (token, int, error) {
+	switch c := s[i]; {
+	case c == '`':
+		return scanIdent(s, i)
+	case c == '\'' || c == '"':
+		return scanString(s, i, i)
+	case c == '(':
+		end, err := scanGroup(s, i)
+		if err != nil {
+			return token{}, 0, err
+		}
+		return token{kind: tokenGroup, text: s[i:end], raw: s[i:end]}, end, nil
+	case isWordByte(c) && c != '.' || c == '-' && i+1 < len(s) && isDigit(s[i+1]):
+		return scanWord(s, i)
+	default:
+		return token{kind: tokenSymbol, text: s[i : i+1], raw: s[i : i+1]}, i + 1, nil
+	}
+}
+
+func scanIdent(s string, i int) (token, int, error) {
+	end, err := scanQuoted(s, i)
+	if err != nil {
+		return token{}, 0, err
+	}
+	return token{kind: tokenIdent, text: strings.ReplaceAll(s[i+1:end-1], "``", "`"), raw: s[i:end]}, end, nil
+}
+
+// scanWord scans a word. A word directly followed by a quote is a character
+// set introducer, as in _utf8mb4'abc', or the prefix of a bit or hex
+// literal, and is scanned with the string.
+func scanWord(s string, i int) (token, int, error) {
+	end := i + 1
+	for end < len(s) && isWordByte(s[end]) {
+		end++
+	}
+	if end < len(s) && s[end] == '\'' {
+		return scanString(s, i, end)
+	}
+	return token{kind: tokenWord, text: s[i:end], raw: s[i:end]}, end, nil
+}
+
+// scanString scans the string literal that starts at s[quote], with an
+// optional introducer from s[start].
+func scanString(s string, start, quote int) (token, int, error) {
+	end, err := scanQuoted(s, quote)
+	if err != nil {
+		return token{}, 0, err
+	}
+	return token{kind: tokenString, text: unescapeString(s[quote+1:end-1], s[quote]), raw: s[start:end]}, end, nil
+}
+
+// scanQuoted returns the index just past the quoted string, identifier, or
+// literal that starts at s[i]. A doubled quote character stands for itself,
+// and a backslash escapes the next character in string literals.
+func scanQuoted(s string, i int) (int, error) {
+	q := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '\\' && q != '`':
+			j++
+		case s[j] == q && j+1 < len(s) && s[j+1] == q:
+			j++
+		case s[j] == q:
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated quote in %q", s)
+}
+
+// scanGroup returns the index just past the balanced parenthesized group
+// that starts at s[i].
+func scanGroup(s string, i int) (int, error) {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\'', '"', '`':
+			end, err := scanQuoted(s, j)
+			if err != nil {
+				return 0, err
+			}
+			j = end - 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return j + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parentheses in %q", s)
+}
+
+var stringEscapes = map[byte]string{
+	'0': "\x00", 'b': "\b", 'n': "\n", 'r': "\r", 't': "\t", 'Z': "\x1a",
+	// \% and \_ keep their backslash, as in MySQL.
+	'%': `\%`, '_': `\_`,
+}
+
+// unescapeString resolves the backslash escapes and doubled quotes of the
+// body of a string literal quoted with q.
+func unescapeString(body string, q byte) string {
+	if !strings.ContainsAny(body, `\`+string(q)) {
+		return body
+	}
+	var sb strings.Builder
+	sb.Grow(len(body))
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && i+1 < len(body):
+			i++
+			if esc, ok := stringEscapes[body[i]]; ok {
+				sb.WriteString(esc)
+			} else {
+				sb.WriteByte(body[i])
+			}
+		case c == q && i+1 < len(body) && body[i+1] == q:
+			i++
+			sb.WriteByte(q)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// unquoteIdentifiers removes the backticks around identifiers in an
+// expression, leaving string literals untouched.
+func unquoteIdentifiers(expr string) string {
+	if !strings.Contains(expr, "`") {
+		return expr
+	}
+	var sb strings.Builder
+	sb.Grow(len(expr))
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		if c != '\'' && c != '"' && c != '`' {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+		end, err := scanQuoted(expr, i)
+		if err != nil {
+			sb.WriteString(expr[i:])
+			break
+		}
+		if c == '`' {
+			sb.WriteString(strings.ReplaceAll(expr[i+1:end-1], "``", "`"))
+		} else {
+			sb.WriteString(expr[i:end])
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+// groupExpression returns the expression inside a parenthesized group without
+// redundant parentheses, which MySQL adds when it stores CHECK and
+// generation expressions, and without identifier quotes.
+func groupExpression(group string) string {
+	expr := unwrapGroup(group)
+	for strings.HasPrefix(expr, "(") {
+		end, err := scanGroup(expr, 0)
+		if err != nil || end != len(expr) {
+			break
+		}
+		expr = unwrapGroup(expr)
+	}
+	return unquoteIdentifiers(expr)
+}
+
+// unwrapGroup returns the content of a parenthesized group.
+func unwrapGroup(group string) string {
+	return strings.TrimSpace(group[1 : len(group)-1])
+}
+
+// groupTokens tokenizes the content of a parenthesized group, such as the
+// values of ENUM('a','b').
+func groupTokens(group string) ([]token, error) {
+	return tokenize(unwrapGroup(group))
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// tokenReader reads a token list from front to back.
+type tokenReader struct {
+	tokens []token
+	pos    int
+}
+
+func (r *tokenReader) done() bool {
+	return r.pos >= len(r.tokens)
+}
+
+// next returns the next token, or the zero token at the end.
+func (r *tokenReader) next() token {
+	if r.done() {
+		return token{}
+	}
+	t := r.tokens[r.pos]
+	r.pos++
+	return t
+}
+
+func (r *tokenReader) peek() token {
+	if r.done() {
+		return token{}
+	}
+	return r.tokens[r.pos]
+}
+
+// accept consumes the next token if it is the given keyword.
+func (r *tokenReader) accept(keyword string) bool {
+	if r.peek().keyword() != keyword {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *tokenReader) expect(keyword string) error {
+	if t := r.next(); t.keyword() != keyword {
+		return fmt.Errorf("expected %s, got %q", keyword, t.raw)
+	}
+	return nil
+}
+
+// wordInto reads a word or string value, optionally preceded by '='.
+func (r *tokenReader) wordInto(dst *string) error {
+	r.acceptEquals()
+	t := r.next()
+	if t.kind != tokenWord && t.kind != tokenString {
+		return fmt.Errorf("expected a value, got %q", t.raw)
+	}
+	*dst = t.text
+	return nil
+}
+
+// stringInto reads a string literal, optionally preceded by '='.
+func (r *tokenReader) stringInto(dst *string) error {
+	r.acceptEquals()
+	t := r.next()
+	if t.kind != tokenString {
+		return fmt.Errorf("expected a string, got %q", t.raw)
+	}
+	*dst = t.text
+	return nil
+}
+
+func (r *tokenReader) acceptEquals() {
+	if t := r.peek(); t.kind == tokenSymbol && t.text == "=" {
+		r.pos++
+	}
+}
+
+// identifier reads a quoted or unquoted identifier.
+func (r *tokenReader) identifier() (string, error) {
+	t := r.next()
+	if t.kind != tokenIdent && t.kind != tokenWord {
+		return "", fmt.Errorf("expected an identifier, got %q", t.raw)
+	}
+	return t.text, nil
+}
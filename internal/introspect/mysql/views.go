@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// viewRow holds one row of information_schema.views.
+type viewRow struct {
+	name        string
+	definition  string
+	checkOption string
+	security    string
+}
+
+func introspectViews(ic *introspectCtx, db *core.Database) error {
+	query := `
+        SELECT TABLE_NAME, VIEW_DEFINITION, CHECK_OPTION, SECURITY_TYPE
+        FROM information_schema.views
+        WHERE TABLE_SCHEMA = DATABASE()
+        ORDER BY TABLE_NAME
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return fmt.Errorf("query views: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r viewRow
+		if err := rows.Scan(&r.name, &r.definition, &r.checkOption, &r.security); err != nil {
+			return fmt.Errorf("scan view: %w", err)
+		}
+		db.Views = append(db.Views, r.build(db.Name))
+	}
+	return rows.Err()
+}
+
+// build converts the row into a core.View. The server qualifies every name in
+// VIEW_DEFINITION with the current database, which is stripped so the
+// definition stays portable between databases. DEFINER is the MySQL default
+// and is left empty.
+func (r viewRow) build(schema string) *core.View {
+	v := &core.View{
+		Name:       r.name,
+		Definition: strings.ReplaceAll(r.definition, "`"+schema+"`.", ""),
+	}
+	if option := core.CheckOption(strings.ToUpper(r.checkOption)); option != "NONE" {
+		v.CheckOption = option
+	}
+	if security := core.ViewSecurity(strings.ToUpper(r.security)); security != core.SecurityDefiner {
+		v.Security = security
+	}
+	return v
+}
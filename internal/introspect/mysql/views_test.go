@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+)
+
+func TestParseViewRow(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		row  viewRow
+		want *core.View
+	}{
+		{
+			name: "strips schema qualifier",
+			row: viewRow{
+				name:        "active_users",
+				definition:  "select `app`.`users`.`id` AS `id` from `app`.`users` where (`app`.`users`.`active` = 1)",
+				checkOption: "NONE",
+				security:    "DEFINER",
+			},
+			want: &core.View{
+				Name:       "active_users",
+				Definition: "select `users`.`id` AS `id` from `users` where (`users`.`active` = 1)",
+			},
+		},
+		{
+			name: "check option and invoker",
+			row: viewRow{
+				name:        "big_orders",
+				definition:  "select `app`.`orders`.`id` AS `id` from `app`.`orders`",
+				checkOption: "CASCADED",
+				security:    "INVOKER",
+			},
+			want: &core.View{
+				Name:        "big_orders",
+				Definition:  "select `orders`.`id` AS `id` from `orders`",
+				CheckOption: core.CheckOptionCascaded,
+				Security:    core.SecurityInvoker,
+			},
+		},
+		{
+			name: "other schema kept",
+			row: viewRow{
+				name:        "audit",
+				definition:  "select `log`.`events`.`id` AS `id` from `log`.`events`",
+				checkOption: "LOCAL",
+				security:    "DEFINER",
+			},
+			want: &core.View{
+				Name:        "audit",
+				Definition:  "select `log`.`events`.`id` AS `id` from `log`.`events`",
+				CheckOption: core.CheckOptionLocal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.row.build("app"))
+		})
+	}
+}
+
+func TestParseViewRowMatchesDeclared(t *testing.T) {
+	t.Parallel()
+	row := viewRow{
+		name:        "big_orders",
+		definition:  "select `app`.`orders`.`id` AS `id`,`app`.`orders`.`total` AS `total` from `app`.`orders` where (`app`.`orders`.`total` > 100)",
+		checkOption: "NONE",
+		security:    "DEFINER",
+	}
+	declared := &core.View{Name: "big_orders", Definition: "SELECT id, total FROM orders WHERE total > 100;"}
+
+	assert.Empty(t, diff.View(row.build("app"), declared))
+}
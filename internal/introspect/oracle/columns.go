@@ -0,0 +1,323 @@
+package oracle
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+	"smf/internal/introspect"
+)
+
+// portableTypes maps the types printed by formatType to the portable type
+// they are written as, and whether the portable type takes arguments. The
+// entries follow the types the generator declares; NUMBER(1) columns only
+// become booleans with the CHECK constraint that goes with them. Every
+// other type is kept as a raw type.
+var portableTypes = map[string]struct {
+	name     string
+	takesArg bool
+}{
+	"VARCHAR2":                     {"varchar", true},
+	"CHAR":                         {"char", true},
+	"CLOB":                         {"text", false},
+	"NUMBER(3)":                    {"tinyint", false},
+	"NUMBER(5)":                    {"smallint", false},
+	"NUMBER(10)":                   {"int", false},
+	"NUMBER(19)":                   {"bigint", false},
+	"NUMBER":                       {"decimal", true},
+	"BINARY_FLOAT":                 {"float", false},
+	"BINARY_DOUBLE":                {"double", false},
+	"BOOLEAN":                      {"boolean", false},
+	"DATE":                         {"date", false},
+	"INTERVAL DAY(0) TO SECOND(6)": {"time", false},
+	"TIMESTAMP(6) WITH TIME ZONE":  {"timestamp", false},
+	"TIMESTAMP(6)":                 {"datetime", false},
+	"JSON":                         {"json", false},
+	"RAW(16)":                      {"uuid", false},
+	"RAW":                          {"varbinary", true},
+	"BLOB":                         {"blob", false},
+}
+
+// encryptionAlgorithms maps all_encrypted_columns.encryption_alg to the
+// algorithm name of the ENCRYPT USING clause. AES192 is the default and is
+// left unset.
+var encryptionAlgorithms = map[string]string{
+	"AES 128 bits key":              "AES128",
+	"AES 192 bits key":              "",
+	"AES 256 bits key":              "AES256",
+	"3 Key Triple DES 168 bits key": "3DES168",
+}
+
+var (
+	stringLiteralRe = regexp.MustCompile(`^'((?:[^']|'')*)'$`)
+	nextvalRe       = regexp.MustCompile(`(?i)^((?:"[^"]+"|[A-Za-z][\w$#]*)(?:\.(?:"[^"]+"|[A-Za-z][\w$#]*))?)\.nextval$`)
+)
+
+// columnRow holds a row of the columns query.
+type columnRow struct {
+	table           string
+	col             *core.Column
+	dataType        string
+	dataLength      int
+	precision       sql.NullInt64
+	scale           sql.NullInt64
+	charLength      int
+	charUsed        sql.NullString
+	nullable        string
+	hidden          string
+	virtual         string
+	defaultOnNull   string
+	collation       sql.NullString
+	comment         sql.NullString
+	identity        sql.NullString
+	identityOptions sql.NullString
+	encryptionAlg   sql.NullString
+	salt            sql.NullString
+	dataDefault     sql.NullString
+}
+
+// queryColumns reads the columns of the tables, including invisible ones;
+// the hidden columns Oracle adds itself are left out. DATA_DEFAULT is a
+// LONG column: it is selected as is, last, and trimmed here, since Oracle
+// does not accept a LONG in any SQL expression.
+func queryColumns(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT c.table_name, c.column_name, c.data_type, c.data_length, c.data_precision, c.data_scale,
+            c.char_length, c.char_used, c.nullable, c.hidden_column, c.virtual_column, c.default_on_null,
+            CASE WHEN c.collation <> t.default_collation THEN c.collation END,
+            cc.comments,
+            ic.generation_type, ic.identity_options,
+            ec.encryption_alg, ec.salt,
+            c.data_default
+        FROM all_tab_cols c
+        JOIN all_tables t ON t.owner = c.owner AND t.table_name = c.table_name
+        LEFT JOIN all_col_comments cc ON cc.owner = c.owner
+            AND cc.table_name = c.table_name
+            AND cc.column_name = c.column_name
+        LEFT JOIN all_tab_identity_cols ic ON ic.owner = c.owner
+            AND ic.table_name = c.table_name
+            AND ic.column_name = c.column_name
+        LEFT JOIN all_encrypted_columns ec ON ec.owner = c.owner
+            AND ec.table_name = c.table_name
+            AND ec.column_name = c.column_name
+        WHERE ` + tableFilter + `
+            AND c.user_generated = 'YES'
+        ORDER BY c.table_name, c.internal_column_id
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := columnRow{col: &core.Column{}}
+		err := rows.Scan(&r.table, &r.col.Name, &r.dataType, &r.dataLength, &r.precision, &r.scale,
+			&r.charLength, &r.charUsed, &r.nullable, &r.hidden, &r.virtual, &r.defaultOnNull,
+			&r.collation, &r.comment, &r.identity, &r.identityOptions, &r.encryptionAlg, &r.salt, &r.dataDefault)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(r.table)
+		if err != nil {
+			return err
+		}
+		if err := r.build(); err != nil {
+			return fmt.Errorf("table %s: column %s: %w", r.table, r.col.Name, err)
+		}
+		table.Columns = append(table.Columns, r.col)
+	}
+	return rows.Err()
+}
+
+// build fills col from the rest of the row.
+func (r *columnRow) build() error {
+	col := r.col
+	col.Nullable = r.nullable == "Y"
+	col.Invisible = r.hidden == "YES"
+	col.Collate = r.collation.String
+	col.Comment = r.comment.String
+	setColumnType(col, formatType(r))
+
+	def := strings.TrimSpace(r.dataDefault.String)
+	switch {
+	case r.virtual == "YES":
+		col.IsGenerated = true
+		col.GenerationExpression = def
+		col.GenerationStorage = core.GenerationVirtual
+	case r.identity.Valid:
+		if err := r.setIdentity(); err != nil {
+			return err
+		}
+	case r.dataDefault.Valid:
+		r.setDefault(def)
+	}
+	r.setOptions()
+	return nil
+}
+
+// setIdentity marks an identity column as auto-increment. GENERATED ALWAYS
+// is the default of the schema, so only BY DEFAULT is written.
+func (r *columnRow) setIdentity() error {
+	col := r.col
+	col.AutoIncrement = true
+	if r.identity.String == "BY DEFAULT" {
+		col.IdentityGeneration = core.IdentityByDefault
+	}
+	opts := identityOptions(r.identityOptions.String)
+	var err error
+	if col.IdentitySeed, err = identityOption(opts, "START WITH"); err != nil {
+		return err
+	}
+	if col.IdentityIncrement, err = identityOption(opts, "INCREMENT BY"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// identityOption returns an option of the sequence behind an identity
+// column, or 0 when it is the default of 1.
+func identityOption(opts map[string]string, name string) (int64, error) {
+	v, ok := opts[name]
+	if !ok || v == "1" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("identity option %s: %w", name, err)
+	}
+	return n, nil
+}
+
+// identityOptions splits the options of an identity column as listed in
+// all_tab_identity_cols.
+//
+// Example input: "START WITH: 1, INCREMENT BY: 1, MAX_VALUE: 9999, CACHE_SIZE: 20".
+func identityOptions(s string) map[string]string {
+	opts := make(map[string]string)
+	for opt := range strings.SplitSeq(s, ", ") {
+		if name, value, ok := strings.Cut(opt, ": "); ok {
+			opts[name] = value
+		}
+	}
+	return opts
+}
+
+// setDefault sets the default of a column. A column that defaults to the
+// next value of a sequence is bound to that sequence.
+func (r *columnRow) setDefault(def string) {
+	if m := nextvalRe.FindStringSubmatch(def); m != nil {
+		r.col.SequenceName = strings.ReplaceAll(m[1], `"`, "")
+		return
+	}
+	r.col.DefaultValue = columnDefault(r.col, def)
+}
+
+// setOptions sets the Oracle options of the column.
+func (r *columnRow) setOptions() {
+	opts := &core.OracleColumnOptions{DefaultOnNull: r.defaultOnNull == "YES"}
+	if r.encryptionAlg.Valid {
+		opts.Encrypt = true
+		opts.EncryptionAlgorithm = r.encryptionAlg.String
+		if alg, ok := encryptionAlgorithms[r.encryptionAlg.String]; ok {
+			opts.EncryptionAlgorithm = alg
+		}
+		if r.salt.String == "NO" {
+			opts.Salt = new(false)
+		}
+	}
+	if opts.DefaultOnNull || opts.Encrypt {
+		r.col.Oracle = opts
+	}
+}
+
+// formatType returns the type of a column as it is declared from its entry
+// in all_tab_cols. The datetime and interval types already carry their
+// precision in DATA_TYPE.
+//
+// Example output: "VARCHAR2(255)", "VARCHAR2(20 CHAR)", "NUMBER(10,2)".
+func formatType(r *columnRow) string {
+	switch r.dataType {
+	case "VARCHAR2", "CHAR":
+		if r.charUsed.String == "C" {
+			return fmt.Sprintf("%s(%d CHAR)", r.dataType, r.charLength)
+		}
+		return fmt.Sprintf("%s(%d)", r.dataType, r.charLength)
+	case "NVARCHAR2", "NCHAR":
+		return fmt.Sprintf("%s(%d)", r.dataType, r.charLength)
+	case "RAW":
+		return fmt.Sprintf("RAW(%d)", r.dataLength)
+	case "FLOAT":
+		return fmt.Sprintf("FLOAT(%d)", r.precision.Int64)
+	case "NUMBER":
+		return numberType(r.precision, r.scale)
+	default:
+		return r.dataType
+	}
+}
+
+// numberType formats a NUMBER type. A NUMBER with a scale of 0 and no
+// precision is what INTEGER declares.
+func numberType(precision, scale sql.NullInt64) string {
+	switch {
+	case !precision.Valid && scale.Valid && scale.Int64 == 0:
+		return "INTEGER"
+	case !precision.Valid:
+		return "NUMBER"
+	case !scale.Valid || scale.Int64 == 0:
+		return fmt.Sprintf("NUMBER(%d)", precision.Int64)
+	default:
+		return fmt.Sprintf("NUMBER(%d,%d)", precision.Int64, scale.Int64)
+	}
+}
+
+// setColumnType sets the type of col from the type printed by formatType.
+// Types that the portable type names describe exactly are written as
+// portable types.
+func setColumnType(col *core.Column, typ string) {
+	if p, ok := portableTypes[typ]; ok && !p.takesArg {
+		col.PortableType = p.name
+		col.Type = core.NormalizeDataType(col.PortableType)
+		return
+	}
+	base, args, _ := strings.Cut(typ, "(")
+	if p, ok := portableTypes[base]; ok && p.takesArg && args != "" && !strings.HasSuffix(args, " CHAR)") {
+		col.PortableType = p.name + "(" + args
+		col.Type = core.NormalizeDataType(col.PortableType)
+		return
+	}
+	col.RawType = typ
+	col.Type = core.NormalizeDataType(typ)
+}
+
+// columnDefault returns the default value for a default expression as it
+// is stored in DATA_DEFAULT. A string literal is stored unquoted, as it is
+// written in the schema, unless it would then read as something else: a
+// number is only unquoted for numeric columns, and keywords and expressions
+// keep their quotes. Other expressions are kept as stored.
+//
+// Example input: "'active'", "0", "SYSTIMESTAMP".
+func columnDefault(col *core.Column, expr string) *string {
+	if strings.EqualFold(expr, "NULL") {
+		return nil
+	}
+	m := stringLiteralRe.FindStringSubmatch(expr)
+	if m == nil {
+		return &expr
+	}
+
+	v := strings.ReplaceAll(m[1], "''", "'")
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return &v
+	case generate.DefaultNumber:
+		if col.Type == core.DataTypeInt || col.Type == core.DataTypeFloat {
+			return &v
+		}
+	}
+	return &expr
+}
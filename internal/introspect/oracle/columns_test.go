@@ -0,0 +1,130 @@
+package oracle
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestFormatType(t *testing.T) {
+	t.Parallel()
+	valid := func(n int64) sql.NullInt64 { return sql.NullInt64{Int64: n, Valid: true} }
+	tests := []struct {
+		row  columnRow
+		want string
+	}{
+		{columnRow{dataType: "VARCHAR2", charLength: 255, charUsed: sql.NullString{String: "B", Valid: true}}, "VARCHAR2(255)"},
+		{columnRow{dataType: "VARCHAR2", charLength: 20, charUsed: sql.NullString{String: "C", Valid: true}}, "VARCHAR2(20 CHAR)"},
+		{columnRow{dataType: "NVARCHAR2", charLength: 50}, "NVARCHAR2(50)"},
+		{columnRow{dataType: "RAW", dataLength: 16}, "RAW(16)"},
+		{columnRow{dataType: "NUMBER", precision: valid(10), scale: valid(0)}, "NUMBER(10)"},
+		{columnRow{dataType: "NUMBER", precision: valid(10), scale: valid(2)}, "NUMBER(10,2)"},
+		{columnRow{dataType: "NUMBER", scale: valid(0)}, "INTEGER"},
+		{columnRow{dataType: "NUMBER"}, "NUMBER"},
+		{columnRow{dataType: "FLOAT", precision: valid(126)}, "FLOAT(126)"},
+		{columnRow{dataType: "TIMESTAMP(6) WITH TIME ZONE"}, "TIMESTAMP(6) WITH TIME ZONE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, formatType(&tt.row))
+		})
+	}
+}
+
+func TestSetColumnType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		typ  string
+		want core.Column
+	}{
+		{"VARCHAR2(255)", core.Column{Type: core.DataTypeString, PortableType: "varchar(255)"}},
+		{"VARCHAR2(20 CHAR)", core.Column{Type: core.DataTypeString, RawType: "VARCHAR2(20 CHAR)"}},
+		{"CLOB", core.Column{Type: core.DataTypeString, PortableType: "text"}},
+		{"NUMBER(10)", core.Column{Type: core.DataTypeInt, PortableType: "int"}},
+		{"NUMBER(10,2)", core.Column{Type: core.DataTypeFloat, PortableType: "decimal(10,2)"}},
+		{"NUMBER", core.Column{Type: core.DataTypeUnknown, RawType: "NUMBER"}},
+		{"TIMESTAMP(6)", core.Column{Type: core.DataTypeDatetime, PortableType: "datetime"}},
+		{"TIMESTAMP(3)", core.Column{Type: core.DataTypeDatetime, RawType: "TIMESTAMP(3)"}},
+		{"RAW(16)", core.Column{Type: core.DataTypeUUID, PortableType: "uuid"}},
+		{"RAW(32)", core.Column{Type: core.DataTypeBinary, PortableType: "varbinary(32)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			t.Parallel()
+			var col core.Column
+			setColumnType(&col, tt.typ)
+			assert.Equal(t, tt.want, col)
+		})
+	}
+}
+
+func TestColumnDefault(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		expr string
+		typ  core.DataType
+		want *string
+	}{
+		{"NULL", core.DataTypeString, nil},
+		{"'active'", core.DataTypeString, new("active")},
+		{"'it''s'", core.DataTypeString, new("it's")},
+		{"'01234'", core.DataTypeString, new("'01234'")},
+		{"'5'", core.DataTypeInt, new("5")},
+		{"-1", core.DataTypeInt, new("-1")},
+		{"SYSTIMESTAMP", core.DataTypeDatetime, new("SYSTIMESTAMP")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, columnDefault(&core.Column{Type: tt.typ}, tt.expr))
+		})
+	}
+}
+
+func TestIdentityOptions(t *testing.T) {
+	t.Parallel()
+	opts := identityOptions("START WITH: 100, INCREMENT BY: 1, MAX_VALUE: 9999999999999999999999999999, CYCLE_FLAG: N")
+	assert.Equal(t, "100", opts["START WITH"])
+	assert.Equal(t, "N", opts["CYCLE_FLAG"])
+
+	n, err := identityOption(opts, "INCREMENT BY")
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	_, err = identityOption(map[string]string{"START WITH": "x"}, "START WITH")
+	assert.Error(t, err)
+}
+
+func TestColumnRowBuild(t *testing.T) {
+	t.Parallel()
+	valid := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+
+	r := &columnRow{col: &core.Column{Name: "id"}, dataType: "NUMBER", precision: sql.NullInt64{Int64: 19, Valid: true},
+		nullable: "N", identity: valid("BY DEFAULT"), identityOptions: valid("START WITH: 100, INCREMENT BY: 1"),
+		defaultOnNull: "YES", dataDefault: valid(`"SMF"."ISEQ$$_73412".nextval`)}
+	require.NoError(t, r.build())
+	assert.Equal(t, &core.Column{Name: "id", Type: core.DataTypeInt, PortableType: "bigint", AutoIncrement: true,
+		IdentityGeneration: core.IdentityByDefault, IdentitySeed: 100,
+		Oracle: &core.OracleColumnOptions{DefaultOnNull: true}}, r.col)
+
+	r = &columnRow{col: &core.Column{Name: "order_no"}, dataType: "NUMBER", precision: sql.NullInt64{Int64: 10, Valid: true},
+		nullable: "Y", hidden: "YES", dataDefault: valid(`"orders_seq".NEXTVAL `)}
+	require.NoError(t, r.build())
+	assert.Equal(t, &core.Column{Name: "order_no", Type: core.DataTypeInt, PortableType: "int", Nullable: true,
+		Invisible: true, SequenceName: "orders_seq"}, r.col)
+
+	r = &columnRow{col: &core.Column{Name: "total"}, dataType: "BINARY_DOUBLE", nullable: "Y", virtual: "YES",
+		dataDefault: valid(`"PRICE"*2`)}
+	require.NoError(t, r.build())
+	assert.Equal(t, &core.Column{Name: "total", Type: core.DataTypeFloat, PortableType: "double", Nullable: true,
+		IsGenerated: true, GenerationExpression: `"PRICE"*2`, GenerationStorage: core.GenerationVirtual}, r.col)
+
+	r = &columnRow{col: &core.Column{Name: "ssn"}, dataType: "VARCHAR2", charLength: 11, nullable: "Y",
+		encryptionAlg: valid("AES 256 bits key"), salt: valid("NO")}
+	require.NoError(t, r.build())
+	assert.Equal(t, &core.OracleColumnOptions{Encrypt: true, EncryptionAlgorithm: "AES256", Salt: new(false)}, r.col.Oracle)
+}
@@ -0,0 +1,241 @@
+package oracle
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// referentialActions maps all_constraints.delete_rule to the action it
+// stands for. NO ACTION is the default and is left unset.
+var referentialActions = map[string]core.ReferentialAction{
+	"NO ACTION": core.RefActionNone,
+	"CASCADE":   core.RefActionCascade,
+	"SET NULL":  core.RefActionSetNull,
+}
+
+// constraintTypes maps all_constraints.constraint_type to the constraint
+// type it stands for.
+var constraintTypes = map[string]core.ConstraintType{
+	"P": core.ConstraintPrimaryKey,
+	"U": core.ConstraintUnique,
+	"R": core.ConstraintForeignKey,
+	"C": core.ConstraintCheck,
+}
+
+// inListRe matches the CHECK constraint the generator declares for an enum
+// or boolean column: `"status" IN ('new', 'paid')`.
+var inListRe = regexp.MustCompile(`^"((?:[^"]|"")+)" IN \((.*)\)$`)
+
+// listItemRe matches an item of the list of such a constraint, and the
+// comma that follows it.
+var listItemRe = regexp.MustCompile(`^\s*('(?:[^']|'')*'|-?\d+)\s*(,|$)`)
+
+// constraintRow holds a row of the constraints query.
+type constraintRow struct {
+	table      string
+	con        *core.Constraint
+	typ        string
+	columns    sql.NullString
+	refTable   sql.NullString
+	refColumns sql.NullString
+	deleteRule sql.NullString
+	status     string
+	condition  sql.NullString
+}
+
+// queryConstraints reads the primary key, unique, foreign key, and CHECK
+// constraints of the tables. SEARCH_CONDITION is a LONG column, selected as
+// is and last. The CHECK constraints behind NOT NULL columns are part of
+// the columns and are not read.
+func queryConstraints(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT c.table_name, c.constraint_name, c.constraint_type,
+            (
+                SELECT JSON_ARRAYAGG(cc.column_name ORDER BY cc.position, cc.column_name RETURNING VARCHAR2(4000))
+                FROM all_cons_columns cc
+                WHERE cc.owner = c.owner
+                    AND cc.constraint_name = c.constraint_name
+                    AND cc.table_name = c.table_name
+            ),
+            r.table_name,
+            (
+                SELECT JSON_ARRAYAGG(rc.column_name ORDER BY rc.position RETURNING VARCHAR2(4000))
+                FROM all_cons_columns rc
+                WHERE rc.owner = r.owner
+                    AND rc.constraint_name = r.constraint_name
+                    AND rc.table_name = r.table_name
+            ),
+            c.delete_rule, c.status,
+            c.search_condition
+        FROM all_constraints c
+        JOIN all_tables t ON t.owner = c.owner AND t.table_name = c.table_name
+        LEFT JOIN all_constraints r ON r.owner = c.r_owner AND r.constraint_name = c.r_constraint_name
+        WHERE ` + tableFilter + `
+            AND c.constraint_type IN ('P', 'U', 'R', 'C')
+        ORDER BY c.table_name, c.constraint_name
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := constraintRow{con: &core.Constraint{}}
+		err := rows.Scan(&r.table, &r.con.Name, &r.typ, &r.columns, &r.refTable, &r.refColumns,
+			&r.deleteRule, &r.status, &r.condition)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(r.table)
+		if err != nil {
+			return err
+		}
+		if err := r.build(); err != nil {
+			return fmt.Errorf("table %s: constraint %s: %w", r.table, r.con.Name, err)
+		}
+		if r.con.Type == core.ConstraintCheck && (notNullCheck(r.con) || setEmulatedType(table, r.con)) {
+			continue
+		}
+		table.Constraints = append(table.Constraints, r.con)
+		if r.con.Type == core.ConstraintPrimaryKey {
+			markPrimaryKey(table, r.con.Columns)
+		}
+	}
+	return rows.Err()
+}
+
+// build fills con from the rest of the row. A disabled constraint is
+// written as not enforced.
+func (r *constraintRow) build() error {
+	con := r.con
+	con.Type = constraintTypes[r.typ]
+	if err := jsonList(r.columns, &con.Columns); err != nil {
+		return fmt.Errorf("columns: %w", err)
+	}
+
+	switch con.Type {
+	case core.ConstraintForeignKey:
+		con.ReferencedTable = r.refTable.String
+		if err := jsonList(r.refColumns, &con.ReferencedColumns); err != nil {
+			return fmt.Errorf("referenced columns: %w", err)
+		}
+		con.OnDelete = referentialActions[r.deleteRule.String]
+	case core.ConstraintCheck:
+		con.CheckExpression = strings.TrimSpace(r.condition.String)
+	}
+	if r.status == "DISABLED" {
+		con.Enforced = new(false)
+	}
+	return nil
+}
+
+// jsonList decodes a JSON array of names, which JSON_ARRAYAGG returns as
+// NULL when there are none.
+func jsonList(s sql.NullString, names *[]string) error {
+	if !s.Valid {
+		return nil
+	}
+	return json.Unmarshal([]byte(s.String), names)
+}
+
+func markPrimaryKey(table *core.Table, columns []string) {
+	for _, name := range columns {
+		if col := table.FindColumn(name); col != nil {
+			col.PrimaryKey = true
+		}
+	}
+}
+
+// notNullCheck reports whether con is the CHECK constraint Oracle keeps
+// for a NOT NULL column.
+func notNullCheck(con *core.Constraint) bool {
+	return len(con.Columns) == 1 && con.CheckExpression == `"`+strings.ReplaceAll(con.Columns[0], `"`, `""`)+`" IS NOT NULL`
+}
+
+// setEmulatedType makes a column an enum or a boolean when con is the CHECK
+// constraint that the generator declares for it, named
+// chk_{table}_{column}_enum or chk_{table}_{column}_bool in any case.
+func setEmulatedType(table *core.Table, con *core.Constraint) bool {
+	if len(con.Columns) != 1 {
+		return false
+	}
+	col := table.FindColumn(con.Columns[0])
+	if col == nil {
+		return false
+	}
+	prefix := "chk_" + table.Name + "_" + col.Name
+	switch strings.ToLower(con.Name) {
+	case strings.ToLower(prefix + "_enum"):
+		return setEnum(col, con.CheckExpression)
+	case strings.ToLower(prefix + "_bool"):
+		return setBool(col, con.CheckExpression)
+	default:
+		return false
+	}
+}
+
+// setEnum makes a character column an enum when check is
+// `"column" IN ('a', ...)`.
+func setEnum(col *core.Column, check string) bool {
+	values, ok := inList(col.Name, check)
+	if !ok || col.Type != core.DataTypeString {
+		return false
+	}
+	for i, v := range values {
+		if !strings.HasPrefix(v, "'") {
+			return false
+		}
+		values[i] = strings.ReplaceAll(v[1:len(v)-1], "''", "'")
+	}
+	col.Type = core.DataTypeEnum
+	col.EnumValues = values
+	col.PortableType = core.BuildEnumTypeRaw(values)
+	col.RawType = ""
+	return true
+}
+
+// setBool makes a NUMBER(1) column a boolean when check is
+// `"column" IN (0, 1)`. Its default of 0 or 1 becomes FALSE or TRUE.
+func setBool(col *core.Column, check string) bool {
+	values, ok := inList(col.Name, check)
+	if !ok || col.PortableType != "decimal(1)" || strings.Join(values, ",") != "0,1" {
+		return false
+	}
+	col.Type = core.DataTypeBoolean
+	col.PortableType = "boolean"
+	if v := col.DefaultValue; v != nil && (*v == "0" || *v == "1") {
+		col.DefaultValue = new(strings.ToUpper(strconv.FormatBool(*v == "1")))
+	}
+	return true
+}
+
+// inList returns the items of a check of the form `"column" IN (...)` on
+// the given column, as they are written.
+func inList(column, check string) ([]string, bool) {
+	m := inListRe.FindStringSubmatch(check)
+	if m == nil || strings.ReplaceAll(m[1], `""`, `"`) != column {
+		return nil, false
+	}
+	var items []string
+	rest := m[2]
+	for rest != "" {
+		item := listItemRe.FindStringSubmatch(rest)
+		if item == nil {
+			return nil, false
+		}
+		items = append(items, item[1])
+		rest = rest[len(item[0]):]
+		if item[2] == "," && rest == "" {
+			return nil, false
+		}
+	}
+	return items, len(items) > 0
+}
@@ -0,0 +1,92 @@
+package oracle
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestConstraintRowBuild(t *testing.T) {
+	t.Parallel()
+	r := constraintRow{
+		con:        &core.Constraint{Name: "fk_orders_users"},
+		typ:        "R",
+		columns:    sql.NullString{String: `["user_id"]`, Valid: true},
+		refTable:   sql.NullString{String: "users", Valid: true},
+		refColumns: sql.NullString{String: `["id"]`, Valid: true},
+		deleteRule: sql.NullString{String: "CASCADE", Valid: true},
+		status:     "DISABLED",
+	}
+	require.NoError(t, r.build())
+	assert.Equal(t, &core.Constraint{
+		Name:              "fk_orders_users",
+		Type:              core.ConstraintForeignKey,
+		Columns:           []string{"user_id"},
+		ReferencedTable:   "users",
+		ReferencedColumns: []string{"id"},
+		OnDelete:          core.RefActionCascade,
+		Enforced:          new(false),
+	}, r.con)
+
+	r = constraintRow{
+		con:       &core.Constraint{Name: "SYS_C008123"},
+		typ:       "C",
+		columns:   sql.NullString{String: `["name"]`, Valid: true},
+		status:    "ENABLED",
+		condition: sql.NullString{String: `"name" IS NOT NULL`, Valid: true},
+	}
+	require.NoError(t, r.build())
+	assert.True(t, notNullCheck(r.con))
+}
+
+func TestSetEmulatedType(t *testing.T) {
+	t.Parallel()
+	table := &core.Table{Name: "orders", Columns: []*core.Column{
+		{Name: "status", Type: core.DataTypeString, PortableType: "varchar(4)"},
+		{Name: "paid", Type: core.DataTypeFloat, PortableType: "decimal(1)", DefaultValue: new("0")},
+		{Name: "qty", Type: core.DataTypeInt, PortableType: "int"},
+	}}
+	enum := &core.Constraint{Name: "CHK_ORDERS_STATUS_ENUM", Type: core.ConstraintCheck, Columns: []string{"status"},
+		CheckExpression: `"status" IN ('new', 'it''s')`}
+	assert.True(t, setEmulatedType(table, enum))
+	assert.Equal(t, &core.Column{Name: "status", Type: core.DataTypeEnum, PortableType: "enum('new','it''s')",
+		EnumValues: []string{"new", "it's"}}, table.Columns[0])
+
+	boolean := &core.Constraint{Name: "chk_orders_paid_bool", Type: core.ConstraintCheck, Columns: []string{"paid"},
+		CheckExpression: `"paid" IN (0, 1)`}
+	assert.True(t, setEmulatedType(table, boolean))
+	assert.Equal(t, &core.Column{Name: "paid", Type: core.DataTypeBoolean, PortableType: "boolean",
+		DefaultValue: new("FALSE")}, table.Columns[1])
+
+	check := &core.Constraint{Name: "chk_orders_qty", Type: core.ConstraintCheck, Columns: []string{"qty"},
+		CheckExpression: `"qty" IN (1, 2)`}
+	assert.False(t, setEmulatedType(table, check))
+}
+
+func TestInList(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		check string
+		want  []string
+	}{
+		{`"status" IN ('new', 'paid')`, []string{"'new'", "'paid'"}},
+		{`"status" IN ('a, b','c')`, []string{"'a, b'", "'c'"}},
+		{`"status" IN (0, 1)`, []string{"0", "1"}},
+		{`"status" IN ('new',)`, nil},
+		{`"status" IN (upper('new'))`, nil},
+		{`"kind" IN ('new')`, nil},
+		{`"status" = 'new'`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.check, func(t *testing.T) {
+			t.Parallel()
+			got, ok := inList("status", tt.check)
+			assert.Equal(t, tt.want != nil, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
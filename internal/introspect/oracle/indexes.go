@@ -0,0 +1,147 @@
+package oracle
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// indexTypes maps all_indexes.index_type to the index type it stands for.
+// Function-based indexes, which descending key columns also make, are
+// B-trees with expressions. Oracle Text indexes are domain indexes of the
+// CONTEXT type. Bitmap, reverse key, and other domain indexes have no
+// counterpart in the schema and are not read.
+var indexTypes = map[string]core.IndexType{
+	"NORMAL":                core.IndexTypeBTree,
+	"FUNCTION-BASED NORMAL": core.IndexTypeBTree,
+	"DOMAIN":                core.IndexTypeFullText,
+}
+
+// quotedNameRe matches an expression that is only a quoted column name, as
+// Oracle stores the key column of a descending index.
+var quotedNameRe = regexp.MustCompile(`^"((?:[^"]|"")+)"$`)
+
+// indexKey identifies an index among the indexes of the tables.
+type indexKey struct {
+	table string
+	index string
+}
+
+// queryIndexes reads the indexes of the tables and then their key columns.
+// The indexes that back a primary key or unique constraint are part of the
+// constraint and are not read. Oracle has no index comments or partial
+// indexes.
+func queryIndexes(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT i.table_name, i.index_name, i.index_type, i.uniqueness, i.visibility, i.ityp_name
+        FROM all_indexes i
+        JOIN all_tables t ON t.owner = i.table_owner AND t.table_name = i.table_name
+        WHERE ` + tableFilter + `
+            AND i.generated = 'N'
+            AND NOT EXISTS (
+                SELECT 1
+                FROM all_constraints c
+                WHERE c.owner = i.table_owner
+                    AND c.table_name = i.table_name
+                    AND c.index_owner = i.owner
+                    AND c.index_name = i.index_name
+                    AND c.constraint_type IN ('P', 'U')
+            )
+        ORDER BY i.table_name, i.index_name
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	indexes := make(map[indexKey]*core.Index)
+	for rows.Next() {
+		idx := &core.Index{}
+		var tableName, indexType, uniqueness, visibility string
+		var itypName sql.NullString
+		err := rows.Scan(&tableName, &idx.Name, &indexType, &uniqueness, &visibility, &itypName)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(tableName)
+		if err != nil {
+			return err
+		}
+		typ, ok := indexTypes[indexType]
+		if !ok || (typ == core.IndexTypeFullText && itypName.String != "CONTEXT") {
+			continue
+		}
+		idx.Type = typ
+		idx.Unique = uniqueness == "UNIQUE"
+		idx.Visibility = core.IndexVisible
+		if visibility == "INVISIBLE" {
+			idx.Visibility = core.IndexInvisible
+		}
+		table.Indexes = append(table.Indexes, idx)
+		indexes[indexKey{tableName, idx.Name}] = idx
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return queryIndexColumns(ic, indexes)
+}
+
+// queryIndexColumns adds the key columns to the indexes. The expressions of
+// function-based indexes come from COLUMN_EXPRESSION, a LONG column that is
+// selected as is and last.
+func queryIndexColumns(ic *introspectCtx, indexes map[indexKey]*core.Index) error {
+	query := `
+        SELECT c.table_name, c.index_name, c.column_name, c.descend, e.column_expression
+        FROM all_ind_columns c
+        JOIN all_tables t ON t.owner = c.table_owner AND t.table_name = c.table_name
+        LEFT JOIN all_ind_expressions e ON e.index_owner = c.index_owner
+            AND e.index_name = c.index_name
+            AND e.column_position = c.column_position
+        WHERE ` + tableFilter + `
+        ORDER BY c.table_name, c.index_name, c.column_position
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, indexName, columnName, descend string
+		var expression sql.NullString
+		if err := rows.Scan(&tableName, &indexName, &columnName, &descend, &expression); err != nil {
+			return err
+		}
+		idx, ok := indexes[indexKey{tableName, indexName}]
+		if !ok {
+			continue
+		}
+		idx.Columns = append(idx.Columns, indexColumn(columnName, descend, expression))
+	}
+	return rows.Err()
+}
+
+// indexColumn returns a key column of an index. Oracle stores a descending
+// key column as an expression that names the column.
+func indexColumn(name, descend string, expression sql.NullString) core.ColumnIndex {
+	col := core.ColumnIndex{Name: name, Order: core.SortAsc}
+	if descend == "DESC" {
+		col.Order = core.SortDesc
+	}
+	if !expression.Valid {
+		return col
+	}
+	expr := strings.TrimSpace(expression.String)
+	if m := quotedNameRe.FindStringSubmatch(expr); m != nil {
+		col.Name = strings.ReplaceAll(m[1], `""`, `"`)
+		return col
+	}
+	col.Name = ""
+	col.Expression = expr
+	return col
+}
@@ -0,0 +1,32 @@
+package oracle
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestIndexColumn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		descend    string
+		expression sql.NullString
+		want       core.ColumnIndex
+	}{
+		{"email", "ASC", sql.NullString{}, core.ColumnIndex{Name: "email", Order: core.SortAsc}},
+		{"SYS_NC00004$", "DESC", sql.NullString{String: `"created_at"`, Valid: true},
+			core.ColumnIndex{Name: "created_at", Order: core.SortDesc}},
+		{"SYS_NC00005$", "ASC", sql.NullString{String: `LOWER("email") `, Valid: true},
+			core.ColumnIndex{Expression: `LOWER("email")`, Order: core.SortAsc}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, indexColumn(tt.name, tt.descend, tt.expression))
+		})
+	}
+}
@@ -1,3 +1,6 @@
+// Package oracle contains introspect implementation for Oracle Database. It reads the
+// tables and views owned by the current schema, the connected user unless the session
+// sets CURRENT_SCHEMA, from the ALL_* data dictionary views with a few bulk queries.
 package oracle
 
 import (
@@ -14,10 +17,46 @@ func init() {
 
 type introspecter struct{}
 
+type introspectCtx struct {
+	db  *sql.DB
+	ctx context.Context
+
+	// tablespace is the default tablespace of the connected user. Tables
+	// stored in it are written without a tablespace.
+	tablespace string
+}
+
 func New() introspect.Introspecter {
 	return &introspecter{}
 }
 
-func (i *introspecter) Introspect(_ context.Context, _ *sql.DB) (*core.Database, error) {
-	return nil, nil
+func (i *introspecter) Introspect(ctx context.Context, db *sql.DB) (*core.Database, error) {
+	d := &core.Database{Dialect: core.DialectOracle}
+	ic := &introspectCtx{
+		db:  db,
+		ctx: ctx,
+	}
+
+	query := `
+        SELECT SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA'),
+            (SELECT default_tablespace FROM user_users)
+        FROM dual
+    `
+	err := db.QueryRowContext(ctx, query).Scan(&d.Name, &ic.tablespace)
+	if err != nil {
+		return nil, err
+	}
+
+	err = introspectTables(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = introspectViews(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	introspect.FoldNames(d)
+	return d, nil
 }
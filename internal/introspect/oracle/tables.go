@@ -0,0 +1,144 @@
+package oracle
+
+import (
+	"database/sql"
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// tableFilter selects the tables of the current schema from all_tables t.
+// Nested tables, the overflow segments of index-organized tables, tables
+// in the recycle bin, temporary tables, and the container tables of
+// materialized views are left out.
+const tableFilter = `t.owner = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+        AND t.nested = 'NO'
+        AND t.secondary = 'N'
+        AND t.dropped = 'NO'
+        AND t.temporary = 'N'
+        AND (t.iot_type IS NULL OR t.iot_type = 'IOT')
+        AND NOT EXISTS (
+            SELECT 1
+            FROM all_mviews m
+            WHERE m.owner = t.owner
+                AND m.mview_name = t.table_name
+        )`
+
+// Defaults of the segment attributes. Attributes that keep them are left
+// unset.
+const (
+	defaultPctfree   = 10
+	defaultPctused   = 40
+	defaultInitTrans = 1
+)
+
+func introspectTables(ic *introspectCtx, db *core.Database) error {
+	set, err := queryTables(ic)
+	if err != nil {
+		return fmt.Errorf("query tables: %w", err)
+	}
+	if len(set.Tables) == 0 {
+		return nil
+	}
+
+	if err := queryColumns(ic, set); err != nil {
+		return fmt.Errorf("query columns: %w", err)
+	}
+	if err := queryConstraints(ic, set); err != nil {
+		return fmt.Errorf("query constraints: %w", err)
+	}
+	if err := queryIndexes(ic, set); err != nil {
+		return fmt.Errorf("query indexes: %w", err)
+	}
+	if err := queryOnUpdateTriggers(ic, set); err != nil {
+		return fmt.Errorf("query triggers: %w", err)
+	}
+
+	db.Tables = append(db.Tables, set.Tables...)
+	return nil
+}
+
+// tableRow holds a row of the tables query. The segment attributes are
+// NULL for index-organized and partitioned tables, whose segments belong
+// to the index or the partitions.
+type tableRow struct {
+	table      *core.Table
+	iotType    sql.NullString
+	logging    sql.NullString
+	pctfree    sql.NullInt64
+	pctused    sql.NullInt64
+	initTrans  sql.NullInt64
+	tablespace sql.NullString
+	comment    sql.NullString
+}
+
+func queryTables(ic *introspectCtx) (*introspect.TableSet, error) {
+	query := `
+        SELECT t.table_name, t.iot_type, t.logging, t.pct_free, t.pct_used, t.ini_trans,
+            t.tablespace_name, tc.comments
+        FROM all_tables t
+        LEFT JOIN all_tab_comments tc ON tc.owner = t.owner AND tc.table_name = t.table_name
+        WHERE ` + tableFilter + `
+        ORDER BY t.table_name
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set := introspect.NewTableSet()
+	for rows.Next() {
+		r := tableRow{table: &core.Table{
+			Columns:     make([]*core.Column, 0),
+			Constraints: make([]*core.Constraint, 0),
+			Indexes:     make([]*core.Index, 0),
+		}}
+		err := rows.Scan(&r.table.Name, &r.iotType, &r.logging, &r.pctfree, &r.pctused, &r.initTrans,
+			&r.tablespace, &r.comment)
+		if err != nil {
+			return nil, err
+		}
+		r.build(ic.tablespace)
+		set.Add(r.table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// build sets the comment, tablespace, and Oracle options of the table.
+// Segment attributes are only written when they differ from the defaults,
+// and the tablespace when it is not the default tablespace of the user.
+// Whether the segment has been created yet depends on the rows, not on the
+// schema, and is not read.
+func (r *tableRow) build(defaultTablespace string) {
+	t := r.table
+	t.Comment = r.comment.String
+	if r.tablespace.String != defaultTablespace {
+		t.Options.Tablespace = r.tablespace.String
+	}
+
+	opts := &core.OracleTableOptions{}
+	if r.iotType.String == "IOT" {
+		opts.Organization = "INDEX"
+	}
+	if r.logging.String == "NO" {
+		opts.Logging = new(false)
+	}
+	if r.pctfree.Valid && r.pctfree.Int64 != defaultPctfree {
+		opts.Pctfree = int(r.pctfree.Int64)
+	}
+	if r.pctused.Valid && r.pctused.Int64 != defaultPctused {
+		opts.Pctused = int(r.pctused.Int64)
+	}
+	if r.initTrans.Valid && r.initTrans.Int64 != defaultInitTrans {
+		opts.InitTrans = int(r.initTrans.Int64)
+	}
+	if opts.Organization != "" || opts.Logging != nil || opts.Pctfree != 0 || opts.Pctused != 0 || opts.InitTrans != 0 {
+		t.Options.Oracle = opts
+	}
+}
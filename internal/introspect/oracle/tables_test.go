@@ -0,0 +1,38 @@
+package oracle
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestTableRowBuild(t *testing.T) {
+	t.Parallel()
+	r := tableRow{
+		table:      &core.Table{Name: "orders"},
+		logging:    sql.NullString{String: "YES", Valid: true},
+		pctfree:    sql.NullInt64{Int64: 10, Valid: true},
+		initTrans:  sql.NullInt64{Int64: 1, Valid: true},
+		tablespace: sql.NullString{String: "USERS", Valid: true},
+		comment:    sql.NullString{String: "Customer orders", Valid: true},
+	}
+	r.build("USERS")
+	assert.Equal(t, &core.Table{Name: "orders", Comment: "Customer orders"}, r.table)
+
+	r = tableRow{
+		table:      &core.Table{Name: "events"},
+		iotType:    sql.NullString{String: "IOT", Valid: true},
+		logging:    sql.NullString{String: "NO", Valid: true},
+		pctfree:    sql.NullInt64{Int64: 20, Valid: true},
+		pctused:    sql.NullInt64{Int64: 60, Valid: true},
+		initTrans:  sql.NullInt64{Int64: 4, Valid: true},
+		tablespace: sql.NullString{String: "HISTORY", Valid: true},
+	}
+	r.build("USERS")
+	assert.Equal(t, "HISTORY", r.table.Options.Tablespace)
+	assert.Equal(t, &core.OracleTableOptions{Organization: "INDEX", Logging: new(false), Pctfree: 20, Pctused: 60, InitTrans: 4},
+		r.table.Options.Oracle)
+}
@@ -0,0 +1,64 @@
+package oracle
+
+import (
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// Oracle has no ON UPDATE column clause, and the generator emulates it with
+// a BEFORE UPDATE trigger named {table}_{column}_on_update. The value such
+// a trigger assigns is read back as the ON UPDATE value of the column;
+// other triggers are not read.
+
+// onUpdateSetRe matches the assignment in the body of an ON UPDATE trigger.
+var onUpdateSetRe = regexp.MustCompile(`(?s):NEW\."((?:[^"]|"")+)" := (.+?);`)
+
+// queryOnUpdateTriggers reads the ON UPDATE triggers of the tables.
+// TRIGGER_BODY is a LONG column, selected as is and last.
+func queryOnUpdateTriggers(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT tr.table_name, tr.trigger_name, tr.trigger_body
+        FROM all_triggers tr
+        JOIN all_tables t ON t.owner = tr.table_owner AND t.table_name = tr.table_name
+        WHERE ` + tableFilter + `
+            AND tr.base_object_type = 'TABLE'
+            AND LOWER(tr.trigger_name) LIKE '%\_on\_update' ESCAPE '\'
+        ORDER BY tr.table_name, tr.trigger_name
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, name, body string
+		if err := rows.Scan(&tableName, &name, &body); err != nil {
+			return err
+		}
+		table, err := set.Get(tableName)
+		if err != nil {
+			return err
+		}
+		setOnUpdate(table, name, body)
+	}
+	return rows.Err()
+}
+
+// setOnUpdate sets the ON UPDATE value of the column a trigger emulates it
+// for, when the trigger is named and written as the generator writes it. The
+// name is compared ignoring case, as the generator creates it in upper case.
+func setOnUpdate(table *core.Table, name, body string) {
+	m := onUpdateSetRe.FindStringSubmatch(body)
+	if m == nil {
+		return
+	}
+	col := table.FindColumn(strings.ReplaceAll(m[1], `""`, `"`))
+	if col == nil || !strings.EqualFold(name, table.Name+"_"+col.Name+"_on_update") {
+		return
+	}
+	col.OnUpdate = new(strings.TrimSpace(m[2]))
+}
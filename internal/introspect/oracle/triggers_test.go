@@ -0,0 +1,24 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestSetOnUpdate(t *testing.T) {
+	t.Parallel()
+	const body = `BEGIN
+  IF NOT UPDATING('"UPDATED_AT"') THEN
+    :NEW."UPDATED_AT" := SYSTIMESTAMP;
+  END IF;
+END;`
+	table := &core.Table{Name: "ORDERS", Columns: []*core.Column{{Name: "ID"}, {Name: "UPDATED_AT"}}}
+	setOnUpdate(table, "ORDERS_TOUCHED_ON_UPDATE", body)
+	assert.Nil(t, table.Columns[1].OnUpdate)
+
+	setOnUpdate(table, "ORDERS_UPDATED_AT_ON_UPDATE", body)
+	assert.Equal(t, new("SYSTIMESTAMP"), table.Columns[1].OnUpdate)
+}
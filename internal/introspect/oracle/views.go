@@ -0,0 +1,116 @@
+package oracle
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// viewRow holds a row of the views or the materialized views query. The
+// query text is a LONG column, so the two are read with separate queries.
+type viewRow struct {
+	name         string
+	text         string
+	materialized bool
+	bequeath     sql.NullString
+	checkOption  bool
+	refreshMode  sql.NullString
+	comment      sql.NullString
+}
+
+func introspectViews(ic *introspectCtx, db *core.Database) error {
+	views, err := queryViews(ic)
+	if err != nil {
+		return fmt.Errorf("query views: %w", err)
+	}
+	mviews, err := queryMaterializedViews(ic)
+	if err != nil {
+		return fmt.Errorf("query materialized views: %w", err)
+	}
+	db.Views = append(db.Views, views...)
+	db.Views = append(db.Views, mviews...)
+	return nil
+}
+
+func queryViews(ic *introspectCtx) ([]*core.View, error) {
+	query := `
+        SELECT v.view_name, v.text, v.bequeath,
+            CASE WHEN EXISTS (
+                SELECT 1
+                FROM all_constraints c
+                WHERE c.owner = v.owner
+                    AND c.table_name = v.view_name
+                    AND c.constraint_type = 'V'
+            ) THEN 1 ELSE 0 END,
+            tc.comments
+        FROM all_views v
+        LEFT JOIN all_tab_comments tc ON tc.owner = v.owner AND tc.table_name = v.view_name
+        WHERE v.owner = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+        ORDER BY v.view_name
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []*core.View
+	for rows.Next() {
+		var r viewRow
+		if err := rows.Scan(&r.name, &r.text, &r.bequeath, &r.checkOption, &r.comment); err != nil {
+			return nil, err
+		}
+		views = append(views, r.build())
+	}
+	return views, rows.Err()
+}
+
+func queryMaterializedViews(ic *introspectCtx) ([]*core.View, error) {
+	query := `
+        SELECT m.mview_name, m.query, m.refresh_mode, mc.comments
+        FROM all_mviews m
+        LEFT JOIN all_mview_comments mc ON mc.owner = m.owner AND mc.mview_name = m.mview_name
+        WHERE m.owner = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+        ORDER BY m.mview_name
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []*core.View
+	for rows.Next() {
+		r := viewRow{materialized: true}
+		if err := rows.Scan(&r.name, &r.text, &r.refreshMode, &r.comment); err != nil {
+			return nil, err
+		}
+		views = append(views, r.build())
+	}
+	return views, rows.Err()
+}
+
+// build converts the row into a core.View. Oracle has a single check
+// option, which is written as CASCADED. BEQUEATH CURRENT_USER is the
+// counterpart of SQL SECURITY INVOKER, and ON DEMAND refresh is the default,
+// which is left unset.
+func (r viewRow) build() *core.View {
+	v := &core.View{
+		Name:         r.name,
+		Definition:   strings.TrimSpace(r.text),
+		Materialized: r.materialized,
+		Comment:      r.comment.String,
+	}
+	if r.checkOption {
+		v.CheckOption = core.CheckOptionCascaded
+	}
+	if r.bequeath.String == "CURRENT_USER" {
+		v.Security = core.SecurityInvoker
+	}
+	if r.refreshMode.String == "COMMIT" {
+		v.Refresh = core.RefreshOnCommit
+	}
+	return v
+}
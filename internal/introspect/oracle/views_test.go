@@ -0,0 +1,77 @@
+package oracle
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestViewRowBuild(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		row  viewRow
+		want *core.View
+	}{
+		{
+			name: "definer view",
+			row: viewRow{
+				name:     "ACTIVE_USERS",
+				text:     "SELECT id FROM users WHERE active = 1 ",
+				bequeath: sql.NullString{String: "DEFINER", Valid: true},
+			},
+			want: &core.View{Name: "ACTIVE_USERS", Definition: "SELECT id FROM users WHERE active = 1"},
+		},
+		{
+			name: "check option, invoker, and comment",
+			row: viewRow{
+				name:        "BIG_ORDERS",
+				text:        "SELECT id FROM orders WHERE total > 100",
+				bequeath:    sql.NullString{String: "CURRENT_USER", Valid: true},
+				checkOption: true,
+				comment:     sql.NullString{String: "large orders", Valid: true},
+			},
+			want: &core.View{
+				Name:        "BIG_ORDERS",
+				Definition:  "SELECT id FROM orders WHERE total > 100",
+				CheckOption: core.CheckOptionCascaded,
+				Security:    core.SecurityInvoker,
+				Comment:     "large orders",
+			},
+		},
+		{
+			name: "materialized view refreshed on demand",
+			row: viewRow{
+				name:         "ORDER_STATS",
+				text:         "SELECT count(*) n FROM orders",
+				materialized: true,
+				refreshMode:  sql.NullString{String: "DEMAND", Valid: true},
+			},
+			want: &core.View{Name: "ORDER_STATS", Definition: "SELECT count(*) n FROM orders", Materialized: true},
+		},
+		{
+			name: "materialized view refreshed on commit",
+			row: viewRow{
+				name:         "ORDER_TOTALS",
+				text:         "SELECT sum(total) t FROM orders",
+				materialized: true,
+				refreshMode:  sql.NullString{String: "COMMIT", Valid: true},
+			},
+			want: &core.View{
+				Name:         "ORDER_TOTALS",
+				Definition:   "SELECT sum(total) t FROM orders",
+				Materialized: true,
+				Refresh:      core.RefreshOnCommit,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.row.build())
+		})
+	}
+}
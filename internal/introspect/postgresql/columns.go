@@ -0,0 +1,266 @@
+package postgresql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+	"smf/internal/introspect"
+)
+
+// portableTypes maps the type names printed by format_type to the portable
+// type they are written as, and whether the portable type takes arguments.
+// Every other type is kept as a raw type.
+var portableTypes = map[string]struct {
+	name     string
+	takesArg bool
+}{
+	"character varying":           {"varchar", true},
+	"character":                   {"char", true},
+	"numeric":                     {"decimal", true},
+	"text":                        {"text", false},
+	"smallint":                    {"smallint", false},
+	"integer":                     {"int", false},
+	"bigint":                      {"bigint", false},
+	"real":                        {"float", false},
+	"double precision":            {"double", false},
+	"boolean":                     {"boolean", false},
+	"date":                        {"date", false},
+	"time without time zone":      {"time", false},
+	"timestamp with time zone":    {"timestamp", false},
+	"timestamp without time zone": {"datetime", false},
+	"jsonb":                       {"json", false},
+	"uuid":                        {"uuid", false},
+	"bytea":                       {"blob", false},
+}
+
+// storageModes maps pg_attribute.attstorage to the storage mode it stands for.
+var storageModes = map[string]string{"p": "PLAIN", "m": "MAIN", "e": "EXTERNAL", "x": "EXTENDED"}
+
+// compressionMethods maps pg_attribute.attcompression to the compression
+// method it stands for.
+var compressionMethods = map[string]string{"p": "pglz", "l": "lz4"}
+
+var (
+	castLiteralRe = regexp.MustCompile(`^'((?:[^']|'')*)'::[A-Za-z0-9_ ."()\[\],]+$`)
+	nextvalRe     = regexp.MustCompile(`^nextval\('((?:[^']|'')+)'(?:::regclass)?\)$`)
+)
+
+// columnRow holds a row of the columns query.
+type columnRow struct {
+	table        string
+	col          *core.Column
+	formatType   string
+	enumValues   string
+	notNull      bool
+	defaultExpr  sql.NullString
+	identity     string
+	generated    string
+	storage      string
+	compression  string
+	ownedSeq     string
+	seqStart     int64
+	seqIncrement int64
+}
+
+func queryColumns(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT c.relname, a.attname,
+            format_type(a.atttypid, a.atttypmod),
+            CASE WHEN t.typtype = 'e' THEN coalesce((
+                SELECT json_agg(e.enumlabel ORDER BY e.enumsortorder)::text
+                FROM pg_enum e
+                WHERE e.enumtypid = t.oid
+            ), '[]') ELSE '' END,
+            a.attnotnull,
+            pg_get_expr(d.adbin, d.adrelid, true),
+            a.attidentity::text,
+            a.attgenerated::text,
+            coalesce(col_description(c.oid, a.attnum), ''),
+            CASE WHEN a.attcollation <> t.typcollation THEN coalesce(co.collname, '') ELSE '' END,
+            CASE WHEN a.attstorage <> t.typstorage THEN a.attstorage::text ELSE '' END,
+            coalesce(a.attcompression::text, ''),
+            coalesce(seq.relname, ''),
+            coalesce(seq.seqstart, 1),
+            coalesce(seq.seqincrement, 1)
+        FROM pg_attribute a
+        JOIN pg_class c ON c.oid = a.attrelid
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        JOIN pg_type t ON t.oid = a.atttypid
+        LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+        LEFT JOIN pg_collation co ON co.oid = a.attcollation
+        LEFT JOIN LATERAL (
+            SELECT sc.relname, s.seqstart, s.seqincrement
+            FROM pg_depend dep
+            JOIN pg_sequence s ON s.seqrelid = dep.objid
+            JOIN pg_class sc ON sc.oid = s.seqrelid
+            WHERE dep.classid = 'pg_class'::regclass
+                AND dep.refclassid = 'pg_class'::regclass
+                AND dep.refobjid = a.attrelid
+                AND dep.refobjsubid = a.attnum
+                AND dep.deptype IN ('a', 'i')
+            LIMIT 1
+        ) seq ON true
+        WHERE ` + tableFilter + `
+            AND a.attnum > 0
+            AND NOT a.attisdropped
+        ORDER BY c.relname, a.attnum
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := columnRow{col: &core.Column{}}
+		err := rows.Scan(&r.table, &r.col.Name, &r.formatType, &r.enumValues, &r.notNull, &r.defaultExpr,
+			&r.identity, &r.generated, &r.col.Comment, &r.col.Collate, &r.storage, &r.compression,
+			&r.ownedSeq, &r.seqStart, &r.seqIncrement)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(r.table)
+		if err != nil {
+			return err
+		}
+		if err := r.build(); err != nil {
+			return fmt.Errorf("table %s: column %s: %w", r.table, r.col.Name, err)
+		}
+		table.Columns = append(table.Columns, r.col)
+	}
+	return rows.Err()
+}
+
+// build fills col from the rest of the row.
+func (r *columnRow) build() error {
+	col := r.col
+	col.Nullable = !r.notNull
+	if r.enumValues != "" {
+		if err := json.Unmarshal([]byte(r.enumValues), &col.EnumValues); err != nil {
+			return fmt.Errorf("enum values: %w", err)
+		}
+		col.Type = core.DataTypeEnum
+		col.PortableType = core.BuildEnumTypeRaw(col.EnumValues)
+	} else {
+		setColumnType(col, r.formatType)
+	}
+
+	switch {
+	case r.generated == "s":
+		col.IsGenerated = true
+		col.GenerationExpression = introspect.StripParens(r.defaultExpr.String)
+		col.GenerationStorage = core.GenerationStored
+	case r.identity != "":
+		r.setIdentity()
+	case r.defaultExpr.Valid:
+		r.setDefault(r.defaultExpr.String)
+	}
+
+	storage, compression := storageModes[r.storage], compressionMethods[r.compression]
+	if storage != "" || compression != "" {
+		col.PostgreSQL = &core.PostgreSQLColumnOptions{Storage: storage, Compression: compression}
+	}
+	return nil
+}
+
+// setIdentity marks an identity column as auto-increment. GENERATED ALWAYS
+// is the default of the schema, so only BY DEFAULT is written.
+func (r *columnRow) setIdentity() {
+	r.col.AutoIncrement = true
+	if r.identity == "d" {
+		r.col.IdentityGeneration = core.IdentityByDefault
+	}
+	r.setSequenceOptions()
+}
+
+// setDefault sets the default of a column from its expression. A column
+// that takes its default from the sequence it owns, as serial columns do,
+// is written as auto-increment, and one that takes it from another sequence
+// is bound to that sequence.
+func (r *columnRow) setDefault(expr string) {
+	m := nextvalRe.FindStringSubmatch(expr)
+	if m == nil {
+		r.col.DefaultValue = columnDefault(r.col, expr)
+		return
+	}
+	seq := strings.ReplaceAll(m[1], "''", "'")
+	name := seq[strings.LastIndexByte(seq, '.')+1:]
+	if r.ownedSeq != "" && strings.Trim(name, `"`) == r.ownedSeq {
+		r.col.AutoIncrement = true
+		r.setSequenceOptions()
+		return
+	}
+	r.col.SequenceName = seq
+}
+
+// setSequenceOptions sets the start and increment of the sequence behind an
+// auto-increment column, unless they are the default of 1.
+func (r *columnRow) setSequenceOptions() {
+	if r.seqStart != 1 {
+		r.col.IdentitySeed = r.seqStart
+	}
+	if r.seqIncrement != 1 {
+		r.col.IdentityIncrement = r.seqIncrement
+	}
+}
+
+// setColumnType sets the type of col from the type printed by format_type.
+// Types that the portable type names describe exactly are written as
+// portable types.
+//
+// Example input: "character varying(255)", "timestamp(3) with time zone".
+func setColumnType(col *core.Column, typ string) {
+	base, args := typ, ""
+	if open := strings.IndexByte(typ, '('); open >= 0 {
+		if end := strings.IndexByte(typ[open:], ')'); end >= 0 {
+			args = typ[open : open+end+1]
+			base = typ[:open] + typ[open+end+1:]
+		}
+	}
+
+	p, ok := portableTypes[base]
+	if !ok || p.takesArg != (args != "") {
+		col.RawType = typ
+		col.Type = core.NormalizeDataType(typ)
+		return
+	}
+	col.PortableType = p.name + args
+	col.Type = core.NormalizeDataType(col.PortableType)
+}
+
+// columnDefault returns the default value for a default expression as
+// printed by pg_get_expr. A literal loses its type cast and is stored
+// unquoted, as it is written in the schema, unless it would then read as
+// something else: a number is only unquoted for numeric columns, and
+// keywords and expressions keep their quotes. Other expressions are kept as
+// printed.
+//
+// Example input: "'active'::character varying", "now()".
+func columnDefault(col *core.Column, expr string) *string {
+	if expr == "NULL" || strings.HasPrefix(expr, "NULL::") {
+		return nil
+	}
+	if expr == "true" || expr == "false" {
+		return new(strings.ToUpper(expr))
+	}
+	m := castLiteralRe.FindStringSubmatch(expr)
+	if m == nil {
+		return &expr
+	}
+
+	v := strings.ReplaceAll(m[1], "''", "'")
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return &v
+	case generate.DefaultNumber:
+		if col.Type == core.DataTypeInt || col.Type == core.DataTypeFloat {
+			return &v
+		}
+	}
+	return new(generate.QuoteString(v))
+}
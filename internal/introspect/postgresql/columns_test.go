@@ -0,0 +1,128 @@
+package postgresql
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestSetColumnType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		typ  string
+		want core.Column
+	}{
+		{"character varying(255)", core.Column{Type: core.DataTypeString, PortableType: "varchar(255)"}},
+		{"character varying", core.Column{Type: core.DataTypeString, RawType: "character varying"}},
+		{"numeric(10,2)", core.Column{Type: core.DataTypeFloat, PortableType: "decimal(10,2)"}},
+		{"integer", core.Column{Type: core.DataTypeInt, PortableType: "int"}},
+		{"double precision", core.Column{Type: core.DataTypeFloat, PortableType: "double"}},
+		{"timestamp with time zone", core.Column{Type: core.DataTypeDatetime, PortableType: "timestamp"}},
+		{"timestamp without time zone", core.Column{Type: core.DataTypeDatetime, PortableType: "datetime"}},
+		{"timestamp(3) with time zone", core.Column{Type: core.DataTypeDatetime, RawType: "timestamp(3) with time zone"}},
+		{"jsonb", core.Column{Type: core.DataTypeJSON, PortableType: "json"}},
+		{"json", core.Column{Type: core.DataTypeJSON, RawType: "json"}},
+		{"bytea", core.Column{Type: core.DataTypeBinary, PortableType: "blob"}},
+		{"inet", core.Column{Type: core.DataTypeUnknown, RawType: "inet"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			t.Parallel()
+			var col core.Column
+			setColumnType(&col, tt.typ)
+			assert.Equal(t, tt.want, col)
+		})
+	}
+}
+
+func TestColumnRowBuild(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		row  columnRow
+		want *core.Column
+	}{
+		{
+			name: "serial",
+			row: columnRow{formatType: "integer", notNull: true, ownedSeq: "users_id_seq", seqStart: 1, seqIncrement: 1,
+				defaultExpr: sql.NullString{String: "nextval('users_id_seq'::regclass)", Valid: true}},
+			want: &core.Column{Name: "c", Type: core.DataTypeInt, PortableType: "int", AutoIncrement: true},
+		},
+		{
+			name: "identity by default",
+			row:  columnRow{formatType: "bigint", notNull: true, identity: "d", seqStart: 1000, seqIncrement: 10},
+			want: &core.Column{Name: "c", Type: core.DataTypeInt, PortableType: "bigint", AutoIncrement: true,
+				IdentityGeneration: core.IdentityByDefault, IdentitySeed: 1000, IdentityIncrement: 10},
+		},
+		{
+			name: "identity always",
+			row:  columnRow{formatType: "bigint", notNull: true, identity: "a", seqStart: 1, seqIncrement: 1},
+			want: &core.Column{Name: "c", Type: core.DataTypeInt, PortableType: "bigint", AutoIncrement: true},
+		},
+		{
+			name: "shared sequence",
+			row: columnRow{formatType: "bigint", notNull: true, seqStart: 1, seqIncrement: 1,
+				defaultExpr: sql.NullString{String: "nextval('billing.invoice_seq'::regclass)", Valid: true}},
+			want: &core.Column{Name: "c", Type: core.DataTypeInt, PortableType: "bigint", SequenceName: "billing.invoice_seq"},
+		},
+		{
+			name: "generated",
+			row: columnRow{formatType: "numeric(12,2)", generated: "s",
+				defaultExpr: sql.NullString{String: "(price * quantity::numeric)", Valid: true}},
+			want: &core.Column{Name: "c", Type: core.DataTypeFloat, PortableType: "decimal(12,2)", Nullable: true,
+				IsGenerated: true, GenerationExpression: "price * quantity::numeric", GenerationStorage: core.GenerationStored},
+		},
+		{
+			name: "enum",
+			row: columnRow{formatType: "plan", enumValues: `["free","pro"]`, notNull: true,
+				defaultExpr: sql.NullString{String: "'free'::plan", Valid: true}},
+			want: &core.Column{Name: "c", Type: core.DataTypeEnum, PortableType: "enum('free','pro')",
+				EnumValues: []string{"free", "pro"}, DefaultValue: new("free")},
+		},
+		{
+			name: "storage and compression",
+			row:  columnRow{formatType: "text", storage: "e", compression: "l"},
+			want: &core.Column{Name: "c", Type: core.DataTypeString, PortableType: "text", Nullable: true,
+				PostgreSQL: &core.PostgreSQLColumnOptions{Storage: "EXTERNAL", Compression: "lz4"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.row.col = &core.Column{Name: "c"}
+			require.NoError(t, tt.row.build())
+			assert.Equal(t, tt.want, tt.row.col)
+		})
+	}
+}
+
+func TestColumnDefault(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		expr string
+		typ  core.DataType
+		want *string
+	}{
+		{"NULL::character varying", core.DataTypeString, nil},
+		{"'active'::character varying", core.DataTypeString, new("active")},
+		{"'it''s'::text", core.DataTypeString, new("it's")},
+		{"'-1'::integer", core.DataTypeInt, new("-1")},
+		{"'01234'::bpchar", core.DataTypeString, new("'01234'")},
+		{"'{}'::jsonb", core.DataTypeJSON, new("{}")},
+		{"true", core.DataTypeBoolean, new("TRUE")},
+		{"0", core.DataTypeInt, new("0")},
+		{"now()", core.DataTypeDatetime, new("now()")},
+		{"CURRENT_TIMESTAMP", core.DataTypeDatetime, new("CURRENT_TIMESTAMP")},
+		{"'a'::text || 'b'::text", core.DataTypeString, new("'a'::text || 'b'::text")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, columnDefault(&core.Column{Type: tt.typ}, tt.expr))
+		})
+	}
+}
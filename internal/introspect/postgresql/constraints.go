@@ -0,0 +1,139 @@
+package postgresql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// referentialActions maps pg_constraint.confdeltype and confupdtype to the
+// action they stand for. NO ACTION is the default and is left unset.
+var referentialActions = map[string]core.ReferentialAction{
+	"a": core.RefActionNone,
+	"r": core.RefActionRestrict,
+	"c": core.RefActionCascade,
+	"n": core.RefActionSetNull,
+	"d": core.RefActionSetDefault,
+}
+
+// constraintTypes maps pg_constraint.contype to the constraint type it
+// stands for. EXCLUDE constraints have no counterpart in the schema and are
+// not read.
+var constraintTypes = map[string]core.ConstraintType{
+	"p": core.ConstraintPrimaryKey,
+	"u": core.ConstraintUnique,
+	"f": core.ConstraintForeignKey,
+	"c": core.ConstraintCheck,
+}
+
+// constraintRow holds a row of the constraints query.
+type constraintRow struct {
+	table      string
+	con        *core.Constraint
+	contype    string
+	columns    string
+	refColumns string
+	onDelete   string
+	onUpdate   string
+	definition string
+}
+
+// queryConstraints reads the constraints declared on the tables
+// themselves; those inherited from a parent table are declared there.
+func queryConstraints(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT c.relname, con.conname, con.contype::text,
+            coalesce((
+                SELECT json_agg(a.attname ORDER BY k.n)::text
+                FROM unnest(con.conkey) WITH ORDINALITY k(attnum, n)
+                JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+            ), '[]'),
+            coalesce(f.relname, ''),
+            coalesce((
+                SELECT json_agg(a.attname ORDER BY k.n)::text
+                FROM unnest(con.confkey) WITH ORDINALITY k(attnum, n)
+                JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+            ), '[]'),
+            con.confdeltype::text,
+            con.confupdtype::text,
+            pg_get_constraintdef(con.oid, true)
+        FROM pg_constraint con
+        JOIN pg_class c ON c.oid = con.conrelid
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        LEFT JOIN pg_class f ON f.oid = con.confrelid
+        WHERE ` + tableFilter + `
+            AND con.contype IN ('p', 'u', 'f', 'c')
+            AND con.conislocal
+        ORDER BY c.relname, con.conname
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := constraintRow{con: &core.Constraint{}}
+		err := rows.Scan(&r.table, &r.con.Name, &r.contype, &r.columns, &r.con.ReferencedTable,
+			&r.refColumns, &r.onDelete, &r.onUpdate, &r.definition)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(r.table)
+		if err != nil {
+			return err
+		}
+		if err := r.build(); err != nil {
+			return fmt.Errorf("table %s: constraint %s: %w", r.table, r.con.Name, err)
+		}
+		table.Constraints = append(table.Constraints, r.con)
+		if r.con.Type == core.ConstraintPrimaryKey {
+			markPrimaryKey(table, r.con.Columns)
+		}
+	}
+	return rows.Err()
+}
+
+// build fills con from the rest of the row.
+func (r *constraintRow) build() error {
+	con := r.con
+	con.Type = constraintTypes[r.contype]
+	if err := json.Unmarshal([]byte(r.columns), &con.Columns); err != nil {
+		return fmt.Errorf("columns: %w", err)
+	}
+
+	switch con.Type {
+	case core.ConstraintForeignKey:
+		if err := json.Unmarshal([]byte(r.refColumns), &con.ReferencedColumns); err != nil {
+			return fmt.Errorf("referenced columns: %w", err)
+		}
+		con.OnDelete = referentialActions[r.onDelete]
+		con.OnUpdate = referentialActions[r.onUpdate]
+	case core.ConstraintCheck:
+		con.CheckExpression = checkExpression(r.definition)
+	}
+	return nil
+}
+
+func markPrimaryKey(table *core.Table, columns []string) {
+	for _, name := range columns {
+		if col := table.FindColumn(name); col != nil {
+			col.PrimaryKey = true
+		}
+	}
+}
+
+// checkExpression returns the expression of a CHECK constraint as printed
+// by pg_get_constraintdef, without the parentheses around it. A constraint
+// that is not validated yet is still read as a CHECK constraint.
+//
+// Example input: "CHECK (price > 0::numeric) NOT VALID".
+func checkExpression(def string) string {
+	expr := strings.TrimPrefix(def, "CHECK ")
+	expr = strings.TrimSuffix(expr, " NOT VALID")
+	expr = strings.TrimSuffix(expr, " NO INHERIT")
+	return introspect.StripParens(expr)
+}
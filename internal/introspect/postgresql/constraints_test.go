@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestCheckExpression(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		def  string
+		want string
+	}{
+		{"CHECK (price > 0::numeric)", "price > 0::numeric"},
+		{"CHECK (price > 0::numeric) NOT VALID", "price > 0::numeric"},
+		{"CHECK ((a > 0) AND (b > 0))", "(a > 0) AND (b > 0)"},
+		{"CHECK ((a > 0) OR (b > 0)) NO INHERIT", "(a > 0) OR (b > 0)"},
+		{"CHECK (status = ANY (ARRAY['a)'::text, 'b'::text]))", "status = ANY (ARRAY['a)'::text, 'b'::text])"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.def, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, checkExpression(tt.def))
+		})
+	}
+}
+
+func TestConstraintRowBuild(t *testing.T) {
+	t.Parallel()
+	r := constraintRow{
+		con:        &core.Constraint{Name: "fk_orders_users", ReferencedTable: "users"},
+		contype:    "f",
+		columns:    `["user_id"]`,
+		refColumns: `["id"]`,
+		onDelete:   "c",
+		onUpdate:   "a",
+	}
+	require.NoError(t, r.build())
+	assert.Equal(t, &core.Constraint{
+		Name:              "fk_orders_users",
+		Type:              core.ConstraintForeignKey,
+		Columns:           []string{"user_id"},
+		ReferencedTable:   "users",
+		ReferencedColumns: []string{"id"},
+		OnDelete:          core.RefActionCascade,
+	}, r.con)
+}
+
+func TestMarkPrimaryKey(t *testing.T) {
+	t.Parallel()
+	table := &core.Table{Columns: []*core.Column{{Name: "id"}, {Name: "name"}}}
+	markPrimaryKey(table, []string{"id"})
+	assert.True(t, table.Columns[0].PrimaryKey)
+	assert.False(t, table.Columns[1].PrimaryKey)
+}
@@ -0,0 +1,108 @@
+package postgresql
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// accessMethods maps the index access methods of pg_am to the index type
+// they stand for. Indexes using other methods, such as SP-GiST, have no
+// counterpart in the schema and are not read.
+var accessMethods = map[string]core.IndexType{
+	"btree": core.IndexTypeBTree,
+	"hash":  core.IndexTypeHash,
+	"gin":   core.IndexTypeGIN,
+	"gist":  core.IndexTypeGiST,
+	"brin":  core.IndexTypeBRIN,
+}
+
+// keyPart is a key part of an index as returned by the indexes query.
+type keyPart struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Desc       bool   `json:"desc"`
+}
+
+// queryIndexes reads the indexes of the tables. The indexes that back a
+// primary key, unique, or exclusion constraint are part of the constraint
+// and are not read.
+func queryIndexes(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT c.relname, ic.relname, i.indisunique, am.amname,
+            coalesce(pg_get_expr(i.indpred, i.indrelid, true), ''),
+            coalesce(obj_description(ic.oid, 'pg_class'), ''),
+            (
+                SELECT json_agg(json_build_object(
+                    'name', coalesce(a.attname, ''),
+                    'expression', CASE WHEN a.attname IS NULL THEN pg_get_indexdef(i.indexrelid, k.n, true) ELSE '' END,
+                    'desc', i.indoption[k.n - 1] & 1 = 1
+                ) ORDER BY k.n)::text
+                FROM generate_series(1, i.indnkeyatts) k(n)
+                LEFT JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = i.indkey[k.n - 1]
+            )
+        FROM pg_index i
+        JOIN pg_class ic ON ic.oid = i.indexrelid
+        JOIN pg_class c ON c.oid = i.indrelid
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        JOIN pg_am am ON am.oid = ic.relam
+        WHERE ` + tableFilter + `
+            AND NOT EXISTS (
+                SELECT 1
+                FROM pg_constraint con
+                WHERE con.conindid = i.indexrelid
+                    AND con.conrelid = i.indrelid
+                    AND con.contype IN ('p', 'u', 'x')
+            )
+        ORDER BY c.relname, ic.relname
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		idx := &core.Index{Visibility: core.IndexVisible}
+		var tableName, method, parts string
+		err := rows.Scan(&tableName, &idx.Name, &idx.Unique, &method, &idx.Where, &idx.Comment, &parts)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(tableName)
+		if err != nil {
+			return err
+		}
+		typ, ok := accessMethods[method]
+		if !ok {
+			continue
+		}
+		idx.Type = typ
+		if idx.Columns, err = indexColumns(parts); err != nil {
+			return fmt.Errorf("table %s: index %s: %w", tableName, idx.Name, err)
+		}
+		table.Indexes = append(table.Indexes, idx)
+	}
+	return rows.Err()
+}
+
+// indexColumns decodes the key parts of an index.
+//
+// Example input: `[{"name":"email","expression":"","desc":false}]`.
+func indexColumns(parts string) ([]core.ColumnIndex, error) {
+	var keys []keyPart
+	if err := json.Unmarshal([]byte(parts), &keys); err != nil {
+		return nil, fmt.Errorf("key parts: %w", err)
+	}
+	columns := make([]core.ColumnIndex, 0, len(keys))
+	for _, k := range keys {
+		col := core.ColumnIndex{Name: k.Name, Expression: k.Expression, Order: core.SortAsc}
+		if k.Desc {
+			col.Order = core.SortDesc
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
@@ -0,0 +1,25 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestIndexColumns(t *testing.T) {
+	t.Parallel()
+	got, err := indexColumns(`[{"name":"tenant_id","expression":"","desc":false},` +
+		`{"name":"","expression":"lower(email)","desc":false},{"name":"created_at","expression":"","desc":true}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []core.ColumnIndex{
+		{Name: "tenant_id", Order: core.SortAsc},
+		{Expression: "lower(email)", Order: core.SortAsc},
+		{Name: "created_at", Order: core.SortDesc},
+	}, got)
+
+	_, err = indexColumns("null,")
+	assert.Error(t, err)
+}
@@ -1,3 +1,7 @@
+// Package postgresql contains introspect implementation for PostgreSQL. It reads the
+// tables and views of the current schema, the first schema of the search_path, from
+// pg_catalog with a few bulk queries, so the schema to introspect is chosen in the
+// connection string.
 package postgresql
 
 import (
@@ -14,10 +18,36 @@ func init() {
 
 type introspecter struct{}
 
+type introspectCtx struct {
+	db  *sql.DB
+	ctx context.Context
+}
+
 func New() introspect.Introspecter {
 	return &introspecter{}
 }
 
-func (i *introspecter) Introspect(_ context.Context, _ *sql.DB) (*core.Database, error) {
-	return nil, nil
+func (i *introspecter) Introspect(ctx context.Context, db *sql.DB) (*core.Database, error) {
+	d := &core.Database{Dialect: core.DialectPostgreSQL}
+	err := db.QueryRowContext(ctx, "SELECT current_database()").Scan(&d.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	ic := &introspectCtx{
+		db:  db,
+		ctx: ctx,
+	}
+
+	err = introspectTables(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = introspectViews(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
 }
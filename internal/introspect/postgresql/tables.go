@@ -0,0 +1,134 @@
+package postgresql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// tableFilter selects the ordinary and partitioned tables of the current
+// schema from pg_class c joined with pg_namespace n. Partitions are left
+// out: the schema describes the partitioned table, and its partitions are
+// created and dropped with the data.
+const tableFilter = `n.nspname = current_schema()
+        AND c.relkind IN ('r', 'p')
+        AND NOT c.relispartition`
+
+func introspectTables(ic *introspectCtx, db *core.Database) error {
+	set, err := queryTables(ic)
+	if err != nil {
+		return fmt.Errorf("query tables: %w", err)
+	}
+	if len(set.Tables) == 0 {
+		return nil
+	}
+
+	if err := queryColumns(ic, set); err != nil {
+		return fmt.Errorf("query columns: %w", err)
+	}
+	if err := queryConstraints(ic, set); err != nil {
+		return fmt.Errorf("query constraints: %w", err)
+	}
+	if err := queryIndexes(ic, set); err != nil {
+		return fmt.Errorf("query indexes: %w", err)
+	}
+	if err := queryOnUpdateTriggers(ic, set); err != nil {
+		return fmt.Errorf("query triggers: %w", err)
+	}
+
+	db.Tables = append(db.Tables, set.Tables...)
+	return nil
+}
+
+func queryTables(ic *introspectCtx) (*introspect.TableSet, error) {
+	query := `
+        SELECT c.relname,
+            n.nspname,
+            c.relpersistence = 'u',
+            coalesce(array_to_string(c.reloptions, ','), ''),
+            coalesce(pg_get_partkeydef(c.oid), ''),
+            coalesce(ts.spcname, ''),
+            coalesce(obj_description(c.oid, 'pg_class'), ''),
+            coalesce((
+                SELECT json_agg(CASE WHEN pn.oid = n.oid THEN p.relname ELSE pn.nspname || '.' || p.relname END
+                    ORDER BY i.inhseqno)::text
+                FROM pg_inherits i
+                JOIN pg_class p ON p.oid = i.inhparent
+                JOIN pg_namespace pn ON pn.oid = p.relnamespace
+                WHERE i.inhrelid = c.oid
+            ), '[]')
+        FROM pg_class c
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        LEFT JOIN pg_tablespace ts ON ts.oid = c.reltablespace
+        WHERE ` + tableFilter + `
+        ORDER BY c.relname
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set := introspect.NewTableSet()
+	for rows.Next() {
+		table, err := scanTable(rows)
+		if err != nil {
+			return nil, err
+		}
+		set.Add(table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+func scanTable(rows *sql.Rows) (*core.Table, error) {
+	table := &core.Table{
+		Columns:     make([]*core.Column, 0),
+		Constraints: make([]*core.Constraint, 0),
+		Indexes:     make([]*core.Index, 0),
+	}
+	opts := &core.PostgreSQLTableOptions{}
+	var reloptions, inherits string
+	err := rows.Scan(&table.Name, &opts.Schema, &opts.Unlogged, &reloptions, &opts.PartitionBy,
+		&table.Options.Tablespace, &table.Comment, &inherits)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Fillfactor, err = fillfactor(reloptions); err != nil {
+		return nil, fmt.Errorf("table %s: %w", table.Name, err)
+	}
+	if err := json.Unmarshal([]byte(inherits), &opts.Inherits); err != nil {
+		return nil, fmt.Errorf("table %s: inherits: %w", table.Name, err)
+	}
+	table.Options.PostgreSQL = opts
+	return table, nil
+}
+
+// fillfactor returns the fillfactor among the storage parameters of a
+// table, as stored in pg_class.reloptions and joined with commas, or 0 when
+// it is not set. The other parameters have no counterpart in the schema.
+//
+// Example input: "fillfactor=70,autovacuum_enabled=false".
+func fillfactor(reloptions string) (int, error) {
+	for opt := range strings.SplitSeq(reloptions, ",") {
+		name, value, _ := strings.Cut(opt, "=")
+		if name != "fillfactor" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid fillfactor %q", value)
+		}
+		return n, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,32 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFillfactor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		reloptions string
+		want       int
+	}{
+		{"", 0},
+		{"fillfactor=70", 70},
+		{"autovacuum_enabled=false,fillfactor=90", 90},
+		{"autovacuum_enabled=false", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reloptions, func(t *testing.T) {
+			t.Parallel()
+			got, err := fillfactor(tt.reloptions)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := fillfactor("fillfactor=high")
+	assert.Error(t, err)
+}
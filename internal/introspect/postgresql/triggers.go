@@ -0,0 +1,66 @@
+package postgresql
+
+import (
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// PostgreSQL has no ON UPDATE column clause, and the generator emulates it
+// with a BEFORE UPDATE trigger named {table}_{column}_on_update that calls a
+// plpgsql function of the same name. The value that function assigns is read
+// back as the ON UPDATE value of the column; other triggers are not read.
+
+// onUpdateSetRe matches the assignment in the body of an ON UPDATE trigger
+// function.
+var onUpdateSetRe = regexp.MustCompile(`(?s)\bNEW\."((?:[^"]|"")+)" := (.+?);\s+RETURN NEW;`)
+
+func queryOnUpdateTriggers(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT c.relname, tg.tgname, p.prosrc
+        FROM pg_trigger tg
+        JOIN pg_class c ON c.oid = tg.tgrelid
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        JOIN pg_proc p ON p.oid = tg.tgfoid
+        WHERE ` + tableFilter + `
+            AND NOT tg.tgisinternal
+            AND tg.tgname LIKE '%\_on\_update'
+            AND p.proname = tg.tgname
+        ORDER BY c.relname, tg.tgname
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, name, source string
+		if err := rows.Scan(&tableName, &name, &source); err != nil {
+			return err
+		}
+		table, err := set.Get(tableName)
+		if err != nil {
+			return err
+		}
+		setOnUpdate(table, name, source)
+	}
+	return rows.Err()
+}
+
+// setOnUpdate sets the ON UPDATE value of the column a trigger emulates it
+// for, when the trigger is named and its function written as the generator
+// writes them.
+func setOnUpdate(table *core.Table, name, source string) {
+	m := onUpdateSetRe.FindStringSubmatch(source)
+	if m == nil {
+		return
+	}
+	col := table.FindColumn(strings.ReplaceAll(m[1], `""`, `"`))
+	if col == nil || name != table.Name+"_"+col.Name+"_on_update" {
+		return
+	}
+	col.OnUpdate = new(strings.TrimSpace(m[2]))
+}
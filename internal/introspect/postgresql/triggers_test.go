@@ -0,0 +1,28 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestSetOnUpdate(t *testing.T) {
+	t.Parallel()
+	const source = `
+BEGIN
+  NEW."updated_at" := CURRENT_TIMESTAMP;
+  RETURN NEW;
+END;
+`
+	table := &core.Table{Name: "orders", Columns: []*core.Column{{Name: "id"}, {Name: "updated_at"}}}
+	setOnUpdate(table, "orders_id_on_update", source)
+	assert.Nil(t, table.Columns[1].OnUpdate)
+
+	setOnUpdate(table, "orders_updated_at_on_update", "BEGIN\n  RETURN NEW;\nEND;")
+	assert.Nil(t, table.Columns[1].OnUpdate)
+
+	setOnUpdate(table, "orders_updated_at_on_update", source)
+	assert.Equal(t, new("CURRENT_TIMESTAMP"), table.Columns[1].OnUpdate)
+}
@@ -0,0 +1,71 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// viewRow holds one view or materialized view of the current schema.
+type viewRow struct {
+	name         string
+	materialized bool
+	definition   string
+	reloptions   string
+	comment      string
+}
+
+func introspectViews(ic *introspectCtx, db *core.Database) error {
+	query := `
+        SELECT c.relname,
+            c.relkind = 'm',
+            pg_get_viewdef(c.oid),
+            coalesce(array_to_string(c.reloptions, ','), ''),
+            coalesce(obj_description(c.oid, 'pg_class'), '')
+        FROM pg_class c
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        WHERE n.nspname = current_schema()
+        AND c.relkind IN ('v', 'm')
+        ORDER BY c.relname
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return fmt.Errorf("query views: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r viewRow
+		if err := rows.Scan(&r.name, &r.materialized, &r.definition, &r.reloptions, &r.comment); err != nil {
+			return fmt.Errorf("query views: %w", err)
+		}
+		db.Views = append(db.Views, r.build())
+	}
+	return rows.Err()
+}
+
+// build converts the row into a core.View. The check option and
+// security_invoker are stored among the view's reloptions.
+//
+// Example reloptions: "check_option=cascaded,security_invoker=true".
+func (r viewRow) build() *core.View {
+	v := &core.View{
+		Name:         r.name,
+		Definition:   strings.TrimSpace(r.definition),
+		Materialized: r.materialized,
+		Comment:      r.comment,
+	}
+	for opt := range strings.SplitSeq(r.reloptions, ",") {
+		name, value, _ := strings.Cut(opt, "=")
+		switch name {
+		case "check_option":
+			v.CheckOption = core.CheckOption(strings.ToUpper(value))
+		case "security_invoker":
+			if value == "true" || value == "on" {
+				v.Security = core.SecurityInvoker
+			}
+		}
+	}
+	return v
+}
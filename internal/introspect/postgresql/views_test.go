@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+)
+
+func TestViewRowBuild(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		row  viewRow
+		want *core.View
+	}{
+		{
+			name: "plain view",
+			row: viewRow{
+				name:       "active_users",
+				definition: " SELECT users.id\n   FROM users\n  WHERE users.active;",
+			},
+			want: &core.View{
+				Name:       "active_users",
+				Definition: "SELECT users.id\n   FROM users\n  WHERE users.active;",
+			},
+		},
+		{
+			name: "check option and security invoker",
+			row: viewRow{
+				name:       "big_orders",
+				definition: "SELECT orders.id FROM orders;",
+				reloptions: "check_option=local,security_invoker=true",
+			},
+			want: &core.View{
+				Name:        "big_orders",
+				Definition:  "SELECT orders.id FROM orders;",
+				CheckOption: core.CheckOptionLocal,
+				Security:    core.SecurityInvoker,
+			},
+		},
+		{
+			name: "materialized view with comment",
+			row: viewRow{
+				name:         "order_stats",
+				materialized: true,
+				definition:   "SELECT count(*) AS n FROM orders;",
+				reloptions:   "fillfactor=90",
+				comment:      "daily stats",
+			},
+			want: &core.View{
+				Name:         "order_stats",
+				Definition:   "SELECT count(*) AS n FROM orders;",
+				Materialized: true,
+				Comment:      "daily stats",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.row.build())
+		})
+	}
+}
+
+func TestViewRowBuildMatchesDeclared(t *testing.T) {
+	t.Parallel()
+	row := viewRow{
+		name:       "big_orders",
+		definition: " SELECT orders.id,\n    orders.total\n   FROM orders\n  WHERE (orders.total > (100)::numeric);",
+	}
+	declared := &core.View{Name: "big_orders", Definition: "SELECT id, total FROM orders WHERE total > 100;"}
+
+	assert.Empty(t, diff.View(row.build(), declared))
+}
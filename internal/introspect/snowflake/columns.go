@@ -0,0 +1,245 @@
+package snowflake
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+	"smf/internal/introspect"
+)
+
+// portableTypes maps the types printed by formatType to the portable type
+// they are written as, and whether the portable type takes arguments. The
+// entries follow the types the generator declares. Snowflake stores every
+// integer type as NUMBER(38,0), which is read as bigint, CHAR as VARCHAR,
+// and DOUBLE as FLOAT. Every other type is kept as a raw type.
+var portableTypes = map[string]struct {
+	name     string
+	takesArg bool
+}{
+	"TEXT":          {"text", false},
+	"VARCHAR":       {"varchar", true},
+	"NUMBER(38,0)":  {"bigint", false},
+	"NUMBER":        {"decimal", true},
+	"FLOAT":         {"double", false},
+	"BOOLEAN":       {"boolean", false},
+	"DATE":          {"date", false},
+	"TIME":          {"time", false},
+	"TIMESTAMP_TZ":  {"timestamp", false},
+	"TIMESTAMP_NTZ": {"datetime", false},
+	"VARIANT":       {"json", false},
+	"BINARY":        {"blob", false},
+	"VARBINARY":     {"varbinary", true},
+}
+
+// Lengths and precisions that the type names imply when they are declared
+// without arguments.
+const (
+	defaultTextLength        = 16777216
+	defaultBinaryLength      = 8388608
+	defaultDatetimePrecision = 9
+)
+
+var (
+	stringLiteralRe = regexp.MustCompile(`^'((?:[^']|'')*)'$`)
+	nextvalRe       = regexp.MustCompile(`(?i)^(?:(?:"(?:[^"]|"")+"|[A-Za-z_][\w$]*)\.)*("(?:[^"]|"")+"|[A-Za-z_][\w$]*)\.NEXTVAL$`)
+)
+
+// columnRow holds a row of the columns query.
+type columnRow struct {
+	table        string
+	col          *core.Column
+	dataType     string
+	length       sql.NullInt64
+	precision    sql.NullInt64
+	scale        sql.NullInt64
+	datetimePrec sql.NullInt64
+	nullable     string
+	defaultExpr  sql.NullString
+	identity     sql.NullString
+	start        sql.NullString
+	increment    sql.NullString
+	collation    sql.NullString
+	comment      sql.NullString
+}
+
+// queryColumns reads the columns of the tables. Snowflake has no
+// generated, invisible, or ON UPDATE columns.
+func queryColumns(ic *introspectCtx, set *introspect.TableSet) error {
+	query := `
+        SELECT c.TABLE_NAME, c.COLUMN_NAME, c.DATA_TYPE, c.CHARACTER_MAXIMUM_LENGTH,
+            c.NUMERIC_PRECISION, c.NUMERIC_SCALE, c.DATETIME_PRECISION, c.IS_NULLABLE, c.COLUMN_DEFAULT,
+            c.IS_IDENTITY, c.IDENTITY_START, c.IDENTITY_INCREMENT, c.COLLATION_NAME, c.COMMENT
+        FROM INFORMATION_SCHEMA.COLUMNS c
+        JOIN INFORMATION_SCHEMA.TABLES t ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+        WHERE ` + tableFilter + `
+        ORDER BY c.TABLE_NAME, c.ORDINAL_POSITION
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := columnRow{col: &core.Column{}}
+		err := rows.Scan(&r.table, &r.col.Name, &r.dataType, &r.length, &r.precision, &r.scale, &r.datetimePrec,
+			&r.nullable, &r.defaultExpr, &r.identity, &r.start, &r.increment, &r.collation, &r.comment)
+		if err != nil {
+			return err
+		}
+		table, err := set.Get(r.table)
+		if err != nil {
+			return err
+		}
+		if err := r.build(); err != nil {
+			return fmt.Errorf("table %s: column %s: %w", r.table, r.col.Name, err)
+		}
+		table.Columns = append(table.Columns, r.col)
+	}
+	return rows.Err()
+}
+
+// build fills col from the rest of the row.
+func (r *columnRow) build() error {
+	col := r.col
+	col.Nullable = r.nullable == "YES"
+	col.Collate = r.collation.String
+	col.Comment = r.comment.String
+	setColumnType(col, formatType(r))
+
+	switch {
+	case r.identity.String == "YES":
+		return r.setIdentity()
+	case r.defaultExpr.Valid:
+		r.setDefault(strings.TrimSpace(r.defaultExpr.String))
+	}
+	return nil
+}
+
+// setIdentity marks an identity column as auto-increment. A start or
+// increment of 1 is left unset.
+func (r *columnRow) setIdentity() error {
+	col := r.col
+	col.AutoIncrement = true
+	var err error
+	if col.IdentitySeed, err = identityOption(r.start); err != nil {
+		return fmt.Errorf("identity start: %w", err)
+	}
+	if col.IdentityIncrement, err = identityOption(r.increment); err != nil {
+		return fmt.Errorf("identity increment: %w", err)
+	}
+	return nil
+}
+
+// identityOption returns the start or increment of an identity column, or
+// 0 when it is the default of 1.
+func identityOption(v sql.NullString) (int64, error) {
+	if !v.Valid || v.String == "1" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v.String, 10, 64)
+}
+
+// setDefault sets the default of a column. A column that defaults to the
+// next value of a sequence is bound to that sequence; Snowflake qualifies
+// the sequence with its database and schema, which are left out.
+func (r *columnRow) setDefault(def string) {
+	if m := nextvalRe.FindStringSubmatch(def); m != nil {
+		r.col.SequenceName = unquote(m[1])
+		return
+	}
+	r.col.DefaultValue = columnDefault(r.col, def)
+}
+
+// unquote returns the name a possibly quoted identifier stands for.
+func unquote(name string) string {
+	if len(name) >= 2 && name[0] == '"' {
+		return strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+	}
+	return name
+}
+
+// formatType returns the type of a column as it is declared from its entry
+// in INFORMATION_SCHEMA.COLUMNS. Snowflake lists every character type as
+// TEXT and every binary type as BINARY.
+//
+// Example output: "VARCHAR(255)", "NUMBER(10,2)", "TIMESTAMP_NTZ(3)".
+func formatType(r *columnRow) string {
+	switch r.dataType {
+	case "TEXT":
+		return sizedType("TEXT", "VARCHAR", r.length, defaultTextLength)
+	case "BINARY":
+		return sizedType("BINARY", "VARBINARY", r.length, defaultBinaryLength)
+	case "NUMBER":
+		return fmt.Sprintf("NUMBER(%d,%d)", r.precision.Int64, r.scale.Int64)
+	case "TIME", "TIMESTAMP_NTZ", "TIMESTAMP_LTZ", "TIMESTAMP_TZ":
+		if !r.datetimePrec.Valid || r.datetimePrec.Int64 == defaultDatetimePrecision {
+			return r.dataType
+		}
+		return fmt.Sprintf("%s(%d)", r.dataType, r.datetimePrec.Int64)
+	default:
+		return r.dataType
+	}
+}
+
+// sizedType formats a character or binary type: the bare type when the
+// length is the maximum the type name implies, and the sized type
+// otherwise.
+func sizedType(bare, sized string, length sql.NullInt64, implied int64) string {
+	if !length.Valid || length.Int64 == implied {
+		return bare
+	}
+	return fmt.Sprintf("%s(%d)", sized, length.Int64)
+}
+
+// setColumnType sets the type of col from the type printed by formatType.
+// Types that the portable type names describe exactly are written as
+// portable types.
+func setColumnType(col *core.Column, typ string) {
+	if p, ok := portableTypes[typ]; ok && !p.takesArg {
+		col.PortableType = p.name
+		col.Type = core.NormalizeDataType(col.PortableType)
+		return
+	}
+	base, args, _ := strings.Cut(typ, "(")
+	if p, ok := portableTypes[base]; ok && p.takesArg && args != "" {
+		col.PortableType = p.name + "(" + args
+		col.Type = core.NormalizeDataType(col.PortableType)
+		return
+	}
+	col.RawType = typ
+	col.Type = core.NormalizeDataType(typ)
+}
+
+// columnDefault returns the default value for a default expression as it
+// is stored in COLUMN_DEFAULT. A string literal is stored unquoted, as it
+// is written in the schema, unless it would then read as something else: a
+// number is only unquoted for numeric columns, and keywords and expressions
+// keep their quotes. Other expressions are kept as stored.
+//
+// Example input: "'active'", "0", "CURRENT_TIMESTAMP()".
+func columnDefault(col *core.Column, expr string) *string {
+	if strings.EqualFold(expr, "NULL") {
+		return nil
+	}
+	m := stringLiteralRe.FindStringSubmatch(expr)
+	if m == nil {
+		return &expr
+	}
+
+	v := strings.ReplaceAll(m[1], "''", "'")
+	switch generate.ClassifyDefault(v) {
+	case generate.DefaultString:
+		return &v
+	case generate.DefaultNumber:
+		if col.Type == core.DataTypeInt || col.Type == core.DataTypeFloat {
+			return &v
+		}
+	}
+	return &expr
+}
@@ -0,0 +1,105 @@
+package snowflake
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestFormatType(t *testing.T) {
+	t.Parallel()
+	valid := func(n int64) sql.NullInt64 { return sql.NullInt64{Int64: n, Valid: true} }
+	tests := []struct {
+		row  columnRow
+		want string
+	}{
+		{columnRow{dataType: "TEXT", length: valid(16777216)}, "TEXT"},
+		{columnRow{dataType: "TEXT", length: valid(255)}, "VARCHAR(255)"},
+		{columnRow{dataType: "BINARY", length: valid(8388608)}, "BINARY"},
+		{columnRow{dataType: "BINARY", length: valid(16)}, "VARBINARY(16)"},
+		{columnRow{dataType: "NUMBER", precision: valid(38), scale: valid(0)}, "NUMBER(38,0)"},
+		{columnRow{dataType: "NUMBER", precision: valid(10), scale: valid(2)}, "NUMBER(10,2)"},
+		{columnRow{dataType: "TIMESTAMP_NTZ", datetimePrec: valid(9)}, "TIMESTAMP_NTZ"},
+		{columnRow{dataType: "TIMESTAMP_LTZ", datetimePrec: valid(3)}, "TIMESTAMP_LTZ(3)"},
+		{columnRow{dataType: "VARIANT"}, "VARIANT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, formatType(&tt.row))
+		})
+	}
+}
+
+func TestSetColumnType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		typ  string
+		want core.Column
+	}{
+		{"VARCHAR(255)", core.Column{Type: core.DataTypeString, PortableType: "varchar(255)"}},
+		{"TEXT", core.Column{Type: core.DataTypeString, PortableType: "text"}},
+		{"NUMBER(38,0)", core.Column{Type: core.DataTypeInt, PortableType: "bigint"}},
+		{"NUMBER(10,2)", core.Column{Type: core.DataTypeFloat, PortableType: "decimal(10,2)"}},
+		{"TIMESTAMP_TZ", core.Column{Type: core.DataTypeDatetime, PortableType: "timestamp"}},
+		{"TIMESTAMP_NTZ(3)", core.Column{Type: core.DataTypeDatetime, RawType: "TIMESTAMP_NTZ(3)"}},
+		{"VARIANT", core.Column{Type: core.DataTypeJSON, PortableType: "json"}},
+		{"VARBINARY(16)", core.Column{Type: core.DataTypeBinary, PortableType: "varbinary(16)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			t.Parallel()
+			var col core.Column
+			setColumnType(&col, tt.typ)
+			assert.Equal(t, tt.want, col)
+		})
+	}
+}
+
+func TestColumnDefault(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		expr string
+		typ  core.DataType
+		want *string
+	}{
+		{"NULL", core.DataTypeString, nil},
+		{"'active'", core.DataTypeString, new("active")},
+		{"'it''s'", core.DataTypeString, new("it's")},
+		{"'01234'", core.DataTypeString, new("'01234'")},
+		{"'5'", core.DataTypeInt, new("5")},
+		{"CURRENT_TIMESTAMP()", core.DataTypeDatetime, new("CURRENT_TIMESTAMP()")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, columnDefault(&core.Column{Type: tt.typ}, tt.expr))
+		})
+	}
+}
+
+func TestColumnRowBuild(t *testing.T) {
+	t.Parallel()
+	valid := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+	number := sql.NullInt64{Int64: 38, Valid: true}
+
+	r := &columnRow{col: &core.Column{Name: "ID"}, dataType: "NUMBER", precision: number, scale: sql.NullInt64{Valid: true},
+		nullable: "NO", identity: valid("YES"), start: valid("100"), increment: valid("1")}
+	require.NoError(t, r.build())
+	assert.Equal(t, &core.Column{Name: "ID", Type: core.DataTypeInt, PortableType: "bigint", AutoIncrement: true,
+		IdentitySeed: 100}, r.col)
+
+	r = &columnRow{col: &core.Column{Name: "ORDER_NO"}, dataType: "NUMBER", precision: number, scale: sql.NullInt64{Valid: true},
+		nullable: "YES", identity: valid("NO"), defaultExpr: valid(`"SMF"."PUBLIC"."orders_seq".NEXTVAL`),
+		comment: valid("Order number")}
+	require.NoError(t, r.build())
+	assert.Equal(t, &core.Column{Name: "ORDER_NO", Type: core.DataTypeInt, PortableType: "bigint", Nullable: true,
+		SequenceName: "orders_seq", Comment: "Order number"}, r.col)
+
+	r = &columnRow{col: &core.Column{Name: "ID"}, dataType: "NUMBER", identity: valid("YES"), start: valid("x")}
+	assert.Error(t, r.build())
+}
@@ -0,0 +1,140 @@
+package snowflake
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// referentialActions maps the update_rule and delete_rule of
+// SHOW IMPORTED KEYS to the action they stand for. NO ACTION is the
+// default and is left unset.
+var referentialActions = map[string]core.ReferentialAction{
+	"NO ACTION":   core.RefActionNone,
+	"CASCADE":     core.RefActionCascade,
+	"SET NULL":    core.RefActionSetNull,
+	"SET DEFAULT": core.RefActionSetDefault,
+	"RESTRICT":    core.RefActionRestrict,
+}
+
+// showKeys lists the SHOW commands that list the columns of the primary
+// key, unique, and foreign key constraints, which INFORMATION_SCHEMA does
+// not expose, and the column of their output that holds the schema.
+var showKeys = []struct {
+	stmt         string
+	schemaColumn string
+	typ          core.ConstraintType
+}{
+	{"SHOW PRIMARY KEYS", "schema_name", core.ConstraintPrimaryKey},
+	{"SHOW UNIQUE KEYS", "schema_name", core.ConstraintUnique},
+	{"SHOW IMPORTED KEYS", "fk_schema_name", core.ConstraintForeignKey},
+}
+
+// keyRow is a column of a constraint as listed by a SHOW command. The
+// referenced fields are only set for foreign keys.
+type keyRow struct {
+	table      string
+	name       string
+	seq        int
+	column     string
+	refTable   string
+	refColumn  string
+	deleteRule string
+	updateRule string
+}
+
+// queryConstraints reads the primary key, unique, and foreign key
+// constraints of the tables. Snowflake has no CHECK constraints, and does
+// not enforce the others, so they are read as the generator declares them.
+func queryConstraints(ic *introspectCtx, set *introspect.TableSet) error {
+	for _, show := range showKeys {
+		rows, err := queryShow(ic, show.stmt, show.schemaColumn)
+		if err != nil {
+			return err
+		}
+		keys, err := keyRows(rows, show.typ)
+		if err != nil {
+			return err
+		}
+		for _, con := range buildConstraints(keys, show.typ) {
+			table := set.Find(con.table)
+			if table == nil {
+				continue
+			}
+			table.Constraints = append(table.Constraints, con.con)
+			if show.typ == core.ConstraintPrimaryKey {
+				markPrimaryKey(table, con.con.Columns)
+			}
+		}
+	}
+	return nil
+}
+
+// keyRows reads the columns of the constraints of a type from the output
+// of its SHOW command, ordered by table, constraint, and position.
+func keyRows(rows []showRow, typ core.ConstraintType) ([]keyRow, error) {
+	keys := make([]keyRow, 0, len(rows))
+	for _, row := range rows {
+		k := keyRow{table: row["table_name"], name: row["constraint_name"], column: row["column_name"]}
+		if typ == core.ConstraintForeignKey {
+			k = keyRow{
+				table:      row["fk_table_name"],
+				name:       row["fk_name"],
+				column:     row["fk_column_name"],
+				refTable:   row["pk_table_name"],
+				refColumn:  row["pk_column_name"],
+				deleteRule: row["delete_rule"],
+				updateRule: row["update_rule"],
+			}
+		}
+		var err error
+		if k.seq, err = strconv.Atoi(row["key_sequence"]); err != nil {
+			return nil, fmt.Errorf("constraint %s: key sequence: %w", k.name, err)
+		}
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b keyRow) int {
+		return cmp.Or(cmp.Compare(a.table, b.table), cmp.Compare(a.name, b.name), cmp.Compare(a.seq, b.seq))
+	})
+	return keys, nil
+}
+
+// tableConstraint is a constraint and the table it belongs to.
+type tableConstraint struct {
+	table string
+	con   *core.Constraint
+}
+
+// buildConstraints groups ordered key columns into constraints.
+func buildConstraints(keys []keyRow, typ core.ConstraintType) []tableConstraint {
+	var cons []tableConstraint
+	for i, k := range keys {
+		if i == 0 || k.table != keys[i-1].table || k.name != keys[i-1].name {
+			con := &core.Constraint{Name: k.name, Type: typ}
+			if typ == core.ConstraintForeignKey {
+				con.ReferencedTable = k.refTable
+				con.OnDelete = referentialActions[k.deleteRule]
+				con.OnUpdate = referentialActions[k.updateRule]
+			}
+			cons = append(cons, tableConstraint{k.table, con})
+		}
+		con := cons[len(cons)-1].con
+		con.Columns = append(con.Columns, k.column)
+		if typ == core.ConstraintForeignKey {
+			con.ReferencedColumns = append(con.ReferencedColumns, k.refColumn)
+		}
+	}
+	return cons
+}
+
+func markPrimaryKey(table *core.Table, columns []string) {
+	for _, name := range columns {
+		if col := table.FindColumn(name); col != nil {
+			col.PrimaryKey = true
+		}
+	}
+}
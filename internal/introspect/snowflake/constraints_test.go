@@ -0,0 +1,44 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestBuildConstraints(t *testing.T) {
+	t.Parallel()
+	rows := []showRow{
+		{"table_name": "ORDERS", "constraint_name": "PK_ORDERS", "column_name": "LINE", "key_sequence": "2"},
+		{"table_name": "ORDERS", "constraint_name": "PK_ORDERS", "column_name": "ID", "key_sequence": "1"},
+		{"table_name": "USERS", "constraint_name": "PK_USERS", "column_name": "ID", "key_sequence": "1"},
+	}
+	keys, err := keyRows(rows, core.ConstraintPrimaryKey)
+	require.NoError(t, err)
+	assert.Equal(t, []tableConstraint{
+		{"ORDERS", &core.Constraint{Name: "PK_ORDERS", Type: core.ConstraintPrimaryKey, Columns: []string{"ID", "LINE"}}},
+		{"USERS", &core.Constraint{Name: "PK_USERS", Type: core.ConstraintPrimaryKey, Columns: []string{"ID"}}},
+	}, buildConstraints(keys, core.ConstraintPrimaryKey))
+
+	rows = []showRow{{
+		"fk_table_name": "ORDERS", "fk_name": "FK_ORDERS_USERS", "fk_column_name": "USER_ID",
+		"pk_table_name": "USERS", "pk_column_name": "ID", "key_sequence": "1",
+		"delete_rule": "CASCADE", "update_rule": "NO ACTION",
+	}}
+	keys, err = keyRows(rows, core.ConstraintForeignKey)
+	require.NoError(t, err)
+	assert.Equal(t, []tableConstraint{{"ORDERS", &core.Constraint{
+		Name:              "FK_ORDERS_USERS",
+		Type:              core.ConstraintForeignKey,
+		Columns:           []string{"USER_ID"},
+		ReferencedTable:   "USERS",
+		ReferencedColumns: []string{"ID"},
+		OnDelete:          core.RefActionCascade,
+	}}}, buildConstraints(keys, core.ConstraintForeignKey))
+
+	_, err = keyRows([]showRow{{"key_sequence": ""}}, core.ConstraintUnique)
+	assert.Error(t, err)
+}
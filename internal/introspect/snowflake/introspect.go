@@ -1,3 +1,7 @@
+// Package snowflake contains introspect implementation for Snowflake. It reads
+// the tables of the current schema from INFORMATION_SCHEMA, and the table
+// options, key columns, and views that INFORMATION_SCHEMA does not fully
+// expose from the output of SHOW commands.
 package snowflake
 
 import (
@@ -14,10 +18,44 @@ func init() {
 
 type introspecter struct{}
 
+type introspectCtx struct {
+	db  *sql.DB
+	ctx context.Context
+
+	// schema is the current schema. SHOW commands list the objects of the
+	// whole database, and their rows are filtered by it.
+	schema string
+}
+
 func New() introspect.Introspecter {
 	return &introspecter{}
 }
 
-func (i *introspecter) Introspect(_ context.Context, _ *sql.DB) (*core.Database, error) {
-	return nil, nil
+func (i *introspecter) Introspect(ctx context.Context, db *sql.DB) (*core.Database, error) {
+	d := &core.Database{Dialect: core.DialectSnowflake}
+	ic := &introspectCtx{
+		db:  db,
+		ctx: ctx,
+	}
+
+	query := `
+        SELECT CURRENT_DATABASE(), CURRENT_SCHEMA()
+    `
+	err := db.QueryRowContext(ctx, query).Scan(&d.Name, &ic.schema)
+	if err != nil {
+		return nil, err
+	}
+
+	err = introspectTables(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = introspectViews(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	introspect.FoldNames(d)
+	return d, nil
 }
@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// showRow is a row of the output of a SHOW command, by lower-case column
+// name. The columns of that output vary between Snowflake releases, so rows
+// are read by name and a column that is missing reads as "".
+type showRow map[string]string
+
+// queryShow runs a SHOW command and returns the rows whose schemaColumn
+// names the current schema.
+func queryShow(ic *introspectCtx, stmt, schemaColumn string) ([]showRow, error) {
+	rows, err := ic.db.QueryContext(ic.ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]sql.NullString, len(names))
+	dest := make([]any, len(names))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var result []showRow
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row := make(showRow, len(names))
+		for i, name := range names {
+			row[strings.ToLower(name)] = values[i].String
+		}
+		if row[schemaColumn] == ic.schema {
+			result = append(result, row)
+		}
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,185 @@
+package snowflake
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/introspect"
+)
+
+// tableFilter selects the permanent and transient tables of the current
+// schema from INFORMATION_SCHEMA.TABLES t. Views, external tables, and
+// temporary tables have other types.
+const tableFilter = `t.TABLE_SCHEMA = CURRENT_SCHEMA()
+        AND t.TABLE_TYPE = 'BASE TABLE'`
+
+// defaultRetentionDays is the Time Travel retention period of an account
+// that does not set DATA_RETENTION_TIME_IN_DAYS. Tables that keep it are
+// written without a retention period.
+const defaultRetentionDays = 1
+
+var (
+	// linearRe matches the clustering key of a table as SHOW TABLES lists it:
+	// "LINEAR(region, TO_DATE(created_at))".
+	linearRe = regexp.MustCompile(`(?is)^LINEAR\s*\((.*)\)$`)
+
+	// quotedNameRe matches a key that is only a quoted name that needs no
+	// quotes, which the generator quotes itself.
+	quotedNameRe = regexp.MustCompile(`^"([A-Za-z_][A-Za-z0-9_$]*)"$`)
+)
+
+func introspectTables(ic *introspectCtx, db *core.Database) error {
+	set, err := queryTables(ic)
+	if err != nil {
+		return fmt.Errorf("query tables: %w", err)
+	}
+	if len(set.Tables) == 0 {
+		return nil
+	}
+
+	if err := queryTableOptions(ic, set); err != nil {
+		return fmt.Errorf("query table options: %w", err)
+	}
+	if err := queryColumns(ic, set); err != nil {
+		return fmt.Errorf("query columns: %w", err)
+	}
+	if err := queryConstraints(ic, set); err != nil {
+		return fmt.Errorf("query constraints: %w", err)
+	}
+
+	db.Tables = append(db.Tables, set.Tables...)
+	return nil
+}
+
+func queryTables(ic *introspectCtx) (*introspect.TableSet, error) {
+	query := `
+        SELECT t.TABLE_NAME, t.COMMENT
+        FROM INFORMATION_SCHEMA.TABLES t
+        WHERE ` + tableFilter + `
+        ORDER BY t.TABLE_NAME
+    `
+	rows, err := ic.db.QueryContext(ic.ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set := introspect.NewTableSet()
+	for rows.Next() {
+		table := &core.Table{
+			Columns:     make([]*core.Column, 0),
+			Constraints: make([]*core.Constraint, 0),
+			Indexes:     make([]*core.Index, 0),
+		}
+		var comment sql.NullString
+		if err := rows.Scan(&table.Name, &comment); err != nil {
+			return nil, err
+		}
+		table.Comment = comment.String
+		set.Add(table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// queryTableOptions sets the Snowflake options of the tables from the
+// output of SHOW TABLES. Tables that SHOW TABLES lists but the tables query
+// did not read, such as temporary tables, are skipped. COPY GRANTS only
+// applies to the statement that creates a table and cannot be read.
+func queryTableOptions(ic *introspectCtx, set *introspect.TableSet) error {
+	rows, err := queryShow(ic, "SHOW TABLES", "schema_name")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		table := set.Find(row["name"])
+		if table == nil {
+			continue
+		}
+		opts, err := tableOptions(row)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", table.Name, err)
+		}
+		table.Options.Snowflake = opts
+	}
+	return nil
+}
+
+// tableOptions returns the Snowflake options of a table listed by
+// SHOW TABLES, or nil when the table has none.
+func tableOptions(row showRow) (*core.SnowflakeTableOptions, error) {
+	opts := &core.SnowflakeTableOptions{
+		ClusterBy:      clusterKeys(row["cluster_by"]),
+		ChangeTracking: row["change_tracking"] == "ON",
+		Transient:      row["kind"] == "TRANSIENT",
+	}
+	if v := row["retention_time"]; v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("retention time: %w", err)
+		}
+		if days != defaultRetentionDays {
+			opts.DataRetentionDays = new(days)
+		}
+	}
+	if len(opts.ClusterBy) == 0 && opts.DataRetentionDays == nil && !opts.ChangeTracking && !opts.Transient {
+		return nil, nil
+	}
+	return opts, nil
+}
+
+// clusterKeys splits a clustering key into its columns and expressions.
+//
+// Example input: `LINEAR("region", TO_DATE(created_at))`.
+func clusterKeys(clusterBy string) []string {
+	m := linearRe.FindStringSubmatch(strings.TrimSpace(clusterBy))
+	if m == nil {
+		return nil
+	}
+	var keys []string
+	for _, key := range splitList(m[1]) {
+		if q := quotedNameRe.FindStringSubmatch(key); q != nil {
+			key = q[1]
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// splitList splits a comma-separated list at the commas that are not
+// inside parentheses, string literals, or quoted identifiers, and trims
+// the items.
+func splitList(s string) []string {
+	var items []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" || len(items) > 0 {
+		items = append(items, last)
+	}
+	return items
+}
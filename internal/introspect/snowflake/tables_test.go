@@ -0,0 +1,53 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestTableOptions(t *testing.T) {
+	t.Parallel()
+	opts, err := tableOptions(showRow{"kind": "TABLE", "retention_time": "1", "change_tracking": "OFF"})
+	require.NoError(t, err)
+	assert.Nil(t, opts)
+
+	opts, err = tableOptions(showRow{
+		"kind":            "TRANSIENT",
+		"cluster_by":      `LINEAR("region", TO_DATE(created_at))`,
+		"retention_time":  "0",
+		"change_tracking": "ON",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &core.SnowflakeTableOptions{
+		ClusterBy:         []string{"region", "TO_DATE(created_at)"},
+		DataRetentionDays: new(0),
+		ChangeTracking:    true,
+		Transient:         true,
+	}, opts)
+
+	_, err = tableOptions(showRow{"retention_time": "x"})
+	assert.Error(t, err)
+}
+
+func TestClusterKeys(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		clusterBy string
+		want      []string
+	}{
+		{"", nil},
+		{"LINEAR(ID)", []string{"ID"}},
+		{`LINEAR("order id", SUBSTRING(sku, 1, 3))`, []string{`"order id"`, "SUBSTRING(sku, 1, 3)"}},
+		{`linear(region, 'a,b')`, []string{"region", "'a,b'"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.clusterBy, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, clusterKeys(tt.clusterBy))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package snowflake
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// createViewRe matches the head of a CREATE VIEW statement as SHOW VIEWS
+// lists it, up to the AS before the query. The optional group is the
+// column list. The clauses between the name and AS, such as COMMENT and
+// COPY GRANTS, are skipped, along with the strings they contain.
+var createViewRe = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?(?:SECURE\s+)?(?:RECURSIVE\s+)?` +
+	`(?:MATERIALIZED\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?(?:(?:"(?:[^"]|"")+"|[\w$]+)\.){0,2}(?:"(?:[^"]|"")+"|[\w$]+)\s*` +
+	`(?:\(([^)]*)\))?(?:[^']|'(?:[^']|'')*')*?\bAS\b\s*`)
+
+func introspectViews(ic *introspectCtx, db *core.Database) error {
+	rows, err := queryShow(ic, "SHOW VIEWS", "schema_name")
+	if err != nil {
+		return fmt.Errorf("query views: %w", err)
+	}
+	for _, row := range rows {
+		v, err := viewFromShow(row)
+		if err != nil {
+			return err
+		}
+		db.Views = append(db.Views, v)
+	}
+	return nil
+}
+
+// viewFromShow converts a view listed by SHOW VIEWS into a core.View. The
+// text column holds the CREATE statement of the view as it was written.
+// Snowflake views are read-only and run with the privileges of their owner,
+// and materialized views are maintained in the background, so the check
+// option, security, and refresh policy are left unset.
+func viewFromShow(row showRow) (*core.View, error) {
+	text := row["text"]
+	m := createViewRe.FindStringSubmatchIndex(text)
+	if m == nil {
+		return nil, fmt.Errorf("view %s: unexpected definition", row["name"])
+	}
+	v := &core.View{
+		Name:         row["name"],
+		Definition:   strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text[m[1]:]), ";")),
+		Materialized: row["is_materialized"] == "true",
+		Comment:      row["comment"],
+	}
+	if m[2] >= 0 {
+		for _, item := range splitList(text[m[2]:m[3]]) {
+			v.Columns = append(v.Columns, viewColumn(item))
+		}
+	}
+	return v, nil
+}
+
+// viewColumn returns the name of a column of the column list of a view,
+// without the COMMENT that may follow it. Unquoted names are stored in
+// upper case.
+func viewColumn(item string) string {
+	if strings.HasPrefix(item, `"`) {
+		for end := 1; end < len(item); end++ {
+			if item[end] != '"' {
+				continue
+			}
+			if end+1 < len(item) && item[end+1] == '"' {
+				end++
+				continue
+			}
+			return strings.ReplaceAll(item[1:end], `""`, `"`)
+		}
+	}
+	if fields := strings.Fields(item); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+	return item
+}
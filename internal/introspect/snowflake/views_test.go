@@ -0,0 +1,65 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestViewFromShow(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		row  showRow
+		want *core.View
+	}{
+		{
+			name: "plain view",
+			row: showRow{
+				"name":            "ACTIVE_USERS",
+				"text":            "create or replace view ACTIVE_USERS as select id from users where active;",
+				"is_materialized": "false",
+			},
+			want: &core.View{Name: "ACTIVE_USERS", Definition: "select id from users where active"},
+		},
+		{
+			name: "columns and comment",
+			row: showRow{
+				"name": "BIG_ORDERS",
+				"text": "CREATE VIEW APP.PUBLIC.\"BIG_ORDERS\" (id, \"Total \"\"USD\"\"\" COMMENT 'sum') " +
+					"COMMENT = 'orders as of today' COPY GRANTS AS\nSELECT id, total FROM orders",
+				"comment":         "orders as of today",
+				"is_materialized": "false",
+			},
+			want: &core.View{
+				Name:       "BIG_ORDERS",
+				Definition: "SELECT id, total FROM orders",
+				Columns:    []string{"ID", `Total "USD"`},
+				Comment:    "orders as of today",
+			},
+		},
+		{
+			name: "materialized view",
+			row: showRow{
+				"name":            "ORDER_STATS",
+				"text":            "create materialized view order_stats as select count(*) n from orders",
+				"is_materialized": "true",
+			},
+			want: &core.View{Name: "ORDER_STATS", Definition: "select count(*) n from orders", Materialized: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := viewFromShow(tt.row)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+
+	_, err := viewFromShow(showRow{"name": "V", "text": "select 1"})
+	require.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// portableTypes lists the declared types that are written as a portable
+// type, and whether the portable type takes arguments. Every other type is
+// kept as a raw type.
+var portableTypes = map[string]bool{
+	"varchar": true, "char": true, "decimal": true,
+	"text": false, "smallint": false, "int": false, "bigint": false, "float": false, "double": false, "boolean": false,
+	"date": false, "time": false, "timestamp": false, "datetime": false, "json": false, "uuid": false, "blob": false,
+}
+
+// Values of the hidden column of PRAGMA table_xinfo.
+const (
+	hiddenVirtualTable = 1 // Hidden column of a virtual table.
+	generatedVirtual   = 2 // VIRTUAL generated column.
+	generatedStored    = 3 // STORED generated column.
+)
+
+// columnRow holds a row of PRAGMA table_xinfo.
+type columnRow struct {
+	col      *core.Column
+	declared string
+	notNull  bool
+	dflt     sql.NullString
+	pk       int
+	hidden   int
+}
+
+func queryColumns(ic *introspectCtx, table string) ([]*columnRow, error) {
+	query := `
+        SELECT name, type, "notnull", dflt_value, pk, hidden
+        FROM pragma_table_xinfo(?)
+        ORDER BY cid
+    `
+	rows, err := ic.conn.QueryContext(ic.ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []*columnRow
+	for rows.Next() {
+		r := &columnRow{col: &core.Column{}}
+		if err := rows.Scan(&r.col.Name, &r.declared, &r.notNull, &r.dflt, &r.pk, &r.hidden); err != nil {
+			return nil, err
+		}
+		if r.hidden != hiddenVirtualTable {
+			columns = append(columns, r)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// build fills col from the row and from the column definition in the
+// CREATE TABLE statement.
+func (r *columnRow) build(ddl *columnDDL) {
+	col := r.col
+	setColumnType(col, r.declared)
+	col.Nullable = !r.notNull
+	col.Collate = ddl.collate
+
+	switch {
+	case r.hidden == generatedVirtual || r.hidden == generatedStored:
+		col.IsGenerated = true
+		col.GenerationExpression = ddl.generated
+		col.GenerationStorage = core.GenerationVirtual
+		if r.hidden == generatedStored {
+			col.GenerationStorage = core.GenerationStored
+		}
+	case r.dflt.Valid:
+		col.DefaultValue = columnDefault(col, r.dflt.String)
+	}
+}
+
+// setColumnType sets the type of col from its declared type. INTEGER is
+// written as int, and types that the portable type names describe exactly
+// are written as portable types. A column declared without a type has the
+// affinity of BLOB.
+func setColumnType(col *core.Column, declared string) {
+	if declared == "" {
+		col.RawType = "BLOB"
+		col.Type = core.DataTypeBinary
+		return
+	}
+
+	typ := strings.ToLower(declared)
+	if typ == "integer" {
+		typ = "int"
+	}
+	base, _, hasArgs := strings.Cut(typ, "(")
+	col.Type = core.NormalizeDataType(typ)
+	if takesArgs, ok := portableTypes[strings.TrimSpace(base)]; ok && takesArgs == hasArgs {
+		col.PortableType = typ
+	} else {
+		col.RawType = declared
+	}
+}
+
+// columnDefault returns the default value for a default as written in the
+// CREATE TABLE statement. A string literal is stored unquoted, as it is
+// written in the schema, unless it would then read as something else: a
+// number is only unquoted for numeric columns, and keywords and
+// expressions keep their quotes. SQLite has no boolean literals, so the 0
+// and 1 of boolean columns are written as FALSE and TRUE.
+//
+// Example input: "'active'", "0", "CURRENT_TIMESTAMP", "(datetime('now'))".
+func columnDefault(col *core.Column, v string) *string {
+	switch {
+	case strings.EqualFold(v, "NULL"):
+		return nil
+	case col.Type == core.DataTypeBoolean && (v == "0" || v == "1" || generate.ClassifyDefault(v) == generate.DefaultBoolean):
+		return new(strings.ToUpper(strconv.FormatBool(generate.BoolDefault(v))))
+	}
+
+	tokens, err := tokenize(v)
+	if err != nil || len(tokens) != 1 || tokens[0].kind != tokenString {
+		return &v
+	}
+	s := tokens[0].text
+	switch generate.ClassifyDefault(s) {
+	case generate.DefaultString:
+		return &s
+	case generate.DefaultNumber:
+		if col.Type == core.DataTypeInt || col.Type == core.DataTypeFloat {
+			return &s
+		}
+	}
+	return &v
+}
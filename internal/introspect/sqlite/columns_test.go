@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestSetColumnType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		declared string
+		want     core.Column
+	}{
+		{"INTEGER", core.Column{Type: core.DataTypeInt, PortableType: "int"}},
+		{"TEXT", core.Column{Type: core.DataTypeString, PortableType: "text"}},
+		{"VARCHAR(255)", core.Column{Type: core.DataTypeString, PortableType: "varchar(255)"}},
+		{"VARCHAR", core.Column{Type: core.DataTypeString, RawType: "VARCHAR"}},
+		{"BOOLEAN", core.Column{Type: core.DataTypeBoolean, PortableType: "boolean"}},
+		{"REAL", core.Column{Type: core.DataTypeFloat, RawType: "REAL"}},
+		{"", core.Column{Type: core.DataTypeBinary, RawType: "BLOB"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.declared, func(t *testing.T) {
+			t.Parallel()
+			var col core.Column
+			setColumnType(&col, tt.declared)
+			assert.Equal(t, tt.want, col)
+		})
+	}
+}
+
+func TestColumnDefault(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		v    string
+		typ  core.DataType
+		want *string
+	}{
+		{"NULL", core.DataTypeString, nil},
+		{"'active'", core.DataTypeString, new("active")},
+		{"'it''s'", core.DataTypeString, new("it's")},
+		{"'01234'", core.DataTypeString, new("'01234'")},
+		{"'5'", core.DataTypeInt, new("5")},
+		{"-1", core.DataTypeInt, new("-1")},
+		{"1", core.DataTypeBoolean, new("TRUE")},
+		{"0", core.DataTypeBoolean, new("FALSE")},
+		{"CURRENT_TIMESTAMP", core.DataTypeDatetime, new("CURRENT_TIMESTAMP")},
+		{"(datetime('now'))", core.DataTypeDatetime, new("(datetime('now'))")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.v, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, columnDefault(&core.Column{Type: tt.typ}, tt.v))
+		})
+	}
+}
+
+func TestColumnRowBuild(t *testing.T) {
+	t.Parallel()
+	r := &columnRow{col: &core.Column{Name: "total"}, declared: "REAL", hidden: generatedStored}
+	r.build(&columnDDL{generated: "price * 2", storage: core.GenerationStored})
+	assert.Equal(t, &core.Column{Name: "total", Type: core.DataTypeFloat, RawType: "REAL", Nullable: true,
+		IsGenerated: true, GenerationExpression: "price * 2", GenerationStorage: core.GenerationStored}, r.col)
+
+	r = &columnRow{col: &core.Column{Name: "code"}, declared: "TEXT", notNull: true, dflt: sql.NullString{String: "'x'", Valid: true}}
+	r.build(&columnDDL{collate: "NOCASE"})
+	assert.Equal(t, &core.Column{Name: "code", Type: core.DataTypeString, PortableType: "text", Collate: "NOCASE",
+		DefaultValue: new("x")}, r.col)
+}
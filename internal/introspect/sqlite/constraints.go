@@ -0,0 +1,174 @@
+package sqlite
+
+import (
+	"database/sql"
+	"slices"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// addPrimaryKey adds the primary key of a table, named as it is declared.
+// The single INTEGER PRIMARY KEY column of a rowid table is an alias for the
+// rowid, and is written as auto-increment.
+func addPrimaryKey(table *core.Table, ddl *tableDDL, columns []*columnRow) {
+	var pk []*columnRow
+	for _, c := range columns {
+		if c.pk > 0 {
+			pk = append(pk, c)
+		}
+	}
+	if len(pk) == 0 {
+		return
+	}
+	slices.SortFunc(pk, func(a, b *columnRow) int { return a.pk - b.pk })
+
+	con := &core.Constraint{Type: core.ConstraintPrimaryKey}
+	for _, c := range pk {
+		c.col.PrimaryKey = true
+		con.Columns = append(con.Columns, c.col.Name)
+	}
+	if d := ddl.constraint(core.ConstraintPrimaryKey, con.Columns); d != nil {
+		con.Name = d.name
+	}
+	table.Constraints = append(table.Constraints, con)
+
+	if len(pk) == 1 && !ddl.withoutRowid && strings.EqualFold(pk[0].declared, "INTEGER") {
+		pk[0].col.AutoIncrement = true
+		if ddl.column(pk[0].col.Name).autoincrement {
+			pk[0].col.SQLite = &core.SQLiteColumnOptions{StrictAutoincrement: true}
+		}
+	}
+}
+
+// foreignKeyRow holds a row of PRAGMA foreign_key_list, one for every
+// column of a foreign key.
+type foreignKeyRow struct {
+	id       int
+	table    string
+	from     string
+	to       sql.NullString
+	onUpdate string
+	onDelete string
+}
+
+// queryForeignKeys adds the foreign keys of a table, named as they are
+// declared. A foreign key that references the primary key without naming
+// its columns has no referenced columns until resolveReferences.
+func queryForeignKeys(ic *introspectCtx, table *core.Table, ddl *tableDDL) error {
+	query := `
+        SELECT id, "table", "from", "to", on_update, on_delete
+        FROM pragma_foreign_key_list(?)
+        ORDER BY id, seq
+    `
+	rows, err := ic.conn.QueryContext(ic.ctx, query, table.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var con *core.Constraint
+	id := -1
+	for rows.Next() {
+		var r foreignKeyRow
+		if err := rows.Scan(&r.id, &r.table, &r.from, &r.to, &r.onUpdate, &r.onDelete); err != nil {
+			return err
+		}
+		if r.id != id {
+			id = r.id
+			con = &core.Constraint{
+				Type:            core.ConstraintForeignKey,
+				ReferencedTable: r.table,
+				OnDelete:        referentialAction(r.onDelete),
+				OnUpdate:        referentialAction(r.onUpdate),
+			}
+			table.Constraints = append(table.Constraints, con)
+		}
+		con.Columns = append(con.Columns, r.from)
+		if r.to.Valid {
+			con.ReferencedColumns = append(con.ReferencedColumns, r.to.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, con := range table.Constraints {
+		if con.Type != core.ConstraintForeignKey {
+			continue
+		}
+		if d := ddl.constraint(con.Type, con.Columns); d != nil {
+			con.Name = d.name
+		}
+	}
+	return nil
+}
+
+// referentialAction returns the action PRAGMA foreign_key_list reports.
+// NO ACTION is the default and is left unset.
+func referentialAction(action string) core.ReferentialAction {
+	if ra := core.ReferentialAction(strings.ToUpper(action)); ra != core.RefActionNoAction {
+		return ra
+	}
+	return core.RefActionNone
+}
+
+// addChecks adds the CHECK constraints of a table. The unnamed check that
+// limits a text column to a list of strings, as the generator declares a
+// portable enum, makes the column an enum instead.
+func addChecks(table *core.Table, ddl *tableDDL) {
+	for _, d := range ddl.constraints {
+		if d.typ != core.ConstraintCheck {
+			continue
+		}
+		con := &core.Constraint{Name: d.name, Type: core.ConstraintCheck, CheckExpression: d.check}
+		if d.inline {
+			col := table.FindColumn(d.columns[0])
+			if col != nil && d.name == "" && setEnum(col, d.check) {
+				continue
+			}
+			con.Columns = d.columns
+		}
+		table.Constraints = append(table.Constraints, con)
+	}
+}
+
+// setEnum makes a text column an enum when check is "column IN ('a', ...)".
+func setEnum(col *core.Column, check string) bool {
+	if col.Type != core.DataTypeString {
+		return false
+	}
+	values, ok := enumValues(col.Name, check)
+	if !ok {
+		return false
+	}
+	col.Type = core.DataTypeEnum
+	col.EnumValues = values
+	col.PortableType = core.BuildEnumTypeRaw(values)
+	col.RawType = ""
+	return true
+}
+
+// enumValues returns the strings of a check of the form "column IN ('a',
+// 'b')" on the given column.
+func enumValues(column, check string) ([]string, bool) {
+	tokens, err := tokenize(check)
+	if err != nil || len(tokens) != 3 || tokens[1].keyword() != "IN" || tokens[2].kind != tokenGroup {
+		return nil, false
+	}
+	if name, err := tokens[0].name(); err != nil || tokens[0].kind == tokenString || !strings.EqualFold(name, column) {
+		return nil, false
+	}
+	list, err := tokenize(unwrapGroup(tokens[2].text))
+	if err != nil {
+		return nil, false
+	}
+	var values []string
+	for _, item := range splitTokens(list) {
+		if len(item) != 1 || item[0].kind != tokenString {
+			return nil, false
+		}
+		values = append(values, item[0].text)
+	}
+	return values, true
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestAddPrimaryKey(t *testing.T) {
+	t.Parallel()
+	ddl, err := parseCreateTable("CREATE TABLE t (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)")
+	require.NoError(t, err)
+	id := &columnRow{col: &core.Column{Name: "id"}, declared: "INTEGER", pk: 1}
+	table := &core.Table{Name: "t"}
+	addPrimaryKey(table, ddl, []*columnRow{id, {col: &core.Column{Name: "name"}, declared: "TEXT"}})
+
+	assert.Equal(t, []*core.Constraint{{Type: core.ConstraintPrimaryKey, Columns: []string{"id"}}}, table.Constraints)
+	assert.True(t, id.col.PrimaryKey)
+	assert.True(t, id.col.AutoIncrement)
+	assert.Equal(t, &core.SQLiteColumnOptions{StrictAutoincrement: true}, id.col.SQLite)
+}
+
+func TestAddPrimaryKeyComposite(t *testing.T) {
+	t.Parallel()
+	ddl, err := parseCreateTable("CREATE TABLE t (a INTEGER, b INTEGER, CONSTRAINT pk_t PRIMARY KEY (b, a)) WITHOUT ROWID")
+	require.NoError(t, err)
+	a := &columnRow{col: &core.Column{Name: "a"}, declared: "INTEGER", pk: 2}
+	b := &columnRow{col: &core.Column{Name: "b"}, declared: "INTEGER", pk: 1}
+	table := &core.Table{Name: "t"}
+	addPrimaryKey(table, ddl, []*columnRow{a, b})
+
+	assert.Equal(t, []*core.Constraint{{Name: "pk_t", Type: core.ConstraintPrimaryKey, Columns: []string{"b", "a"}}}, table.Constraints)
+	assert.False(t, a.col.AutoIncrement)
+}
+
+func TestAddChecks(t *testing.T) {
+	t.Parallel()
+	ddl, err := parseCreateTable(`CREATE TABLE t (
+  status TEXT CHECK ("status" IN ('new', 'paid')),
+  qty INTEGER CONSTRAINT chk_t_qty CHECK (qty > 0),
+  kind TEXT CHECK (kind IN ('a', upper('b'))),
+  CHECK (qty < 100)
+)`)
+	require.NoError(t, err)
+	table := &core.Table{Name: "t", Columns: []*core.Column{
+		{Name: "status", Type: core.DataTypeString, PortableType: "text"},
+		{Name: "qty", Type: core.DataTypeInt, PortableType: "int"},
+		{Name: "kind", Type: core.DataTypeString, PortableType: "text"},
+	}}
+	addChecks(table, ddl)
+
+	assert.Equal(t, &core.Column{Name: "status", Type: core.DataTypeEnum, PortableType: "enum('new','paid')",
+		EnumValues: []string{"new", "paid"}}, table.Columns[0])
+	assert.Equal(t, []*core.Constraint{
+		{Name: "chk_t_qty", Type: core.ConstraintCheck, Columns: []string{"qty"}, CheckExpression: "qty > 0"},
+		{Type: core.ConstraintCheck, Columns: []string{"kind"}, CheckExpression: "kind IN ('a', upper('b'))"},
+		{Type: core.ConstraintCheck, CheckExpression: "qty < 100"},
+	}, table.Constraints)
+}
+
+func TestReferentialAction(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, core.RefActionNone, referentialAction("NO ACTION"))
+	assert.Equal(t, core.RefActionCascade, referentialAction("CASCADE"))
+	assert.Equal(t, core.RefActionSetNull, referentialAction("SET NULL"))
+}
+
+func TestResolveReferences(t *testing.T) {
+	t.Parallel()
+	fk := &core.Constraint{Type: core.ConstraintForeignKey, Columns: []string{"user_id"}, ReferencedTable: "Users"}
+	tables := []*core.Table{
+		{Name: "orders", Constraints: []*core.Constraint{fk}},
+		{Name: "users", Constraints: []*core.Constraint{{Type: core.ConstraintPrimaryKey, Columns: []string{"id"}}}},
+	}
+	resolveReferences(tables)
+	assert.Equal(t, []string{"id"}, fk.ReferencedColumns)
+}
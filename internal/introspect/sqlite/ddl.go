@@ -0,0 +1,405 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// tableDDL holds what the CREATE TABLE statement of a table declares beyond
+// what the PRAGMA functions report: CHECK constraints, generated columns,
+// collations, constraint names, and the table options.
+type tableDDL struct {
+	columns      map[string]*columnDDL // Keyed by lower-cased column name.
+	constraints  []*constraintDDL
+	withoutRowid bool
+	strict       bool
+}
+
+// columnDDL holds the parts of a column definition that the PRAGMA
+// functions do not report.
+type columnDDL struct {
+	collate       string
+	autoincrement bool
+	generated     string
+	storage       core.GenerationStorage
+}
+
+// constraintDDL is a table constraint, or a constraint declared inline on
+// the column in columns.
+type constraintDDL struct {
+	name    string
+	typ     core.ConstraintType
+	columns []string
+	check   string
+	inline  bool
+}
+
+// column returns the parsed definition of a column, or an empty one for a
+// column the statement does not declare.
+func (d *tableDDL) column(name string) *columnDDL {
+	if c, ok := d.columns[strings.ToLower(name)]; ok {
+		return c
+	}
+	return &columnDDL{}
+}
+
+// constraint returns the declared constraint of the given type on exactly
+// the given columns, or nil.
+func (d *tableDDL) constraint(typ core.ConstraintType, columns []string) *constraintDDL {
+	for _, con := range d.constraints {
+		if con.typ == typ && sameColumns(con.columns, columns) {
+			return con
+		}
+	}
+	return nil
+}
+
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCreateTable parses a CREATE TABLE statement as stored in
+// sqlite_schema.
+//
+// Example input: "CREATE TABLE t (id INTEGER PRIMARY KEY, CHECK (id > 0)) STRICT".
+func parseCreateTable(stmt string) (*tableDDL, error) {
+	tokens, err := tokenize(stmt)
+	if err != nil {
+		return nil, err
+	}
+	body := -1
+	for i, t := range tokens {
+		if t.kind == tokenGroup {
+			body = i
+			break
+		}
+	}
+	if body < 0 {
+		return nil, fmt.Errorf("no column definitions in %q", stmt)
+	}
+
+	d := &tableDDL{columns: make(map[string]*columnDDL)}
+	items, err := tokenize(unwrapGroup(tokens[body].text))
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range splitTokens(items) {
+		if err := d.parseItem(item); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.parseOptions(&tokenReader{tokens: tokens[body+1:]}); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// tableConstraintKeywords start a table constraint rather than a column
+// definition.
+var tableConstraintKeywords = map[string]bool{"CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true, "CHECK": true, "FOREIGN": true}
+
+func (d *tableDDL) parseItem(item []token) error {
+	if len(item) == 0 {
+		return fmt.Errorf("empty item in column definitions")
+	}
+	if tableConstraintKeywords[item[0].keyword()] {
+		con, err := parseTableConstraint(&tokenReader{tokens: item})
+		if err != nil {
+			return fmt.Errorf("constraint %s: %w", con.name, err)
+		}
+		d.constraints = append(d.constraints, con)
+		return nil
+	}
+
+	name, err := item[0].name()
+	if err != nil {
+		return err
+	}
+	col := &columnDDL{}
+	d.columns[strings.ToLower(name)] = col
+	p := &columnParser{tokenReader: tokenReader{tokens: item, pos: 1}, ddl: d, name: name, col: col}
+	if err := p.parse(); err != nil {
+		return fmt.Errorf("column %s: %w", name, err)
+	}
+	return nil
+}
+
+// parseOptions parses the table options after the column definitions.
+func (d *tableDDL) parseOptions(r *tokenReader) error {
+	for !r.done() {
+		t := r.next()
+		switch t.keyword() {
+		case "STRICT":
+			d.strict = true
+		case "WITHOUT":
+			if err := r.expect("ROWID"); err != nil {
+				return err
+			}
+			d.withoutRowid = true
+		default:
+			if t.kind != tokenSymbol || t.text != "," {
+				return fmt.Errorf("unexpected %q after column definitions", t.text)
+			}
+		}
+	}
+	return nil
+}
+
+// parseTableConstraint parses a table constraint. The conflict clauses and
+// the referenced table of a foreign key are read from the PRAGMA functions.
+func parseTableConstraint(r *tokenReader) (*constraintDDL, error) {
+	con := &constraintDDL{}
+	if r.accept("CONSTRAINT") {
+		name, err := r.next().name()
+		if err != nil {
+			return con, err
+		}
+		con.name = name
+	}
+
+	var err error
+	switch k := r.next().keyword(); k {
+	case "PRIMARY", "FOREIGN":
+		con.typ = core.ConstraintForeignKey
+		if k == "PRIMARY" {
+			con.typ = core.ConstraintPrimaryKey
+		}
+		if err = r.expect("KEY"); err == nil {
+			con.columns, err = groupColumns(r)
+		}
+	case "UNIQUE":
+		con.typ = core.ConstraintUnique
+		con.columns, err = groupColumns(r)
+	case "CHECK":
+		con.typ = core.ConstraintCheck
+		var group string
+		if group, err = r.group(); err == nil {
+			con.check = groupExpression(group)
+		}
+	default:
+		err = fmt.Errorf("unknown constraint %q", k)
+	}
+	return con, err
+}
+
+// groupColumns reads the parenthesized column list of a constraint. A
+// column may be followed by COLLATE and a sort order, which are dropped.
+func groupColumns(r *tokenReader) ([]string, error) {
+	group, err := r.group()
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenize(unwrapGroup(group))
+	if err != nil {
+		return nil, err
+	}
+	var columns []string
+	for _, item := range splitTokens(tokens) {
+		if len(item) == 0 {
+			return nil, fmt.Errorf("empty column in %s", group)
+		}
+		name, err := item[0].name()
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+	return columns, nil
+}
+
+// columnParser parses the constraints of a column definition. Its type,
+// nullability, and default are read from PRAGMA table_xinfo.
+type columnParser struct {
+	tokenReader
+	ddl     *tableDDL
+	name    string
+	col     *columnDDL
+	pending string // Name given by CONSTRAINT to the next constraint.
+}
+
+// columnClauses maps the keyword that starts a column constraint or clause
+// to its parser. The other words of a column definition are skipped.
+var columnClauses = map[string]func(*columnParser) error{
+	"CONSTRAINT":    func(p *columnParser) (err error) { p.pending, err = p.next().name(); return err },
+	"PRIMARY":       func(p *columnParser) error { p.addConstraint(core.ConstraintPrimaryKey, ""); return nil },
+	"UNIQUE":        func(p *columnParser) error { p.addConstraint(core.ConstraintUnique, ""); return nil },
+	"CHECK":         (*columnParser).parseCheck,
+	"REFERENCES":    (*columnParser).parseReferences,
+	"COLLATE":       func(p *columnParser) (err error) { p.col.collate, err = p.next().name(); return err },
+	"DEFAULT":       (*columnParser).skipDefault,
+	"AS":            (*columnParser).parseGenerated,
+	"STORED":        func(p *columnParser) error { p.col.storage = core.GenerationStored; return nil },
+	"VIRTUAL":       func(p *columnParser) error { p.col.storage = core.GenerationVirtual; return nil },
+	"AUTOINCREMENT": func(p *columnParser) error { p.col.autoincrement = true; return nil },
+}
+
+func (p *columnParser) parse() error {
+	for !p.done() {
+		t := p.next()
+		parse, ok := columnClauses[t.keyword()]
+		if !ok {
+			continue
+		}
+		if err := parse(p); err != nil {
+			return fmt.Errorf("%s: %w", t.keyword(), err)
+		}
+	}
+	return nil
+}
+
+func (p *columnParser) parseCheck() error {
+	group, err := p.group()
+	if err != nil {
+		return err
+	}
+	p.addConstraint(core.ConstraintCheck, groupExpression(group))
+	return nil
+}
+
+func (p *columnParser) addConstraint(typ core.ConstraintType, check string) {
+	p.ddl.constraints = append(p.ddl.constraints, &constraintDDL{
+		name: p.pending, typ: typ, columns: []string{p.name}, check: check, inline: true,
+	})
+	p.pending = ""
+}
+
+// parseReferences records an inline foreign key and skips the referenced
+// table and columns and the ON and MATCH clauses, so that SET DEFAULT is not
+// read as a default. The rest is read from PRAGMA foreign_key_list.
+func (p *columnParser) parseReferences() error {
+	p.addConstraint(core.ConstraintForeignKey, "")
+	p.next()
+	if p.peek().kind == tokenGroup {
+		p.next()
+	}
+	for {
+		switch {
+		case p.accept("ON"):
+			p.next()
+			if k := p.next().keyword(); k == "SET" || k == "NO" {
+				p.next()
+			}
+		case p.accept("MATCH"):
+			p.next()
+		default:
+			return nil
+		}
+	}
+}
+
+// skipDefault skips a default value, which may be a signed number. It is
+// read from PRAGMA table_xinfo.
+func (p *columnParser) skipDefault() error {
+	if t := p.peek(); t.kind == tokenSymbol && (t.text == "-" || t.text == "+") {
+		p.next()
+	}
+	p.next()
+	return nil
+}
+
+// parseGenerated parses the expression of a generated column after AS.
+// GENERATED ALWAYS may precede it, and VIRTUAL is the default storage.
+func (p *columnParser) parseGenerated() error {
+	group, err := p.group()
+	if err != nil {
+		return err
+	}
+	p.col.generated = groupExpression(group)
+	p.col.storage = core.GenerationVirtual
+	return nil
+}
+
+// indexDDL holds what the CREATE INDEX statement of an index declares
+// beyond what the PRAGMA functions report.
+type indexDDL struct {
+	keyParts []string // The verbatim text of every key part.
+	where    string
+}
+
+// parseCreateIndex parses a CREATE INDEX statement as stored in
+// sqlite_schema.
+//
+// Example input: "CREATE INDEX idx ON t (lower(email), id DESC) WHERE deleted = 0".
+func parseCreateIndex(stmt string) (*indexDDL, error) {
+	tokens, err := tokenize(stmt)
+	if err != nil {
+		return nil, err
+	}
+	d := &indexDDL{}
+	for i, t := range tokens {
+		switch {
+		case t.kind == tokenGroup && d.keyParts == nil:
+			if d.keyParts, err = keyParts(t.text); err != nil {
+				return nil, err
+			}
+		case t.keyword() == "WHERE" && i+1 < len(tokens):
+			d.where = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(stmt[tokens[i+1].pos:]), ";"))
+			return d, nil
+		}
+	}
+	return d, nil
+}
+
+// keyParts returns the key parts of an index without their COLLATE clause
+// and sort order.
+func keyParts(group string) ([]string, error) {
+	inner := group[1 : len(group)-1]
+	tokens, err := tokenize(inner)
+	if err != nil {
+		return nil, err
+	}
+	parts := []string{}
+	for _, item := range splitTokens(tokens) {
+		end := len(item)
+		for i, t := range item {
+			if k := t.keyword(); k == "COLLATE" || k == "ASC" || k == "DESC" {
+				end = i
+				break
+			}
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("empty key part in %s", group)
+		}
+		parts = append(parts, inner[item[0].pos:item[end-1].end])
+	}
+	return parts, nil
+}
+
+// viewDDL holds what the CREATE VIEW statement of a view declares.
+type viewDDL struct {
+	columns    []string
+	definition string
+}
+
+// parseCreateView parses a CREATE VIEW statement as stored in sqlite_schema.
+//
+// Example input: "CREATE VIEW v (id, total) AS SELECT id, sum(amount) FROM orders GROUP BY id".
+func parseCreateView(stmt string) (*viewDDL, error) {
+	tokens, err := tokenize(stmt)
+	if err != nil {
+		return nil, err
+	}
+	d := &viewDDL{}
+	for i, t := range tokens {
+		switch {
+		case t.kind == tokenGroup && d.columns == nil:
+			if d.columns, err = groupColumns(&tokenReader{tokens: []token{t}}); err != nil {
+				return nil, err
+			}
+		case t.keyword() == "AS" && i+1 < len(tokens):
+			d.definition = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(stmt[tokens[i+1].pos:]), ";"))
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("missing AS in %q", stmt)
+}
@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestParseCreateTable(t *testing.T) {
+	t.Parallel()
+	const stmt = `CREATE TABLE "orders" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  [user_id] INTEGER NOT NULL CONSTRAINT "fk_orders_users" REFERENCES "users" ("id") ON DELETE SET DEFAULT,
+  status TEXT NOT NULL DEFAULT 'new' CHECK ("status" IN ('new', 'paid')),
+  code TEXT COLLATE NOCASE CONSTRAINT uq_orders_code UNIQUE,
+  price REAL DEFAULT -1, -- unit price
+  total REAL GENERATED ALWAYS AS ((price * 2)) STORED,
+  label TEXT AS (upper(code)),
+  CONSTRAINT "chk_orders_price" CHECK (price >= 0),
+  UNIQUE (user_id, code COLLATE NOCASE)
+) WITHOUT ROWID, STRICT`
+	d, err := parseCreateTable(stmt)
+	require.NoError(t, err)
+
+	assert.True(t, d.withoutRowid)
+	assert.True(t, d.strict)
+	assert.Equal(t, &columnDDL{autoincrement: true}, d.column("id"))
+	assert.Equal(t, &columnDDL{collate: "NOCASE"}, d.column("CODE"))
+	assert.Equal(t, &columnDDL{generated: "price * 2", storage: core.GenerationStored}, d.column("total"))
+	assert.Equal(t, &columnDDL{generated: "upper(code)", storage: core.GenerationVirtual}, d.column("label"))
+	assert.Equal(t, &columnDDL{}, d.column("missing"))
+
+	assert.Equal(t, []*constraintDDL{
+		{typ: core.ConstraintPrimaryKey, columns: []string{"id"}, inline: true},
+		{name: "fk_orders_users", typ: core.ConstraintForeignKey, columns: []string{"user_id"}, inline: true},
+		{typ: core.ConstraintCheck, columns: []string{"status"}, check: `"status" IN ('new', 'paid')`, inline: true},
+		{name: "uq_orders_code", typ: core.ConstraintUnique, columns: []string{"code"}, inline: true},
+		{name: "chk_orders_price", typ: core.ConstraintCheck, check: "price >= 0"},
+		{typ: core.ConstraintUnique, columns: []string{"user_id", "code"}},
+	}, d.constraints)
+	assert.Equal(t, "fk_orders_users", d.constraint(core.ConstraintForeignKey, []string{"USER_ID"}).name)
+	assert.Nil(t, d.constraint(core.ConstraintUnique, []string{"user_id"}))
+}
+
+func TestParseCreateTableErrors(t *testing.T) {
+	t.Parallel()
+	for _, stmt := range []string{
+		"CREATE TABLE t",
+		"CREATE TABLE t (id INTEGER,)",
+		"CREATE TABLE t (id INTEGER) WITHOUT",
+		"CREATE TABLE t (id INTEGER) TEMPORARY",
+		"CREATE TABLE t (id INTEGER, CHECK id > 0)",
+		"CREATE TABLE t (id INTEGER, PRIMARY (id))",
+		"CREATE TABLE t (name TEXT DEFAULT 'unterminated)",
+	} {
+		_, err := parseCreateTable(stmt)
+		assert.Error(t, err, stmt)
+	}
+}
+
+func TestParseCreateIndex(t *testing.T) {
+	t.Parallel()
+	d, err := parseCreateIndex(`CREATE UNIQUE INDEX "idx_users_email" ON "users" (lower(email) COLLATE NOCASE, (id + 1) DESC, name)
+WHERE deleted_at IS NULL AND name <> 'x'`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lower(email)", "(id + 1)", "name"}, d.keyParts)
+	assert.Equal(t, "deleted_at IS NULL AND name <> 'x'", d.where)
+}
+
+func TestParseCreateView(t *testing.T) {
+	t.Parallel()
+	d, err := parseCreateView(`CREATE VIEW IF NOT EXISTS "order_totals" ("id", total) AS
+SELECT id, sum(amount) FROM orders GROUP BY id;`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "total"}, d.columns)
+	assert.Equal(t, "SELECT id, sum(amount) FROM orders GROUP BY id", d.definition)
+
+	d, err = parseCreateView("CREATE VIEW active_users AS SELECT * FROM users WHERE (active)")
+	require.NoError(t, err)
+	assert.Nil(t, d.columns)
+	assert.Equal(t, "SELECT * FROM users WHERE (active)", d.definition)
+
+	_, err = parseCreateView("CREATE VIEW broken")
+	require.Error(t, err)
+}
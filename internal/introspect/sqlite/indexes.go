@@ -0,0 +1,170 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// Values of the origin column of PRAGMA index_list for automatic indexes.
+// Indexes created by CREATE INDEX have the origin "c".
+const (
+	originUnique = "u"  // Created by a UNIQUE constraint.
+	originPK     = "pk" // Created by a PRIMARY KEY constraint.
+)
+
+// indexRow holds a row of PRAGMA index_list, with the CREATE INDEX
+// statement of the index.
+type indexRow struct {
+	name   string
+	unique bool
+	origin string
+	sql    string
+}
+
+// keyColumn holds a key column row of PRAGMA index_xinfo. An expression
+// has no name.
+type keyColumn struct {
+	name sql.NullString
+	desc bool
+}
+
+// queryIndexes adds the indexes of a table, and the UNIQUE constraints,
+// which SQLite implements as automatic indexes, named as they are declared.
+func queryIndexes(ic *introspectCtx, table *core.Table, ddl *tableDDL) error {
+	indexes, err := queryIndexList(ic, table.Name)
+	if err != nil {
+		return err
+	}
+	for _, row := range indexes {
+		if row.origin == originPK {
+			continue
+		}
+		keys, err := queryIndexColumns(ic, row.name)
+		if err != nil {
+			return fmt.Errorf("index %s: %w", row.name, err)
+		}
+		if row.origin == originUnique {
+			addUnique(table, ddl, keys)
+			continue
+		}
+		idx, err := buildIndex(row, keys)
+		if err != nil {
+			return fmt.Errorf("index %s: %w", row.name, err)
+		}
+		table.Indexes = append(table.Indexes, idx)
+	}
+	return nil
+}
+
+func queryIndexList(ic *introspectCtx, table string) ([]indexRow, error) {
+	query := `
+        SELECT il.name, il."unique", il.origin, coalesce(s.sql, '')
+        FROM pragma_index_list(?) il
+        LEFT JOIN sqlite_schema s ON s.type = 'index' AND s.name = il.name
+        ORDER BY il.name
+    `
+	rows, err := ic.conn.QueryContext(ic.ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []indexRow
+	for rows.Next() {
+		var r indexRow
+		if err := rows.Scan(&r.name, &r.unique, &r.origin, &r.sql); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return indexes, nil
+}
+
+func queryIndexColumns(ic *introspectCtx, index string) ([]keyColumn, error) {
+	query := `
+        SELECT name, "desc"
+        FROM pragma_index_xinfo(?)
+        WHERE key = 1
+        ORDER BY seqno
+    `
+	rows, err := ic.conn.QueryContext(ic.ctx, query, index)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []keyColumn
+	for rows.Next() {
+		var k keyColumn
+		if err := rows.Scan(&k.name, &k.desc); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func addUnique(table *core.Table, ddl *tableDDL, keys []keyColumn) {
+	con := &core.Constraint{Type: core.ConstraintUnique}
+	for _, k := range keys {
+		con.Columns = append(con.Columns, k.name.String)
+	}
+	if d := ddl.constraint(core.ConstraintUnique, con.Columns); d != nil {
+		con.Name = d.name
+	}
+	table.Constraints = append(table.Constraints, con)
+}
+
+// buildIndex builds an index from its key columns. The expressions and
+// the WHERE clause of a partial index are read from its CREATE INDEX
+// statement.
+func buildIndex(row indexRow, keys []keyColumn) (*core.Index, error) {
+	ddl, err := parseCreateIndex(row.sql)
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	if len(ddl.keyParts) != len(keys) {
+		return nil, fmt.Errorf("%d key parts in %q, expected %d", len(ddl.keyParts), row.sql, len(keys))
+	}
+
+	idx := &core.Index{
+		Name:       row.name,
+		Unique:     row.unique,
+		Type:       core.IndexTypeBTree,
+		Visibility: core.IndexVisible,
+		Where:      ddl.where,
+	}
+	for i, k := range keys {
+		part := core.ColumnIndex{Name: k.name.String, Order: core.SortAsc}
+		if !k.name.Valid {
+			part.Expression = keyExpression(ddl.keyParts[i])
+		}
+		if k.desc {
+			part.Order = core.SortDesc
+		}
+		idx.Columns = append(idx.Columns, part)
+	}
+	return idx, nil
+}
+
+// keyExpression returns the expression of a key part without the
+// parentheses around the whole of it.
+func keyExpression(part string) string {
+	if strings.HasPrefix(part, "(") {
+		if end, err := scanGroup(part, 0); err == nil && end == len(part) {
+			return groupExpression(part)
+		}
+	}
+	return part
+}
@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func TestBuildIndex(t *testing.T) {
+	t.Parallel()
+	row := indexRow{
+		name:   "idx_users_email",
+		unique: true,
+		sql:    "CREATE UNIQUE INDEX idx_users_email ON users (lower(email), (id + 1), created_at DESC) WHERE deleted_at IS NULL",
+	}
+	keys := []keyColumn{{}, {}, {name: sql.NullString{String: "created_at", Valid: true}, desc: true}}
+	idx, err := buildIndex(row, keys)
+	require.NoError(t, err)
+	assert.Equal(t, &core.Index{
+		Name:       "idx_users_email",
+		Unique:     true,
+		Type:       core.IndexTypeBTree,
+		Visibility: core.IndexVisible,
+		Where:      "deleted_at IS NULL",
+		Columns: []core.ColumnIndex{
+			{Expression: "lower(email)", Order: core.SortAsc},
+			{Expression: "id + 1", Order: core.SortAsc},
+			{Name: "created_at", Order: core.SortDesc},
+		},
+	}, idx)
+
+	_, err = buildIndex(row, keys[:1])
+	assert.Error(t, err)
+}
+
+func TestAddUnique(t *testing.T) {
+	t.Parallel()
+	ddl, err := parseCreateTable("CREATE TABLE t (a TEXT, b TEXT, CONSTRAINT uq_t_a_b UNIQUE (a, b))")
+	require.NoError(t, err)
+	table := &core.Table{Name: "t"}
+	addUnique(table, ddl, []keyColumn{{name: sql.NullString{String: "a", Valid: true}}, {name: sql.NullString{String: "b", Valid: true}}})
+	assert.Equal(t, []*core.Constraint{{Name: "uq_t_a_b", Type: core.ConstraintUnique, Columns: []string{"a", "b"}}}, table.Constraints)
+}
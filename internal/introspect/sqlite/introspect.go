@@ -1,8 +1,14 @@
+// Package sqlite contains introspect implementation for SQLite. It reads the tables and
+// views of the main database from sqlite_schema and the PRAGMA table-valued functions, and
+// parses the stored CREATE statements for what the PRAGMA functions do not report, such as
+// CHECK constraints, generated columns, and the STRICT and WITHOUT ROWID options.
 package sqlite
 
 import (
 	"context"
 	"database/sql"
+	"path/filepath"
+	"strings"
 
 	"smf/internal/core"
 	"smf/internal/introspect"
@@ -14,10 +20,56 @@ func init() {
 
 type introspecter struct{}
 
+// introspectCtx holds a single connection, since every connection to an
+// in-memory database opens a database of its own.
+type introspectCtx struct {
+	conn *sql.Conn
+	ctx  context.Context
+}
+
 func New() introspect.Introspecter {
 	return &introspecter{}
 }
 
-func (i *introspecter) Introspect(_ context.Context, _ *sql.DB) (*core.Database, error) {
-	return nil, nil
+func (i *introspecter) Introspect(ctx context.Context, db *sql.DB) (*core.Database, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var file string
+	err = conn.QueryRowContext(ctx, "SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&file)
+	if err != nil {
+		return nil, err
+	}
+	d := &core.Database{Name: databaseName(file), Dialect: core.DialectSQLite}
+
+	ic := &introspectCtx{
+		conn: conn,
+		ctx:  ctx,
+	}
+
+	err = introspectTables(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = introspectViews(ic, d)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// databaseName names a database after its file, without the directory and
+// the extension. In-memory and temporary databases have no file and are
+// named main, as SQLite names them.
+func databaseName(file string) string {
+	if file == "" {
+		return "main"
+	}
+	base := filepath.Base(file)
+	return strings.TrimSuffix(base, filepath.Ext(base))
 }
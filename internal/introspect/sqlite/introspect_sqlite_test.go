@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+	gensqlite "smf/internal/generate/sqlite"
+	"smf/internal/pars/toml"
+)
+
+const shopDDL = `
+CREATE TABLE users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	email TEXT NOT NULL COLLATE NOCASE UNIQUE,
+	name TEXT DEFAULT 'anonymous',
+	age INTEGER CHECK (age >= 0)
+);
+CREATE INDEX idx_users_name ON users (name) WHERE name IS NOT NULL;
+CREATE TABLE orders (
+	id INTEGER NOT NULL,
+	user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	quantity INTEGER NOT NULL DEFAULT 1,
+	price REAL NOT NULL,
+	total REAL GENERATED ALWAYS AS (quantity * price) STORED,
+	created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (id)
+) STRICT;
+CREATE TABLE tags (
+	name TEXT PRIMARY KEY,
+	label TEXT
+) WITHOUT ROWID;
+CREATE VIEW big_orders AS SELECT id, total FROM orders WHERE total > 100;
+`
+
+// openFile opens a SQLite database file in a temporary directory and runs
+// the statements in it.
+func openFile(t *testing.T, stmts ...string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "shop.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	for _, stmt := range stmts {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err, stmt)
+	}
+	return db
+}
+
+func TestSQLiteIntrospect(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	got, err := New().Introspect(ctx, openFile(t, shopDDL))
+	require.NoError(t, err)
+
+	assert.Equal(t, "shop", got.Name)
+	assert.Equal(t, core.DialectSQLite, got.Dialect)
+	require.Len(t, got.Tables, 3)
+
+	users := got.FindTable("users")
+	require.NotNil(t, users)
+	id := users.FindColumn("id")
+	require.NotNil(t, id)
+	assert.True(t, id.PrimaryKey)
+	assert.True(t, id.AutoIncrement)
+	require.NotNil(t, id.SQLite)
+	assert.True(t, id.SQLite.StrictAutoincrement)
+	email := users.FindColumn("email")
+	require.NotNil(t, email)
+	assert.False(t, email.Nullable)
+	assert.Equal(t, "NOCASE", email.Collate)
+	assert.Equal(t, new("anonymous"), users.FindColumn("name").DefaultValue)
+	assert.Contains(t, users.Constraints, &core.Constraint{Type: core.ConstraintUnique, Columns: []string{"email"}})
+	assert.Contains(t, users.Constraints, &core.Constraint{
+		Type: core.ConstraintCheck, Columns: []string{"age"}, CheckExpression: "age >= 0",
+	})
+	require.Len(t, users.Indexes, 1)
+	assert.Equal(t, "idx_users_name", users.Indexes[0].Name)
+	assert.Equal(t, "name IS NOT NULL", users.Indexes[0].Where)
+
+	orders := got.FindTable("orders")
+	require.NotNil(t, orders)
+	assert.True(t, orders.Options.SQLite.Strict)
+	total := orders.FindColumn("total")
+	require.NotNil(t, total)
+	assert.True(t, total.IsGenerated)
+	assert.Equal(t, "quantity * price", total.GenerationExpression)
+	assert.Equal(t, new("CURRENT_TIMESTAMP"), orders.FindColumn("created_at").DefaultValue)
+	assert.Contains(t, orders.Constraints, &core.Constraint{
+		Type:              core.ConstraintForeignKey,
+		Columns:           []string{"user_id"},
+		ReferencedTable:   "users",
+		ReferencedColumns: []string{"id"},
+		OnDelete:          core.RefActionCascade,
+	})
+
+	tags := got.FindTable("tags")
+	require.NotNil(t, tags)
+	assert.True(t, tags.Options.SQLite.WithoutRowid)
+
+	require.Len(t, got.Views, 1)
+	assert.Equal(t, "big_orders", got.Views[0].Name)
+	assert.Equal(t, "SELECT id, total FROM orders WHERE total > 100", got.Views[0].Definition)
+}
+
+const shopSchema = `
+[database]
+name = "shop"
+dialect = "sqlite"
+
+[[tables]]
+name = "users"
+comment = "registered users"
+
+  [tables.timestamps]
+  enabled = true
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+  auto_increment = true
+
+  [[tables.columns]]
+  name = "email"
+  type = "varchar(255)"
+  unique = true
+
+  [[tables.columns]]
+  name = "status"
+  type = "enum"
+  values = ["active", "banned"]
+  default = "active"
+
+  [[tables.columns]]
+  name = "score"
+  type = "decimal(10,2)"
+  nullable = true
+
+  [[tables.columns]]
+  name = "verified"
+  type = "boolean"
+  default = "false"
+
+  [[tables.indexes]]
+  columns = ["email", "status"]
+
+[[tables]]
+name = "orders"
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+  auto_increment = true
+
+  [[tables.columns]]
+  name = "user_id"
+  type = "bigint"
+  references = "users.id"
+  on_delete = "CASCADE"
+
+  [[tables.columns]]
+  name = "note"
+  type = "text"
+  nullable = true
+
+  [[tables.constraints]]
+  name = "chk_orders_id"
+  type = "CHECK"
+  columns = ["id"]
+  check_expression = "id > 0"
+
+[[views]]
+name = "active_users"
+definition = "SELECT id, email FROM users WHERE status = 'active'"
+`
+
+// TestSQLiteIntrospectRoundTrip checks that a schema file, generated and
+// run against an empty database file, introspects to the same schema once
+// both are normalized to what SQLite stores.
+func TestSQLiteIntrospectRoundTrip(t *testing.T) {
+	t.Parallel()
+	want, err := toml.NewParser().Parse(strings.NewReader(shopSchema))
+	require.NoError(t, err)
+	script, err := gensqlite.New().Generate(want)
+	require.NoError(t, err)
+
+	got, err := New().Introspect(context.Background(), openFile(t, script.Statements...))
+	require.NoError(t, err)
+	require.Equal(t, new("CURRENT_TIMESTAMP"), got.FindTable("users").FindColumn("updated_at").OnUpdate)
+
+	generate.Normalize(got)
+	generate.Normalize(want)
+	assert.Empty(t, diff.Databases(got, want).Changes)
+}
@@ -0,0 +1,13 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "shop", databaseName("/var/lib/app/shop.db"))
+	assert.Equal(t, "main", databaseName(""))
+}
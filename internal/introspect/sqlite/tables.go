@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// tableRow is a table as listed in sqlite_schema.
+type tableRow struct {
+	name string
+	sql  string
+}
+
+func introspectTables(ic *introspectCtx, db *core.Database) error {
+	rows, err := queryTables(ic)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		table, err := introspectTable(ic, row)
+		if err != nil {
+			return fmt.Errorf("introspect table %s: %w", row.name, err)
+		}
+		db.Tables = append(db.Tables, table)
+	}
+	resolveReferences(db.Tables)
+	if err := queryOnUpdateTriggers(ic, db); err != nil {
+		return fmt.Errorf("query triggers: %w", err)
+	}
+	return nil
+}
+
+// queryTables lists the tables of the main database. The internal tables
+// of SQLite and virtual tables are left out.
+func queryTables(ic *introspectCtx) ([]tableRow, error) {
+	query := `
+        SELECT name, sql
+        FROM sqlite_schema
+        WHERE type = 'table'
+        AND name NOT LIKE 'sqlite\_%' ESCAPE '\'
+        AND sql NOT LIKE 'CREATE VIRTUAL %'
+        ORDER BY name
+    `
+	rows, err := ic.conn.QueryContext(ic.ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []tableRow
+	for rows.Next() {
+		var t tableRow
+		if err := rows.Scan(&t.name, &t.sql); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+func introspectTable(ic *introspectCtx, row tableRow) (*core.Table, error) {
+	ddl, err := parseCreateTable(row.sql)
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+
+	table := &core.Table{
+		Name:        row.name,
+		Columns:     make([]*core.Column, 0),
+		Constraints: make([]*core.Constraint, 0),
+		Indexes:     make([]*core.Index, 0),
+	}
+	if ddl.withoutRowid || ddl.strict {
+		table.Options.SQLite = &core.SQLiteTableOptions{WithoutRowid: ddl.withoutRowid, Strict: ddl.strict}
+	}
+
+	columns, err := queryColumns(ic, row.name)
+	if err != nil {
+		return nil, fmt.Errorf("query columns: %w", err)
+	}
+	for _, c := range columns {
+		c.build(ddl.column(c.col.Name))
+		table.Columns = append(table.Columns, c.col)
+	}
+	addPrimaryKey(table, ddl, columns)
+
+	if err := queryIndexes(ic, table, ddl); err != nil {
+		return nil, fmt.Errorf("query indexes: %w", err)
+	}
+	if err := queryForeignKeys(ic, table, ddl); err != nil {
+		return nil, fmt.Errorf("query foreign keys: %w", err)
+	}
+	addChecks(table, ddl)
+	return table, nil
+}
+
+// resolveReferences fills in the referenced columns of the foreign keys
+// that reference the primary key of a table without naming its columns.
+func resolveReferences(tables []*core.Table) {
+	for _, t := range tables {
+		for _, con := range t.Constraints {
+			if con.Type != core.ConstraintForeignKey || len(con.ReferencedColumns) > 0 {
+				continue
+			}
+			if pk := primaryKeyOf(tables, con.ReferencedTable); pk != nil {
+				con.ReferencedColumns = pk.Columns
+			}
+		}
+	}
+}
+
+func primaryKeyOf(tables []*core.Table, name string) *core.Constraint {
+	for _, t := range tables {
+		if strings.EqualFold(t.Name, name) {
+			return t.PrimaryKey()
+		}
+	}
+	return nil
+}
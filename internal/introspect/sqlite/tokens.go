@@ -0,0 +1,251 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenKind classifies a token of a CREATE TABLE, INDEX, or VIEW statement
+// as stored in sqlite_schema.
+type tokenKind int
+
+const (
+	tokenEnd    tokenKind = iota // End of input, the zero token.
+	tokenWord                    // Keyword, unquoted identifier, or number.
+	tokenIdent                   // Identifier quoted with "", [], or ``.
+	tokenString                  // Single-quoted string literal.
+	tokenGroup                   // Parenthesized group, kept verbatim.
+	tokenSymbol                  // Any other character, such as ',' or '-'.
+)
+
+// token is a single lexical token. text holds the unquoted identifier or
+// string for tokenIdent and tokenString, and the verbatim source otherwise.
+// pos and end are the offsets of the token in the tokenized text.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	end  int
+}
+
+// keyword returns the upper-cased text of a word token, or "" for any other
+// kind of token.
+func (t token) keyword() string {
+	if t.kind != tokenWord {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+// name returns the identifier a word or quoted identifier token stands for.
+func (t token) name() (string, error) {
+	if t.kind != tokenWord && t.kind != tokenIdent && t.kind != tokenString {
+		return "", fmt.Errorf("expected an identifier, got %q", t.text)
+	}
+	return t.text, nil
+}
+
+// tokenize splits s into tokens. SQLite keeps a CREATE statement as it was
+// written, so comments are dropped.
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(s[i:], "--"):
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 4
+		default:
+			t, n, err := scanToken(s, i)
+			if err != nil {
+				return nil, err
+			}
+			t.end = n
+			tokens = append(tokens, t)
+			i = n
+		}
+	}
+	return tokens, nil
+}
+
+// closingQuotes maps the characters that open a quoted identifier or string
+// to the character that closes it.
+var closingQuotes = map[byte]byte{'"': '"', '`': '`', '[': ']', '\'': '\''}
+
+// scanToken scans the token that starts at s[i] and returns it with the
+// index just past it.
+func scanToken(s string, i int) (token, int, error) {
+	c := s[i]
+	if q, ok := closingQuotes[c]; ok {
+		end, err := scanQuoted(s, i, q)
+		if err != nil {
+			return token{}, 0, err
+		}
+		kind := tokenIdent
+		if c == '\'' {
+			kind = tokenString
+		}
+		text := s[i+1 : end-1]
+		if q != ']' {
+			text = strings.ReplaceAll(text, string(q)+string(q), string(q))
+		}
+		return token{kind: kind, text: text, pos: i}, end, nil
+	}
+	if c == '(' {
+		end, err := scanGroup(s, i)
+		if err != nil {
+			return token{}, 0, err
+		}
+		return token{kind: tokenGroup, text: s[i:end], pos: i}, end, nil
+	}
+	if isWordByte(c) {
+		end := i + 1
+		for end < len(s) && isWordByte(s[end]) {
+			end++
+		}
+		return token{kind: tokenWord, text: s[i:end], pos: i}, end, nil
+	}
+	return token{kind: tokenSymbol, text: s[i : i+1], pos: i}, i + 1, nil
+}
+
+// scanQuoted returns the index just past the quoted identifier or string
+// that starts at s[i] and ends with q. A doubled quote stands for itself.
+func scanQuoted(s string, i int, q byte) (int, error) {
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != q {
+			continue
+		}
+		if q != ']' && j+1 < len(s) && s[j+1] == q {
+			j++
+			continue
+		}
+		return j + 1, nil
+	}
+	return 0, fmt.Errorf("unterminated quote in %q", s)
+}
+
+// scanGroup returns the index just past the balanced parenthesized group
+// that starts at s[i].
+func scanGroup(s string, i int) (int, error) {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		if q, ok := closingQuotes[s[j]]; ok {
+			end, err := scanQuoted(s, j, q)
+			if err != nil {
+				return 0, err
+			}
+			j = end - 1
+			continue
+		}
+		switch s[j] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return j + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parentheses in %q", s)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// unwrapGroup returns the content of a parenthesized group.
+func unwrapGroup(group string) string {
+	return strings.TrimSpace(group[1 : len(group)-1])
+}
+
+// groupExpression returns the expression inside a parenthesized group
+// without redundant parentheses.
+func groupExpression(group string) string {
+	expr := unwrapGroup(group)
+	for strings.HasPrefix(expr, "(") {
+		end, err := scanGroup(expr, 0)
+		if err != nil || end != len(expr) {
+			break
+		}
+		expr = unwrapGroup(expr)
+	}
+	return expr
+}
+
+// splitTokens splits tokens at the top-level commas, as between the items
+// of a CREATE TABLE body or the key parts of an index.
+func splitTokens(tokens []token) [][]token {
+	var items [][]token
+	start := 0
+	for i, t := range tokens {
+		if t.kind == tokenSymbol && t.text == "," {
+			items = append(items, tokens[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, tokens[start:])
+}
+
+// tokenReader reads a token list from front to back.
+type tokenReader struct {
+	tokens []token
+	pos    int
+}
+
+func (r *tokenReader) done() bool {
+	return r.pos >= len(r.tokens)
+}
+
+// next returns the next token, or the zero token at the end.
+func (r *tokenReader) next() token {
+	if r.done() {
+		return token{}
+	}
+	t := r.tokens[r.pos]
+	r.pos++
+	return t
+}
+
+func (r *tokenReader) peek() token {
+	if r.done() {
+		return token{}
+	}
+	return r.tokens[r.pos]
+}
+
+// accept consumes the next token if it is the given keyword.
+func (r *tokenReader) accept(keyword string) bool {
+	if r.peek().keyword() != keyword {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *tokenReader) expect(keyword string) error {
+	if t := r.next(); t.keyword() != keyword {
+		return fmt.Errorf("expected %s, got %q", keyword, t.text)
+	}
+	return nil
+}
+
+// group reads a parenthesized group.
+func (r *tokenReader) group() (string, error) {
+	t := r.next()
+	if t.kind != tokenGroup {
+		return "", fmt.Errorf("expected a parenthesized group, got %q", t.text)
+	}
+	return t.text, nil
+}
@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// SQLite has no ON UPDATE column clause, and the generator emulates it
+// with an AFTER UPDATE trigger named {table}_{column}_on_update. The value
+// such a trigger assigns is read back as the ON UPDATE value of the column;
+// other triggers are not read.
+
+// onUpdateSetRe matches the assignment in the body of an ON UPDATE trigger.
+var onUpdateSetRe = regexp.MustCompile(`(?is)\bUPDATE\s+"(?:[^"]|"")+"\s+SET\s+"((?:[^"]|"")+)"\s*=\s*(.+?)\s+WHERE\s`)
+
+func queryOnUpdateTriggers(ic *introspectCtx, db *core.Database) error {
+	query := `
+        SELECT tbl_name, name, sql
+        FROM sqlite_schema
+        WHERE type = 'trigger'
+        AND name LIKE '%\_on\_update' ESCAPE '\'
+        ORDER BY tbl_name, name
+    `
+	rows, err := ic.conn.QueryContext(ic.ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, name, definition string
+		if err := rows.Scan(&tableName, &name, &definition); err != nil {
+			return err
+		}
+		if table := db.FindTable(tableName); table != nil {
+			setOnUpdate(table, name, definition)
+		}
+	}
+	return rows.Err()
+}
+
+// setOnUpdate sets the ON UPDATE value of the column a trigger emulates it
+// for, when the trigger is named and written as the generator writes it.
+func setOnUpdate(table *core.Table, name, definition string) {
+	m := onUpdateSetRe.FindStringSubmatch(definition)
+	if m == nil {
+		return
+	}
+	col := table.FindColumn(strings.ReplaceAll(m[1], `""`, `"`))
+	if col == nil || name != table.Name+"_"+col.Name+"_on_update" {
+		return
+	}
+	col.OnUpdate = new(strings.TrimSpace(m[2]))
+}
@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/core"
+)
+
+func TestSetOnUpdate(t *testing.T) {
+	t.Parallel()
+	const definition = `CREATE TRIGGER "notes_updated_at_on_update" AFTER UPDATE ON "notes" FOR EACH ROW WHEN NEW."updated_at" IS OLD."updated_at"
+BEGIN
+  UPDATE "notes" SET "updated_at" = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END`
+	table := &core.Table{Name: "notes", Columns: []*core.Column{{Name: "id"}, {Name: "updated_at"}}}
+	setOnUpdate(table, "notes_id_on_update", definition)
+	assert.Nil(t, table.Columns[1].OnUpdate)
+
+	setOnUpdate(table, "notes_updated_at_on_update", definition)
+	assert.Equal(t, new("CURRENT_TIMESTAMP"), table.Columns[1].OnUpdate)
+}
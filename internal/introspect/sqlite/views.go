@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+)
+
+func introspectViews(ic *introspectCtx, db *core.Database) error {
+	query := `
+        SELECT name, sql
+        FROM sqlite_schema
+        WHERE type = 'view'
+        ORDER BY name
+    `
+	rows, err := ic.conn.QueryContext(ic.ctx, query)
+	if err != nil {
+		return fmt.Errorf("query views: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, stmt string
+		if err := rows.Scan(&name, &stmt); err != nil {
+			return fmt.Errorf("query views: %w", err)
+		}
+		ddl, err := parseCreateView(stmt)
+		if err != nil {
+			return fmt.Errorf("introspect view %s: parse: %w", name, err)
+		}
+		db.Views = append(db.Views, &core.View{Name: name, Definition: ddl.definition, Columns: ddl.columns})
+	}
+	return rows.Err()
+}
@@ -0,0 +1,41 @@
+package introspect
+
+import (
+	"fmt"
+
+	"smf/internal/core"
+)
+
+// TableSet holds the introspected tables in the order they were read,
+// indexed by name. Introspecters that read the columns, constraints and
+// indexes of all tables with one query each use it to attach every row to
+// its table.
+type TableSet struct {
+	Tables []*core.Table
+	byName map[string]*core.Table
+}
+
+func NewTableSet() *TableSet {
+	return &TableSet{byName: make(map[string]*core.Table)}
+}
+
+// Add appends t to the set.
+func (s *TableSet) Add(t *core.Table) {
+	s.Tables = append(s.Tables, t)
+	s.byName[t.Name] = t
+}
+
+// Find returns the table with the given name, or nil.
+func (s *TableSet) Find(name string) *core.Table {
+	return s.byName[name]
+}
+
+// Get returns the table a row of a bulk query belongs to, and fails for a
+// table that was not read, which means the queries disagree.
+func (s *TableSet) Get(name string) (*core.Table, error) {
+	t, ok := s.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("unexpected table %s", name)
+	}
+	return t, nil
+}
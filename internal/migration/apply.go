@@ -0,0 +1,420 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// ApplyOptions control how Apply runs the pending migrations.
+type ApplyOptions struct {
+	// DryRun prints the statements and runs the preflight checks without
+	// executing anything.
+	DryRun bool
+	// Transaction runs each migration in a transaction when the dialect
+	// supports transactional DDL.
+	Transaction bool
+	// Unsafe allows destructive statements.
+	Unsafe bool
+	// AppliedBy is recorded in the history table.
+	AppliedBy string
+	// Out receives the progress report and, on a dry run, the statements.
+	Out io.Writer
+}
+
+// Applier runs the pending migrations of a migrations directory against a
+// database and records them in the history table.
+type Applier struct {
+	db      *sql.DB
+	dir     string
+	history history
+	opts    ApplyOptions
+	now     func() time.Time
+}
+
+// NewApplier returns an Applier for the migrations in dir.
+func NewApplier(db *sql.DB, dialect core.Dialect, dir string, opts ApplyOptions) *Applier {
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	return &Applier{db: db, dir: dir, history: history{dialect: dialect}, opts: opts, now: time.Now}
+}
+
+// pending is a migration that has not been applied yet.
+type pending struct {
+	*Migration
+	checksum string
+	stmts    []string
+}
+
+// Checksum returns the checksum recorded for a migration file.
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// TransactionalDDL reports whether the migrations of the dialect run in a
+// transaction. The other dialects commit every DDL statement implicitly.
+// Db2 rolls DDL back too, but smf cannot connect to it, so its migrations
+// are run with the db2 command line processor instead.
+func TransactionalDDL(d core.Dialect) bool {
+	switch d {
+	case core.DialectPostgreSQL, core.DialectSQLite, core.DialectMSSQL:
+		return true
+	default:
+		return false
+	}
+}
+
+// Apply runs the pending migrations in version order and returns them. It
+// refuses to run when an applied migration was modified or removed, when a
+// pending migration is older than the latest applied one, or when a pending
+// migration is destructive and Unsafe is not set.
+func (a *Applier) Apply(ctx context.Context) ([]*Migration, error) {
+	migrations, err := List(a.dir)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := a.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := a.pending(migrations, applied)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.preflight(migrations, todo); err != nil {
+		return nil, err
+	}
+
+	out := make([]*Migration, 0, len(todo))
+	for _, p := range todo {
+		if err := a.run(ctx, p); err != nil {
+			return out, err
+		}
+		out = append(out, p.Migration)
+	}
+	return out, nil
+}
+
+// applied reads the history table and creates it when it does not exist.
+// A dry run does not create it.
+func (a *Applier) applied(ctx context.Context) (map[string]*Applied, error) {
+	if a.history.exists(ctx, a.db) {
+		return a.history.applied(ctx, a.db)
+	}
+	if !a.opts.DryRun {
+		if _, err := a.db.ExecContext(ctx, a.history.createStatement()); err != nil {
+			return nil, fmt.Errorf("migration: create %s: %w", HistoryTable, err)
+		}
+	}
+	return map[string]*Applied{}, nil
+}
+
+// pending verifies the applied migrations against the files and loads the
+// migrations that have not been applied.
+func (a *Applier) pending(migrations []*Migration, applied map[string]*Applied) ([]*pending, error) {
+	var latest string
+	files := make(map[string]bool, len(migrations))
+	var out []*pending
+	for _, m := range migrations {
+		files[m.Version] = true
+		content, err := os.ReadFile(m.Path)
+		if err != nil {
+			return nil, fmt.Errorf("migration: read %s: %w", filepath.Base(m.Path), err)
+		}
+		sum := Checksum(content)
+		if rec, ok := applied[m.Version]; ok {
+			if rec.Checksum != sum {
+				return nil, fmt.Errorf("migration: %s was modified after it was applied", filepath.Base(m.Path))
+			}
+			latest = max(latest, m.Version)
+			continue
+		}
+		out = append(out, &pending{Migration: m, checksum: sum, stmts: Split(string(content))})
+	}
+	for version, rec := range applied {
+		if !files[version] {
+			return nil, fmt.Errorf("migration: applied migration %s_%s is missing from %s", version, rec.Name, a.dir)
+		}
+	}
+	if len(out) > 0 && out[0].Version < latest {
+		return nil, fmt.Errorf("migration: %s is older than the latest applied migration %s",
+			filepath.Base(out[0].Path), latest)
+	}
+	return out, nil
+}
+
+var destructiveRe = regexp.MustCompile(`(?is)^(DROP\s+(TABLE|SCHEMA|DATABASE)\b|TRUNCATE\b|ALTER\s+TABLE\b.*\bDROP\s+COLUMN\b)`)
+
+// preflight refuses destructive migrations unless Unsafe is set.
+func (a *Applier) preflight(migrations []*Migration, todo []*pending) error {
+	if a.opts.Unsafe {
+		return nil
+	}
+	var found []string
+	for _, p := range todo {
+		destructive, err := destructiveChanges(migrations, p)
+		if err != nil {
+			return err
+		}
+		for _, d := range destructive {
+			found = append(found, filepath.Base(p.Path)+": "+d)
+		}
+	}
+	if len(found) > 0 {
+		return fmt.Errorf("migration: refusing to run destructive statements without --unsafe:\n  %s",
+			strings.Join(found, "\n  "))
+	}
+	return nil
+}
+
+// destructiveChanges describes what p may lose data with. A migration that
+// has schema snapshots is judged by the changes between the snapshot before
+// it and its own, with the rule migrate applies. A migration without one is
+// judged by its statements.
+func destructiveChanges(migrations []*Migration, p *pending) ([]string, error) {
+	from, to, ok, err := snapshotsAround(migrations, p.Migration)
+	if err != nil {
+		return nil, err
+	}
+	var found []string
+	if ok {
+		for _, c := range diff.Databases(from, to).Destructive() {
+			found = append(found, diff.Describe(c))
+		}
+		return found, nil
+	}
+	for i, stmt := range p.stmts {
+		if destructiveRe.MatchString(stmt) && !replacesTable(p.stmts, i) {
+			found = append(found, firstLine(stmt))
+		}
+	}
+	return found, nil
+}
+
+// snapshotsAround reads the snapshot of the migration before m, nil for the
+// first migration, and the snapshot of m. ok is false when one of them does
+// not exist.
+func snapshotsAround(migrations []*Migration, m *Migration) (from, to *core.Database, ok bool, err error) {
+	i := slices.Index(migrations, m)
+	for _, s := range migrations[max(i-1, 0) : i+1] {
+		if _, err := os.Stat(s.SnapshotPath()); err != nil {
+			return nil, nil, false, nil
+		}
+	}
+	if i > 0 {
+		if from, err = ReadSnapshot(migrations[i-1]); err != nil {
+			return nil, nil, false, err
+		}
+	}
+	if to, err = ReadSnapshot(m); err != nil {
+		return nil, nil, false, err
+	}
+	return from, to, true, nil
+}
+
+// rebuildRenameRe matches the statement that renames the rebuilt copy of a
+// SQLite table to the table's name.
+var rebuildRenameRe = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+"_smf_new_((?:[^"]|"")+)"\s+RENAME\s+TO\s+"((?:[^"]|"")+)"$`)
+
+var dropTableRe = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+"((?:[^"]|"")+)"$`)
+
+// replacesTable reports whether stmts[i] is the DROP TABLE of a SQLite
+// rebuild, which the next statement replaces by the table's rebuilt copy.
+func replacesTable(stmts []string, i int) bool {
+	drop := dropTableRe.FindStringSubmatch(stmts[i])
+	if drop == nil || i+1 == len(stmts) {
+		return false
+	}
+	rename := rebuildRenameRe.FindStringSubmatch(stmts[i+1])
+	return rename != nil && rename[1] == drop[1] && rename[2] == drop[1]
+}
+
+func firstLine(stmt string) string {
+	if i := strings.IndexByte(stmt, '\n'); i >= 0 {
+		return stmt[:i] + " …"
+	}
+	return stmt
+}
+
+var transactionControlRe = regexp.MustCompile(`(?i)^(BEGIN(\s+(TRANSACTION|TRAN|WORK|DEFERRED|IMMEDIATE|EXCLUSIVE))?|START\s+TRANSACTION|COMMIT|ROLLBACK|END)\s*;?$`)
+
+// inTransaction reports whether p runs in a transaction, and explains why
+// not when a transaction was requested.
+func (a *Applier) inTransaction(p *pending) (bool, string) {
+	if !a.opts.Transaction {
+		return false, ""
+	}
+	if !TransactionalDDL(a.history.dialect) {
+		return false, fmt.Sprintf("%s commits DDL statements implicitly", a.history.dialect)
+	}
+	for _, stmt := range p.stmts {
+		if transactionControlRe.MatchString(stmt) {
+			return false, "the migration controls its own transaction"
+		}
+	}
+	return true, ""
+}
+
+// run executes p and records it in the history table. On a dry run it
+// prints the statements instead.
+func (a *Applier) run(ctx context.Context, p *pending) error {
+	name := filepath.Base(p.Path)
+	tx, reason := a.inTransaction(p)
+	if reason != "" {
+		fmt.Fprintf(a.opts.Out, "-- %s runs without a transaction: %s\n", name, reason)
+	}
+	if a.opts.DryRun {
+		fmt.Fprintf(a.opts.Out, "-- %s\n", name)
+		sep := generate.BatchSeparator(a.history.dialect)
+		for _, stmt := range p.stmts {
+			script := &generate.Script{Statements: []string{stmt}, BatchSeparator: sep}
+			fmt.Fprint(a.opts.Out, script.String())
+		}
+		return nil
+	}
+
+	start := a.now()
+	var err error
+	if tx {
+		err = a.runInTransaction(ctx, p, start)
+	} else {
+		err = a.runOnConnection(ctx, p, start)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.opts.Out, "Applied %s (%s)\n", name, a.now().Sub(start).Round(time.Millisecond))
+	return nil
+}
+
+// foreignKeysOffRe matches the PRAGMA with which a SQLite table rebuild
+// turns foreign key enforcement off until it has committed.
+var foreignKeysOffRe = regexp.MustCompile(`(?i)^PRAGMA\s+(\w+\.)?foreign_keys\s*=\s*(OFF|0|FALSE|NO)\s*;?$`)
+
+// runOnConnection runs p on a single connection, so a transaction the
+// migration opens itself spans all of its statements. When such a migration
+// fails, its transaction is rolled back, and foreign key enforcement is
+// turned back on if the migration turned it off, before the connection
+// returns to the pool.
+func (a *Applier) runOnConnection(ctx context.Context, p *pending, start time.Time) error {
+	conn, err := a.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("migration: %s: connect: %w", filepath.Base(p.Path), err)
+	}
+	defer conn.Close()
+
+	err = a.runStatements(ctx, conn, p, start)
+	if err == nil {
+		return nil
+	}
+	if slices.ContainsFunc(p.stmts, transactionControlRe.MatchString) {
+		_, _ = conn.ExecContext(ctx, "ROLLBACK")
+	}
+	if slices.ContainsFunc(p.stmts, foreignKeysOffRe.MatchString) {
+		_, _ = conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	}
+	return err
+}
+
+func (a *Applier) runInTransaction(ctx context.Context, p *pending, start time.Time) error {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migration: %s: begin transaction: %w", filepath.Base(p.Path), err)
+	}
+	if err := a.runStatements(ctx, tx, p, start); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migration: %s: commit: %w", filepath.Base(p.Path), err)
+	}
+	return nil
+}
+
+var commitRe = regexp.MustCompile(`(?i)^(COMMIT|END)(\s+(TRANSACTION|TRAN|WORK))?\s*;?$`)
+
+// runStatements executes the statements of p and records it in the history
+// table. A migration that controls its own transaction is recorded before
+// its last COMMIT, so that the history row is committed with its changes.
+func (a *Applier) runStatements(ctx context.Context, ex execer, p *pending, start time.Time) error {
+	commit := lastCommit(p.stmts)
+	if err := a.execStatements(ctx, ex, p, 0, commit); err != nil {
+		return err
+	}
+	err := a.history.record(ctx, ex, record{
+		version:   p.Version,
+		name:      p.Name,
+		checksum:  p.checksum,
+		appliedAt: a.now().UTC(),
+		duration:  a.now().Sub(start),
+		appliedBy: a.opts.AppliedBy,
+	})
+	if err != nil {
+		return err
+	}
+	return a.execStatements(ctx, ex, p, commit, len(p.stmts))
+}
+
+// lastCommit returns the index of the last COMMIT of stmts, or len(stmts)
+// when there is none.
+func lastCommit(stmts []string) int {
+	for i := len(stmts) - 1; i >= 0; i-- {
+		if commitRe.MatchString(stmts[i]) {
+			return i
+		}
+	}
+	return len(stmts)
+}
+
+// execStatements executes the statements of p from index from up to to.
+func (a *Applier) execStatements(ctx context.Context, ex execer, p *pending, from, to int) error {
+	for i := from; i < to; i++ {
+		if err := execStatement(ctx, ex, p.stmts[i]); err != nil {
+			return fmt.Errorf("migration: %s: statement %d: %w\n%s", filepath.Base(p.Path), i+1, err, p.stmts[i])
+		}
+	}
+	return nil
+}
+
+// foreignKeyCheckRe matches the PRAGMA foreign_key_check that ends a SQLite
+// table rebuild. It reports the violations as rows instead of failing.
+var foreignKeyCheckRe = regexp.MustCompile(`(?i)^PRAGMA\s+(\w+\.)?foreign_key_check\b`)
+
+// execStatement executes stmt. A foreign key check fails when it reports a
+// violation, so the rebuild that caused it is not committed.
+func execStatement(ctx context.Context, ex execer, stmt string) error {
+	if !foreignKeyCheckRe.MatchString(stmt) {
+		_, err := ex.ExecContext(ctx, stmt)
+		return err
+	}
+	rows, err := ex.QueryContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var table, parent string
+		var rowid sql.NullInt64
+		var fkid int
+		if err := rows.Scan(&table, &rowid, &parent, &fkid); err != nil {
+			return err
+		}
+		return fmt.Errorf("foreign key violation: a row of %s references a missing row of %s", table, parent)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,359 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate/sqlite"
+)
+
+// fakeDB is an in-memory database/sql backend that records the executed
+// statements and keeps the history table rows.
+type fakeDB struct {
+	mu      sync.Mutex
+	exec    []string
+	history [][]driver.Value
+	// recordedAt holds the number of executed statements at each history
+	// insert.
+	recordedAt []int
+	created    bool
+	commits    int
+	rollbacks  int
+	// fkViolations are the rows PRAGMA foreign_key_check returns.
+	fkViolations [][]driver.Value
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return nil }
+
+func (f *fakeDB) statements() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.exec...)
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return c, nil }
+
+func (c *fakeConn) Commit() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.commits++
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.rollbacks++
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE "+HistoryTable):
+		c.db.created = true
+	case strings.HasPrefix(query, "INSERT INTO "+HistoryTable):
+		c.db.history = append(c.db.history, []driver.Value{args[0].Value, args[1].Value, args[2].Value})
+		c.db.recordedAt = append(c.db.recordedAt, len(c.db.exec))
+	case strings.Contains(query, "FAIL"):
+		return nil, errors.New("syntax error")
+	default:
+		c.db.exec = append(c.db.exec, query)
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if strings.HasPrefix(query, "PRAGMA foreign_key_check") {
+		c.db.exec = append(c.db.exec, query)
+		return &fakeRows{columns: []string{"table", "rowid", "parent", "fkid"}, rows: c.db.fkViolations}, nil
+	}
+	if !c.db.created {
+		return nil, errors.New("no such table")
+	}
+	if strings.Contains(query, "1 = 0") {
+		return &fakeRows{}, nil
+	}
+	return &fakeRows{rows: append([][]driver.Value(nil), c.db.history...)}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if r.columns == nil {
+		return []string{"version", "name", "checksum"}
+	}
+	return r.columns
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func writeMigration(t *testing.T, dir, file, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644))
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeMigration(t, dir, "20260101000000_init.sql", "CREATE TABLE a (x INT);\n")
+	writeMigration(t, dir, "20260102000000_b.sql", "-- WARNING: note\n\nCREATE TABLE b (x INT);\nCREATE INDEX i ON b (x);\n")
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	var out bytes.Buffer
+	opts := ApplyOptions{Transaction: true, AppliedBy: "dev@host", Out: &out}
+	applied, err := NewApplier(db, core.DialectPostgreSQL, dir, opts).Apply(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, []string{"CREATE TABLE a (x INT)", "CREATE TABLE b (x INT)", "CREATE INDEX i ON b (x)"}, fake.statements())
+	assert.Equal(t, 2, fake.commits)
+	require.Len(t, fake.history, 2)
+	assert.Equal(t, "20260102000000", fake.history[1][0])
+	assert.Contains(t, out.String(), "Applied 20260101000000_init.sql")
+
+	applied, err = NewApplier(db, core.DialectPostgreSQL, dir, opts).Apply(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.Len(t, fake.statements(), 3)
+}
+
+func TestApplyRefusesModifiedMigration(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeMigration(t, dir, "20260101000000_init.sql", "CREATE TABLE a (x INT);\n")
+	db := sql.OpenDB(&fakeDB{})
+	defer db.Close()
+
+	_, err := NewApplier(db, core.DialectMySQL, dir, ApplyOptions{}).Apply(context.Background())
+	require.NoError(t, err)
+
+	writeMigration(t, dir, "20260101000000_init.sql", "CREATE TABLE a (x BIGINT);\n")
+	_, err = NewApplier(db, core.DialectMySQL, dir, ApplyOptions{}).Apply(context.Background())
+	require.ErrorContains(t, err, "20260101000000_init.sql was modified after it was applied")
+}
+
+func TestApplyRefusesOutOfOrderMigration(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeMigration(t, dir, "20260102000000_b.sql", "CREATE TABLE b (x INT);\n")
+	db := sql.OpenDB(&fakeDB{})
+	defer db.Close()
+
+	_, err := NewApplier(db, core.DialectMySQL, dir, ApplyOptions{}).Apply(context.Background())
+	require.NoError(t, err)
+
+	writeMigration(t, dir, "20260101000000_a.sql", "CREATE TABLE a (x INT);\n")
+	_, err = NewApplier(db, core.DialectMySQL, dir, ApplyOptions{}).Apply(context.Background())
+	require.ErrorContains(t, err, "older than the latest applied migration")
+}
+
+func TestApplyRefusesDestructiveStatements(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeMigration(t, dir, "20260101000000_drop.sql", "ALTER TABLE a DROP COLUMN x;\nDROP TABLE b;\nDROP INDEX i;\n")
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	_, err := NewApplier(db, core.DialectMySQL, dir, ApplyOptions{}).Apply(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ALTER TABLE a DROP COLUMN x")
+	assert.Contains(t, err.Error(), "DROP TABLE b")
+	assert.NotContains(t, err.Error(), "DROP INDEX")
+	assert.Empty(t, fake.statements())
+
+	_, err = NewApplier(db, core.DialectMySQL, dir, ApplyOptions{Unsafe: true}).Apply(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, fake.statements(), 3)
+}
+
+func TestApplyAllowsSQLiteRebuild(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	base := strings.Replace(schema, `"postgresql"`, `"sqlite"`, 1)
+	collated := strings.Replace(base, `unique = true`, "unique = true\n  collate = \"NOCASE\"", 1)
+	dropped := strings.Replace(collated, "\n  [[tables.columns]]\n  name = \"email\"", "\n  [[tables.columns]]\n  name = \"login\"", 1)
+
+	var last *core.Database
+	for i, s := range []string{base, collated, dropped} {
+		target := parse(t, s)
+		script, err := sqlite.New().GenerateChanges(diff.Databases(last, target))
+		require.NoError(t, err)
+		now := time.Date(2026, 1, 1+i, 0, 0, 0, 0, time.UTC)
+		_, err = Write(dir, "step", now, script, target)
+		require.NoError(t, err)
+		last = target
+	}
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	_, err := NewApplier(db, core.DialectSQLite, dir, ApplyOptions{Transaction: true}).Apply(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "20260103000000_step.sql: drop_column users.email")
+	assert.NotContains(t, err.Error(), "20260102000000_step.sql")
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "20260103000000_step.sql")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "20260103000000_step.json")))
+	_, err = NewApplier(db, core.DialectSQLite, dir, ApplyOptions{Transaction: true}).Apply(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, fake.statements(), `DROP TABLE "users"`)
+}
+
+func TestReplacesTable(t *testing.T) {
+	t.Parallel()
+	stmts := []string{`DROP TABLE "users"`, `ALTER TABLE "_smf_new_users" RENAME TO "users"`, `DROP TABLE "tags"`}
+	assert.True(t, replacesTable(stmts, 0))
+	assert.False(t, replacesTable(stmts, 2))
+	assert.False(t, replacesTable([]string{`DROP TABLE "tags"`, `ALTER TABLE "_smf_new_users" RENAME TO "users"`}, 0))
+}
+
+func TestTransactionalDDL(t *testing.T) {
+	t.Parallel()
+	for _, d := range []core.Dialect{core.DialectPostgreSQL, core.DialectSQLite, core.DialectMSSQL} {
+		assert.True(t, TransactionalDDL(d), d)
+	}
+	for _, d := range []core.Dialect{core.DialectMySQL, core.DialectOracle, core.DialectSnowflake, core.DialectDB2} {
+		assert.False(t, TransactionalDDL(d), d)
+	}
+}
+
+func TestApplyDryRun(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeMigration(t, dir, "20260101000000_init.sql", "CREATE TABLE a (x INT);\n")
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	var out bytes.Buffer
+	applied, err := NewApplier(db, core.DialectMySQL, dir, ApplyOptions{DryRun: true, Transaction: true, Out: &out}).
+		Apply(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Empty(t, fake.statements())
+	assert.False(t, fake.created)
+	assert.Equal(t, "-- 20260101000000_init.sql runs without a transaction: mysql commits DDL statements implicitly\n"+
+		"-- 20260101000000_init.sql\n"+
+		"CREATE TABLE a (x INT);\n", out.String())
+}
+
+func TestApplyRollsBackFailedMigration(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeMigration(t, dir, "20260101000000_init.sql", "CREATE TABLE a (x INT);\nFAIL;\n")
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	_, err := NewApplier(db, core.DialectSQLite, dir, ApplyOptions{Transaction: true}).Apply(context.Background())
+	require.ErrorContains(t, err, "statement 2: syntax error")
+	assert.Equal(t, 1, fake.rollbacks)
+	assert.Empty(t, fake.history)
+}
+
+func TestApplyFailsForeignKeyCheck(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeMigration(t, dir, "20260101000000_rebuild.sql", "PRAGMA foreign_keys = OFF;\nBEGIN TRANSACTION;\n"+
+		"ALTER TABLE \"_smf_new_orders\" RENAME TO \"orders\";\nPRAGMA foreign_key_check;\nCOMMIT;\nPRAGMA foreign_keys = ON;\n")
+	fake := &fakeDB{fkViolations: [][]driver.Value{{"orders", int64(3), "users", int64(0)}}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	_, err := NewApplier(db, core.DialectSQLite, dir, ApplyOptions{Transaction: true}).Apply(context.Background())
+	require.ErrorContains(t, err, "statement 4: foreign key violation: a row of orders references a missing row of users")
+	stmts := fake.statements()
+	assert.Equal(t, []string{"ROLLBACK", "PRAGMA foreign_keys = ON"}, stmts[len(stmts)-2:])
+	assert.NotContains(t, stmts, "COMMIT")
+	assert.Empty(t, fake.history)
+
+	fake.fkViolations = nil
+	_, err = NewApplier(db, core.DialectSQLite, dir, ApplyOptions{Transaction: true}).Apply(context.Background())
+	require.NoError(t, err)
+	stmts = fake.statements()
+	require.Len(t, fake.history, 1)
+	assert.Equal(t, "COMMIT", stmts[fake.recordedAt[0]], "recorded inside the migration's transaction")
+}
+
+func TestApplySQLiteHistory(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeMigration(t, dir, "20260101000000_init.sql", "CREATE TABLE a (x INT);\n")
+	writeMigration(t, dir, "20260102000000_rebuild.sql", "PRAGMA foreign_keys = OFF;\nBEGIN TRANSACTION;\n"+
+		"CREATE TABLE b (x INT);\nINSERT INTO missing VALUES (1);\nCOMMIT;\nPRAGMA foreign_keys = ON;\n")
+	db, err := sql.Open("sqlite", filepath.Join(dir, "app.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec("PRAGMA foreign_keys = ON")
+	require.NoError(t, err)
+
+	_, err = NewApplier(db, core.DialectSQLite, dir, ApplyOptions{Transaction: true}).Apply(context.Background())
+	require.ErrorContains(t, err, "20260102000000_rebuild.sql: statement 4")
+
+	var appliedAt string
+	require.NoError(t, db.QueryRow("SELECT applied_at FROM "+HistoryTable).Scan(&appliedAt))
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}Z$`, appliedAt)
+	var count int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM "+HistoryTable).Scan(&count))
+	assert.Equal(t, 1, count, "the failed migration is not recorded")
+	var foreignKeys bool
+	require.NoError(t, db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys))
+	assert.True(t, foreignKeys)
+}
+
+func TestApplySkipsTransactionForSelfManagedScripts(t *testing.T) {
+	t.Parallel()
+	p := &pending{stmts: []string{"PRAGMA foreign_keys = OFF", "BEGIN TRANSACTION", "COMMIT"}}
+	a := NewApplier(nil, core.DialectSQLite, "", ApplyOptions{Transaction: true})
+	tx, reason := a.inTransaction(p)
+	assert.False(t, tx)
+	assert.Equal(t, "the migration controls its own transaction", reason)
+}
+
+func TestHistoryStatements(t *testing.T) {
+	t.Parallel()
+	assert.Contains(t, history{core.DialectPostgreSQL}.insertStatement(), "VALUES ($1, $2, $3, $4, $5, $6)")
+	assert.Contains(t, history{core.DialectMSSQL}.insertStatement(), "VALUES (@p1, @p2")
+	assert.Contains(t, history{core.DialectOracle}.createStatement(), "version VARCHAR2(14) NOT NULL PRIMARY KEY")
+	assert.Contains(t, history{core.DialectMySQL}.createStatement(), "applied_at DATETIME(6) NOT NULL")
+}
@@ -0,0 +1,145 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"smf/internal/core"
+)
+
+// HistoryTable is the table that records the applied migrations.
+const HistoryTable = "smf_schema_migrations"
+
+// Applied is a migration recorded in the history table.
+type Applied struct {
+	Version  string
+	Name     string
+	Checksum string
+}
+
+// record is a history row written after a migration ran.
+type record struct {
+	version   string
+	name      string
+	checksum  string
+	appliedAt time.Time
+	duration  time.Duration
+	appliedBy string
+}
+
+// execer runs the statements of a migration: a database connection or a
+// transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// history reads and writes the history table of a dialect.
+type history struct {
+	dialect core.Dialect
+}
+
+// createStatement renders the CREATE TABLE statement of the history table.
+func (h history) createStatement() string {
+	varchar, timestamp, bigint := "VARCHAR", "TIMESTAMP", "BIGINT"
+	switch h.dialect {
+	case core.DialectOracle:
+		varchar, bigint = "VARCHAR2", "NUMBER(19)"
+	case core.DialectMSSQL:
+		varchar, timestamp = "NVARCHAR", "DATETIME2"
+	case core.DialectMySQL, core.DialectMariaDB, core.DialectTiDB:
+		timestamp = "DATETIME(6)"
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n"+
+		"  version %s(14) NOT NULL PRIMARY KEY,\n"+
+		"  name %[2]s(255) NOT NULL,\n"+
+		"  checksum %[2]s(64) NOT NULL,\n"+
+		"  applied_at %s NOT NULL,\n"+
+		"  execution_ms %s NOT NULL,\n"+
+		"  applied_by %[2]s(255) NOT NULL\n"+
+		")", HistoryTable, varchar, timestamp, bigint)
+}
+
+// insertStatement renders the INSERT statement of a history row with the
+// bind parameter syntax of the dialect's driver.
+func (h history) insertStatement() string {
+	params := make([]string, 6)
+	for i := range params {
+		params[i] = h.param(i + 1)
+	}
+	return fmt.Sprintf("INSERT INTO %s (version, name, checksum, applied_at, execution_ms, applied_by) VALUES (%s)",
+		HistoryTable, strings.Join(params, ", "))
+}
+
+func (h history) param(n int) string {
+	switch h.dialect {
+	case core.DialectPostgreSQL:
+		return "$" + strconv.Itoa(n)
+	case core.DialectMSSQL:
+		return "@p" + strconv.Itoa(n)
+	case core.DialectOracle:
+		return ":" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// exists reports whether the history table exists. Any error of the probe
+// query is taken as a missing table.
+func (h history) exists(ctx context.Context, db *sql.DB) bool {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM "+HistoryTable+" WHERE 1 = 0")
+	if err != nil {
+		return false
+	}
+	rows.Close()
+	return true
+}
+
+// applied returns the recorded migrations by version.
+func (h history) applied(ctx context.Context, db *sql.DB) (map[string]*Applied, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version, name, checksum FROM "+HistoryTable)
+	if err != nil {
+		return nil, fmt.Errorf("migration: read %s: %w", HistoryTable, err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]*Applied)
+	for rows.Next() {
+		var a Applied
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum); err != nil {
+			return nil, fmt.Errorf("migration: read %s: %w", HistoryTable, err)
+		}
+		out[a.Version] = &a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("migration: read %s: %w", HistoryTable, err)
+	}
+	return out, nil
+}
+
+// sqliteTimeFormat is the ISO-8601 form in which applied_at is stored in
+// SQLite, which has no timestamp type and understands this form in its date
+// and time functions.
+const sqliteTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// timestamp returns t as the value bound to applied_at. SQLite drivers
+// store time.Time in a format of their own, so it is formatted as text.
+func (h history) timestamp(t time.Time) any {
+	if h.dialect == core.DialectSQLite {
+		return t.Format(sqliteTimeFormat)
+	}
+	return t
+}
+
+func (h history) record(ctx context.Context, ex execer, r record) error {
+	_, err := ex.ExecContext(ctx, h.insertStatement(),
+		r.version, r.name, r.checksum, h.timestamp(r.appliedAt), r.duration.Milliseconds(), r.appliedBy)
+	if err != nil {
+		return fmt.Errorf("migration: record %s in %s: %w", r.version, HistoryTable, err)
+	}
+	return nil
+}
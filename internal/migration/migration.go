@@ -0,0 +1,167 @@
+// Package migration manages the migrations directory. Every migration is a
+// SQL file named <version>_<name>.sql, where the version is the UTC time the
+// migration was created at. Next to it a <version>_<name>.json snapshot
+// stores the schema the migration migrates to, so the last migrated state
+// can be restored without connecting to a database. Applied migrations are
+// recorded with a checksum of their file in the smf_schema_migrations table
+// of the target database.
+package migration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"smf/internal/core"
+	"smf/internal/generate"
+)
+
+// VersionLayout is the time layout of migration versions.
+const VersionLayout = "20060102150405"
+
+const (
+	sqlExt      = ".sql"
+	snapshotExt = ".json"
+)
+
+// Migration is a migration file in the migrations directory.
+type Migration struct {
+	// Version is the creation time of the migration in VersionLayout.
+	Version string
+	// Name is the descriptive name of the migration.
+	Name string
+	// Path is the path of the SQL file.
+	Path string
+}
+
+// SnapshotPath returns the path of the schema snapshot stored next to the
+// SQL file.
+func (m *Migration) SnapshotPath() string {
+	return strings.TrimSuffix(m.Path, sqlExt) + snapshotExt
+}
+
+var fileRe = regexp.MustCompile(`^(\d{14})_([a-z0-9_]+)\.sql$`)
+
+// List returns the migrations in dir ordered by version. A missing
+// directory has no migrations. Files that do not follow the naming scheme
+// are ignored.
+func List(dir string) ([]*Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("migration: read directory %s: %w", dir, err)
+	}
+
+	var out []*Migration
+	for _, e := range entries {
+		m := fileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		out = append(out, &Migration{Version: m[1], Name: m[2], Path: filepath.Join(dir, e.Name())})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// LastState returns the schema snapshot of the latest migration in dir, or
+// nil when there are no migrations yet.
+func LastState(dir string) (*core.Database, error) {
+	migrations, err := List(dir)
+	if err != nil || len(migrations) == 0 {
+		return nil, err
+	}
+	return ReadSnapshot(migrations[len(migrations)-1])
+}
+
+// ReadSnapshot reads the schema snapshot of m.
+func ReadSnapshot(m *Migration) (*core.Database, error) {
+	data, err := os.ReadFile(m.SnapshotPath())
+	if err != nil {
+		return nil, fmt.Errorf("migration: read snapshot of %s: %w", filepath.Base(m.Path), err)
+	}
+	var db core.Database
+	if err := json.Unmarshal(data, &db); err != nil {
+		return nil, fmt.Errorf("migration: decode snapshot of %s: %w", filepath.Base(m.Path), err)
+	}
+	return &db, nil
+}
+
+var nameRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Name normalizes a descriptive migration name to lower snake case.
+func Name(s string) string {
+	return strings.Trim(nameRe.ReplaceAllString(strings.ToLower(s), "_"), "_")
+}
+
+// Write stores script as a new migration in dir, together with the
+// snapshot of target. The directory is created when it does not exist. An
+// existing migration with the same version is never overwritten.
+func Write(dir, name string, now time.Time, script *generate.Script, target *core.Database) (*Migration, error) {
+	name = Name(name)
+	if name == "" {
+		return nil, errors.New("migration: name is empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("migration: create directory %s: %w", dir, err)
+	}
+
+	version := now.UTC().Format(VersionLayout)
+	m := &Migration{Version: version, Name: name, Path: filepath.Join(dir, version+"_"+name+sqlExt)}
+	existing, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(existing); n > 0 && existing[n-1].Version >= version {
+		return nil, fmt.Errorf("migration: version %s is not newer than the latest migration %s",
+			version, filepath.Base(existing[n-1].Path))
+	}
+
+	snapshot, err := json.MarshalIndent(target, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("migration: encode snapshot: %w", err)
+	}
+	if err := writeNew(m.SnapshotPath(), append(snapshot, '\n')); err != nil {
+		return nil, err
+	}
+	if err := writeNew(m.Path, []byte(content(script))); err != nil {
+		os.Remove(m.SnapshotPath())
+		return nil, err
+	}
+	return m, nil
+}
+
+// content renders script as the migration file, with the generator
+// warnings as leading comments.
+func content(script *generate.Script) string {
+	var sb strings.Builder
+	for _, w := range script.Warnings {
+		sb.WriteString("-- WARNING: " + w + "\n")
+	}
+	if len(script.Warnings) > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(script.String())
+	return sb.String()
+}
+
+func writeNew(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("migration: create %s: %w", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("migration: write %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,104 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+	"smf/internal/pars/toml"
+)
+
+const schema = `
+[database]
+name = "app"
+dialect = "postgresql"
+
+[[tables]]
+name = "users"
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "email"
+  type = "varchar(255)"
+  unique = true
+`
+
+func parse(t *testing.T, s string) *core.Database {
+	t.Helper()
+	db, err := toml.NewParser().Parse(strings.NewReader(s))
+	require.NoError(t, err)
+	return db
+}
+
+func TestWriteAndLastState(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "migrations")
+	target := parse(t, schema)
+	script := &generate.Script{Statements: []string{"CREATE TABLE users ()"}, Warnings: []string{"table users: note"}}
+
+	now := time.Date(2026, 2, 15, 12, 30, 0, 0, time.UTC)
+	m, err := Write(dir, "Add Users-Table", now, script, target)
+	require.NoError(t, err)
+	assert.Equal(t, "20260215123000", m.Version)
+	assert.Equal(t, "add_users_table", m.Name)
+	assert.Equal(t, filepath.Join(dir, "20260215123000_add_users_table.sql"), m.Path)
+
+	content, err := os.ReadFile(m.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "-- WARNING: table users: note\n\nCREATE TABLE users ();\n", string(content))
+
+	last, err := LastState(dir)
+	require.NoError(t, err)
+	assert.True(t, diff.Databases(last, target).Empty())
+
+	_, err = Write(dir, "again", now, script, target)
+	require.ErrorContains(t, err, "not newer")
+}
+
+func TestListOrdersAndSkipsForeignFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, name := range []string{"20260301000000_b.sql", "20260101000000_a.sql", "README.md", "draft.sql"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+
+	migrations, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, "a", migrations[0].Name)
+	assert.Equal(t, "b", migrations[1].Name)
+	assert.Equal(t, filepath.Join(dir, "20260301000000_b.json"), migrations[1].SnapshotPath())
+}
+
+func TestLastStateWithoutMigrations(t *testing.T) {
+	t.Parallel()
+	last, err := LastState(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Nil(t, last)
+}
+
+func TestLastStateMissingSnapshot(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20260101000000_a.sql"), nil, 0o644))
+	_, err := LastState(dir)
+	require.ErrorContains(t, err, "read snapshot")
+}
+
+func TestName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "add_users_table", Name("  Add users table!"))
+	assert.Empty(t, Name("--"))
+}
@@ -0,0 +1,242 @@
+package migration
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Split splits a migration script into the statements to execute, in the
+// format written by generate.Script.String. Statements end with a semicolon
+// outside of quotes, comments, dollar-quoted bodies, and BEGIN … END blocks,
+// or with a line that only holds "/", as in Oracle scripts, or GO, as in SQL
+// Server scripts. Blocks keep
+// their final semicolon; other statements are returned without it. Comments
+// before a statement are dropped.
+func Split(script string) []string {
+	s := &splitter{src: script}
+	for s.pos < len(s.src) {
+		s.step()
+	}
+	s.flush(false)
+	return s.stmts
+}
+
+type splitter struct {
+	src   string
+	pos   int
+	cur   strings.Builder
+	stmts []string
+	// depth counts the open BEGIN and CASE keywords of the statement.
+	depth int
+	// block is set when the statement contains a BEGIN … END block.
+	block bool
+	// words counts the keywords of the statement read so far.
+	words int
+	// lastWord is the last keyword read, in upper case.
+	lastWord string
+}
+
+var dollarTagRe = regexp.MustCompile(`^\$[A-Za-z_]*\$`)
+
+func (s *splitter) step() {
+	rest := s.src[s.pos:]
+	if s.comment(rest) || s.quoted(rest) {
+		return
+	}
+	switch c := rest[0]; {
+	case s.separatorLine(rest):
+		s.flush(false)
+		s.skipLine()
+	case c == ';':
+		s.semicolon()
+	case isIdentStart(rune(c)):
+		s.word()
+	default:
+		s.cur.WriteByte(c)
+		s.pos++
+	}
+}
+
+// comment consumes a comment at the start of rest. Comments inside a
+// statement are kept.
+func (s *splitter) comment(rest string) bool {
+	var n int
+	switch {
+	case strings.HasPrefix(rest, "--"):
+		n = strings.IndexByte(rest, '\n')
+	case strings.HasPrefix(rest, "/*"):
+		if n = strings.Index(rest[2:], "*/"); n >= 0 {
+			n += 4
+		}
+	default:
+		return false
+	}
+	if n < 0 {
+		n = len(rest)
+	}
+	if strings.TrimSpace(s.cur.String()) != "" {
+		s.cur.WriteString(rest[:n])
+	}
+	s.pos += n
+	return true
+}
+
+// quoted copies a quoted string or identifier or a dollar-quoted body at
+// the start of rest.
+func (s *splitter) quoted(rest string) bool {
+	switch c := rest[0]; c {
+	case '\'', '"', '`':
+		s.copyUntil(1, string(c))
+		return true
+	case '$':
+		tag := dollarTagRe.FindString(rest)
+		if tag == "" || s.afterIdent() {
+			return false
+		}
+		s.copyUntil(len(tag), tag)
+		return true
+	default:
+		return false
+	}
+}
+
+// copyUntil copies an opening delimiter of length open and everything up to
+// and including the closing delimiter. A doubled quote closes and reopens
+// the quote, which keeps it intact.
+func (s *splitter) copyUntil(open int, closing string) {
+	end := strings.Index(s.src[s.pos+open:], closing)
+	if end < 0 {
+		end = len(s.src)
+	} else {
+		end = s.pos + open + end + len(closing)
+	}
+	s.cur.WriteString(s.src[s.pos:end])
+	s.pos = end
+}
+
+func (s *splitter) afterIdent() bool {
+	return s.pos > 0 && isIdentPart(rune(s.src[s.pos-1]))
+}
+
+// separatorLine reports whether rest starts with a "/" or GO that is alone
+// on its line.
+func (s *splitter) separatorLine(rest string) bool {
+	var n int
+	switch {
+	case rest[0] == '/':
+		n = 1
+	case len(rest) >= 2 && strings.EqualFold(rest[:2], "GO"):
+		n = 2
+	default:
+		return false
+	}
+	start := strings.LastIndexByte(s.src[:s.pos], '\n') + 1
+	end := strings.IndexByte(rest, '\n')
+	if end < 0 {
+		end = len(rest)
+	}
+	return strings.TrimSpace(s.src[start:s.pos]) == "" && strings.TrimSpace(rest[n:end]) == ""
+}
+
+func (s *splitter) skipLine() {
+	if end := strings.IndexByte(s.src[s.pos:], '\n'); end >= 0 {
+		s.pos += end + 1
+	} else {
+		s.pos = len(s.src)
+	}
+}
+
+func (s *splitter) semicolon() {
+	s.pos++
+	if s.depth > 0 {
+		s.cur.WriteByte(';')
+		return
+	}
+	s.flush(s.block && s.lastWord == "END")
+}
+
+// word reads a keyword or identifier and tracks the BEGIN … END blocks.
+func (s *splitter) word() {
+	start := s.pos
+	for s.pos < len(s.src) && isIdentPart(rune(s.src[s.pos])) {
+		s.pos++
+	}
+	w := strings.ToUpper(s.src[start:s.pos])
+	s.cur.WriteString(s.src[start:s.pos])
+	s.words++
+
+	switch w {
+	case "BEGIN":
+		if s.words > 1 || !transactionStart(s.nextWord()) {
+			s.depth++
+			s.block = true
+		}
+	case "CASE":
+		if s.lastWord != "END" {
+			s.depth++
+		}
+	case "END":
+		if s.depth > 0 && !loopEnd(s.nextWord()) {
+			s.depth--
+		}
+	}
+	s.lastWord = w
+}
+
+// nextWord returns the next keyword in upper case, or ";" when the
+// statement ends first.
+func (s *splitter) nextWord() string {
+	rest := strings.TrimLeftFunc(s.src[s.pos:], unicode.IsSpace)
+	end := strings.IndexFunc(rest, func(r rune) bool { return !isIdentPart(r) })
+	switch {
+	case end < 0:
+		return strings.ToUpper(rest)
+	case end == 0:
+		return rest[:1]
+	default:
+		return strings.ToUpper(rest[:end])
+	}
+}
+
+// transactionStart reports whether a statement starting with BEGIN
+// followed by next starts a transaction rather than a block.
+func transactionStart(next string) bool {
+	switch next {
+	case "", ";", "TRANSACTION", "TRAN", "WORK", "DEFERRED", "IMMEDIATE", "EXCLUSIVE", "DISTRIBUTED":
+		return true
+	default:
+		return false
+	}
+}
+
+// loopEnd reports whether END followed by next closes a control statement
+// that did not open a block.
+func loopEnd(next string) bool {
+	switch next {
+	case "IF", "LOOP", "WHILE", "REPEAT", "FOR":
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *splitter) flush(semicolon bool) {
+	stmt := strings.TrimSpace(s.cur.String())
+	if stmt != "" && semicolon {
+		stmt += ";"
+	}
+	if stmt != "" {
+		s.stmts = append(s.stmts, stmt)
+	}
+	s.cur.Reset()
+	s.depth, s.block, s.words, s.lastWord = 0, false, 0, ""
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || r == '$' || r == '#' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,82 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"smf/internal/generate"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		stmts []string
+		want  []string
+	}{
+		{
+			name:  "plain statements",
+			stmts: []string{"CREATE TABLE a (x INT)", "INSERT INTO a VALUES ('x;y')", `COMMENT ON TABLE "a;b" IS 'it''s'`},
+		},
+		{
+			name: "dollar-quoted function",
+			stmts: []string{"CREATE OR REPLACE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.x := now();\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql",
+				"SELECT 1"},
+		},
+		{
+			name: "trigger without final semicolon",
+			stmts: []string{"BEGIN TRANSACTION",
+				"CREATE TRIGGER t AFTER UPDATE ON a FOR EACH ROW\nBEGIN\n  UPDATE a SET x = 1 WHERE rowid = NEW.rowid;\nEND",
+				"COMMIT"},
+			want: []string{"BEGIN TRANSACTION",
+				"CREATE TRIGGER t AFTER UPDATE ON a FOR EACH ROW\nBEGIN\n  UPDATE a SET x = 1 WHERE rowid = NEW.rowid;\nEND;",
+				"COMMIT"},
+		},
+		{
+			name: "PL/SQL block",
+			stmts: []string{"BEGIN\n  EXECUTE IMMEDIATE 'CREATE SEQUENCE s';\nEXCEPTION\n  WHEN OTHERS THEN\n" +
+				"    IF SQLCODE != -955 THEN\n      RAISE;\n    END IF;\nEND;",
+				"CREATE TABLE b (x NUMBER)"},
+		},
+		{
+			name:  "CASE expression",
+			stmts: []string{"CREATE VIEW v AS SELECT CASE WHEN x > 1 THEN 'a' ELSE 'b' END AS y FROM a", "SELECT 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			want := tt.want
+			if want == nil {
+				want = tt.stmts
+			}
+			script := &generate.Script{Statements: tt.stmts}
+			assert.Equal(t, want, Split(script.String()))
+		})
+	}
+}
+
+func TestSplitBatchSeparator(t *testing.T) {
+	t.Parallel()
+	script := &generate.Script{
+		Statements: []string{
+			"CREATE TABLE [a] ([x] INT, [go] INT)",
+			"CREATE TRIGGER [a_x] ON [a] AFTER UPDATE AS\nBEGIN\n  SET NOCOUNT ON;\n  UPDATE [a] SET [x] = 1;\nEND",
+			"CREATE VIEW [v] AS SELECT [x]\nFROM [a]",
+		},
+		BatchSeparator: "GO",
+	}
+	assert.Equal(t, []string{
+		"CREATE TABLE [a] ([x] INT, [go] INT)",
+		"CREATE TRIGGER [a_x] ON [a] AFTER UPDATE AS\nBEGIN\n  SET NOCOUNT ON;\n  UPDATE [a] SET [x] = 1;\nEND;",
+		"CREATE VIEW [v] AS SELECT [x]\nFROM [a]",
+	}, Split(script.String()))
+	assert.Equal(t, []string{"SELECT 1", "SELECT 2"}, Split("SELECT 1\ngo\nSELECT 2\n"))
+}
+
+func TestSplitDropsLeadingComments(t *testing.T) {
+	t.Parallel()
+	script := "-- WARNING: table a: skipped\n\n/* header */\nCREATE TABLE a (\n  x INT -- the x\n);\n"
+	assert.Equal(t, []string{"CREATE TABLE a (\n  x INT -- the x\n)"}, Split(script))
+}
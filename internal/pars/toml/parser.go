@@ -17,26 +17,28 @@ import (
 )
 
 // schemaFile is the top-level TOML document.
-// In the new schema format, [database], [validation], and [[tables]]
-// are all top-level keys (tables and validation are NOT nested under a database).
+// In the new schema format, [database], [validation], [[tables]], and
+// [[views]] are all top-level keys (tables, views, and validation are NOT
+// nested under a database).
 type schemaFile struct {
-	Database   tomlDatabase    `toml:"database"`
-	Validation *tomlValidation `toml:"validation"`
-	Tables     []tomlTable     `toml:"tables"`
+	Database   tomlDatabase    `toml:"database,omitempty"`
+	Validation *tomlValidation `toml:"validation,omitempty"`
+	Tables     []tomlTable     `toml:"tables,omitempty"`
+	Views      []tomlView      `toml:"views,omitempty"`
 }
 
 // tomlDatabase maps [database].
 type tomlDatabase struct {
-	Name    string `toml:"name"`
-	Dialect string `toml:"dialect"`
+	Name    string `toml:"name,omitempty"`
+	Dialect string `toml:"dialect,omitempty"`
 }
 
 // tomlValidation maps [validation].
 type tomlValidation struct {
-	MaxTableNameLength          int    `toml:"max_table_name_length"`
-	MaxColumnNameLength         int    `toml:"max_column_name_length"`
-	AutoGenerateConstraintNames bool   `toml:"auto_generate_constraint_names"`
-	AllowedNamePattern          string `toml:"allowed_name_pattern"`
+	MaxTableNameLength          int    `toml:"max_table_name_length,omitzero"`
+	MaxColumnNameLength         int    `toml:"max_column_name_length,omitzero"`
+	AutoGenerateConstraintNames bool   `toml:"auto_generate_constraint_names,omitempty"`
+	AllowedNamePattern          string `toml:"allowed_name_pattern,omitempty"`
 }
 
 // Parser reads smf TOML schema files.
@@ -82,6 +84,9 @@ func (p *Parser) Parse(r io.Reader) (*core.Database, error) {
 		}
 		db.Tables = append(db.Tables, t)
 	}
+	for i := range sf.Views {
+		db.Views = append(db.Views, view(&sf.Views[i]))
+	}
 
 	if err := validate.Database(db); err != nil {
 		return nil, fmt.Errorf("toml: validate database: %w", err)
@@ -10,112 +10,120 @@ import (
 
 // tomlColumn maps [[tables.columns]].
 type tomlColumn struct {
-	Name          string `toml:"name"`
-	Type          string `toml:"type"`
-	PrimaryKey    bool   `toml:"primary_key"`
-	AutoIncrement bool   `toml:"auto_increment"`
-	Nullable      bool   `toml:"nullable"`
-	Comment       string `toml:"comment"`
-	Collate       string `toml:"collate"`
-	Charset       string `toml:"charset"`
+	Name          string `toml:"name,omitempty"`
+	Type          string `toml:"type,omitempty"`
+	PrimaryKey    bool   `toml:"primary_key,omitempty"`
+	AutoIncrement bool   `toml:"auto_increment,omitempty"`
+	Nullable      bool   `toml:"nullable,omitempty"`
+	Comment       string `toml:"comment,omitempty"`
+	Collate       string `toml:"collate,omitempty"`
+	Charset       string `toml:"charset,omitempty"`
 
 	// DefaultValue accepts string, bool, or number from TOML.
 	// The converter normalizes everything to a string.
 	// In the new schema this is the `default` key (was `default_value`).
-	DefaultValue any `toml:"default"`
+	DefaultValue any `toml:"default,omitempty"`
 
 	// OnUpdate is used for MySQL ON UPDATE CURRENT_TIMESTAMP when there is
 	// no inline FK (references are empty).  When references ARE set,
 	// on_update is treated as a referential action (CASCADE, RESTRICT, …).
-	OnUpdate string `toml:"on_update"`
-	OnDelete string `toml:"on_delete"`
+	OnUpdate string `toml:"on_update,omitempty"`
+	OnDelete string `toml:"on_delete,omitempty"`
 
-	Unique     bool     `toml:"unique"`
-	Check      string   `toml:"check"`
-	References string   `toml:"references"`
-	EnumValues []string `toml:"values"`
+	Unique     bool     `toml:"unique,omitempty"`
+	Check      string   `toml:"check,omitempty"`
+	References string   `toml:"references,omitempty"`
+	EnumValues []string `toml:"values,omitempty"`
 
 	// RawType is a single dialect-specific type override string.
 	// When set, it applies to the dialect declared in [database].
 	// For all other dialects the portable `type` value is used.
 	// This replaces the old `type_overrides` map.
-	RawType string `toml:"raw_type"`
+	RawType string `toml:"raw_type,omitempty"`
 
-	IsGenerated          bool   `toml:"is_generated"`
-	GenerationExpression string `toml:"generation_expression"`
-	GenerationStorage    string `toml:"generation_storage"` // "VIRTUAL" or "STORED"
+	IsGenerated          bool   `toml:"is_generated,omitempty"`
+	GenerationExpression string `toml:"generation_expression,omitempty"`
+	GenerationStorage    string `toml:"generation_storage,omitempty"` // "VIRTUAL" or "STORED"
 
 	// Invisible hides the column from SELECT * and some metadata views.
-	Invisible bool `toml:"invisible"`
+	Invisible bool `toml:"invisible,omitempty"`
 
 	// Identity / sequence fields for MSSQL, Oracle, DB2, PostgreSQL, Snowflake.
-	IdentitySeed       int64  `toml:"identity_seed"`
-	IdentityIncrement  int64  `toml:"identity_increment"`
-	IdentityGeneration string `toml:"identity_generation"` // "ALWAYS" or "BY DEFAULT"
-	SequenceName       string `toml:"sequence_name"`
+	IdentitySeed       int64  `toml:"identity_seed,omitzero"`
+	IdentityIncrement  int64  `toml:"identity_increment,omitzero"`
+	IdentityGeneration string `toml:"identity_generation,omitempty"` // "ALWAYS" or "BY DEFAULT"
+	SequenceName       string `toml:"sequence_name,omitempty"`
 
 	// Dialect-specific column option groups.
-	MySQL  *tomlMySQLColumnOptions  `toml:"mysql"`
-	TiDB   *tomlTiDBColumnOptions   `toml:"tidb"`
-	Oracle *tomlOracleColumnOptions `toml:"oracle"`
-	MSSQL  *tomlMSSQLColumnOptions  `toml:"mssql"`
-	DB2    *tomlDB2ColumnOptions    `toml:"db2"`
-	SQLite *tomlSQLiteColumnOptions `toml:"sqlite"`
+	MySQL      *tomlMySQLColumnOptions      `toml:"mysql,omitempty"`
+	TiDB       *tomlTiDBColumnOptions       `toml:"tidb,omitempty"`
+	PostgreSQL *tomlPostgreSQLColumnOptions `toml:"postgresql,omitempty"`
+	Oracle     *tomlOracleColumnOptions     `toml:"oracle,omitempty"`
+	MSSQL      *tomlMSSQLColumnOptions      `toml:"mssql,omitempty"`
+	DB2        *tomlDB2ColumnOptions        `toml:"db2,omitempty"`
+	SQLite     *tomlSQLiteColumnOptions     `toml:"sqlite,omitempty"`
 }
 
 // tomlMySQLColumnOptions maps [tables.columns.mysql].
 type tomlMySQLColumnOptions struct {
-	ColumnFormat             string `toml:"column_format"`
-	Storage                  string `toml:"storage"`
-	PrimaryEngineAttribute   string `toml:"primary_engine_attribute"`
-	SecondaryEngineAttribute string `toml:"secondary_engine_attribute"`
+	ColumnFormat             string  `toml:"column_format,omitempty"`
+	Storage                  string  `toml:"storage,omitempty"`
+	PrimaryEngineAttribute   string  `toml:"primary_engine_attribute,omitempty"`
+	SecondaryEngineAttribute string  `toml:"secondary_engine_attribute,omitempty"`
+	SRID                     *uint32 `toml:"srid,omitempty"`
 }
 
 // tomlTiDBColumnOptions maps [tables.columns.tidb].
 type tomlTiDBColumnOptions struct {
-	ShardBits uint64  `toml:"shard_bits"`
-	RangeBits *uint64 `toml:"range_bits"`
+	ShardBits uint64  `toml:"shard_bits,omitzero"`
+	RangeBits *uint64 `toml:"range_bits,omitempty"`
+}
+
+// tomlPostgreSQLColumnOptions maps [tables.columns.postgresql].
+type tomlPostgreSQLColumnOptions struct {
+	Storage     string `toml:"storage,omitempty"`
+	Compression string `toml:"compression,omitempty"`
 }
 
 // tomlOracleColumnOptions maps [tables.columns.oracle].
 type tomlOracleColumnOptions struct {
-	Encrypt             bool   `toml:"encrypt"`
-	EncryptionAlgorithm string `toml:"encryption_algorithm"`
-	Salt                *bool  `toml:"salt"`
-	DefaultOnNull       bool   `toml:"default_on_null"`
+	Encrypt             bool   `toml:"encrypt,omitempty"`
+	EncryptionAlgorithm string `toml:"encryption_algorithm,omitempty"`
+	Salt                *bool  `toml:"salt,omitempty"`
+	DefaultOnNull       bool   `toml:"default_on_null,omitempty"`
 }
 
 // tomlMSSQLColumnOptions maps [tables.columns.mssql].
 type tomlMSSQLColumnOptions struct {
-	FileStream                bool                      `toml:"file_stream"`
-	Sparse                    bool                      `toml:"sparse"`
-	RowGUIDCol                bool                      `toml:"row_guid_col"`
-	IdentityNotForReplication bool                      `toml:"identity_not_for_replication"`
-	Persisted                 bool                      `toml:"persisted"`
-	AlwaysEncrypted           *tomlMSSQLAlwaysEncrypted `toml:"always_encrypted"`
-	DataMasking               *tomlMSSQLDataMasking     `toml:"data_masking"`
+	FileStream                bool                      `toml:"file_stream,omitempty"`
+	Sparse                    bool                      `toml:"sparse,omitempty"`
+	RowGUIDCol                bool                      `toml:"row_guid_col,omitempty"`
+	IdentityNotForReplication bool                      `toml:"identity_not_for_replication,omitempty"`
+	Persisted                 bool                      `toml:"persisted,omitempty"`
+	AlwaysEncrypted           *tomlMSSQLAlwaysEncrypted `toml:"always_encrypted,omitempty"`
+	DataMasking               *tomlMSSQLDataMasking     `toml:"data_masking,omitempty"`
 }
 
 type tomlMSSQLAlwaysEncrypted struct {
-	ColumnEncryptionKey string `toml:"column_encryption_key"`
-	EncryptionType      string `toml:"encryption_type"`
-	Algorithm           string `toml:"algorithm"`
+	ColumnEncryptionKey string `toml:"column_encryption_key,omitempty"`
+	EncryptionType      string `toml:"encryption_type,omitempty"`
+	Algorithm           string `toml:"algorithm,omitempty"`
 }
 
 type tomlMSSQLDataMasking struct {
-	Function string `toml:"function"`
+	Function string `toml:"function,omitempty"`
 }
 
 // tomlDB2ColumnOptions maps [tables.columns.db2].
 type tomlDB2ColumnOptions struct {
-	InlineLength     *int  `toml:"inline_length"`
-	Compress         *bool `toml:"compress"`
-	ImplicitlyHidden bool  `toml:"implicitly_hidden"`
+	InlineLength     *int  `toml:"inline_length,omitempty"`
+	Compress         *bool `toml:"compress,omitempty"`
+	ImplicitlyHidden bool  `toml:"implicitly_hidden,omitempty"`
 }
 
 // tomlSQLiteColumnOptions maps [tables.columns.sqlite].
 type tomlSQLiteColumnOptions struct {
-	StrictAutoincrement bool `toml:"strict_autoincrement"`
+	StrictAutoincrement bool `toml:"strict_autoincrement,omitempty"`
 }
 
 func (p *Parser) column(tc *tomlColumn) (*core.Column, error) {
@@ -163,6 +171,7 @@ func resolveColumnType(col *core.Column, tc *tomlColumn) error {
 		if strings.EqualFold(portableType, "enum") && len(tc.EnumValues) > 0 {
 			portableType = core.BuildEnumTypeRaw(tc.EnumValues)
 		}
+		col.PortableType = portableType
 		col.Type = core.NormalizeDataType(portableType)
 	}
 
@@ -202,6 +211,7 @@ func applyColumnDialectOptions(col *core.Column, tc *tomlColumn) {
 			Storage:                  tc.MySQL.Storage,
 			PrimaryEngineAttribute:   tc.MySQL.PrimaryEngineAttribute,
 			SecondaryEngineAttribute: tc.MySQL.SecondaryEngineAttribute,
+			SRID:                     tc.MySQL.SRID,
 		}
 	}
 	if tc.TiDB != nil {
@@ -210,6 +220,12 @@ func applyColumnDialectOptions(col *core.Column, tc *tomlColumn) {
 			RangeBits: tc.TiDB.RangeBits,
 		}
 	}
+	if tc.PostgreSQL != nil {
+		col.PostgreSQL = &core.PostgreSQLColumnOptions{
+			Storage:     tc.PostgreSQL.Storage,
+			Compression: tc.PostgreSQL.Compression,
+		}
+	}
 	if tc.Oracle != nil {
 		col.Oracle = &core.OracleColumnOptions{
 			Encrypt:             tc.Oracle.Encrypt,
@@ -130,6 +130,35 @@ name = "items"
 	assert.Empty(t, col.RawType)
 }
 
+func TestParsePortableTypePreserved(t *testing.T) {
+	t.Parallel()
+	const schema = `
+[database]
+name = "testdb"
+dialect = "mysql"
+
+[[tables]]
+name = "items"
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "price"
+  type = " decimal(10,2) "
+`
+	p := NewParser()
+	db, err := p.Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+
+	assert.Equal(t, "bigint", db.Tables[0].FindColumn("id").PortableType)
+	price := db.Tables[0].FindColumn("price")
+	assert.Equal(t, "decimal(10,2)", price.PortableType)
+	assert.Equal(t, core.DataTypeFloat, price.Type)
+}
+
 func TestParseBooleanDefaultValue(t *testing.T) {
 	t.Parallel()
 	const schema = `
@@ -653,3 +682,38 @@ name = "items"
 	assert.Contains(t, err.Error(), "duplicate column name")
 	assert.Contains(t, err.Error(), "id")
 }
+
+func TestParseColumnOptionsPostgreSQL(t *testing.T) {
+	t.Parallel()
+	const schema = `
+[database]
+name = "testdb"
+dialect = "postgresql"
+
+[[tables]]
+name = "items"
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "payload"
+  type = "json"
+
+    [tables.columns.postgresql]
+    storage     = "EXTERNAL"
+    compression = "lz4"
+`
+	p := NewParser()
+	db, err := p.Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+
+	col := db.Tables[0].FindColumn("payload")
+	require.NotNil(t, col)
+	require.NotNil(t, col.PostgreSQL)
+	assert.Equal(t, "EXTERNAL", col.PostgreSQL.Storage)
+	assert.Equal(t, "lz4", col.PostgreSQL.Compression)
+	assert.Nil(t, db.Tables[0].FindColumn("id").PostgreSQL)
+}
@@ -6,15 +6,15 @@ import (
 
 // tomlConstraint maps [[tables.constraints]].
 type tomlConstraint struct {
-	Name              string   `toml:"name"`
-	Type              string   `toml:"type"`
-	Columns           []string `toml:"columns"`
-	ReferencedTable   string   `toml:"referenced_table"`
-	ReferencedColumns []string `toml:"referenced_columns"`
-	OnDelete          string   `toml:"on_delete"`
-	OnUpdate          string   `toml:"on_update"`
-	CheckExpression   string   `toml:"check_expression"`
-	Enforced          *bool    `toml:"enforced"` // pointer: absent -> true/not supported
+	Name              string   `toml:"name,omitempty"`
+	Type              string   `toml:"type,omitempty"`
+	Columns           []string `toml:"columns,omitempty"`
+	ReferencedTable   string   `toml:"referenced_table,omitempty"`
+	ReferencedColumns []string `toml:"referenced_columns,omitempty"`
+	OnDelete          string   `toml:"on_delete,omitempty"`
+	OnUpdate          string   `toml:"on_update,omitempty"`
+	CheckExpression   string   `toml:"check_expression,omitempty"`
+	Enforced          *bool    `toml:"enforced,omitempty"` // pointer: absent -> true/not supported
 }
 
 func constraint(tc *tomlConstraint) *core.Constraint {
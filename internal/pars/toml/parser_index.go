@@ -8,24 +8,27 @@ import (
 
 // tomlIndex maps [[tables.indexes]].
 type tomlIndex struct {
-	Name       string `toml:"name"`
-	Unique     bool   `toml:"unique"`
-	Type       string `toml:"type"`
-	Comment    string `toml:"comment"`
-	Visibility string `toml:"visibility"`
+	Name       string `toml:"name,omitempty"`
+	Unique     bool   `toml:"unique,omitempty"`
+	Type       string `toml:"type,omitempty"`
+	Comment    string `toml:"comment,omitempty"`
+	Visibility string `toml:"visibility,omitempty"`
+	Where      string `toml:"where,omitempty"`
+	Parser     string `toml:"parser,omitempty"`
 
 	// Simple form: columns = ["tenant_id", "created_at"]
-	Columns []string `toml:"columns"`
+	Columns []string `toml:"columns,omitempty"`
 
 	// Advanced form: [[tables.indexes.column_defs]]
-	ColumnDefs []tomlColumnIndex `toml:"column_defs"`
+	ColumnDefs []tomlColumnIndex `toml:"column_defs,omitempty"`
 }
 
 // tomlColumnIndex maps [[tables.indexes.column_defs]].
 type tomlColumnIndex struct {
-	Name   string `toml:"name"`
-	Length int    `toml:"length"`
-	Order  string `toml:"order"`
+	Name       string `toml:"name,omitempty"`
+	Length     int    `toml:"length,omitzero"`
+	Order      string `toml:"order,omitempty"`
+	Expression string `toml:"expression,omitempty"`
 }
 
 func index(ti *tomlIndex) (*core.Index, error) {
@@ -37,6 +40,8 @@ func index(ti *tomlIndex) (*core.Index, error) {
 		Name:    ti.Name,
 		Unique:  ti.Unique,
 		Comment: ti.Comment,
+		Where:   ti.Where,
+		Parser:  ti.Parser,
 	}
 
 	if ti.Type != "" {
@@ -81,8 +86,9 @@ func mergeColumnIndexes(ti *tomlIndex) []core.ColumnIndex {
 
 func columnIndex(tc *tomlColumnIndex) core.ColumnIndex {
 	ic := core.ColumnIndex{
-		Name:   tc.Name,
-		Length: tc.Length,
+		Name:       tc.Name,
+		Length:     tc.Length,
+		Expression: tc.Expression,
 	}
 
 	if tc.Order != "" {
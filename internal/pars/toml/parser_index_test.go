@@ -100,6 +100,42 @@ name = "items"
 	assert.Equal(t, core.SortDesc, idx.Columns[0].Order)
 }
 
+func TestParseIndexExpression(t *testing.T) {
+	t.Parallel()
+	const schema = `
+[database]
+name = "testdb"
+dialect = "mysql"
+
+[[tables]]
+name = "users"
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "email"
+  type = "varchar(255)"
+
+  [[tables.indexes]]
+  name = "idx_users_email"
+
+    [[tables.indexes.column_defs]]
+    expression = "lower(email)"
+`
+	db, err := NewParser().Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+
+	idx := db.Tables[0].FindIndex("idx_users_email")
+	require.NotNil(t, idx)
+	require.Len(t, idx.Columns, 1)
+	assert.Empty(t, idx.Columns[0].Name)
+	assert.Equal(t, "lower(email)", idx.Columns[0].Expression)
+	assert.Equal(t, core.SortAsc, idx.Columns[0].Order)
+}
+
 func TestParseIndexDefaultValues(t *testing.T) {
 	t.Parallel()
 	const schema = `
@@ -14,153 +14,154 @@ const (
 
 // tomlTable maps [[tables]].
 type tomlTable struct {
-	Name        string           `toml:"name"`
-	Comment     string           `toml:"comment"`
-	Options     tomlTableOptions `toml:"options"`
-	Columns     []tomlColumn     `toml:"columns"`
-	Constraints []tomlConstraint `toml:"constraints"`
-	Indexes     []tomlIndex      `toml:"indexes"`
-	Timestamps  *tomlTimestamps  `toml:"timestamps"`
+	Name        string           `toml:"name,omitempty"`
+	Comment     string           `toml:"comment,omitempty"`
+	Options     tomlTableOptions `toml:"options,omitempty"`
+	Columns     []tomlColumn     `toml:"columns,omitempty"`
+	Constraints []tomlConstraint `toml:"constraints,omitempty"`
+	Indexes     []tomlIndex      `toml:"indexes,omitempty"`
+	Timestamps  *tomlTimestamps  `toml:"timestamps,omitempty"`
 }
 
 // tomlTimestamps maps [tables.timestamps].
 type tomlTimestamps struct {
-	Enabled       bool   `toml:"enabled"`
-	CreatedColumn string `toml:"created_column"`
-	UpdatedColumn string `toml:"updated_column"`
+	Enabled       bool   `toml:"enabled,omitempty"`
+	CreatedColumn string `toml:"created_column,omitempty"`
+	UpdatedColumn string `toml:"updated_column,omitempty"`
 }
 
 // tomlTableOptions maps [tables.options].
 type tomlTableOptions struct {
-	Tablespace string `toml:"tablespace"`
-
-	MySQL      *tomlMySQLTableOptions      `toml:"mysql"`
-	TiDB       *tomlTiDBTableOptions       `toml:"tidb"`
-	PostgreSQL *tomlPostgreSQLTableOptions `toml:"postgresql"`
-	Oracle     *tomlOracleTableOptions     `toml:"oracle"`
-	SQLServer  *tomlSQLServerTableOptions  `toml:"sqlserver"`
-	DB2        *tomlDB2TableOptions        `toml:"db2"`
-	Snowflake  *tomlSnowflakeTableOptions  `toml:"snowflake"`
-	SQLite     *tomlSQLiteTableOptions     `toml:"sqlite"`
-	MariaDB    *tomlMariaDBTableOptions    `toml:"mariadb"`
+	Tablespace string `toml:"tablespace,omitempty"`
+
+	MySQL      *tomlMySQLTableOptions      `toml:"mysql,omitempty"`
+	TiDB       *tomlTiDBTableOptions       `toml:"tidb,omitempty"`
+	PostgreSQL *tomlPostgreSQLTableOptions `toml:"postgresql,omitempty"`
+	Oracle     *tomlOracleTableOptions     `toml:"oracle,omitempty"`
+	SQLServer  *tomlSQLServerTableOptions  `toml:"sqlserver,omitempty"`
+	DB2        *tomlDB2TableOptions        `toml:"db2,omitempty"`
+	Snowflake  *tomlSnowflakeTableOptions  `toml:"snowflake,omitempty"`
+	SQLite     *tomlSQLiteTableOptions     `toml:"sqlite,omitempty"`
+	MariaDB    *tomlMariaDBTableOptions    `toml:"mariadb,omitempty"`
 }
 
 // tomlMySQLTableOptions maps [tables.options.mysql].
 type tomlMySQLTableOptions struct {
-	Engine                   string   `toml:"engine"`
-	Charset                  string   `toml:"charset"`
-	Collate                  string   `toml:"collate"`
-	AutoIncrement            uint64   `toml:"auto_increment"`
-	RowFormat                string   `toml:"row_format"`
-	AvgRowLength             uint64   `toml:"avg_row_length"`
-	KeyBlockSize             uint64   `toml:"key_block_size"`
-	MaxRows                  uint64   `toml:"max_rows"`
-	MinRows                  uint64   `toml:"min_rows"`
-	Checksum                 uint64   `toml:"checksum"`
-	DelayKeyWrite            uint64   `toml:"delay_key_write"`
-	Compression              string   `toml:"compression"`
-	Encryption               string   `toml:"encryption"`
-	PackKeys                 string   `toml:"pack_keys"`
-	DataDirectory            string   `toml:"data_directory"`
-	IndexDirectory           string   `toml:"index_directory"`
-	InsertMethod             string   `toml:"insert_method"`
-	StorageMedia             string   `toml:"storage_media"`
-	StatsPersistent          string   `toml:"stats_persistent"`
-	StatsAutoRecalc          string   `toml:"stats_auto_recalc"`
-	StatsSamplePages         string   `toml:"stats_sample_pages"`
-	Connection               string   `toml:"connection"`
-	Password                 string   `toml:"password"`
-	AutoextendSize           string   `toml:"autoextend_size"`
-	Union                    []string `toml:"union"`
-	SecondaryEngine          string   `toml:"secondary_engine"`
-	TableChecksum            uint64   `toml:"table_checksum"`
-	EngineAttribute          string   `toml:"engine_attribute"`
-	SecondaryEngineAttribute string   `toml:"secondary_engine_attribute"`
-	PageCompressed           bool     `toml:"page_compressed"`
-	PageCompressionLevel     uint64   `toml:"page_compression_level"`
-	IETFQuotes               bool     `toml:"ietf_quotes"`
-	Nodegroup                uint64   `toml:"nodegroup"`
+	Engine                   string   `toml:"engine,omitempty"`
+	Charset                  string   `toml:"charset,omitempty"`
+	Collate                  string   `toml:"collate,omitempty"`
+	AutoIncrement            uint64   `toml:"auto_increment,omitzero"`
+	RowFormat                string   `toml:"row_format,omitempty"`
+	AvgRowLength             uint64   `toml:"avg_row_length,omitzero"`
+	KeyBlockSize             uint64   `toml:"key_block_size,omitzero"`
+	MaxRows                  uint64   `toml:"max_rows,omitzero"`
+	MinRows                  uint64   `toml:"min_rows,omitzero"`
+	Checksum                 uint64   `toml:"checksum,omitzero"`
+	DelayKeyWrite            uint64   `toml:"delay_key_write,omitzero"`
+	Compression              string   `toml:"compression,omitempty"`
+	Encryption               string   `toml:"encryption,omitempty"`
+	PackKeys                 string   `toml:"pack_keys,omitempty"`
+	DataDirectory            string   `toml:"data_directory,omitempty"`
+	IndexDirectory           string   `toml:"index_directory,omitempty"`
+	InsertMethod             string   `toml:"insert_method,omitempty"`
+	StorageMedia             string   `toml:"storage_media,omitempty"`
+	StatsPersistent          string   `toml:"stats_persistent,omitempty"`
+	StatsAutoRecalc          string   `toml:"stats_auto_recalc,omitempty"`
+	StatsSamplePages         string   `toml:"stats_sample_pages,omitempty"`
+	Connection               string   `toml:"connection,omitempty"`
+	Password                 string   `toml:"password,omitempty"`
+	AutoextendSize           string   `toml:"autoextend_size,omitempty"`
+	Union                    []string `toml:"union,omitempty"`
+	SecondaryEngine          string   `toml:"secondary_engine,omitempty"`
+	TableChecksum            uint64   `toml:"table_checksum,omitzero"`
+	EngineAttribute          string   `toml:"engine_attribute,omitempty"`
+	SecondaryEngineAttribute string   `toml:"secondary_engine_attribute,omitempty"`
+	PageCompressed           bool     `toml:"page_compressed,omitempty"`
+	PageCompressionLevel     uint64   `toml:"page_compression_level,omitzero"`
+	IETFQuotes               bool     `toml:"ietf_quotes,omitempty"`
+	Nodegroup                uint64   `toml:"nodegroup,omitzero"`
+	PartitionBy              string   `toml:"partition_by,omitempty"`
 }
 
 // tomlTiDBTableOptions maps [tables.options.tidb].
 type tomlTiDBTableOptions struct {
-	AutoIDCache     uint64  `toml:"auto_id_cache"`
-	AutoRandomBase  uint64  `toml:"auto_random_base"`
-	ShardRowID      uint64  `toml:"shard_row_id"`
-	PreSplitRegion  uint64  `toml:"pre_split_region"`
-	TTL             string  `toml:"ttl"`
-	TTLEnable       bool    `toml:"ttl_enable"`
-	TTLJobInterval  string  `toml:"ttl_job_interval"`
-	Affinity        string  `toml:"affinity"`
-	PlacementPolicy string  `toml:"placement_policy"`
-	StatsBuckets    uint64  `toml:"stats_buckets"`
-	StatsTopN       uint64  `toml:"stats_top_n"`
-	StatsColsChoice string  `toml:"stats_cols_choice"`
-	StatsColList    string  `toml:"stats_col_list"`
-	StatsSampleRate float64 `toml:"stats_sample_rate"`
-	Sequence        bool    `toml:"sequence"`
+	AutoIDCache     uint64  `toml:"auto_id_cache,omitzero"`
+	AutoRandomBase  uint64  `toml:"auto_random_base,omitzero"`
+	ShardRowID      uint64  `toml:"shard_row_id,omitzero"`
+	PreSplitRegion  uint64  `toml:"pre_split_region,omitzero"`
+	TTL             string  `toml:"ttl,omitempty"`
+	TTLEnable       bool    `toml:"ttl_enable,omitempty"`
+	TTLJobInterval  string  `toml:"ttl_job_interval,omitempty"`
+	Affinity        string  `toml:"affinity,omitempty"`
+	PlacementPolicy string  `toml:"placement_policy,omitempty"`
+	StatsBuckets    uint64  `toml:"stats_buckets,omitzero"`
+	StatsTopN       uint64  `toml:"stats_top_n,omitzero"`
+	StatsColsChoice string  `toml:"stats_cols_choice,omitempty"`
+	StatsColList    string  `toml:"stats_col_list,omitempty"`
+	StatsSampleRate float64 `toml:"stats_sample_rate,omitzero"`
+	Sequence        bool    `toml:"sequence,omitempty"`
 }
 
 // tomlPostgreSQLTableOptions maps [tables.options.postgresql].
 type tomlPostgreSQLTableOptions struct {
-	Schema      string   `toml:"schema"`
-	Unlogged    bool     `toml:"unlogged"`
-	Fillfactor  int      `toml:"fillfactor"`
-	PartitionBy string   `toml:"partition_by"`
-	Inherits    []string `toml:"inherits"`
+	Schema      string   `toml:"schema,omitempty"`
+	Unlogged    bool     `toml:"unlogged,omitempty"`
+	Fillfactor  int      `toml:"fillfactor,omitzero"`
+	PartitionBy string   `toml:"partition_by,omitempty"`
+	Inherits    []string `toml:"inherits,omitempty"`
 }
 
 // tomlOracleTableOptions maps [tables.options.oracle].
 type tomlOracleTableOptions struct {
-	Organization    string `toml:"organization"`
-	Logging         *bool  `toml:"logging"`
-	Pctfree         int    `toml:"pctfree"`
-	Pctused         int    `toml:"pctused"`
-	InitTrans       int    `toml:"init_trans"`
-	SegmentCreation string `toml:"segment_creation"`
+	Organization    string `toml:"organization,omitempty"`
+	Logging         *bool  `toml:"logging,omitempty"`
+	Pctfree         int    `toml:"pctfree,omitzero"`
+	Pctused         int    `toml:"pctused,omitzero"`
+	InitTrans       int    `toml:"init_trans,omitzero"`
+	SegmentCreation string `toml:"segment_creation,omitempty"`
 }
 
 // tomlSQLServerTableOptions maps [tables.options.sqlserver].
 type tomlSQLServerTableOptions struct {
-	FileGroup        string `toml:"file_group"`
-	DataCompression  string `toml:"data_compression"`
-	MemoryOptimized  bool   `toml:"memory_optimized"`
-	SystemVersioning bool   `toml:"system_versioning"`
-	TextImageOn      string `toml:"textimage_on"`
-	LedgerTable      bool   `toml:"ledger_table"`
+	FileGroup        string `toml:"file_group,omitempty"`
+	DataCompression  string `toml:"data_compression,omitempty"`
+	MemoryOptimized  bool   `toml:"memory_optimized,omitempty"`
+	SystemVersioning bool   `toml:"system_versioning,omitempty"`
+	TextImageOn      string `toml:"textimage_on,omitempty"`
+	LedgerTable      bool   `toml:"ledger_table,omitempty"`
 }
 
 // tomlDB2TableOptions maps [tables.options.db2].
 type tomlDB2TableOptions struct {
-	OrganizeBy  string `toml:"organize_by"`
-	Compress    string `toml:"compress"`
-	DataCapture string `toml:"data_capture"`
-	AppendMode  bool   `toml:"append_mode"`
-	Volatile    bool   `toml:"volatile"`
+	OrganizeBy  string `toml:"organize_by,omitempty"`
+	Compress    string `toml:"compress,omitempty"`
+	DataCapture string `toml:"data_capture,omitempty"`
+	AppendMode  bool   `toml:"append_mode,omitempty"`
+	Volatile    bool   `toml:"volatile,omitempty"`
 }
 
 // tomlSnowflakeTableOptions maps [tables.options.snowflake].
 type tomlSnowflakeTableOptions struct {
-	ClusterBy         []string `toml:"cluster_by"`
-	DataRetentionDays *int     `toml:"data_retention_days"`
-	ChangeTracking    bool     `toml:"change_tracking"`
-	CopyGrants        bool     `toml:"copy_grants"`
-	Transient         bool     `toml:"transient"`
+	ClusterBy         []string `toml:"cluster_by,omitempty"`
+	DataRetentionDays *int     `toml:"data_retention_days,omitempty"`
+	ChangeTracking    bool     `toml:"change_tracking,omitempty"`
+	CopyGrants        bool     `toml:"copy_grants,omitempty"`
+	Transient         bool     `toml:"transient,omitempty"`
 }
 
 // tomlSQLiteTableOptions maps [tables.options.sqlite].
 type tomlSQLiteTableOptions struct {
-	WithoutRowid bool `toml:"without_rowid"`
-	Strict       bool `toml:"strict"`
+	WithoutRowid bool `toml:"without_rowid,omitempty"`
+	Strict       bool `toml:"strict,omitempty"`
 }
 
 // tomlMariaDBTableOptions maps [tables.options.mariadb].
 type tomlMariaDBTableOptions struct {
-	PageChecksum         uint64 `toml:"page_checksum"`
-	Transactional        uint64 `toml:"transactional"`
-	EncryptionKeyID      *int   `toml:"encryption_key_id"`
-	Sequence             bool   `toml:"sequence"`
-	WithSystemVersioning bool   `toml:"with_system_versioning"`
+	PageChecksum         uint64 `toml:"page_checksum,omitzero"`
+	Transactional        uint64 `toml:"transactional,omitzero"`
+	EncryptionKeyID      *int   `toml:"encryption_key_id,omitempty"`
+	Sequence             bool   `toml:"sequence,omitempty"`
+	WithSystemVersioning bool   `toml:"with_system_versioning,omitempty"`
 }
 
 // table method parses a toml table into core.Table struct.
@@ -273,6 +274,7 @@ func mysqlTableOptions(m *tomlMySQLTableOptions) *core.MySQLTableOptions {
 		PageCompressionLevel:     m.PageCompressionLevel,
 		IETFQuotes:               m.IETFQuotes,
 		Nodegroup:                m.Nodegroup,
+		PartitionBy:              m.PartitionBy,
 	}
 }
 
@@ -387,14 +389,7 @@ func (p *Parser) tableColumns(table *core.Table, tt *tomlTable, tableIdx int) er
 // injectTimestampColumns resolves the created/updated column names and appends
 // the columns when not already present.
 func injectTimestampColumns(table *core.Table) {
-	createdCol := defaultCreatedColumn
-	updatedCol := defaultUpdatedColumn
-	if table.Timestamps.CreatedColumn != "" {
-		createdCol = table.Timestamps.CreatedColumn
-	}
-	if table.Timestamps.UpdatedColumn != "" {
-		updatedCol = table.Timestamps.UpdatedColumn
-	}
+	createdCol, updatedCol := timestampColumnNames(table.Timestamps)
 
 	columnNames := make(map[string]bool, len(table.Columns))
 	for _, c := range table.Columns {
@@ -402,19 +397,36 @@ func injectTimestampColumns(table *core.Table) {
 	}
 
 	if !columnNames[createdCol] {
-		table.Columns = append(table.Columns, &core.Column{
-			Name:         createdCol,
-			Type:         core.DataTypeDatetime,
-			DefaultValue: new(defaultTimestampValue),
-		})
+		table.Columns = append(table.Columns, timestampColumn(createdCol, false))
 	}
 
 	if !columnNames[updatedCol] {
-		table.Columns = append(table.Columns, &core.Column{
-			Name:         updatedCol,
-			Type:         core.DataTypeDatetime,
-			DefaultValue: new(defaultTimestampValue),
-			OnUpdate:     new(defaultTimestampValue),
-		})
+		table.Columns = append(table.Columns, timestampColumn(updatedCol, true))
+	}
+}
+
+// timestampColumnNames resolves the created/updated column names of ts.
+func timestampColumnNames(ts *core.TimestampsConfig) (created, updated string) {
+	created, updated = defaultCreatedColumn, defaultUpdatedColumn
+	if ts != nil && ts.CreatedColumn != "" {
+		created = ts.CreatedColumn
+	}
+	if ts != nil && ts.UpdatedColumn != "" {
+		updated = ts.UpdatedColumn
+	}
+	return created, updated
+}
+
+// timestampColumn returns an injected timestamp column. The updated column
+// is refreshed on every update.
+func timestampColumn(name string, updated bool) *core.Column {
+	col := &core.Column{
+		Name:         name,
+		Type:         core.DataTypeDatetime,
+		DefaultValue: new(defaultTimestampValue),
+	}
+	if updated {
+		col.OnUpdate = new(defaultTimestampValue)
 	}
+	return col
 }
@@ -671,6 +671,7 @@ name = "items"
     column_format              = "FIXED"
     storage                    = "DISK"
     secondary_engine_attribute = '{"key":"val"}'
+    srid                       = 4326
 `
 	p := NewParser()
 	db, err := p.Parse(strings.NewReader(schema))
@@ -682,6 +683,7 @@ name = "items"
 	assert.Equal(t, "FIXED", col.MySQL.ColumnFormat)
 	assert.Equal(t, "DISK", col.MySQL.Storage)
 	assert.JSONEq(t, `{"key":"val"}`, col.MySQL.SecondaryEngineAttribute)
+	assert.Equal(t, new(uint32(4326)), col.MySQL.SRID)
 }
 
 func TestParseTiDBColumnOptions(t *testing.T) {
@@ -0,0 +1,30 @@
+package toml
+
+import (
+	"smf/internal/core"
+)
+
+// tomlView maps [[views]].
+type tomlView struct {
+	Name         string   `toml:"name,omitempty"`
+	Definition   string   `toml:"definition,omitempty"`
+	Columns      []string `toml:"columns,omitempty"`
+	CheckOption  string   `toml:"check_option,omitempty"`
+	Security     string   `toml:"security,omitempty"`
+	Materialized bool     `toml:"materialized,omitempty"`
+	Refresh      string   `toml:"refresh,omitempty"`
+	Comment      string   `toml:"comment,omitempty"`
+}
+
+func view(tv *tomlView) *core.View {
+	return &core.View{
+		Name:         tv.Name,
+		Definition:   tv.Definition,
+		Columns:      tv.Columns,
+		CheckOption:  core.CheckOption(tv.CheckOption),
+		Security:     core.ViewSecurity(tv.Security),
+		Materialized: tv.Materialized,
+		Refresh:      core.RefreshPolicy(tv.Refresh),
+		Comment:      tv.Comment,
+	}
+}
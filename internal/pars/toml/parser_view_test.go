@@ -0,0 +1,76 @@
+package toml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+const viewTables = `
+[database]
+name = "testdb"
+dialect = "postgresql"
+
+[[tables]]
+name = "orders"
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "total"
+  type = "decimal(10,2)"
+`
+
+func TestParseViews(t *testing.T) {
+	t.Parallel()
+	const schema = viewTables + `
+[[views]]
+name = "large_orders"
+definition = "SELECT id, total FROM orders WHERE total > 1000"
+columns = ["order_id", "total"]
+check_option = "CASCADED"
+security = "INVOKER"
+comment = "Orders above the review threshold"
+
+[[views]]
+name = "order_stats"
+definition = "SELECT count(*) AS orders FROM orders"
+materialized = true
+refresh = "ON COMMIT"
+`
+	db, err := NewParser().Parse(strings.NewReader(schema))
+	require.NoError(t, err)
+	require.Len(t, db.Views, 2)
+
+	assert.Equal(t, &core.View{
+		Name:        "large_orders",
+		Definition:  "SELECT id, total FROM orders WHERE total > 1000",
+		Columns:     []string{"order_id", "total"},
+		CheckOption: core.CheckOptionCascaded,
+		Security:    core.SecurityInvoker,
+		Comment:     "Orders above the review threshold",
+	}, db.FindView("large_orders"))
+
+	stats := db.FindView("order_stats")
+	require.NotNil(t, stats)
+	assert.True(t, stats.Materialized)
+	assert.Equal(t, core.RefreshOnCommit, stats.Refresh)
+}
+
+func TestParseViewWithoutDefinition(t *testing.T) {
+	t.Parallel()
+	const schema = viewTables + `
+[[views]]
+name = "large_orders"
+`
+	_, err := NewParser().Parse(strings.NewReader(schema))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `view "large_orders": definition is required`)
+}
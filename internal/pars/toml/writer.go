@@ -0,0 +1,408 @@
+package toml
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// Writer writes a core.Database as an smf TOML schema. It is the inverse of
+// Parser: constraints that the parser would synthesize from column
+// shorthand are folded back into primary_key, unique, check, and references
+// on the column, and created/updated columns that match the injected ones
+// are written as [tables.timestamps]. Everything else is written
+// explicitly, so parsing the output yields the same schema. Option tables
+// that set nothing are left out.
+type Writer struct{}
+
+// NewWriter creates a new TOML schema writer.
+func NewWriter() *Writer {
+	return &Writer{}
+}
+
+// Write writes db as TOML to out.
+func (w *Writer) Write(out io.Writer, db *core.Database) error {
+	sf := schemaFile{
+		Database:   tomlDatabase{Name: db.Name, Dialect: string(db.Dialect)},
+		Validation: validationRules(db.Validation),
+		Tables:     make([]tomlTable, 0, len(db.Tables)),
+	}
+	for _, t := range db.Tables {
+		sf.Tables = append(sf.Tables, writeTable(db.Dialect, t))
+	}
+	for _, v := range db.Views {
+		sf.Views = append(sf.Views, writeView(v))
+	}
+
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(sf); err != nil {
+		return fmt.Errorf("toml: encode error: %w", err)
+	}
+	_, err := io.WriteString(out, dropEmptyHeaders(buf.String()))
+	return err
+}
+
+// dropEmptyHeaders removes the headers of tables that hold nothing but
+// sub-tables, which the encoder writes right before the first sub-table:
+// [tables.options] before [tables.options.sqlite].
+func dropEmptyHeaders(s string) string {
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	for i, line := range lines {
+		if i+1 < len(lines) && strings.HasPrefix(line, "[") && !strings.HasPrefix(line, "[[") &&
+			strings.HasPrefix(lines[i+1], strings.TrimSuffix(line, "]")+".") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// nonZero returns p, or nil when it points to the zero value, so that an
+// options table that sets nothing is not written.
+func nonZero[T any](p *T) *T {
+	if p == nil || reflect.ValueOf(p).Elem().IsZero() {
+		return nil
+	}
+	return p
+}
+
+// validationRules converts core.ValidationRules to [validation], which is
+// omitted when no rule is set.
+func validationRules(r *core.ValidationRules) *tomlValidation {
+	if r == nil || *r == (core.ValidationRules{}) {
+		return nil
+	}
+	return &tomlValidation{
+		MaxTableNameLength:          r.MaxTableNameLength,
+		MaxColumnNameLength:         r.MaxColumnNameLength,
+		AutoGenerateConstraintNames: r.AutoGenerateConstraintNames,
+		AllowedNamePattern:          r.AllowedNamePattern,
+	}
+}
+
+func writeTable(dialect core.Dialect, t *core.Table) tomlTable {
+	tt := tomlTable{
+		Name:       t.Name,
+		Comment:    t.Comment,
+		Options:    writeTableOptions(&t.Options),
+		Timestamps: writeTimestamps(dialect, t),
+	}
+
+	columns := t.Columns
+	if tt.Timestamps != nil {
+		columns = columns[:len(columns)-2]
+	}
+	tt.Columns = make([]tomlColumn, 0, len(columns))
+	for _, c := range columns {
+		tt.Columns = append(tt.Columns, writeColumn(c))
+	}
+
+	f := folder{table: t, columns: make(map[string]*tomlColumn, len(tt.Columns))}
+	for i := range tt.Columns {
+		f.columns[tt.Columns[i].Name] = &tt.Columns[i]
+	}
+	for _, con := range t.Constraints {
+		if !f.fold(con) {
+			tt.Constraints = append(tt.Constraints, writeConstraint(con))
+		}
+	}
+
+	for _, idx := range t.Indexes {
+		tt.Indexes = append(tt.Indexes, writeIndex(idx))
+	}
+	return tt
+}
+
+// writeTimestamps returns [tables.timestamps] when the last two columns of t
+// are the created and updated columns the parser would inject. Both pairs
+// are compared in the form the dialect stores them in, so the TEXT columns
+// and ON UPDATE trigger of SQLite match the injected datetime columns.
+func writeTimestamps(dialect core.Dialect, t *core.Table) *tomlTimestamps {
+	created, updated := timestampColumnNames(t.Timestamps)
+	n := len(t.Columns)
+	if n < 2 {
+		return nil
+	}
+	live := &core.Table{Name: t.Name, Options: t.Options,
+		Columns: []*core.Column{copyColumn(t.Columns[n-2]), copyColumn(t.Columns[n-1])}}
+	want := &core.Table{Name: t.Name, Options: t.Options,
+		Columns: []*core.Column{timestampColumn(created, false), timestampColumn(updated, true)}}
+	generate.Normalize(&core.Database{Dialect: dialect, Tables: []*core.Table{live, want}})
+	if !sameColumn(t, live.Columns[0], want.Columns[0]) || !sameColumn(t, live.Columns[1], want.Columns[1]) {
+		return nil
+	}
+
+	ts := &tomlTimestamps{Enabled: true}
+	if created != defaultCreatedColumn {
+		ts.CreatedColumn = created
+	}
+	if updated != defaultUpdatedColumn {
+		ts.UpdatedColumn = updated
+	}
+	return ts
+}
+
+func sameColumn(t *core.Table, a, b *core.Column) bool {
+	return a.Name == b.Name && len(diff.Column(t, a, b)) == 0
+}
+
+// copyColumn returns a shallow copy of c. Normalizing the copy leaves c as
+// it is, since normalizing replaces the fields of a column rather than
+// writing through them.
+func copyColumn(c *core.Column) *core.Column {
+	cp := *c
+	return &cp
+}
+
+// folder folds the constraints of a table into the shorthand of its
+// written columns. A constraint is only folded when the parser synthesizes
+// exactly the same constraint from the shorthand.
+type folder struct {
+	table   *core.Table
+	columns map[string]*tomlColumn
+}
+
+func (f *folder) fold(con *core.Constraint) bool {
+	if con.Enforced != nil && !*con.Enforced {
+		return false
+	}
+	switch con.Type {
+	case core.ConstraintPrimaryKey:
+		return f.primaryKey(con)
+	case core.ConstraintUnique:
+		return f.unique(con)
+	case core.ConstraintCheck:
+		return f.check(con)
+	case core.ConstraintForeignKey:
+		return f.foreignKey(con)
+	default:
+		return false
+	}
+}
+
+// primaryKey folds a primary key whose columns are listed in table order.
+// Primary keys are matched by type, so its name is not kept.
+func (f *folder) primaryKey(con *core.Constraint) bool {
+	var ordered []string
+	for _, c := range f.table.Columns {
+		if slices.Contains(con.Columns, c.Name) {
+			ordered = append(ordered, c.Name)
+		}
+	}
+	if len(ordered) == 0 || !slices.Equal(ordered, con.Columns) {
+		return false
+	}
+	for _, name := range con.Columns {
+		if f.columns[name] == nil {
+			return false
+		}
+	}
+	for _, name := range con.Columns {
+		f.columns[name].PrimaryKey = true
+	}
+	return true
+}
+
+func (f *folder) unique(con *core.Constraint) bool {
+	col := f.single(con, "")
+	if col == nil || col.Unique {
+		return false
+	}
+	col.Unique = true
+	return true
+}
+
+func (f *folder) check(con *core.Constraint) bool {
+	if len(con.Columns) > 0 {
+		return false
+	}
+	for name, col := range f.columns {
+		if col.Check == "" && con.Name == core.AutoGenerateConstraintName(con.Type, f.table.Name, []string{name}, "") {
+			col.Check = con.CheckExpression
+			return true
+		}
+	}
+	return false
+}
+
+// foreignKey folds a single-column foreign key. A column with on_update
+// for ON UPDATE CURRENT_TIMESTAMP cannot also carry an inline reference.
+func (f *folder) foreignKey(con *core.Constraint) bool {
+	if len(con.ReferencedColumns) != 1 {
+		return false
+	}
+	col := f.single(con, con.ReferencedTable)
+	if col == nil || col.References != "" || col.OnUpdate != "" {
+		return false
+	}
+	col.References = con.ReferencedTable + "." + con.ReferencedColumns[0]
+	col.OnDelete = string(con.OnDelete)
+	col.OnUpdate = string(con.OnUpdate)
+	return true
+}
+
+// single returns the written column of a single-column constraint that
+// carries the name the parser generates for it.
+func (f *folder) single(con *core.Constraint, refTable string) *tomlColumn {
+	if len(con.Columns) != 1 || con.Name != core.AutoGenerateConstraintName(con.Type, f.table.Name, con.Columns, refTable) {
+		return nil
+	}
+	return f.columns[con.Columns[0]]
+}
+
+func writeColumn(c *core.Column) tomlColumn {
+	tc := tomlColumn{
+		Name:                 c.Name,
+		AutoIncrement:        c.AutoIncrement,
+		Nullable:             c.Nullable,
+		Comment:              c.Comment,
+		Collate:              c.Collate,
+		Charset:              c.Charset,
+		EnumValues:           c.EnumValues,
+		RawType:              c.RawType,
+		IsGenerated:          c.IsGenerated,
+		GenerationExpression: c.GenerationExpression,
+		GenerationStorage:    string(c.GenerationStorage),
+		Invisible:            c.Invisible,
+		IdentitySeed:         c.IdentitySeed,
+		IdentityIncrement:    c.IdentityIncrement,
+		IdentityGeneration:   string(c.IdentityGeneration),
+		SequenceName:         c.SequenceName,
+		MySQL:                nonZero((*tomlMySQLColumnOptions)(c.MySQL)),
+		TiDB:                 nonZero((*tomlTiDBColumnOptions)(c.TiDB)),
+		PostgreSQL:           nonZero((*tomlPostgreSQLColumnOptions)(c.PostgreSQL)),
+		Oracle:               nonZero((*tomlOracleColumnOptions)(c.Oracle)),
+		MSSQL:                nonZero(writeMSSQLColumnOptions(c.MSSQL)),
+		DB2:                  nonZero((*tomlDB2ColumnOptions)(c.DB2)),
+		SQLite:               nonZero((*tomlSQLiteColumnOptions)(c.SQLite)),
+	}
+	tc.Type = writeColumnType(c)
+	if c.DefaultValue != nil {
+		tc.DefaultValue = *c.DefaultValue
+	}
+	if c.OnUpdate != nil {
+		tc.OnUpdate = *c.OnUpdate
+	}
+	return tc
+}
+
+// writeColumnType returns the portable type of c. An enum built from its
+// values is written as "enum". A column that only has a raw type gets its
+// type category, which the other dialects fall back to.
+func writeColumnType(c *core.Column) string {
+	switch {
+	case c.PortableType != "" && len(c.EnumValues) > 0 && c.PortableType == core.BuildEnumTypeRaw(c.EnumValues):
+		return string(core.DataTypeEnum)
+	case c.PortableType != "":
+		return c.PortableType
+	case c.Type == core.DataTypeUnknown:
+		return ""
+	default:
+		return string(c.Type)
+	}
+}
+
+func writeMSSQLColumnOptions(o *core.MSSQLColumnOptions) *tomlMSSQLColumnOptions {
+	if o == nil {
+		return nil
+	}
+	return &tomlMSSQLColumnOptions{
+		FileStream:                o.FileStream,
+		Sparse:                    o.Sparse,
+		RowGUIDCol:                o.RowGUIDCol,
+		IdentityNotForReplication: o.IdentityNotForReplication,
+		Persisted:                 o.Persisted,
+		AlwaysEncrypted:           (*tomlMSSQLAlwaysEncrypted)(o.AlwaysEncrypted),
+		DataMasking:               (*tomlMSSQLDataMasking)(o.DataMasking),
+	}
+}
+
+func writeConstraint(con *core.Constraint) tomlConstraint {
+	tc := tomlConstraint{
+		Name:              con.Name,
+		Type:              string(con.Type),
+		Columns:           con.Columns,
+		ReferencedTable:   con.ReferencedTable,
+		ReferencedColumns: con.ReferencedColumns,
+		OnDelete:          string(con.OnDelete),
+		OnUpdate:          string(con.OnUpdate),
+		CheckExpression:   con.CheckExpression,
+	}
+	if con.Enforced != nil && !*con.Enforced {
+		tc.Enforced = new(false)
+	}
+	return tc
+}
+
+// writeIndex writes the simple columns form unless a column has a prefix
+// length or a descending order, or is an expression. Default type and visibility are omitted.
+func writeIndex(idx *core.Index) tomlIndex {
+	ti := tomlIndex{Name: idx.Name, Unique: idx.Unique, Comment: idx.Comment, Where: idx.Where, Parser: idx.Parser}
+	if idx.Type != core.IndexTypeBTree {
+		ti.Type = string(idx.Type)
+	}
+	if idx.Visibility != core.IndexVisible {
+		ti.Visibility = string(idx.Visibility)
+	}
+
+	writeIndexColumns(&ti, idx.Columns)
+	return ti
+}
+
+func writeIndexColumns(ti *tomlIndex, cols []core.ColumnIndex) {
+	simple := true
+	for _, c := range cols {
+		simple = simple && c.Length == 0 && c.Expression == "" && (c.Order == "" || c.Order == core.SortAsc)
+	}
+	for _, c := range cols {
+		if simple {
+			ti.Columns = append(ti.Columns, c.Name)
+			continue
+		}
+		tc := tomlColumnIndex{Name: c.Name, Length: c.Length, Expression: c.Expression}
+		if c.Order != core.SortAsc {
+			tc.Order = string(c.Order)
+		}
+		ti.ColumnDefs = append(ti.ColumnDefs, tc)
+	}
+}
+
+func writeView(v *core.View) tomlView {
+	return tomlView{
+		Name:         v.Name,
+		Definition:   v.Definition,
+		Columns:      v.Columns,
+		CheckOption:  string(v.CheckOption),
+		Security:     string(v.Security),
+		Materialized: v.Materialized,
+		Refresh:      string(v.Refresh),
+		Comment:      v.Comment,
+	}
+}
+
+func writeTableOptions(o *core.TableOptions) tomlTableOptions {
+	return tomlTableOptions{
+		Tablespace: o.Tablespace,
+		MySQL:      nonZero((*tomlMySQLTableOptions)(o.MySQL)),
+		TiDB:       nonZero((*tomlTiDBTableOptions)(o.TiDB)),
+		PostgreSQL: nonZero((*tomlPostgreSQLTableOptions)(o.PostgreSQL)),
+		Oracle:     nonZero((*tomlOracleTableOptions)(o.Oracle)),
+		SQLServer:  nonZero((*tomlSQLServerTableOptions)(o.SQLServer)),
+		DB2:        nonZero((*tomlDB2TableOptions)(o.DB2)),
+		Snowflake:  nonZero((*tomlSnowflakeTableOptions)(o.Snowflake)),
+		SQLite:     nonZero((*tomlSQLiteTableOptions)(o.SQLite)),
+		MariaDB:    nonZero((*tomlMariaDBTableOptions)(o.MariaDB)),
+	}
+}
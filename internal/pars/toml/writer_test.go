@@ -0,0 +1,205 @@
+package toml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+)
+
+func write(t *testing.T, db *core.Database) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, NewWriter().Write(&buf, db))
+	return buf.String()
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, path := range []string{"../../../test/data/schema.toml", "../../../test/data/example_schema.toml"} {
+		db, err := NewParser().ParseFile(path)
+		require.NoError(t, err)
+
+		out := write(t, db)
+		back, err := NewParser().Parse(strings.NewReader(out))
+		require.NoError(t, err, out)
+
+		assert.Empty(t, diff.Databases(db, back).Changes, path)
+		assert.Equal(t, out, write(t, back), path)
+	}
+}
+
+// introspected returns a schema as an introspecter reports it: every
+// constraint is explicit, there is no column shorthand, and MySQL primary
+// keys, which are all named PRIMARY, have no name.
+func introspected() *core.Database {
+	return &core.Database{
+		Name:    "app",
+		Dialect: core.DialectMySQL,
+		Tables: []*core.Table{
+			{
+				Name: "tenants",
+				Columns: []*core.Column{
+					{Name: "id", Type: core.DataTypeInt, RawType: "bigint", AutoIncrement: true},
+				},
+				Constraints: []*core.Constraint{
+					{Type: core.ConstraintPrimaryKey, Columns: []string{"id"}},
+				},
+			},
+			{
+				Name: "users",
+				Columns: []*core.Column{
+					{Name: "id", Type: core.DataTypeInt, PortableType: "bigint"},
+					{Name: "tenant_id", Type: core.DataTypeInt, PortableType: "bigint"},
+					{Name: "email", Type: core.DataTypeString, PortableType: "varchar(255)"},
+					{Name: "age", Type: core.DataTypeInt, PortableType: "int", Nullable: true},
+					{Name: "created_at", Type: core.DataTypeDatetime, RawType: "timestamp", DefaultValue: new("CURRENT_TIMESTAMP")},
+					{Name: "updated_at", Type: core.DataTypeDatetime, RawType: "timestamp", DefaultValue: new("CURRENT_TIMESTAMP"), OnUpdate: new("CURRENT_TIMESTAMP")},
+				},
+				Constraints: []*core.Constraint{
+					{Type: core.ConstraintPrimaryKey, Columns: []string{"id"}},
+					{Name: "uq_users_email", Type: core.ConstraintUnique, Columns: []string{"email"}},
+					{Name: "fk_users_tenants", Type: core.ConstraintForeignKey, Columns: []string{"tenant_id"},
+						ReferencedTable: "tenants", ReferencedColumns: []string{"id"}, OnDelete: core.RefActionCascade},
+					{Name: "chk_users_age", Type: core.ConstraintCheck, CheckExpression: "age >= 0", Enforced: new(true)},
+				},
+			},
+		},
+	}
+}
+
+func TestWriteFoldsShorthand(t *testing.T) {
+	t.Parallel()
+	db := introspected()
+	out := write(t, db)
+
+	assert.NotContains(t, out, "[[tables.constraints]]")
+	assert.Contains(t, out, "primary_key = true")
+	assert.Contains(t, out, "unique = true")
+	assert.Contains(t, out, `references = "tenants.id"`)
+	assert.Contains(t, out, `on_delete = "CASCADE"`)
+	assert.Contains(t, out, `check = "age >= 0"`)
+	assert.Contains(t, out, "[tables.timestamps]\nenabled = true")
+	assert.NotContains(t, out, "created_at")
+
+	back, err := NewParser().Parse(strings.NewReader(out))
+	require.NoError(t, err, out)
+	assert.Empty(t, diff.Databases(db, back).Changes)
+}
+
+func TestWriteRawType(t *testing.T) {
+	t.Parallel()
+	out := write(t, introspected())
+	assert.Contains(t, out, "name = \"id\"\ntype = \"int\"\nprimary_key = true\nauto_increment = true\nraw_type = \"bigint\"")
+}
+
+func TestWriteKeepsConstraints(t *testing.T) {
+	t.Parallel()
+	db := introspected()
+	users := db.FindTable("users")
+	users.Constraints = []*core.Constraint{
+		// Out of column order, so the shorthand would reorder it.
+		{Type: core.ConstraintPrimaryKey, Columns: []string{"tenant_id", "id"}},
+		{Name: "email", Type: core.ConstraintUnique, Columns: []string{"email"}},
+		{Name: "users_tenant_fk", Type: core.ConstraintForeignKey, Columns: []string{"tenant_id"},
+			ReferencedTable: "tenants", ReferencedColumns: []string{"id"}},
+		{Name: "chk_users_age", Type: core.ConstraintCheck, CheckExpression: "age >= 0", Enforced: new(false)},
+		{Name: "uq_users_created_at", Type: core.ConstraintUnique, Columns: []string{"created_at"}},
+	}
+	out := write(t, db)
+
+	assert.Equal(t, 5, strings.Count(out, "[[tables.constraints]]"))
+	assert.Equal(t, 1, strings.Count(out, "primary_key = true"), "only tenants.id")
+	assert.NotContains(t, out, "unique = true")
+	assert.NotContains(t, out, "references =")
+	assert.NotContains(t, out, "check =")
+	assert.Contains(t, out, "enforced = false")
+
+	back, err := NewParser().Parse(strings.NewReader(out))
+	require.NoError(t, err, out)
+	assert.Empty(t, diff.Databases(db, back).Changes)
+}
+
+func TestWriteTimestampsMismatch(t *testing.T) {
+	t.Parallel()
+	db := introspected()
+	users := db.FindTable("users")
+	users.Columns[4].Nullable = true
+	out := write(t, db)
+
+	assert.NotContains(t, out, "[tables.timestamps]")
+	assert.Contains(t, out, `name = "created_at"`)
+	assert.Contains(t, out, `on_update = "CURRENT_TIMESTAMP"`)
+}
+
+func TestWriteOptions(t *testing.T) {
+	t.Parallel()
+	db := introspected()
+	db.Tables[0].Options.MySQL = &core.MySQLTableOptions{}
+	db.Tables[1].Options.MySQL = &core.MySQLTableOptions{Engine: "InnoDB"}
+	db.Tables[1].Columns[0].MySQL = &core.MySQLColumnOptions{}
+	out := write(t, db)
+
+	assert.Equal(t, 1, strings.Count(out, "[tables.options.mysql]"), out)
+	assert.NotContains(t, out, "[tables.options]")
+	assert.NotContains(t, out, "[tables.columns.mysql]")
+	assert.Contains(t, out, "[tables.options.mysql]\nengine = \"InnoDB\"\n")
+}
+
+func TestWriteIndexes(t *testing.T) {
+	t.Parallel()
+	db := introspected()
+	db.FindTable("users").Indexes = []*core.Index{
+		{Name: "idx_users_tenant", Type: core.IndexTypeBTree, Visibility: core.IndexVisible,
+			Columns: []core.ColumnIndex{{Name: "tenant_id", Order: core.SortAsc}, {Name: "email", Order: core.SortAsc}}},
+		{Name: "idx_users_email", Type: core.IndexTypeBTree, Visibility: core.IndexInvisible,
+			Columns: []core.ColumnIndex{{Name: "email", Length: 16, Order: core.SortAsc}, {Name: "age", Order: core.SortDesc}}},
+		{Name: "ft_users_email", Type: core.IndexTypeFullText, Visibility: core.IndexVisible, Parser: "ngram",
+			Columns: []core.ColumnIndex{{Name: "email", Order: core.SortAsc}}},
+	}
+	out := write(t, db)
+
+	assert.Contains(t, out, `columns = ["tenant_id", "email"]`)
+	assert.Contains(t, out, "[[tables.indexes.column_defs]]\nname = \"email\"\nlength = 16\n")
+	assert.Contains(t, out, "[[tables.indexes.column_defs]]\nname = \"age\"\norder = \"DESC\"\n")
+	assert.NotContains(t, out, `type = "BTREE"`)
+	assert.Contains(t, out, "type = \"FULLTEXT\"\nparser = \"ngram\"\n")
+
+	back, err := NewParser().Parse(strings.NewReader(out))
+	require.NoError(t, err, out)
+	assert.Empty(t, diff.Databases(db, back).Changes)
+}
+
+func TestWriteEmptyDefault(t *testing.T) {
+	t.Parallel()
+	db := introspected()
+	db.FindTable("users").Columns[2].DefaultValue = new("")
+	out := write(t, db)
+
+	back, err := NewParser().Parse(strings.NewReader(out))
+	require.NoError(t, err, out)
+	require.NotNil(t, back.FindTable("users").FindColumn("email").DefaultValue)
+	assert.Empty(t, *back.FindTable("users").FindColumn("email").DefaultValue)
+}
+
+func TestWriteViews(t *testing.T) {
+	t.Parallel()
+	db := introspected()
+	db.Views = []*core.View{
+		{Name: "tenant_users", Definition: "SELECT id, email FROM users", Security: core.SecurityDefiner},
+		{Name: "user_count", Definition: "SELECT count(*) AS n FROM users", Materialized: true},
+	}
+	out := write(t, db)
+
+	assert.Contains(t, out, "[[views]]\nname = \"tenant_users\"\ndefinition = \"SELECT id, email FROM users\"\nsecurity = \"DEFINER\"")
+	assert.Contains(t, out, "materialized = true")
+
+	back, err := NewParser().Parse(strings.NewReader(out))
+	require.NoError(t, err, out)
+	assert.Equal(t, db.Views, back.Views)
+}
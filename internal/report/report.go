@@ -0,0 +1,277 @@
+// Package report summarizes a diff.ChangeSet for people and tools. The
+// Report type is the JSON document printed by "smf diff --format json";
+// its field names and status values are part of the CLI contract and only
+// change together with SchemaVersion. WriteText renders the same report as
+// a grouped, optionally colored summary.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/generate"
+)
+
+// SchemaVersion is the version of the JSON document.
+const SchemaVersion = 1
+
+// Table statuses.
+const (
+	StatusAdded   = "added"
+	StatusRemoved = "removed"
+	StatusChanged = "changed"
+)
+
+// Object kinds a change applies to.
+const (
+	ObjectTable      = "table"
+	ObjectColumn     = "column"
+	ObjectIndex      = "index"
+	ObjectConstraint = "constraint"
+)
+
+// Report summarizes the pending changes of a schema.
+type Report struct {
+	SchemaVersion int          `json:"schema_version"`
+	Dialect       core.Dialect `json:"dialect"`
+	InSync        bool         `json:"in_sync"`
+	Destructive   bool         `json:"destructive"`
+	Summary       Summary      `json:"summary"`
+	Tables        []Table      `json:"tables"`
+	Views         []View       `json:"views,omitempty"`
+}
+
+// Summary counts the tables and changes of a report.
+type Summary struct {
+	TablesAdded   int `json:"tables_added"`
+	TablesRemoved int `json:"tables_removed"`
+	TablesChanged int `json:"tables_changed"`
+	ViewsAdded    int `json:"views_added,omitempty"`
+	ViewsRemoved  int `json:"views_removed,omitempty"`
+	ViewsChanged  int `json:"views_changed,omitempty"`
+	Changes       int `json:"changes"`
+}
+
+// Table lists the changes of one table. Added and removed tables have no
+// further changes.
+type Table struct {
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Changes []Change `json:"changes"`
+}
+
+// View is an added, removed, or recreated view. A changed view lists the
+// attributes that differ; a view that is only recreated because an object
+// it selects from changed has none.
+type View struct {
+	Name         string             `json:"name"`
+	Status       string             `json:"status"`
+	Materialized bool               `json:"materialized,omitempty"`
+	Fields       []diff.FieldChange `json:"fields,omitempty"`
+}
+
+// Change is a single change of a table.
+type Change struct {
+	Kind   diff.Kind `json:"kind"`
+	Object string    `json:"object"`
+	// Name is the name of the column, index, or constraint. It is empty
+	// for changes of the table itself.
+	Name string `json:"name,omitempty"`
+	// Detail describes an added or dropped object, such as the type of a
+	// column or the columns of an index.
+	Detail      string             `json:"detail,omitempty"`
+	Destructive bool               `json:"destructive"`
+	Fields      []diff.FieldChange `json:"fields,omitempty"`
+}
+
+// New builds the report of cs. Tables and views are ordered by name; the
+// changes of a table keep the order of the change set.
+func New(cs *diff.ChangeSet) *Report {
+	r := &Report{SchemaVersion: SchemaVersion, InSync: cs.Empty(), Tables: []Table{}}
+	if cs == nil {
+		return r
+	}
+	if cs.To != nil {
+		r.Dialect = cs.To.Dialect
+	}
+
+	tables := make(map[string]*Table)
+	views := make(map[string]*View)
+	for _, c := range cs.Changes {
+		if viewChange(views, c) {
+			continue
+		}
+		t := tables[c.TableName()]
+		if t == nil {
+			t = &Table{Name: c.TableName(), Status: StatusChanged, Changes: []Change{}}
+			tables[t.Name] = t
+		}
+		switch c.(type) {
+		case *diff.AddTable:
+			t.Status = StatusAdded
+		case *diff.DropTable:
+			t.Status = StatusRemoved
+		default:
+			t.Changes = append(t.Changes, change(c))
+		}
+		r.Destructive = r.Destructive || diff.IsDestructive(c)
+	}
+
+	for _, t := range tables {
+		r.Tables = append(r.Tables, *t)
+		r.Summary.count(t)
+	}
+	sort.Slice(r.Tables, func(i, j int) bool { return r.Tables[i].Name < r.Tables[j].Name })
+	for _, v := range views {
+		r.Views = append(r.Views, *v)
+		r.Summary.countView(v)
+	}
+	sort.Slice(r.Views, func(i, j int) bool { return r.Views[i].Name < r.Views[j].Name })
+	return r
+}
+
+// viewChange records c in views and reports whether it is a view change.
+// A recreated view is dropped before it is added again, so the AddView
+// change that follows its DropView decides its status.
+func viewChange(views map[string]*View, c diff.Change) bool {
+	switch c := c.(type) {
+	case *diff.DropView:
+		views[c.View.Name] = &View{Name: c.View.Name, Status: StatusRemoved, Materialized: c.View.Materialized}
+	case *diff.AddView:
+		v := &View{Name: c.View.Name, Status: StatusAdded, Materialized: c.View.Materialized}
+		if c.Old != nil {
+			v.Status, v.Fields = StatusChanged, diff.View(c.Old, c.View)
+		}
+		views[v.Name] = v
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Summary) countView(v *View) {
+	switch v.Status {
+	case StatusAdded:
+		s.ViewsAdded++
+	case StatusRemoved:
+		s.ViewsRemoved++
+	default:
+		s.ViewsChanged++
+	}
+	s.Changes++
+}
+
+func (s *Summary) count(t *Table) {
+	switch t.Status {
+	case StatusAdded:
+		s.TablesAdded++
+		s.Changes++
+	case StatusRemoved:
+		s.TablesRemoved++
+		s.Changes++
+	default:
+		s.TablesChanged++
+		s.Changes += len(t.Changes)
+	}
+}
+
+func change(c diff.Change) Change {
+	out := Change{Kind: c.Kind(), Object: ObjectTable, Destructive: diff.IsDestructive(c)}
+	switch c := c.(type) {
+	case *diff.ChangeTableComment:
+		out.Fields = []diff.FieldChange{{Name: "comment", Old: c.Old, New: c.New}}
+	case *diff.TableOptionChange:
+		out.Fields = c.Fields
+	case *diff.AddColumn:
+		out.Object, out.Name, out.Detail = ObjectColumn, c.Column.Name, ColumnType(c.Column)
+	case *diff.DropColumn:
+		out.Object, out.Name, out.Detail = ObjectColumn, c.Column.Name, ColumnType(c.Column)
+	case *diff.AddIndex, *diff.DropIndex, *diff.AddConstraint, *diff.DropConstraint:
+		keyChange(&out, c)
+	default:
+		columnChange(&out, c)
+	}
+	return out
+}
+
+func keyChange(out *Change, c diff.Change) {
+	switch c := c.(type) {
+	case *diff.AddIndex:
+		out.Object, out.Name, out.Detail = ObjectIndex, generate.IndexName(c.Table, c.Index), indexDetail(c.Index)
+	case *diff.DropIndex:
+		out.Object, out.Name, out.Detail = ObjectIndex, generate.IndexName(c.Table, c.Index), indexDetail(c.Index)
+	case *diff.AddConstraint:
+		out.Object, out.Name, out.Detail = ObjectConstraint, generate.ConstraintName(c.Table, c.Constraint), constraintDetail(c.Constraint)
+	case *diff.DropConstraint:
+		out.Object, out.Name, out.Detail = ObjectConstraint, generate.ConstraintName(c.Table, c.Constraint), constraintDetail(c.Constraint)
+	}
+}
+
+func columnChange(out *Change, c diff.Change) {
+	out.Object = ObjectColumn
+	switch c := c.(type) {
+	case *diff.AlterColumnType:
+		out.Name = c.New.Name
+		out.Fields = []diff.FieldChange{{Name: "type", Old: ColumnType(c.Old), New: ColumnType(c.New)}}
+	case *diff.AlterNullability:
+		out.Name = c.New.Name
+		out.Fields = []diff.FieldChange{{Name: "nullable", Old: c.Old.Nullable, New: c.New.Nullable}}
+	case *diff.ChangeDefault:
+		out.Name = c.New.Name
+		out.Fields = []diff.FieldChange{{Name: "default", Old: stringPtr(c.Old.DefaultValue), New: stringPtr(c.New.DefaultValue)}}
+	case *diff.AlterColumn:
+		out.Name = c.New.Name
+		out.Fields = c.Fields
+	}
+}
+
+// ColumnType returns the declared type of c: the raw type, or the portable
+// type as written in the schema, with the values of an enum.
+func ColumnType(c *core.Column) string {
+	t := c.RawType
+	if t == "" {
+		t = c.PortableType
+	}
+	if t == "" {
+		t = string(c.Type)
+	}
+	if len(c.EnumValues) > 0 && !strings.Contains(t, "(") {
+		t += "(" + strings.Join(c.EnumValues, ", ") + ")"
+	}
+	return t
+}
+
+func indexDetail(idx *core.Index) string {
+	detail := "(" + strings.Join(idx.Names(), ", ") + ")"
+	if idx.Unique {
+		detail = "UNIQUE " + detail
+	}
+	if idx.Type != "" && idx.Type != core.IndexTypeBTree {
+		detail = string(idx.Type) + " " + detail
+	}
+	return detail
+}
+
+func constraintDetail(con *core.Constraint) string {
+	detail := string(con.Type)
+	if len(con.Columns) > 0 {
+		detail += " (" + strings.Join(con.Columns, ", ") + ")"
+	}
+	if con.ReferencedTable != "" {
+		detail += fmt.Sprintf(" REFERENCES %s (%s)", con.ReferencedTable, strings.Join(con.ReferencedColumns, ", "))
+	}
+	if con.CheckExpression != "" {
+		detail += " CHECK (" + con.CheckExpression + ")"
+	}
+	return detail
+}
+
+func stringPtr(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
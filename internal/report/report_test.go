@@ -0,0 +1,195 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+	"smf/internal/diff"
+	"smf/internal/pars/toml"
+)
+
+const fromSchema = `
+[database]
+name = "app"
+dialect = "mysql"
+
+[[tables]]
+name = "users"
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "email"
+  type = "varchar(100)"
+
+  [[tables.columns]]
+  name = "age"
+  type = "int"
+
+[[tables]]
+name = "legacy"
+
+  [[tables.columns]]
+  name = "id"
+  type = "int"
+`
+
+const toSchema = `
+[database]
+name = "app"
+dialect = "mysql"
+
+[[tables]]
+name = "users"
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+
+  [[tables.columns]]
+  name = "email"
+  type = "varchar(255)"
+  nullable = true
+
+  [[tables.indexes]]
+  columns = ["email"]
+  unique = true
+
+[[tables]]
+name = "orders"
+
+  [[tables.columns]]
+  name = "id"
+  type = "bigint"
+  primary_key = true
+`
+
+func parse(t *testing.T, s string) *core.Database {
+	t.Helper()
+	db, err := toml.NewParser().Parse(strings.NewReader(s))
+	require.NoError(t, err)
+	return db
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	r := New(diff.Databases(parse(t, fromSchema), parse(t, toSchema)))
+
+	assert.False(t, r.InSync)
+	assert.True(t, r.Destructive)
+	assert.Equal(t, core.DialectMySQL, r.Dialect)
+	assert.Equal(t, Summary{TablesAdded: 1, TablesRemoved: 1, TablesChanged: 1, Changes: 6}, r.Summary)
+	require.Len(t, r.Tables, 3)
+	assert.Equal(t, Table{Name: "legacy", Status: StatusRemoved, Changes: []Change{}}, r.Tables[0])
+	assert.Equal(t, Table{Name: "orders", Status: StatusAdded, Changes: []Change{}}, r.Tables[1])
+	assert.Equal(t, []Change{
+		{Kind: diff.KindDropColumn, Object: ObjectColumn, Name: "age", Detail: "int", Destructive: true},
+		{Kind: diff.KindAlterColumnType, Object: ObjectColumn, Name: "email",
+			Fields: []diff.FieldChange{{Name: "type", Old: "varchar(100)", New: "varchar(255)"}}},
+		{Kind: diff.KindAlterNullability, Object: ObjectColumn, Name: "email",
+			Fields: []diff.FieldChange{{Name: "nullable", Old: false, New: true}}},
+		{Kind: diff.KindAddIndex, Object: ObjectIndex, Name: "idx_users_email", Detail: "UNIQUE (email)"},
+	}, r.Tables[2].Changes)
+}
+
+func TestNewInSync(t *testing.T) {
+	t.Parallel()
+	db := parse(t, toSchema)
+	r := New(diff.Databases(db, db))
+	assert.True(t, r.InSync)
+
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"schema_version": 1, "dialect": "mysql", "in_sync": true, "destructive": false,
+		"summary": {"tables_added": 0, "tables_removed": 0, "tables_changed": 0, "changes": 0}, "tables": []}`, string(data))
+}
+
+func TestWriteText(t *testing.T) {
+	t.Parallel()
+	r := New(diff.Databases(parse(t, fromSchema), parse(t, toSchema)))
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf, false))
+	assert.Equal(t, "Tables added (1)\n"+
+		"  + orders\n"+
+		"Tables removed (1)\n"+
+		"  - legacy\n"+
+		"Tables changed (1)\n"+
+		"  ~ users\n"+
+		"      - column age int (destructive)\n"+
+		"      ~ column email: type varchar(100) → varchar(255)\n"+
+		"      ~ column email: nullable false → true\n"+
+		"      + index idx_users_email UNIQUE (email)\n"+
+		"1 added, 1 removed, 1 changed tables; 6 changes (destructive)\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, r.WriteText(&buf, true))
+	assert.Contains(t, buf.String(), colorGreen+"  + orders"+colorReset)
+}
+
+func TestNewViews(t *testing.T) {
+	t.Parallel()
+	from := parse(t, toSchema+`
+[[views]]
+name = "user_emails"
+definition = "SELECT id, email FROM users"
+
+[[views]]
+name = "recent_emails"
+definition = "SELECT email FROM user_emails"
+
+[[views]]
+name = "order_ids"
+definition = "SELECT id FROM orders"
+`)
+	to := parse(t, toSchema+`
+[[views]]
+name = "user_emails"
+definition = "SELECT id, email FROM users WHERE email IS NOT NULL"
+security = "INVOKER"
+
+[[views]]
+name = "recent_emails"
+definition = "SELECT email FROM user_emails"
+
+[[views]]
+name = "order_count"
+definition = "SELECT count(*) AS n FROM orders"
+materialized = true
+`)
+	r := New(diff.Databases(from, to))
+
+	assert.False(t, r.Destructive)
+	assert.Empty(t, r.Tables)
+	assert.Equal(t, Summary{ViewsAdded: 1, ViewsRemoved: 1, ViewsChanged: 2, Changes: 4}, r.Summary)
+	assert.Equal(t, []View{
+		{Name: "order_count", Status: StatusAdded, Materialized: true},
+		{Name: "order_ids", Status: StatusRemoved},
+		{Name: "recent_emails", Status: StatusChanged},
+		{Name: "user_emails", Status: StatusChanged, Fields: []diff.FieldChange{
+			{Name: "definition", Old: "SELECT id, email FROM users", New: "SELECT id, email FROM users WHERE email IS NOT NULL"},
+			{Name: "security", Old: core.ViewSecurity(""), New: core.SecurityInvoker},
+		}},
+	}, r.Views)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf, false))
+	assert.Equal(t, "Views added (1)\n"+
+		"  + order_count\n"+
+		"Views removed (1)\n"+
+		"  - order_ids\n"+
+		"Views changed (2)\n"+
+		"  ~ recent_emails (recreated)\n"+
+		"  ~ user_emails: definition, security\n"+
+		"0 added, 0 removed, 0 changed tables; 1 added, 1 removed, 2 changed views; 4 changes\n", buf.String())
+}
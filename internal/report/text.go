@@ -0,0 +1,183 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ANSI escape sequences used by the colored text output.
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBold   = "\x1b[1m"
+)
+
+var statusGroups = []struct {
+	status, title, marker, color string
+}{
+	{StatusAdded, "Tables added", "+", colorGreen},
+	{StatusRemoved, "Tables removed", "-", colorRed},
+	{StatusChanged, "Tables changed", "~", colorYellow},
+}
+
+var viewStatusGroups = []struct {
+	status, title, marker, color string
+}{
+	{StatusAdded, "Views added", "+", colorGreen},
+	{StatusRemoved, "Views removed", "-", colorRed},
+	{StatusChanged, "Views changed", "~", colorYellow},
+}
+
+// WriteText writes r as a human-readable summary grouped by table and view
+// status.
+// Colors are written with ANSI escape sequences when color is set.
+func (r *Report) WriteText(w io.Writer, color bool) error {
+	p := &printer{w: w, color: color}
+	if r.InSync {
+		p.line("", "Schema is in sync with the latest migration")
+		return p.err
+	}
+	for _, g := range statusGroups {
+		tables := r.tablesWith(g.status)
+		if len(tables) == 0 {
+			continue
+		}
+		p.line(colorBold, fmt.Sprintf("%s (%d)", g.title, len(tables)))
+		for _, t := range tables {
+			p.line(g.color, fmt.Sprintf("  %s %s", g.marker, t.Name))
+			for _, c := range t.Changes {
+				p.change(c)
+			}
+		}
+	}
+	for _, g := range viewStatusGroups {
+		views := r.viewsWith(g.status)
+		if len(views) == 0 {
+			continue
+		}
+		p.line(colorBold, fmt.Sprintf("%s (%d)", g.title, len(views)))
+		for _, v := range views {
+			p.line(g.color, fmt.Sprintf("  %s %s%s", g.marker, v.Name, viewDetail(v)))
+		}
+	}
+	p.summary(r)
+	return p.err
+}
+
+func (r *Report) tablesWith(status string) []Table {
+	var out []Table
+	for _, t := range r.Tables {
+		if t.Status == status {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func (r *Report) viewsWith(status string) []View {
+	var out []View
+	for _, v := range r.Views {
+		if v.Status == status {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// viewDetail names the changed attributes of a view. Definitions are too
+// long to print, so only the attribute names are listed.
+func viewDetail(v View) string {
+	if v.Status != StatusChanged {
+		return ""
+	}
+	if len(v.Fields) == 0 {
+		return " (recreated)"
+	}
+	names := make([]string, len(v.Fields))
+	for i, f := range v.Fields {
+		names[i] = f.Name
+	}
+	return ": " + strings.Join(names, ", ")
+}
+
+type printer struct {
+	w     io.Writer
+	color bool
+	err   error
+}
+
+func (p *printer) line(color, s string) {
+	if p.err != nil {
+		return
+	}
+	if p.color && color != "" {
+		s = color + s + colorReset
+	}
+	_, p.err = fmt.Fprintln(p.w, s)
+}
+
+func (p *printer) change(c Change) {
+	marker, color := "~", colorYellow
+	switch {
+	case strings.HasPrefix(string(c.Kind), "add_"):
+		marker, color = "+", colorGreen
+	case strings.HasPrefix(string(c.Kind), "drop_"):
+		marker, color = "-", colorRed
+	}
+
+	s := "      " + marker + " " + c.Object
+	if c.Name != "" {
+		s += " " + c.Name
+	}
+	if c.Detail != "" {
+		s += " " + c.Detail
+	}
+	if len(c.Fields) > 0 {
+		s += ": " + fields(c)
+	}
+	if c.Destructive {
+		s += " (destructive)"
+		color = colorRed
+	}
+	p.line(color, s)
+}
+
+// fields renders the changed attributes as "name old → new".
+func fields(c Change) string {
+	parts := make([]string, len(c.Fields))
+	for i, f := range c.Fields {
+		parts[i] = fmt.Sprintf("%s %s → %s", f.Name, value(f.Old), value(f.New))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func value(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (p *printer) summary(r *Report) {
+	s := fmt.Sprintf("%d added, %d removed, %d changed tables",
+		r.Summary.TablesAdded, r.Summary.TablesRemoved, r.Summary.TablesChanged)
+	if len(r.Views) > 0 {
+		s += fmt.Sprintf("; %d added, %d removed, %d changed views",
+			r.Summary.ViewsAdded, r.Summary.ViewsRemoved, r.Summary.ViewsChanged)
+	}
+	s += fmt.Sprintf("; %d changes", r.Summary.Changes)
+	if r.Destructive {
+		s += " (destructive)"
+	}
+	p.line(colorBold, s)
+}
@@ -59,6 +59,10 @@ func Validate(db *core.Database) error {
 		return err
 	}
 
+	if err := Views(db, nameRe); err != nil {
+		return err
+	}
+
 	return Enums(db)
 }
 
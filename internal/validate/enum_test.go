@@ -392,6 +392,7 @@ func TestIndexEnumsValidTypes(t *testing.T) {
 		{Name: "idx", Type: core.IndexTypeSpatial},
 		{Name: "idx", Type: core.IndexTypeGIN},
 		{Name: "idx", Type: core.IndexTypeGiST},
+		{Name: "idx", Type: core.IndexTypeBRIN},
 		{Name: "idx", Type: ""},
 	}
 
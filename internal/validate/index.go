@@ -10,9 +10,22 @@ func Indexes(t *core.Table) error {
 	if err := IndexNames(t); err != nil {
 		return err
 	}
+	if err := IndexParsers(t); err != nil {
+		return err
+	}
 	return IndexColumns(t)
 }
 
+// IndexParsers checks that only FULLTEXT indexes name a full-text parser.
+func IndexParsers(t *core.Table) error {
+	for _, idx := range t.Indexes {
+		if idx.Parser != "" && idx.Type != core.IndexTypeFullText {
+			return fmt.Errorf("index %q: parser can only be set for FULLTEXT indexes", idx.Name)
+		}
+	}
+	return nil
+}
+
 func IndexNames(t *core.Table) error {
 	seen := make(map[string]bool, len(t.Indexes))
 	for _, idx := range t.Indexes {
@@ -40,7 +53,7 @@ func IndexColumns(t *core.Table) error {
 			return fmt.Errorf("index %s has no columns", name)
 		}
 		for _, ic := range idx.Columns {
-			if t.FindColumn(ic.Name) == nil {
+			if ic.Expression == "" && t.FindColumn(ic.Name) == nil {
 				return fmt.Errorf("index %q references nonexistent column %q", idx.Name, ic.Name)
 			}
 		}
@@ -30,6 +30,26 @@ func TestIndexDuplicateNames(t *testing.T) {
 	assert.Contains(t, err.Error(), "duplicate index name")
 }
 
+func TestIndexParserRequiresFullText(t *testing.T) {
+	db := &core.Database{
+		Name:    "app",
+		Dialect: core.DialectMySQL,
+		Tables: []*core.Table{
+			{
+				Name:    "users",
+				Columns: []*core.Column{{Name: "email", Type: core.DataTypeString}},
+				Indexes: []*core.Index{
+					{Name: "idx_email", Type: core.IndexTypeBTree, Parser: "ngram", Columns: []core.ColumnIndex{{Name: "email"}}},
+				},
+			},
+		},
+	}
+
+	err := Database(db)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parser can only be set for FULLTEXT indexes")
+}
+
 func TestIndexHasNoColumns(t *testing.T) {
 	db := &core.Database{
 		Name:    "app",
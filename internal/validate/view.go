@@ -0,0 +1,78 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"smf/internal/core"
+)
+
+// Views checks the views of db. Views share the namespace of tables, so a
+// view cannot be named like a table.
+func Views(db *core.Database, nameRe *regexp.Regexp) error {
+	seen := make(map[string]bool, len(db.Views))
+	for _, v := range db.Views {
+		if seen[v.Name] {
+			return fmt.Errorf("duplicate view name %q", v.Name)
+		}
+		if db.FindTable(v.Name) != nil {
+			return fmt.Errorf("view %q has the name of a table", v.Name)
+		}
+		seen[v.Name] = true
+		if err := View(v, db.Validation, nameRe); err != nil {
+			return fmt.Errorf("view %q: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+func View(v *core.View, rules *core.ValidationRules, nameRe *regexp.Regexp) error {
+	if err := Name(v.Name, rules, nameRe, true); err != nil {
+		return err
+	}
+	if strings.TrimSpace(v.Definition) == "" {
+		return errors.New("definition is required")
+	}
+	if err := ViewColumns(v, rules, nameRe); err != nil {
+		return err
+	}
+	return ViewEnums(v)
+}
+
+func ViewColumns(v *core.View, rules *core.ValidationRules, nameRe *regexp.Regexp) error {
+	seen := make(map[string]bool, len(v.Columns))
+	for _, name := range v.Columns {
+		if err := Name(name, rules, nameRe, false); err != nil {
+			return fmt.Errorf("column %q: %w", name, err)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate column name %q", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// ViewEnums checks the check option, security, and refresh policy of v. A
+// materialized view cannot be written through, so it takes no check
+// option, and only a materialized view takes a refresh policy.
+func ViewEnums(v *core.View) error {
+	if !v.CheckOption.IsValid() {
+		return fmt.Errorf("invalid check option %q", v.CheckOption)
+	}
+	if v.Security != "" && !v.Security.IsValid() {
+		return fmt.Errorf("invalid security %q", v.Security)
+	}
+	if v.Refresh != "" && !v.Refresh.IsValid() {
+		return fmt.Errorf("invalid refresh policy %q", v.Refresh)
+	}
+	if v.Materialized && v.CheckOption != core.CheckOptionNone {
+		return errors.New("check_option is not supported for materialized views")
+	}
+	if !v.Materialized && v.Refresh != "" {
+		return errors.New("refresh is only supported for materialized views")
+	}
+	return nil
+}
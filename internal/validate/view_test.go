@@ -0,0 +1,95 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"smf/internal/core"
+)
+
+func viewDatabase(views ...*core.View) *core.Database {
+	return &core.Database{
+		Name:    "app",
+		Dialect: core.DialectPostgreSQL,
+		Tables: []*core.Table{
+			{Name: "orders", Columns: []*core.Column{{Name: "id", Type: core.DataTypeInt}}},
+		},
+		Views: views,
+	}
+}
+
+func TestViews(t *testing.T) {
+	const def = "SELECT id FROM orders"
+	tests := []struct {
+		name    string
+		views   []*core.View
+		wantErr string
+	}{
+		{
+			name: "valid views",
+			views: []*core.View{
+				{Name: "open_orders", Definition: def, Columns: []string{"order_id"}, CheckOption: core.CheckOptionCascaded},
+				{Name: "order_stats", Definition: def, Materialized: true, Refresh: core.RefreshOnCommit},
+			},
+		},
+		{
+			name:    "duplicate name",
+			views:   []*core.View{{Name: "open_orders", Definition: def}, {Name: "open_orders", Definition: def}},
+			wantErr: `duplicate view name "open_orders"`,
+		},
+		{
+			name:    "table name",
+			views:   []*core.View{{Name: "orders", Definition: def}},
+			wantErr: `view "orders" has the name of a table`,
+		},
+		{
+			name:    "invalid name",
+			views:   []*core.View{{Name: "OpenOrders", Definition: def}},
+			wantErr: "must be in snake_case",
+		},
+		{
+			name:    "missing definition",
+			views:   []*core.View{{Name: "open_orders", Definition: "  "}},
+			wantErr: "definition is required",
+		},
+		{
+			name:    "duplicate column",
+			views:   []*core.View{{Name: "open_orders", Definition: def, Columns: []string{"id", "id"}}},
+			wantErr: `duplicate column name "id"`,
+		},
+		{
+			name:    "invalid check option",
+			views:   []*core.View{{Name: "open_orders", Definition: def, CheckOption: "ALWAYS"}},
+			wantErr: `invalid check option "ALWAYS"`,
+		},
+		{
+			name:    "invalid security",
+			views:   []*core.View{{Name: "open_orders", Definition: def, Security: "OWNER"}},
+			wantErr: `invalid security "OWNER"`,
+		},
+		{
+			name:    "materialized check option",
+			views:   []*core.View{{Name: "order_stats", Definition: def, Materialized: true, CheckOption: core.CheckOptionLocal}},
+			wantErr: "check_option is not supported for materialized views",
+		},
+		{
+			name:    "refresh without materialized",
+			views:   []*core.View{{Name: "open_orders", Definition: def, Refresh: core.RefreshOnDemand}},
+			wantErr: "refresh is only supported for materialized views",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Database(viewDatabase(tt.views...))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}